@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultBaseURL = "http://localhost:8080"
+
+// apiClient calls the Portalight API with the token from the environment
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// apiError is a non-2xx response; the server sends plain-text messages via http.Error
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// newAPIClient reads PORTALIGHT_URL and PORTALIGHT_TOKEN
+func newAPIClient() (*apiClient, error) {
+	token := os.Getenv("PORTALIGHT_TOKEN")
+	if token == "" {
+		return nil, usagef("PORTALIGHT_TOKEN is not set")
+	}
+	baseURL := os.Getenv("PORTALIGHT_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &apiClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 5 * time.Minute}, // Syncs run inline
+	}, nil
+}
+
+// do sends body (if not nil) as JSON and decodes the response into out (if not nil)
+func (c *apiClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return &apiError{StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// errorMessage extracts the message of an error response: plain text from
+// http.Error, or {"error": "..."} from the auth middleware
+func errorMessage(data []byte) string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		return body.Error
+	}
+	return strings.TrimSpace(string(data))
+}
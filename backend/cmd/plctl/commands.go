@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/models"
+)
+
+// pollInterval is how often --wait checks a provisioning resource
+const pollInterval = 5 * time.Second
+
+// catalogValidation is the result of validating one catalog file
+type catalogValidation struct {
+	File     string                    `json:"file"`
+	Valid    bool                      `json:"valid"`
+	Errors   []catalog.ValidationError `json:"errors"`
+	Warnings []string                  `json:"warnings"`
+}
+
+// runCatalogValidate validates catalog files with the server's schema rules, without
+// calling the API. dependsOn references to other projects cannot be resolved offline,
+// so they are reported as warnings.
+func runCatalogValidate(args []string) error {
+	var output outputFormat
+	fs := newFlagSet("catalog validate", &output)
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return usagef("catalog validate: no catalog file given")
+	}
+
+	results := make([]catalogValidation, 0, len(files))
+	invalid := 0
+	for _, file := range files {
+		result := validateCatalogFile(file)
+		if !result.Valid {
+			invalid++
+		}
+		results = append(results, result)
+	}
+
+	if output == outputJSON {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		t := newTable(os.Stdout, "FILE", "LEVEL", "FIELD", "MESSAGE")
+		for _, result := range results {
+			if len(result.Errors) == 0 && len(result.Warnings) == 0 {
+				t.row(result.File, "ok", "-", "-")
+			}
+			for _, e := range result.Errors {
+				t.row(result.File, "error", orDash(e.Field), e.Message)
+			}
+			for _, warning := range result.Warnings {
+				t.row(result.File, "warning", "-", warning)
+			}
+		}
+		if err := t.flush(); err != nil {
+			return err
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d catalog files are invalid", invalid, len(files))
+	}
+	return nil
+}
+
+func validateCatalogFile(file string) catalogValidation {
+	result := catalogValidation{File: file, Errors: []catalog.ValidationError{}, Warnings: []string{}}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		result.Errors = append(result.Errors, catalog.ValidationError{Message: err.Error()})
+		return result
+	}
+	parsed, err := catalog.ParseYAML(content)
+	if err != nil {
+		result.Errors = append(result.Errors, catalog.ValidationError{Message: err.Error()})
+		return result
+	}
+
+	validationErrors, warnings := catalog.ValidateSchema(parsed, nil)
+	result.Errors = append(result.Errors, validationErrors...)
+	result.Warnings = append(result.Warnings, warnings...)
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// runProjectSync syncs a project from its catalog file and waits for the result
+func runProjectSync(args []string) error {
+	var output outputFormat
+	fs := newFlagSet("project sync", &output)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("project sync: expected exactly one project ID")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var result models.ProjectSyncResponse
+	if err := client.do("POST", "/api/v1/projects/"+url.PathEscape(positional[0])+"/sync", nil, &result); err != nil {
+		return err
+	}
+
+	if output == outputJSON {
+		return printJSON(result)
+	}
+	t := newTable(os.Stdout, "PROJECT", "STATUS", "MESSAGE")
+	t.row(result.ProjectName, result.Status, result.Message)
+	return t.flush()
+}
+
+// runResourceStatus shows a provisioned resource; it fails if provisioning failed
+func runResourceStatus(args []string) error {
+	var output outputFormat
+	fs := newFlagSet("resource status", &output)
+	wait := fs.Bool("wait", false, "Wait until the resource is no longer provisioning")
+	timeout := fs.Duration("timeout", 15*time.Minute, "How long --wait waits")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("resource status: expected exactly one resource ID")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	resource, err := getResource(client, positional[0], *wait, *timeout)
+	if err != nil {
+		return err
+	}
+	return printResource(resource, output)
+}
+
+// runProvision requests a new resource; --config is a JSON file with the type's
+// configuration ("-" reads it from stdin)
+func runProvision(args []string) error {
+	var output outputFormat
+	fs := newFlagSet("provision", &output)
+	projectID := fs.String("project", "", "Project ID (required)")
+	name := fs.String("name", "", "Resource name (required)")
+	reason := fs.String("reason", "", "Why the resource is needed (required)")
+	ticketURL := fs.String("ticket", "", "URL of the ticket tracking the request")
+	configFile := fs.String("config", "", "JSON file with the resource configuration, or - for stdin")
+	secretID := fs.String("secret", "", "AWS credential ID (default: the project's credential)")
+	wait := fs.Bool("wait", false, "Wait until provisioning finishes")
+	timeout := fs.Duration("timeout", 15*time.Minute, "How long --wait waits")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("provision: expected exactly one resource type, e.g. provision s3")
+	}
+	if *projectID == "" || *name == "" || *reason == "" {
+		return usagef("provision: --project, --name and --reason are required")
+	}
+
+	req := models.CreateResourceRequest{
+		ProjectID: *projectID,
+		SecretID:  *secretID,
+		Name:      *name,
+		Type:      positional[0],
+		Reason:    *reason,
+		TicketURL: *ticketURL,
+	}
+	if *configFile != "" {
+		req.Config, err = readConfig(*configFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var resource models.Resource
+	if err := client.do("POST", "/api/v1/provision", req, &resource); err != nil {
+		return err
+	}
+	if *wait {
+		waited, err := getResource(client, resource.ID, true, *timeout)
+		if err != nil {
+			return err
+		}
+		resource = *waited
+	}
+	return printResource(&resource, output)
+}
+
+func readConfig(file string) (json.RawMessage, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, usagef("provision: --config must contain JSON")
+	}
+	return json.RawMessage(data), nil
+}
+
+// getResource fetches a provisioned resource, polling while it is provisioning if wait is set
+func getResource(client *apiClient, id string, wait bool, timeout time.Duration) (*models.Resource, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		var resource models.Resource
+		if err := client.do("GET", "/api/v1/resources/"+url.PathEscape(id), nil, &resource); err != nil {
+			return nil, err
+		}
+		if !wait || resource.Status != models.ProvisionStatusProvisioning {
+			return &resource, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("resource %s is still provisioning after %s", id, timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// printResource prints the resource and fails if its provisioning failed
+func printResource(resource *models.Resource, output outputFormat) error {
+	if output == outputJSON {
+		if err := printJSON(resource); err != nil {
+			return err
+		}
+	} else {
+		t := newTable(os.Stdout, "ID", "NAME", "TYPE", "STATUS", "STAGE", "ARN", "ERROR")
+		t.row(resource.ID, resource.Name, resource.Type, string(resource.Status),
+			orDash(resource.Stage), orDash(resource.ARN), orDash(resource.ErrorMsg))
+		if err := t.flush(); err != nil {
+			return err
+		}
+	}
+
+	if resource.Status == models.ProvisionStatusFailed {
+		return fmt.Errorf("provisioning of %s failed: %s", resource.Name, resource.ErrorMsg)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Command plctl is a command-line client for the Portalight API, meant for scripts
+// and CI jobs.
+//
+//	plctl catalog validate portalight.yaml
+//	plctl project sync <project-id>
+//	plctl resource status <resource-id>
+//	plctl provision s3 --project <project-id> --name my-bucket --reason "Invoice exports" --config s3.json
+//
+// The API is read from PORTALIGHT_URL (default http://localhost:8080) and requests are
+// authenticated with the access token in PORTALIGHT_TOKEN. Every command accepts
+// -o json|table. plctl exits with 1 when the command fails (including validation
+// errors and failed provisioning) and 2 on usage errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// usageError marks mistakes in the command line, which exit with exitUsage
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// command is a subcommand; args are the arguments after its name
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"catalog validate", "Validate a catalog file locally", runCatalogValidate},
+	{"project sync", "Sync a project from its catalog file", runProjectSync},
+	{"resource status", "Show the status of a provisioned resource", runResourceStatus},
+	{"provision", "Provision a resource: provision <type> --project ID --name NAME --reason TEXT --config FILE", runProvision},
+}
+
+func main() {
+	err := run(os.Args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "plctl:", err)
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		os.Exit(exitUsage)
+	}
+	os.Exit(exitFailure)
+}
+
+func run(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage()
+		if len(args) == 0 {
+			return usagef("no command given")
+		}
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if rest, ok := matchCommand(cmd.name, args); ok {
+			return cmd.run(rest)
+		}
+	}
+
+	printUsage()
+	return usagef("unknown command %q", args[0])
+}
+
+// matchCommand reports whether args start with the words of name and returns the rest
+func matchCommand(name string, args []string) ([]string, bool) {
+	words := strings.Fields(name)
+	if len(args) < len(words) {
+		return nil, false
+	}
+	for i, word := range words {
+		if args[i] != word {
+			return nil, false
+		}
+	}
+	return args[len(words):], true
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: plctl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Environment:")
+	fmt.Fprintln(os.Stderr, "  PORTALIGHT_URL     API base URL (default http://localhost:8080)")
+	fmt.Fprintln(os.Stderr, "  PORTALIGHT_TOKEN   Access token sent as a bearer token")
+}
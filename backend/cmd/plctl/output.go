@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// outputFormat selects how results are printed
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+// String and Set make outputFormat a flag.Value
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+func (f *outputFormat) Set(value string) error {
+	switch outputFormat(value) {
+	case outputTable, outputJSON:
+		*f = outputFormat(value)
+		return nil
+	}
+	return fmt.Errorf("must be %s or %s", outputTable, outputJSON)
+}
+
+// newFlagSet creates the flags of a subcommand, including -o/--output
+func newFlagSet(name string, output *outputFormat) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	*output = outputTable
+	fs.Var(output, "o", "Output format: table or json")
+	fs.Var(output, "output", "Output format: table or json")
+	return fs
+}
+
+// parseArgs parses flags placed before, between or after the positional arguments
+// and returns the positional ones
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usagef("%s: %v", fs.Name(), err)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// printJSON writes v as indented JSON
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// table collects tab-separated rows and aligns them on flush
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(out io.Writer, headers ...string) *table {
+	t := &table{w: tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)}
+	t.row(headers...)
+	return t
+}
+
+func (t *table) row(columns ...string) {
+	fmt.Fprintln(t.w, strings.Join(columns, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+// orDash renders empty table cells as "-"
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
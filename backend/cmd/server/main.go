@@ -1,337 +1,163 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/portalight/backend/internal/api/handlers"
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/catalog"
 	"github.com/portalight/backend/internal/config"
+	"github.com/portalight/backend/internal/crypto"
 	"github.com/portalight/backend/internal/database"
-	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/logging"
+	"github.com/portalight/backend/internal/services"
+	"github.com/portalight/backend/internal/telemetry"
 )
 
 func main() {
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := logging.Setup(cfg.LogLevel); err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	crypto.SetKey(cfg.EncryptionKey)
+	if cfg.CredentialBackend == config.CredentialBackendSecretsManager {
+		backend, err := crypto.NewSecretsManagerBackend(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to initialize AWS Secrets Manager credential backend: %v", err)
+		}
+		crypto.SetCredentialBackend(backend)
+		log.Printf("🔐 Storing new credentials in AWS Secrets Manager")
+	}
+
+	// Initialize tracing before the database so pool queries are traced
+	shutdownTracing, err := telemetry.Init(context.Background(), cfg.OTelExporterEndpoint)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database connection
-	if err := database.Connect(); err != nil {
+	if err := database.Connect(database.PoolConfig{
+		MaxConns:        int32(cfg.DBMaxConns),
+		MinConns:        int32(cfg.DBMinConns),
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		QueryTimeout:    cfg.DBQueryTimeout,
+	}); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer database.Close()
 
-	// Initialize repositories
-	projectRepo := &repositories.ProjectRepository{}
-	serviceRepo := &repositories.ServiceRepository{}
-	teamRepo := &repositories.TeamRepository{}
-	githubConfigRepo := repositories.NewGitHubConfigRepository(database.DB)
-	syncHistoryRepo := repositories.NewSyncHistoryRepository(database.DB)
-	resourceRepo := repositories.NewResourceRepository(database.DB)
+	// Build every repository and client once; handlers share them
+	deps := handlers.NewDeps()
+
+	// Email settings saved in the UI take precedence over the SMTP_* environment
+	notifications := services.NewNotificationService(services.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		TLSMode:  cfg.SMTPTLSMode,
+	}, deps.SMTPSettings)
+	notifier := services.NewUserNotifier(notifications, deps.Users, deps.NotificationPreferences)
 
 	// Initialize Syncer
-	syncer := catalog.NewSyncer(projectRepo, serviceRepo, teamRepo, syncHistoryRepo, githubConfigRepo)
+	syncer := catalog.NewSyncer(deps.Projects, deps.Services, deps.Teams, deps.SyncHistory, deps.GitHubConfig, deps.Users, notifier, deps.TeamNotifier)
+	teamSync := catalog.NewGitHubTeamSyncService(syncer, deps.Teams, deps.Users, deps.AuditLogs)
 
-	// Initialize handlers
-	secretHandler := handlers.NewSecretHandler()
-	provisionHandler := handlers.NewProvisionHandler(resourceRepo)
-	authHandler := handlers.NewAuthHandler(cfg)
-	catalogHandler := handlers.NewCatalogHandler(githubConfigRepo, syncer)
-	webhookHandler := handlers.NewGitHubWebhookHandler(syncer, githubConfigRepo)
-	projectSyncHandler := handlers.NewProjectSyncHandler(syncer, projectRepo)
-	credentialsHandler := handlers.NewCredentialsHandler()
+	// Project budgets are evaluated daily; there is no cost source yet, so
+	// estimated_cost budgets are stored but not evaluated
+	budgetEvaluator := services.NewBudgetEvaluator(deps.Resources, nil, services.NewWebhookBudgetNotifier(notifications))
+	teamDigestJob := services.NewTeamDigestJob(notifications, notifier, cfg.DigestSendHour)
 
 	// Setup routes
-	mux := http.NewServeMux()
-
-	// Auth endpoints
-	mux.HandleFunc("/auth/login", authHandler.HandleLogin) // Username/password login
-	mux.HandleFunc("/auth/github/login", authHandler.HandleGithubLogin)
-	mux.HandleFunc("/auth/github/callback", authHandler.HandleGithubCallback)
-
-	// Services API
-	serviceLinksHandler := handlers.NewServiceLinksHandler()
-	serviceResourcesHandler := handlers.NewServiceResourcesHandler()
-
-	mux.HandleFunc("/api/v1/services", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			handlers.GetServices(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Service detail endpoints with links and resources
-	mux.HandleFunc("/api/v1/services/", func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-		// Route to links handler
-		if strings.Contains(path, "/links") {
-			serviceLinksHandler.HandleLinks(w, r)
-			return
-		}
-		// Route to resources handler
-		if strings.Contains(path, "/resources") {
-			serviceResourcesHandler.HandleResources(w, r)
-			return
-		}
-		// Default: Get or Update service by ID
-		switch r.Method {
-		case http.MethodGet:
-			handlers.GetServiceByID(w, r)
-		case http.MethodPatch, http.MethodPut:
-			handlers.UpdateService(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Secret management endpoints (legacy)
-	mux.HandleFunc("/api/v1/secrets", secretHandler.GetSecrets)
-
-	// AWS Credentials management (superadmin only)
-	mux.HandleFunc("/api/v1/credentials", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			credentialsHandler.ListCredentials(w, r)
-		case http.MethodPost:
-			credentialsHandler.CreateCredential(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/api/v1/credentials/", credentialsHandler.DeleteCredential)
-
-	// Provisioning endpoints
-	mux.HandleFunc("/api/v1/provision", provisionHandler.ProvisionResource)
-
-	// Discovery endpoints
-	discoveryHandler := handlers.NewDiscoveryHandler()
-	mux.HandleFunc("/api/v1/discover", discoveryHandler.DiscoverResources)
-
-	// Resource metrics endpoints
-	resourceDetailsHandler := handlers.NewResourceDetailsHandler()
-	mux.HandleFunc("/api/v1/resources/metrics", resourceDetailsHandler.GetResourceMetrics)
-
-	// Sync endpoints
-	syncHandler := handlers.NewSyncHandler()
-	mux.HandleFunc("/api/v1/resources/sync", syncHandler.SyncProjectResources)
-	mux.HandleFunc("/api/v1/resources/associate", syncHandler.AssociateResources)
-	mux.HandleFunc("/api/v1/resources/discovered", syncHandler.GetProjectDiscoveredResources)
-	mux.HandleFunc("/api/v1/resources/discovered/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			resourceDetailsHandler.GetResourceByID(w, r)
-		} else if r.Method == http.MethodDelete {
-			syncHandler.RemoveDiscoveredResource(w, r)
-		} else {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-
-	// Repository management endpoints
-	mux.HandleFunc("/api/v1/register", handlers.RegisterRepository)
-
-	// User routes
-	mux.HandleFunc("/api/v1/users/current", handlers.GetCurrentUser)
-	mux.HandleFunc("/api/v1/users", handlers.GetUsers)
-	mux.HandleFunc("/api/v1/users/create", handlers.CreateUser)
-
-	// Dev provisioning permissions endpoints
-	devPermissionsHandler := handlers.NewDevPermissionsHandler()
-	mux.HandleFunc("/api/v1/users/", func(w http.ResponseWriter, r *http.Request) {
-		// Check if this is a provisioning-permissions request
-		if strings.Contains(r.URL.Path, "provisioning-permissions") {
-			switch r.Method {
-			case http.MethodGet:
-				devPermissionsHandler.GetDevPermissions(w, r)
-			case http.MethodPut:
-				devPermissionsHandler.UpdateDevPermissions(w, r)
-			default:
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-			return
-		}
-		// Otherwise handle user update/delete
-		switch r.Method {
-		case http.MethodPut, http.MethodPatch:
-			handlers.UpdateUser(w, r)
-		case http.MethodDelete:
-			handlers.DeleteUser(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
+	mux := newRouter(cfg, deps, syncer, teamSync, notifier, budgetEvaluator)
 
-	// Team management endpoints
-	mux.HandleFunc("/api/v1/teams", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			handlers.GetTeams(w, r)
-		case http.MethodPost:
-			handlers.CreateTeam(w, r)
-		case http.MethodDelete:
-			handlers.DeleteTeam(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/api/v1/teams/members", handlers.UpdateTeamMembers)
-
-	// Project management endpoints
-	mux.HandleFunc("/api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			handlers.GetProjects(w, r)
-		case http.MethodPost:
-			handlers.CreateProject(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
+	// Apply Auth middleware to all /api/* routes, then CORS
+	concurrencyLimit := middleware.ConcurrencyLimit(middleware.ConcurrencyLimits{
+		Global: cfg.MaxInFlightRequests,
+		Routes: cfg.RouteMaxInFlightRequests,
 	})
-	// Project sync endpoint
-	mux.HandleFunc("/api/v1/projects/", func(w http.ResponseWriter, r *http.Request) {
-		// Check if it's a sync request
-		if strings.HasSuffix(r.URL.Path, "/sync") && r.Method == http.MethodPost {
-			projectSyncHandler.SyncProject(w, r)
-			return
-		}
-
-		// Check if it's a resources request
-		if strings.HasSuffix(r.URL.Path, "/resources") && r.Method == http.MethodGet {
-			provisionHandler.GetProjectResources(w, r)
-			return
-		}
+	// Audit every mutating API call; inside auth, so the actor is known
+	handler := applyMiddleware(
+		middleware.Audit(deps.AuditLogs)(mux),
+		cfg,
+		middleware.AuthMiddleware(cfg, deps.RevokedTokens),
+		concurrencyLimit,
+		mux.PublicPaths(),
+	)
 
-		// Otherwise handle normal project operations
-		switch r.Method {
-		case http.MethodGet:
-			handlers.GetProjectByID(w, r)
-		case http.MethodPut, http.MethodPatch:
-			handlers.UpdateProject(w, r)
-		case http.MethodDelete:
-			handlers.DeleteProject(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/api/v1/projects/access", handlers.UpdateProjectAccess)
+	// Time and count every request, including rejected and unauthenticated ones
+	handler = middleware.RequestTiming()(handler)
 
-	// Catalog endpoints
-	mux.HandleFunc("/api/v1/catalog/config", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			catalogHandler.GetConfig(w, r)
-		case http.MethodPost, http.MethodPut:
-			catalogHandler.UpdateConfig(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/api/v1/catalog/scan", catalogHandler.Scan)
-	mux.HandleFunc("/api/v1/catalog/sync", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		catalogHandler.Sync(w, r)
-	})
+	// Give every request an ID for its log lines and audit entries
+	handler = middleware.RequestID()(handler)
 
-	// GitHub Webhook endpoint (no auth required - validated by signature)
-	mux.HandleFunc("/api/v1/webhook/github", webhookHandler.HandleWebhook)
+	// Trace every request, including rejected and unauthenticated ones
+	handler = middleware.Tracing(telemetry.ServiceName)(handler)
 
-	// Audit log endpoints
-	mux.HandleFunc("/api/v1/audit-logs", handlers.GetAuditLogs)
+	// Purge long-deleted discovered resources and expired entries from the token revocation list
+	cleanupJob := services.NewResourceCleanupJob(deps.DiscoveredResources, deps.RevokedTokens)
+	cleanupJob.Start(cfg.CleanupInterval, time.Duration(cfg.ResourceRetentionDays)*24*time.Hour)
 
-	// ArgoCD integration endpoints
-	argocdHandler := handlers.NewArgoCDHandler()
-	mux.HandleFunc("/api/v1/argocd/config", argocdHandler.GetConfig)
-	mux.HandleFunc("/api/v1/argocd/applications", argocdHandler.ListApplications)
-	mux.HandleFunc("/api/v1/argocd/service/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			argocdHandler.GetServiceApps(w, r)
-		case http.MethodPost:
-			argocdHandler.LinkApp(w, r)
-		case http.MethodDelete:
-			argocdHandler.UnlinkApp(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/api/v1/argocd/apps/", func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-		switch {
-		case strings.HasSuffix(path, "/status"):
-			argocdHandler.GetAppStatus(w, r)
-		case strings.HasSuffix(path, "/pods"):
-			argocdHandler.GetAppPods(w, r)
-		case strings.HasSuffix(path, "/logs"):
-			argocdHandler.GetPodLogs(w, r)
-		case strings.HasSuffix(path, "/sync"):
-			if r.Method == http.MethodPost {
-				argocdHandler.SyncApp(w, r)
-			} else {
-				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			}
-		case strings.Contains(path, "/pods/") && r.Method == http.MethodDelete:
-			argocdHandler.DeletePod(w, r)
-		default:
-			http.Error(w, "Not found", http.StatusNotFound)
-		}
-	})
+	// Check project budgets and alert on new breaches
+	budgetEvaluator.Start(cfg.BudgetEvaluationInterval)
 
-	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy"}`))
-	})
+	// Re-sync catalog files whose webhook was missed
+	if cfg.CatalogReconcileInterval > 0 {
+		catalog.NewScheduledReconciler(syncer).Start(cfg.CatalogReconcileInterval)
+	}
 
-	// Apply Auth middleware to all /api/* routes, then CORS
-	handler := applyMiddleware(
-		mux,
-		cfg,
-		[]string{"/health", "/auth/login", "/auth/github/login", "/auth/github/callback", "/api/v1/webhook/github"},
-	)
+	// Send opted-in teams their daily change digest
+	teamDigestJob.Start()
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Port)
 	log.Printf("🚀 Portalight backend starting on %s", addr)
-	log.Printf("📡 CORS allowed origins: %v", cfg.CORSAllowedOrigins)
+	log.Printf("📡 CORS allowed origins: %v (credentials: %t, preflight max age: %s)", cfg.CORSAllowedOrigins, cfg.CORSAllowCredentials, cfg.CORSMaxAge)
 
 	if err := http.ListenAndServe(addr, handler); err != nil {
 		log.Fatal(err)
 	}
 }
 
-// applyMiddleware applies auth middleware to all routes except excluded ones
-func applyMiddleware(handler http.Handler, cfg *config.Config, excludedPaths []string) http.Handler {
+// applyMiddleware applies auth middleware to all routes except excluded ones. Both
+// chains are built once; CORS answers preflights before auth or the concurrency limits.
+func applyMiddleware(handler http.Handler, cfg *config.Config, auth, concurrencyLimit func(http.Handler) http.Handler, excludedPaths []string) http.Handler {
+	cors := middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	})
+	// CORS and concurrency limits only
+	public := cors(concurrencyLimit(handler))
+	// CORS, concurrency limits (before auth, which hits the database) and Auth
+	protected := cors(concurrencyLimit(auth(handler)))
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if path should be excluded from auth
-		isExcluded := false
 		for _, path := range excludedPaths {
 			if r.URL.Path == path || r.URL.Path == path+"/" {
-				isExcluded = true
-				break
+				public.ServeHTTP(w, r)
+				return
 			}
 			if strings.Contains(path, "*") {
 				parts := strings.Split(path, "*")
 				if len(parts) == 2 && strings.HasPrefix(r.URL.Path, parts[0]) && strings.HasSuffix(r.URL.Path, parts[1]) {
-					isExcluded = true
-					break
+					public.ServeHTTP(w, r)
+					return
 				}
 			}
 		}
 
-		if isExcluded {
-			// Apply CORS only
-			middleware.CORS(cfg.CORSAllowedOrigins)(handler).ServeHTTP(w, r)
-			return
-		}
-
-		// Apply both Auth and CORS middleware for protected routes
-		middleware.CORS(cfg.CORSAllowedOrigins)(
-			middleware.AuthMiddleware(cfg)(handler),
-		).ServeHTTP(w, r)
+		protected.ServeHTTP(w, r)
 	})
 }
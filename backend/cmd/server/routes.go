@@ -0,0 +1,264 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/handlers"
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/api/router"
+	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/config"
+	"github.com/portalight/backend/internal/services"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// newRouter builds the handlers and registers every route. Each route declares its
+// method and access policy; public routes form the auth exclusion list and
+// GET /api/v1/admin/routes lists them all.
+func newRouter(cfg *config.Config, deps *handlers.Deps, syncer *catalog.Syncer, teamSync *catalog.GitHubTeamSyncService, notifier *services.UserNotifier, budgetEvaluator *services.BudgetEvaluator) *router.Router {
+	// Initialize handlers
+	secretHandler := handlers.NewSecretHandler(deps)
+	provisionLimiter := services.NewProvisionLimiter(cfg.ProvisionMaxConcurrency, cfg.ProvisionQueueTimeout)
+	provisionHandler := handlers.NewProvisionHandler(deps, provisionLimiter, notifier, cfg.RequireTicketURL)
+	authHandler := handlers.NewAuthHandler(deps, cfg)
+	catalogHandler := handlers.NewCatalogHandler(deps, syncer, cfg.PublicURL)
+	webhookHandler := handlers.NewGitHubWebhookHandler(deps, syncer)
+	projectSyncHandler := handlers.NewProjectSyncHandler(deps, syncer)
+	credentialsHandler := handlers.NewCredentialsHandler(deps)
+	servicesHandler := handlers.NewServicesHandler(deps)
+	usersHandler := handlers.NewUsersHandler(deps)
+	teamsHandler := handlers.NewTeamsHandler(deps)
+	teamDigestHandler := handlers.NewTeamDigestHandler(deps)
+	teamNotificationsHandler := handlers.NewTeamNotificationsHandler(deps)
+	projectsHandler := handlers.NewProjectsHandler(deps)
+	searchHandler := handlers.NewSearchHandler(deps)
+	auditLogsHandler := handlers.NewAuditLogsHandler(deps)
+
+	budgetHandler := handlers.NewBudgetHandler(deps, budgetEvaluator)
+
+	mux := router.New()
+
+	// Auth endpoints
+	mux.HandleFunc("POST /auth/login", router.Public, authHandler.HandleLogin) // Username/password login
+	mux.HandleFunc("GET /auth/github/login", router.Public, authHandler.HandleGithubLogin)
+	mux.HandleFunc("GET /auth/github/callback", router.Public, authHandler.HandleGithubCallback)
+	mux.HandleFunc("GET /auth/gitlab/login", router.Public, authHandler.HandleGitlabLogin) // gitlab.com or GITLAB_URL
+	mux.HandleFunc("GET /auth/gitlab/callback", router.Public, authHandler.HandleGitlabCallback)
+	mux.HandleFunc("GET /auth/oidc/login", router.Public, authHandler.HandleOIDCLogin) // OpenID Connect, e.g. Google Workspace
+	mux.HandleFunc("GET /auth/oidc/callback", router.Public, authHandler.HandleOIDCCallback)
+	mux.HandleFunc("POST /auth/logout", router.Authenticated, authHandler.HandleLogout)
+	mux.HandleFunc("POST /auth/refresh", router.Authenticated, authHandler.HandleRefresh)
+
+	// Services API
+	serviceLinksHandler := handlers.NewServiceLinksHandler(deps)
+	serviceResourcesHandler := handlers.NewServiceResourcesHandler(deps)
+	serviceEnvironmentHandler := handlers.NewServiceEnvironmentHandler(deps)
+	serviceDependencyHandler := handlers.NewServiceDependencyHandler(deps)
+
+	mux.HandleFunc("GET /api/v1/services", router.Authenticated, servicesHandler.GetServices)
+	mux.HandleFunc("GET /api/v1/services/tags", router.Authenticated, servicesHandler.GetServiceTags)
+	mux.HandleFunc("GET /api/v1/services/{id}", router.Authenticated, servicesHandler.GetServiceByID)
+	mux.HandleFunc("PUT /api/v1/services/{id}", router.Lead, servicesHandler.UpdateService)
+	mux.HandleFunc("PATCH /api/v1/services/{id}", router.Lead, servicesHandler.UpdateService)
+
+	// Service links and resource mappings
+	mux.HandleFunc("GET /api/v1/services/{id}/links", router.Authenticated, serviceLinksHandler.GetLinks)
+	mux.HandleFunc("POST /api/v1/services/{id}/links", router.Lead, serviceLinksHandler.AddLink)
+	mux.HandleFunc("PUT /api/v1/services/{id}/links/{linkID}", router.Lead, serviceLinksHandler.UpdateLink)
+	mux.HandleFunc("DELETE /api/v1/services/{id}/links/{linkID}", router.Lead, serviceLinksHandler.DeleteLink)
+	mux.HandleFunc("GET /api/v1/services/{id}/environments", router.Authenticated, serviceEnvironmentHandler.ListEnvironments)
+	mux.HandleFunc("POST /api/v1/services/{id}/environments", router.Lead, serviceEnvironmentHandler.AddEnvironment)
+	mux.HandleFunc("DELETE /api/v1/services/{id}/environments/{name}", router.Lead, serviceEnvironmentHandler.DeleteEnvironment)
+	mux.HandleFunc("GET /api/v1/services/{id}/resources", router.Authenticated, serviceResourcesHandler.GetResources)
+	mux.HandleFunc("POST /api/v1/services/{id}/resources", router.Lead, serviceResourcesHandler.MapResource)
+	mux.HandleFunc("DELETE /api/v1/services/{id}/resources/{resourceID}", router.Lead, serviceResourcesHandler.UnmapResource)
+	mux.HandleFunc("GET /api/v1/services/{id}/dependencies", router.Authenticated, serviceDependencyHandler.GetDependencies)
+
+	// Service health combines ArgoCD, AWS resource and metric state
+	serviceHealthHandler := handlers.NewServiceHealthHandler(deps)
+	mux.HandleFunc("GET /api/v1/services/{id}/health", router.Authenticated, serviceHealthHandler.GetServiceHealth)
+	mux.HandleFunc("GET /api/v1/projects/{id}/services/health", router.Authenticated.WithChecks("non-superadmins only see projects they can access"), serviceHealthHandler.GetProjectServicesHealth)
+
+	// Service readiness scorecards against catalog completeness checks
+	scorecardHandler := handlers.NewScorecardHandler(deps)
+	mux.HandleFunc("GET /api/v1/services/{id}/scorecard", router.Authenticated, scorecardHandler.GetServiceScorecard)
+	mux.HandleFunc("GET /api/v1/projects/{id}/scorecard", router.Authenticated.WithChecks("non-superadmins only see projects they can access"), scorecardHandler.GetProjectScorecard)
+
+	// CI status comes from GitHub Actions through the catalog's GitHub integration
+	serviceCIHandler := handlers.NewServiceCIHandler(deps, syncer)
+	mux.HandleFunc("GET /api/v1/services/{id}/ci-status", router.Authenticated, serviceCIHandler.GetCIStatus)
+
+	// Secret management endpoints (legacy)
+	mux.HandleFunc("GET /api/v1/secrets", router.Authenticated, secretHandler.GetSecrets)
+
+	// AWS Credentials management
+	adminIPAllowlist := middleware.IPAllowlist(cfg.AdminIPAllowlist, cfg.TrustedProxies)
+	mux.Handle("GET /api/v1/credentials", router.Authenticated.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(credentialsHandler.ListCredentials)))
+	mux.Handle("POST /api/v1/credentials", router.Superadmin.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(credentialsHandler.CreateCredential)))
+	mux.Handle("GET /api/v1/credentials/{id}/usage", router.Superadmin.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(credentialsHandler.GetCredentialUsage)))
+	mux.Handle("PUT /api/v1/credentials/{id}/rotate", router.Superadmin.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(credentialsHandler.RotateCredential)))
+	mux.Handle("DELETE /api/v1/credentials/{id}", router.Superadmin.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(credentialsHandler.DeleteCredential)))
+
+	// Provisioning endpoints
+	mux.HandleFunc("POST /api/v1/provision", router.Authenticated.WithChecks("devs need a provisioning permission for the resource type"), provisionHandler.ProvisionResource)
+	mux.HandleFunc("POST /api/v1/provision/{resourceID}/approve", router.Lead.WithChecks("leads only approve requests of projects they can access"), provisionHandler.ApproveProvisioning)
+	mux.HandleFunc("POST /api/v1/provision/{resourceID}/reject", router.Lead.WithChecks("leads only reject requests of projects they can access"), provisionHandler.RejectProvisioning)
+	mux.HandleFunc("GET /api/v1/provision/types", router.Authenticated, provisionHandler.GetProvisionTypes)
+	mux.HandleFunc("GET /api/v1/provision/types/{type}", router.Authenticated, provisionHandler.GetProvisionTypeSchema)
+	mux.HandleFunc("GET /api/v1/resources", router.Authenticated.WithChecks("leads only see projects they can access, devs only projects of their teams"), provisionHandler.ListResources)
+	mux.HandleFunc("GET /api/v1/resources/{id}", router.Authenticated, provisionHandler.GetResource)
+	mux.HandleFunc("DELETE /api/v1/resources/{id}", router.Lead, provisionHandler.DeprovisionResource)
+
+	// Discovery endpoints
+	discoveryHandler := handlers.NewDiscoveryHandler(deps)
+	mux.HandleFunc("POST /api/v1/discover", router.Lead, discoveryHandler.DiscoverResources)
+
+	// Resource metrics endpoints
+	resourceDetailsHandler := handlers.NewResourceDetailsHandler(deps)
+	mux.HandleFunc("POST /api/v1/resources/metrics", router.Authenticated, resourceDetailsHandler.GetResourceMetrics)
+	mux.HandleFunc("GET /api/v1/resources/alarms", router.Authenticated, resourceDetailsHandler.GetResourceAlarms)
+
+	// Sync endpoints
+	syncHandler := handlers.NewSyncHandler(deps)
+	mux.HandleFunc("POST /api/v1/resources/sync", router.Lead, syncHandler.SyncProjectResources)
+	mux.HandleFunc("POST /api/v1/resources/associate", router.Lead, syncHandler.AssociateResources)
+	mux.HandleFunc("POST /api/v1/resources/associate/bulk", router.Lead, syncHandler.BulkAssociateResources)
+	mux.HandleFunc("GET /api/v1/resources/discovered", router.Authenticated, syncHandler.GetProjectDiscoveredResources)
+	mux.HandleFunc("GET /api/v1/resources/discovered/{id}", router.Authenticated, resourceDetailsHandler.GetResourceByID)
+	mux.HandleFunc("PATCH /api/v1/resources/discovered/{id}", router.Lead.WithChecks("leads need access to the resource's project"), resourceDetailsHandler.UpdateDiscoveredResource)
+	mux.HandleFunc("GET /api/v1/resources/discovered/{id}/subscriptions", router.Authenticated, resourceDetailsHandler.GetSubscriptions)
+	mux.HandleFunc("POST /api/v1/resources/discovered/{id}/subscriptions", router.Lead, resourceDetailsHandler.CreateSubscription)
+	mux.HandleFunc("DELETE /api/v1/resources/discovered/{id}", router.Lead, syncHandler.RemoveDiscoveredResource)
+
+	// Repository management endpoints
+	mux.HandleFunc("POST /api/v1/register", router.Authenticated, handlers.RegisterRepository)
+
+	// User routes
+	mux.HandleFunc("GET /api/v1/users/current", router.Authenticated, usersHandler.GetCurrentUser)
+	mux.HandleFunc("GET /api/v1/users", router.Authenticated, usersHandler.GetUsers)
+	mux.HandleFunc("POST /api/v1/users/create", router.Superadmin, usersHandler.CreateUser)
+	mux.HandleFunc("PUT /api/v1/users/{id}", router.Authenticated.WithChecks("leads: team changes within their own teams; devs: own name only"), usersHandler.UpdateUser)
+	mux.HandleFunc("PATCH /api/v1/users/{id}", router.Authenticated.WithChecks("leads: team changes within their own teams; devs: own name only"), usersHandler.UpdateUser)
+	mux.HandleFunc("DELETE /api/v1/users/{id}", router.Superadmin, usersHandler.DeleteUser)
+
+	// Dev provisioning permissions endpoints
+	devPermissionsHandler := handlers.NewDevPermissionsHandler(deps)
+	mux.HandleFunc("GET /api/v1/users/{id}/provisioning-permissions", router.Authenticated, devPermissionsHandler.GetDevPermissions)
+	mux.Handle("PUT /api/v1/users/{id}/provisioning-permissions", router.Lead.WithChecks("source IP in ADMIN_IP_ALLOWLIST"), adminIPAllowlist(http.HandlerFunc(devPermissionsHandler.UpdateDevPermissions)))
+
+	// Team management endpoints
+	mux.HandleFunc("GET /api/v1/teams", router.Authenticated, teamsHandler.GetTeams)
+	mux.HandleFunc("POST /api/v1/teams", router.Authenticated, teamsHandler.CreateTeam)
+	mux.HandleFunc("DELETE /api/v1/teams/{id}", router.Authenticated, teamsHandler.DeleteTeam)
+	mux.HandleFunc("PUT /api/v1/teams/members", router.Authenticated, teamsHandler.UpdateTeamMembers)
+	mux.HandleFunc("POST /api/v1/teams/{id}/members/import", router.Superadmin, teamsHandler.ImportTeamMembers)
+	mux.HandleFunc("GET /api/v1/teams/digest", router.Authenticated, teamDigestHandler.HandleTeamDigest)
+	mux.HandleFunc("PUT /api/v1/teams/digest", router.Lead.WithChecks("leads must be a member of the team"), teamDigestHandler.HandleTeamDigest)
+	mux.HandleFunc("GET /api/v1/teams/{id}/notifications", router.Lead.WithChecks("leads must be a member of the team"), teamNotificationsHandler.ListChannels)
+	mux.HandleFunc("POST /api/v1/teams/{id}/notifications", router.Lead.WithChecks("leads must be a member of the team"), teamNotificationsHandler.CreateChannel)
+	mux.HandleFunc("DELETE /api/v1/teams/{id}/notifications/{channelID}", router.Lead.WithChecks("leads must be a member of the team"), teamNotificationsHandler.DeleteChannel)
+
+	// Project management endpoints
+	mux.HandleFunc("GET /api/v1/projects", router.Authenticated, projectsHandler.GetProjects)
+	mux.HandleFunc("POST /api/v1/projects", router.Authenticated, projectsHandler.CreateProject)
+	mux.HandleFunc("GET /api/v1/projects/{id}", router.Authenticated, projectsHandler.GetProjectByID)
+	mux.HandleFunc("GET /api/v1/projects/{id}/stats", router.Authenticated.WithChecks("non-superadmins only see projects they can access"), projectsHandler.GetProjectStats)
+	mux.HandleFunc("GET /api/v1/projects/{id}/docs", router.Authenticated.WithChecks("non-superadmins only see projects they can access"), projectsHandler.GetProjectDocs)
+	mux.HandleFunc("PUT /api/v1/projects/{id}", router.Authenticated.WithChecks("secret_id: superadmins, or leads who can access the project"), projectsHandler.UpdateProject)
+	mux.HandleFunc("PATCH /api/v1/projects/{id}", router.Authenticated.WithChecks("secret_id: superadmins, or leads who can access the project"), projectsHandler.UpdateProject)
+	mux.HandleFunc("DELETE /api/v1/projects/{id}", router.Authenticated, projectsHandler.DeleteProject)
+	mux.HandleFunc("PUT /api/v1/projects/access", router.Authenticated, projectsHandler.UpdateProjectAccess)
+	mux.HandleFunc("POST /api/v1/projects/{id}/sync", router.Authenticated, projectSyncHandler.SyncProject)
+	mux.HandleFunc("POST /api/v1/projects/{id}/reconcile", router.Lead.WithChecks("leads: projects they can access"), projectSyncHandler.ReconcileProject)
+	mux.HandleFunc("GET /api/v1/projects/{id}/sync-history", router.Lead.WithChecks("leads only see projects they can access"), projectSyncHandler.GetProjectSyncHistory)
+	mux.HandleFunc("GET /api/v1/sync-history", router.Superadmin, projectSyncHandler.GetSyncHistory)
+	mux.HandleFunc("GET /api/v1/projects/{id}/resources", router.Authenticated, provisionHandler.GetProjectResources)
+	mux.HandleFunc("GET /api/v1/projects/{id}/resources/export", router.Authenticated.WithChecks("non-superadmins only export projects they can access"), provisionHandler.ExportProjectResources)
+
+	// Project budgets
+	mux.HandleFunc("GET /api/v1/projects/{id}/budgets", router.Authenticated, budgetHandler.HandleBudgets)
+	mux.HandleFunc("POST /api/v1/projects/{id}/budgets", router.Lead, budgetHandler.HandleBudgets)
+	mux.HandleFunc("GET /api/v1/projects/{id}/budgets/events", router.Authenticated, budgetHandler.HandleBudgets)
+	mux.HandleFunc("PUT /api/v1/projects/{id}/budgets/{budgetID}", router.Lead, budgetHandler.HandleBudgets)
+	mux.HandleFunc("PATCH /api/v1/projects/{id}/budgets/{budgetID}", router.Lead, budgetHandler.HandleBudgets)
+	mux.HandleFunc("DELETE /api/v1/projects/{id}/budgets/{budgetID}", router.Lead, budgetHandler.HandleBudgets)
+
+	// Catalog endpoints
+	mux.HandleFunc("GET /api/v1/catalog/config", router.Authenticated, catalogHandler.GetConfig)
+	mux.HandleFunc("POST /api/v1/catalog/config", router.Authenticated, catalogHandler.UpdateConfig)
+	mux.HandleFunc("PUT /api/v1/catalog/config", router.Authenticated, catalogHandler.UpdateConfig)
+	mux.HandleFunc("GET /api/v1/catalog/scan", router.Authenticated, catalogHandler.Scan)
+	mux.HandleFunc("GET /api/v1/catalog/status", router.Authenticated, catalogHandler.GetStatus)
+	mux.HandleFunc("POST /api/v1/catalog/preview", router.Authenticated, catalogHandler.Preview)
+	mux.HandleFunc("POST /api/v1/catalog/validate", router.Public, catalogHandler.Validate) // Lints a file without the database; used from CI
+	mux.HandleFunc("POST /api/v1/catalog/sync", router.Authenticated, catalogHandler.Sync)
+	mux.HandleFunc("POST /api/v1/catalog/reconcile", router.Superadmin, catalogHandler.Reconcile)
+	mux.HandleFunc("POST /api/v1/catalog/webhook/setup", router.Superadmin, catalogHandler.SetupWebhook)
+	mux.HandleFunc("DELETE /api/v1/catalog/webhook/setup", router.Superadmin, catalogHandler.DeleteWebhook)
+	mux.HandleFunc("POST /api/v1/catalog/sync-teams", router.Superadmin, handlers.NewGitHubTeamSyncHandler(deps, teamSync).SyncTeams)
+
+	// Service tag vocabulary, applied during catalog sync
+	tagVocabularyHandler := handlers.NewTagVocabularyHandler(deps)
+	mux.HandleFunc("GET /api/v1/settings/tag-vocabulary", router.Authenticated, tagVocabularyHandler.GetTagVocabulary)
+	mux.HandleFunc("PUT /api/v1/settings/tag-vocabulary", router.Superadmin, tagVocabularyHandler.UpdateTagVocabulary)
+
+	// Email notifications: SMTP server and per-user event opt-outs
+	notificationSettingsHandler := handlers.NewNotificationSettingsHandler(deps)
+	mux.HandleFunc("GET /api/v1/settings/smtp", router.Superadmin, notificationSettingsHandler.GetSMTPSettings)
+	mux.HandleFunc("PUT /api/v1/settings/smtp", router.Superadmin, notificationSettingsHandler.UpdateSMTPSettings)
+	mux.HandleFunc("GET /api/v1/users/current/notification-preferences", router.Authenticated, notificationSettingsHandler.GetNotificationPreferences)
+	mux.HandleFunc("PUT /api/v1/users/current/notification-preferences", router.Authenticated, notificationSettingsHandler.UpdateNotificationPreferences)
+
+	// GitHub Webhook endpoint (no auth required - validated by signature)
+	mux.HandleFunc("POST /api/v1/webhook/github", router.Public.WithChecks("HMAC signature of the webhook secret"), webhookHandler.HandleWebhook)
+
+	// Search endpoint (all authenticated roles)
+	searchRateLimit := middleware.UserRateLimit("search", cfg.SearchRateLimit)
+	mux.Handle("GET /api/v1/search", router.Authenticated.WithChecks("non-superadmins only see projects they can access; rate limited per user"), searchRateLimit(http.HandlerFunc(searchHandler.Search)))
+
+	// Audit log endpoints
+	mux.HandleFunc("GET /api/v1/audit-logs", router.Authenticated, auditLogsHandler.GetAuditLogs)
+	mux.HandleFunc("GET /api/v1/projects/{id}/audit-logs", router.Authenticated.WithChecks("non-superadmins only see projects they can access"), auditLogsHandler.GetProjectAuditLogs)
+	auditExportHandler := handlers.NewAuditExportHandler(deps)
+	mux.HandleFunc("GET /api/v1/audit-logs/export", router.Superadmin, auditExportHandler.Export)
+	accessReportHandler := handlers.NewAccessReportHandler(deps)
+	mux.HandleFunc("GET /api/v1/admin/access-report", router.Superadmin, accessReportHandler.Export)
+
+	// ArgoCD integration endpoints
+	argocdHandler := handlers.NewArgoCDHandler(deps)
+	mux.HandleFunc("GET /api/v1/argocd/config", router.Authenticated, argocdHandler.GetConfig)
+	mux.HandleFunc("GET /api/v1/argocd/applications", router.Authenticated, argocdHandler.ListApplications)
+	mux.HandleFunc("GET /api/v1/argocd/service/{id}/apps", router.Authenticated, argocdHandler.GetServiceApps)
+	mux.HandleFunc("POST /api/v1/argocd/service/{id}/apps", router.Lead, argocdHandler.LinkApp)
+	mux.HandleFunc("DELETE /api/v1/argocd/service/{id}/apps/{appID}", router.Lead, argocdHandler.UnlinkApp)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/status", router.Authenticated, argocdHandler.GetAppStatus)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/operation", router.Authenticated, argocdHandler.GetOperation)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/events", router.Authenticated, argocdHandler.GetEvents)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/pods", router.Authenticated, argocdHandler.GetAppPods)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/pods/{pod}/logs", router.Authenticated, argocdHandler.GetPodLogs)
+	mux.HandleFunc("GET /api/v1/argocd/apps/{app}/pods/{pod}/describe", router.Authenticated, argocdHandler.DescribePod)
+	mux.HandleFunc("DELETE /api/v1/argocd/apps/{app}/pods/{pod}", router.Lead, argocdHandler.DeletePod)
+	mux.HandleFunc("POST /api/v1/argocd/apps/{app}/sync", router.Lead, argocdHandler.SyncApp)
+	mux.HandleFunc("POST /api/v1/argocd/apps/{app}/resources/{kind}/{name}/restart", router.Lead, argocdHandler.RestartWorkload)
+
+	// Provisioned resources vs. the discovered resources tracking them
+	reconciliationHandler := handlers.NewResourceReconciliationHandler(deps)
+	mux.HandleFunc("GET /api/v1/admin/resource-divergences", router.Superadmin, reconciliationHandler.GetDivergences)
+	mux.HandleFunc("POST /api/v1/admin/resource-divergences/repair", router.Superadmin, reconciliationHandler.RepairDivergences)
+
+	// Route inventory for security review
+	mux.HandleFunc("GET /api/v1/admin/routes", router.Superadmin, handlers.NewRoutesHandler(mux.Routes).ListRoutes)
+
+	// Health check
+	mux.HandleFunc("GET /health", router.Public, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"healthy"}`))
+	})
+	// Database reachability and connection pool statistics; pool internals are for superadmins only
+	mux.HandleFunc("GET /health/deep", router.Superadmin, handlers.NewHealthHandler().DeepHealth)
+
+	// Prometheus metrics
+	mux.Handle("GET /metrics", router.Public, promhttp.Handler())
+
+	return mux
+}
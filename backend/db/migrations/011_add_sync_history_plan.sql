@@ -0,0 +1,5 @@
+-- Record the resolved sync plan on each catalog sync
+-- Migration: Add plan to catalog_sync_history
+
+ALTER TABLE catalog_sync_history
+ADD COLUMN IF NOT EXISTS plan JSONB;
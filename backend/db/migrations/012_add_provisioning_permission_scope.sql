@@ -0,0 +1,21 @@
+-- Scope dev provisioning permissions to a project and/or AWS credential
+-- Migration: Add project_id and credential_id to user_provisioning_permissions
+-- Rows with NULL project_id / credential_id keep granting global access
+
+ALTER TABLE user_provisioning_permissions
+ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+ADD COLUMN IF NOT EXISTS credential_id UUID REFERENCES secrets(id) ON DELETE CASCADE;
+
+-- The same resource type can now be granted once per scope
+ALTER TABLE user_provisioning_permissions
+DROP CONSTRAINT IF EXISTS user_provisioning_permissions_user_id_resource_type_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_provisioning_permissions_scope
+ON user_provisioning_permissions (
+    user_id,
+    resource_type,
+    COALESCE(project_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(credential_id, '00000000-0000-0000-0000-000000000000'::uuid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_provisioning_permissions_project ON user_provisioning_permissions(project_id);
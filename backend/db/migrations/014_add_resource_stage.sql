@@ -0,0 +1,2 @@
+-- Add a human-readable provisioning stage (e.g. "queued (3 ahead)") to resources
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS stage VARCHAR(100);
@@ -0,0 +1,10 @@
+-- Full-text search over services and projects
+-- Migration: Add GIN indexes on name + description
+-- NOTE: CREATE INDEX CONCURRENTLY cannot run inside a transaction block,
+-- so apply this file without wrapping it in BEGIN/COMMIT.
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_services_search
+    ON services USING GIN (to_tsvector('english', name || ' ' || COALESCE(description, '')));
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projects_search
+    ON projects USING GIN (to_tsvector('english', name || ' ' || COALESCE(description, '')));
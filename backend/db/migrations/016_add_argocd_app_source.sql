@@ -0,0 +1,4 @@
+-- Track where an ArgoCD app link came from so catalog sync only manages its own links
+-- Migration: Add source column to service_argocd_apps
+
+ALTER TABLE service_argocd_apps ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual';
@@ -0,0 +1,19 @@
+-- Substring/fuzzy search over projects, services and discovered resources
+-- Migration: Enable pg_trgm and add trigram indexes
+-- NOTE: CREATE INDEX CONCURRENTLY cannot run inside a transaction block,
+-- so apply this file without wrapping it in BEGIN/COMMIT.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projects_name_trgm
+    ON projects USING GIN (name gin_trgm_ops);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_projects_description_trgm
+    ON projects USING GIN (description gin_trgm_ops);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_services_name_trgm
+    ON services USING GIN (name gin_trgm_ops);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_discovered_resources_name_trgm
+    ON discovered_resources USING GIN (name gin_trgm_ops);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_discovered_resources_arn_trgm
+    ON discovered_resources USING GIN (arn gin_trgm_ops);
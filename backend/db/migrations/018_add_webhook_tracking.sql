@@ -0,0 +1,14 @@
+-- Track GitHub webhook deliveries so operators can see whether webhooks arrive
+-- Migration: Add last_webhook_received_at and webhook_deliveries
+
+ALTER TABLE github_metadata_config ADD COLUMN IF NOT EXISTS last_webhook_received_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id VARCHAR(100),          -- X-GitHub-Delivery header
+    event_type VARCHAR(50) NOT NULL,   -- X-GitHub-Event header
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Used to count deliveries in the last 24 hours and to purge old rows
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_received_at ON webhook_deliveries(received_at);
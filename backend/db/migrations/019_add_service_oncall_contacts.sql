@@ -0,0 +1,5 @@
+-- On-call contacts resolved from the catalog (service oncall block or project contacts)
+-- Migration: Add slack_channel and pagerduty_service columns to services
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS slack_channel VARCHAR(255);
+ALTER TABLE services ADD COLUMN IF NOT EXISTS pagerduty_service VARCHAR(255);
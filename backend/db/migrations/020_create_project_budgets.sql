@@ -0,0 +1,31 @@
+-- Soft guardrails per project: alert when resource counts or estimated cost exceed a threshold
+-- Migration: Create project_budgets and budget_events tables
+
+CREATE TABLE IF NOT EXISTS project_budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('resource_count', 'estimated_cost')),
+    resource_type VARCHAR(50), -- resource_count only; NULL counts all resource types
+    threshold NUMERIC(14, 2) NOT NULL CHECK (threshold >= 0),
+    notify_channel TEXT,       -- Incoming webhook URL (Slack compatible)
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_budgets_project_id ON project_budgets(project_id);
+
+-- One row per breach, open until the value drops back under the threshold
+CREATE TABLE IF NOT EXISTS budget_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES project_budgets(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    value NUMERIC(14, 2) NOT NULL,     -- Latest evaluated value while open
+    threshold NUMERIC(14, 2) NOT NULL, -- Threshold at the time of the breach
+    breached_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    evaluated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_budget_events_open ON budget_events(budget_id) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_budget_events_project_id ON budget_events(project_id, breached_at DESC);
@@ -0,0 +1,15 @@
+-- Allow several catalog paths (directories or single files) per GitHub config
+-- Migration: Replace projects_path with projects_paths
+
+ALTER TABLE github_metadata_config ADD COLUMN IF NOT EXISTS projects_paths TEXT[] NOT NULL DEFAULT ARRAY['projects'];
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'github_metadata_config' AND column_name = 'projects_path'
+    ) THEN
+        UPDATE github_metadata_config SET projects_paths = ARRAY[projects_path];
+        ALTER TABLE github_metadata_config DROP COLUMN projects_path;
+    END IF;
+END $$;
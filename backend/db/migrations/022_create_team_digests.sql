@@ -0,0 +1,19 @@
+-- Daily digest of catalog and resource changes, per team
+-- Migration: Create team_digest_settings and team_digest_deliveries
+
+CREATE TABLE IF NOT EXISTS team_digest_settings (
+    team_id UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',   -- IANA name, e.g. Europe/Berlin
+    slack_webhook_url TEXT,                        -- Incoming webhook URL
+    email_recipients TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One row per team and local day; claiming the row makes the digest job idempotent
+CREATE TABLE IF NOT EXISTS team_digest_deliveries (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    digest_date DATE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (team_id, digest_date)
+);
@@ -0,0 +1,13 @@
+-- Service dependency graph, populated from depends_on during catalog sync
+-- Migration: Create service_dependencies
+
+CREATE TABLE IF NOT EXISTS service_dependencies (
+    source_service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE, -- The dependent service
+    target_service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE, -- The service it depends on
+    dependency_type VARCHAR(50) NOT NULL DEFAULT 'runtime',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (source_service_id, target_service_id, dependency_type),
+    CHECK (source_service_id <> target_service_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_dependencies_target ON service_dependencies(target_service_id);
@@ -0,0 +1,7 @@
+-- Trigram indexes for the remaining ILIKE columns of the global search
+-- Migration: Add trigram indexes on services.description
+-- NOTE: CREATE INDEX CONCURRENTLY cannot run inside a transaction block,
+-- so apply this file without wrapping it in BEGIN/COMMIT.
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_services_description_trgm
+    ON services USING GIN (description gin_trgm_ops);
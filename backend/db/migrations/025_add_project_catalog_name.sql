@@ -0,0 +1,12 @@
+-- Catalog name (metadata.name) of synced projects, used by project-name/service-name
+-- references in dependsOn
+-- Migration: Add catalog_name to projects
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS catalog_name VARCHAR(255);
+
+-- Backfill from the stored catalog; it is serialised with Go field names
+UPDATE projects
+SET catalog_name = catalog_metadata->'Metadata'->>'Name'
+WHERE catalog_name IS NULL AND catalog_metadata IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_projects_catalog_name ON projects(catalog_name);
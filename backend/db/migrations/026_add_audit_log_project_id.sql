@@ -0,0 +1,23 @@
+-- Project an audit log entry belongs to, for per-project audit views
+-- Migration: Add project_id to audit_logs
+
+-- No foreign key: entries outlive the projects they describe
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS project_id UUID;
+
+-- Backfill entries whose resource_id is a project, a provisioned resource or a budget
+UPDATE audit_logs a
+SET project_id = p.id
+FROM projects p
+WHERE a.project_id IS NULL AND a.resource_id = p.id::text;
+
+UPDATE audit_logs a
+SET project_id = r.project_id
+FROM resources r
+WHERE a.project_id IS NULL AND a.resource_id = r.id::text;
+
+UPDATE audit_logs a
+SET project_id = b.project_id
+FROM project_budgets b
+WHERE a.project_id IS NULL AND a.resource_type = 'project_budget' AND a.resource_id = b.id::text;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_project_id ON audit_logs(project_id, timestamp DESC);
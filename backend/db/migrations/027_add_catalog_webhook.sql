@@ -0,0 +1,6 @@
+-- Webhook secret and GitHub hook ID for the catalog push webhook, managed by
+-- POST/DELETE /api/v1/catalog/webhook/setup
+-- Migration: Add webhook_secret and webhook_hook_id to github_metadata_config
+
+ALTER TABLE github_metadata_config ADD COLUMN IF NOT EXISTS webhook_secret TEXT;
+ALTER TABLE github_metadata_config ADD COLUMN IF NOT EXISTS webhook_hook_id BIGINT;
@@ -0,0 +1,17 @@
+-- Controlled vocabulary for service tags, applied during catalog sync
+-- Migration: Create tag_vocabulary
+
+CREATE TABLE IF NOT EXISTS tag_vocabulary (
+    id UUID PRIMARY KEY DEFAULT '00000000-0000-0000-0000-000000000001',
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    strict BOOLEAN NOT NULL DEFAULT false,  -- Unknown tags are dropped and reported as warnings
+    tags TEXT[] NOT NULL DEFAULT '{}',      -- Canonical tags
+    aliases JSONB NOT NULL DEFAULT '{}',    -- {"golang": "go"}
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Singleton, like github_metadata_config
+    CONSTRAINT single_tag_vocabulary_row CHECK (id = '00000000-0000-0000-0000-000000000001')
+);
+
+-- Tag filters and the tag counts endpoint unnest services.tags
+CREATE INDEX IF NOT EXISTS idx_services_tags ON services USING GIN (tags);
@@ -0,0 +1,6 @@
+-- Request metadata recorded by the audit middleware for every mutating API call
+-- Migration: Add route, status_code and duration_ms to audit_logs
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS route VARCHAR(255);  -- Route pattern, e.g. PUT /api/v1/projects/{id}
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS status_code INTEGER; -- HTTP response status
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS duration_ms INTEGER; -- Handler latency
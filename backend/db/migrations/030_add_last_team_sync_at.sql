@@ -0,0 +1,4 @@
+-- Time of the last GitHub team membership sync (POST /api/v1/catalog/sync-teams)
+-- Migration: Add last_team_sync_at to github_metadata_config
+
+ALTER TABLE github_metadata_config ADD COLUMN IF NOT EXISTS last_team_sync_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,23 @@
+-- Email notifications: SMTP transport configured in the UI and per-user event preferences
+-- Migration: Create smtp_settings and user_notification_preferences
+
+CREATE TABLE IF NOT EXISTS smtp_settings (
+    id UUID PRIMARY KEY DEFAULT '00000000-0000-0000-0000-000000000001',
+    host VARCHAR(255) NOT NULL DEFAULT '',        -- Empty falls back to the SMTP_* environment variables
+    port INTEGER NOT NULL DEFAULT 587,
+    tls_mode VARCHAR(20) NOT NULL DEFAULT 'starttls', -- starttls, tls or none
+    from_address VARCHAR(255) NOT NULL DEFAULT '',
+    username VARCHAR(255) NOT NULL DEFAULT '',
+    password_encrypted TEXT NOT NULL DEFAULT '',  -- AES-GCM, see internal/crypto
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Singleton, like github_metadata_config
+    CONSTRAINT single_smtp_settings_row CHECK (id = '00000000-0000-0000-0000-000000000001')
+);
+
+-- Users receive every event by default; only opt-outs are stored
+CREATE TABLE IF NOT EXISTS user_notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    disabled_events TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,24 @@
+-- A service runs in several environments at once, each with its own ArgoCD app and resources
+-- Migration: Create service_environments
+
+CREATE TABLE IF NOT EXISTS service_environments (
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    environment VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (service_id, environment)
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_environments_environment ON service_environments(environment);
+
+-- Backfill from the single environment column, which is kept for older clients
+INSERT INTO service_environments (service_id, environment)
+SELECT id, environment
+FROM services
+WHERE environment IS NOT NULL AND environment <> ''
+ON CONFLICT DO NOTHING;
+
+-- Catalog environment names are free-form ("production", "staging", "dev")
+ALTER TABLE services DROP CONSTRAINT IF EXISTS services_environment_check;
+
+-- Resources can belong to one environment of a service; NULL means every environment
+ALTER TABLE service_resource_mappings ADD COLUMN IF NOT EXISTS environment VARCHAR(50);
@@ -0,0 +1,16 @@
+-- Link each provisioned resource to its discovered_resources row so their statuses
+-- can be kept in step instead of drifting apart
+-- Migration: Add discovered_resources.resource_id
+
+ALTER TABLE discovered_resources
+    ADD COLUMN IF NOT EXISTS resource_id UUID REFERENCES resources(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_discovered_resources_resource ON discovered_resources(resource_id);
+
+-- Backfill rows auto-added by provisioning before the link existed
+UPDATE discovered_resources d
+SET resource_id = r.id
+FROM resources r
+WHERE d.resource_id IS NULL
+  AND r.project_id = d.project_id
+  AND r.arn = d.arn;
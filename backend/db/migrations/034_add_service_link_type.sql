@@ -0,0 +1,10 @@
+-- Links carry a semantic type so runbooks and incident trackers can be found without parsing labels
+-- Migration: Add type to service_links
+
+ALTER TABLE service_links ADD COLUMN IF NOT EXISTS type VARCHAR(30) NOT NULL DEFAULT 'generic';
+
+ALTER TABLE service_links DROP CONSTRAINT IF EXISTS service_links_type_check;
+ALTER TABLE service_links ADD CONSTRAINT service_links_type_check
+    CHECK (type IN ('generic', 'runbook', 'incident_tracker', 'monitoring', 'api_docs', 'repository'));
+
+CREATE INDEX IF NOT EXISTS idx_service_links_service_type ON service_links(service_id, type);
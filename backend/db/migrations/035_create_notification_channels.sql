@@ -0,0 +1,14 @@
+-- Per-team Slack and webhook channels notified about provisioning and sync events
+-- Migration: Create notification_channels
+
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('slack', 'webhook')),
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',  -- models.TeamEvent* names
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_channels_team ON notification_channels(team_id);
@@ -0,0 +1,6 @@
+-- Stable identity of users who sign in with OpenID Connect (e.g. Google Workspace)
+-- Migration: Add users.oidc_subject
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users(oidc_subject) WHERE oidc_subject IS NOT NULL;
@@ -0,0 +1,17 @@
+-- Stable identifier (metadata.name) of catalog projects. Unlike the display name
+-- (metadata.title) it does not change when a project is renamed, and no two catalog
+-- files may claim the same one.
+-- Migration: Add projects.slug
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- Backfill from catalog_name; when several projects share one, the oldest keeps it
+UPDATE projects p
+SET slug = p.catalog_name
+WHERE p.slug IS NULL AND p.catalog_name IS NOT NULL AND p.catalog_name <> ''
+  AND NOT EXISTS (
+    SELECT 1 FROM projects o
+    WHERE o.catalog_name = p.catalog_name AND (o.created_at, o.id) < (p.created_at, p.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_slug ON projects(slug) WHERE slug IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Request ID of the API call an audit entry was recorded for, to find its lines in the request log
+-- Migration: Add request_id to audit_logs
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS request_id VARCHAR(128);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs(request_id) WHERE request_id IS NOT NULL;
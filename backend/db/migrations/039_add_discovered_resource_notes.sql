@@ -0,0 +1,6 @@
+-- Free-form notes and an owner label leads attach to discovered resources, e.g.
+-- "legacy bucket, do not delete" owned by "payments-squad". Syncs never overwrite them.
+-- Migration: Add notes and owner_label to discovered_resources
+
+ALTER TABLE discovered_resources ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE discovered_resources ADD COLUMN IF NOT EXISTS owner_label VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Blob SHA of the catalog file as of the last sync. The scheduled reconciliation
+-- compares it against the repository tree to find files whose webhook was missed.
+-- Migration: Add last_synced_sha to projects
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS last_synced_sha VARCHAR(64);
@@ -0,0 +1,7 @@
+-- Identity of users who sign in with GitLab (gitlab.com or the instance at GITLAB_URL)
+-- Migration: Add users.gitlab_id and users.gitlab_username
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gitlab_id BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS gitlab_username VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_gitlab_id ON users(gitlab_id) WHERE gitlab_id IS NOT NULL;
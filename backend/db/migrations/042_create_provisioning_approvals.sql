@@ -0,0 +1,17 @@
+-- Dev provisioning requests wait here for a lead to approve or reject them
+-- Migration: Create provisioning_approvals
+
+CREATE TABLE IF NOT EXISTS provisioning_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_id UUID NOT NULL REFERENCES resources(id) ON DELETE CASCADE,
+    secret_id UUID NOT NULL REFERENCES secrets(id) ON DELETE CASCADE,  -- Credential the resource is provisioned with once approved
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_by UUID REFERENCES users(id) ON DELETE SET NULL,  -- Lead who approved or rejected the request
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reason TEXT NOT NULL DEFAULT '',  -- Why the request was rejected
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_provisioning_approvals_resource ON provisioning_approvals(resource_id);
+CREATE INDEX IF NOT EXISTS idx_provisioning_approvals_pending ON provisioning_approvals(status) WHERE status = 'pending';
@@ -0,0 +1,13 @@
+-- Environments can be switched off without losing them, and point at their ArgoCD app
+-- Migration: Add is_active and argocd_app_id to service_environments
+
+ALTER TABLE service_environments ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE service_environments ADD COLUMN IF NOT EXISTS argocd_app_id UUID REFERENCES service_argocd_apps(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_service_environments_argocd_app ON service_environments(argocd_app_id);
+
+-- Link every environment to the app of the same name; service_argocd_apps has one app per environment
+UPDATE service_environments se
+SET argocd_app_id = a.id
+FROM service_argocd_apps a
+WHERE a.service_id = se.service_id AND LOWER(a.environment_name) = LOWER(se.environment);
@@ -0,0 +1,14 @@
+-- Markdown docs of catalog projects, fetched from the file named by metadata.docs
+-- on every sync. The blob SHA doubles as the ETag of GET /api/v1/projects/{id}/docs.
+-- Migration: Create project_docs table
+
+CREATE TABLE IF NOT EXISTS project_docs (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    content TEXT NOT NULL,
+    sha VARCHAR(64) NOT NULL,
+    fetched_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- The webhook looks projects up by the docs files a push changed
+CREATE INDEX IF NOT EXISTS idx_project_docs_path ON project_docs(path);
@@ -0,0 +1,5 @@
+-- Record why a resource was provisioned, shown to approvers and in listings
+-- Migration: Add reason and ticket_url to resources
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS ticket_url TEXT NOT NULL DEFAULT '';
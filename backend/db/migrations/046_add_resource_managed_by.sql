@@ -0,0 +1,10 @@
+-- Resources adopted by Terraform (or created outside the portal) must not be changed by it
+-- Migration: Add managed_by to resources and discovered_resources
+
+ALTER TABLE resources ADD COLUMN IF NOT EXISTS managed_by VARCHAR(20) NOT NULL DEFAULT 'portalight'
+    CHECK (managed_by IN ('portalight', 'terraform', 'manual'));
+ALTER TABLE discovered_resources ADD COLUMN IF NOT EXISTS managed_by VARCHAR(20) NOT NULL DEFAULT 'manual'
+    CHECK (managed_by IN ('portalight', 'terraform', 'manual'));
+
+-- Discovered resources tracking a provisioned resource were created by the portal
+UPDATE discovered_resources SET managed_by = 'portalight' WHERE resource_id IS NOT NULL;
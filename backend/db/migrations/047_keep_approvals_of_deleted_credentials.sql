@@ -0,0 +1,11 @@
+-- Deleting a credential must not silently drop the approvals of resources waiting for it:
+-- SecretRepository.Delete rejects pending approvals and fails their resources, then
+-- clears secret_id on the rows it keeps as history
+-- Migration: Make provisioning_approvals.secret_id nullable and restrict deletes
+
+ALTER TABLE provisioning_approvals ALTER COLUMN secret_id DROP NOT NULL;
+
+ALTER TABLE provisioning_approvals DROP CONSTRAINT IF EXISTS provisioning_approvals_secret_id_fkey;
+ALTER TABLE provisioning_approvals
+ADD CONSTRAINT provisioning_approvals_secret_id_fkey
+FOREIGN KEY (secret_id) REFERENCES secrets(id) ON DELETE RESTRICT;
@@ -0,0 +1,8 @@
+-- Provisioning permissions scoped to a credential are removed by SecretRepository.Delete,
+-- which lists them in the credential's usage first; the database no longer cascades
+-- Migration: Restrict deleting credentials referenced by user_provisioning_permissions
+
+ALTER TABLE user_provisioning_permissions DROP CONSTRAINT IF EXISTS user_provisioning_permissions_credential_id_fkey;
+ALTER TABLE user_provisioning_permissions
+ADD CONSTRAINT user_provisioning_permissions_credential_id_fkey
+FOREIGN KEY (credential_id) REFERENCES secrets(id) ON DELETE RESTRICT;
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// AccessReportHandler exports who can access which project, for access reviews
+type AccessReportHandler struct {
+	auditRecorder
+	accessReportRepo *repositories.AccessReportRepository
+}
+
+// NewAccessReportHandler creates a new access report handler
+func NewAccessReportHandler(deps *Deps) *AccessReportHandler {
+	return &AccessReportHandler{
+		auditRecorder:    newAuditRecorder(deps),
+		accessReportRepo: deps.AccessReport,
+	}
+}
+
+// Export handles GET /api/v1/admin/access-report?format=csv|json
+func (h *AccessReportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, "Invalid format. Supported formats: csv, json", http.StatusBadRequest)
+		return
+	}
+
+	setExportHeaders(w, "access-report", format)
+
+	count := 0
+	var err error
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"user_id", "user_email", "user_name", "user_role", "project_id", "project_name", "source", "team_name", "permission", "credential"})
+		err = h.accessReportRepo.ForEach(r.Context(), func(e models.AccessReportEntry) error {
+			count++
+			return writer.Write([]string{
+				e.UserID,
+				e.UserEmail,
+				e.UserName,
+				string(e.UserRole),
+				e.ProjectID,
+				e.ProjectName,
+				e.Source,
+				e.TeamName,
+				e.Permission,
+				e.Credential,
+			})
+		})
+		writer.Flush()
+	} else {
+		err = streamJSONArray(w, func(emit func(interface{}) error) error {
+			return h.accessReportRepo.ForEach(r.Context(), func(e models.AccessReportEntry) error {
+				count++
+				return emit(e)
+			})
+		})
+	}
+
+	status := "success"
+	if err != nil {
+		// Headers are already sent, so the client only sees a truncated file
+		log.Printf("Failed to export access report: %v", err)
+		status = "failed"
+	}
+
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "export_access_report",
+		ResourceType: "access_report",
+		Status:       status,
+		Details:      fmt.Sprintf("Exported %d access report entries as %s", count, format),
+	})
+}
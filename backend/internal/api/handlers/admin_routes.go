@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/router"
+)
+
+// RoutesHandler lists the registered API routes and their auth requirements for security review
+type RoutesHandler struct {
+	routes func() []router.Route
+}
+
+// NewRoutesHandler creates a routes handler reading from the live route table
+func NewRoutesHandler(routes func() []router.Route) *RoutesHandler {
+	return &RoutesHandler{routes: routes}
+}
+
+// ListRoutes handles GET /api/v1/admin/routes (superadmin)
+func (h *RoutesHandler) ListRoutes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.routes())
+}
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -14,15 +17,17 @@ import (
 
 // ArgoCDHandler handles ArgoCD-related HTTP requests
 type ArgoCDHandler struct {
+	auditRecorder
 	client *services.ArgoCDClient
 	repo   *repositories.ArgoCDRepository
 }
 
 // NewArgoCDHandler creates a new ArgoCD handler
-func NewArgoCDHandler() *ArgoCDHandler {
+func NewArgoCDHandler(deps *Deps) *ArgoCDHandler {
 	return &ArgoCDHandler{
-		client: services.NewArgoCDClient(),
-		repo:   repositories.NewArgoCDRepository(),
+		auditRecorder: newAuditRecorder(deps),
+		client:        deps.ArgoCD,
+		repo:          deps.ArgoCDApps,
 	}
 }
 
@@ -62,7 +67,7 @@ func (h *ArgoCDHandler) ListApplications(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	apps, err := h.client.ListApplications()
+	apps, err := h.client.ListApplications(r.Context())
 	if err != nil {
 		log.Printf("Failed to list ArgoCD applications: %v", err)
 		http.Error(w, "Failed to fetch applications from ArgoCD", http.StatusInternalServerError)
@@ -73,7 +78,7 @@ func (h *ArgoCDHandler) ListApplications(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(apps)
 }
 
-// GetServiceApps returns all ArgoCD apps linked to a service
+// GetServiceApps returns the ArgoCD apps linked to a service, optionally only those of ?environment=
 func (h *ArgoCDHandler) GetServiceApps(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -100,9 +105,14 @@ func (h *ArgoCDHandler) GetServiceApps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if apps == nil {
-		apps = []models.ServiceArgoCDApp{}
+	environment := r.URL.Query().Get("environment")
+	filtered := []models.ServiceArgoCDApp{}
+	for _, app := range apps {
+		if inEnvironment(app.EnvironmentName, environment) {
+			filtered = append(filtered, app)
+		}
 	}
+	apps = filtered
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(apps)
@@ -159,6 +169,14 @@ func (h *ArgoCDHandler) LinkApp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	middleware.EnrichAudit(ctx, models.AuditLog{
+		Action:       "link_argocd_app",
+		ResourceType: "service",
+		ResourceID:   serviceID,
+		ResourceName: req.ArgoCDAppName,
+		Details:      "Environment: " + req.EnvironmentName,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(app)
@@ -218,7 +236,7 @@ func (h *ArgoCDHandler) GetAppStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	appName := parts[0]
 
-	app, err := h.client.GetApplicationStatus(appName)
+	app, err := h.client.GetApplicationStatus(r.Context(), appName)
 	if err != nil {
 		log.Printf("Failed to get application status: %v", err)
 		http.Error(w, "Failed to fetch application status", http.StatusInternalServerError)
@@ -254,7 +272,9 @@ func (h *ArgoCDHandler) GetAppPods(w http.ResponseWriter, r *http.Request) {
 	}
 	appName := parts[0]
 
-	pods, err := h.client.GetApplicationPods(appName)
+	// Container requests and limits are opt-in
+	withResources := r.URL.Query().Get("resources") == "true"
+	pods, truncated, err := h.client.GetApplicationPods(r.Context(), appName, withResources)
 	if err != nil {
 		log.Printf("Failed to get application pods: %v", err)
 		http.Error(w, "Failed to fetch pods", http.StatusInternalServerError)
@@ -265,10 +285,40 @@ func (h *ArgoCDHandler) GetAppPods(w http.ResponseWriter, r *http.Request) {
 		pods = []models.ArgoCDPod{}
 	}
 
+	// Very large apps are capped; the body stays a plain list
+	if truncated {
+		w.Header().Set("X-Truncated", "true")
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(pods)
 }
 
+// DescribePod returns a pod's node, QoS class, conditions and container statuses,
+// including why each container last terminated
+func (h *ArgoCDHandler) DescribePod(w http.ResponseWriter, r *http.Request) {
+	if !h.client.IsConfigured() {
+		http.Error(w, "ArgoCD is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	appName := r.PathValue("app")
+	podName := r.PathValue("pod")
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	description, err := h.client.DescribePod(r.Context(), appName, podName, namespace)
+	if err != nil {
+		log.Printf("Failed to describe pod %s of %s: %v", podName, appName, err)
+		http.Error(w, "Failed to describe pod", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(description)
+}
+
 // GetPodLogs returns logs for a pod
 func (h *ArgoCDHandler) GetPodLogs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -303,7 +353,7 @@ func (h *ArgoCDHandler) GetPodLogs(w http.ResponseWriter, r *http.Request) {
 	container := r.URL.Query().Get("container")
 	tailLines := 500 // Default
 
-	logs, err := h.client.GetPodLogs(appName, podName, namespace, container, tailLines)
+	logs, err := h.client.GetPodLogs(r.Context(), appName, podName, namespace, container, tailLines)
 	if err != nil {
 		log.Printf("Failed to get pod logs: %v", err)
 		http.Error(w, "Failed to fetch logs", http.StatusInternalServerError)
@@ -345,7 +395,7 @@ func (h *ArgoCDHandler) DeletePod(w http.ResponseWriter, r *http.Request) {
 		namespace = "default"
 	}
 
-	if err := h.client.DeletePod(appName, podName, namespace); err != nil {
+	if err := h.client.DeletePod(r.Context(), appName, podName, namespace); err != nil {
 		log.Printf("Failed to delete pod: %v", err)
 		http.Error(w, "Failed to delete pod", http.StatusInternalServerError)
 		return
@@ -354,7 +404,74 @@ func (h *ArgoCDHandler) DeletePod(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// SyncApp triggers a sync for an application
+// RestartWorkload performs a rolling restart of a Deployment or StatefulSet
+func (h *ArgoCDHandler) RestartWorkload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Verify authentication - must be lead or superadmin
+	userRole := middleware.GetUserRole(ctx)
+	if userRole != "lead" && userRole != "superadmin" {
+		http.Error(w, "Forbidden: requires lead or superadmin role", http.StatusForbidden)
+		return
+	}
+
+	if !h.client.IsConfigured() {
+		http.Error(w, "ArgoCD is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Extract from URL: /api/v1/argocd/apps/{appName}/resources/{kind}/{name}/restart
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/argocd/apps/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[1] != "resources" || parts[2] == "" || parts[3] == "" {
+		http.Error(w, "Invalid path", http.StatusBadRequest)
+		return
+	}
+	appName, kind, name := parts[0], parts[2], parts[3]
+
+	namespace, err := h.client.RestartWorkload(r.Context(), appName, kind, name, r.URL.Query().Get("namespace"))
+	switch {
+	case errors.Is(err, services.ErrUnsupportedWorkloadKind):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, services.ErrWorkloadNotManaged):
+		http.Error(w, fmt.Sprintf("%s %s is not managed by ArgoCD application %s, so it cannot be restarted from the portal", kind, name, appName), http.StatusConflict)
+		return
+	case err != nil:
+		log.Printf("Failed to restart %s %s: %v", kind, name, err)
+		h.recordAudit(r.Context(), models.AuditLog{
+			UserEmail:    middleware.GetUserEmail(ctx),
+			Action:       "restart_workload",
+			ResourceType: "argocd_" + strings.ToLower(kind),
+			ResourceID:   appName + "/" + name,
+			ResourceName: name,
+			Status:       "failed",
+			Details:      err.Error(),
+		})
+		http.Error(w, "Failed to restart workload", http.StatusInternalServerError)
+		return
+	}
+
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "restart_workload",
+		ResourceType: "argocd_" + strings.ToLower(kind),
+		ResourceID:   appName + "/" + name,
+		ResourceName: name,
+		Status:       "success",
+		Details:      fmt.Sprintf("Rolling restart of %s %s/%s in application %s", kind, namespace, name, appName),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":    "restarting",
+		"kind":      kind,
+		"name":      name,
+		"namespace": namespace,
+	})
+}
+
+// SyncApp triggers a sync for an application, optionally pruning and/or at a given revision
 func (h *ArgoCDHandler) SyncApp(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -379,7 +496,16 @@ func (h *ArgoCDHandler) SyncApp(w http.ResponseWriter, r *http.Request) {
 	}
 	appName := parts[0]
 
-	if err := h.client.SyncApplication(appName); err != nil {
+	// The body is optional: {"prune": true, "revision": "..."}
+	var opts models.ArgoCDSyncOptions
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.client.SyncApplication(r.Context(), appName, opts); err != nil {
 		log.Printf("Failed to sync application: %v", err)
 		http.Error(w, "Failed to sync application", http.StatusInternalServerError)
 		return
@@ -388,3 +514,42 @@ func (h *ArgoCDHandler) SyncApp(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "sync initiated"})
 }
+
+// GetOperation returns the state of an application's current or last sync, so the UI
+// can follow a sync started with SyncApp. The body is null if it was never synced.
+func (h *ArgoCDHandler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	if !h.client.IsConfigured() {
+		http.Error(w, "ArgoCD is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	appName := r.PathValue("app")
+	state, err := h.client.GetOperationState(r.Context(), appName)
+	if err != nil {
+		log.Printf("Failed to get operation state of %s: %v", appName, err)
+		http.Error(w, "Failed to fetch sync operation", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// GetEvents returns the Kubernetes events of an application, most recent first
+func (h *ArgoCDHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	if !h.client.IsConfigured() {
+		http.Error(w, "ArgoCD is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	appName := r.PathValue("app")
+	events, err := h.client.GetApplicationEvents(r.Context(), appName)
+	if err != nil {
+		log.Printf("Failed to get events of %s: %v", appName, err)
+		http.Error(w, "Failed to fetch application events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
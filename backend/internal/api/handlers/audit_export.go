@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// AuditExportHandler handles audit log exports for compliance reviews
+type AuditExportHandler struct {
+	auditRecorder
+	auditRepo *repositories.AuditLogRepository
+}
+
+// NewAuditExportHandler creates a new audit export handler
+func NewAuditExportHandler(deps *Deps) *AuditExportHandler {
+	return &AuditExportHandler{
+		auditRecorder: newAuditRecorder(deps),
+		auditRepo:     deps.AuditLogs,
+	}
+}
+
+// Export handles GET /api/v1/audit-logs/export?format=csv|jsonl|json&from=YYYY-MM-DD&to=YYYY-MM-DD
+// Only superadmin can export audit logs
+func (h *AuditExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if middleware.GetUserRole(r.Context()) != "superadmin" {
+		http.Error(w, "Forbidden: Only superadmins can export audit logs", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" && format != "json" {
+		http.Error(w, "Invalid format. Supported formats: csv, jsonl, json", http.StatusBadRequest)
+		return
+	}
+
+	var from, to time.Time
+	fromLabel, toLabel := "start", time.Now().Format("2006-01-02")
+	if value := query.Get("from"); value != "" {
+		t, err := parseExportDate(value)
+		if err != nil {
+			http.Error(w, "Invalid from date (expected YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		from, fromLabel = t, t.Format("2006-01-02")
+	}
+	if value := query.Get("to"); value != "" {
+		t, err := parseExportDate(value)
+		if err != nil {
+			http.Error(w, "Invalid to date (expected YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+		toLabel = t.Format("2006-01-02")
+		// A plain date includes the whole day
+		if !strings.Contains(value, "T") {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+	case "jsonl":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-logs-%s-%s.%s"`, fromLabel, toLabel, format))
+
+	count := 0
+	var err error
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"id", "user_email", "user_name", "action", "resource_type", "resource_id", "resource_name", "status", "timestamp"})
+		err = h.auditRepo.FindByDateRange(r.Context(), from, to, func(entry models.AuditLog) error {
+			count++
+			return writer.Write([]string{
+				entry.ID,
+				entry.UserEmail,
+				entry.UserName,
+				entry.Action,
+				entry.ResourceType,
+				entry.ResourceID,
+				entry.ResourceName,
+				entry.Status,
+				entry.Timestamp.Format(time.RFC3339),
+			})
+		})
+		writer.Flush()
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		err = h.auditRepo.FindByDateRange(r.Context(), from, to, func(entry models.AuditLog) error {
+			count++
+			return encoder.Encode(entry)
+		})
+	default:
+		err = streamJSONArray(w, func(emit func(interface{}) error) error {
+			return h.auditRepo.FindByDateRange(r.Context(), from, to, func(entry models.AuditLog) error {
+				count++
+				return emit(entry)
+			})
+		})
+	}
+
+	status := "success"
+	if err != nil {
+		// Headers are already sent, so the client only sees a truncated file
+		log.Printf("Failed to export audit logs: %v", err)
+		status = "failed"
+	}
+
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "export_audit_logs",
+		ResourceType: "audit_log",
+		Status:       status,
+		Details:      fmt.Sprintf("Exported %d audit logs as %s (from: %s, to: %s)", count, format, fromLabel, toLabel),
+	})
+}
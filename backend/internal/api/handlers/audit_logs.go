@@ -10,15 +10,35 @@ import (
 	"github.com/portalight/backend/internal/repositories"
 )
 
+// AuditLogsHandler serves the audit log endpoints
+type AuditLogsHandler struct {
+	auditRepo   *repositories.AuditLogRepository
+	projectRepo *repositories.ProjectRepository
+}
+
+// NewAuditLogsHandler creates a new AuditLogsHandler
+func NewAuditLogsHandler(deps *Deps) *AuditLogsHandler {
+	return &AuditLogsHandler{
+		auditRepo:   deps.AuditLogs,
+		projectRepo: deps.Projects,
+	}
+}
+
 // GetAuditLogs returns audit logs from the database
-func GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+func (h *AuditLogsHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	auditRepo := &repositories.AuditLogRepository{}
 
-	// Get optional user_email filter from query params
-	userEmail := r.URL.Query().Get("user_email")
+	// Optional filters: user_email, and request_id to find the entries of one API call
+	filter := repositories.AuditLogFilter{
+		UserEmail: r.URL.Query().Get("user_email"),
+		RequestID: r.URL.Query().Get("request_id"),
+	}
 
-	logs, err := auditRepo.GetAll(ctx, userEmail)
+	logs := []models.AuditLog{}
+	err := h.auditRepo.ForEach(ctx, filter, func(log models.AuditLog) error {
+		logs = append(logs, log)
+		return nil
+	})
 	if err != nil {
 		http.Error(w, "Failed to fetch audit logs", http.StatusInternalServerError)
 		return
@@ -28,8 +48,46 @@ func GetAuditLogs(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(logs)
 }
 
+// GetProjectAuditLogs returns one page of the audit logs linked to a project as
+// {"items": [...], "total": N}. Non-superadmins must have access to the project.
+func (h *AuditLogsHandler) GetProjectAuditLogs(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.projectRepo.FindByID(r.Context(), projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	if middleware.GetUserRole(r.Context()) != "superadmin" {
+		userID := middleware.GetUserID(r.Context())
+		if userID == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		allowed, err := h.projectRepo.CanUserAccess(r.Context(), projectID, userID)
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	logs, total, err := h.auditRepo.List(r.Context(), repositories.AuditLogFilter{ProjectID: projectID}, opts)
+	writeList(w, logs, total, err, "Failed to fetch audit logs")
+}
+
 // CreateAuditLog creates a new audit log entry in the database
-func CreateAuditLog(w http.ResponseWriter, r *http.Request) {
+func (h *AuditLogsHandler) CreateAuditLog(w http.ResponseWriter, r *http.Request) {
 	var log models.AuditLog
 	if err := json.NewDecoder(r.Body).Decode(&log); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -43,9 +101,8 @@ func CreateAuditLog(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	auditRepo := &repositories.AuditLogRepository{}
 
-	if err := auditRepo.Create(ctx, &log); err != nil {
+	if err := h.auditRepo.Create(ctx, &log); err != nil {
 		http.Error(w, "Failed to create audit log", http.StatusInternalServerError)
 		return
 	}
@@ -53,17 +110,29 @@ func CreateAuditLog(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-// CreateAuditLogEntry is a helper function to create audit log entries from other handlers
-func CreateAuditLogEntry(log models.AuditLog) {
-	ctx := context.Background()
-	auditRepo := &repositories.AuditLogRepository{}
-	auditRepo.Create(ctx, &log)
+// auditRecorder is embedded by handlers that write audit log entries
+type auditRecorder struct {
+	auditLogs *repositories.AuditLogRepository
 }
 
-// GetAuditLogCount returns the total count of audit logs
-func GetAuditLogCount() int {
+func newAuditRecorder(deps *Deps) auditRecorder {
+	return auditRecorder{auditLogs: deps.AuditLogs}
+}
+
+// CreateAuditLogEntry is a helper to create audit log entries from other handlers
+func (a auditRecorder) CreateAuditLogEntry(log models.AuditLog) {
 	ctx := context.Background()
-	auditRepo := &repositories.AuditLogRepository{}
-	count, _ := auditRepo.Count(ctx)
-	return count
+	a.auditLogs.Create(ctx, &log)
+}
+
+// recordAudit describes the request's action on the entry written by the audit
+// middleware; further entries in the same request, and calls outside an audited
+// request, are written on their own
+func (a auditRecorder) recordAudit(ctx context.Context, log models.AuditLog) {
+	if log.RequestID == "" {
+		log.RequestID = middleware.GetRequestID(ctx)
+	}
+	if !middleware.EnrichAudit(ctx, log) {
+		a.CreateAuditLogEntry(log)
+	}
 }
@@ -3,12 +3,18 @@ package handlers
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
@@ -17,14 +23,25 @@ import (
 	"github.com/portalight/backend/internal/config"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/services"
 )
 
 type AuthHandler struct {
-	Config      *config.Config
-	OAuthConfig *oauth2.Config
+	Config           *config.Config
+	OAuthConfig      *oauth2.Config
+	oidc             *services.OIDCProvider // nil when OIDC login is not configured
+	gitlab           *oauth2.Config         // nil when GitLab login is not configured
+	userRepo         *repositories.UserRepository
+	revokedTokenRepo *repositories.RevokedTokenRepository
 }
 
-func NewAuthHandler(cfg *config.Config) *AuthHandler {
+// oidcStateCookie carries the state and nonce of an OIDC login to its callback
+const oidcStateCookie = "portalight_oidc_state"
+
+// gitlabStateCookie carries the state of a GitLab login to its callback
+const gitlabStateCookie = "portalight_gitlab_state"
+
+func NewAuthHandler(deps *Deps, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
 		Config: cfg,
 		OAuthConfig: &oauth2.Config{
@@ -34,13 +51,50 @@ func NewAuthHandler(cfg *config.Config) *AuthHandler {
 			Endpoint:     github.Endpoint,
 			RedirectURL:  fmt.Sprintf("http://localhost:%s/auth/github/callback", cfg.Port),
 		},
+		oidc: services.NewOIDCProvider(services.OIDCConfig{
+			IssuerURL:     cfg.OIDCIssuerURL,
+			ClientID:      cfg.OIDCClientID,
+			ClientSecret:  cfg.OIDCClientSecret,
+			RedirectURL:   callbackBaseURL(cfg) + "/auth/oidc/callback",
+			AllowedDomain: cfg.OIDCAllowedDomain,
+		}),
+		gitlab:           newGitlabOAuthConfig(cfg),
+		userRepo:         deps.Users,
+		revokedTokenRepo: deps.RevokedTokens,
+	}
+}
+
+// newGitlabOAuthConfig returns the OAuth client of GITLAB_URL, or nil if GitLab login
+// is not configured
+func newGitlabOAuthConfig(cfg *config.Config) *oauth2.Config {
+	if cfg.GitlabClientID == "" {
+		return nil
+	}
+	return &oauth2.Config{
+		ClientID:     cfg.GitlabClientID,
+		ClientSecret: cfg.GitlabClientSecret,
+		Scopes:       []string{"read_user", "read_api"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  cfg.GitlabURL + "/oauth/authorize",
+			TokenURL: cfg.GitlabURL + "/oauth/token",
+		},
+		RedirectURL: callbackBaseURL(cfg) + "/auth/gitlab/callback",
 	}
 }
 
+// callbackBaseURL is where identity providers send users back to: PUBLIC_URL, or
+// the local server during development
+func callbackBaseURL(cfg *config.Config) string {
+	if cfg.PublicURL != "" {
+		return cfg.PublicURL
+	}
+	return fmt.Sprintf("http://localhost:%s", cfg.Port)
+}
+
 // LoginRequest represents username/password login request
 type LoginRequest struct {
 	Username string `json:"username"`
-	Password string `json:"password"`
+	Password string `json:"password" redact:"true"`
 }
 
 // LoginResponse represents login response
@@ -61,9 +115,8 @@ func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 
 	// Find superadmin user
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
-	superadmin, err := userRepo.FindByEmail(ctx, req.Username)
+	superadmin, err := h.userRepo.FindByEmail(ctx, req.Username)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
@@ -194,7 +247,12 @@ func (h *AuthHandler) HandleGithubCallback(w http.ResponseWriter, r *http.Reques
 	}
 
 	// 5. Redirect to Frontend with Token
-	frontendURL := "http://localhost:3000/auth/callback?token=" + jwtToken
+	redirectWithToken(w, r, jwtToken)
+}
+
+// redirectWithToken sends the browser back to the frontend with a new session token
+func redirectWithToken(w http.ResponseWriter, r *http.Request, token string) {
+	frontendURL := "http://localhost:3000/auth/callback?token=" + token
 	http.Redirect(w, r, frontendURL, http.StatusTemporaryRedirect)
 }
 
@@ -213,17 +271,16 @@ func (h *AuthHandler) findOrCreateGithubUser(githubID int64, login, name, email,
 	}
 
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
 	// Try to find existing user by GitHub ID
-	existingUser, err := userRepo.FindByGithubID(ctx, githubID)
+	existingUser, err := h.userRepo.FindByGithubID(ctx, githubID)
 	if err == nil {
 		// Update user info on each login
 		existingUser.Name = displayName
 		existingUser.Email = userEmail
 		existingUser.Avatar = avatarURL
 		existingUser.GithubUsername = login
-		userRepo.Update(ctx, existingUser)
+		h.userRepo.Update(ctx, existingUser)
 		return existingUser
 	}
 
@@ -239,7 +296,7 @@ func (h *AuthHandler) findOrCreateGithubUser(githubID int64, login, name, email,
 		CreatedAt:      time.Now(),
 	}
 
-	if err := userRepo.Create(ctx, newUser); err != nil {
+	if err := h.userRepo.Create(ctx, newUser); err != nil {
 		// Fallback to in-memory if database fails (shouldn't happen)
 		newUser.ID = generateID()
 		return newUser
@@ -248,6 +305,371 @@ func (h *AuthHandler) findOrCreateGithubUser(githubID int64, login, name, email,
 	return newUser
 }
 
+// HandleGitlabLogin redirects to GitLab's authorization page
+func (h *AuthHandler) HandleGitlabLogin(w http.ResponseWriter, r *http.Request) {
+	if h.gitlab == nil {
+		http.Error(w, "GitLab login is not configured", http.StatusNotFound)
+		return
+	}
+
+	state := generateID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     gitlabStateCookie,
+		Value:    state,
+		Path:     "/auth/gitlab",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.Config.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, h.gitlab.AuthCodeURL(state, oauth2.AccessTypeOnline), http.StatusTemporaryRedirect)
+}
+
+// HandleGitlabCallback exchanges the authorization code, reads the GitLab user and
+// signs them in
+func (h *AuthHandler) HandleGitlabCallback(w http.ResponseWriter, r *http.Request) {
+	if h.gitlab == nil {
+		http.Error(w, "GitLab login is not configured", http.StatusNotFound)
+		return
+	}
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		http.Error(w, "Login failed: "+errParam, http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(gitlabStateCookie)
+	if err != nil {
+		http.Error(w, "Login session expired, please try again", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: gitlabStateCookie, Path: "/auth/gitlab", MaxAge: -1})
+	if cookie.Value == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		http.Error(w, "Code not found", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.gitlab.Exchange(r.Context(), code)
+	if err != nil {
+		log.Printf("GitLab token exchange failed: %v", err)
+		http.Error(w, "Failed to exchange token", http.StatusInternalServerError)
+		return
+	}
+
+	client := h.gitlab.Client(r.Context(), token)
+	userResp, err := client.Get(h.Config.GitlabURL + "/api/v4/user")
+	if err != nil {
+		http.Error(w, "Failed to get user info", http.StatusInternalServerError)
+		return
+	}
+	defer userResp.Body.Close()
+	if userResp.StatusCode != http.StatusOK {
+		log.Printf("GitLab user lookup returned %s", userResp.Status)
+		http.Error(w, "Failed to get user info", http.StatusBadGateway)
+		return
+	}
+
+	var gitlabUser struct {
+		ID        int64  `json:"id"`
+		Username  string `json:"username"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := json.NewDecoder(userResp.Body).Decode(&gitlabUser); err != nil {
+		http.Error(w, "Failed to decode user info", http.StatusInternalServerError)
+		return
+	}
+
+	if group := h.Config.GitlabAllowedGroup; group != "" {
+		isMember, err := h.isGitlabGroupMember(client, group)
+		if err != nil {
+			log.Printf("GitLab group lookup for %s failed: %v", gitlabUser.Username, err)
+			http.Error(w, "Failed to get groups", http.StatusBadGateway)
+			return
+		}
+		if !isMember {
+			http.Error(w, fmt.Sprintf("You must be a member of %s to login", group), http.StatusForbidden)
+			return
+		}
+	}
+
+	user, err := h.findOrCreateGitlabUser(r.Context(), gitlabUser.ID, gitlabUser.Username, gitlabUser.Name, gitlabUser.Email, gitlabUser.AvatarURL)
+	if err != nil {
+		log.Printf("Failed to sign in GitLab user %s: %v", gitlabUser.Username, err)
+		http.Error(w, "Failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	jwtToken, err := h.generateToken(user.ID, user.Email, string(user.Role))
+	if err != nil {
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	redirectWithToken(w, r, jwtToken)
+}
+
+// isGitlabGroupMember reports whether the signed-in GitLab user is a member of the
+// group with the given full path
+func (h *AuthHandler) isGitlabGroupMember(client *http.Client, group string) (bool, error) {
+	// Search by the group's own name, then match the full path exactly
+	query := url.Values{
+		"min_access_level": {"10"}, // Guest; lists only groups the user belongs to
+		"search":           {group[strings.LastIndex(group, "/")+1:]},
+		"per_page":         {"100"},
+	}
+	resp, err := client.Get(h.Config.GitlabURL + "/api/v4/groups?" + query.Encode())
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("GitLab returned %s", resp.Status)
+	}
+
+	var groups []struct {
+		FullPath string `json:"full_path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return false, fmt.Errorf("failed to decode groups: %w", err)
+	}
+	for _, g := range groups {
+		if strings.EqualFold(g.FullPath, group) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findOrCreateGitlabUser finds the user linked to a GitLab ID, refreshing their
+// profile, or creates a new user with dev role
+func (h *AuthHandler) findOrCreateGitlabUser(ctx context.Context, gitlabID int64, username, name, email, avatarURL string) (*models.User, error) {
+	// Use GitLab username as fallback if name is empty
+	displayName := name
+	if displayName == "" {
+		displayName = username
+	}
+
+	// If email is empty (private profile), use GitLab username
+	userEmail := email
+	if userEmail == "" {
+		userEmail = username
+	}
+
+	if existingUser, err := h.userRepo.FindByGitlabID(ctx, gitlabID); err == nil {
+		// Update user info on each login
+		existingUser.Name = displayName
+		existingUser.Email = userEmail
+		existingUser.Avatar = avatarURL
+		if err := h.userRepo.Update(ctx, existingUser); err != nil {
+			return nil, err
+		}
+		if err := h.userRepo.SetGitlabIdentity(ctx, existingUser.ID, gitlabID, username); err != nil {
+			return nil, err
+		}
+		existingUser.GitlabUsername = username
+		return existingUser, nil
+	}
+
+	newUser := &models.User{
+		Name:           displayName,
+		Email:          userEmail,
+		Role:           models.RoleDev, // All new GitLab users start as dev
+		TeamIDs:        []string{},
+		Avatar:         avatarURL,
+		GitlabID:       gitlabID,
+		GitlabUsername: username,
+		CreatedAt:      time.Now(),
+	}
+	if err := h.userRepo.Create(ctx, newUser); err != nil {
+		return nil, err
+	}
+	if err := h.userRepo.SetGitlabIdentity(ctx, newUser.ID, gitlabID, username); err != nil {
+		return nil, err
+	}
+	return newUser, nil
+}
+
+// HandleOIDCLogin redirects to the OpenID Connect provider's login page
+func (h *AuthHandler) HandleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if h.oidc == nil {
+		http.Error(w, "OIDC login is not configured", http.StatusNotFound)
+		return
+	}
+
+	state, nonce := generateID(), generateID()
+	loginURL, err := h.oidc.AuthCodeURL(r.Context(), state, nonce)
+	if err != nil {
+		log.Printf("OIDC login unavailable: %v", err)
+		http.Error(w, "OIDC provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state + "." + nonce,
+		Path:     "/auth/oidc",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.Config.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, loginURL, http.StatusTemporaryRedirect)
+}
+
+// HandleOIDCCallback verifies the provider's ID token and signs the user in
+func (h *AuthHandler) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if h.oidc == nil {
+		http.Error(w, "OIDC login is not configured", http.StatusNotFound)
+		return
+	}
+	if errParam := r.URL.Query().Get("error"); errParam != "" {
+		http.Error(w, "Login failed: "+errParam, http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		http.Error(w, "Login session expired, please try again", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/auth/oidc", MaxAge: -1})
+	state, nonce, ok := strings.Cut(cookie.Value, ".")
+	if !ok || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(r.URL.Query().Get("state"))) != 1 {
+		http.Error(w, "Invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		http.Error(w, "Code not found", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := h.oidc.Exchange(r.Context(), code, nonce)
+	if err != nil {
+		log.Printf("OIDC login rejected: %v", err)
+		http.Error(w, "Login failed: "+err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.findOrCreateOIDCUser(r.Context(), claims)
+	if err != nil {
+		log.Printf("Failed to sign in OIDC user %s: %v", claims.Email, err)
+		http.Error(w, "Failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	jwtToken, err := h.generateToken(user.ID, user.Email, string(user.Role))
+	if err != nil {
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	redirectWithToken(w, r, jwtToken)
+}
+
+// findOrCreateOIDCUser finds the user linked to the ID token's subject, links an
+// existing user (e.g. one created by GitHub login) with the same verified email, or
+// creates a new user with dev role
+func (h *AuthHandler) findOrCreateOIDCUser(ctx context.Context, claims *services.OIDCClaims) (*models.User, error) {
+	if user, err := h.userRepo.FindByOIDCSubject(ctx, claims.Subject); err == nil {
+		return user, nil
+	}
+
+	user, err := h.userRepo.LinkOIDCSubject(ctx, claims.Email, claims.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+	newUser := &models.User{
+		Name:      name,
+		Email:     claims.Email,
+		Role:      models.RoleDev, // All new OIDC users start as dev
+		TeamIDs:   []string{},
+		Avatar:    claims.Picture,
+		CreatedAt: time.Now(),
+	}
+	if err := h.userRepo.Create(ctx, newUser); err != nil {
+		return nil, err
+	}
+	return h.userRepo.LinkOIDCSubject(ctx, newUser.Email, claims.Subject)
+}
+
+// revokeCurrentToken adds the request's token to the revocation list until it expires
+func (h *AuthHandler) revokeCurrentToken(r *http.Request) error {
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		// Tokens issued before revocation support have no jti; they expire on their own
+		return nil
+	}
+	return h.revokedTokenRepo.Revoke(r.Context(), claims.ID, claims.UserID, claims.ExpiresAt.Time)
+}
+
+// HandleLogout revokes the current token
+func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.revokeCurrentToken(r); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to log out"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Logged out"})
+}
+
+// HandleRefresh issues a new token for the current user and revokes the old one
+func (h *AuthHandler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Reload the user so role changes take effect on refresh
+	user, err := h.userRepo.FindByID(r.Context(), middleware.GetUserID(r.Context()))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+		return
+	}
+
+	token, err := h.generateToken(user.ID, user.Email, string(user.Role))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to generate token"})
+		return
+	}
+
+	if err := h.revokeCurrentToken(r); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to revoke previous token"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{Token: token, User: *user})
+}
+
 // generateToken generates a JWT token
 func (h *AuthHandler) generateToken(userID, email, role string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
@@ -256,6 +678,7 @@ func (h *AuthHandler) generateToken(userID, email, role string) (string, error)
 		Email:  email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "portalight",
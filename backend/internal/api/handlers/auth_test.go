@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/portalight/backend/internal/config"
+)
+
+func TestIsGitlabGroupMember(t *testing.T) {
+	// The fake GitLab answers /api/v4/groups for a member of acme/platform and of
+	// another team's platform group, matching the search case-insensitively
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/groups" || r.URL.Query().Get("min_access_level") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		groups := []map[string]string{}
+		if strings.EqualFold(r.URL.Query().Get("search"), "platform") {
+			groups = append(groups, map[string]string{"full_path": "acme/platform"}, map[string]string{"full_path": "other/platform"})
+		}
+		json.NewEncoder(w).Encode(groups)
+	}))
+	defer gitlab.Close()
+
+	h := &AuthHandler{Config: &config.Config{GitlabURL: gitlab.URL}}
+	tests := []struct {
+		group string
+		want  bool
+	}{
+		{"acme/platform", true},
+		{"Acme/Platform", true},
+		{"acme/payments", false},
+		{"evil/platform", false},
+	}
+	for _, tt := range tests {
+		got, err := h.isGitlabGroupMember(gitlab.Client(), tt.group)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.group, err)
+		}
+		if got != tt.want {
+			t.Errorf("member of %s = %t, want %t", tt.group, got, tt.want)
+		}
+	}
+}
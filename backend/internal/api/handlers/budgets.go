@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/services"
+)
+
+// budgetEventsLimit is how many past breaches the events endpoint returns
+const budgetEventsLimit = 50
+
+// BudgetHandler manages project budgets
+type BudgetHandler struct {
+	auditRecorder
+	budgetRepo  *repositories.BudgetRepository
+	projectRepo *repositories.ProjectRepository
+	evaluator   *services.BudgetEvaluator
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(deps *Deps, evaluator *services.BudgetEvaluator) *BudgetHandler {
+	return &BudgetHandler{
+		auditRecorder: newAuditRecorder(deps),
+		budgetRepo:    deps.Budgets,
+		projectRepo:   deps.Projects,
+		evaluator:     evaluator,
+	}
+}
+
+// budgetRequest is the body of create and update requests
+type budgetRequest struct {
+	Type          models.BudgetType `json:"type"`
+	ResourceType  string            `json:"resource_type"`
+	Threshold     *float64          `json:"threshold"`
+	NotifyChannel string            `json:"notify_channel"`
+}
+
+// HandleBudgets routes /api/v1/projects/{id}/budgets[/{budgetID}|/events]
+func (h *BudgetHandler) HandleBudgets(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != "budgets" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	projectID := parts[0]
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		h.listBudgets(w, r, projectID)
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		h.createBudget(w, r, projectID)
+	case len(parts) == 3 && parts[2] == "events" && r.Method == http.MethodGet:
+		h.listEvents(w, r, projectID)
+	case len(parts) == 3 && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		h.updateBudget(w, r, projectID, parts[2])
+	case len(parts) == 3 && r.Method == http.MethodDelete:
+		h.deleteBudget(w, r, projectID, parts[2])
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listBudgets returns the budgets of a project with their open breach
+func (h *BudgetHandler) listBudgets(w http.ResponseWriter, r *http.Request, projectID string) {
+	budgets, err := h.budgetRepo.ListByProject(r.Context(), projectID)
+	if err != nil {
+		log.Printf("Failed to list budgets: %v", err)
+		http.Error(w, "Failed to list budgets", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budgets)
+}
+
+// listEvents returns the recent breach history of a project
+func (h *BudgetHandler) listEvents(w http.ResponseWriter, r *http.Request, projectID string) {
+	events, err := h.budgetRepo.ListEvents(r.Context(), projectID, budgetEventsLimit)
+	if err != nil {
+		log.Printf("Failed to list budget events: %v", err)
+		http.Error(w, "Failed to list budget events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// createBudget adds a budget to a project (lead or superadmin)
+func (h *BudgetHandler) createBudget(w http.ResponseWriter, r *http.Request, projectID string) {
+	if !canManageBudgets(r) {
+		http.Error(w, "Forbidden: lead or superadmin access required", http.StatusForbidden)
+		return
+	}
+
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validateBudgetRequest(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.projectRepo.FindByID(r.Context(), projectID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	budget := &models.ProjectBudget{
+		ProjectID:     project.ID,
+		ProjectName:   project.Name,
+		Type:          req.Type,
+		ResourceType:  req.ResourceType,
+		Threshold:     *req.Threshold,
+		NotifyChannel: req.NotifyChannel,
+	}
+	if err := h.budgetRepo.Create(r.Context(), budget); err != nil {
+		log.Printf("Failed to create budget: %v", err)
+		http.Error(w, "Failed to create budget", http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, "create_project_budget", budget)
+	h.evaluateNow(r, *budget)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(budget)
+}
+
+// updateBudget replaces the settings of a budget (lead or superadmin)
+func (h *BudgetHandler) updateBudget(w http.ResponseWriter, r *http.Request, projectID, budgetID string) {
+	if !canManageBudgets(r) {
+		http.Error(w, "Forbidden: lead or superadmin access required", http.StatusForbidden)
+		return
+	}
+
+	var req budgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validateBudgetRequest(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.budgetRepo.FindByID(r.Context(), projectID, budgetID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Budget not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load budget: %v", err)
+		http.Error(w, "Failed to load budget", http.StatusInternalServerError)
+		return
+	}
+
+	budget.Type = req.Type
+	budget.ResourceType = req.ResourceType
+	budget.Threshold = *req.Threshold
+	budget.NotifyChannel = req.NotifyChannel
+	if err := h.budgetRepo.Update(r.Context(), budget); err != nil {
+		log.Printf("Failed to update budget: %v", err)
+		http.Error(w, "Failed to update budget", http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, "update_project_budget", budget)
+	h.evaluateNow(r, *budget)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
+
+// deleteBudget removes a budget (lead or superadmin)
+func (h *BudgetHandler) deleteBudget(w http.ResponseWriter, r *http.Request, projectID, budgetID string) {
+	if !canManageBudgets(r) {
+		http.Error(w, "Forbidden: lead or superadmin access required", http.StatusForbidden)
+		return
+	}
+
+	budget, err := h.budgetRepo.FindByID(r.Context(), projectID, budgetID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Budget not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load budget: %v", err)
+		http.Error(w, "Failed to load budget", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.budgetRepo.Delete(r.Context(), projectID, budgetID); err != nil {
+		log.Printf("Failed to delete budget: %v", err)
+		http.Error(w, "Failed to delete budget", http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, "delete_project_budget", budget)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// evaluateNow checks a new or changed budget right away so its breach state is current
+func (h *BudgetHandler) evaluateNow(r *http.Request, budget models.ProjectBudget) {
+	if h.evaluator == nil {
+		return
+	}
+	if err := h.evaluator.Evaluate(r.Context(), budget); err != nil {
+		log.Printf("Failed to evaluate budget %s: %v", budget.ID, err)
+	}
+}
+
+func (h *BudgetHandler) audit(r *http.Request, action string, budget *models.ProjectBudget) {
+	details := fmt.Sprintf("%s budget, threshold %.2f", budget.Type, budget.Threshold)
+	if budget.ResourceType != "" {
+		details += ", resource type " + budget.ResourceType
+	}
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       action,
+		ResourceType: "project_budget",
+		ResourceID:   budget.ID,
+		ResourceName: budget.ProjectName,
+		ProjectID:    budget.ProjectID,
+		Status:       "success",
+		Details:      details,
+	})
+}
+
+func canManageBudgets(r *http.Request) bool {
+	role := middleware.GetUserRole(r.Context())
+	return role == string(models.RoleAdmin) || role == string(models.RoleLead)
+}
+
+func validateBudgetRequest(req budgetRequest) error {
+	switch req.Type {
+	case models.BudgetTypeResourceCount:
+		if req.ResourceType != "" {
+			if _, ok := services.LookupProvisionerType(req.ResourceType); !ok {
+				return fmt.Errorf("unknown resource_type: %s", req.ResourceType)
+			}
+		}
+	case models.BudgetTypeEstimatedCost:
+		if req.ResourceType != "" {
+			return errors.New("resource_type only applies to resource_count budgets")
+		}
+	default:
+		return fmt.Errorf("type must be %s or %s", models.BudgetTypeResourceCount, models.BudgetTypeEstimatedCost)
+	}
+
+	if req.Threshold == nil || *req.Threshold < 0 {
+		return errors.New("threshold must be zero or greater")
+	}
+
+	if req.NotifyChannel != "" {
+		u, err := url.Parse(req.NotifyChannel)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("notify_channel must be an https webhook URL")
+		}
+	}
+
+	return nil
+}
@@ -1,27 +1,71 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"mime"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/github"
+	"github.com/portalight/backend/internal/logging"
+	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
 )
 
 type CatalogHandler struct {
+	auditRecorder
 	configRepo *repositories.GitHubConfigRepository
 	syncer     *catalog.Syncer
+	publicURL  string // Base URL GitHub delivers webhooks to; see SetupWebhook
 }
 
-func NewCatalogHandler(configRepo *repositories.GitHubConfigRepository, syncer *catalog.Syncer) *CatalogHandler {
+func NewCatalogHandler(deps *Deps, syncer *catalog.Syncer, publicURL string) *CatalogHandler {
 	return &CatalogHandler{
-		configRepo: configRepo,
-		syncer:     syncer,
+		auditRecorder: newAuditRecorder(deps),
+		configRepo:    deps.GitHubConfig,
+		syncer:        syncer,
+		publicURL:     publicURL,
 	}
 }
 
+// CatalogActivity summarizes webhook traffic and the sync backlog
+type CatalogActivity struct {
+	WebhookDeliveries24h int `json:"webhook_deliveries_24h"`
+	SyncQueueDepth       int `json:"sync_queue_depth"`
+}
+
+// CatalogStatus aggregates catalog integration health for the status widget
+type CatalogStatus struct {
+	Configured            bool       `json:"configured"`
+	Enabled               bool       `json:"enabled"`
+	LastScanAt            *time.Time `json:"last_scan_at"`
+	LastScanStatus        *string    `json:"last_scan_status"`
+	LastScanError         *string    `json:"last_scan_error"`
+	LastWebhookReceivedAt *time.Time `json:"last_webhook_received_at"`
+	CatalogActivity
+}
+
+// activity collects webhook and sync queue counters
+func (h *CatalogHandler) activity(ctx context.Context) (CatalogActivity, error) {
+	deliveries, err := h.configRepo.CountRecentWebhookDeliveries(ctx, 24*time.Hour)
+	if err != nil {
+		return CatalogActivity{}, err
+	}
+	return CatalogActivity{
+		WebhookDeliveries24h: deliveries,
+		SyncQueueDepth:       h.syncer.QueueDepth(),
+	}, nil
+}
+
 // GetConfig returns the current GitHub configuration
 func (h *CatalogHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
 	config, err := h.configRepo.GetConfig(r.Context())
@@ -44,18 +88,63 @@ func (h *CatalogHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
 		config.PATEncrypted = &masked
 	}
 
+	activity, err := h.activity(r.Context())
+	if err != nil {
+		log.Printf("❌ [Config] Failed to load catalog activity: %v", err)
+		http.Error(w, "Failed to get config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		*repositories.GitHubConfig
+		CatalogActivity
+	}{config, activity})
+}
+
+// GetStatus handles GET /api/v1/catalog/status for the catalog health widget
+func (h *CatalogHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	config, err := h.configRepo.GetConfig(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to get catalog status", http.StatusInternalServerError)
+		return
+	}
+
+	activity, err := h.activity(r.Context())
+	if err != nil {
+		log.Printf("❌ [Status] Failed to load catalog activity: %v", err)
+		http.Error(w, "Failed to get catalog status", http.StatusInternalServerError)
+		return
+	}
+
+	status := CatalogStatus{CatalogActivity: activity}
+	if config != nil {
+		status.Configured = true
+		status.Enabled = config.Enabled
+		status.LastScanAt = config.LastScanAt
+		status.LastScanStatus = config.LastScanStatus
+		status.LastScanError = config.LastScanError
+		status.LastWebhookReceivedAt = config.LastWebhookReceivedAt
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(config)
+	json.NewEncoder(w).Encode(status)
 }
 
 type UpdateConfigRequest struct {
-	RepoOwner           string `json:"repo_owner"`
-	RepoName            string `json:"repo_name"`
-	Branch              string `json:"branch"`
-	ProjectsPath        string `json:"projects_path"`
-	AuthType            string `json:"auth_type"`
-	PersonalAccessToken string `json:"personal_access_token"`
-	Enabled             bool   `json:"enabled"`
+	RepoOwner           string   `json:"repo_owner"`
+	RepoName            string   `json:"repo_name"`
+	Branch              string   `json:"branch"`
+	ProjectsPaths       []string `json:"projects_paths"`
+	ProjectsPath        string   `json:"projects_path"` // Deprecated: single path, used when projects_paths is empty
+	AuthType            string   `json:"auth_type"`
+	PersonalAccessToken string   `json:"personal_access_token" redact:"true"`
+	Enabled             bool     `json:"enabled"`
 }
 
 // UpdateConfig updates the GitHub configuration
@@ -76,13 +165,23 @@ func (h *CatalogHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	projectsPaths := req.ProjectsPaths
+	if len(projectsPaths) == 0 && req.ProjectsPath != "" {
+		projectsPaths = []string{req.ProjectsPath}
+	}
+	projectsPaths, err := normalizeProjectsPaths(projectsPaths)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	config := &repositories.GitHubConfig{
-		RepoOwner:    req.RepoOwner,
-		RepoName:     req.RepoName,
-		Branch:       req.Branch,
-		ProjectsPath: req.ProjectsPath,
-		AuthType:     req.AuthType,
-		Enabled:      req.Enabled,
+		RepoOwner:     req.RepoOwner,
+		RepoName:      req.RepoName,
+		Branch:        req.Branch,
+		ProjectsPaths: projectsPaths,
+		AuthType:      req.AuthType,
+		Enabled:       req.Enabled,
 	}
 
 	if req.PersonalAccessToken != "" {
@@ -95,8 +194,47 @@ func (h *CatalogHandler) UpdateConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	response := map[string]interface{}{"status": "success"}
+	if req.PersonalAccessToken != "" {
+		if warning := patScopeWarning(r.Context(), req.PersonalAccessToken); warning != "" {
+			response["warnings"] = []string{warning}
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	json.NewEncoder(w).Encode(response)
+}
+
+// patScopeWarning explains what stops working when a classic PAT lacks the
+// repo:status scope. Fine-grained tokens don't report scopes and get no warning.
+func patScopeWarning(ctx context.Context, token string) string {
+	scopes, ok, err := github.NewClientWithPAT(ctx, token).TokenScopes(ctx)
+	if err != nil {
+		log.Printf("⚠️ [Config] Failed to check token scopes: %v", err)
+		return ""
+	}
+	if ok && !github.HasScope(scopes, "repo:status") {
+		return "The token lacks the repo:status scope, so sync results cannot be reported as commit statuses"
+	}
+	return ""
+}
+
+// normalizeProjectsPaths trims and dedupes configured catalog paths and requires at least one
+func normalizeProjectsPaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, p := range paths {
+		p = strings.Trim(strings.TrimSpace(p), "/")
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		normalized = append(normalized, p)
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("at least one projects path is required")
+	}
+	return normalized, nil
 }
 
 // Scan lists available project files
@@ -113,6 +251,36 @@ func (h *CatalogHandler) Scan(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Reconcile handles POST /api/v1/catalog/reconcile: it runs the full-scan
+// reconciliation now and returns the drift it found
+func (h *CatalogHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userEmail := middleware.GetUserEmail(ctx)
+
+	result, err := h.syncer.Reconcile(ctx, "reconcile", middleware.GetUserID(ctx), userEmail)
+	if errors.Is(err, catalog.ErrReconcileRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to reconcile catalog: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    userEmail,
+		Action:       "reconcile_catalog",
+		ResourceType: "catalog",
+		ResourceID:   result.SyncID,
+		Status:       "success",
+		Details: fmt.Sprintf("%d changed, %d orphaned, %d untracked, %d failed",
+			len(result.Changed), len(result.Orphaned), len(result.Untracked), len(result.Failed)),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 type FileTeamMapping struct {
 	File   string `json:"file"`
 	TeamID string `json:"team_id"`
@@ -122,27 +290,108 @@ type SyncRequest struct {
 	Mappings []FileTeamMapping `json:"mappings"`
 }
 
+// Preview returns the sync plan for a single file without writing anything
+func (h *CatalogHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	// A dry run changes nothing
+	middleware.SkipAudit(r.Context())
+
+	var req FileTeamMapping
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.File == "" {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.syncer.PreviewProject(r.Context(), req.File, req.TeamID)
+	if err != nil {
+		log.Printf("❌ [Preview] Failed to preview file %s: %v", req.File, err)
+		http.Error(w, "Failed to preview file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+// maxCatalogFileSize bounds uploads to the public validate endpoint
+const maxCatalogFileSize = 1 << 20
+
+// CatalogValidationResult is the response of Validate
+type CatalogValidationResult struct {
+	Valid    bool                      `json:"valid"`
+	Errors   []catalog.ValidationError `json:"errors"`
+	Warnings []string                  `json:"warnings"`
+}
+
+// Validate handles POST /api/v1/catalog/validate: it lints a catalog file before it
+// is committed, as a multipart upload ("file") or a raw YAML body. It is public so CI
+// pipelines can call it, and never touches the database or GitHub, so references to
+// services of other projects are only reported as warnings.
+func (h *CatalogHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	// Nothing changes, and public requests have no actor to record
+	middleware.SkipAudit(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCatalogFileSize)
+
+	var content []byte
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		file, _, formErr := r.FormFile("file")
+		if formErr != nil {
+			http.Error(w, "Multipart upload needs a \"file\" field of at most 1 MB", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, err = io.ReadAll(file)
+	case "application/x-yaml", "application/yaml", "text/yaml", "text/x-yaml":
+		content, err = io.ReadAll(r.Body)
+	default:
+		http.Error(w, "Content-Type must be multipart/form-data or application/x-yaml", http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Catalog file must be at most 1 MB", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	result := CatalogValidationResult{Errors: []catalog.ValidationError{}, Warnings: []string{}}
+	parsed, err := catalog.ParseYAML(content)
+	if err != nil {
+		result.Errors = append(result.Errors, catalog.ValidationError{Field: "yaml", Message: err.Error()})
+	} else {
+		validationErrors, warnings := catalog.ValidateSchemaWithRules(parsed, catalog.LintRules(nil))
+		result.Errors = append(result.Errors, validationErrors...)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	result.Valid = len(result.Errors) == 0
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 // Sync triggers synchronization for selected files
 func (h *CatalogHandler) Sync(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("================================")
-	fmt.Println("SYNC HANDLER CALLED")
-	fmt.Println("================================")
-	log.Println("📥 [Sync] Received sync request")
+	logger := logging.FromContext(r.Context())
+	logger.Info("📥 [Sync] Received sync request")
 
 	var req SyncRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		fmt.Printf("ERROR DECODING: %v\n", err)
-		log.Printf("❌ [Sync] Failed to decode request: %v", err)
+		logger.Warn("❌ [Sync] Failed to decode request", slog.Any("error", err))
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	fmt.Printf("DECODED %d MAPPINGS\n", len(req.Mappings))
-	log.Printf("✅ [Sync] Decoded request with %d mappings", len(req.Mappings))
+	logger.Info("✅ [Sync] Decoded request", slog.Int("mappings", len(req.Mappings)))
 
 	// Validate mappings
 	if len(req.Mappings) == 0 {
-		log.Println("❌ [Sync] No mappings provided")
+		logger.Warn("❌ [Sync] No mappings provided")
 		http.Error(w, "at least one file-team mapping is required", http.StatusBadRequest)
 		return
 	}
@@ -155,10 +404,10 @@ func (h *CatalogHandler) Sync(w http.ResponseWriter, r *http.Request) {
 
 	results := make([]map[string]interface{}, 0)
 	for i, mapping := range req.Mappings {
-		log.Printf("🔄 [Sync] Processing mapping %d/%d: file=%s, teamID=%s", i+1, len(req.Mappings), mapping.File, mapping.TeamID)
+		logger.Info("🔄 [Sync] Processing mapping", slog.Int("mapping", i+1), slog.Int("mappings", len(req.Mappings)), slog.String("file", mapping.File), slog.String("team_id", mapping.TeamID))
 
 		if mapping.TeamID == "" {
-			log.Printf("❌ [Sync] Missing teamID for file: %s", mapping.File)
+			logger.Warn("❌ [Sync] Missing teamID", slog.String("file", mapping.File))
 			results = append(results, map[string]interface{}{
 				"file":   mapping.File,
 				"status": "failed",
@@ -172,18 +421,18 @@ func (h *CatalogHandler) Sync(w http.ResponseWriter, r *http.Request) {
 			"file": mapping.File,
 		}
 		if err != nil {
-			log.Printf("❌ [Sync] Failed to sync file %s: %v", mapping.File, err)
+			logger.Error("❌ [Sync] Failed to sync file", slog.String("file", mapping.File), slog.Any("error", err))
 			result["status"] = "failed"
 			result["error"] = err.Error()
 		} else {
-			log.Printf("✅ [Sync] Successfully synced file %s -> project %s", mapping.File, history.ProjectName)
+			logger.Info("✅ [Sync] Successfully synced file", slog.String("file", mapping.File), slog.String("project", history.ProjectName))
 			result["status"] = history.Status
 			result["project_name"] = history.ProjectName
 		}
 		results = append(results, result)
 	}
 
-	log.Printf("📤 [Sync] Returning %d results", len(results))
+	logger.Info("📤 [Sync] Returning results", slog.Int("results", len(results)))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"results": results,
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/github"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// catalogWebhookPath is where GitHub delivers catalog push events
+const catalogWebhookPath = "/api/v1/webhook/github"
+
+// catalogWebhookEvents are the events the catalog webhook subscribes to
+var catalogWebhookEvents = []string{"push"}
+
+// SetupWebhook handles POST /api/v1/catalog/webhook/setup: it creates a push webhook
+// on the configured repository pointing at PUBLIC_URL + /api/v1/webhook/github with a
+// freshly generated secret. If a hook with that URL already exists its secret is
+// rotated instead. Returns {"hook_id", "url", "created"}.
+func (h *CatalogHandler) SetupWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.publicURL == "" {
+		http.Error(w, "PUBLIC_URL is not configured", http.StatusBadRequest)
+		return
+	}
+	webhookURL := h.publicURL + catalogWebhookPath
+
+	config, client, ok := h.webhookTarget(w, r)
+	if !ok {
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		http.Error(w, "Failed to generate webhook secret", http.StatusInternalServerError)
+		return
+	}
+
+	hooks, err := client.ListHooks(r.Context(), config.RepoOwner, config.RepoName)
+	if err != nil {
+		log.Printf("❌ [Webhook Setup] %v", err)
+		http.Error(w, "Failed to list repository webhooks (the token needs admin:repo_hook): "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	existing := findCatalogHook(hooks, config.WebhookHookID, webhookURL)
+
+	var hookID int64
+	if existing != nil {
+		hookID = existing.ID
+		err = client.UpdateHook(r.Context(), config.RepoOwner, config.RepoName, hookID, webhookURL, secret, catalogWebhookEvents)
+	} else {
+		hookID, err = client.CreateHook(r.Context(), config.RepoOwner, config.RepoName, webhookURL, secret, catalogWebhookEvents)
+	}
+	if err != nil {
+		log.Printf("❌ [Webhook Setup] %v", err)
+		h.auditWebhook(r, "setup_catalog_webhook", config, "failed", err.Error())
+		http.Error(w, "Failed to set up webhook: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	// GitHub already uses the new secret, so a failure here leaves deliveries unverifiable
+	// until setup is run again
+	if err := h.configRepo.SaveWebhook(r.Context(), &hookID, secret); err != nil {
+		log.Printf("❌ [Webhook Setup] Hook %d configured but secret not saved: %v", hookID, err)
+		http.Error(w, "Webhook configured but failed to save its secret; run setup again", http.StatusInternalServerError)
+		return
+	}
+
+	created := existing == nil
+	h.auditWebhook(r, "setup_catalog_webhook", config, "success", fmt.Sprintf("Hook %d -> %s (created: %t)", hookID, webhookURL, created))
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"hook_id": hookID,
+		"url":     webhookURL,
+		"created": created,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/v1/catalog/webhook/setup: it removes the catalog
+// webhook from the repository and forgets its secret
+func (h *CatalogHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	config, client, ok := h.webhookTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var webhookURL string
+	if h.publicURL != "" {
+		webhookURL = h.publicURL + catalogWebhookPath
+	}
+
+	hooks, err := client.ListHooks(r.Context(), config.RepoOwner, config.RepoName)
+	if err != nil {
+		log.Printf("❌ [Webhook Setup] %v", err)
+		http.Error(w, "Failed to list repository webhooks (the token needs admin:repo_hook): "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if existing := findCatalogHook(hooks, config.WebhookHookID, webhookURL); existing != nil {
+		if err := client.DeleteHook(r.Context(), config.RepoOwner, config.RepoName, existing.ID); err != nil {
+			log.Printf("❌ [Webhook Setup] %v", err)
+			h.auditWebhook(r, "delete_catalog_webhook", config, "failed", err.Error())
+			http.Error(w, "Failed to delete webhook: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	if err := h.configRepo.SaveWebhook(r.Context(), nil, ""); err != nil {
+		http.Error(w, "Failed to clear webhook secret", http.StatusInternalServerError)
+		return
+	}
+
+	h.auditWebhook(r, "delete_catalog_webhook", config, "success", "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webhookTarget loads the catalog configuration and a GitHub client for it; it writes
+// an error response and returns false when the integration is not usable
+func (h *CatalogHandler) webhookTarget(w http.ResponseWriter, r *http.Request) (*repositories.GitHubConfig, *github.GitHubClient, bool) {
+	config, err := h.configRepo.GetConfig(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to get config", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if config == nil {
+		http.Error(w, "GitHub integration not configured", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	client, err := h.syncer.Client(r.Context())
+	if err != nil {
+		http.Error(w, "GitHub client unavailable: "+err.Error(), http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return config, client, true
+}
+
+// findCatalogHook returns the hook created by a previous setup (by stored ID), or
+// else one already delivering to webhookURL
+func findCatalogHook(hooks []github.Hook, storedID *int64, webhookURL string) *github.Hook {
+	if storedID != nil {
+		for i := range hooks {
+			if hooks[i].ID == *storedID {
+				return &hooks[i]
+			}
+		}
+	}
+	if webhookURL != "" {
+		for i := range hooks {
+			if hooks[i].URL == webhookURL {
+				return &hooks[i]
+			}
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret returns 32 random bytes, hex encoded
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (h *CatalogHandler) auditWebhook(r *http.Request, action string, config *repositories.GitHubConfig, status, details string) {
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       action,
+		ResourceType: "github_webhook",
+		ResourceName: config.RepoOwner + "/" + config.RepoName,
+		Status:       status,
+		Details:      details,
+	})
+}
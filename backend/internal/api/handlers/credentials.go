@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
@@ -13,12 +14,14 @@ import (
 )
 
 type CredentialsHandler struct {
+	auditRecorder
 	secretRepo *repositories.SecretRepository
 }
 
-func NewCredentialsHandler() *CredentialsHandler {
+func NewCredentialsHandler(deps *Deps) *CredentialsHandler {
 	return &CredentialsHandler{
-		secretRepo: &repositories.SecretRepository{},
+		auditRecorder: newAuditRecorder(deps),
+		secretRepo:    deps.Secrets,
 	}
 }
 
@@ -87,7 +90,7 @@ func (h *CredentialsHandler) CreateCredential(w http.ResponseWriter, r *http.Req
 		Status:       "success",
 		Details:      "AWS credential created (encrypted)",
 	}
-	CreateAuditLogEntry(auditLog)
+	h.recordAudit(r.Context(), auditLog)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -95,7 +98,8 @@ func (h *CredentialsHandler) CreateCredential(w http.ResponseWriter, r *http.Req
 }
 
 // ListCredentials handles GET /api/v1/credentials
-// Returns all credentials (metadata only, never secrets)
+// Returns all credentials (metadata only, never secrets). ?access_type=write keeps
+// only credentials that can provision, e.g. for the provisioning form.
 func (h *CredentialsHandler) ListCredentials(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 	secrets, err := h.secretRepo.GetAll(ctx)
@@ -105,12 +109,91 @@ func (h *CredentialsHandler) ListCredentials(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if accessType := models.AccessType(r.URL.Query().Get("access_type")); accessType != "" {
+		if accessType != models.AccessTypeRead && accessType != models.AccessTypeWrite {
+			http.Error(w, "access_type must be read or write", http.StatusBadRequest)
+			return
+		}
+		filtered := []models.Secret{}
+		for _, secret := range secrets {
+			if secret.AccessType == accessType {
+				filtered = append(filtered, secret)
+			}
+		}
+		secrets = filtered
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(secrets)
 }
 
+// GetCredentialUsage handles GET /api/v1/credentials/{id}/usage
+// Superadmin only - lists what would lose its credential if it were deleted
+func (h *CredentialsHandler) GetCredentialUsage(w http.ResponseWriter, r *http.Request) {
+	credentialID := r.PathValue("id")
+	if _, err := h.secretRepo.FindByID(r.Context(), credentialID); err != nil {
+		http.Error(w, "Credential not found", http.StatusNotFound)
+		return
+	}
+
+	usage, err := h.secretRepo.GetUsage(r.Context(), credentialID)
+	if err != nil {
+		log.Printf("Failed to get credential usage: %v", err)
+		http.Error(w, "Failed to get credential usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// RotateCredential handles PUT /api/v1/credentials/{id}/rotate
+// Superadmin only - replaces the key pair of a credential in place, so projects
+// and resources using it pick up the new keys without being re-pointed
+func (h *CredentialsHandler) RotateCredential(w http.ResponseWriter, r *http.Request) {
+	credentialID := r.PathValue("id")
+	secret, err := h.secretRepo.FindByID(r.Context(), credentialID)
+	if err != nil {
+		http.Error(w, "Credential not found", http.StatusNotFound)
+		return
+	}
+
+	var req models.RotateSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.AccessKeyID == "" || req.SecretAccessKey == "" {
+		http.Error(w, "Missing required fields: access_key_id, secret_access_key", http.StatusBadRequest)
+		return
+	}
+
+	credentials := &models.AWSCredentials{
+		AccessKeyID:     req.AccessKeyID,
+		SecretAccessKey: req.SecretAccessKey,
+	}
+	if err := h.secretRepo.UpdateCredentials(r.Context(), credentialID, credentials); err != nil {
+		log.Printf("Failed to rotate credential: %v", err)
+		http.Error(w, "Failed to rotate credential", http.StatusInternalServerError)
+		return
+	}
+
+	auditLog := models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "rotate_aws_credential",
+		ResourceType: "credential",
+		ResourceName: secret.Name,
+		Status:       "success",
+		Details:      "AWS credential rotated (encrypted)",
+	}
+	h.recordAudit(r.Context(), auditLog)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // DeleteCredential handles DELETE /api/v1/credentials/:id
-// Superadmin only
+// Superadmin only. Returns 409 with the usage report while the credential is
+// referenced, unless ?force=true is given.
 func (h *CredentialsHandler) DeleteCredential(w http.ResponseWriter, r *http.Request) {
 	// Check superadmin role
 	userRole := middleware.GetUserRole(r.Context())
@@ -135,6 +218,25 @@ func (h *CredentialsHandler) DeleteCredential(w http.ResponseWriter, r *http.Req
 	}
 
 	ctx := context.Background()
+
+	// Refuse to orphan projects and resources unless the caller insists
+	usage, err := h.secretRepo.GetUsage(ctx, credentialID)
+	if err != nil {
+		log.Printf("Failed to get credential usage: %v", err)
+		http.Error(w, "Failed to check credential usage", http.StatusInternalServerError)
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+	if usage.InUse() && !force {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Credential is still in use; pass force=true to delete it and detach its references",
+			"usage": usage,
+		})
+		return
+	}
+
 	if err := h.secretRepo.Delete(ctx, credentialID); err != nil {
 		log.Printf("Failed to delete credential: %v", err)
 		http.Error(w, "Failed to delete credential", http.StatusInternalServerError)
@@ -150,7 +252,11 @@ func (h *CredentialsHandler) DeleteCredential(w http.ResponseWriter, r *http.Req
 		Status:       "success",
 		Details:      "AWS credential deleted",
 	}
-	CreateAuditLogEntry(auditLog)
+	if usage.InUse() {
+		auditLog.Details = fmt.Sprintf("AWS credential force-deleted; detached from %d projects, %d resources and %d discovered resources; rejected %d pending approvals; removed %d provisioning permissions",
+			usage.Projects.Count, usage.Resources.Count, usage.DiscoveredResources.Count, usage.PendingApprovals.Count, usage.Permissions.Count)
+	}
+	h.recordAudit(r.Context(), auditLog)
 
 	w.WriteHeader(http.StatusNoContent)
 }
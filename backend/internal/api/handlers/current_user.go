@@ -7,7 +7,6 @@ import (
 
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
-	"github.com/portalight/backend/internal/repositories"
 )
 
 // CurrentUserResponse represents the current logged-in user
@@ -22,7 +21,7 @@ type CurrentUserResponse struct {
 }
 
 // GetCurrentUser returns the currently logged-in user from JWT token
-func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+func (h *UsersHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -37,10 +36,9 @@ func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
 	// Find user in database
-	currentUser, err := userRepo.FindByID(ctx, userID)
+	currentUser, err := h.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/scorecard"
+	"github.com/portalight/backend/internal/services"
+)
+
+// Deps holds the repositories and clients shared by all handlers. main builds it
+// once at startup and passes it to the handler constructors, so caches and HTTP
+// clients are shared instead of being rebuilt per request. Handlers must not
+// construct repositories or services themselves.
+type Deps struct {
+	Users                   *repositories.UserRepository
+	Teams                   *repositories.TeamRepository
+	Projects                *repositories.ProjectRepository
+	ProjectDocs             *repositories.ProjectDocsRepository
+	Services                *repositories.ServiceRepository
+	ServiceLinks            *repositories.ServiceLinkRepository
+	ServiceResourceMappings *repositories.ServiceResourceMappingRepository
+	ServiceDependencies     *repositories.ServiceDependencyRepository
+	ServiceEnvironments     *repositories.ServiceEnvironmentRepository
+	Secrets                 *repositories.SecretRepository
+	AuditLogs               *repositories.AuditLogRepository
+	RevokedTokens           *repositories.RevokedTokenRepository
+	ProvisioningPermissions *repositories.ProvisioningPermissionRepository
+	ProvisioningApprovals   *repositories.ProvisioningApprovalRepository
+	Resources               *repositories.ResourceRepository
+	DiscoveredResources     *repositories.DiscoveredResourceRepository
+	Budgets                 *repositories.BudgetRepository
+	Digests                 *repositories.DigestRepository
+	ArgoCDApps              *repositories.ArgoCDRepository
+	GitHubConfig            *repositories.GitHubConfigRepository
+	SyncHistory             *repositories.SyncHistoryRepository
+	Search                  *repositories.SearchRepository
+	TagVocabulary           *repositories.TagVocabularyRepository
+	SMTPSettings            *repositories.SMTPSettingsRepository
+	NotificationPreferences *repositories.NotificationPreferencesRepository
+	AccessReport            *repositories.AccessReportRepository
+	NotificationChannels    *repositories.NotificationChannelRepository
+
+	ArgoCD       *services.ArgoCDClient
+	Discovery    *services.AWSDiscovery
+	Provisioner  *services.AWSProvisioner
+	Metrics      *services.AWSMetrics
+	ResourceSync *services.ResourceSyncService
+	Reconciler   *services.ResourceReconciler
+	TeamNotifier *services.TeamNotifier
+	Scorecards   *scorecard.Evaluator
+}
+
+// NewDeps builds every shared repository and client. The database must be connected.
+func NewDeps() *Deps {
+	projects := &repositories.ProjectRepository{}
+	notificationChannels := repositories.NewNotificationChannelRepository()
+	teamNotifier := services.NewTeamNotifier(notificationChannels, projects)
+
+	deps := &Deps{
+		Users:                   &repositories.UserRepository{},
+		Teams:                   &repositories.TeamRepository{},
+		Projects:                projects,
+		ProjectDocs:             repositories.NewProjectDocsRepository(),
+		Services:                &repositories.ServiceRepository{},
+		ServiceLinks:            repositories.NewServiceLinkRepository(),
+		ServiceResourceMappings: repositories.NewServiceResourceMappingRepository(),
+		ServiceDependencies:     repositories.NewServiceDependencyRepository(),
+		ServiceEnvironments:     repositories.NewServiceEnvironmentRepository(),
+		Secrets:                 &repositories.SecretRepository{},
+		AuditLogs:               &repositories.AuditLogRepository{},
+		RevokedTokens:           &repositories.RevokedTokenRepository{},
+		ProvisioningPermissions: &repositories.ProvisioningPermissionRepository{},
+		ProvisioningApprovals:   repositories.NewProvisioningApprovalRepository(),
+		Resources:               repositories.NewResourceRepository(database.DB),
+		DiscoveredResources:     repositories.NewDiscoveredResourceRepository(),
+		Budgets:                 repositories.NewBudgetRepository(),
+		Digests:                 repositories.NewDigestRepository(),
+		ArgoCDApps:              repositories.NewArgoCDRepository(),
+		GitHubConfig:            repositories.NewGitHubConfigRepository(database.DB),
+		SyncHistory:             repositories.NewSyncHistoryRepository(database.DB),
+		Search:                  repositories.NewSearchRepository(),
+		TagVocabulary:           repositories.NewTagVocabularyRepository(),
+		SMTPSettings:            repositories.NewSMTPSettingsRepository(),
+		NotificationPreferences: repositories.NewNotificationPreferencesRepository(),
+		AccessReport:            repositories.NewAccessReportRepository(),
+		NotificationChannels:    notificationChannels,
+
+		ArgoCD:       services.NewArgoCDClient(),
+		Discovery:    services.NewAWSDiscovery(),
+		Provisioner:  services.NewAWSProvisioner(),
+		Metrics:      services.NewAWSMetrics(),
+		ResourceSync: services.NewResourceSyncService(teamNotifier),
+		Reconciler:   services.NewResourceReconciler(),
+		TeamNotifier: teamNotifier,
+	}
+	deps.Scorecards = scorecard.NewEvaluator(deps.Services, &scorecard.Sources{
+		ArgoCDApps: deps.ArgoCDApps,
+		Mappings:   deps.ServiceResourceMappings,
+		Links:      deps.ServiceLinks,
+	})
+	return deps
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// moduleRoot is the backend module, relative to this package
+const moduleRoot = "../../.."
+
+// mayBuild reports whether repositories and services may be built in fn: in
+// constructors (NewDeps, NewX, newX), which run once at startup, in main, and in
+// WithQuerier, which binds a shared repository to a transaction
+func mayBuild(fn *ast.FuncDecl, pkg string) bool {
+	name := fn.Name.Name
+	return strings.HasPrefix(name, "New") || strings.HasPrefix(name, "new") || name == "WithQuerier" || pkg == "main" && name == "main"
+}
+
+// builds returns a description of the repository or service node constructs, or ""
+func builds(node ast.Node, pkg string) string {
+	switch n := node.(type) {
+	case *ast.CompositeLit:
+		switch t := n.Type.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := t.X.(*ast.Ident); ok && x.Name == "repositories" && strings.HasSuffix(t.Sel.Name, "Repository") {
+				return "repositories." + t.Sel.Name + "{}"
+			}
+		case *ast.Ident:
+			if pkg == "repositories" && strings.HasSuffix(t.Name, "Repository") {
+				return t.Name + "{}"
+			}
+		}
+	case *ast.CallExpr:
+		switch f := n.Fun.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := f.X.(*ast.Ident); ok && (x.Name == "repositories" || x.Name == "services") && strings.HasPrefix(f.Sel.Name, "New") {
+				return x.Name + "." + f.Sel.Name + "()"
+			}
+		case *ast.Ident:
+			if pkg == "repositories" && strings.HasPrefix(f.Name, "New") && strings.HasSuffix(f.Name, "Repository") {
+				return f.Name + "()"
+			}
+		}
+	}
+	return ""
+}
+
+// TestRepositoriesAreBuiltOnce enforces the dependency container: repositories and
+// services are built once, by NewDeps or another constructor, and shared. Building
+// one inside a handler or method would give every request its own copy and defeat
+// any cache or client it holds.
+func TestRepositoriesAreBuiltOnce(t *testing.T) {
+	files := 0
+	err := filepath.WalkDir(moduleRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		files++
+
+		pkg := file.Name.Name
+		for _, decl := range file.Decls {
+			// A package-level var is a second copy that bypasses Deps
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				ast.Inspect(gen, func(node ast.Node) bool {
+					if what := builds(node, pkg); what != "" {
+						t.Errorf("%s: package-level var builds %s; take it from Deps or a constructor instead", fset.Position(node.Pos()), what)
+					}
+					return true
+				})
+				continue
+			}
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || mayBuild(fn, pkg) {
+				continue
+			}
+			ast.Inspect(fn.Body, func(node ast.Node) bool {
+				if what := builds(node, pkg); what != "" {
+					t.Errorf("%s: %s builds %s; take it from Deps or a constructor instead", fset.Position(node.Pos()), fn.Name.Name, what)
+				}
+				return true
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to scan the module: %v", err)
+	}
+	if files == 0 {
+		t.Fatal("no Go files found")
+	}
+}
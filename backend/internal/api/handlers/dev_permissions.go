@@ -10,17 +10,20 @@ import (
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/services"
 )
 
 type DevPermissionsHandler struct {
+	auditRecorder
 	permissionRepo *repositories.ProvisioningPermissionRepository
 	userRepo       *repositories.UserRepository
 }
 
-func NewDevPermissionsHandler() *DevPermissionsHandler {
+func NewDevPermissionsHandler(deps *Deps) *DevPermissionsHandler {
 	return &DevPermissionsHandler{
-		permissionRepo: &repositories.ProvisioningPermissionRepository{},
-		userRepo:       &repositories.UserRepository{},
+		auditRecorder:  newAuditRecorder(deps),
+		permissionRepo: deps.ProvisioningPermissions,
+		userRepo:       deps.Users,
 	}
 }
 
@@ -84,6 +87,15 @@ func (h *DevPermissionsHandler) UpdateDevPermissions(w http.ResponseWriter, r *h
 		return
 	}
 
+	// Every requested type must be provisionable
+	allowedTypes := req.Types()
+	for _, t := range allowedTypes {
+		if _, ok := services.LookupProvisionerType(t); !ok {
+			http.Error(w, "Invalid resource type "+t+". Supported types: "+strings.Join(services.ProvisionableTypes(), ", "), http.StatusBadRequest)
+			return
+		}
+	}
+
 	ctx := context.Background()
 
 	// Verify the target user is a dev (only devs need explicit permissions)
@@ -109,17 +121,6 @@ func (h *DevPermissionsHandler) UpdateDevPermissions(w http.ResponseWriter, r *h
 	}
 
 	// Audit log
-	allowedTypes := []string{}
-	if req.S3Enabled {
-		allowedTypes = append(allowedTypes, "s3")
-	}
-	if req.SQSEnabled {
-		allowedTypes = append(allowedTypes, "sqs")
-	}
-	if req.SNSEnabled {
-		allowedTypes = append(allowedTypes, "sns")
-	}
-
 	auditLog := models.AuditLog{
 		UserEmail:    middleware.GetUserEmail(r.Context()),
 		Action:       "update_dev_provisioning_permissions",
@@ -128,7 +129,13 @@ func (h *DevPermissionsHandler) UpdateDevPermissions(w http.ResponseWriter, r *h
 		Status:       "success",
 		Details:      "Allowed types: " + strings.Join(allowedTypes, ", "),
 	}
-	CreateAuditLogEntry(auditLog)
+	if req.ProjectID != "" {
+		auditLog.Details += "; project: " + req.ProjectID
+	}
+	if req.CredentialID != "" {
+		auditLog.Details += "; credential: " + req.CredentialID
+	}
+	h.recordAudit(r.Context(), auditLog)
 
 	// Return updated permissions
 	permissions, _ := h.permissionRepo.GetUserPermissions(ctx, userID)
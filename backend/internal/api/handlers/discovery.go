@@ -16,22 +16,27 @@ type DiscoveryHandler struct {
 	discovery              *services.AWSDiscovery
 	secretRepo             *repositories.SecretRepository
 	discoveredResourceRepo *repositories.DiscoveredResourceRepository
+	projectRepo            *repositories.ProjectRepository
 }
 
 // NewDiscoveryHandler creates a new discovery handler
-func NewDiscoveryHandler() *DiscoveryHandler {
+func NewDiscoveryHandler(deps *Deps) *DiscoveryHandler {
 	return &DiscoveryHandler{
-		discovery:              services.NewAWSDiscovery(),
-		secretRepo:             &repositories.SecretRepository{},
-		discoveredResourceRepo: repositories.NewDiscoveredResourceRepository(),
+		discovery:              deps.Discovery,
+		secretRepo:             deps.Secrets,
+		discoveredResourceRepo: deps.DiscoveredResources,
+		projectRepo:            deps.Projects,
 	}
 }
 
 // DiscoverResourcesRequest is the request body for discovery
 type DiscoverResourcesRequest struct {
-	SecretID string   `json:"secret_id"`
-	Region   string   `json:"region"`
-	Types    []string `json:"types"` // Optional: specific types to discover (s3, sqs, sns, rds, lambda)
+	SecretID  string   `json:"secret_id"`
+	ProjectID string   `json:"project_id"` // Optional: its default credential is used when secret_id is empty
+	Region    string   `json:"region"`
+	Types     []string `json:"types"` // Optional: specific types to discover (s3, sqs, sns, rds, lambda, eks)
+
+	IncludeAllRegions bool `json:"include_all_regions"` // Also return S3 buckets of other regions
 }
 
 // DiscoverResources discovers AWS resources using the provided credentials
@@ -54,10 +59,16 @@ func (h *DiscoveryHandler) DiscoverResources(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if req.SecretID == "" {
-		http.Error(w, "secret_id is required", http.StatusBadRequest)
+	secretID, err := resolveSecretID(r.Context(), h.projectRepo, req.ProjectID, req.SecretID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if secretID == "" {
+		http.Error(w, "secret_id is required (or a project_id with a default credential)", http.StatusBadRequest)
 		return
 	}
+	req.SecretID = secretID
 
 	// Get the secret credentials
 	secret, credentials, err := h.secretRepo.GetByIDWithCredentials(r.Context(), req.SecretID)
@@ -97,7 +108,7 @@ func (h *DiscoveryHandler) DiscoverResources(w http.ResponseWriter, r *http.Requ
 	typesToDiscover := req.Types
 	if len(typesToDiscover) == 0 {
 		// Default to all types
-		typesToDiscover = []string{"s3", "sqs", "sns", "rds", "lambda"}
+		typesToDiscover = []string{"s3", "sqs", "sns", "rds", "lambda", "eks", "cloudformation", "kinesis", "eventbridge"}
 	}
 
 	for _, resourceType := range typesToDiscover {
@@ -106,7 +117,7 @@ func (h *DiscoveryHandler) DiscoverResources(w http.ResponseWriter, r *http.Requ
 
 		switch strings.ToLower(resourceType) {
 		case "s3":
-			resources, discoverErr = h.discovery.DiscoverS3(r.Context(), credentials, region)
+			resources, discoverErr = h.discovery.DiscoverS3(r.Context(), credentials, region, req.IncludeAllRegions)
 		case "sqs":
 			resources, discoverErr = h.discovery.DiscoverSQS(r.Context(), credentials, region)
 		case "sns":
@@ -115,6 +126,14 @@ func (h *DiscoveryHandler) DiscoverResources(w http.ResponseWriter, r *http.Requ
 			resources, discoverErr = h.discovery.DiscoverRDS(r.Context(), credentials, region)
 		case "lambda":
 			resources, discoverErr = h.discovery.DiscoverLambda(r.Context(), credentials, region)
+		case "eks":
+			resources, discoverErr = h.discovery.DiscoverEKS(r.Context(), credentials, region)
+		case "cloudformation":
+			resources, discoverErr = h.discovery.DiscoverCloudFormation(r.Context(), credentials, region)
+		case "kinesis":
+			resources, discoverErr = h.discovery.DiscoverKinesis(r.Context(), credentials, region)
+		case "eventbridge":
+			resources, discoverErr = h.discovery.DiscoverEventBridge(r.Context(), credentials, region)
 		}
 
 		if discoverErr != nil {
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfNoneMatchHas(t *testing.T) {
+	const etag = `"abc"`
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := ifNoneMatchHas(tt.ifNoneMatch, etag); got != tt.want {
+			t.Errorf("If-None-Match %q: match = %t, want %t", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCachedList(t *testing.T) {
+	items := []string{"payments", "ledger"}
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/api/v1/projects", nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		writeCachedList(w, r, items, len(items), nil, "Failed to fetch projects")
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("first GET: status %d, ETag %q, %d bytes", first.Code, etag, first.Body.Len())
+	}
+	if cacheControl := first.Header().Get("Cache-Control"); cacheControl != "private, max-age=0" {
+		t.Errorf("Cache-Control = %q", cacheControl)
+	}
+
+	revalidated := get(etag)
+	if revalidated.Code != http.StatusNotModified || revalidated.Body.Len() != 0 {
+		t.Errorf("revalidation: status %d with %d bytes, want 304 without a body", revalidated.Code, revalidated.Body.Len())
+	}
+
+	items = append(items, "billing")
+	changed := get(etag)
+	if changed.Code != http.StatusOK || changed.Header().Get("ETag") == etag {
+		t.Errorf("after a change: status %d, ETag %q, want 200 with a new ETag", changed.Code, changed.Header().Get("ETag"))
+	}
+}
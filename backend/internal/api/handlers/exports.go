@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+)
+
+// parseExportDate parses a from/to query value as YYYY-MM-DD or RFC3339
+func parseExportDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// setExportHeaders sets the content type and a dated attachment filename
+func setExportHeaders(w http.ResponseWriter, prefix, format string) {
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	filename := fmt.Sprintf("%s-%s.%s", prefix, time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+}
+
+// inventoryItem is one resource of a project's inventory export
+type inventoryItem struct {
+	ARN           string     `json:"arn"`
+	ResourceType  string     `json:"resource_type"`
+	Name          string     `json:"name"`
+	Region        string     `json:"region"`
+	Status        string     `json:"status"`
+	ProvisionedAt *time.Time `json:"provisioned_at"` // Set for resources provisioned through the portal
+	DiscoveredAt  *time.Time `json:"discovered_at"`  // Set for resources tracked in discovered_resources
+	LastSyncedAt  *time.Time `json:"last_synced_at"`
+}
+
+// ExportProjectResources handles GET /api/v1/projects/{id}/resources/export?format=csv|json&status=...
+// It exports the project's provisioned and discovered resources as one inventory. A resource
+// present in both is listed once, with the discovered row's fields. Non-superadmins must
+// have access to the project.
+func (h *ProvisionHandler) ExportProjectResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := r.PathValue("id")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		http.Error(w, "Invalid format. Supported formats: csv, json", http.StatusBadRequest)
+		return
+	}
+	status := r.URL.Query().Get("status")
+
+	if _, err := h.projectRepo.FindByID(ctx, projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, projectID, middleware.GetUserID(ctx))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	items, err := h.projectInventory(ctx, projectID, status)
+	if err != nil {
+		log.Printf("Failed to export resources for project %s: %v", projectID, err)
+		http.Error(w, "Failed to export resources", http.StatusInternalServerError)
+		return
+	}
+
+	details := fmt.Sprintf("Exported %d resources as %s", len(items), format)
+	if status != "" {
+		details += " (status " + status + ")"
+	}
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "export_project_resources",
+		ResourceType: "project",
+		ResourceID:   projectID,
+		ProjectID:    projectID,
+		Status:       "success",
+		Details:      details,
+	})
+
+	setExportHeaders(w, "project-"+projectID+"-resources", format)
+
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"arn", "resource_type", "name", "region", "status", "provisioned_at", "discovered_at", "last_synced_at"})
+		for _, item := range items {
+			if err = writer.Write([]string{
+				item.ARN,
+				item.ResourceType,
+				item.Name,
+				item.Region,
+				item.Status,
+				formatExportTime(item.ProvisionedAt),
+				formatExportTime(item.DiscoveredAt),
+				formatExportTime(item.LastSyncedAt),
+			}); err != nil {
+				break
+			}
+		}
+		writer.Flush()
+	} else {
+		err = streamJSONArray(w, func(emit func(interface{}) error) error {
+			for _, item := range items {
+				if err := emit(item); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if err != nil {
+		log.Printf("Failed to write resource export for project %s: %v", projectID, err)
+	}
+}
+
+// projectInventory merges a project's provisioned and discovered resources, deduplicated
+// by ARN with the discovered row winning, keeping only those with the given status (if any)
+func (h *ProvisionHandler) projectInventory(ctx context.Context, projectID, status string) ([]inventoryItem, error) {
+	provisioned, err := h.resourceRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provisioned resources: %w", err)
+	}
+	discovered, err := h.discoveredResourceRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discovered resources: %w", err)
+	}
+
+	provisionedAt := make(map[string]time.Time, len(provisioned))
+	for _, res := range provisioned {
+		if res.ARN != "" {
+			provisionedAt[res.ARN] = res.CreatedAt
+		}
+	}
+
+	items := []inventoryItem{}
+	seen := make(map[string]bool, len(discovered))
+	for _, res := range discovered {
+		seen[res.ARN] = true
+		if status != "" && string(res.Status) != status {
+			continue
+		}
+		item := inventoryItem{
+			ARN:          res.ARN,
+			ResourceType: res.ResourceType,
+			Name:         res.Name,
+			Region:       res.Region,
+			Status:       string(res.Status),
+			DiscoveredAt: &res.DiscoveredAt,
+			LastSyncedAt: res.LastSyncedAt,
+		}
+		if t, ok := provisionedAt[res.ARN]; ok {
+			item.ProvisionedAt = &t
+		}
+		items = append(items, item)
+	}
+
+	for _, res := range provisioned {
+		// Resources that never reached AWS have no ARN and are always listed
+		if (res.ARN != "" && seen[res.ARN]) || (status != "" && string(res.Status) != status) {
+			continue
+		}
+		var config struct {
+			Region string `json:"region"`
+		}
+		json.Unmarshal(res.Config, &config)
+		items = append(items, inventoryItem{
+			ARN:           res.ARN,
+			ResourceType:  res.Type,
+			Name:          res.Name,
+			Region:        config.Region,
+			Status:        string(res.Status),
+			ProvisionedAt: &res.CreatedAt,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ResourceType != items[j].ResourceType {
+			return items[i].ResourceType < items[j].ResourceType
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// formatExportTime formats an optional timestamp as RFC3339, or empty when unset
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// streamJSONArray writes the items emitted by produce as a JSON array, one at a time
+func streamJSONArray(w http.ResponseWriter, produce func(emit func(interface{}) error) error) error {
+	encoder := json.NewEncoder(w)
+	first := true
+
+	if _, err := w.Write([]byte("[")); err != nil {
+		return err
+	}
+	err := produce(func(item interface{}) error {
+		if !first {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		first = false
+		return encoder.Encode(item)
+	})
+	w.Write([]byte("]\n"))
+	return err
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/database"
+)
+
+// HealthHandler reports whether the server's dependencies are reachable
+type HealthHandler struct{}
+
+// NewHealthHandler creates a health handler
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+// PoolStats is a snapshot of the database connection pool
+type PoolStats struct {
+	MaxConns                int32   `json:"max_conns"`
+	TotalConns              int32   `json:"total_conns"`
+	AcquiredConns           int32   `json:"acquired_conns"`
+	IdleConns               int32   `json:"idle_conns"`
+	AcquireCount            int64   `json:"acquire_count"`
+	EmptyAcquireCount       int64   `json:"empty_acquire_count"`    // Acquires that had to wait for a connection
+	CanceledAcquireCount    int64   `json:"canceled_acquire_count"` // Acquires that timed out or were canceled while waiting
+	EmptyAcquireWaitSeconds float64 `json:"empty_acquire_wait_seconds"`
+}
+
+// DeepHealthResponse is the body of GET /health/deep
+type DeepHealthResponse struct {
+	Status   string    `json:"status"`   // healthy or unhealthy
+	Database string    `json:"database"` // ok or unreachable
+	Pool     PoolStats `json:"pool"`
+}
+
+// DeepHealth handles GET /health/deep (superadmin only): pings the database under the query timeout and
+// reports the pool statistics. It returns 503 when the database does not answer.
+func (h *HealthHandler) DeepHealth(w http.ResponseWriter, r *http.Request) {
+	stat := database.DB.Stat()
+	resp := DeepHealthResponse{
+		Status:   "healthy",
+		Database: "ok",
+		Pool: PoolStats{
+			MaxConns:                stat.MaxConns(),
+			TotalConns:              stat.TotalConns(),
+			AcquiredConns:           stat.AcquiredConns(),
+			IdleConns:               stat.IdleConns(),
+			AcquireCount:            stat.AcquireCount(),
+			EmptyAcquireCount:       stat.EmptyAcquireCount(),
+			CanceledAcquireCount:    stat.CanceledAcquireCount(),
+			EmptyAcquireWaitSeconds: stat.EmptyAcquireWaitTime().Seconds(),
+		},
+	}
+
+	status := http.StatusOK
+	ctx, cancel := database.WithTimeout(r.Context())
+	defer cancel()
+	if err := database.DB.Ping(ctx); err != nil {
+		status = http.StatusServiceUnavailable
+		resp.Status = "unhealthy"
+		resp.Database = "unreachable" // The error may name the database host; it is only logged
+		log.Printf("Deep health check: database ping failed: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
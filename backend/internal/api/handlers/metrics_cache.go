@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/services"
+)
+
+const metricsCacheSize = 512
+
+// metricsCacheTTLs scale with the CloudWatch granularity of each period, since
+// nothing new can show up before the next datapoint
+var metricsCacheTTLs = map[string]time.Duration{
+	"1h":  1 * time.Minute,  // 1 minute granularity
+	"6h":  3 * time.Minute,  // 5 minute granularity
+	"24h": 5 * time.Minute,  // 15 minute granularity
+	"7d":  10 * time.Minute, // 1 hour granularity
+	"30d": 30 * time.Minute, // 1 day granularity
+}
+
+// metricsCacheKey identifies one metrics query
+type metricsCacheKey struct {
+	secretID     string
+	resourceType string
+	resourceName string
+	region       string
+	period       string
+}
+
+type metricsEntry struct {
+	key       metricsCacheKey
+	metrics   *services.ResourceMetrics // Never modified once cached
+	expiresAt time.Time
+}
+
+// metricsCache is a size-bounded, TTL-based LRU of CloudWatch query results
+type metricsCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttls     map[string]time.Duration
+	order    *list.List // Front is most recently used
+	entries  map[metricsCacheKey]*list.Element
+}
+
+func newMetricsCache(capacity int, ttls map[string]time.Duration) *metricsCache {
+	return &metricsCache{
+		capacity: capacity,
+		ttls:     ttls,
+		order:    list.New(),
+		entries:  make(map[metricsCacheKey]*list.Element),
+	}
+}
+
+// get returns the cached metrics, or nil if missing or expired
+func (c *metricsCache) get(key metricsCacheKey) *services.ResourceMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*metricsEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.metrics
+}
+
+// put stores metrics with the TTL of their period, evicting the least recently used
+// entry when full. Periods without a TTL are not cached.
+func (c *metricsCache) put(key metricsCacheKey, metrics *services.ResourceMetrics) {
+	ttl, ok := c.ttls[key.period]
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	c.entries[key] = c.order.PushFront(&metricsEntry{
+		key:       key,
+		metrics:   metrics,
+		expiresAt: time.Now().Add(ttl),
+	})
+
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// removeElement unlinks an entry. Caller must hold c.mu.
+func (c *metricsCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*metricsEntry)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+}
+
+// bypassMetricsCache reports whether a request asks for fresh metrics with
+// ?refresh=true; only leads and superadmins may, since it costs CloudWatch calls
+func bypassMetricsCache(r *http.Request, userRole string) bool {
+	return r.URL.Query().Get("refresh") == "true" && (userRole == "superadmin" || userRole == "lead")
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/services"
+)
+
+func testMetricsKey(name, period string) metricsCacheKey {
+	return metricsCacheKey{secretID: "secret", resourceType: "sqs", resourceName: name, region: "eu-west-1", period: period}
+}
+
+func TestMetricsCacheExpiresPerPeriod(t *testing.T) {
+	cache := newMetricsCache(10, map[string]time.Duration{
+		"1h": 20 * time.Millisecond,
+		"7d": time.Minute,
+	})
+	short, long := testMetricsKey("queue", "1h"), testMetricsKey("queue", "7d")
+	cache.put(short, &services.ResourceMetrics{Period: "1h"})
+	cache.put(long, &services.ResourceMetrics{Period: "7d"})
+
+	if cache.get(short) == nil || cache.get(long) == nil {
+		t.Fatal("fresh entries were not returned")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if cache.get(short) != nil {
+		t.Error("1h entry outlived its TTL")
+	}
+	if cache.get(long) == nil {
+		t.Error("7d entry expired with the 1h TTL")
+	}
+	if _, ok := cache.entries[short]; ok {
+		t.Error("expired entry was not removed")
+	}
+}
+
+func TestMetricsCacheSkipsPeriodsWithoutTTL(t *testing.T) {
+	cache := newMetricsCache(10, map[string]time.Duration{"1h": time.Minute})
+	key := testMetricsKey("queue", "90d")
+	cache.put(key, &services.ResourceMetrics{})
+	if cache.get(key) != nil {
+		t.Error("a period without a TTL was cached")
+	}
+}
+
+func TestMetricsCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newMetricsCache(2, metricsCacheTTLs)
+	a, b, c := testMetricsKey("a", "1h"), testMetricsKey("b", "1h"), testMetricsKey("c", "1h")
+	cache.put(a, &services.ResourceMetrics{})
+	cache.put(b, &services.ResourceMetrics{})
+	cache.get(a) // b is now the least recently used
+	cache.put(c, &services.ResourceMetrics{})
+
+	if cache.get(b) != nil {
+		t.Error("least recently used entry was not evicted")
+	}
+	if cache.get(a) == nil || cache.get(c) == nil {
+		t.Error("recently used entries were evicted")
+	}
+}
+
+func TestMetricsCacheConcurrentAccess(t *testing.T) {
+	const capacity = 16
+	cache := newMetricsCache(capacity, metricsCacheTTLs)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := testMetricsKey(fmt.Sprintf("queue-%d", (worker+i)%40), "1h")
+				if cached := cache.get(key); cached != nil && cached.ResourceARN != key.resourceName {
+					t.Errorf("got metrics of %s for %s", cached.ResourceARN, key.resourceName)
+					return
+				}
+				cache.put(key, &services.ResourceMetrics{ResourceARN: key.resourceName})
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if cache.order.Len() > capacity || len(cache.entries) != cache.order.Len() {
+		t.Errorf("cache holds %d list entries and %d map entries, capacity %d", cache.order.Len(), len(cache.entries), capacity)
+	}
+}
+
+func TestBypassMetricsCache(t *testing.T) {
+	tests := []struct {
+		url  string
+		role string
+		want bool
+	}{
+		{"/api/v1/resources/metrics?refresh=true", "superadmin", true},
+		{"/api/v1/resources/metrics?refresh=true", "lead", true},
+		{"/api/v1/resources/metrics?refresh=true", "dev", false},
+		{"/api/v1/resources/metrics", "superadmin", false},
+		{"/api/v1/resources/metrics?refresh=1", "lead", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", tt.url, nil)
+		if got := bypassMetricsCache(r, tt.role); got != tt.want {
+			t.Errorf("%s as %s: bypass = %t, want %t", tt.url, tt.role, got, tt.want)
+		}
+	}
+}
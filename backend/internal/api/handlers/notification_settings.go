@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// NotificationSettingsHandler serves the SMTP settings and users' notification preferences
+type NotificationSettingsHandler struct {
+	auditRecorder
+	smtpRepo  *repositories.SMTPSettingsRepository
+	prefsRepo *repositories.NotificationPreferencesRepository
+}
+
+// NewNotificationSettingsHandler creates a new NotificationSettingsHandler
+func NewNotificationSettingsHandler(deps *Deps) *NotificationSettingsHandler {
+	return &NotificationSettingsHandler{
+		auditRecorder: newAuditRecorder(deps),
+		smtpRepo:      deps.SMTPSettings,
+		prefsRepo:     deps.NotificationPreferences,
+	}
+}
+
+// GetSMTPSettings returns the outgoing mail server settings; the password is never returned
+func (h *NotificationSettingsHandler) GetSMTPSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.smtpRepo.Get(r.Context())
+	if err != nil {
+		log.Printf("Failed to load SMTP settings: %v", err)
+		http.Error(w, "Failed to load SMTP settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSMTPSettings replaces the outgoing mail server settings. An empty password
+// keeps the stored one; an empty host falls back to the SMTP_* environment.
+func (h *NotificationSettingsHandler) UpdateSMTPSettings(w http.ResponseWriter, r *http.Request) {
+	var req models.SMTPSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.TLSMode == "" {
+		req.TLSMode = models.SMTPTLSStartTLS
+	}
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.smtpRepo.Save(r.Context(), &req); err != nil {
+		log.Printf("Failed to save SMTP settings: %v", err)
+		http.Error(w, "Failed to save SMTP settings", http.StatusInternalServerError)
+		return
+	}
+
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"host":         req.Host,
+		"port":         req.Port,
+		"tls_mode":     req.TLSMode,
+		"from":         req.From,
+		"password_set": req.PasswordSet,
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "update_smtp_settings",
+		ResourceType: "settings",
+		ResourceName: "smtp",
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+// GetNotificationPreferences returns which notification events the current user receives
+func (h *NotificationSettingsHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.prefsRepo.Get(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to load notification preferences of user %s: %v", userID, err)
+		http.Error(w, "Failed to load notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateNotificationPreferences saves which notification events the current user
+// receives. Events left out of the request are enabled.
+func (h *NotificationSettingsHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	known := make(map[string]bool, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		known[event] = true
+	}
+	for event := range req.Events {
+		if !known[event] {
+			http.Error(w, "Unknown notification event: "+event, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.prefsRepo.Save(r.Context(), userID, &req); err != nil {
+		log.Printf("Failed to save notification preferences of user %s: %v", userID, err)
+		http.Error(w, "Failed to save notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	prefs, err := h.prefsRepo.Get(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to load notification preferences of user %s: %v", userID, err)
+		http.Error(w, "Failed to load notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
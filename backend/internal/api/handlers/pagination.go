@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ListResponse is the envelope of paginated list endpoints
+type ListResponse struct {
+	Items interface{} `json:"items"`
+	Total int         `json:"total"`
+}
+
+// parseListOptions reads limit, offset, sort_by and sort_dir from the query string.
+// Sort values are validated by the repository against its allowed columns.
+func parseListOptions(r *http.Request) (repositories.ListOptions, error) {
+	query := r.URL.Query()
+	opts := repositories.ListOptions{
+		SortField: query.Get("sort_by"),
+		SortDir:   query.Get("sort_dir"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return opts, errors.New("Invalid limit")
+		}
+		opts.Limit = n
+	}
+	if value := query.Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return opts, errors.New("Invalid offset")
+		}
+		opts.Offset = n
+	}
+
+	return opts, nil
+}
+
+// writeList encodes one page of a list endpoint, or maps a repository error to a response
+func writeList(w http.ResponseWriter, items interface{}, total int, err error, errorMessage string) {
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidListOptions) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, errorMessage, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListResponse{Items: items, Total: total})
+}
+
+// writeCachedList is writeList with an ETag, answering 304 Not Modified when the page
+// is unchanged since the client's If-None-Match
+func writeCachedList(w http.ResponseWriter, r *http.Request, items interface{}, total int, err error, errorMessage string) {
+	if err != nil {
+		writeList(w, nil, 0, err, errorMessage)
+		return
+	}
+	writeJSONWithETag(w, r, ListResponse{Items: items, Total: total})
+}
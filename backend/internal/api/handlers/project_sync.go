@@ -3,23 +3,30 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
 )
 
 type ProjectSyncHandler struct {
+	auditRecorder
 	syncer      *catalog.Syncer
 	projectRepo *repositories.ProjectRepository
+	historyRepo *repositories.SyncHistoryRepository
 }
 
-func NewProjectSyncHandler(syncer *catalog.Syncer, projectRepo *repositories.ProjectRepository) *ProjectSyncHandler {
+func NewProjectSyncHandler(deps *Deps, syncer *catalog.Syncer) *ProjectSyncHandler {
 	return &ProjectSyncHandler{
-		syncer:      syncer,
-		projectRepo: projectRepo,
+		auditRecorder: newAuditRecorder(deps),
+		syncer:        syncer,
+		projectRepo:   deps.Projects,
+		historyRepo:   deps.SyncHistory,
 	}
 }
 
@@ -71,6 +78,21 @@ func (h *ProjectSyncHandler) SyncProject(w http.ResponseWriter, r *http.Request)
 		userName,
 	)
 
+	auditLog := models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "sync_project",
+		ResourceType: "project",
+		ResourceID:   project.ID,
+		ResourceName: project.Name,
+		ProjectID:    project.ID,
+		Status:       "success",
+	}
+	if err != nil {
+		auditLog.Status = "failed"
+		auditLog.Details = err.Error()
+	}
+	h.recordAudit(r.Context(), auditLog)
+
 	if err != nil {
 		log.Printf("❌ [Manual Sync] Failed to sync project: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -80,10 +102,159 @@ func (h *ProjectSyncHandler) SyncProject(w http.ResponseWriter, r *http.Request)
 	log.Printf("✅ [Manual Sync] Successfully synced project: %s", project.Name)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success":      true,
-		"project_name": history.ProjectName,
-		"status":       history.Status,
-		"message":      "Project synced successfully",
+	json.NewEncoder(w).Encode(models.ProjectSyncResponse{
+		Success:     true,
+		ProjectName: history.ProjectName,
+		Status:      history.Status,
+		Message:     "Project synced successfully",
 	})
 }
+
+// ReconcileProject re-runs a full sync for one project, fixing missing services,
+// stale orphans and wrong owners. Only lead and superadmin can reconcile, and leads
+// only projects they have access to.
+func (h *ProjectSyncHandler) ReconcileProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+	if userRole != "superadmin" && userRole != "lead" {
+		http.Error(w, "Forbidden: Only leads and superadmins can reconcile projects", http.StatusForbidden)
+		return
+	}
+
+	// Extract project ID from URL: /api/v1/projects/{id}/reconcile
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[1] != "reconcile" {
+		http.Error(w, "Invalid URL", http.StatusBadRequest)
+		return
+	}
+	projectID := parts[0]
+
+	project, err := h.projectRepo.FindByID(r.Context(), projectID)
+	if err != nil || project == nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	if userRole != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(r.Context(), projectID, middleware.GetUserID(r.Context()))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if project.CatalogFilePath == "" {
+		http.Error(w, "Project is not linked to a catalog file", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("🔧 [Reconcile] Reconciling project '%s' from %s", project.Name, project.CatalogFilePath)
+
+	userEmail := middleware.GetUserEmail(r.Context())
+	history, err := h.syncer.ReconcileProject(r.Context(), project, middleware.GetUserID(r.Context()), userEmail)
+
+	auditLog := models.AuditLog{
+		UserEmail:    userEmail,
+		Action:       "reconcile_project",
+		ResourceType: "project",
+		ResourceID:   project.ID,
+		ResourceName: project.Name,
+		ProjectID:    project.ID,
+		Status:       "success",
+	}
+	if err != nil {
+		log.Printf("❌ [Reconcile] Failed to reconcile project %s: %v", project.Name, err)
+		auditLog.Status = "failed"
+		auditLog.Details = err.Error()
+	}
+	h.recordAudit(r.Context(), auditLog)
+
+	// The sync history is returned even on failure so the plan can be inspected
+	if history == nil {
+		http.Error(w, "Failed to reconcile project: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(history)
+}
+
+// syncHistoryResponse is one page of sync history
+type syncHistoryResponse struct {
+	History []models.SyncHistory `json:"history"`
+	Total   int                  `json:"total"`
+}
+
+// GetProjectSyncHistory handles GET /api/v1/projects/{id}/sync-history?limit=20&offset=0.
+// Leads must have access to the project.
+func (h *ProjectSyncHandler) GetProjectSyncHistory(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.projectRepo.FindByID(r.Context(), projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	if middleware.GetUserRole(r.Context()) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(r.Context(), projectID, middleware.GetUserID(r.Context()))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	history, total, err := h.historyRepo.FindByProjectID(r.Context(), projectID, opts)
+	writeSyncHistory(w, history, total, err)
+}
+
+// GetSyncHistory handles GET /api/v1/sync-history?limit=20&offset=0 across all projects
+func (h *ProjectSyncHandler) GetSyncHistory(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, total, err := h.historyRepo.FindAll(r.Context(), opts)
+	writeSyncHistory(w, history, total, err)
+}
+
+// writeSyncHistory encodes a page of sync history, or maps a repository error to a response
+func writeSyncHistory(w http.ResponseWriter, history []models.SyncHistory, total int, err error) {
+	if err != nil {
+		log.Printf("Failed to list sync history: %v", err)
+		if errors.Is(err, repositories.ErrInvalidListOptions) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to fetch sync history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(syncHistoryResponse{History: history, Total: total})
+}
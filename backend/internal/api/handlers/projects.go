@@ -3,36 +3,62 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
 	"github.com/portalight/backend/internal/repositories"
 )
 
-// GetProjects returns all projects
-func GetProjects(w http.ResponseWriter, r *http.Request) {
+// ProjectsHandler serves the project endpoints
+type ProjectsHandler struct {
+	auditRecorder
+	projectRepo *repositories.ProjectRepository
+	docsRepo    *repositories.ProjectDocsRepository
+	serviceRepo *repositories.ServiceRepository
+	teamRepo    *repositories.TeamRepository
+	budgetRepo  *repositories.BudgetRepository
+	secretRepo  *repositories.SecretRepository
+
+	resourceRepo *repositories.DiscoveredResourceRepository
+}
+
+// NewProjectsHandler creates a new ProjectsHandler
+func NewProjectsHandler(deps *Deps) *ProjectsHandler {
+	return &ProjectsHandler{
+		auditRecorder: newAuditRecorder(deps),
+		projectRepo:   deps.Projects,
+		docsRepo:      deps.ProjectDocs,
+		serviceRepo:   deps.Services,
+		teamRepo:      deps.Teams,
+		budgetRepo:    deps.Budgets,
+		secretRepo:    deps.Secrets,
+		resourceRepo:  deps.DiscoveredResources,
+	}
+}
+
+// GetProjects returns one page of projects as {"items": [...], "total": N}
+func (h *ProjectsHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
 
-	projects, err := projectRepo.GetAll(ctx)
+	opts, err := parseListOptions(r)
 	if err != nil {
-		http.Error(w, "Failed to fetch projects", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(projects)
+	projects, total, err := h.projectRepo.GetAll(ctx, opts)
+	writeCachedList(w, r, projects, total, err, "Failed to fetch projects")
 }
 
 // GetProjectByID returns a single project with its associated services
-func GetProjectByID(w http.ResponseWriter, r *http.Request) {
+func (h *ProjectsHandler) GetProjectByID(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
-	serviceRepo := &repositories.ServiceRepository{}
-	teamRepo := &repositories.TeamRepository{}
 
 	// Extract ID/name from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
@@ -44,9 +70,9 @@ func GetProjectByID(w http.ResponseWriter, r *http.Request) {
 
 	// Simple UUID check: 36 characters with hyphens in right places
 	if len(projectIdentifier) == 36 && strings.Count(projectIdentifier, "-") == 4 {
-		project, err = projectRepo.FindByID(ctx, projectIdentifier)
+		project, err = h.projectRepo.FindByID(ctx, projectIdentifier)
 	} else {
-		project, err = projectRepo.FindByName(ctx, projectIdentifier)
+		project, err = h.projectRepo.FindByName(ctx, projectIdentifier)
 	}
 
 	if err != nil {
@@ -60,7 +86,7 @@ func GetProjectByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get associated services
-	services, err := serviceRepo.FindByProjectID(ctx, project.ID)
+	services, err := h.serviceRepo.FindByProjectID(ctx, project.ID)
 	if err != nil {
 		// Log error but continue with empty services
 		log.Printf("Failed to fetch services for project %s: %v", project.ID, err)
@@ -70,24 +96,130 @@ func GetProjectByID(w http.ResponseWriter, r *http.Request) {
 	// Get team name
 	var teamName string
 	if project.OwnerTeamID != "" {
-		team, err := teamRepo.FindByID(ctx, project.OwnerTeamID)
+		team, err := h.teamRepo.FindByID(ctx, project.OwnerTeamID)
 		if err == nil {
 			teamName = team.Name
 		}
 	}
 
+	// Get budgets and their breach state
+	budgets, err := h.budgetRepo.ListByProject(ctx, project.ID)
+	if err != nil {
+		log.Printf("Failed to fetch budgets for project %s: %v", project.ID, err)
+		budgets = []models.ProjectBudget{}
+	}
+	breached := false
+	for _, budget := range budgets {
+		if budget.Breach != nil {
+			breached = true
+		}
+	}
+
 	result := models.ProjectWithServices{
-		Project:  *project,
-		Services: services,
-		TeamName: teamName,
+		Project:        *project,
+		Services:       services,
+		TeamName:       teamName,
+		Budgets:        budgets,
+		BudgetBreached: breached,
+	}
+
+	writeJSONWithETag(w, r, result)
+}
+
+// GetProjectStats handles GET /api/v1/projects/{id}/stats: service counts by
+// language, environment and team, and discovered resource counts by type
+func (h *ProjectsHandler) GetProjectStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := r.PathValue("id")
+
+	if _, err := h.projectRepo.FindByID(ctx, projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, projectID, middleware.GetUserID(ctx))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	stats, err := h.serviceRepo.GetProjectStats(ctx, projectID)
+	if err != nil {
+		log.Printf("Failed to compute stats of project %s: %v", projectID, err)
+		http.Error(w, "Failed to compute project stats", http.StatusInternalServerError)
+		return
+	}
+	stats.ResourceCount, stats.ResourcesByType, err = h.resourceRepo.CountByProjectID(ctx, projectID)
+	if err != nil {
+		log.Printf("Failed to count resources of project %s: %v", projectID, err)
+		http.Error(w, "Failed to compute project stats", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetProjectDocs handles GET /api/v1/projects/{id}/docs: the raw markdown of the
+// file the catalog names as metadata.docs, as of the last sync. The ETag is the
+// file's blob SHA (or a hash of the content when the sync recorded none), so
+// clients revalidate with If-None-Match.
+func (h *ProjectsHandler) GetProjectDocs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := r.PathValue("id")
+
+	if _, err := h.projectRepo.FindByID(ctx, projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, projectID, middleware.GetUserID(ctx))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	docs, err := h.docsRepo.FindByProjectID(ctx, projectID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Project has no docs", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load docs of project %s: %v", projectID, err)
+		http.Error(w, "Failed to load project docs", http.StatusInternalServerError)
+		return
+	}
+
+	etag := etagOf([]byte(docs.Content))
+	if docs.SHA != "" {
+		etag = `"` + docs.SHA + `"`
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Docs-Path", docs.Path)
+	if ifNoneMatchHas(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Write([]byte(docs.Content))
 }
 
 // CreateProject creates a new project
-func CreateProject(w http.ResponseWriter, r *http.Request) {
+func (h *ProjectsHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	var newProject models.Project
 	if err := json.NewDecoder(r.Body).Decode(&newProject); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -95,15 +227,14 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
 
-	if err := projectRepo.Create(ctx, &newProject); err != nil {
+	if err := h.projectRepo.Create(ctx, &newProject); err != nil {
 		http.Error(w, "Failed to create project", http.StatusInternalServerError)
 		return
 	}
 
 	// Create audit log
-	detailsJSON, _ := json.Marshal(map[string]interface{}{
+	detailsJSON := redact.JSON(map[string]interface{}{
 		"project_id":     newProject.ID,
 		"project_name":   newProject.Name,
 		"description":    newProject.Description,
@@ -118,10 +249,11 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 		ResourceType: "project",
 		ResourceID:   newProject.ID,
 		ResourceName: newProject.Name,
-		Details:      string(detailsJSON),
+		ProjectID:    newProject.ID,
+		Details:      detailsJSON,
 		Status:       "success",
 	}
-	CreateAuditLogEntry(auditLog)
+	h.recordAudit(r.Context(), auditLog)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -129,7 +261,7 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateProject updates an existing project
-func UpdateProject(w http.ResponseWriter, r *http.Request) {
+func (h *ProjectsHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
 	projectID := strings.Split(path, "/")[0]
@@ -141,10 +273,9 @@ func UpdateProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
 
 	// Find project
-	project, err := projectRepo.FindByID(ctx, projectID)
+	project, err := h.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		http.Error(w, "Project not found", http.StatusNotFound)
 		return
@@ -166,27 +297,71 @@ func UpdateProject(w http.ResponseWriter, r *http.Request) {
 	if owner, ok := updateData["owner_team_id"].(string); ok {
 		project.OwnerTeamID = owner
 	}
+	// Default AWS credential for provisioning, discovery and sync; "" clears it.
+	// Only superadmins and leads with access to the project may point it at an account.
+	if secretID, ok := updateData["secret_id"].(string); ok && secretID != project.SecretID {
+		allowed, err := h.canChangeCredential(r.Context(), projectID)
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "Forbidden: only superadmins and leads of the project can change its credential", http.StatusForbidden)
+			return
+		}
+		if secretID != "" {
+			if _, err := h.secretRepo.FindByID(ctx, secretID); err != nil {
+				http.Error(w, "Credential not found", http.StatusBadRequest)
+				return
+			}
+		}
+		project.SecretID = secretID
+	}
 
 	// Save to database
-	if err := projectRepo.Update(ctx, project); err != nil {
+	if err := h.projectRepo.Update(ctx, project); err != nil {
 		http.Error(w, "Failed to update project", http.StatusInternalServerError)
 		return
 	}
 
+	detailsJSON := redact.JSON(updateData)
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "update_project",
+		ResourceType: "project",
+		ResourceID:   project.ID,
+		ResourceName: project.Name,
+		ProjectID:    project.ID,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(project)
 }
 
+// canChangeCredential reports whether the caller may change a project's default
+// credential: superadmins always, leads only on projects they can access
+func (h *ProjectsHandler) canChangeCredential(ctx context.Context, projectID string) (bool, error) {
+	switch middleware.GetUserRole(ctx) {
+	case string(models.RoleAdmin):
+		return true, nil
+	case string(models.RoleLead):
+		return h.projectRepo.CanUserAccess(ctx, projectID, middleware.GetUserID(ctx))
+	default:
+		return false, nil
+	}
+}
+
 // DeleteProject deletes a project
-func DeleteProject(w http.ResponseWriter, r *http.Request) {
+func (h *ProjectsHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
 	projectID := strings.Split(path, "/")[0]
 
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
 
-	if err := projectRepo.Delete(ctx, projectID); err != nil {
+	if err := h.projectRepo.Delete(ctx, projectID); err != nil {
 		http.Error(w, "Failed to delete project", http.StatusInternalServerError)
 		return
 	}
@@ -198,15 +373,16 @@ func DeleteProject(w http.ResponseWriter, r *http.Request) {
 		Action:       "delete_project",
 		ResourceType: "project",
 		ResourceID:   projectID,
+		ProjectID:    projectID,
 		Status:       "success",
 	}
-	CreateAuditLogEntry(auditLog)
+	h.recordAudit(r.Context(), auditLog)
 
 	w.WriteHeader(http.StatusOK)
 }
 
 // UpdateProjectAccess updates who has access to a project
-func UpdateProjectAccess(w http.ResponseWriter, r *http.Request) {
+func (h *ProjectsHandler) UpdateProjectAccess(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
 	parts := strings.Split(path, "/")
@@ -223,21 +399,48 @@ func UpdateProjectAccess(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	projectRepo := &repositories.ProjectRepository{}
 
 	// Update access
-	if err := projectRepo.UpdateProjectAccess(ctx, projectID, request.TeamIDs, request.UserIDs); err != nil {
+	if err := h.projectRepo.UpdateProjectAccess(ctx, projectID, request.TeamIDs, request.UserIDs); err != nil {
 		http.Error(w, "Failed to update project access", http.StatusInternalServerError)
 		return
 	}
 
 	// Return updated project
-	project, err := projectRepo.FindByID(ctx, projectID)
+	project, err := h.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		http.Error(w, "Project not found", http.StatusNotFound)
 		return
 	}
 
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"team_ids": request.TeamIDs,
+		"user_ids": request.UserIDs,
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "update_project_access",
+		ResourceType: "project",
+		ResourceID:   project.ID,
+		ResourceName: project.Name,
+		ProjectID:    project.ID,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(project)
 }
+
+// resolveSecretID returns secretID, or the project's default credential when
+// secretID is empty. The result is empty if neither is set.
+func resolveSecretID(ctx context.Context, projectRepo *repositories.ProjectRepository, projectID, secretID string) (string, error) {
+	if secretID != "" || projectID == "" {
+		return secretID, nil
+	}
+	project, err := projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	return project.SecretID, nil
+}
@@ -3,37 +3,65 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/logging"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
 	"github.com/portalight/backend/internal/repositories"
 	"github.com/portalight/backend/internal/services"
 )
 
 type ProvisionHandler struct {
+	auditRecorder
 	resourceRepo           *repositories.ResourceRepository
+	projectRepo            *repositories.ProjectRepository
 	secretRepo             *repositories.SecretRepository
 	permissionRepo         *repositories.ProvisioningPermissionRepository
+	approvalRepo           *repositories.ProvisioningApprovalRepository
+	userRepo               *repositories.UserRepository
 	discoveredResourceRepo *repositories.DiscoveredResourceRepository
 	provisioner            *services.AWSProvisioner
+	limiter                *services.ProvisionLimiter
+	notifier               *services.UserNotifier
+	teamNotifier           *services.TeamNotifier
+	requireTicketURL       bool
 }
 
-func NewProvisionHandler(resourceRepo *repositories.ResourceRepository) *ProvisionHandler {
+func NewProvisionHandler(deps *Deps, limiter *services.ProvisionLimiter, notifier *services.UserNotifier, requireTicketURL bool) *ProvisionHandler {
 	return &ProvisionHandler{
-		resourceRepo:           resourceRepo,
-		secretRepo:             &repositories.SecretRepository{},
-		permissionRepo:         &repositories.ProvisioningPermissionRepository{},
-		discoveredResourceRepo: repositories.NewDiscoveredResourceRepository(),
-		provisioner:            services.NewAWSProvisioner(),
+		auditRecorder:          newAuditRecorder(deps),
+		resourceRepo:           deps.Resources,
+		projectRepo:            deps.Projects,
+		secretRepo:             deps.Secrets,
+		permissionRepo:         deps.ProvisioningPermissions,
+		approvalRepo:           deps.ProvisioningApprovals,
+		userRepo:               deps.Users,
+		discoveredResourceRepo: deps.DiscoveredResources,
+		provisioner:            deps.Provisioner,
+		limiter:                limiter,
+		notifier:               notifier,
+		teamNotifier:           deps.TeamNotifier,
+		requireTicketURL:       requireTicketURL,
 	}
 }
 
+// readOnlyCredentialMessage rejects changing AWS resources with a read-only credential
+const readOnlyCredentialMessage = "The AWS credential is read-only: it can discover and monitor resources but not create, change or delete them. Use a credential with write access."
+
 // ProvisionResource handles resource provisioning requests
 // Lead and superadmin can provision any resource
-// Dev users can only provision resources they have been granted access to
+// Dev users can only provision resources they have been granted access to, and
+// their requests wait in awaiting_approval until a lead approves them
+// Every request needs a reason, which approvers see on the resource
 func (h *ProvisionHandler) ProvisionResource(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateResourceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -41,15 +69,33 @@ func (h *ProvisionHandler) ProvisionResource(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Every request says why the resource is needed, before anything else is checked
+	if err := h.validateJustification(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Validate request
-	if req.ProjectID == "" || req.Name == "" || req.Type == "" || req.SecretID == "" {
-		http.Error(w, "Missing required fields: project_id, name, type, secret_id", http.StatusBadRequest)
+	if req.ProjectID == "" || req.Name == "" || req.Type == "" {
+		http.Error(w, "Missing required fields: project_id, name, type", http.StatusBadRequest)
+		return
+	}
+
+	// Fall back to the project's default credential
+	secretID, err := resolveSecretID(r.Context(), h.projectRepo, req.ProjectID, req.SecretID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if secretID == "" {
+		http.Error(w, "secret_id is required: the project has no default credential", http.StatusBadRequest)
 		return
 	}
+	req.SecretID = secretID
 
 	// Validate resource type
-	if req.Type != "s3" && req.Type != "sqs" && req.Type != "sns" {
-		http.Error(w, "Invalid resource type. Supported types: s3, sqs, sns", http.StatusBadRequest)
+	if _, ok := services.LookupProvisionerType(req.Type); !ok {
+		http.Error(w, "Invalid resource type. Supported types: "+strings.Join(services.ProvisionableTypes(), ", "), http.StatusBadRequest)
 		return
 	}
 
@@ -58,46 +104,61 @@ func (h *ProvisionHandler) ProvisionResource(w http.ResponseWriter, r *http.Requ
 	userID := middleware.GetUserID(r.Context())
 
 	if userRole == "dev" {
-		// Dev users need explicit permission for the resource type
-		canProvision, err := h.permissionRepo.CanUserProvision(r.Context(), userID, req.Type)
+		// Dev users need explicit permission for the resource type, project and credential
+		canProvision, err := h.permissionRepo.CanUserProvision(r.Context(), userID, req.Type, req.ProjectID, req.SecretID)
 		if err != nil {
-			log.Printf("Failed to check provisioning permissions: %v", err)
+			logging.FromContext(r.Context()).Error("Failed to check provisioning permissions", slog.Any("error", err))
 			http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
 			return
 		}
 		if !canProvision {
-			http.Error(w, "Forbidden: You don't have permission to provision "+req.Type+" resources", http.StatusForbidden)
+			http.Error(w, "Forbidden: You don't have permission to provision "+req.Type+" resources in this project with this credential", http.StatusForbidden)
 			return
 		}
 	}
 
-	// Create resource in DB with "provisioning" status
+	// Get AWS credentials; a read-only credential would only fail deep inside AWS
+	credentials, accessType, err := h.secretRepo.GetCredentials(r.Context(), req.SecretID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Failed to get credentials", slog.Any("error", err))
+		http.Error(w, "Failed to retrieve AWS credentials", http.StatusInternalServerError)
+		return
+	}
+	if !accessType.CanWrite() {
+		http.Error(w, readOnlyCredentialMessage, http.StatusBadRequest)
+		return
+	}
+
+	// Create resource in DB with "provisioning" status; a dev's request waits for approval
+	status := models.ProvisionStatusProvisioning
+	if userRole == "dev" {
+		status = models.ProvisionStatusAwaitingApproval
+	}
 	resource := &models.Resource{
 		ProjectID: req.ProjectID,
 		Name:      req.Name,
 		Type:      req.Type,
-		Status:    "provisioning",
+		Status:    status,
 		Config:    req.Config,
+		Reason:    req.Reason,
+		TicketURL: req.TicketURL,
+		ManagedBy: models.ManagedByPortalight,
 	}
 
 	if err := h.resourceRepo.Create(r.Context(), resource); err != nil {
-		log.Printf("Failed to create resource: %v", err)
+		logging.FromContext(r.Context()).Error("Failed to create resource", slog.Any("error", err))
 		http.Error(w, "Failed to create resource", http.StatusInternalServerError)
 		return
 	}
 
-	// Get AWS credentials
-	credentials, err := h.secretRepo.GetCredentials(r.Context(), req.SecretID)
-	if err != nil {
-		log.Printf("Failed to get credentials: %v", err)
-		h.resourceRepo.UpdateStatusWithError(r.Context(), resource.ID, "failed", "Failed to retrieve AWS credentials")
-		http.Error(w, "Failed to retrieve AWS credentials", http.StatusInternalServerError)
+	userEmail := middleware.GetUserEmail(r.Context())
+	if status == models.ProvisionStatusAwaitingApproval {
+		h.requestApproval(w, r, resource, req)
 		return
 	}
 
-	// Provision asynchronously
-	userEmail := middleware.GetUserEmail(r.Context())
-	go h.provisionAsync(resource.ID, req, credentials, userEmail)
+	// Provision asynchronously; the detached context keeps the request's logger
+	go h.provisionAsync(logging.Detach(r.Context()), resource.ID, req, credentials, userEmail, h.provisionTags(r.Context(), req.ProjectID))
 
 	// Audit Log - initial request
 	auditLog := models.AuditLog{
@@ -105,105 +166,560 @@ func (h *ProvisionHandler) ProvisionResource(w http.ResponseWriter, r *http.Requ
 		Action:       "provision_resource",
 		ResourceType: req.Type,
 		ResourceName: req.Name,
+		ProjectID:    req.ProjectID,
+		Status:       "pending",
+		Details:      provisionAuditDetails(req),
+	}
+	h.recordAudit(r.Context(), auditLog)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resource)
+}
+
+// validateJustification trims the request's reason and ticket URL and checks them.
+// The ticket URL is optional unless REQUIRE_TICKET_URL is set.
+func (h *ProvisionHandler) validateJustification(req *models.CreateResourceRequest) error {
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.TicketURL = strings.TrimSpace(req.TicketURL)
+
+	if req.Reason == "" {
+		return errors.New("reason is required: say why the resource is needed")
+	}
+	if req.TicketURL == "" {
+		if h.requireTicketURL {
+			return errors.New("ticket_url is required")
+		}
+		return nil
+	}
+	u, err := url.Parse(req.TicketURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("ticket_url must be an http(s) URL")
+	}
+	return nil
+}
+
+// provisionAuditDetails is the audit log entry of a provisioning request: its reason,
+// ticket and configuration as JSON, with sensitive configuration keys masked
+func provisionAuditDetails(req models.CreateResourceRequest) string {
+	return redact.JSON(struct {
+		Reason    string          `json:"reason"`
+		TicketURL string          `json:"ticket_url,omitempty"`
+		Config    json.RawMessage `json:"config,omitempty"`
+	}{req.Reason, req.TicketURL, req.Config})
+}
+
+// requestApproval records a dev's provisioning request for a lead to approve
+func (h *ProvisionHandler) requestApproval(w http.ResponseWriter, r *http.Request, resource *models.Resource, req models.CreateResourceRequest) {
+	ctx := r.Context()
+	approval := &models.ProvisioningApproval{
+		ResourceID:  resource.ID,
+		SecretID:    req.SecretID,
+		RequestedBy: middleware.GetUserID(ctx),
+	}
+	if err := h.approvalRepo.Create(ctx, approval); err != nil {
+		logging.FromContext(ctx).Error("Failed to request provisioning approval", slog.Any("error", err))
+		h.resourceRepo.TransitionStatus(ctx, resource.ID, models.ProvisionStatusAwaitingApproval, models.ProvisionStatusFailed, repositories.StatusDetails{ErrorMessage: "Failed to request approval"})
+		http.Error(w, "Failed to request approval", http.StatusInternalServerError)
+		return
+	}
+
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "request_provisioning",
+		ResourceType: req.Type,
+		ResourceID:   resource.ID,
+		ResourceName: req.Name,
+		ProjectID:    req.ProjectID,
 		Status:       "pending",
-		Details:      string(req.Config),
+		Details:      provisionAuditDetails(req),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resource)
+}
+
+// ApproveProvisioning handles POST /api/v1/provision/{resourceID}/approve: it starts
+// provisioning a resource a dev requested
+func (h *ProvisionHandler) ApproveProvisioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resource, approval, ok := h.pendingApproval(w, r)
+	if !ok {
+		return
 	}
-	CreateAuditLogEntry(auditLog)
+
+	// The credential may have been changed to read-only since the request
+	credentials, accessType, err := h.secretRepo.GetCredentials(ctx, approval.SecretID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get credentials", slog.Any("error", err))
+		http.Error(w, "Failed to retrieve AWS credentials", http.StatusInternalServerError)
+		return
+	}
+	if !accessType.CanWrite() {
+		http.Error(w, readOnlyCredentialMessage, http.StatusBadRequest)
+		return
+	}
+
+	if !h.decide(w, r, resource, approval, models.ApprovalStatusApproved, "", models.ProvisionStatusProvisioning, repositories.StatusDetails{}) {
+		return
+	}
+	resource.Status = models.ProvisionStatusProvisioning
+
+	// Provision as the requester, who is notified of the result
+	req := models.CreateResourceRequest{
+		ProjectID: resource.ProjectID,
+		SecretID:  approval.SecretID,
+		Name:      resource.Name,
+		Type:      resource.Type,
+		Config:    resource.Config,
+		Reason:    resource.Reason,
+		TicketURL: resource.TicketURL,
+	}
+	requesterEmail := h.requesterEmail(ctx, approval)
+	go h.provisionAsync(logging.Detach(ctx), resource.ID, req, credentials, requesterEmail, h.provisionTags(ctx, resource.ProjectID))
+
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "approve_provisioning",
+		ResourceType: resource.Type,
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ProjectID:    resource.ProjectID,
+		Status:       "success",
+		Details:      "Requested by " + requesterEmail,
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(resource)
 }
 
+// RejectProvisioning handles POST /api/v1/provision/{resourceID}/reject: the
+// resource moves to failed with the reason, which the requester is sent
+func (h *ProvisionHandler) RejectProvisioning(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var body models.RejectProvisioningRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	body.Reason = strings.TrimSpace(body.Reason)
+	if body.Reason == "" {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	resource, approval, ok := h.pendingApproval(w, r)
+	if !ok {
+		return
+	}
+
+	failure := "Rejected: " + body.Reason
+	if !h.decide(w, r, resource, approval, models.ApprovalStatusRejected, body.Reason, models.ProvisionStatusFailed, repositories.StatusDetails{ErrorMessage: failure}) {
+		return
+	}
+
+	req := models.CreateResourceRequest{
+		ProjectID: resource.ProjectID,
+		SecretID:  approval.SecretID,
+		Name:      resource.Name,
+		Type:      resource.Type,
+		Config:    resource.Config,
+		Reason:    resource.Reason,
+		TicketURL: resource.TicketURL,
+	}
+	go h.reportProvisioningResult(h.requesterEmail(ctx, approval), req, nil, failure)
+
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "reject_provisioning",
+		ResourceType: resource.Type,
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ProjectID:    resource.ProjectID,
+		Status:       "success",
+		Details:      body.Reason,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approval)
+}
+
+// pendingApproval loads the resource of the request path and its pending approval,
+// writing the error response if there is none the caller may decide
+func (h *ProvisionHandler) pendingApproval(w http.ResponseWriter, r *http.Request) (*models.Resource, *models.ProvisioningApproval, bool) {
+	ctx := r.Context()
+	resource, err := h.resourceRepo.FindByID(ctx, r.PathValue("resourceID"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get resource", slog.Any("error", err))
+		http.Error(w, "Failed to get resource", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+
+	// Leads only decide requests of projects they can access
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, resource.ProjectID, middleware.GetUserID(ctx))
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to check project access", slog.Any("error", err))
+			http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+			return nil, nil, false
+		}
+		if !allowed {
+			http.Error(w, "Resource not found", http.StatusNotFound)
+			return nil, nil, false
+		}
+	}
+
+	approval, err := h.approvalRepo.FindByResourceID(ctx, resource.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Resource has no provisioning request to approve", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get provisioning approval", slog.Any("error", err))
+		http.Error(w, "Failed to get provisioning request", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if approval.Status != models.ApprovalStatusPending {
+		http.Error(w, "Provisioning request was already "+string(approval.Status), http.StatusConflict)
+		return nil, nil, false
+	}
+	return resource, approval, true
+}
+
+// decide records a lead's decision on a pending approval and moves the resource out of
+// awaiting_approval in one transaction, so a failed transition leaves the request open
+// to be decided again. It writes the error response and returns false on failure.
+func (h *ProvisionHandler) decide(w http.ResponseWriter, r *http.Request, resource *models.Resource, approval *models.ProvisioningApproval, decision models.ApprovalStatus, reason string, to models.ProvisionStatus, details repositories.StatusDetails) bool {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx)
+
+	tx, err := repositories.BeginTx(ctx)
+	if err != nil {
+		logger.Error("Failed to begin approval transaction", slog.Any("error", err))
+		http.Error(w, "Failed to update provisioning request", http.StatusInternalServerError)
+		return false
+	}
+	defer tx.Rollback(ctx)
+
+	if err := h.approvalRepo.WithQuerier(tx).Decide(ctx, approval, decision, middleware.GetUserID(ctx), reason); err != nil {
+		writeDecideError(ctx, w, err)
+		return false
+	}
+	err = h.resourceRepo.WithQuerier(tx).TransitionStatus(ctx, resource.ID, models.ProvisionStatusAwaitingApproval, to, details)
+	var conflict *repositories.StatusConflictError
+	if errors.As(err, &conflict) {
+		http.Error(w, "Resource is no longer awaiting approval", http.StatusConflict)
+		return false
+	}
+	if err != nil {
+		logger.Error("Failed to update resource status", slog.Any("error", err))
+		http.Error(w, "Failed to update resource", http.StatusInternalServerError)
+		return false
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit provisioning decision", slog.Any("error", err))
+		http.Error(w, "Failed to update provisioning request", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// writeDecideError writes the response for a failed approval decision
+func writeDecideError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, repositories.ErrApprovalDecided) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	logging.FromContext(ctx).Error("Failed to decide provisioning request", slog.Any("error", err))
+	http.Error(w, "Failed to update provisioning request", http.StatusInternalServerError)
+}
+
+// requesterEmail returns the email of the dev who requested a provisioning, or
+// empty if the user was deleted
+func (h *ProvisionHandler) requesterEmail(ctx context.Context, approval *models.ProvisioningApproval) string {
+	if approval.RequestedBy == "" {
+		return ""
+	}
+	user, err := h.userRepo.FindByID(ctx, approval.RequestedBy)
+	if err != nil {
+		return ""
+	}
+	return user.Email
+}
+
+// provisionTags tags a resource with its owning project so it can be traced back from AWS
+func (h *ProvisionHandler) provisionTags(ctx context.Context, projectID string) map[string]string {
+	projectName := ""
+	if project, err := h.projectRepo.FindByID(ctx, projectID); err == nil {
+		projectName = project.Name
+	}
+	return services.PortalightTags(projectID, projectName)
+}
+
+// markFailed moves a resource that is still provisioning to failed
+func (h *ProvisionHandler) markFailed(ctx context.Context, resourceID string, reason string) {
+	err := h.resourceRepo.TransitionStatus(ctx, resourceID, models.ProvisionStatusProvisioning, models.ProvisionStatusFailed, repositories.StatusDetails{ErrorMessage: reason})
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to mark resource as failed", slog.Any("error", err))
+	}
+}
+
 // provisionAsync handles the actual AWS provisioning in the background
-func (h *ProvisionHandler) provisionAsync(resourceID string, req models.CreateResourceRequest, creds *models.AWSCredentials, userEmail string) {
-	ctx := context.Background()
+func (h *ProvisionHandler) provisionAsync(ctx context.Context, resourceID string, req models.CreateResourceRequest, creds *models.AWSCredentials, userEmail string, tags map[string]string) {
+	ctx = logging.With(ctx, slog.String("resource_id", resourceID), slog.String("resource_type", req.Type), slog.String("resource_name", req.Name))
+	logger := logging.FromContext(ctx)
 	var result *models.ProvisionResult
 	var err error
 
+	// Wait for a global provisioning slot, surfacing the queue position as the resource stage
+	release, err := h.limiter.Acquire(ctx, func(ahead int) {
+		if err := h.resourceRepo.UpdateStage(ctx, resourceID, fmt.Sprintf("queued (%d ahead)", ahead)); err != nil {
+			logger.Warn("Failed to update resource stage", slog.Any("error", err))
+		}
+	})
+	if err != nil {
+		logger.Error("Provisioning not started", slog.Any("error", err))
+		h.resourceRepo.UpdateStage(ctx, resourceID, "")
+		h.markFailed(ctx, resourceID, err.Error())
+		h.reportProvisioningResult(userEmail, req, nil, err.Error())
+		return
+	}
+	defer release()
+
+	if err := h.resourceRepo.UpdateStage(ctx, resourceID, "provisioning"); err != nil {
+		logger.Warn("Failed to update resource stage", slog.Any("error", err))
+	}
+	defer h.resourceRepo.UpdateStage(ctx, resourceID, "")
+
 	switch req.Type {
 	case "s3":
 		var config models.S3Config
 		if err := json.Unmarshal(req.Config, &config); err != nil {
-			log.Printf("Failed to parse S3 config: %v", err)
-			h.resourceRepo.UpdateStatusWithError(ctx, resourceID, "failed", "Invalid S3 configuration")
-			h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "failed", "Invalid S3 configuration")
+			logger.Error("Failed to parse S3 config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid S3 configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid S3 configuration")
 			return
 		}
-		result, err = h.provisioner.ProvisionS3(ctx, req.Name, config, creds)
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionS3(ctx, req.Name, config, creds, userEmail)
 
 	case "sqs":
 		var config models.SQSConfig
 		if err := json.Unmarshal(req.Config, &config); err != nil {
-			log.Printf("Failed to parse SQS config: %v", err)
-			h.resourceRepo.UpdateStatusWithError(ctx, resourceID, "failed", "Invalid SQS configuration")
-			h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "failed", "Invalid SQS configuration")
+			logger.Error("Failed to parse SQS config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid SQS configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid SQS configuration")
 			return
 		}
-		result, err = h.provisioner.ProvisionSQS(ctx, req.Name, config, creds)
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionSQS(ctx, req.Name, config, creds, userEmail)
 
 	case "sns":
 		var config models.SNSConfig
 		if err := json.Unmarshal(req.Config, &config); err != nil {
-			log.Printf("Failed to parse SNS config: %v", err)
-			h.resourceRepo.UpdateStatusWithError(ctx, resourceID, "failed", "Invalid SNS configuration")
-			h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "failed", "Invalid SNS configuration")
+			logger.Error("Failed to parse SNS config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid SNS configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid SNS configuration")
 			return
 		}
-		result, err = h.provisioner.ProvisionSNS(ctx, req.Name, config, creds)
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionSNS(ctx, req.Name, config, creds, userEmail)
+
+	case "dynamodb":
+		var config models.DynamoDBConfig
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			logger.Error("Failed to parse DynamoDB config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid DynamoDB configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid DynamoDB configuration")
+			return
+		}
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionDynamoDB(ctx, req.Name, config, creds, userEmail)
+
+	case "elasticache":
+		var config models.ElastiCacheConfig
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			logger.Error("Failed to parse ElastiCache config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid ElastiCache configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid ElastiCache configuration")
+			return
+		}
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionElastiCache(ctx, req.Name, config, creds, userEmail)
+
+	case "lambda":
+		var config models.LambdaConfig
+		if err := json.Unmarshal(req.Config, &config); err != nil {
+			logger.Error("Failed to parse Lambda config", slog.Any("error", err))
+			h.markFailed(ctx, resourceID, "Invalid Lambda configuration")
+			h.reportProvisioningResult(userEmail, req, nil, "Invalid Lambda configuration")
+			return
+		}
+		config.Tags = services.MergeTags(config.Tags, tags)
+		result, err = h.provisioner.ProvisionLambda(ctx, req.Name, config, creds, userEmail)
 	}
 
 	if err != nil {
-		log.Printf("Provisioning error: %v", err)
-		h.resourceRepo.UpdateStatusWithError(ctx, resourceID, "failed", err.Error())
-		h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "failed", err.Error())
+		logger.Error("Provisioning error", slog.Any("error", err))
+		h.markFailed(ctx, resourceID, err.Error())
+		h.reportProvisioningResult(userEmail, req, nil, err.Error())
 		return
 	}
 
 	if result != nil && !result.Success {
-		log.Printf("Provisioning failed: %s", result.Error)
-		h.resourceRepo.UpdateStatusWithError(ctx, resourceID, "failed", result.Error)
-		h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "failed", result.Error)
+		logger.Error("Provisioning failed", slog.String("error", result.Error))
+		h.markFailed(ctx, resourceID, result.Error)
+		h.reportProvisioningResult(userEmail, req, nil, result.Error)
 		return
 	}
 
 	// Update status to active with ARN
-	if err := h.resourceRepo.UpdateStatusWithARN(ctx, resourceID, "active", result.ARN); err != nil {
-		log.Printf("Failed to update resource status: %v", err)
+	err = h.resourceRepo.TransitionStatus(ctx, resourceID, models.ProvisionStatusProvisioning, models.ProvisionStatusActive, repositories.StatusDetails{ARN: result.ARN})
+	if err != nil {
+		logger.Error("Failed to update resource status", slog.Any("error", err))
 	} else {
-		log.Printf("Resource %s provisioned successfully! ARN: %s", resourceID, result.ARN)
-		h.createProvisioningAuditLog(userEmail, req.Type, req.Name, "success", "ARN: "+result.ARN)
+		logger.Info("Resource provisioned successfully", slog.String("arn", result.ARN))
+		for _, warning := range result.Warnings {
+			logger.Warn("Provisioning warning", slog.String("warning", warning))
+		}
+		h.reportProvisioningResult(userEmail, req, result, "")
 
-		// Auto-add provisioned resource to discovered_resources so it appears in Cloud Resources
+		// Auto-add provisioned resource to discovered_resources so it appears in Cloud Resources.
+		// The link keeps both statuses in step.
 		discoveredResource := &models.DiscoveredResource{
 			ProjectID:    req.ProjectID,
 			SecretID:     req.SecretID,
+			ResourceID:   resourceID,
 			ARN:          result.ARN,
 			ResourceType: req.Type,
 			Name:         req.Name,
 			Region:       result.Region,
 			Status:       models.ResourceStatusActive,
-			Metadata:     req.Config,
+			Metadata:     provisionedMetadata(req.Config, result.Tags, result.Endpoint),
 		}
 		if err := h.discoveredResourceRepo.Create(ctx, discoveredResource); err != nil {
-			log.Printf("Failed to add provisioned resource to discovered_resources: %v", err)
+			logger.Error("Failed to add provisioned resource to discovered_resources", slog.Any("error", err))
 		} else {
-			log.Printf("Provisioned resource %s auto-added to discovered_resources", req.Name)
+			logger.Info("Provisioned resource auto-added to discovered_resources")
+		}
+
+		// Companion resources (e.g. an SQS dead-letter queue) are listed on their own and
+		// not linked, so losing one does not mark the primary resource deleted
+		for role, arn := range result.SecondaryARNs {
+			metadata, _ := json.Marshal(map[string]string{"role": role, "primary_arn": result.ARN})
+			secondary := &models.DiscoveredResource{
+				ProjectID:    req.ProjectID,
+				SecretID:     req.SecretID,
+				ARN:          arn,
+				ResourceType: req.Type,
+				Name:         arn[strings.LastIndex(arn, ":")+1:],
+				Region:       result.Region,
+				Status:       models.ResourceStatusActive,
+				Metadata:     provisionedMetadata(metadata, result.Tags, ""),
+			}
+			if err := h.discoveredResourceRepo.Create(ctx, secondary); err != nil {
+				logger.Error("Failed to add companion resource to discovered_resources", slog.String("role", role), slog.Any("error", err))
+			}
 		}
 	}
 }
 
-// createProvisioningAuditLog creates an audit log entry for provisioning result
-func (h *ProvisionHandler) createProvisioningAuditLog(userEmail, resourceType, resourceName, status, details string) {
-	auditLog := models.AuditLog{
+// provisionedMetadata stores the applied tags and the endpoint (if any) in the resource
+// config so the discovery view shows them
+func provisionedMetadata(config json.RawMessage, tags map[string]string, endpoint string) json.RawMessage {
+	if len(tags) == 0 && endpoint == "" {
+		return config
+	}
+
+	metadata := map[string]interface{}{}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &metadata); err != nil {
+			return config
+		}
+	}
+	if len(tags) > 0 {
+		metadata["tags"] = tags
+	}
+	if endpoint != "" {
+		metadata["endpoint"] = endpoint
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return config
+	}
+	return data
+}
+
+// reportProvisioningResult audits the outcome of provisioning, emails the requester and
+// posts it to the project's team channels.
+// result is nil if provisioning failed, with the reason in failure.
+func (h *ProvisionHandler) reportProvisioningResult(userEmail string, req models.CreateResourceRequest, result *models.ProvisionResult, failure string) {
+	status, details := "failed", failure
+	email := services.ProvisioningEmail{
+		ResourceName: req.Name,
+		ResourceType: req.Type,
+		Error:        failure,
+	}
+	if result != nil {
+		status, details = "success", "ARN: "+result.ARN
+		email.ARN = result.ARN
+		email.Details = append(email.Details, result.Details...)
+		for _, detail := range result.Details {
+			details += "; " + detail
+		}
+		for _, warning := range result.Warnings {
+			details += "; warning: " + warning
+			email.Details = append(email.Details, "Warning: "+warning)
+		}
+	}
+
+	// Runs after the request has returned, so it is not part of the request's audit entry
+	h.CreateAuditLogEntry(models.AuditLog{
 		UserEmail:    userEmail,
 		Action:       "provision_resource_complete",
-		ResourceType: resourceType,
-		ResourceName: resourceName,
+		ResourceType: req.Type,
+		ResourceName: req.Name,
+		ProjectID:    req.ProjectID,
 		Status:       status,
 		Details:      details,
+	})
+
+	event := models.TeamEvent{
+		Event:        models.NotificationProvisioningFailed,
+		ProjectID:    req.ProjectID,
+		ResourceType: req.Type,
+		ResourceName: req.Name,
+		Actor:        userEmail,
+		Error:        failure,
+	}
+	if result != nil {
+		event.Event = models.NotificationProvisioningSucceeded
+		event.ARN = result.ARN
+	}
+	h.teamNotifier.Notify(event)
+
+	if userEmail == "" {
+		return
+	}
+	email.ProjectName = req.ProjectID
+	if project, err := h.projectRepo.FindByID(context.Background(), req.ProjectID); err == nil {
+		email.ProjectName = project.Name
+	}
+	if result != nil {
+		h.notifier.Notify(models.NotificationProvisioningSucceeded, []string{userEmail},
+			fmt.Sprintf("%s %s is ready", req.Type, req.Name), email)
+	} else {
+		h.notifier.Notify(models.NotificationProvisioningFailed, []string{userEmail},
+			fmt.Sprintf("Provisioning of %s %s failed", req.Type, req.Name), email)
 	}
-	CreateAuditLogEntry(auditLog)
 }
 
 // GetProjectResources returns all resources for a project
@@ -233,3 +749,297 @@ func (h *ProvisionHandler) GetProjectResources(w http.ResponseWriter, r *http.Re
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resources)
 }
+
+// resourceListResponse is a page of resources with the per-status counts of every
+// resource the caller can see, for the dashboard header
+type resourceListResponse struct {
+	ListResponse
+	StatusCounts map[models.ProvisionStatus]int `json:"status_counts"`
+}
+
+// ListResources handles GET /api/v1/resources?status=failed&type=sqs&limit=50 across
+// projects. Superadmins see every resource, leads those of projects they can access
+// and devs those of projects owned by one of their teams.
+func (h *ProvisionHandler) ListResources(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := repositories.ResourceFilter{
+		Status: models.ProvisionStatus(query.Get("status")),
+		Type:   query.Get("type"),
+	}
+	switch filter.Status {
+	case "", models.ProvisionStatusAwaitingApproval, models.ProvisionStatusProvisioning, models.ProvisionStatusActive, models.ProvisionStatusFailed, models.ProvisionStatusDeleted:
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	role := middleware.GetUserRole(r.Context())
+	userID := middleware.GetUserID(r.Context())
+	if role != string(models.RoleAdmin) && userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch role {
+	case string(models.RoleAdmin):
+	case string(models.RoleLead):
+		filter.AccessibleBy = userID
+	default:
+		filter.OwnedBy = userID
+	}
+
+	resources, total, err := h.resourceRepo.GetAll(r.Context(), filter, opts)
+	if err != nil {
+		log.Printf("Failed to list resources: %v", err)
+		writeList(w, nil, 0, err, "Failed to list resources")
+		return
+	}
+	counts, err := h.resourceRepo.CountByStatus(r.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to count resources: %v", err)
+		http.Error(w, "Failed to list resources", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resourceListResponse{
+		ListResponse: ListResponse{Items: resources, Total: total},
+		StatusCounts: counts,
+	})
+}
+
+// GetResource handles GET /api/v1/resources/{id}, e.g. to poll a provisioning request
+func (h *ProvisionHandler) GetResource(w http.ResponseWriter, r *http.Request) {
+	resource, err := h.resourceRepo.FindByID(r.Context(), r.PathValue("id"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get resource: %v", err)
+		http.Error(w, "Failed to get resource", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resource)
+}
+
+// DeprovisionResource handles DELETE /api/v1/resources/{resourceID}
+// Only lead and superadmin can delete provisioned resources
+func (h *ProvisionHandler) DeprovisionResource(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+	if userRole != "lead" && userRole != "superadmin" {
+		http.Error(w, "Forbidden: Only leads and superadmins can delete resources", http.StatusForbidden)
+		return
+	}
+
+	resourceID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/resources/"), "/")
+	if resourceID == "" || strings.Contains(resourceID, "/") {
+		http.Error(w, "Resource ID required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	resource, err := h.resourceRepo.FindByID(ctx, resourceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "Resource not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get resource: %v", err)
+		http.Error(w, "Failed to get resource", http.StatusInternalServerError)
+		return
+	}
+
+	switch resource.Status {
+	case models.ProvisionStatusDeleted:
+		http.Error(w, "Resource is already deleted", http.StatusConflict)
+		return
+	case models.ProvisionStatusProvisioning:
+		http.Error(w, "Resource is still being provisioned", http.StatusConflict)
+		return
+	}
+	if resource.ARN == "" {
+		http.Error(w, "Resource was never provisioned in AWS", http.StatusConflict)
+		return
+	}
+	// Resources taken over by Terraform or managed by hand are changed there, not here
+	if resource.ManagedBy != models.ManagedByPortalight {
+		http.Error(w, fmt.Sprintf("Resource is managed by %s; delete it there", resource.ManagedBy), http.StatusConflict)
+		return
+	}
+
+	// The credential used for provisioning is recorded on the discovered resource;
+	// fall back to the project's default credential
+	var secretID, region string
+	discovered, err := h.discoveredResourceRepo.GetByARN(ctx, resource.ProjectID, resource.ARN)
+	if err == nil {
+		secretID, region = discovered.SecretID, discovered.Region
+	} else if project, err := h.projectRepo.FindByID(ctx, resource.ProjectID); err == nil {
+		secretID = project.SecretID
+	}
+	if secretID == "" {
+		http.Error(w, "No AWS credential found for this resource", http.StatusUnprocessableEntity)
+		return
+	}
+
+	var config struct {
+		Region string `json:"region"`
+	}
+	if err := json.Unmarshal(resource.Config, &config); err == nil && config.Region != "" {
+		region = config.Region
+	}
+
+	credentials, accessType, err := h.secretRepo.GetCredentials(ctx, secretID)
+	if err != nil {
+		log.Printf("Failed to get credentials: %v", err)
+		http.Error(w, "Failed to retrieve AWS credentials", http.StatusInternalServerError)
+		return
+	}
+	if !accessType.CanWrite() {
+		http.Error(w, readOnlyCredentialMessage, http.StatusBadRequest)
+		return
+	}
+
+	var result *models.ProvisionResult
+	switch resource.Type {
+	case "s3":
+		result, err = h.provisioner.DeprovisionS3(ctx, resource.Name, region, credentials)
+	case "sqs":
+		// The queue name is the last ARN segment (includes the .fifo suffix)
+		queueName := resource.ARN[strings.LastIndex(resource.ARN, ":")+1:]
+		result, err = h.provisioner.DeprovisionSQS(ctx, queueName, region, credentials)
+	case "sns":
+		result, err = h.provisioner.DeprovisionSNS(ctx, resource.ARN, region, credentials)
+	case "elasticache":
+		result, err = h.provisioner.DeprovisionElastiCache(ctx, resource.ARN, region, credentials)
+	default:
+		http.Error(w, "Deprovisioning is not supported for resource type: "+resource.Type, http.StatusBadRequest)
+		return
+	}
+
+	userEmail := middleware.GetUserEmail(ctx)
+	if err == nil && !result.Success {
+		err = errors.New(result.Error)
+	}
+	if err != nil {
+		log.Printf("Deprovisioning error: %v", err)
+		h.recordAudit(r.Context(), models.AuditLog{
+			UserEmail:    userEmail,
+			Action:       "deprovision_resource",
+			ResourceType: resource.Type,
+			ResourceID:   resource.ID,
+			ResourceName: resource.Name,
+			ProjectID:    resource.ProjectID,
+			Status:       "failed",
+			Details:      err.Error(),
+		})
+		http.Error(w, "Failed to delete resource: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if err := h.resourceRepo.TransitionStatus(ctx, resource.ID, resource.Status, models.ProvisionStatusDeleted, repositories.StatusDetails{}); err != nil {
+		log.Printf("Failed to update resource status: %v", err)
+	}
+	if discovered != nil {
+		if err := h.discoveredResourceRepo.Delete(ctx, discovered.ID); err != nil {
+			log.Printf("Failed to remove deprovisioned resource from discovered_resources: %v", err)
+		}
+	}
+
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    userEmail,
+		Action:       "deprovision_resource",
+		ResourceType: resource.Type,
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ProjectID:    resource.ProjectID,
+		Status:       "success",
+		Details:      "ARN: " + resource.ARN,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ProvisionTypeInfo describes a provisionable resource type for the current user
+type ProvisionTypeInfo struct {
+	Type         string `json:"type"`
+	DisplayName  string `json:"display_name"`
+	Description  string `json:"description"`
+	CanProvision bool   `json:"can_provision"`
+	Scoped       bool   `json:"scoped"`     // Only allowed for specific projects/credentials
+	SchemaURL    string `json:"schema_url"` // Option metadata for the config form
+}
+
+// GetProvisionTypes handles GET /api/v1/provision/types
+func (h *ProvisionHandler) GetProvisionTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userRole := middleware.GetUserRole(r.Context())
+
+	// Dev users need explicit permissions; leads and superadmins can provision everything
+	globalTypes := map[string]bool{}
+	scopedTypes := map[string]bool{}
+	if userRole == "dev" {
+		permissions, err := h.permissionRepo.GetUserPermissions(r.Context(), middleware.GetUserID(r.Context()))
+		if err != nil {
+			log.Printf("Failed to get provisioning permissions: %v", err)
+			http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+			return
+		}
+		for _, t := range permissions.AllowedTypes {
+			globalTypes[t] = true
+		}
+		for _, perm := range permissions.Scoped {
+			scopedTypes[perm.ResourceType] = true
+		}
+	}
+
+	types := []ProvisionTypeInfo{}
+	for _, t := range services.ProvisionerTypes() {
+		info := ProvisionTypeInfo{
+			Type:         t.Type,
+			DisplayName:  t.DisplayName,
+			Description:  t.Description,
+			CanProvision: userRole == "superadmin" || userRole == "lead" || globalTypes[t.Type] || scopedTypes[t.Type],
+			Scoped:       userRole == "dev" && !globalTypes[t.Type] && scopedTypes[t.Type],
+			SchemaURL:    "/api/v1/provision/types/" + t.Type,
+		}
+		types = append(types, info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types)
+}
+
+// GetProvisionTypeSchema handles GET /api/v1/provision/types/{type}
+func (h *ProvisionHandler) GetProvisionTypeSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	typeName := strings.TrimPrefix(r.URL.Path, "/api/v1/provision/types/")
+	t, ok := services.LookupProvisionerType(typeName)
+	if !ok {
+		http.Error(w, "Unknown resource type", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -14,17 +16,25 @@ import (
 
 // ResourceDetailsHandler handles resource details and metrics endpoints
 type ResourceDetailsHandler struct {
+	auditRecorder
 	metrics      *services.AWSMetrics
+	provisioner  *services.AWSProvisioner
 	secretRepo   *repositories.SecretRepository
 	resourceRepo *repositories.DiscoveredResourceRepository
+	projectRepo  *repositories.ProjectRepository
+	metricsCache *metricsCache // Shields CloudWatch from repeated page views
 }
 
 // NewResourceDetailsHandler creates a new resource details handler
-func NewResourceDetailsHandler() *ResourceDetailsHandler {
+func NewResourceDetailsHandler(deps *Deps) *ResourceDetailsHandler {
 	return &ResourceDetailsHandler{
-		metrics:      services.NewAWSMetrics(),
-		secretRepo:   &repositories.SecretRepository{},
-		resourceRepo: repositories.NewDiscoveredResourceRepository(),
+		auditRecorder: newAuditRecorder(deps),
+		metrics:       deps.Metrics,
+		provisioner:   deps.Provisioner,
+		secretRepo:    deps.Secrets,
+		resourceRepo:  deps.DiscoveredResources,
+		projectRepo:   deps.Projects,
+		metricsCache:  newMetricsCache(metricsCacheSize, metricsCacheTTLs),
 	}
 }
 
@@ -68,21 +78,129 @@ func (h *ResourceDetailsHandler) GetResourceByID(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(resource)
 }
 
+// Limits on discovered resource annotations
+const (
+	maxResourceNotesLength      = 2000
+	maxResourceOwnerLabelLength = 255
+)
+
+// UpdateDiscoveredResource handles PATCH /api/v1/resources/discovered/{id}: it sets
+// the notes, owner label and manager of a discovered resource. The manager is copied
+// to the provisioned resource it tracks. Leads need access to its project.
+func (h *ResourceDetailsHandler) UpdateDiscoveredResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.UpdateDiscoveredResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Notes == nil && req.OwnerLabel == nil && req.ManagedBy == nil {
+		http.Error(w, "notes, owner_label or managed_by is required", http.StatusBadRequest)
+		return
+	}
+	if req.ManagedBy != nil && !models.IsValidManagedBy(*req.ManagedBy) {
+		http.Error(w, "managed_by must be portalight, terraform or manual", http.StatusBadRequest)
+		return
+	}
+
+	resource, err := h.resourceRepo.FindByID(ctx, r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, resource.ProjectID, middleware.GetUserID(ctx))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing resource so resource IDs are not disclosed
+			http.Error(w, "Resource not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	notes, ownerLabel := resource.Notes, resource.OwnerLabel
+	if req.Notes != nil {
+		notes = strings.TrimSpace(*req.Notes)
+	}
+	if req.OwnerLabel != nil {
+		ownerLabel = strings.TrimSpace(*req.OwnerLabel)
+	}
+	if len(notes) > maxResourceNotesLength {
+		http.Error(w, fmt.Sprintf("notes must be at most %d characters", maxResourceNotesLength), http.StatusBadRequest)
+		return
+	}
+	if len(ownerLabel) > maxResourceOwnerLabelLength {
+		http.Error(w, fmt.Sprintf("owner_label must be at most %d characters", maxResourceOwnerLabelLength), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.resourceRepo.UpdateAnnotations(ctx, resource.ID, notes, ownerLabel); err != nil {
+		log.Printf("Failed to update annotations of %s: %v", resource.ARN, err)
+		http.Error(w, "Failed to update resource", http.StatusInternalServerError)
+		return
+	}
+	managedBy := resource.ManagedBy
+	if req.ManagedBy != nil && *req.ManagedBy != managedBy {
+		managedBy = *req.ManagedBy
+		if err := h.resourceRepo.SetManagedBy(ctx, resource.ID, managedBy); err != nil {
+			log.Printf("Failed to update manager of %s: %v", resource.ARN, err)
+			http.Error(w, "Failed to update resource", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var changes []string
+	if notes != resource.Notes {
+		changes = append(changes, fmt.Sprintf("notes: %q -> %q", resource.Notes, notes))
+	}
+	if ownerLabel != resource.OwnerLabel {
+		changes = append(changes, fmt.Sprintf("owner_label: %q -> %q", resource.OwnerLabel, ownerLabel))
+	}
+	if managedBy != resource.ManagedBy {
+		changes = append(changes, fmt.Sprintf("managed_by: %s -> %s", resource.ManagedBy, managedBy))
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "no changes")
+	}
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "update_discovered_resource",
+		ResourceType: resource.ResourceType,
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ProjectID:    resource.ProjectID,
+		Status:       "success",
+		Details:      strings.Join(changes, "; "),
+	})
+
+	resource.Notes, resource.OwnerLabel, resource.ManagedBy = notes, ownerLabel, managedBy
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resource)
+}
+
 // GetResourceMetricsRequest is the request body for fetching metrics
 type GetResourceMetricsRequest struct {
 	SecretID     string `json:"secret_id"`
 	ResourceType string `json:"resource_type"` // rds, lambda, s3, sqs
 	ResourceName string `json:"resource_name"`
 	Region       string `json:"region"`
-	Period       string `json:"period"` // 1h, 6h, 24h, 7d
+	Period       string `json:"period"` // 1h, 6h, 24h, 7d, 30d
 }
 
-// GetResourceMetrics fetches CloudWatch metrics for a resource
+// GetResourceMetrics fetches CloudWatch metrics for a resource. Results are cached
+// per query for a TTL that grows with the period's granularity; fetched_at tells the
+// UI how old they are. Leads and superadmins can bypass the cache with ?refresh=true.
 func (h *ResourceDetailsHandler) GetResourceMetrics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	// POST only to carry the query; nothing changes
+	middleware.SkipAudit(r.Context())
 
 	// Verify authentication
 	userRole := middleware.GetUserRole(r.Context())
@@ -97,35 +215,35 @@ func (h *ResourceDetailsHandler) GetResourceMetrics(w http.ResponseWriter, r *ht
 		return
 	}
 
-	if req.SecretID == "" || req.ResourceType == "" || req.ResourceName == "" {
-		http.Error(w, "secret_id, resource_type, and resource_name are required", http.StatusBadRequest)
-		return
-	}
-
-	// Get credentials
-	secret, credentials, err := h.secretRepo.GetByIDWithCredentials(r.Context(), req.SecretID)
-	if err != nil {
-		log.Printf("Failed to get secret: %v", err)
-		http.Error(w, "Failed to get credentials", http.StatusInternalServerError)
+	credentials, region, ok := h.resourceCredentials(w, r, &req)
+	if !ok {
 		return
 	}
 
-	region := req.Region
-	if region == "" {
-		region = secret.Region
-	}
-	if region == "" {
-		region = "ap-south-1"
-	}
-
 	period := req.Period
 	if period == "" {
 		period = "24h"
 	}
 
+	cacheKey := metricsCacheKey{
+		secretID:     req.SecretID,
+		resourceType: strings.ToLower(req.ResourceType),
+		resourceName: req.ResourceName,
+		region:       region,
+		period:       period,
+	}
+	if !bypassMetricsCache(r, userRole) {
+		if cached := h.metricsCache.get(cacheKey); cached != nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(cached)
+			return
+		}
+	}
+
 	var metrics *services.ResourceMetrics
+	var err error
 
-	switch strings.ToLower(req.ResourceType) {
+	switch cacheKey.resourceType {
 	case "rds":
 		metrics, err = h.metrics.GetRDSMetrics(r.Context(), credentials, region, req.ResourceName, period)
 	case "lambda":
@@ -136,8 +254,18 @@ func (h *ResourceDetailsHandler) GetResourceMetrics(w http.ResponseWriter, r *ht
 		metrics, err = h.metrics.GetSQSMetrics(r.Context(), credentials, region, req.ResourceName, period)
 	case "sns":
 		metrics, err = h.metrics.GetSNSMetrics(r.Context(), credentials, region, req.ResourceName, period)
+	case "eks":
+		metrics, err = h.metrics.GetEKSMetrics(r.Context(), credentials, region, req.ResourceName, period)
+	case "dynamodb":
+		metrics, err = h.metrics.GetDynamoDBMetrics(r.Context(), credentials, region, req.ResourceName, period)
+	case "kinesis":
+		metrics, err = h.metrics.GetKinesisMetrics(r.Context(), credentials, region, req.ResourceName, period)
+	case "eventbridge":
+		metrics, err = h.metrics.GetEventBridgeMetrics(r.Context(), credentials, region, req.ResourceName, period)
+	case "cloudformation":
+		metrics, err = h.metrics.GetCloudFormationMetrics(r.Context(), credentials, region, req.ResourceName, period)
 	default:
-		http.Error(w, "Unsupported resource type. Supported: rds, lambda, s3, sqs, sns", http.StatusBadRequest)
+		http.Error(w, "Unsupported resource type. Supported: rds, lambda, s3, sqs, sns, eks, dynamodb, kinesis, eventbridge, cloudformation", http.StatusBadRequest)
 		return
 	}
 
@@ -147,6 +275,196 @@ func (h *ResourceDetailsHandler) GetResourceMetrics(w http.ResponseWriter, r *ht
 		return
 	}
 
+	// Alarms are extra context; the metrics are still useful without them
+	alarms, err := h.metrics.GetResourceAlarms(r.Context(), credentials, region, req.ResourceType, req.ResourceName)
+	if err != nil {
+		log.Printf("Failed to fetch alarms of %s %s: %v", req.ResourceType, req.ResourceName, err)
+	}
+	metrics.Alarms = alarms
+	h.metricsCache.put(cacheKey, metrics)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(metrics)
 }
+
+// GetResourceAlarms handles GET /api/v1/resources/alarms: the current state of the
+// CloudWatch alarms of a resource. It takes the same fields as GetResourceMetrics,
+// as query parameters or as a JSON body.
+func (h *ResourceDetailsHandler) GetResourceAlarms(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := GetResourceMetricsRequest{
+		SecretID:     query.Get("secret_id"),
+		ResourceType: query.Get("resource_type"),
+		ResourceName: query.Get("resource_name"),
+		Region:       query.Get("region"),
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	credentials, region, ok := h.resourceCredentials(w, r, &req)
+	if !ok {
+		return
+	}
+
+	alarms, err := h.metrics.GetResourceAlarms(r.Context(), credentials, region, req.ResourceType, req.ResourceName)
+	if err != nil {
+		log.Printf("Failed to fetch alarms of %s %s: %v", req.ResourceType, req.ResourceName, err)
+		http.Error(w, "Failed to fetch alarms", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alarms)
+}
+
+// resourceCredentials checks a metrics or alarms request and returns the credentials
+// and region to query; the region defaults to the credential's. It writes an error
+// response and returns false on failure.
+func (h *ResourceDetailsHandler) resourceCredentials(w http.ResponseWriter, r *http.Request, req *GetResourceMetricsRequest) (*models.AWSCredentials, string, bool) {
+	if req.SecretID == "" || req.ResourceType == "" || req.ResourceName == "" {
+		http.Error(w, "secret_id, resource_type, and resource_name are required", http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	secret, credentials, err := h.secretRepo.GetByIDWithCredentials(r.Context(), req.SecretID)
+	if err != nil {
+		log.Printf("Failed to get secret: %v", err)
+		http.Error(w, "Failed to get credentials", http.StatusInternalServerError)
+		return nil, "", false
+	}
+
+	region := req.Region
+	if region == "" {
+		region = secret.Region
+	}
+	if region == "" {
+		region = "ap-south-1"
+	}
+	return credentials, region, true
+}
+
+// CreateSubscriptionRequest is the request body for subscribing to an SNS topic
+type CreateSubscriptionRequest struct {
+	Protocol string `json:"protocol"` // email, sqs, https or lambda
+	Endpoint string `json:"endpoint"` // Address, URL or ARN depending on the protocol
+	SecretID string `json:"secret_id"`
+}
+
+// CreateSubscription handles POST /api/v1/resources/discovered/{id}/subscriptions:
+// it subscribes an endpoint to a discovered SNS topic. secret_id defaults to the
+// credential the topic was discovered with, then to the project's default.
+func (h *ResourceDetailsHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Protocol == "" || req.Endpoint == "" {
+		http.Error(w, "protocol and endpoint are required", http.StatusBadRequest)
+		return
+	}
+
+	resource, credentials, ok := h.snsTopic(w, r, req.SecretID, true)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.provisioner.CreateSNSSubscription(r.Context(), resource.ARN, req.Protocol, req.Endpoint, credentials)
+	auditLog := models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "create_sns_subscription",
+		ResourceType: "sns",
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ProjectID:    resource.ProjectID,
+		Status:       "success",
+		Details:      fmt.Sprintf("%s: %s", req.Protocol, req.Endpoint),
+	}
+	if err != nil {
+		log.Printf("Failed to subscribe %s to %s: %v", req.Endpoint, resource.ARN, err)
+		auditLog.Status = "failed"
+		auditLog.Details += " (" + err.Error() + ")"
+		h.recordAudit(r.Context(), auditLog)
+		http.Error(w, "Failed to create subscription: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.recordAudit(r.Context(), auditLog)
+
+	h.refreshSubscriptions(r, resource, credentials)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// GetSubscriptions handles GET /api/v1/resources/discovered/{id}/subscriptions,
+// listing the subscriptions of a discovered SNS topic (?secret_id= overrides the credential)
+func (h *ResourceDetailsHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	resource, credentials, ok := h.snsTopic(w, r, r.URL.Query().Get("secret_id"), false)
+	if !ok {
+		return
+	}
+
+	subscriptions := h.refreshSubscriptions(r, resource, credentials)
+	if subscriptions == nil {
+		http.Error(w, "Failed to list subscriptions", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+// snsTopic loads the discovered SNS resource named by the path and the credentials to
+// manage it, which must have write access if write is set; it writes an error
+// response and returns false on failure
+func (h *ResourceDetailsHandler) snsTopic(w http.ResponseWriter, r *http.Request, secretID string, write bool) (*models.DiscoveredResource, *models.AWSCredentials, bool) {
+	resource, err := h.resourceRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if resource.ResourceType != "sns" {
+		http.Error(w, "Subscriptions are only supported for SNS topics", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	if secretID == "" {
+		secretID = resource.SecretID
+	}
+	secretID, err = resolveSecretID(r.Context(), h.projectRepo, resource.ProjectID, secretID)
+	if err != nil || secretID == "" {
+		http.Error(w, "secret_id is required: no credential is associated with this topic", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	credentials, accessType, err := h.secretRepo.GetCredentials(r.Context(), secretID)
+	if err != nil {
+		log.Printf("Failed to get credentials: %v", err)
+		http.Error(w, "Failed to retrieve AWS credentials", http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if write && !accessType.CanWrite() {
+		http.Error(w, readOnlyCredentialMessage, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return resource, credentials, true
+}
+
+// refreshSubscriptions lists the topic's subscriptions and stores them under the
+// "subscriptions" metadata key. It returns nil if they could not be listed.
+func (h *ResourceDetailsHandler) refreshSubscriptions(r *http.Request, resource *models.DiscoveredResource, credentials *models.AWSCredentials) []models.SNSSubscription {
+	subscriptions, err := h.provisioner.ListSNSSubscriptions(r.Context(), resource.ARN, credentials)
+	if err != nil {
+		log.Printf("Failed to list subscriptions of %s: %v", resource.ARN, err)
+		return nil
+	}
+	if err := h.resourceRepo.SetMetadataKey(r.Context(), resource.ID, "subscriptions", subscriptions); err != nil {
+		log.Printf("Failed to store subscriptions of %s: %v", resource.ARN, err)
+	}
+	return subscriptions
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/services"
+)
+
+// ResourceReconciliationHandler reports and repairs provisioned resources whose
+// discovered resource is missing or disagrees with them
+type ResourceReconciliationHandler struct {
+	auditRecorder
+	reconciler *services.ResourceReconciler
+}
+
+// NewResourceReconciliationHandler creates a new ResourceReconciliationHandler
+func NewResourceReconciliationHandler(deps *Deps) *ResourceReconciliationHandler {
+	return &ResourceReconciliationHandler{
+		auditRecorder: newAuditRecorder(deps),
+		reconciler:    deps.Reconciler,
+	}
+}
+
+// GetDivergences handles GET /api/v1/admin/resource-divergences
+func (h *ResourceReconciliationHandler) GetDivergences(w http.ResponseWriter, r *http.Request) {
+	divergences, err := h.reconciler.Report(r.Context())
+	if err != nil {
+		log.Printf("Failed to find divergent resources: %v", err)
+		http.Error(w, "Failed to find divergent resources", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(divergences)
+}
+
+// repairDivergencesRequest is the optional body of POST /api/v1/admin/resource-divergences/repair
+type repairDivergencesRequest struct {
+	ResourceIDs []string `json:"resource_ids"` // Empty links by ARN and repairs everything
+}
+
+// RepairDivergences handles POST /api/v1/admin/resource-divergences/repair
+func (h *ResourceReconciliationHandler) RepairDivergences(w http.ResponseWriter, r *http.Request) {
+	var req repairDivergencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.reconciler.Reconcile(r.Context(), req.ResourceIDs)
+	if err != nil {
+		log.Printf("Failed to reconcile resources: %v", err)
+		http.Error(w, "Failed to reconcile resources", http.StatusInternalServerError)
+		return
+	}
+
+	repairs := make(map[string]string, len(result.Repaired))
+	for _, d := range result.Repaired {
+		repairs[d.ResourceID] = d.Repair
+	}
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"linked":    result.Linked,
+		"repaired":  repairs,
+		"remaining": len(result.Remaining),
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "reconcile_resources",
+		ResourceType: "resource",
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
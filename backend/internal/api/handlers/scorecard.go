@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/scorecard"
+)
+
+// ScorecardHandler serves service readiness scorecards
+type ScorecardHandler struct {
+	serviceRepo *repositories.ServiceRepository
+	projectRepo *repositories.ProjectRepository
+	scorecards  *scorecard.Evaluator
+}
+
+// NewScorecardHandler creates a new ScorecardHandler
+func NewScorecardHandler(deps *Deps) *ScorecardHandler {
+	return &ScorecardHandler{
+		serviceRepo: deps.Services,
+		projectRepo: deps.Projects,
+		scorecards:  deps.Scorecards,
+	}
+}
+
+// GetServiceScorecard handles GET /api/v1/services/{id}/scorecard: the result of
+// every registered check for the service
+func (h *ScorecardHandler) GetServiceScorecard(w http.ResponseWriter, r *http.Request) {
+	service, err := h.serviceRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Service not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.scorecards.Service(r.Context(), service))
+}
+
+// GetProjectScorecard handles GET /api/v1/projects/{id}/scorecard: the scorecards of
+// every service of the project and per-check totals. Rollups are cached for a few
+// minutes. Non-superadmins only see projects they can access.
+func (h *ScorecardHandler) GetProjectScorecard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := r.PathValue("id")
+
+	if _, err := h.projectRepo.FindByID(ctx, projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+	if middleware.GetUserRole(ctx) != "superadmin" {
+		allowed, err := h.projectRepo.CanUserAccess(ctx, projectID, middleware.GetUserID(ctx))
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	card, err := h.scorecards.Project(ctx, projectID)
+	if err != nil {
+		log.Printf("Failed to compute scorecard of project %s: %v", projectID, err)
+		http.Error(w, "Failed to compute project scorecard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(card)
+}
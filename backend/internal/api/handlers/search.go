@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50 // Hard cap per type; larger limits are clamped
+)
+
+// SearchHandler serves global search
+type SearchHandler struct {
+	searchRepo *repositories.SearchRepository
+}
+
+// NewSearchHandler creates a new SearchHandler
+func NewSearchHandler(deps *Deps) *SearchHandler {
+	return &SearchHandler{
+		searchRepo: deps.Search,
+	}
+}
+
+// Search handles GET /api/v1/search?q=payments&types=project,service,resource&limit=20
+// All authenticated roles can search; limit applies per type and the response flags
+// truncated types. Non-superadmins only see projects (and their services and
+// resources) they have access to.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(w, "Missing required query parameter: q", http.StatusBadRequest)
+		return
+	}
+
+	var types []string
+	if value := query.Get("types"); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			t = strings.TrimSpace(t)
+			if t != models.SearchTypeProject && t != models.SearchTypeService && t != models.SearchTypeResource {
+				http.Error(w, "Invalid type. Supported types: project, service, resource", http.StatusBadRequest)
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit := defaultSearchLimit
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	userID := ""
+	if middleware.GetUserRole(r.Context()) != "superadmin" {
+		userID = middleware.GetUserID(r.Context())
+		if userID == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	results, err := h.searchRepo.Search(r.Context(), q, types, limit, userID)
+	if err != nil {
+		log.Printf("Search failed: %v", err)
+		http.Error(w, "Failed to search", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -8,18 +8,21 @@ import (
 	"github.com/portalight/backend/internal/repositories"
 )
 
-type SecretHandler struct{}
+type SecretHandler struct {
+	secretRepo *repositories.SecretRepository
+}
 
-func NewSecretHandler() *SecretHandler {
-	return &SecretHandler{}
+func NewSecretHandler(deps *Deps) *SecretHandler {
+	return &SecretHandler{
+		secretRepo: deps.Secrets,
+	}
 }
 
 // GetSecrets returns available cloud provider credentials from the database
 func (h *SecretHandler) GetSecrets(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	secretRepo := &repositories.SecretRepository{}
 
-	secrets, err := secretRepo.GetAll(ctx)
+	secrets, err := h.secretRepo.GetAll(ctx)
 	if err != nil {
 		http.Error(w, "Failed to fetch secrets", http.StatusInternalServerError)
 		return
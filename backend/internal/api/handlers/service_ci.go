@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/github"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ciStatusCacheTTL keeps repeated service page loads from using up the GitHub API rate limit
+const ciStatusCacheTTL = 2 * time.Minute
+
+// ServiceCIStatus is the latest GitHub Actions run of a service's repository
+type ServiceCIStatus struct {
+	ServiceID  string                     `json:"service_id"`
+	Repository string                     `json:"repository"` // owner/repo
+	LatestRun  *github.WorkflowRunSummary `json:"latest_run"` // nil if the repository has no runs
+}
+
+type ciStatusEntry struct {
+	status    *ServiceCIStatus // Never modified once cached
+	expiresAt time.Time
+}
+
+// ServiceCIHandler reports the CI status of services from GitHub Actions, using the
+// token of the catalog's GitHub integration
+type ServiceCIHandler struct {
+	serviceRepo *repositories.ServiceRepository
+	syncer      *catalog.Syncer
+
+	mu    sync.Mutex
+	cache map[string]ciStatusEntry // By service ID
+}
+
+// NewServiceCIHandler creates a new ServiceCIHandler
+func NewServiceCIHandler(deps *Deps, syncer *catalog.Syncer) *ServiceCIHandler {
+	return &ServiceCIHandler{
+		serviceRepo: deps.Services,
+		syncer:      syncer,
+		cache:       make(map[string]ciStatusEntry),
+	}
+}
+
+// GetCIStatus handles GET /api/v1/services/{id}/ci-status: the latest workflow run on
+// the default branch of the service's repository. Responses are cached for two minutes.
+func (h *ServiceCIHandler) GetCIStatus(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.PathValue("id")
+	if status := h.cached(serviceID); status != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+		return
+	}
+
+	service, err := h.serviceRepo.FindByID(r.Context(), serviceID)
+	if err != nil {
+		http.Error(w, "Service not found", http.StatusNotFound)
+		return
+	}
+	owner, repo, ok := parseGitHubRepository(service.Repository)
+	if !ok {
+		http.Error(w, "Service has no GitHub repository", http.StatusUnprocessableEntity)
+		return
+	}
+
+	client, err := h.syncer.Client(r.Context())
+	if err != nil {
+		http.Error(w, "GitHub integration is not configured: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	run, err := client.GetLatestWorkflowRun(r.Context(), owner, repo, "")
+	if err != nil {
+		log.Printf("Failed to get CI status of service %s (%s/%s): %v", serviceID, owner, repo, err)
+		http.Error(w, "Failed to get CI status from GitHub", http.StatusBadGateway)
+		return
+	}
+
+	status := &ServiceCIStatus{ServiceID: serviceID, Repository: owner + "/" + repo, LatestRun: run}
+	h.store(serviceID, status)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// cached returns the cached status of a service, or nil if missing or expired
+func (h *ServiceCIHandler) cached(serviceID string) *ServiceCIStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry, ok := h.cache[serviceID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return entry.status
+}
+
+// store caches a status and drops expired entries so the cache only holds recently viewed services
+func (h *ServiceCIHandler) store(serviceID string, status *ServiceCIStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range h.cache {
+		if now.After(entry.expiresAt) {
+			delete(h.cache, id)
+		}
+	}
+	h.cache[serviceID] = ciStatusEntry{status: status, expiresAt: now.Add(ciStatusCacheTTL)}
+}
+
+// parseGitHubRepository extracts owner and repo from a GitHub repository reference:
+// "https://github.com/owner/repo", "github.com/owner/repo", "git@github.com:owner/repo.git"
+// or plain "owner/repo". URLs may point into the repository ("/tree/main").
+func parseGitHubRepository(repository string) (owner, repo string, ok bool) {
+	ref := strings.TrimSpace(repository)
+	isURL := true
+	switch {
+	case strings.HasPrefix(ref, "git@github.com:"):
+		ref = strings.TrimPrefix(ref, "git@github.com:")
+	case strings.Contains(ref, "://"):
+		_, rest, _ := strings.Cut(ref, "://")
+		host, path, _ := strings.Cut(rest, "/")
+		if !strings.EqualFold(host, "github.com") && !strings.EqualFold(host, "www.github.com") {
+			return "", "", false
+		}
+		ref = path
+	case strings.HasPrefix(strings.ToLower(ref), "github.com/"):
+		ref = ref[len("github.com/"):]
+	default:
+		isURL = false
+	}
+
+	parts := strings.Split(strings.Trim(ref, "/"), "/")
+	if len(parts) < 2 || (!isURL && len(parts) != 2) || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), true
+}
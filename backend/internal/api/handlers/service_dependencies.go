@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ServiceDependencyHandler serves the service dependency graph declared with dependsOn
+type ServiceDependencyHandler struct {
+	depRepo     *repositories.ServiceDependencyRepository
+	serviceRepo *repositories.ServiceRepository
+}
+
+// NewServiceDependencyHandler creates a new ServiceDependencyHandler
+func NewServiceDependencyHandler(deps *Deps) *ServiceDependencyHandler {
+	return &ServiceDependencyHandler{
+		depRepo:     deps.ServiceDependencies,
+		serviceRepo: deps.Services,
+	}
+}
+
+// GetDependencies handles GET /api/v1/services/{id}/dependencies.
+// Cycles are allowed in the catalog; they are flagged so the UI can warn.
+func (h *ServiceDependencyHandler) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	serviceID := r.PathValue("id")
+	if _, err := h.serviceRepo.FindByID(ctx, serviceID); err != nil {
+		http.Error(w, "Service not found", http.StatusNotFound)
+		return
+	}
+
+	graph, err := h.depRepo.GetGraph(ctx, serviceID)
+	if err != nil {
+		log.Printf("Failed to load dependency graph of service %s: %v", serviceID, err)
+		http.Error(w, "Failed to list dependencies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(graph)
+}
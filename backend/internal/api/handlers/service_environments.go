@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// maxEnvironmentNameLength is the size of service_environments.environment
+const maxEnvironmentNameLength = 50
+
+// ServiceEnvironmentHandler handles the environments of a service
+type ServiceEnvironmentHandler struct {
+	environmentRepo *repositories.ServiceEnvironmentRepository
+	serviceRepo     *repositories.ServiceRepository
+}
+
+// NewServiceEnvironmentHandler creates a new ServiceEnvironmentHandler
+func NewServiceEnvironmentHandler(deps *Deps) *ServiceEnvironmentHandler {
+	return &ServiceEnvironmentHandler{
+		environmentRepo: deps.ServiceEnvironments,
+		serviceRepo:     deps.Services,
+	}
+}
+
+// ListEnvironments handles GET /api/v1/services/{id}/environments: every environment
+// of the service, including inactive ones, with its linked ArgoCD app
+func (h *ServiceEnvironmentHandler) ListEnvironments(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.PathValue("id")
+	if !h.serviceExists(w, r, serviceID) {
+		return
+	}
+
+	environments, err := h.environmentRepo.ListByService(r.Context(), serviceID)
+	if err != nil {
+		log.Printf("Failed to get service environments: %v", err)
+		http.Error(w, "Failed to get environments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(environments)
+}
+
+// AddEnvironment handles POST /api/v1/services/{id}/environments. Posting an existing
+// environment updates whether it is active.
+func (h *ServiceEnvironmentHandler) AddEnvironment(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.PathValue("id")
+
+	var req models.CreateServiceEnvironmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Name) > maxEnvironmentNameLength {
+		http.Error(w, "name must be at most 50 characters", http.StatusBadRequest)
+		return
+	}
+
+	if !h.serviceExists(w, r, serviceID) {
+		return
+	}
+
+	env := &models.ServiceEnvironment{
+		ServiceID: serviceID,
+		Name:      req.Name,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+	}
+	created, err := h.environmentRepo.Upsert(r.Context(), env)
+	if err != nil {
+		log.Printf("Failed to save service environment: %v", err)
+		http.Error(w, "Failed to save environment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(env)
+}
+
+// DeleteEnvironment handles DELETE /api/v1/services/{id}/environments/{name}
+func (h *ServiceEnvironmentHandler) DeleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	err := h.environmentRepo.Delete(r.Context(), r.PathValue("id"), r.PathValue("name"))
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Environment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete service environment: %v", err)
+		http.Error(w, "Failed to delete environment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serviceExists writes a 404 when the service does not exist
+func (h *ServiceEnvironmentHandler) serviceExists(w http.ResponseWriter, r *http.Request, serviceID string) bool {
+	_, err := h.serviceRepo.FindByID(r.Context(), serviceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Service not found", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to get service: %v", err)
+		http.Error(w, "Failed to get service", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/services"
+)
+
+// projectHealthConcurrency bounds how many services of a project are checked at once
+const projectHealthConcurrency = 8
+
+// ServiceHealthHandler aggregates ArgoCD, AWS resource and metric state into one health view
+type ServiceHealthHandler struct {
+	serviceRepo    *repositories.ServiceRepository
+	projectRepo    *repositories.ProjectRepository
+	argocdRepo     *repositories.ArgoCDRepository
+	mappingRepo    *repositories.ServiceResourceMappingRepository
+	discoveredRepo *repositories.DiscoveredResourceRepository
+	secretRepo     *repositories.SecretRepository
+	argocd         *services.ArgoCDClient
+	metrics        *services.AWSMetrics
+}
+
+// NewServiceHealthHandler creates a new ServiceHealthHandler
+func NewServiceHealthHandler(deps *Deps) *ServiceHealthHandler {
+	return &ServiceHealthHandler{
+		serviceRepo:    deps.Services,
+		projectRepo:    deps.Projects,
+		argocdRepo:     deps.ArgoCDApps,
+		mappingRepo:    deps.ServiceResourceMappings,
+		discoveredRepo: deps.DiscoveredResources,
+		secretRepo:     deps.Secrets,
+		argocd:         deps.ArgoCD,
+		metrics:        deps.Metrics,
+	}
+}
+
+// GetServiceHealth handles GET /api/v1/services/{id}/health. ?metrics=true also
+// checks the last minutes of Lambda and RDS metrics, which calls CloudWatch.
+func (h *ServiceHealthHandler) GetServiceHealth(w http.ResponseWriter, r *http.Request) {
+	service, err := h.serviceRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Service not found", http.StatusNotFound)
+		return
+	}
+
+	health := h.checkService(r.Context(), service, r.URL.Query().Get("metrics") == "true")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
+
+// GetProjectServicesHealth handles GET /api/v1/projects/{id}/services/health: the
+// health of every service of the project, checked concurrently. Non-superadmins
+// must have access to the project.
+func (h *ServiceHealthHandler) GetProjectServicesHealth(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	if _, err := h.projectRepo.FindByID(r.Context(), projectID); err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	if middleware.GetUserRole(r.Context()) != "superadmin" {
+		userID := middleware.GetUserID(r.Context())
+		if userID == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		allowed, err := h.projectRepo.CanUserAccess(r.Context(), projectID, userID)
+		if err != nil {
+			http.Error(w, "Failed to check project access", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			// Same response as a missing project so project IDs are not disclosed
+			http.Error(w, "Project not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	projectServices, err := h.serviceRepo.FindByProjectID(r.Context(), projectID)
+	if err != nil {
+		log.Printf("Failed to list services of project %s: %v", projectID, err)
+		http.Error(w, "Failed to fetch services", http.StatusInternalServerError)
+		return
+	}
+
+	withMetrics := r.URL.Query().Get("metrics") == "true"
+	results := make([]*models.ServiceHealth, len(projectServices))
+	slots := make(chan struct{}, projectHealthConcurrency)
+	var wg sync.WaitGroup
+	for i := range projectServices {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = h.checkService(r.Context(), &projectServices[i], withMetrics)
+		}(i)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// checkService gathers the ArgoCD, resource and (optionally) metric state of a
+// service concurrently. Failing sources are logged and show up as unknown statuses
+// rather than failing the whole check.
+func (h *ServiceHealthHandler) checkService(ctx context.Context, service *models.Service, withMetrics bool) *models.ServiceHealth {
+	health := &models.ServiceHealth{
+		ServiceID:        service.ID,
+		ServiceName:      service.Name,
+		ArgoCDStatuses:   make(map[string]string),
+		ResourceStatuses: make(map[string]string),
+		MetricAlerts:     []string{},
+	}
+
+	var resources []*models.DiscoveredResource
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h.checkArgoCD(ctx, service.ID, health.ArgoCDStatuses)
+	}()
+	go func() {
+		defer wg.Done()
+		resources = h.checkResources(ctx, service.ID, health.ResourceStatuses)
+	}()
+	wg.Wait()
+
+	// Metrics need the mapped resources' credentials and regions, so they come last
+	if withMetrics {
+		health.MetricAlerts = h.checkMetrics(ctx, resources)
+	}
+
+	health.Overall = overallHealth(health)
+	health.LastChecked = time.Now()
+	return health
+}
+
+// checkArgoCD records the ArgoCD health of each environment's app
+func (h *ServiceHealthHandler) checkArgoCD(ctx context.Context, serviceID string, statuses map[string]string) {
+	if !h.argocd.IsConfigured() {
+		return
+	}
+	apps, err := h.argocdRepo.GetByServiceID(ctx, serviceID)
+	if err != nil {
+		log.Printf("Failed to get ArgoCD apps of service %s: %v", serviceID, err)
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app models.ServiceArgoCDApp) {
+			defer wg.Done()
+			status := "Unknown"
+			if application, err := h.argocd.GetApplicationStatus(ctx, app.ArgoCDAppName); err != nil {
+				log.Printf("Failed to get status of ArgoCD app %s: %v", app.ArgoCDAppName, err)
+			} else if application.Health != "" {
+				status = application.Health
+			}
+			mu.Lock()
+			statuses[app.EnvironmentName] = status
+			mu.Unlock()
+		}(app)
+	}
+	wg.Wait()
+}
+
+// checkResources records the status of each mapped AWS resource and returns the
+// resources that could be loaded
+func (h *ServiceHealthHandler) checkResources(ctx context.Context, serviceID string, statuses map[string]string) []*models.DiscoveredResource {
+	mappings, err := h.mappingRepo.GetByServiceID(ctx, serviceID)
+	if err != nil {
+		log.Printf("Failed to get resource mappings of service %s: %v", serviceID, err)
+		return nil
+	}
+
+	var resources []*models.DiscoveredResource
+	for _, mapping := range mappings {
+		key := mapping.ResourceType + "/" + mapping.ResourceName
+		resource, err := h.discoveredRepo.FindByID(ctx, mapping.DiscoveredResourceID)
+		if err != nil {
+			statuses[key] = string(models.ResourceStatusUnknown)
+			continue
+		}
+		statuses[key] = string(resource.Status)
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// checkMetrics collects the recent metric alerts of the active Lambda functions and RDS instances
+func (h *ServiceHealthHandler) checkMetrics(ctx context.Context, resources []*models.DiscoveredResource) []string {
+	alerts := []string{}
+	for _, resource := range resources {
+		if resource.Status != models.ResourceStatusActive || resource.SecretID == "" {
+			continue
+		}
+		if resource.ResourceType != "lambda" && resource.ResourceType != "rds" {
+			continue
+		}
+
+		_, credentials, err := h.secretRepo.GetByIDWithCredentials(ctx, resource.SecretID)
+		if err != nil {
+			log.Printf("Failed to get credentials for %s: %v", resource.Name, err)
+			continue
+		}
+		resourceAlerts, err := h.metrics.RecentAlerts(ctx, credentials, resource.Region, resource.ResourceType, resource.Name)
+		if err != nil {
+			log.Printf("Failed to check metrics of %s: %v", resource.Name, err)
+			continue
+		}
+		alerts = append(alerts, resourceAlerts...)
+	}
+	return alerts
+}
+
+// overallHealth is unhealthy if an app is degraded or missing or a resource was
+// deleted, degraded if anything else is not plainly fine, and unknown if there is
+// nothing to judge by
+func overallHealth(health *models.ServiceHealth) string {
+	if len(health.ArgoCDStatuses) == 0 && len(health.ResourceStatuses) == 0 {
+		return models.ServiceHealthUnknown
+	}
+
+	overall := models.ServiceHealthHealthy
+	for _, status := range health.ArgoCDStatuses {
+		switch status {
+		case "Healthy":
+		case "Degraded", "Missing":
+			return models.ServiceHealthUnhealthy
+		default:
+			overall = models.ServiceHealthDegraded
+		}
+	}
+	for _, status := range health.ResourceStatuses {
+		switch models.DiscoveredResourceStatus(status) {
+		case models.ResourceStatusActive:
+		case models.ResourceStatusDeleted:
+			return models.ServiceHealthUnhealthy
+		default:
+			overall = models.ServiceHealthDegraded
+		}
+	}
+	if len(health.MetricAlerts) > 0 {
+		overall = models.ServiceHealthDegraded
+	}
+	return overall
+}
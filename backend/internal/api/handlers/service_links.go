@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
@@ -18,14 +20,14 @@ type ServiceLinksHandler struct {
 }
 
 // NewServiceLinksHandler creates a new ServiceLinksHandler
-func NewServiceLinksHandler() *ServiceLinksHandler {
+func NewServiceLinksHandler(deps *Deps) *ServiceLinksHandler {
 	return &ServiceLinksHandler{
-		linkRepo:    repositories.NewServiceLinkRepository(),
-		serviceRepo: &repositories.ServiceRepository{},
+		linkRepo:    deps.ServiceLinks,
+		serviceRepo: deps.Services,
 	}
 }
 
-// GetLinks handles GET /api/v1/services/:id/links
+// GetLinks handles GET /api/v1/services/:id/links, optionally filtered with ?type=runbook
 func (h *ServiceLinksHandler) GetLinks(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -41,7 +43,17 @@ func (h *ServiceLinksHandler) GetLinks(w http.ResponseWriter, r *http.Request) {
 	}
 	serviceID := parts[4]
 
-	links, err := h.linkRepo.GetByServiceID(r.Context(), serviceID)
+	var links []models.ServiceLink
+	var err error
+	if linkType := r.URL.Query().Get("type"); linkType != "" {
+		if !models.IsValidServiceLinkType(linkType) {
+			http.Error(w, "Invalid link type", http.StatusBadRequest)
+			return
+		}
+		links, err = h.linkRepo.GetByServiceIDAndType(r.Context(), serviceID, linkType)
+	} else {
+		links, err = h.linkRepo.GetByServiceID(r.Context(), serviceID)
+	}
 	if err != nil {
 		log.Printf("Failed to get service links: %v", err)
 		http.Error(w, "Failed to get links", http.StatusInternalServerError)
@@ -80,6 +92,7 @@ func (h *ServiceLinksHandler) AddLink(w http.ResponseWriter, r *http.Request) {
 	serviceID := parts[4]
 
 	var req struct {
+		Type  string `json:"type"`
 		Label string `json:"label"`
 		URL   string `json:"url"`
 		Icon  string `json:"icon"`
@@ -90,13 +103,18 @@ func (h *ServiceLinksHandler) AddLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Label == "" || req.URL == "" {
-		http.Error(w, "Label and URL are required", http.StatusBadRequest)
+	if req.Type == "" || req.Label == "" || req.URL == "" {
+		http.Error(w, "Type, label and URL are required", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidServiceLinkType(req.Type) {
+		http.Error(w, "Invalid link type (allowed: generic, runbook, incident_tracker, monitoring, api_docs, repository)", http.StatusBadRequest)
 		return
 	}
 
 	link := &models.ServiceLink{
 		ServiceID: serviceID,
+		Type:      req.Type,
 		Label:     req.Label,
 		URL:       req.URL,
 		Icon:      req.Icon,
@@ -137,6 +155,7 @@ func (h *ServiceLinksHandler) UpdateLink(w http.ResponseWriter, r *http.Request)
 	linkID := parts[6]
 
 	var req struct {
+		Type  string `json:"type"` // Optional: empty keeps the current type
 		Label string `json:"label"`
 		URL   string `json:"url"`
 		Icon  string `json:"icon"`
@@ -146,15 +165,25 @@ func (h *ServiceLinksHandler) UpdateLink(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.Type != "" && !models.IsValidServiceLinkType(req.Type) {
+		http.Error(w, "Invalid link type (allowed: generic, runbook, incident_tracker, monitoring, api_docs, repository)", http.StatusBadRequest)
+		return
+	}
 
 	link := &models.ServiceLink{
 		ID:    linkID,
+		Type:  req.Type,
 		Label: req.Label,
 		URL:   req.URL,
 		Icon:  req.Icon,
 	}
 
-	if err := h.linkRepo.Update(r.Context(), link); err != nil {
+	err := h.linkRepo.Update(r.Context(), link)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "Link not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
 		log.Printf("Failed to update service link: %v", err)
 		http.Error(w, "Failed to update link", http.StatusInternalServerError)
 		return
@@ -8,6 +8,7 @@ import (
 
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
 	"github.com/portalight/backend/internal/repositories"
 )
 
@@ -18,14 +19,14 @@ type ServiceResourcesHandler struct {
 }
 
 // NewServiceResourcesHandler creates a new ServiceResourcesHandler
-func NewServiceResourcesHandler() *ServiceResourcesHandler {
+func NewServiceResourcesHandler(deps *Deps) *ServiceResourcesHandler {
 	return &ServiceResourcesHandler{
-		mappingRepo:  repositories.NewServiceResourceMappingRepository(),
-		resourceRepo: repositories.NewDiscoveredResourceRepository(),
+		mappingRepo:  deps.ServiceResourceMappings,
+		resourceRepo: deps.DiscoveredResources,
 	}
 }
 
-// GetResources handles GET /api/v1/services/:id/resources
+// GetResources handles GET /api/v1/services/:id/resources[?environment=production]
 func (h *ServiceResourcesHandler) GetResources(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -48,9 +49,15 @@ func (h *ServiceResourcesHandler) GetResources(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if mappings == nil {
-		mappings = []models.ServiceResourceMapping{}
+	// Resources mapped without an environment serve every environment
+	environment := r.URL.Query().Get("environment")
+	filtered := []models.ServiceResourceMapping{}
+	for _, mapping := range mappings {
+		if inEnvironment(mapping.Environment, environment) {
+			filtered = append(filtered, mapping)
+		}
 	}
+	mappings = filtered
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(mappings)
@@ -82,6 +89,7 @@ func (h *ServiceResourcesHandler) MapResource(w http.ResponseWriter, r *http.Req
 	var req struct {
 		ResourceID  string   `json:"resource_id"`
 		ResourceIDs []string `json:"resource_ids"` // Support bulk mapping
+		Environment string   `json:"environment"`  // Optional; empty maps the resources for every environment
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -115,6 +123,7 @@ func (h *ServiceResourcesHandler) MapResource(w http.ResponseWriter, r *http.Req
 		mapping := &models.ServiceResourceMapping{
 			ServiceID:            serviceID,
 			DiscoveredResourceID: resourceID,
+			Environment:          req.Environment,
 		}
 
 		if err := h.mappingRepo.Create(r.Context(), mapping); err != nil {
@@ -125,6 +134,18 @@ func (h *ServiceResourcesHandler) MapResource(w http.ResponseWriter, r *http.Req
 		created = append(created, *mapping)
 	}
 
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"resource_ids": resourceIDs,
+		"environment":  req.Environment,
+		"mapped":       len(created),
+	})
+	middleware.EnrichAudit(r.Context(), models.AuditLog{
+		Action:       "map_service_resources",
+		ResourceType: "service",
+		ResourceID:   serviceID,
+		Details:      detailsJSON,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
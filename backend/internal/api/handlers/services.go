@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 
@@ -12,23 +13,55 @@ import (
 	"github.com/portalight/backend/internal/repositories"
 )
 
-// GetServices returns all services from the database
-func GetServices(w http.ResponseWriter, r *http.Request) {
+// ServicesHandler serves the service catalog endpoints
+type ServicesHandler struct {
+	serviceRepo *repositories.ServiceRepository
+	linkRepo    *repositories.ServiceLinkRepository
+	mappingRepo *repositories.ServiceResourceMappingRepository
+	teamRepo    *repositories.TeamRepository
+}
+
+// NewServicesHandler creates a new ServicesHandler
+func NewServicesHandler(deps *Deps) *ServicesHandler {
+	return &ServicesHandler{
+		serviceRepo: deps.Services,
+		linkRepo:    deps.ServiceLinks,
+		mappingRepo: deps.ServiceResourceMappings,
+		teamRepo:    deps.Teams,
+	}
+}
+
+// GetServices returns one page of services as {"items": [...], "total": N}.
+// ?environment=production lists only the services running in that environment.
+func (h *ServicesHandler) GetServices(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	serviceRepo := &repositories.ServiceRepository{}
 
-	services, err := serviceRepo.GetAll(ctx)
+	opts, err := parseListOptions(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to fetch services: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := repositories.ServiceFilter{Environment: r.URL.Query().Get("environment")}
+	services, total, err := h.serviceRepo.GetAll(ctx, filter, opts)
+	writeCachedList(w, r, services, total, err, "Failed to fetch services")
+}
+
+// GetServiceTags returns every distinct service tag with its usage count, for tag filters
+func (h *ServicesHandler) GetServiceTags(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.serviceRepo.TagCounts(r.Context())
+	if err != nil {
+		log.Printf("Failed to count service tags: %v", err)
+		http.Error(w, "Failed to fetch service tags", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(services)
+	json.NewEncoder(w).Encode(counts)
 }
 
 // GetServiceByID returns a single service with its links and mapped resources
-func GetServiceByID(w http.ResponseWriter, r *http.Request) {
+func (h *ServicesHandler) GetServiceByID(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
 	// Extract service ID/name from path: /api/v1/services/{id}
@@ -45,20 +78,15 @@ func GetServiceByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	serviceRepo := &repositories.ServiceRepository{}
-	linkRepo := repositories.NewServiceLinkRepository()
-	mappingRepo := repositories.NewServiceResourceMappingRepository()
-	teamRepo := &repositories.TeamRepository{}
-
 	// Determine if it's a UUID or a name
 	var service *models.Service
 	var err error
 
 	// Simple UUID check: 36 characters with hyphens in right places
 	if len(serviceIdentifier) == 36 && strings.Count(serviceIdentifier, "-") == 4 {
-		service, err = serviceRepo.FindByID(ctx, serviceIdentifier)
+		service, err = h.serviceRepo.FindByID(ctx, serviceIdentifier)
 	} else {
-		service, err = serviceRepo.FindByName(ctx, serviceIdentifier)
+		service, err = h.serviceRepo.FindByName(ctx, serviceIdentifier)
 	}
 
 	if err != nil {
@@ -71,14 +99,14 @@ func GetServiceByID(w http.ResponseWriter, r *http.Request) {
 
 	// Get team name
 	if service.Team != "" {
-		team, err := teamRepo.FindByID(ctx, service.Team)
+		team, err := h.teamRepo.FindByID(ctx, service.Team)
 		if err == nil && team != nil {
 			service.TeamName = team.Name
 		}
 	}
 
 	// Get links
-	links, err := linkRepo.GetByServiceID(ctx, serviceID)
+	links, err := h.linkRepo.GetByServiceID(ctx, serviceID)
 	if err != nil {
 		fmt.Printf("Warning: Failed to get service links: %v\n", err)
 		links = nil
@@ -86,15 +114,14 @@ func GetServiceByID(w http.ResponseWriter, r *http.Request) {
 	service.Links = links
 
 	// Get mapped resources
-	mappings, err := mappingRepo.GetByServiceID(ctx, serviceID)
+	mappings, err := h.mappingRepo.GetByServiceID(ctx, serviceID)
 	if err != nil {
 		fmt.Printf("Warning: Failed to get service resources: %v\n", err)
 		mappings = nil
 	}
 	service.MappedResources = mappings
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(service)
+	writeJSONWithETag(w, r, service)
 }
 
 // UpdateServiceRequest represents the request body for updating a service
@@ -103,7 +130,7 @@ type UpdateServiceRequest struct {
 }
 
 // UpdateService updates a service's editable fields
-func UpdateService(w http.ResponseWriter, r *http.Request) {
+func (h *ServicesHandler) UpdateService(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
 	// Extract service ID from path: /api/v1/services/{id}
@@ -134,10 +161,8 @@ func UpdateService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	serviceRepo := &repositories.ServiceRepository{}
-
 	// Get existing service
-	service, err := serviceRepo.FindByID(ctx, serviceID)
+	service, err := h.serviceRepo.FindByID(ctx, serviceID)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Service not found: %v", err), http.StatusNotFound)
 		return
@@ -149,7 +174,7 @@ func UpdateService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save updated service
-	err = serviceRepo.Update(ctx, service)
+	err = h.serviceRepo.Update(ctx, service)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to update service: %v", err), http.StatusInternalServerError)
 		return
@@ -158,3 +183,9 @@ func UpdateService(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(service)
 }
+
+// inEnvironment reports whether something of the given environment passes an
+// ?environment= filter. An empty filter or environment matches everything.
+func inEnvironment(environment, filter string) bool {
+	return filter == "" || environment == "" || strings.EqualFold(environment, filter)
+}
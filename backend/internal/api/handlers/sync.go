@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
@@ -14,15 +17,23 @@ import (
 
 // SyncHandler handles resource sync endpoints
 type SyncHandler struct {
+	auditRecorder
 	syncService  *services.ResourceSyncService
 	resourceRepo *repositories.DiscoveredResourceRepository
+	projectRepo  *repositories.ProjectRepository
+	secretRepo   *repositories.SecretRepository
+	discovery    *services.AWSDiscovery
 }
 
 // NewSyncHandler creates a new sync handler
-func NewSyncHandler() *SyncHandler {
+func NewSyncHandler(deps *Deps) *SyncHandler {
 	return &SyncHandler{
-		syncService:  services.NewResourceSyncService(),
-		resourceRepo: repositories.NewDiscoveredResourceRepository(),
+		auditRecorder: newAuditRecorder(deps),
+		syncService:   deps.ResourceSync,
+		resourceRepo:  deps.DiscoveredResources,
+		projectRepo:   deps.Projects,
+		secretRepo:    deps.Secrets,
+		discovery:     deps.Discovery,
 	}
 }
 
@@ -52,8 +63,11 @@ func (h *SyncHandler) SyncProjectResources(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	if req.ProjectID == "" || req.SecretID == "" {
-		http.Error(w, "project_id and secret_id are required", http.StatusBadRequest)
+	if req.ProjectID == "" {
+		http.Error(w, "project_id is required", http.StatusBadRequest)
+		return
+	}
+	if !h.resolveSecret(w, r, req.ProjectID, &req.SecretID) {
 		return
 	}
 
@@ -63,16 +77,46 @@ func (h *SyncHandler) SyncProjectResources(w http.ResponseWriter, r *http.Reques
 	}
 
 	result, err := h.syncService.SyncProject(r.Context(), req.ProjectID, req.SecretID, region)
+	auditLog := models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "sync_project_resources",
+		ResourceType: "project",
+		ResourceID:   req.ProjectID,
+		ProjectID:    req.ProjectID,
+		Status:       "success",
+		Details:      "Region: " + region,
+	}
 	if err != nil {
 		log.Printf("Sync failed: %v", err)
 		// Still return the result with error info
+		auditLog.Status = "failed"
+		auditLog.Details = err.Error()
 	}
+	h.recordAudit(r.Context(), auditLog)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
 
-// AssociateResources associates discovered resources with a project
+// resolveSecret fills in the project's default credential when secretID is
+// empty; it writes an error response and returns false if there is none
+func (h *SyncHandler) resolveSecret(w http.ResponseWriter, r *http.Request, projectID string, secretID *string) bool {
+	resolved, err := resolveSecretID(r.Context(), h.projectRepo, projectID, *secretID)
+	if err != nil {
+		http.Error(w, "Project not found", http.StatusNotFound)
+		return false
+	}
+	if resolved == "" {
+		http.Error(w, "secret_id is required: the project has no default credential", http.StatusBadRequest)
+		return false
+	}
+	*secretID = resolved
+	return true
+}
+
+// AssociateResources associates discovered resources with a project. Each resource is
+// verified to exist and be visible to the credential first; rejected resources are
+// reported per ARN.
 func (h *SyncHandler) AssociateResources(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -91,10 +135,33 @@ func (h *SyncHandler) AssociateResources(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.ProjectID == "" || req.SecretID == "" {
-		http.Error(w, "project_id and secret_id are required", http.StatusBadRequest)
+	if req.ProjectID == "" {
+		http.Error(w, "project_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.SkipVerification && userRole != "superadmin" {
+		http.Error(w, "Only superadmins can skip verification", http.StatusForbidden)
 		return
 	}
+	if !h.resolveSecret(w, r, req.ProjectID, &req.SecretID) {
+		return
+	}
+
+	rejected := []models.ResourceAssociationError{}
+	var verified map[string]services.DiscoveredResource
+	if !req.SkipVerification {
+		arns := make([]string, 0, len(req.Resources))
+		for _, res := range req.Resources {
+			arns = append(arns, res.ARN)
+		}
+		var err error
+		verified, rejected, err = h.verifyResources(r.Context(), req.SecretID, arns)
+		if err != nil {
+			log.Printf("Failed to verify resources: %v", err)
+			http.Error(w, "Failed to verify resources", http.StatusInternalServerError)
+			return
+		}
+	}
 
 	added := 0
 	for _, res := range req.Resources {
@@ -108,22 +175,262 @@ func (h *SyncHandler) AssociateResources(w http.ResponseWriter, r *http.Request)
 			Status:       models.ResourceStatusActive,
 			Metadata:     res.Metadata,
 		}
+		if !req.SkipVerification {
+			d, ok := verified[res.ARN]
+			if !ok {
+				continue
+			}
+			// Trust what AWS reported over the submitted fields
+			resource.ResourceType, resource.Name, resource.Region = d.Type, d.Name, d.Region
+			resource.ManagedBy = d.ManagedBy
+		}
 
 		err := h.resourceRepo.Create(r.Context(), resource)
 		if err != nil {
 			log.Printf("Failed to associate resource %s: %v", res.ARN, err)
+			rejected = append(rejected, models.ResourceAssociationError{ARN: res.ARN, Error: "failed to save resource"})
 			continue
 		}
 		added++
 	}
 
+	details := fmt.Sprintf("Associated %d of %d resources", added, len(req.Resources))
+	if req.SkipVerification {
+		details += " without verification"
+	}
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "associate_resources",
+		ResourceType: "project",
+		ResourceID:   req.ProjectID,
+		ProjectID:    req.ProjectID,
+		Status:       "success",
+		Details:      details,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":         true,
 		"resources_added": added,
+		"errors":          rejected,
 	})
 }
 
+// verifyResources checks that each ARN exists and is visible to the credential. Every
+// resource type is discovered once per region rather than once per resource. It returns
+// the discovered resources by ARN and an error for each ARN that could not be verified.
+func (h *SyncHandler) verifyResources(ctx context.Context, secretID string, arns []string) (map[string]services.DiscoveredResource, []models.ResourceAssociationError, error) {
+	type discoveryKey struct{ resourceType, region string }
+
+	rejected := []models.ResourceAssociationError{}
+	groups := make(map[discoveryKey][]string)
+	var order []discoveryKey
+	for _, resourceARN := range arns {
+		parsed, err := arn.Parse(resourceARN)
+		if err != nil {
+			rejected = append(rejected, models.ResourceAssociationError{ARN: resourceARN, Error: "invalid ARN"})
+			continue
+		}
+		// S3 ARNs have no region; bucket discovery covers every region anyway
+		key := discoveryKey{resourceType: parsed.Service, region: parsed.Region}
+		if key.region == "" {
+			key.region = "us-east-1"
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], resourceARN)
+	}
+	if len(groups) == 0 {
+		return nil, rejected, nil
+	}
+
+	credentials, _, err := h.secretRepo.GetCredentials(ctx, secretID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	verified := make(map[string]services.DiscoveredResource)
+	for _, key := range order {
+		resources, err := h.discovery.DiscoverType(ctx, credentials, key.resourceType, key.region)
+		if err != nil {
+			for _, resourceARN := range groups[key] {
+				rejected = append(rejected, models.ResourceAssociationError{
+					ARN:   resourceARN,
+					Error: fmt.Sprintf("could not verify %s resources in %s: %v", key.resourceType, key.region, err),
+				})
+			}
+			continue
+		}
+
+		found := make(map[string]services.DiscoveredResource, len(resources))
+		for _, res := range resources {
+			found[res.ARN] = res
+		}
+		for _, resourceARN := range groups[key] {
+			if res, ok := found[resourceARN]; ok {
+				verified[resourceARN] = res
+			} else {
+				rejected = append(rejected, models.ResourceAssociationError{ARN: resourceARN, Error: "not found with this credential"})
+			}
+		}
+	}
+	return verified, rejected, nil
+}
+
+// BulkAssociateResources associates resources with a project by ARN. ARNs another
+// project already tracks are copied from its row; the rest are looked up in AWS.
+func (h *SyncHandler) BulkAssociateResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userRole := middleware.GetUserRole(ctx)
+	if userRole != "superadmin" && userRole != "lead" {
+		http.Error(w, "Only leads and superadmins can associate resources", http.StatusForbidden)
+		return
+	}
+
+	var req models.BulkAssociateResourcesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ProjectID == "" {
+		http.Error(w, "project_id is required", http.StatusBadRequest)
+		return
+	}
+
+	arns := []string{}
+	seen := make(map[string]bool)
+	for _, arn := range req.ARNs {
+		arn = strings.TrimSpace(arn)
+		if arn != "" && !seen[arn] {
+			seen[arn] = true
+			arns = append(arns, arn)
+		}
+	}
+	if len(arns) == 0 {
+		http.Error(w, "arns is required", http.StatusBadRequest)
+		return
+	}
+	if !h.resolveSecret(w, r, req.ProjectID, &req.SecretID) {
+		return
+	}
+
+	existing, err := h.resourceRepo.GetByARNs(ctx, arns)
+	if err != nil {
+		log.Printf("Failed to look up resources by ARN: %v", err)
+		http.Error(w, "Failed to look up resources", http.StatusInternalServerError)
+		return
+	}
+	tracked := make(map[string]models.DiscoveredResource)
+	inProject := make(map[string]bool)
+	for _, res := range existing {
+		if res.ProjectID == req.ProjectID {
+			inProject[res.ARN] = true
+		} else if _, ok := tracked[res.ARN]; !ok {
+			tracked[res.ARN] = res
+		}
+	}
+
+	result := models.BulkAssociateResourcesResult{NotFound: []string{}}
+	var unknown []string
+	for _, arn := range arns {
+		if inProject[arn] {
+			result.AlreadyExisted++
+			continue
+		}
+		if res, ok := tracked[arn]; ok {
+			h.associate(ctx, req, res, &result)
+			continue
+		}
+		unknown = append(unknown, arn)
+	}
+
+	if len(unknown) > 0 {
+		discovered, err := h.discoverARNs(ctx, req.SecretID, req.Region)
+		if err != nil {
+			log.Printf("Failed to discover resources: %v", err)
+			http.Error(w, "Failed to discover resources: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+		for _, arn := range unknown {
+			d, ok := discovered[arn]
+			if !ok {
+				result.NotFound = append(result.NotFound, arn)
+				continue
+			}
+			metadata, _ := json.Marshal(d.Metadata)
+			h.associate(ctx, req, models.DiscoveredResource{
+				ARN:          arn,
+				ResourceType: d.Type,
+				Name:         d.Name,
+				Region:       d.Region,
+				Metadata:     metadata,
+				ManagedBy:    d.ManagedBy,
+			}, &result)
+		}
+	}
+
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "associate_resources",
+		ResourceType: "project",
+		ResourceID:   req.ProjectID,
+		ProjectID:    req.ProjectID,
+		Status:       "success",
+		Details: fmt.Sprintf("Bulk associated %d of %d resources (%d already associated, %d not found)",
+			result.Associated, len(arns), result.AlreadyExisted, len(result.NotFound)),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// associate tracks a copy of res for the project of a bulk request; failures are
+// logged and reported as not found
+func (h *SyncHandler) associate(ctx context.Context, req models.BulkAssociateResourcesRequest, res models.DiscoveredResource, result *models.BulkAssociateResourcesResult) {
+	resource := &models.DiscoveredResource{
+		ProjectID:    req.ProjectID,
+		SecretID:     req.SecretID,
+		ARN:          res.ARN,
+		ResourceType: res.ResourceType,
+		Name:         res.Name,
+		Region:       res.Region,
+		Status:       models.ResourceStatusActive,
+		Metadata:     res.Metadata,
+		ManagedBy:    res.ManagedBy,
+	}
+	if err := h.resourceRepo.Create(ctx, resource); err != nil {
+		log.Printf("Failed to associate resource %s: %v", res.ARN, err)
+		result.NotFound = append(result.NotFound, res.ARN)
+		return
+	}
+	result.Associated++
+}
+
+// discoverARNs lists every resource the credential can see, keyed by ARN
+func (h *SyncHandler) discoverARNs(ctx context.Context, secretID, region string) (map[string]services.DiscoveredResource, error) {
+	secret, credentials, err := h.secretRepo.GetByIDWithCredentials(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	if region == "" {
+		region = secret.Region
+	}
+	if region == "" {
+		region = "ap-south-1"
+	}
+
+	resources, err := h.discovery.DiscoverAll(ctx, credentials, region)
+	if err != nil {
+		return nil, err
+	}
+	byARN := make(map[string]services.DiscoveredResource, len(resources))
+	for _, res := range resources {
+		byARN[res.ARN] = res
+	}
+	return byARN, nil
+}
+
 // GetProjectDiscoveredResources gets all discovered resources for a project
 func (h *SyncHandler) GetProjectDiscoveredResources(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// TagVocabularyHandler serves the service tag vocabulary settings
+type TagVocabularyHandler struct {
+	auditRecorder
+	tagRepo *repositories.TagVocabularyRepository
+}
+
+// NewTagVocabularyHandler creates a new TagVocabularyHandler
+func NewTagVocabularyHandler(deps *Deps) *TagVocabularyHandler {
+	return &TagVocabularyHandler{
+		auditRecorder: newAuditRecorder(deps),
+		tagRepo:       deps.TagVocabulary,
+	}
+}
+
+// GetTagVocabulary returns the controlled vocabulary applied to service tags during catalog sync
+func (h *TagVocabularyHandler) GetTagVocabulary(w http.ResponseWriter, r *http.Request) {
+	vocab, err := h.tagRepo.Get(r.Context())
+	if err != nil {
+		log.Printf("Failed to load tag vocabulary: %v", err)
+		http.Error(w, "Failed to load tag vocabulary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vocab)
+}
+
+// UpdateTagVocabulary replaces the tag vocabulary; it takes effect on the next catalog sync
+func (h *TagVocabularyHandler) UpdateTagVocabulary(w http.ResponseWriter, r *http.Request) {
+	var req models.TagVocabulary
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.Normalize(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Strict && len(req.Tags) == 0 {
+		http.Error(w, "strict mode needs at least one tag in the vocabulary", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.tagRepo.Save(r.Context(), &req); err != nil {
+		log.Printf("Failed to save tag vocabulary: %v", err)
+		http.Error(w, "Failed to save tag vocabulary", http.StatusInternalServerError)
+		return
+	}
+
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"enabled": req.Enabled,
+		"strict":  req.Strict,
+		"tags":    len(req.Tags),
+		"aliases": len(req.Aliases),
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "update_tag_vocabulary",
+		ResourceType: "settings",
+		ResourceName: "tag_vocabulary",
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// TeamDigestHandler serves the team digest settings endpoints
+type TeamDigestHandler struct {
+	auditRecorder
+	digestRepo *repositories.DigestRepository
+	userRepo   *repositories.UserRepository
+}
+
+// NewTeamDigestHandler creates a new TeamDigestHandler
+func NewTeamDigestHandler(deps *Deps) *TeamDigestHandler {
+	return &TeamDigestHandler{
+		auditRecorder: newAuditRecorder(deps),
+		digestRepo:    deps.Digests,
+		userRepo:      deps.Users,
+	}
+}
+
+// HandleTeamDigest serves GET and PUT /api/v1/teams/digest?team_id=...
+func (h *TeamDigestHandler) HandleTeamDigest(w http.ResponseWriter, r *http.Request) {
+	teamID := r.URL.Query().Get("team_id")
+	if teamID == "" {
+		http.Error(w, "team_id is required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getTeamDigestSettings(w, r, teamID)
+	case http.MethodPut:
+		h.updateTeamDigestSettings(w, r, teamID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TeamDigestHandler) getTeamDigestSettings(w http.ResponseWriter, r *http.Request, teamID string) {
+	settings, err := h.digestRepo.GetSettings(r.Context(), teamID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load digest settings: %v", err)
+		http.Error(w, "Failed to load digest settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// updateTeamDigestSettings opts a team in or out of the daily digest (superadmin, or a lead of the team)
+func (h *TeamDigestHandler) updateTeamDigestSettings(w http.ResponseWriter, r *http.Request, teamID string) {
+	ctx := r.Context()
+	if status, err := canManageTeam(ctx, h.userRepo, teamID); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var req models.TeamDigestSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.TeamID = teamID
+	if err := validateDigestSettings(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.digestRepo.GetSettings(ctx, teamID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load digest settings: %v", err)
+		http.Error(w, "Failed to load digest settings", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.digestRepo.SaveSettings(ctx, &req); err != nil {
+		log.Printf("Failed to save digest settings: %v", err)
+		http.Error(w, "Failed to save digest settings", http.StatusInternalServerError)
+		return
+	}
+	req.TeamName = current.TeamName
+
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"enabled":          req.Enabled,
+		"timezone":         req.Timezone,
+		"slack":            req.SlackWebhookURL != "",
+		"email_recipients": len(req.EmailRecipients),
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "update_team_digest",
+		ResourceType: "team",
+		ResourceID:   teamID,
+		ResourceName: current.TeamName,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+// canManageTeam allows superadmins and leads who are members of the team
+func canManageTeam(ctx context.Context, userRepo *repositories.UserRepository, teamID string) (int, error) {
+	switch middleware.GetUserRole(ctx) {
+	case string(models.RoleAdmin):
+		return 0, nil
+	case string(models.RoleLead):
+		teamIDs, err := userRepo.GetUserTeamIDs(ctx, middleware.GetUserID(ctx))
+		if err != nil {
+			log.Printf("Failed to load teams for lead: %v", err)
+			return http.StatusInternalServerError, errors.New("Failed to load your teams")
+		}
+		for _, id := range teamIDs {
+			if id == teamID {
+				return 0, nil
+			}
+		}
+		return http.StatusForbidden, errors.New("Forbidden: you are not a member of this team")
+	default:
+		return http.StatusForbidden, errors.New("Forbidden: lead or superadmin access required")
+	}
+}
+
+func validateDigestSettings(s *models.TeamDigestSettings) error {
+	if s.Timezone == "" {
+		s.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return errors.New("timezone must be an IANA name such as Europe/Berlin")
+	}
+
+	if s.SlackWebhookURL != "" {
+		u, err := url.Parse(s.SlackWebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("slack_webhook_url must be an https webhook URL")
+		}
+	}
+
+	recipients := []string{}
+	for _, addr := range s.EmailRecipients {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return errors.New("invalid email recipient: " + addr)
+		}
+		recipients = append(recipients, addr)
+	}
+	s.EmailRecipients = recipients
+
+	if s.Enabled && s.SlackWebhookURL == "" && len(s.EmailRecipients) == 0 {
+		return errors.New("an enabled digest needs a slack_webhook_url or email_recipients")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// TeamNotificationsHandler manages the Slack and webhook channels of a team
+type TeamNotificationsHandler struct {
+	auditRecorder
+	channelRepo *repositories.NotificationChannelRepository
+	teamRepo    *repositories.TeamRepository
+	userRepo    *repositories.UserRepository
+}
+
+// NewTeamNotificationsHandler creates a new TeamNotificationsHandler
+func NewTeamNotificationsHandler(deps *Deps) *TeamNotificationsHandler {
+	return &TeamNotificationsHandler{
+		auditRecorder: newAuditRecorder(deps),
+		channelRepo:   deps.NotificationChannels,
+		teamRepo:      deps.Teams,
+		userRepo:      deps.Users,
+	}
+}
+
+// ListChannels handles GET /api/v1/teams/{id}/notifications (superadmin, or a lead of the team)
+func (h *TeamNotificationsHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	teamID := r.PathValue("id")
+	if status, err := canManageTeam(ctx, h.userRepo, teamID); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	channels, err := h.channelRepo.ListByTeam(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to list notification channels: %v", err)
+		http.Error(w, "Failed to list notification channels", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(channels)
+}
+
+// CreateChannel handles POST /api/v1/teams/{id}/notifications (superadmin, or a lead of the team)
+func (h *TeamNotificationsHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	teamID := r.PathValue("id")
+	if status, err := canManageTeam(ctx, h.userRepo, teamID); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var channel models.NotificationChannel
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := channel.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.teamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+
+	channel.TeamID = teamID
+	channel.CreatedBy = middleware.GetUserEmail(ctx)
+	if err := h.channelRepo.Create(ctx, &channel); err != nil {
+		log.Printf("Failed to create notification channel: %v", err)
+		http.Error(w, "Failed to create notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	// The URL is a credential for Slack webhooks, so only its type is audited
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"channel_id": channel.ID,
+		"type":       channel.Type,
+		"events":     channel.Events,
+	})
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    channel.CreatedBy,
+		Action:       "create_notification_channel",
+		ResourceType: "team",
+		ResourceID:   teamID,
+		ResourceName: team.Name,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(channel)
+}
+
+// DeleteChannel handles DELETE /api/v1/teams/{id}/notifications/{channelID} (superadmin, or a lead of the team)
+func (h *TeamNotificationsHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	teamID := r.PathValue("id")
+	channelID := r.PathValue("channelID")
+	if status, err := canManageTeam(ctx, h.userRepo, teamID); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	err := h.channelRepo.Delete(ctx, teamID, channelID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "Notification channel not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete notification channel: %v", err)
+		http.Error(w, "Failed to delete notification channel", http.StatusInternalServerError)
+		return
+	}
+
+	detailsJSON := redact.JSON(map[string]string{"channel_id": channelID})
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(ctx),
+		Action:       "delete_notification_channel",
+		ResourceType: "team",
+		ResourceID:   teamID,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/catalog"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+)
+
+// GitHubTeamSyncHandler syncs team memberships from GitHub organization teams
+type GitHubTeamSyncHandler struct {
+	auditRecorder
+	teamSync *catalog.GitHubTeamSyncService
+}
+
+// NewGitHubTeamSyncHandler creates a new GitHubTeamSyncHandler
+func NewGitHubTeamSyncHandler(deps *Deps, teamSync *catalog.GitHubTeamSyncService) *GitHubTeamSyncHandler {
+	return &GitHubTeamSyncHandler{
+		auditRecorder: newAuditRecorder(deps),
+		teamSync:      teamSync,
+	}
+}
+
+// syncTeamsRequest is the optional body of POST /api/v1/catalog/sync-teams
+type syncTeamsRequest struct {
+	Org string `json:"org"` // Defaults to the owner of the catalog repository
+}
+
+// SyncTeams replaces the members of each portalight team with the members of the
+// GitHub team of the same name. The GitHub token needs the read:org scope.
+func (h *GitHubTeamSyncHandler) SyncTeams(w http.ResponseWriter, r *http.Request) {
+	var req syncTeamsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userEmail := middleware.GetUserEmail(r.Context())
+	result, err := h.teamSync.Sync(r.Context(), req.Org, userEmail)
+	if err != nil {
+		log.Printf("❌ [Team Sync] %v", err)
+		h.recordAudit(r.Context(), models.AuditLog{
+			UserEmail:    userEmail,
+			Action:       "sync_github_teams",
+			ResourceType: "team",
+			ResourceName: req.Org,
+			Status:       "failed",
+			Details:      err.Error(),
+		})
+		http.Error(w, "Failed to sync teams from GitHub: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"org":             result.Org,
+		"teams":           len(result.Teams),
+		"unmatched_teams": result.UnmatchedTeams,
+		"unknown_users":   len(result.UnknownUsers),
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    userEmail,
+		Action:       "sync_github_teams",
+		ResourceType: "team",
+		ResourceName: result.Org,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -2,32 +2,53 @@ package handlers
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
 	"github.com/portalight/backend/internal/repositories"
 )
 
-// GetTeams returns all teams
-func GetTeams(w http.ResponseWriter, r *http.Request) {
+// TeamsHandler serves the team endpoints
+type TeamsHandler struct {
+	auditRecorder
+	teamRepo *repositories.TeamRepository
+	userRepo *repositories.UserRepository
+}
+
+// NewTeamsHandler creates a new TeamsHandler
+func NewTeamsHandler(deps *Deps) *TeamsHandler {
+	return &TeamsHandler{
+		auditRecorder: newAuditRecorder(deps),
+		teamRepo:      deps.Teams,
+		userRepo:      deps.Users,
+	}
+}
+
+// GetTeams returns one page of teams as {"items": [...], "total": N}
+func (h *TeamsHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	teamRepo := &repositories.TeamRepository{}
 
-	teams, err := teamRepo.GetAll(ctx)
+	opts, err := parseListOptions(r)
 	if err != nil {
-		http.Error(w, "Failed to fetch teams", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(teams)
+	teams, total, err := h.teamRepo.GetAll(ctx, opts)
+	writeCachedList(w, r, teams, total, err, "Failed to fetch teams")
 }
 
 // CreateTeam creates a new team
-func CreateTeam(w http.ResponseWriter, r *http.Request) {
+func (h *TeamsHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var team models.Team
 	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -37,9 +58,8 @@ func CreateTeam(w http.ResponseWriter, r *http.Request) {
 	team.CreatedAt = time.Now()
 
 	ctx := context.Background()
-	teamRepo := &repositories.TeamRepository{}
 
-	if err := teamRepo.Create(ctx, &team); err != nil {
+	if err := h.teamRepo.Create(ctx, &team); err != nil {
 		http.Error(w, "Failed to create team", http.StatusInternalServerError)
 		return
 	}
@@ -48,14 +68,13 @@ func CreateTeam(w http.ResponseWriter, r *http.Request) {
 	userEmail := middleware.GetUserEmail(r.Context())
 	userName := userEmail
 	if userEmail != "" {
-		userRepo := &repositories.UserRepository{}
-		user, err := userRepo.FindByEmail(ctx, userEmail)
+		user, err := h.userRepo.FindByEmail(ctx, userEmail)
 		if err == nil {
 			userName = user.Name
 		}
 	}
 
-	detailsJSON, _ := json.Marshal(map[string]interface{}{
+	detailsJSON := redact.JSON(map[string]interface{}{
 		"team_id":     team.ID,
 		"team_name":   team.Name,
 		"description": team.Description,
@@ -68,10 +87,10 @@ func CreateTeam(w http.ResponseWriter, r *http.Request) {
 		ResourceType: "team",
 		ResourceID:   team.ID,
 		ResourceName: team.Name,
-		Details:      string(detailsJSON),
+		Details:      detailsJSON,
 		Status:       "success",
 	}
-	CreateAuditLogEntry(auditLog)
+	h.recordAudit(r.Context(), auditLog)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -79,7 +98,7 @@ func CreateTeam(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteTeam deletes a team
-func DeleteTeam(w http.ResponseWriter, r *http.Request) {
+func (h *TeamsHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
 	// Extract team ID from URL
 	teamID := r.URL.Path[len("/api/v1/teams/"):]
 	if len(teamID) > 0 && teamID[len(teamID)-1] == '/' {
@@ -87,9 +106,8 @@ func DeleteTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	teamRepo := &repositories.TeamRepository{}
 
-	if err := teamRepo.Delete(ctx, teamID); err != nil {
+	if err := h.teamRepo.Delete(ctx, teamID); err != nil {
 		http.Error(w, "Failed to delete team", http.StatusInternalServerError)
 		return
 	}
@@ -98,7 +116,7 @@ func DeleteTeam(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateTeamMembers updates members of a team
-func UpdateTeamMembers(w http.ResponseWriter, r *http.Request) {
+func (h *TeamsHandler) UpdateTeamMembers(w http.ResponseWriter, r *http.Request) {
 	var updateData struct {
 		TeamID    string   `json:"team_id"`
 		MemberIDs []string `json:"member_ids"`
@@ -110,21 +128,213 @@ func UpdateTeamMembers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	teamRepo := &repositories.TeamRepository{}
 
 	// Update team members
-	if err := teamRepo.UpdateTeamMembers(ctx, updateData.TeamID, updateData.MemberIDs); err != nil {
+	diff, err := h.teamRepo.UpdateTeamMembers(ctx, updateData.TeamID, updateData.MemberIDs)
+	if err != nil {
 		http.Error(w, "Failed to update team members", http.StatusInternalServerError)
 		return
 	}
 
 	// Return updated team
-	team, err := teamRepo.FindByID(ctx, updateData.TeamID)
+	team, err := h.teamRepo.FindByID(ctx, updateData.TeamID)
 	if err != nil {
 		http.Error(w, "Team not found", http.StatusNotFound)
 		return
 	}
 
+	h.auditMembershipChanges(ctx, r, team, diff)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(team)
 }
+
+// maxMemberImportSize bounds CSV uploads to the member import endpoint
+const maxMemberImportSize = 1 << 20
+
+// MemberImportResult is the response of ImportTeamMembers
+type MemberImportResult struct {
+	Added    int      `json:"added"`     // Users who were not members yet
+	Skipped  int      `json:"skipped"`   // Entries naming a user who already was a member, or repeated in the file
+	NotFound []string `json:"not_found"` // Entries that match no user
+}
+
+// ImportTeamMembers handles POST /api/v1/teams/{id}/members/import (superadmin): a
+// multipart upload ("file") of a CSV with one email or GitHub username per line.
+// Resolved users are added to the team; current members are kept. A header line
+// ("email" or "github_username") is ignored.
+func (h *TeamsHandler) ImportTeamMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	teamID := r.PathValue("id")
+
+	team, err := h.teamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		http.Error(w, "Team not found", http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxMemberImportSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Multipart upload needs a \"file\" field of at most 1 MB", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	entries, err := readMemberCSV(file)
+	if err != nil {
+		http.Error(w, "Invalid CSV: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	memberIDs, err := h.teamRepo.GetTeamMemberIDs(ctx, team.ID)
+	if err != nil {
+		http.Error(w, "Failed to get team members", http.StatusInternalServerError)
+		return
+	}
+	isMember := make(map[string]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		isMember[id] = true
+	}
+
+	result := MemberImportResult{NotFound: []string{}}
+	for _, entry := range entries {
+		user, err := h.resolveMember(ctx, entry)
+		if err != nil {
+			result.NotFound = append(result.NotFound, entry)
+			continue
+		}
+		if isMember[user.ID] {
+			result.Skipped++
+			continue
+		}
+		isMember[user.ID] = true
+		memberIDs = append(memberIDs, user.ID)
+		result.Added++
+	}
+
+	if result.Added > 0 {
+		if _, err := h.teamRepo.UpdateTeamMembers(ctx, team.ID, memberIDs); err != nil {
+			log.Printf("Failed to import members of team %s: %v", team.ID, err)
+			http.Error(w, "Failed to update team members", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	userEmail := middleware.GetUserEmail(ctx)
+	userName := userEmail
+	if user, err := h.userRepo.FindByEmail(ctx, userEmail); err == nil {
+		userName = user.Name
+	}
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"team_name": team.Name,
+		"added":     result.Added,
+		"skipped":   result.Skipped,
+		"not_found": len(result.NotFound),
+	})
+	h.recordAudit(ctx, models.AuditLog{
+		UserEmail:    userEmail,
+		UserName:     userName,
+		Action:       "import_team_members",
+		ResourceType: "team",
+		ResourceID:   team.ID,
+		ResourceName: team.Name,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// readMemberCSV returns the first column of every line of a member import,
+// without blank entries and a leading header line
+func readMemberCSV(file io.Reader) ([]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Extra columns are ignored
+	reader.TrimLeadingSpace = true
+
+	var entries []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := strings.TrimSpace(record[0])
+		if entry == "" {
+			continue
+		}
+		if len(entries) == 0 && (strings.EqualFold(entry, "email") || strings.EqualFold(entry, "github_username")) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// resolveMember finds the user an import entry names: an email address, or
+// otherwise a GitHub username (with or without a leading @)
+func (h *TeamsHandler) resolveMember(ctx context.Context, entry string) (*models.User, error) {
+	if strings.Contains(entry, "@") && !strings.HasPrefix(entry, "@") {
+		return h.userRepo.FindByEmail(ctx, entry)
+	}
+	return h.userRepo.FindByGithubUsername(ctx, strings.TrimPrefix(entry, "@"))
+}
+
+// auditMembershipChanges describes the membership update on the request's audit
+// entry and records one more entry per member added to or removed from the team
+func (h *TeamsHandler) auditMembershipChanges(ctx context.Context, r *http.Request, team *models.Team, diff *models.TeamMembershipDiff) {
+	userEmail := middleware.GetUserEmail(r.Context())
+	userName := userEmail
+	if userEmail != "" {
+		user, err := h.userRepo.FindByEmail(ctx, userEmail)
+		if err == nil {
+			userName = user.Name
+		}
+	}
+
+	detailsJSON := redact.JSON(map[string]interface{}{
+		"added":   diff.Added,
+		"removed": diff.Removed,
+	})
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    userEmail,
+		UserName:     userName,
+		Action:       "update_team_members",
+		ResourceType: "team",
+		ResourceID:   team.ID,
+		ResourceName: team.Name,
+		Details:      detailsJSON,
+		Status:       "success",
+	})
+
+	// One entry per member, so a user's team history can be queried by action
+	record := func(action, memberID string) {
+		detailsJSON := redact.JSON(map[string]interface{}{
+			"team_id":   team.ID,
+			"team_name": team.Name,
+			"user_id":   memberID,
+		})
+
+		h.recordAudit(r.Context(), models.AuditLog{
+			UserEmail:    userEmail,
+			UserName:     userName,
+			Action:       action,
+			ResourceType: "team",
+			ResourceID:   team.ID,
+			ResourceName: team.Name,
+			Details:      detailsJSON,
+			Status:       "success",
+		})
+	}
+
+	for _, memberID := range diff.Added {
+		record("add_team_member", memberID)
+	}
+	for _, memberID := range diff.Removed {
+		record("remove_team_member", memberID)
+	}
+}
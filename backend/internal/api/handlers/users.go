@@ -3,53 +3,103 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/portalight/backend/internal/api/middleware"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
 )
 
-// GetUsers returns all users
-func GetUsers(w http.ResponseWriter, r *http.Request) {
+// UsersHandler serves the user endpoints
+type UsersHandler struct {
+	auditRecorder
+	userRepo *repositories.UserRepository
+}
+
+// NewUsersHandler creates a new UsersHandler
+func NewUsersHandler(deps *Deps) *UsersHandler {
+	return &UsersHandler{
+		auditRecorder: newAuditRecorder(deps),
+		userRepo:      deps.Users,
+	}
+}
+
+// GetUsers returns one page of users as {"items": [...], "total": N}
+func (h *UsersHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
-	users, err := userRepo.GetAll(ctx)
+	opts, err := parseListOptions(r)
 	if err != nil {
-		http.Error(w, "Failed to fetch users", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	users, total, err := h.userRepo.GetAll(ctx, opts)
+	writeCachedList(w, r, users, total, err, "Failed to fetch users")
 }
 
-// CreateUser creates a new user
-func CreateUser(w http.ResponseWriter, r *http.Request) {
+// CreateUser creates a new user, superadmin only: the request sets the role and may
+// link a GitHub or GitLab account, whose next login then signs in as this user
+func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if middleware.GetUserRole(r.Context()) != string(models.RoleAdmin) {
+		http.Error(w, "Forbidden: superadmin access required", http.StatusForbidden)
+		return
+	}
+
 	var user models.User
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if user.Role == "" {
+		user.Role = models.RoleDev
+	}
+	if user.Role != models.RoleAdmin && user.Role != models.RoleLead && user.Role != models.RoleDev {
+		http.Error(w, "Invalid role: "+string(user.Role), http.StatusBadRequest)
+		return
+	}
+	user.ID = ""
 	user.CreatedAt = time.Now()
 
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
-	if err := userRepo.Create(ctx, &user); err != nil {
+	if err := h.userRepo.Create(ctx, &user); err != nil {
 		http.Error(w, "Failed to create user", http.StatusInternalServerError)
 		return
 	}
 
+	details := []string{"role: " + string(user.Role)}
+	if user.GithubID != 0 {
+		details = append(details, fmt.Sprintf("github_id: %d", user.GithubID))
+	}
+	if user.GitlabID != 0 {
+		details = append(details, fmt.Sprintf("gitlab_id: %d", user.GitlabID))
+	}
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "create_user",
+		ResourceType: "user",
+		ResourceID:   user.ID,
+		ResourceName: user.Email,
+		Status:       "success",
+		Details:      strings.Join(details, "; "),
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(user)
 }
 
-// UpdateUser updates a user
-func UpdateUser(w http.ResponseWriter, r *http.Request) {
+// UpdateUser updates a user.
+// Superadmins may change anything; leads may only change memberships of their own
+// teams for users who share a team with them; everyone else may only rename themselves.
+func (h *UsersHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	var updateData struct {
 		Role    *string   `json:"role"`
 		TeamIDs *[]string `json:"team_ids"`
@@ -64,19 +114,65 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Extract user ID from URL path
 	userID := r.URL.Path[len("/api/v1/users/"):]
 
+	callerID := middleware.GetUserID(r.Context())
+	callerRole := middleware.GetUserRole(r.Context())
+	if callerID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	ctx := context.Background()
-	userRepo := &repositories.UserRepository{}
 
 	// Find user
-	user, err := userRepo.FindByID(ctx, userID)
+	user, err := h.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
 	}
+	previousRole := user.Role
+
+	switch callerRole {
+	case string(models.RoleAdmin):
+	case string(models.RoleLead):
+		if updateData.Role != nil && models.Role(*updateData.Role) != user.Role {
+			http.Error(w, "Forbidden: only superadmins can change roles", http.StatusForbidden)
+			return
+		}
+		if updateData.Name != nil && userID != callerID {
+			http.Error(w, "Forbidden: you can only rename yourself", http.StatusForbidden)
+			return
+		}
+		if updateData.TeamIDs != nil {
+			teamIDs, status, err := leadTeamUpdate(ctx, h.userRepo, callerID, user, *updateData.TeamIDs)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			updateData.TeamIDs = &teamIDs
+		}
+	default:
+		if userID != callerID {
+			http.Error(w, "Forbidden: you can only update your own profile", http.StatusForbidden)
+			return
+		}
+		if updateData.Role != nil && models.Role(*updateData.Role) != user.Role {
+			http.Error(w, "Forbidden: only superadmins can change roles", http.StatusForbidden)
+			return
+		}
+		if updateData.TeamIDs != nil {
+			http.Error(w, "Forbidden: only leads and superadmins can change team membership", http.StatusForbidden)
+			return
+		}
+	}
 
 	// Update fields
 	if updateData.Role != nil {
-		user.Role = models.Role(*updateData.Role)
+		role := models.Role(*updateData.Role)
+		if role != models.RoleAdmin && role != models.RoleLead && role != models.RoleDev {
+			http.Error(w, "Invalid role: "+*updateData.Role, http.StatusBadRequest)
+			return
+		}
+		user.Role = role
 	}
 	if updateData.TeamIDs != nil {
 		user.TeamIDs = *updateData.TeamIDs
@@ -85,18 +181,134 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		user.Name = *updateData.Name
 	}
 
-	// Save to database
-	if err := userRepo.Update(ctx, user); err != nil {
+	// Save to database; demoting the last superadmin would lock everyone out of administration
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repositories.ErrLastSuperadmin) {
+			http.Error(w, "Cannot remove the last remaining superadmin", http.StatusConflict)
+			return
+		}
 		http.Error(w, "Failed to update user", http.StatusInternalServerError)
 		return
 	}
+	if updateData.TeamIDs != nil {
+		if err := h.userRepo.SetTeamIDs(ctx, user.ID, user.TeamIDs); err != nil {
+			log.Printf("Failed to update team memberships for user %s: %v", user.ID, err)
+			http.Error(w, "Failed to update team memberships", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	details := []string{}
+	if user.Role != previousRole {
+		details = append(details, fmt.Sprintf("role: %s -> %s", previousRole, user.Role))
+	}
+	if updateData.TeamIDs != nil {
+		details = append(details, "team_ids: "+strings.Join(user.TeamIDs, ","))
+	}
+	if updateData.Name != nil {
+		details = append(details, "name: "+user.Name)
+	}
+	h.recordAudit(r.Context(), models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "update_user",
+		ResourceType: "user",
+		ResourceID:   user.ID,
+		ResourceName: user.Email,
+		Status:       "success",
+		Details:      strings.Join(details, "; "),
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
 
-// DeleteUser deletes a user
-func DeleteUser(w http.ResponseWriter, r *http.Request) {
-	// Mock implementation
-	w.WriteHeader(http.StatusOK)
+// leadTeamUpdate restricts a lead's team change to the teams they belong to.
+// Memberships of the target in other teams are kept as they are.
+func leadTeamUpdate(ctx context.Context, userRepo *repositories.UserRepository, leadID string, target *models.User, requested []string) ([]string, int, error) {
+	leadTeamIDs, err := userRepo.GetUserTeamIDs(ctx, leadID)
+	if err != nil {
+		log.Printf("Failed to load teams for lead %s: %v", leadID, err)
+		return nil, http.StatusInternalServerError, errors.New("Failed to load your teams")
+	}
+	leadTeams := make(map[string]bool, len(leadTeamIDs))
+	for _, id := range leadTeamIDs {
+		leadTeams[id] = true
+	}
+
+	sharesTeam := false
+	for _, id := range target.TeamIDs {
+		if leadTeams[id] {
+			sharesTeam = true
+			break
+		}
+	}
+	if !sharesTeam && target.ID != leadID {
+		return nil, http.StatusForbidden, errors.New("Forbidden: user is not a member of your teams")
+	}
+
+	var teamIDs []string
+	for _, id := range target.TeamIDs {
+		if !leadTeams[id] {
+			teamIDs = append(teamIDs, id)
+		}
+	}
+	for _, id := range requested {
+		if !leadTeams[id] {
+			return nil, http.StatusForbidden, fmt.Errorf("Forbidden: you are not a member of team %s", id)
+		}
+		teamIDs = append(teamIDs, id)
+	}
+	if teamIDs == nil {
+		teamIDs = []string{}
+	}
+	return teamIDs, 0, nil
+}
+
+// DeleteUser deletes a user, superadmin only
+func (h *UsersHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if middleware.GetUserRole(r.Context()) != string(models.RoleAdmin) {
+		http.Error(w, "Forbidden: superadmin access required", http.StatusForbidden)
+		return
+	}
+
+	userID := r.URL.Path[len("/api/v1/users/"):]
+	callerID := middleware.GetUserID(r.Context())
+	if userID == callerID {
+		http.Error(w, "You cannot delete your own account", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+
+	user, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	auditLog := models.AuditLog{
+		UserEmail:    middleware.GetUserEmail(r.Context()),
+		Action:       "delete_user",
+		ResourceType: "user",
+		ResourceID:   user.ID,
+		ResourceName: user.Email,
+		Status:       "success",
+		Details:      fmt.Sprintf("Deleted %s with role %s", user.Name, user.Role),
+	}
+
+	if err := h.userRepo.Delete(ctx, user.ID, callerID); err != nil {
+		if errors.Is(err, repositories.ErrLastSuperadmin) {
+			http.Error(w, "Cannot remove the last remaining superadmin", http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to delete user %s: %v", user.ID, err)
+		auditLog.Status = "failed"
+		auditLog.Details = err.Error()
+		h.recordAudit(r.Context(), auditLog)
+		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
+		return
+	}
+	h.recordAudit(r.Context(), auditLog)
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -17,20 +17,27 @@ import (
 )
 
 type GitHubWebhookHandler struct {
-	syncer     *catalog.Syncer
-	configRepo *repositories.GitHubConfigRepository
+	syncer      *catalog.Syncer
+	configRepo  *repositories.GitHubConfigRepository
+	projectRepo *repositories.ProjectRepository
+	docsRepo    *repositories.ProjectDocsRepository
 }
 
-func NewGitHubWebhookHandler(syncer *catalog.Syncer, configRepo *repositories.GitHubConfigRepository) *GitHubWebhookHandler {
+func NewGitHubWebhookHandler(deps *Deps, syncer *catalog.Syncer) *GitHubWebhookHandler {
 	return &GitHubWebhookHandler{
-		syncer:     syncer,
-		configRepo: configRepo,
+		syncer:      syncer,
+		configRepo:  deps.GitHubConfig,
+		projectRepo: deps.Projects,
+		docsRepo:    deps.ProjectDocs,
 	}
 }
 
 // GitHubPushEvent represents the relevant parts of a GitHub push webhook
 type GitHubPushEvent struct {
-	Ref     string `json:"ref"`
+	Ref        string `json:"ref"`
+	HeadCommit struct {
+		ID string `json:"id"` // SHA the catalog sync status is reported on
+	} `json:"head_commit"`
 	Commits []struct {
 		Added    []string `json:"added"`
 		Modified []string `json:"modified"`
@@ -66,10 +73,10 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Validate webhook signature if secret is configured
+	// Validate webhook signature if secret is configured; unsigned deliveries are rejected then
 	signature := r.Header.Get("X-Hub-Signature-256")
-	if signature != "" && config.WebhookSecret != "" {
-		if !validateSignature(body, signature, config.WebhookSecret) {
+	if config.WebhookSecret != "" {
+		if signature == "" || !validateSignature(body, signature, config.WebhookSecret) {
 			log.Printf("❌ [Webhook] Invalid signature")
 			http.Error(w, "Invalid signature", http.StatusUnauthorized)
 			return
@@ -80,6 +87,11 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 	eventType := r.Header.Get("X-GitHub-Event")
 	log.Printf("📥 [Webhook] Received %s event from GitHub", eventType)
 
+	// Track deliveries so operators can see whether webhooks are arriving
+	if err := h.configRepo.RecordWebhookDelivery(context.Background(), eventType, r.Header.Get("X-GitHub-Delivery")); err != nil {
+		log.Printf("⚠️ [Webhook] Failed to record delivery: %v", err)
+	}
+
 	// Only process push events
 	if eventType != "push" {
 		log.Printf("ℹ️ [Webhook] Ignoring %s event", eventType)
@@ -105,24 +117,39 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Docs files referenced by projects re-sync the catalog files naming them
+	docsPaths, err := h.docsRepo.CatalogPathsByDocsPath(context.Background())
+	if err != nil {
+		log.Printf("⚠️ [Webhook] Failed to load project docs paths: %v", err)
+	}
+
 	// Collect all changed files in the projects path
 	changedFiles := make(map[string]bool)
 	for _, commit := range pushEvent.Commits {
 		for _, file := range commit.Added {
-			if isYAMLInProjectsPath(file, config.ProjectsPath) {
+			if isYAMLInProjectsPaths(file, config.ProjectsPaths) {
 				changedFiles[file] = true
 			}
 		}
 		for _, file := range commit.Modified {
-			if isYAMLInProjectsPath(file, config.ProjectsPath) {
+			if isYAMLInProjectsPaths(file, config.ProjectsPaths) {
 				changedFiles[file] = true
 			}
 		}
 		// Note: We don't handle removed files yet - projects remain in DB
+
+		// A removed docs file is synced too, so the failed sync reports it
+		for _, files := range [][]string{commit.Added, commit.Modified, commit.Removed} {
+			for _, file := range files {
+				for _, catalogPath := range docsPaths[file] {
+					changedFiles[catalogPath] = true
+				}
+			}
+		}
 	}
 
 	if len(changedFiles) == 0 {
-		log.Printf("ℹ️ [Webhook] No catalog YAML files changed in %s", config.ProjectsPath)
+		log.Printf("ℹ️ [Webhook] No catalog YAML or project docs files changed in %s", strings.Join(config.ProjectsPaths, ", "))
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]string{"message": "No catalog files changed"})
 		return
@@ -130,8 +157,11 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 
 	log.Printf("🔄 [Webhook] Found %d changed catalog files, triggering sync", len(changedFiles))
 
-	// Need project repository to look up existing projects
-	projectRepo := &repositories.ProjectRepository{}
+	// One status covers all files of the push; statuses share a context, so a
+	// per-file status would let a later success hide an earlier failure
+	sha := pushEvent.HeadCommit.ID
+	h.reportCommitStatus(sha, "running", fmt.Sprintf("Syncing %d catalog file(s)", len(changedFiles)))
+	var synced, failed []string
 
 	// Trigger sync for each changed file
 	results := make([]map[string]interface{}, 0)
@@ -143,7 +173,7 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 		}
 
 		// Look up existing project by catalog_file_path
-		existingProject, err := projectRepo.FindByCatalogPath(context.Background(), file)
+		existingProject, err := h.projectRepo.FindByCatalogPath(context.Background(), file)
 		if err != nil || existingProject == nil {
 			// Project doesn't exist yet - skip (must be manually imported)
 			log.Printf("ℹ️ [Webhook] No existing project for %s, skipping (new projects must be manually imported)", file)
@@ -162,7 +192,9 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 			log.Printf("❌ [Webhook] Failed to sync %s: %v", file, err)
 			result["status"] = "failed"
 			result["error"] = err.Error()
+			failed = append(failed, file)
 		} else {
+			synced = append(synced, file)
 			log.Printf("✅ [Webhook] Successfully synced %s -> %s", file, history.ProjectName)
 			result["status"] = history.Status
 			result["project_name"] = history.ProjectName
@@ -171,6 +203,15 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 		results = append(results, result)
 	}
 
+	switch {
+	case len(failed) > 0:
+		h.reportCommitStatus(sha, "failed", fmt.Sprintf("Sync failed for %s", strings.Join(failed, ", ")))
+	case len(synced) > 0:
+		h.reportCommitStatus(sha, "success", fmt.Sprintf("Synced %d catalog file(s)", len(synced)))
+	default:
+		h.reportCommitStatus(sha, "success", "No imported projects changed; new catalog files must be imported in Portalight")
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -179,6 +220,17 @@ func (h *GitHubWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Requ
 	})
 }
 
+// reportCommitStatus sets the catalog sync status on the pushed commit.
+// Failures (e.g. a token without repo:status scope) are logged, never fatal.
+func (h *GitHubWebhookHandler) reportCommitStatus(sha string, syncStatus string, description string) {
+	if sha == "" {
+		return
+	}
+	if err := h.syncer.ReportCommitStatus(context.Background(), sha, syncStatus, description); err != nil {
+		log.Printf("⚠️ [Webhook] Failed to report commit status for %s: %v", sha, err)
+	}
+}
+
 // validateSignature validates the GitHub webhook signature
 func validateSignature(payload []byte, signature string, secret string) bool {
 	// GitHub sends signatures in format: sha256=<hash>
@@ -195,15 +247,23 @@ func validateSignature(payload []byte, signature string, secret string) bool {
 	return hmac.Equal([]byte(actualMAC), []byte(expectedMAC))
 }
 
-// isYAMLInProjectsPath checks if a file is a YAML file in the projects path
-func isYAMLInProjectsPath(file string, projectsPath string) bool {
-	// Normalize paths
-	if !strings.HasSuffix(projectsPath, "/") {
-		projectsPath += "/"
+// isYAMLInProjectsPaths checks if a file is a YAML file in any of the projects paths
+func isYAMLInProjectsPaths(file string, projectsPaths []string) bool {
+	for _, projectsPath := range projectsPaths {
+		if isYAMLInProjectsPath(file, projectsPath) {
+			return true
+		}
 	}
+	return false
+}
+
+// isYAMLInProjectsPath checks if a file is a YAML file in the projects path.
+// The path may also name a single catalog file.
+func isYAMLInProjectsPath(file string, projectsPath string) bool {
+	projectsPath = strings.TrimSuffix(projectsPath, "/")
 
-	// Check if file is in projects path
-	if !strings.HasPrefix(file, projectsPath) {
+	// Check if file is the configured file or in the projects path
+	if file != projectsPath && !strings.HasPrefix(file, projectsPath+"/") {
 		return false
 	}
 
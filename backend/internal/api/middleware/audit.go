@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// auditedPathPrefix is where mutating calls are audited
+const auditedPathPrefix = "/api/v1/"
+
+// anonymousActor is recorded for audited calls without a signed-in user (e.g. webhooks)
+const anonymousActor = "anonymous"
+
+type auditKey struct{}
+
+// auditState is the audit entry of one request, shared with its handler through the context
+type auditState struct {
+	mu       sync.Mutex
+	entry    models.AuditLog
+	enriched bool
+	skipped  bool
+	written  bool // Set once the middleware has recorded (or dropped) the entry
+}
+
+// Audit records one audit log entry for every POST, PUT, PATCH and DELETE under
+// /api/v1 that matched a route: actor, route pattern, target taken from the path,
+// response status, latency and request ID. Handlers describe the action with
+// EnrichAudit instead of writing their own entry. Request bodies are never read, so
+// credentials and tokens sent to the API cannot end up in the audit log.
+func Audit(repo *repositories.AuditLogRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r.Method) || !strings.HasPrefix(r.URL.Path, auditedPathPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			r, route := withRoute(r)
+			state := &auditState{}
+			r = r.WithContext(context.WithValue(r.Context(), auditKey{}, state))
+			sw := NewStatusCapturingWriter(w)
+
+			next.ServeHTTP(sw, r)
+
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			state.written = true
+			// Unknown routes are not actions, so they are left to the request log
+			if state.skipped || *route == unmatchedRoute {
+				return
+			}
+
+			entry := state.entry
+			if email := GetUserEmail(r.Context()); email != "" {
+				if entry.UserEmail != email {
+					entry.UserName = ""
+				}
+				entry.UserEmail = email
+			} else if entry.UserEmail == "" {
+				entry.UserEmail = anonymousActor
+			}
+			entry.Route = r.Method + " " + *route
+			entry.RequestID = GetRequestID(r.Context())
+			entry.StatusCode = sw.StatusCode
+			entry.DurationMs = time.Since(start).Milliseconds()
+			if entry.Action == "" {
+				entry.Action = entry.Route
+			}
+			if entry.ResourceType == "" && entry.ResourceID == "" {
+				entry.ResourceType, entry.ResourceID = auditTarget(*route, r.URL.Path)
+			}
+			if entry.Status == "" {
+				entry.Status = "success"
+				if sw.StatusCode >= http.StatusBadRequest {
+					entry.Status = "failed"
+				}
+			}
+
+			if err := repo.Create(context.Background(), &entry); err != nil {
+				log.Printf("Failed to record audit log for %s: %v", entry.Route, err)
+			}
+		})
+	}
+}
+
+// EnrichAudit fills the audit entry the middleware records for the current request
+// with the non-empty fields of entry, leaving the rest derived from the request. It
+// returns false when there is no entry left to enrich: the request is not audited,
+// the handler already enriched it, or the entry was already recorded. The caller
+// should then record entry on its own.
+func EnrichAudit(ctx context.Context, entry models.AuditLog) bool {
+	state, ok := ctx.Value(auditKey{}).(*auditState)
+	if !ok {
+		return false
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.enriched || state.written {
+		return false
+	}
+	state.enriched = true
+	state.entry = entry
+	return true
+}
+
+// SkipAudit stops the audit middleware from recording the current request, for POST
+// endpoints that only read
+func SkipAudit(ctx context.Context) {
+	if state, ok := ctx.Value(auditKey{}).(*auditState); ok {
+		state.mu.Lock()
+		state.skipped = true
+		state.mu.Unlock()
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditTarget derives the resource type and ID from the matched route: the last path
+// wildcard is the ID and the literal segment before it the type, so
+// /api/v1/services/{id}/links/{linkID} targets ("links", <linkID>). Routes without a
+// wildcard only yield a type.
+func auditTarget(pattern, path string) (string, string) {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	resourceType, resourceID, literal := "", "", ""
+	for i, segment := range patternSegments {
+		if !strings.HasPrefix(segment, "{") {
+			literal = segment
+			continue
+		}
+		if i >= len(pathSegments) {
+			break
+		}
+		resourceType, resourceID = literal, pathSegments[i]
+		if strings.HasSuffix(segment, "...}") {
+			resourceID = strings.Join(pathSegments[i:], "/")
+			break
+		}
+	}
+	if resourceID == "" {
+		resourceType = literal
+	}
+	return resourceType, resourceID
+}
@@ -3,11 +3,14 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/portalight/backend/internal/config"
+	"github.com/portalight/backend/internal/logging"
+	"github.com/portalight/backend/internal/repositories"
 )
 
 type Claims struct {
@@ -23,9 +26,11 @@ const (
 	UserIDKey    contextKey = "userID"
 	UserEmailKey contextKey = "email"
 	UserRoleKey  contextKey = "userRole"
+	ClaimsKey    contextKey = "claims"
 )
 
-func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+// AuthMiddleware verifies the bearer JWT and rejects tokens found in revokedTokens
+func AuthMiddleware(cfg *config.Config, revokedTokens *repositories.RevokedTokenRepository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -50,10 +55,29 @@ func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 				return
 			}
 
+			// Reject tokens revoked by logout or refresh
+			if claims.ID != "" {
+				revoked, err := revokedTokens.IsRevoked(r.Context(), claims.ID)
+				if err != nil {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					json.NewEncoder(w).Encode(map[string]string{"error": "Failed to verify token"})
+					return
+				}
+				if revoked {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					json.NewEncoder(w).Encode(map[string]string{"error": "Token has been revoked"})
+					return
+				}
+			}
+
 			// Set user info in context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
 			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, ClaimsKey, claims)
+			ctx = logging.With(ctx, slog.String("user_id", claims.UserID))
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -83,3 +107,27 @@ func GetUserRole(ctx context.Context) string {
 	}
 	return ""
 }
+
+// GetClaims returns the validated token claims from the context
+func GetClaims(ctx context.Context) *Claims {
+	if val, ok := ctx.Value(ClaimsKey).(*Claims); ok {
+		return val
+	}
+	return nil
+}
+
+// RequireRole rejects requests whose user role is not one of roles
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := GetUserRole(r.Context())
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			http.Error(w, "Forbidden: requires "+strings.Join(roles, " or ")+" role", http.StatusForbidden)
+		})
+	}
+}
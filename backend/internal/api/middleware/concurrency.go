@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// concurrencyExemptPaths are never limited so health checks keep working under load
+var concurrencyExemptPaths = []string{"/health", "/metrics"}
+
+var (
+	requestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "portalight_http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served, by concurrency limit",
+	}, []string{"limit"})
+	requestsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "portalight_http_requests_rejected_total",
+		Help: "Number of HTTP requests rejected with 503 because a concurrency limit was reached",
+	}, []string{"limit"})
+)
+
+func init() {
+	prometheus.MustRegister(requestsInFlight, requestsRejected)
+}
+
+// ConcurrencyLimits configures the in-flight request caps
+type ConcurrencyLimits struct {
+	Global int            // Max in-flight requests across all routes (0 disables the cap)
+	Routes map[string]int // Stricter caps for expensive routes, keyed by exact path
+}
+
+// slots is a non-blocking counting semaphore
+type slots chan struct{}
+
+func (s slots) tryAcquire() bool {
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s slots) release() {
+	<-s
+}
+
+// ConcurrencyLimit rejects requests with 503 and Retry-After once the global or
+// per-route in-flight cap is reached, instead of queueing them on the database pool
+func ConcurrencyLimit(limits ConcurrencyLimits) func(http.Handler) http.Handler {
+	var global slots
+	if limits.Global > 0 {
+		global = make(slots, limits.Global)
+	}
+	routes := make(map[string]slots, len(limits.Routes))
+	for path, limit := range limits.Routes {
+		if limit > 0 {
+			routes[strings.TrimSuffix(path, "/")] = make(slots, limit)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimSuffix(r.URL.Path, "/")
+			for _, exempt := range concurrencyExemptPaths {
+				if path == exempt {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			// Preflight requests are answered by CORS and never reach the database
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if global != nil {
+				if !global.tryAcquire() {
+					rejectOverloaded(w, "global")
+					return
+				}
+				requestsInFlight.WithLabelValues("global").Inc()
+				defer func() {
+					requestsInFlight.WithLabelValues("global").Dec()
+					global.release()
+				}()
+			}
+
+			if route, ok := routes[path]; ok {
+				if !route.tryAcquire() {
+					rejectOverloaded(w, path)
+					return
+				}
+				requestsInFlight.WithLabelValues(path).Inc()
+				defer func() {
+					requestsInFlight.WithLabelValues(path).Dec()
+					route.release()
+				}()
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rejectOverloaded writes a 503 telling the client to retry shortly
+func rejectOverloaded(w http.ResponseWriter, limit string) {
+	requestsRejected.WithLabelValues(limit).Inc()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(map[string]string{"error": "Server is busy, please retry shortly"})
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingHandler holds every request until release is closed, counting how many
+// are in flight at once
+type blockingHandler struct {
+	entered  chan struct{}
+	release  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{entered: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (h *blockingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.inFlight.Add(1)
+	for {
+		peak := h.peak.Load()
+		if n <= peak || h.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	h.entered <- struct{}{}
+	<-h.release
+	h.inFlight.Add(-1)
+	w.WriteHeader(http.StatusOK)
+}
+
+// burst sends n concurrent requests for path and returns a channel of their status codes
+func burst(handler http.Handler, n int, path string) <-chan int {
+	codes := make(chan int, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			codes <- rec.Code
+		}()
+	}
+	return codes
+}
+
+// expectBurst waits until admitted requests are held by the handler and the rest were
+// rejected, then releases the held ones and checks they succeed
+func expectBurst(t *testing.T, backend *blockingHandler, codes <-chan int, admitted, rejected int) {
+	t.Helper()
+	for i := 0; i < admitted; i++ {
+		<-backend.entered
+	}
+	for i := 0; i < rejected; i++ {
+		if code := <-codes; code != http.StatusServiceUnavailable {
+			t.Fatalf("excess request got status %d, want 503", code)
+		}
+	}
+	if peak := backend.peak.Load(); int(peak) > admitted {
+		t.Fatalf("%d requests were in flight at once, cap %d", peak, admitted)
+	}
+
+	close(backend.release)
+	for i := 0; i < admitted; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Fatalf("admitted request got status %d, want 200", code)
+		}
+	}
+}
+
+func TestConcurrencyLimitGlobalCap(t *testing.T) {
+	backend := newBlockingHandler()
+	handler := ConcurrencyLimit(ConcurrencyLimits{Global: 5})(backend)
+
+	expectBurst(t, backend, burst(handler, 50, "/api/v1/projects"), 5, 45)
+
+	// Every slot was released, so a second burst is admitted up to the cap again
+	backend.release = make(chan struct{})
+	expectBurst(t, backend, burst(handler, 5, "/api/v1/services"), 5, 0)
+}
+
+func TestConcurrencyLimitRouteCap(t *testing.T) {
+	backend := newBlockingHandler()
+	handler := ConcurrencyLimit(ConcurrencyLimits{
+		Global: 20,
+		Routes: map[string]int{"/api/v1/audit-logs/": 2},
+	})(backend)
+
+	codes := burst(handler, 10, "/api/v1/audit-logs")
+	for i := 0; i < 8; i++ {
+		if code := <-codes; code != http.StatusServiceUnavailable {
+			t.Fatalf("excess request got status %d, want 503", code)
+		}
+	}
+
+	// Other routes only count against the global cap
+	others := burst(handler, 5, "/api/v1/projects")
+	for i := 0; i < 7; i++ {
+		<-backend.entered
+	}
+	close(backend.release)
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("admitted audit log request got status %d", code)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if code := <-others; code != http.StatusOK {
+			t.Errorf("request to another route got status %d", code)
+		}
+	}
+}
+
+func TestConcurrencyLimitRejection(t *testing.T) {
+	backend := newBlockingHandler()
+	handler := ConcurrencyLimit(ConcurrencyLimits{Global: 1})(backend)
+
+	held := burst(handler, 1, "/api/v1/projects")
+	<-backend.entered
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("status %d, Retry-After %q; want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// Health checks and preflights are admitted while the cap is reached
+	exempt := make(chan int, 2)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/health", nil),
+		httptest.NewRequest(http.MethodOptions, "/api/v1/projects", nil),
+	} {
+		go func() {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			exempt <- rec.Code
+		}()
+	}
+	<-backend.entered
+	<-backend.entered
+
+	close(backend.release)
+	for i := 0; i < 2; i++ {
+		if code := <-exempt; code != http.StatusOK {
+			t.Errorf("exempt request got status %d, want 200", code)
+		}
+	}
+	<-held
+}
@@ -2,33 +2,126 @@ package middleware
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+// CORSOptions configures the CORS middleware
+type CORSOptions struct {
+	// AllowedOrigins are exact origins (https://app.example.com), "*" for any origin,
+	// or wildcard subdomains (https://*.example.com) that match any subdomain of
+	// example.com, at any depth, with the same scheme and port but not example.com itself
+	AllowedOrigins []string
+
+	// AllowCredentials lets browsers send cookies and Authorization headers; it cannot
+	// be combined with "*"
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response; zero omits the header
+	MaxAge time.Duration
+}
+
+// originPattern is a parsed wildcard origin: scheme://*.suffix[:port]
+type originPattern struct {
+	scheme string
+	suffix string // ".example.com"
+	port   string
+}
+
+func (p originPattern) matches(origin *url.URL) bool {
+	host := strings.ToLower(origin.Hostname())
+	return origin.Scheme == p.scheme &&
+		origin.Port() == p.port &&
+		strings.HasSuffix(host, p.suffix) &&
+		len(host) > len(p.suffix) &&
+		!strings.HasPrefix(host, ".")
+}
+
+// CORS adds CORS headers for allowed origins and answers OPTIONS requests itself,
+// so preflights never reach authentication or the handlers. The origin list is
+// compiled once, when the middleware is built.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	anyOrigin := false
+	exact := make(map[string]bool)
+	var patterns []originPattern
+	for _, allowed := range opts.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			anyOrigin = true
+		case strings.Contains(allowed, "://*."):
+			scheme, rest, _ := strings.Cut(allowed, "://*.")
+			u, err := url.Parse(scheme + "://" + strings.TrimSuffix(rest, "/"))
+			if err != nil {
+				continue // Rejected by config validation
+			}
+			patterns = append(patterns, originPattern{
+				scheme: strings.ToLower(scheme),
+				suffix: "." + strings.ToLower(u.Hostname()),
+				port:   u.Port(),
+			})
+		default:
+			exact[strings.ToLower(strings.TrimSuffix(allowed, "/"))] = true
+		}
+	}
+
+	isAllowed := func(origin string) bool {
+		if origin == "" {
+			return false
+		}
+		if anyOrigin || exact[strings.ToLower(origin)] {
+			return true
+		}
+		if len(patterns) == 0 {
+			return false
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || u.Path != "" {
+			return false
+		}
+		for _, p := range patterns {
+			if p.matches(u) {
+				return true
+			}
+		}
+		return false
+	}
+
+	maxAge := ""
+	if opts.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
+			allowed := isAllowed(origin)
 
-			// Check if origin is allowed
-			allowed := false
-			for _, allowedOrigin := range allowedOrigins {
-				if origin == allowedOrigin || allowedOrigin == "*" {
-					allowed = true
-					break
-				}
-			}
-
+			// The response depends on the origin, so shared caches must key on it
+			w.Header().Add("Vary", "Origin")
 			if allowed {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if opts.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				w.Header().Set("Access-Control-Expose-Headers", "X-Truncated, X-Request-ID")
 			}
 
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			w.Header().Set("Access-Control-Max-Age", "86400")
-
 			// Handle preflight
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
+			if r.Method == http.MethodOptions {
+				if origin != "" && !allowed {
+					http.Error(w, "Origin not allowed", http.StatusForbidden)
+					return
+				}
+				if allowed {
+					w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+					w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+					if maxAge != "" {
+						w.Header().Set("Access-Control-Max-Age", maxAge)
+					}
+				}
+				w.WriteHeader(http.StatusNoContent)
 				return
 			}
 
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// serveCORS sends a request with the given origin through the CORS middleware and
+// reports whether it reached the next handler
+func serveCORS(opts CORSOptions, method, origin string) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	handler := CORS(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(method, "/api/v1/projects", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func TestCORSPreflight(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	rec, reached := serveCORS(opts, http.MethodOptions, "https://app.example.com")
+
+	if reached {
+		t.Error("preflight reached the next handler")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status %d, want 204", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		"Access-Control-Allow-Headers":     "Content-Type, Authorization, X-Request-ID",
+		"Access-Control-Max-Age":           "600",
+		"Vary":                             "Origin",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	opts := CORSOptions{AllowedOrigins: []string{"https://app.example.com"}}
+
+	rec, reached := serveCORS(opts, http.MethodOptions, "https://evil.example.org")
+	if reached || rec.Code != http.StatusForbidden {
+		t.Errorf("preflight from a disallowed origin: status %d, reached %t; want 403", rec.Code, reached)
+	}
+
+	// Simple requests still reach the handler, but without CORS headers the browser
+	// does not expose the response
+	rec, reached = serveCORS(opts, http.MethodGet, "https://evil.example.org")
+	if !reached {
+		t.Error("request from a disallowed origin did not reach the handler")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q for a disallowed origin", got)
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"exact match", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"exact match ignores case and trailing slash", []string{"https://App.example.com/"}, "https://app.example.com", true},
+		{"exact match needs the same scheme", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"any origin", []string{"*"}, "https://anything.test", true},
+		{"wildcard subdomain", []string{"https://*.example.com"}, "https://app.example.com", true},
+		{"wildcard nested subdomain", []string{"https://*.example.com"}, "https://a.b.example.com", true},
+		{"wildcard excludes the apex", []string{"https://*.example.com"}, "https://example.com", false},
+		{"wildcard needs a dot boundary", []string{"https://*.example.com"}, "https://evilexample.com", false},
+		{"wildcard needs the same scheme", []string{"https://*.example.com"}, "http://app.example.com", false},
+		{"wildcard needs the same port", []string{"https://*.example.com:8443"}, "https://app.example.com", false},
+		{"wildcard with port", []string{"https://*.example.com:8443"}, "https://app.example.com:8443", true},
+		{"wildcard rejects a suffix attack", []string{"https://*.example.com"}, "https://app.example.com.evil.test", false},
+		{"wildcard rejects a path", []string{"https://*.example.com"}, "https://app.example.com/x", false},
+		{"no origin", []string{"*"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := serveCORS(CORSOptions{AllowedOrigins: tt.allowed}, http.MethodGet, tt.origin)
+			got := rec.Header().Get("Access-Control-Allow-Origin") != ""
+			if got != tt.want {
+				t.Errorf("origin %q allowed = %t, want %t", tt.origin, got, tt.want)
+			}
+			if got && rec.Header().Get("Access-Control-Allow-Origin") != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", rec.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPAllowlist(t *testing.T) {
+	allowlist := []string{"203.0.113.0/24"}
+	proxies := []string{"10.0.0.0/8"}
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  string
+		wantForbidden bool
+	}{
+		{"allowed direct client", "203.0.113.7:5000", "", false},
+		{"denied direct client", "198.51.100.1:5000", "", true},
+		{"direct client spoofing X-Forwarded-For", "198.51.100.1:5000", "203.0.113.7", true},
+		{"allowed client behind trusted proxy", "10.0.0.2:5000", "203.0.113.7", false},
+		{"denied client behind trusted proxy", "10.0.0.2:5000", "198.51.100.1", true},
+		{"client-prepended hop is ignored", "10.0.0.2:5000", "203.0.113.7, 198.51.100.1", true},
+		{"chain of trusted proxies", "10.0.0.2:5000", "203.0.113.7, 10.1.1.1", false},
+		{"trusted proxy without X-Forwarded-For", "10.0.0.2:5000", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := IPAllowlist(allowlist, proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/credentials", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if forbidden := rec.Code == http.StatusForbidden; forbidden != tt.wantForbidden {
+				t.Errorf("status %d, want forbidden=%t", rec.Code, tt.wantForbidden)
+			}
+		})
+	}
+}
+
+func TestIPAllowlistDisabledWhenEmpty(t *testing.T) {
+	handler := IPAllowlist(nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "portalight_http_requests_rate_limited_total",
+	Help: "Number of HTTP requests rejected with 429 because a per-user rate limit was reached",
+}, []string{"limit"})
+
+func init() {
+	prometheus.MustRegister(rateLimited)
+}
+
+// rateWindow counts one user's requests in the current one-minute window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// UserRateLimit allows each authenticated user perMinute requests per one-minute
+// window and answers the rest with 429 and Retry-After. name labels the metric.
+// Requests without a user ID (never the case behind AuthMiddleware) are not limited;
+// perMinute <= 0 disables the limit.
+func UserRateLimit(name string, perMinute int) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	windows := make(map[string]*rateWindow)
+
+	return func(next http.Handler) http.Handler {
+		if perMinute <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserID(r.Context())
+			if userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			mu.Lock()
+			window, ok := windows[userID]
+			if !ok || now.Sub(window.start) >= time.Minute {
+				// Drop expired windows of other users while we hold the lock
+				for id, other := range windows {
+					if now.Sub(other.start) >= time.Minute {
+						delete(windows, id)
+					}
+				}
+				window = &rateWindow{start: now}
+				windows[userID] = window
+			}
+			window.count++
+			allowed := window.count <= perMinute
+			retryAfter := window.start.Add(time.Minute).Sub(now)
+			mu.Unlock()
+
+			if !allowed {
+				rateLimited.WithLabelValues(name).Inc()
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Retry-After", fmt.Sprintf("%d", int(retryAfter.Seconds())+1))
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"error": "Too many requests, please slow down"})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/portalight/backend/internal/logging"
+)
+
+// RequestIDHeader carries the request ID; an incoming value is kept so IDs can be
+// correlated with the frontend or a proxy
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-set request IDs, which end up in logs and audit entries
+const maxRequestIDLength = 128
+
+// RequestID gives every request an ID: the client's X-Request-ID if it is a sane
+// value, otherwise a new UUID. It is set on the response and carried in the context,
+// where GetRequestID returns it and every log line written through
+// logging.FromContext includes it as request_id.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = uuid.New().String()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// GetRequestID returns the ID of the current request, or "" outside a request
+func GetRequestID(ctx context.Context) string {
+	return logging.RequestID(ctx)
+}
+
+// validRequestID accepts IDs of letters, digits and "-_.:" so a client cannot inject
+// line breaks or markup into logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// unmatchedRoute labels requests that did not match any registered route, so
+// scanners cannot blow up the metric cardinality
+const unmatchedRoute = "unmatched"
+
+type routeKey struct{}
+
+// SetRoute records the matched route pattern for the timing and audit middleware.
+// The router calls it, since the pattern is only known once the mux has matched.
+func SetRoute(ctx context.Context, pattern string) {
+	if route, ok := ctx.Value(routeKey{}).(*string); ok {
+		*route = pattern
+	}
+}
+
+// withRoute returns the request's route pattern holder, adding one to the context if
+// no outer middleware did. It reads unmatchedRoute until the router calls SetRoute.
+func withRoute(r *http.Request) (*http.Request, *string) {
+	if route, ok := r.Context().Value(routeKey{}).(*string); ok {
+		return r, route
+	}
+	route := unmatchedRoute
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, &route)), &route
+}
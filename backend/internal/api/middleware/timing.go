@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/portalight/backend/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "portalight_http_requests_total",
+		Help: "Number of HTTP requests served, by method, route pattern and status code",
+	}, []string{"method", "path", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "portalight_http_request_duration_seconds",
+		Help:    "Wall-clock duration of HTTP requests, by method and route pattern",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpRequestDuration)
+}
+
+// StatusCapturingWriter records the status code written by the wrapped handler
+type StatusCapturingWriter struct {
+	http.ResponseWriter
+	StatusCode int
+}
+
+// NewStatusCapturingWriter wraps w; the status defaults to 200 for handlers that
+// only call Write
+func NewStatusCapturingWriter(w http.ResponseWriter) *StatusCapturingWriter {
+	return &StatusCapturingWriter{ResponseWriter: w, StatusCode: http.StatusOK}
+}
+
+// WriteHeader stores the status code and forwards it
+func (w *StatusCapturingWriter) WriteHeader(code int) {
+	w.StatusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *StatusCapturingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// RequestTiming logs every request with its status and duration and records the
+// portalight_http_requests_total and portalight_http_request_duration_seconds metrics.
+// Requests are labelled by route pattern ("/api/v1/services/{id}"), not raw path.
+func RequestTiming() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// The logger carries request_id from RequestID; auth adds user_id and the router route
+			r, route := withRoute(r)
+			sw := NewStatusCapturingWriter(w)
+
+			next.ServeHTTP(sw, r)
+
+			duration := time.Since(start)
+			httpRequests.WithLabelValues(r.Method, *route, strconv.Itoa(sw.StatusCode)).Inc()
+			httpRequestDuration.WithLabelValues(r.Method, *route).Observe(duration.Seconds())
+
+			logging.FromContext(r.Context()).LogAttrs(r.Context(), slog.LevelInfo, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", *route),
+				slog.Int("status_code", sw.StatusCode),
+				slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+			)
+		})
+	}
+}
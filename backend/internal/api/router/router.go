@@ -0,0 +1,148 @@
+// Package router registers HTTP routes together with their access requirements, so the
+// auth exclusion list and the route inventory come from the same data as the mux.
+package router
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/portalight/backend/internal/api/middleware"
+	"github.com/portalight/backend/internal/logging"
+	"github.com/portalight/backend/internal/models"
+)
+
+// Access is the minimum caller a route admits
+type Access string
+
+const (
+	AccessPublic        Access = "public"        // No token; skipped by the auth middleware
+	AccessAuthenticated Access = "authenticated" // Any signed-in user
+	AccessLead          Access = "lead"          // Lead or superadmin
+	AccessSuperadmin    Access = "superadmin"
+)
+
+// Policy is the auth metadata every route must declare
+type Policy struct {
+	Access Access
+	// Checks describes finer rules the handler applies on top of Access, e.g. team membership
+	Checks string
+}
+
+// Shorthands for the common policies
+var (
+	Public        = Policy{Access: AccessPublic}
+	Authenticated = Policy{Access: AccessAuthenticated}
+	Lead          = Policy{Access: AccessLead}
+	Superadmin    = Policy{Access: AccessSuperadmin}
+)
+
+// WithChecks returns a copy of the policy noting the handler's own checks
+func (p Policy) WithChecks(checks string) Policy {
+	p.Checks = checks
+	return p
+}
+
+// Roles returns the user roles the policy admits; nil for public routes
+func (p Policy) Roles() []string {
+	switch p.Access {
+	case AccessAuthenticated:
+		return []string{string(models.RoleAdmin), string(models.RoleLead), string(models.RoleDev)}
+	case AccessLead:
+		return []string{string(models.RoleAdmin), string(models.RoleLead)}
+	case AccessSuperadmin:
+		return []string{string(models.RoleAdmin)}
+	default:
+		return nil
+	}
+}
+
+// Route describes one registered method and pattern
+type Route struct {
+	Method       string   `json:"method"`
+	Pattern      string   `json:"pattern"`
+	Access       Access   `json:"access"`
+	Roles        []string `json:"roles"`
+	AuthExcluded bool     `json:"auth_excluded"`
+	Checks       string   `json:"handler_checks,omitempty"`
+}
+
+// Router is an http.ServeMux that records the policy of every route
+type Router struct {
+	mux    *http.ServeMux
+	routes []Route
+}
+
+// New creates an empty router
+func New() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// Handle registers handler for a "METHOD /path" pattern. Lead and superadmin routes
+// are wrapped in middleware.RequireRole, so the recorded policy is also enforced.
+// It panics when the pattern has no method or the policy no access level, so a route
+// without explicit auth metadata fails at startup instead of shipping unreviewed.
+func (rt *Router) Handle(pattern string, policy Policy, handler http.Handler) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		panic(fmt.Sprintf("router: pattern %q must be \"METHOD /path\"", pattern))
+	}
+	if policy.Roles() == nil && policy.Access != AccessPublic {
+		panic(fmt.Sprintf("router: route %q has no access policy", pattern))
+	}
+
+	if policy.Access == AccessLead || policy.Access == AccessSuperadmin {
+		handler = middleware.RequireRole(policy.Roles()...)(handler)
+	}
+	next := handler
+	rt.mux.Handle(pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetRoute(r.Context(), path)
+		next.ServeHTTP(w, r.WithContext(logging.With(r.Context(), slog.String("route", path))))
+	}))
+
+	rt.routes = append(rt.routes, Route{
+		Method:       method,
+		Pattern:      path,
+		Access:       policy.Access,
+		Roles:        policy.Roles(),
+		AuthExcluded: policy.Access == AccessPublic,
+		Checks:       policy.Checks,
+	})
+}
+
+// HandleFunc registers a handler function, see Handle
+func (rt *Router) HandleFunc(pattern string, policy Policy, handler http.HandlerFunc) {
+	rt.Handle(pattern, policy, handler)
+}
+
+// ServeHTTP dispatches to the underlying mux
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+// Routes returns every registered route sorted by pattern and method
+func (rt *Router) Routes() []Route {
+	routes := append([]Route(nil), rt.routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Pattern != routes[j].Pattern {
+			return routes[i].Pattern < routes[j].Pattern
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// PublicPaths returns the paths of public routes, for the auth middleware's exclusion list
+func (rt *Router) PublicPaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, route := range rt.routes {
+		if route.AuthExcluded && !seen[route.Pattern] {
+			seen[route.Pattern] = true
+			paths = append(paths, route.Pattern)
+		}
+	}
+	return paths
+}
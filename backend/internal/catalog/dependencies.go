@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// dependencyRef is a parsed dependsOn entry
+type dependencyRef struct {
+	Project string // Catalog name (metadata.name) of another project; empty for this file
+	Service string
+}
+
+// String returns the reference as written in dependsOn
+func (d dependencyRef) String() string {
+	if d.Project == "" {
+		return d.Service
+	}
+	return d.Project + "/" + d.Service
+}
+
+// parseDependencyRef parses a dependsOn entry: a service name of the same file
+// or project-name/service-name. References to the file's own project are
+// normalised to a bare service name.
+func parseDependencyRef(catalogName, ref string) (dependencyRef, error) {
+	if ref == "" {
+		return dependencyRef{}, errors.New("must not be empty")
+	}
+	project, service, qualified := strings.Cut(ref, "/")
+	if !qualified {
+		return dependencyRef{Service: ref}, nil
+	}
+	if project == "" || service == "" || strings.Contains(service, "/") {
+		return dependencyRef{}, errors.New("must be a service name or project-name/service-name")
+	}
+	if project == catalogName {
+		return dependencyRef{Service: service}, nil
+	}
+	return dependencyRef{Project: project, Service: service}, nil
+}
+
+// DependencyCycle returns the services of a catalog file that take part in a
+// dependsOn cycle, sorted by name, or nil if the graph is acyclic.
+//
+// It runs Kahn's algorithm over the edges between services of the same file:
+// services that can never reach in-degree zero are on (or behind) a cycle.
+// Dependencies on other projects cannot be followed here and are ignored.
+func DependencyCycle(catalog *ProjectCatalog) []string {
+	services := catalog.Spec.Services
+	inFile := make(map[string]bool, len(services))
+	for _, svc := range services {
+		inFile[svc.Name] = true
+	}
+
+	// Edge dependency -> dependent, so a service is released once its dependencies are
+	inDegree := make(map[string]int, len(services))
+	dependents := make(map[string][]string)
+	for _, svc := range services {
+		if _, ok := inDegree[svc.Name]; !ok {
+			inDegree[svc.Name] = 0
+		}
+		seen := make(map[string]bool)
+		for _, entry := range svc.DependsOn {
+			ref, err := parseDependencyRef(catalog.Metadata.Name, entry)
+			// Invalid and self-dependencies are reported on their own by ValidateSchema
+			if err != nil || ref.Project != "" || !inFile[ref.Service] || seen[ref.Service] || ref.Service == svc.Name {
+				continue
+			}
+			seen[ref.Service] = true
+			inDegree[svc.Name]++
+			dependents[ref.Service] = append(dependents[ref.Service], svc.Name)
+		}
+	}
+
+	var queue []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		delete(inDegree, name)
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	if len(inDegree) == 0 {
+		return nil
+	}
+	cycle := make([]string, 0, len(inDegree))
+	for name := range inDegree {
+		cycle = append(cycle, name)
+	}
+	sort.Strings(cycle)
+	return cycle
+}
+
+// externalDependencies returns the project-name/service-name references to
+// services of other projects
+func externalDependencies(catalog *ProjectCatalog) []string {
+	seen := make(map[string]bool)
+	var refs []string
+	for _, svc := range catalog.Spec.Services {
+		for _, entry := range svc.DependsOn {
+			ref, err := parseDependencyRef(catalog.Metadata.Name, entry)
+			if err != nil || ref.Project == "" || seen[ref.String()] {
+				continue
+			}
+			seen[ref.String()] = true
+			refs = append(refs, ref.String())
+		}
+	}
+	return refs
+}
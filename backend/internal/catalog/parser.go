@@ -2,12 +2,14 @@ package catalog
 
 import (
 	"fmt"
+	"net/mail"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// ParseYAML parses the raw YAML content into a ProjectCatalog struct
+// ParseYAML parses the raw YAML content into a ProjectCatalog struct.
+// Unknown fields are ignored so older servers accept newer catalog files.
 func ParseYAML(content []byte) (*ProjectCatalog, error) {
 	var catalog ProjectCatalog
 	if err := yaml.Unmarshal(content, &catalog); err != nil {
@@ -22,11 +24,64 @@ type ValidationError struct {
 	Message string `json:"message"`
 }
 
-// ValidateSchema checks if the catalog structure is valid according to rules
-func ValidateSchema(catalog *ProjectCatalog) []ValidationError {
+// ValidationRule checks one aspect of a catalog. Errors block a sync; warnings do not.
+type ValidationRule struct {
+	Name  string
+	Check func(catalog *ProjectCatalog) ([]ValidationError, []string)
+}
+
+// ValidationRuleSet is the list of rules a catalog is validated against
+type ValidationRuleSet struct {
+	Rules []ValidationRule
+}
+
+// DefaultValidationRules returns the rules a catalog must pass to be synced.
+// knownServices holds the project-name/service-name references of services of
+// other projects that are already in the database.
+func DefaultValidationRules(knownServices map[string]bool) *ValidationRuleSet {
+	return &ValidationRuleSet{Rules: []ValidationRule{
+		{Name: "header", Check: validateHeader},
+		{Name: "metadata", Check: validateMetadata},
+		{Name: "services", Check: validateServices},
+		{Name: "dependsOn", Check: func(catalog *ProjectCatalog) ([]ValidationError, []string) {
+			return validateDependsOn(catalog, knownServices)
+		}},
+	}}
+}
+
+// LintRules returns the default rules plus warnings for optional fields that are
+// left empty, for checking a file before it is committed
+func LintRules(knownServices map[string]bool) *ValidationRuleSet {
+	rules := DefaultValidationRules(knownServices)
+	rules.Rules = append(rules.Rules, ValidationRule{Name: "optionalFields", Check: lintOptionalFields})
+	return rules
+}
+
+// ValidateSchema checks if the catalog structure is valid according to rules.
+// Warnings do not block a sync.
+//
+// knownServices holds the project-name/service-name references of services of
+// other projects that are already in the database.
+func ValidateSchema(catalog *ProjectCatalog, knownServices map[string]bool) ([]ValidationError, []string) {
+	return ValidateSchemaWithRules(catalog, DefaultValidationRules(knownServices))
+}
+
+// ValidateSchemaWithRules runs every rule of the set and collects their errors and warnings
+func ValidateSchemaWithRules(catalog *ProjectCatalog, rules *ValidationRuleSet) ([]ValidationError, []string) {
+	var errors []ValidationError
+	var warnings []string
+	for _, rule := range rules.Rules {
+		ruleErrors, ruleWarnings := rule.Check(catalog)
+		errors = append(errors, ruleErrors...)
+		warnings = append(warnings, ruleWarnings...)
+	}
+	return errors, warnings
+}
+
+// validateHeader checks apiVersion and kind
+func validateHeader(catalog *ProjectCatalog) ([]ValidationError, []string) {
 	var errors []ValidationError
 
-	// Validate API Version and Kind
 	if catalog.APIVersion != "portalight.dev/v1alpha1" {
 		errors = append(errors, ValidationError{
 			Field:   "apiVersion",
@@ -40,7 +95,13 @@ func ValidateSchema(catalog *ProjectCatalog) []ValidationError {
 		})
 	}
 
-	// Validate Metadata
+	return errors, nil
+}
+
+// validateMetadata checks the required project fields and contacts
+func validateMetadata(catalog *ProjectCatalog) ([]ValidationError, []string) {
+	var errors []ValidationError
+
 	if catalog.Metadata.Name == "" {
 		errors = append(errors, ValidationError{
 			Field:   "metadata.name",
@@ -60,7 +121,22 @@ func ValidateSchema(catalog *ProjectCatalog) []ValidationError {
 		})
 	}
 
-	// Validate Services
+	if _, err := catalog.Metadata.DocsPath(); err != nil {
+		errors = append(errors, ValidationError{
+			Field:   "metadata.docs",
+			Message: err.Error(),
+		})
+	}
+
+	errors = append(errors, validateContacts(catalog.Metadata.Contacts)...)
+
+	return errors, nil
+}
+
+// validateServices checks every service definition
+func validateServices(catalog *ProjectCatalog) ([]ValidationError, []string) {
+	var errors []ValidationError
+
 	if len(catalog.Spec.Services) == 0 {
 		errors = append(errors, ValidationError{
 			Field:   "spec.services",
@@ -91,11 +167,182 @@ func ValidateSchema(catalog *ProjectCatalog) []ValidationError {
 				Message: "is required",
 			})
 		}
+
+		errors = append(errors, validateArgoCDApps(i, service)...)
+		errors = append(errors, validateOnCall(i, service.OnCall)...)
+	}
+
+	return errors, nil
+}
+
+// lintOptionalFields warns about optional fields that most catalogs fill in
+func lintOptionalFields(catalog *ProjectCatalog) ([]ValidationError, []string) {
+	var warnings []string
+
+	if catalog.Metadata.Description == "" {
+		warnings = append(warnings, "metadata.description: is empty")
+	}
+	contacts := catalog.Metadata.Contacts
+	if contacts.Slack == "" && contacts.Email == "" && contacts.PagerDuty == "" {
+		warnings = append(warnings, "metadata.contacts: no contact channel is set")
+	}
+	for i, service := range catalog.Spec.Services {
+		field := fmt.Sprintf("spec.services[%d]", i)
+		if service.Description == "" {
+			warnings = append(warnings, field+".description: is empty")
+		}
+		if service.Repository == "" {
+			warnings = append(warnings, field+".repository: is empty")
+		}
+		if service.Language == "" {
+			warnings = append(warnings, field+".language: is empty")
+		}
+	}
+
+	return nil, warnings
+}
+
+// validateDependsOn checks the dependsOn entries of every service. Names of the
+// same file must resolve; references to other projects that are not synced yet
+// and cycles only produce warnings.
+func validateDependsOn(catalog *ProjectCatalog, knownServices map[string]bool) ([]ValidationError, []string) {
+	var errors []ValidationError
+	var warnings []string
+
+	inFile := make(map[string]bool, len(catalog.Spec.Services))
+	for _, service := range catalog.Spec.Services {
+		inFile[service.Name] = true
+	}
+
+	for i, service := range catalog.Spec.Services {
+		for j, entry := range service.DependsOn {
+			field := fmt.Sprintf("spec.services[%d].dependsOn[%d]", i, j)
+			ref, err := parseDependencyRef(catalog.Metadata.Name, entry)
+			switch {
+			case err != nil:
+				errors = append(errors, ValidationError{Field: field, Message: err.Error()})
+			case ref.Project == "" && ref.Service == service.Name:
+				errors = append(errors, ValidationError{Field: field, Message: "a service cannot depend on itself"})
+			case ref.Project == "" && !inFile[ref.Service]:
+				errors = append(errors, ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("unknown service '%s': not defined in this file (use project-name/service-name for other projects)", ref.Service),
+				})
+			case ref.Project != "" && !knownServices[ref.String()]:
+				warnings = append(warnings, fmt.Sprintf("%s: service '%s' does not exist yet; the dependency is recorded once it is synced", field, ref))
+			}
+		}
+	}
+
+	if cycle := DependencyCycle(catalog); cycle != nil {
+		warnings = append(warnings, fmt.Sprintf("dependsOn cycle between services: %s", strings.Join(cycle, ", ")))
+	}
+
+	return errors, warnings
+}
+
+// validateArgoCDApps checks the environments list, argocd block and annotation of a service
+func validateArgoCDApps(i int, service ServiceSpec) []ValidationError {
+	var errors []ValidationError
+
+	apps, err := service.ArgoCDApps()
+	if err != nil {
+		return []ValidationError{{
+			Field:   fmt.Sprintf("spec.services[%d].annotations.%s", i, AnnotationArgoCDApps),
+			Message: err.Error(),
+		}}
+	}
+
+	declared := make(map[string]bool, len(service.Environments))
+	for j, env := range service.Environments {
+		if strings.TrimSpace(env) == "" {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("spec.services[%d].environments[%d]", i, j), Message: "must not be empty"})
+		}
+		declared[env] = true
+	}
+
+	seenEnvironments := make(map[string]bool)
+	for j, app := range apps {
+		field := fmt.Sprintf("spec.services[%d].argocd[%d]", i, j)
+		if app.Environment == "" {
+			errors = append(errors, ValidationError{Field: field + ".environment", Message: "is required"})
+		} else if seenEnvironments[app.Environment] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".environment",
+				Message: fmt.Sprintf("duplicate ArgoCD app for environment '%s'", app.Environment),
+			})
+		}
+		seenEnvironments[app.Environment] = true
+		// Catch typos such as "prod" vs "production" once environments are listed
+		if app.Environment != "" && len(declared) > 0 && !declared[app.Environment] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".environment",
+				Message: fmt.Sprintf("environment '%s' is not listed in environments", app.Environment),
+			})
+		}
+
+		if app.AppName == "" {
+			errors = append(errors, ValidationError{Field: field + ".appName", Message: "is required"})
+		}
+	}
+
+	return errors
+}
+
+// validateContacts checks the project-level contact channels
+func validateContacts(contacts Contacts) []ValidationError {
+	var errors []ValidationError
+
+	if contacts.Slack != "" && !isSlackChannel(contacts.Slack) {
+		errors = append(errors, ValidationError{
+			Field:   "metadata.contacts.slack",
+			Message: "must be a channel name starting with '#'",
+		})
+	}
+	if contacts.Email != "" {
+		if _, err := mail.ParseAddress(contacts.Email); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "metadata.contacts.email",
+				Message: "must be a valid email address",
+			})
+		}
 	}
 
 	return errors
 }
 
+// validateOnCall checks the on-call block of a service
+func validateOnCall(i int, oncall *OnCallSpec) []ValidationError {
+	if oncall == nil {
+		return nil
+	}
+	var errors []ValidationError
+
+	field := fmt.Sprintf("spec.services[%d].oncall", i)
+	if oncall.Slack != "" && !isSlackChannel(oncall.Slack) {
+		errors = append(errors, ValidationError{
+			Field:   field + ".slack",
+			Message: "must be a channel name starting with '#'",
+		})
+	}
+	for j, step := range oncall.Escalation {
+		stepField := fmt.Sprintf("%s.escalation[%d]", field, j)
+		if step.Contact == "" {
+			errors = append(errors, ValidationError{Field: stepField + ".contact", Message: "is required"})
+		}
+		if step.AfterMinutes < 0 {
+			errors = append(errors, ValidationError{Field: stepField + ".afterMinutes", Message: "must not be negative"})
+		}
+	}
+
+	return errors
+}
+
+// isSlackChannel accepts channel names such as #payments-oncall
+func isSlackChannel(channel string) bool {
+	return len(channel) > 1 && strings.HasPrefix(channel, "#") && !strings.ContainsAny(channel, " \t")
+}
+
 // IsValidTeamName checks if the team name exists in the database
 // This is a placeholder - actual validation needs database access
 func IsValidTeamName(teamName string, validTeams map[string]string) bool {
@@ -0,0 +1,134 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// parseFixture parses a catalog file from testdata
+func parseFixture(t *testing.T, name string) *ProjectCatalog {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	catalog, err := ParseYAML(content)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", name, err)
+	}
+	return catalog
+}
+
+func TestValidateSchemaFixtures(t *testing.T) {
+	tests := []struct {
+		file       string
+		wantFields []string // Fields with a validation error, in order
+	}{
+		{"legacy.yaml", nil},
+		{"contacts_oncall.yaml", nil},
+		{"unknown_fields.yaml", nil},
+		{"invalid_contacts.yaml", []string{
+			"metadata.contacts.slack",
+			"metadata.contacts.email",
+		}},
+		{"invalid_oncall.yaml", []string{
+			"spec.services[0].oncall.slack",
+			"spec.services[0].oncall.escalation[0].contact",
+			"spec.services[0].oncall.escalation[1].afterMinutes",
+		}},
+		{"missing_required.yaml", []string{
+			"apiVersion",
+			"kind",
+			"metadata.name",
+			"metadata.title",
+			"metadata.owner",
+			"spec.services",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			errs, _ := ValidateSchema(parseFixture(t, tt.file), nil)
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("errors on %v, want %v (%+v)", fields, tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestParseYAMLRejectsMalformedFile(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "malformed.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if _, err := ParseYAML(content); err == nil {
+		t.Error("malformed YAML parsed without an error")
+	}
+}
+
+func TestParseLegacyCatalog(t *testing.T) {
+	catalog := parseFixture(t, "legacy.yaml")
+
+	if catalog.Metadata.Owner != "payments-team" {
+		t.Errorf("owner = %q, want payments-team", catalog.Metadata.Owner)
+	}
+	if catalog.Metadata.Contacts != (Contacts{}) {
+		t.Errorf("contacts = %+v, want none", catalog.Metadata.Contacts)
+	}
+	service := catalog.Spec.Services[0]
+	if service.OnCall != nil {
+		t.Errorf("oncall = %+v, want none", service.OnCall)
+	}
+	if envs := service.AllEnvironments(); !slices.Equal(envs, []string{"production"}) {
+		t.Errorf("environments = %v, want the deprecated environment field", envs)
+	}
+}
+
+func TestParseContactsAndOnCall(t *testing.T) {
+	catalog := parseFixture(t, "contacts_oncall.yaml")
+
+	want := Contacts{Slack: "#payments", Email: "payments@example.com", PagerDuty: "PPAYMNT"}
+	if catalog.Metadata.Contacts != want {
+		t.Errorf("contacts = %+v, want %+v", catalog.Metadata.Contacts, want)
+	}
+
+	api := catalog.Spec.Services[0]
+	if api.OnCall == nil || api.OnCall.Rotation != "payments-primary" || len(api.OnCall.Escalation) != 2 {
+		t.Fatalf("oncall = %+v, want the rotation and two escalation steps", api.OnCall)
+	}
+	if step := api.OnCall.Escalation[1]; step.Contact != "#engineering-managers" || step.AfterMinutes != 30 {
+		t.Errorf("second escalation step = %+v", step)
+	}
+
+	// Services fall back to the project contacts for what their on-call block leaves out
+	worker := catalog.Spec.Services[1]
+	tests := []struct {
+		name, got, want string
+	}{
+		{"api slack", api.SlackChannel(catalog.Metadata.Contacts), "#payments-oncall"},
+		{"api pagerduty", api.PagerDutyService(catalog.Metadata.Contacts), "PPAYMNT"},
+		{"worker slack", worker.SlackChannel(catalog.Metadata.Contacts), "#payments"},
+		{"worker pagerduty", worker.PagerDutyService(catalog.Metadata.Contacts), "PWORKER"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseIgnoresUnknownFields(t *testing.T) {
+	catalog := parseFixture(t, "unknown_fields.yaml")
+
+	if catalog.Metadata.Contacts.Slack != "#payments" {
+		t.Errorf("contacts.slack = %q, want the known field next to unknown ones", catalog.Metadata.Contacts.Slack)
+	}
+	if oncall := catalog.Spec.Services[0].OnCall; oncall == nil || oncall.Slack != "#payments-oncall" {
+		t.Errorf("oncall = %+v, want slack #payments-oncall", oncall)
+	}
+}
@@ -0,0 +1,278 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// PlanAction describes what a sync would do to a project or service
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionOrphan PlanAction = "orphan"
+)
+
+// SyncPlan describes the changes a catalog sync would make, without applying them
+type SyncPlan struct {
+	FilePath         string            `json:"file_path"`
+	Project          ProjectPlan       `json:"project"`
+	Services         []ServicePlan     `json:"services"`
+	Warnings         []string          `json:"warnings"`
+	Errors           []string          `json:"errors"` // Conflicts that would make the sync fail
+	ValidationErrors []ValidationError `json:"validation_errors,omitempty"`
+}
+
+// ProjectPlan describes the planned change to the project
+type ProjectPlan struct {
+	Action        PlanAction `json:"action"`
+	ID            string     `json:"id,omitempty"` // Existing project ID when updating
+	Name          string     `json:"name"`
+	CatalogName   string     `json:"catalog_name"`
+	OwnerTeamID   string     `json:"owner_team_id,omitempty"`
+	OwnerTeamName string     `json:"owner_team_name,omitempty"`
+}
+
+// ServicePlan describes the planned change to a single service
+type ServicePlan struct {
+	Action        PlanAction      `json:"action"`
+	ID            string          `json:"id,omitempty"` // Existing service ID when updating or orphaning
+	Name          string          `json:"name"`
+	OwnerTeamID   string          `json:"owner_team_id,omitempty"`
+	OwnerTeamName string          `json:"owner_team_name,omitempty"`
+	ArgoCDApps    []ArgoCDAppSpec `json:"argocd_apps,omitempty"`
+}
+
+// HasErrors reports whether applying the plan would fail
+func (p *SyncPlan) HasErrors() bool {
+	return len(p.Errors) > 0 || len(p.ValidationErrors) > 0
+}
+
+// CountServices returns the number of planned services with the given action
+func (p *SyncPlan) CountServices(action PlanAction) int {
+	count := 0
+	for _, svc := range p.Services {
+		if svc.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// buildPlan resolves owners and compares the catalog against the database.
+// It only reads from the database, so it is safe to use for previews.
+func (s *Syncer) buildPlan(ctx context.Context, filePath string, catalog *ProjectCatalog, teamID string) (*SyncPlan, error) {
+	// dependsOn may point at services synced from other catalog files
+	externalIDs, err := s.serviceRepo.FindIDsByRefs(ctx, externalDependencies(catalog))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service dependencies: %w", err)
+	}
+	knownServices := make(map[string]bool, len(externalIDs))
+	for ref := range externalIDs {
+		knownServices[ref] = true
+	}
+	validationErrors, validationWarnings := ValidateSchema(catalog, knownServices)
+
+	plan := &SyncPlan{
+		FilePath:         filePath,
+		Services:         []ServicePlan{},
+		Warnings:         append([]string{}, validationWarnings...),
+		Errors:           []string{},
+		ValidationErrors: validationErrors,
+	}
+
+	// 1. Project: create or update, keyed by catalog file path
+	existing, err := s.projectRepo.FindByCatalogPath(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing project: %w", err)
+	}
+
+	plan.Project = ProjectPlan{
+		Action:      PlanActionCreate,
+		Name:        catalog.Metadata.Title,
+		CatalogName: catalog.Metadata.Name,
+	}
+	if existing != nil {
+		plan.Project.Action = PlanActionUpdate
+		plan.Project.ID = existing.ID
+	}
+
+	// Project names are unique, so another project with the same name blocks the upsert
+	if catalog.Metadata.Title != "" {
+		if other, err := s.projectRepo.FindByName(ctx, catalog.Metadata.Title); err == nil && other.CatalogFilePath != filePath {
+			conflict := &repositories.ProjectConflictError{Field: "name", Value: catalog.Metadata.Title, CatalogFilePath: other.CatalogFilePath}
+			plan.Errors = append(plan.Errors, conflict.Error())
+		}
+	}
+
+	// metadata.name identifies the project, so two catalog files may not claim the same one
+	if catalog.Metadata.Name != "" {
+		other, err := s.projectRepo.FindBySlug(ctx, catalog.Metadata.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up project slug: %w", err)
+		}
+		if other != nil && other.CatalogFilePath != filePath {
+			conflict := &repositories.ProjectConflictError{Field: "slug", Value: catalog.Metadata.Name, CatalogFilePath: other.CatalogFilePath}
+			plan.ValidationErrors = append(plan.ValidationErrors, ValidationError{
+				Field:   "metadata.name",
+				Message: conflict.Error(),
+			})
+		}
+	}
+
+	// 2. Resolve project owner: explicit mapping, then existing owner, then catalog owner name
+	ownerTeamID := teamID
+	if ownerTeamID == "" && existing != nil {
+		ownerTeamID = existing.OwnerTeamID
+	}
+	if ownerTeamID != "" {
+		team, err := s.teamRepo.FindByID(ctx, ownerTeamID)
+		if err != nil {
+			plan.Errors = append(plan.Errors, fmt.Sprintf("owner team '%s' not found", ownerTeamID))
+		} else {
+			plan.Project.OwnerTeamID = team.ID
+			plan.Project.OwnerTeamName = team.Name
+			if catalog.Metadata.Owner != "" && !strings.EqualFold(catalog.Metadata.Owner, team.Name) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("catalog owner '%s' differs from selected team '%s'", catalog.Metadata.Owner, team.Name))
+			}
+		}
+	} else if catalog.Metadata.Owner != "" {
+		team, err := s.teamRepo.FindByName(ctx, catalog.Metadata.Owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve owner team '%s': %w", catalog.Metadata.Owner, err)
+		}
+		if team == nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("no team selected and catalog owner '%s' does not match any team", catalog.Metadata.Owner))
+		} else {
+			plan.Project.OwnerTeamID = team.ID
+			plan.Project.OwnerTeamName = team.Name
+		}
+	}
+
+	// Service tags are stored normalized; the catalog is updated in place so the
+	// sync upserts what the plan reports
+	vocab, err := s.tagRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tag vocabulary: %w", err)
+	}
+	for i := range catalog.Spec.Services {
+		svcSpec := &catalog.Spec.Services[i]
+		tags, warnings := normalizeTags(svcSpec.Tags, vocab)
+		svcSpec.Tags = tags
+		for _, warning := range warnings {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("service '%s': %s", svcSpec.Name, warning))
+		}
+	}
+
+	// 3. Services: create or update, keyed by name within the project
+	existingServices := make(map[string]string) // name -> ID
+	var autoSyncedServices []string
+	if existing != nil {
+		services, err := s.serviceRepo.FindByProjectID(ctx, existing.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load existing services: %w", err)
+		}
+		for _, svc := range services {
+			existingServices[svc.Name] = svc.ID
+			if svc.AutoSynced {
+				autoSyncedServices = append(autoSyncedServices, svc.Name)
+			}
+		}
+	}
+
+	inCatalog := make(map[string]bool)
+	for _, svcSpec := range catalog.Spec.Services {
+		if svcSpec.Name == "" {
+			continue
+		}
+		inCatalog[svcSpec.Name] = true
+
+		svcPlan := ServicePlan{
+			Action:        PlanActionCreate,
+			Name:          svcSpec.Name,
+			OwnerTeamID:   plan.Project.OwnerTeamID,
+			OwnerTeamName: plan.Project.OwnerTeamName,
+		}
+		if id, ok := existingServices[svcSpec.Name]; ok {
+			svcPlan.Action = PlanActionUpdate
+			svcPlan.ID = id
+		} else if other, err := s.serviceRepo.FindByName(ctx, svcSpec.Name); err == nil && other.ProjectID != plan.Project.ID {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("service name '%s' is also used by another project", svcSpec.Name))
+		}
+
+		// Service owner override
+		if svcSpec.Owner != "" {
+			svcTeam, err := s.teamRepo.FindByName(ctx, svcSpec.Owner)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find service owner team '%s': %w", svcSpec.Owner, err)
+			}
+			if svcTeam == nil {
+				plan.Errors = append(plan.Errors, fmt.Sprintf("service owner team '%s' not found", svcSpec.Owner))
+			} else {
+				svcPlan.OwnerTeamID = svcTeam.ID
+				svcPlan.OwnerTeamName = svcTeam.Name
+			}
+		}
+
+		// Invalid entries are already reported by ValidateSchema
+		svcPlan.ArgoCDApps, _ = svcSpec.ArgoCDApps()
+
+		plan.Services = append(plan.Services, svcPlan)
+	}
+
+	plan.Warnings = append(plan.Warnings, s.checkArgoCDApps(ctx, plan.Services)...)
+
+	// 4. Orphans: auto-synced services no longer in the catalog
+	for _, name := range autoSyncedServices {
+		if !inCatalog[name] {
+			plan.Services = append(plan.Services, ServicePlan{
+				Action: PlanActionOrphan,
+				ID:     existingServices[name],
+				Name:   name,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// checkArgoCDApps warns about catalog ArgoCD apps that do not exist in the live ArgoCD.
+// It is skipped when ArgoCD is not configured and never fails the plan.
+func (s *Syncer) checkArgoCDApps(ctx context.Context, services []ServicePlan) []string {
+	if s.argocdClient == nil || !s.argocdClient.IsConfigured() {
+		return nil
+	}
+
+	var declared []ServicePlan
+	for _, svc := range services {
+		if len(svc.ArgoCDApps) > 0 {
+			declared = append(declared, svc)
+		}
+	}
+	if len(declared) == 0 {
+		return nil
+	}
+
+	liveApps, err := s.argocdClient.ListApplications(ctx)
+	if err != nil {
+		return []string{fmt.Sprintf("could not validate ArgoCD apps: %v", err)}
+	}
+	known := make(map[string]bool, len(liveApps))
+	for _, app := range liveApps {
+		known[app.Name] = true
+	}
+
+	var warnings []string
+	for _, svc := range declared {
+		for _, app := range svc.ArgoCDApps {
+			if !known[app.AppName] {
+				warnings = append(warnings, fmt.Sprintf("service '%s': ArgoCD app '%s' (%s) not found in ArgoCD", svc.Name, app.AppName, app.Environment))
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,212 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/logging"
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ErrReconcileRunning is returned when a full-scan reconciliation is already in progress
+var ErrReconcileRunning = errors.New("catalog reconciliation already running")
+
+// ReconcileFailure is a catalog file whose re-sync failed
+type ReconcileFailure struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// ReconcileResult is the drift a full-scan reconciliation found between the
+// repository and the database, and what it did about it
+type ReconcileResult struct {
+	SyncID    string             `json:"sync_id"`   // Summary row in the sync history
+	Changed   []string           `json:"changed"`   // Files whose blob SHA changed; re-synced
+	Orphaned  []string           `json:"orphaned"`  // Files that vanished; their projects are marked orphaned
+	Untracked []string           `json:"untracked"` // Files no project is linked to; a first sync needs an owner team
+	Unchanged int                `json:"unchanged"` // Files already in sync
+	Failed    []ReconcileFailure `json:"failed"`    // Changed files whose re-sync failed
+}
+
+// Reconcile scans the repository and compares it against the projects linked to
+// catalog files: files whose blob SHA changed since the last sync are re-synced and
+// projects whose file vanished are marked orphaned. It catches up on missed webhooks.
+// The run is recorded as a sync history row of syncType with the result as its plan.
+func (s *Syncer) Reconcile(ctx context.Context, syncType string, userID string, userName string) (*ReconcileResult, error) {
+	if !s.reconciling.TryLock() {
+		return nil, ErrReconcileRunning
+	}
+	defer s.reconciling.Unlock()
+	ctx = database.WithQueryTimeout(ctx, syncQueryTimeout)
+
+	files, err := s.scanFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan catalog files: %w", err)
+	}
+	projects, err := s.projectRepo.ListCatalogProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.SyncHistory{
+		ID:           uuid.New().String(),
+		SyncType:     syncType,
+		Status:       "running",
+		StartedAt:    time.Now(),
+		SyncedBy:     userID,
+		SyncedByName: userName,
+	}
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		return nil, fmt.Errorf("failed to create sync history: %w", err)
+	}
+	// Re-syncs log under their own sync ID, so only the run's own lines carry this one
+	logger := logging.FromContext(ctx).With(slog.String("sync_id", history.ID))
+	logger.Info("catalog reconciliation started", slog.String("sync_type", syncType), slog.Int("files", len(files)))
+
+	result := &ReconcileResult{
+		SyncID:    history.ID,
+		Changed:   []string{},
+		Orphaned:  []string{},
+		Untracked: []string{},
+		Failed:    []ReconcileFailure{},
+	}
+
+	shas := make(map[string]string, len(files))
+	for _, f := range files {
+		shas[f.Path] = f.SHA
+	}
+	linked := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		linked[project.CatalogFilePath] = true
+
+		sha, exists := shas[project.CatalogFilePath]
+		switch {
+		case !exists:
+			if project.SyncStatus == repositories.ProjectSyncStatusOrphaned {
+				continue // Reported when it was first found missing
+			}
+			reason := fmt.Sprintf("catalog file %s no longer exists in the repository", project.CatalogFilePath)
+			if err := s.projectRepo.MarkOrphaned(ctx, project.ID, reason); err != nil {
+				result.Failed = append(result.Failed, ReconcileFailure{File: project.CatalogFilePath, Error: err.Error()})
+				continue
+			}
+			result.Orphaned = append(result.Orphaned, project.CatalogFilePath)
+		case sha == project.LastSyncedSHA:
+			result.Unchanged++
+		default:
+			result.Changed = append(result.Changed, project.CatalogFilePath)
+			if err := s.resync(ctx, project, sha, syncType, userID, userName); err != nil {
+				result.Failed = append(result.Failed, ReconcileFailure{File: project.CatalogFilePath, Error: err.Error()})
+			}
+		}
+	}
+	for _, f := range files {
+		if !linked[f.Path] {
+			result.Untracked = append(result.Untracked, f.Path)
+		}
+	}
+
+	history.Plan = result
+	history.ProjectsUpdated = len(result.Changed) - len(result.Failed)
+	history.Status = "success"
+	if len(result.Failed) > 0 {
+		history.Status = "partial"
+		history.ErrorMessage = fmt.Sprintf("%d catalog files failed to reconcile", len(result.Failed))
+	}
+	now := time.Now()
+	history.CompletedAt = &now
+	history.DurationMs = now.Sub(history.StartedAt).Milliseconds()
+	_ = s.historyRepo.Update(ctx, history)
+
+	logger.Info("catalog reconciliation finished",
+		slog.Int("changed", len(result.Changed)),
+		slog.Int("orphaned", len(result.Orphaned)),
+		slog.Int("untracked", len(result.Untracked)),
+		slog.Int("failed", len(result.Failed)),
+		slog.Int64("duration_ms", history.DurationMs),
+	)
+	return result, nil
+}
+
+// resync syncs a project's catalog file and records the blob SHA it was synced at
+func (s *Syncer) resync(ctx context.Context, project models.Project, sha string, syncType string, userID string, userName string) error {
+	history, err := s.syncProject(ctx, syncType, project.CatalogFilePath, project.OwnerTeamID, userID, userName)
+	if err != nil {
+		return err
+	}
+	if history.Status != "success" {
+		return fmt.Errorf("sync %s", history.Status)
+	}
+	return s.projectRepo.SetLastSyncedSHA(ctx, history.ProjectID, sha)
+}
+
+// ScheduledReconciler runs Syncer.Reconcile periodically
+type ScheduledReconciler struct {
+	syncer  *Syncer
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	running bool
+}
+
+// NewScheduledReconciler creates a scheduled reconciler for syncer
+func NewScheduledReconciler(syncer *Syncer) *ScheduledReconciler {
+	return &ScheduledReconciler{syncer: syncer}
+}
+
+// Start reconciles on every interval; the first run is one interval after start
+func (r *ScheduledReconciler) Start(interval time.Duration) {
+	r.mu.Lock()
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.stopCh = make(chan struct{})
+	r.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.run(context.Background())
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+
+	slog.Info("catalog reconciliation scheduled", slog.Duration("interval", interval))
+}
+
+// Stop stops the periodic reconciliation
+func (r *ScheduledReconciler) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		close(r.stopCh)
+		r.running = false
+	}
+}
+
+// run reconciles once, skipping installations without an enabled GitHub integration
+func (r *ScheduledReconciler) run(ctx context.Context) {
+	config, err := r.syncer.configRepo.GetConfig(ctx)
+	if err != nil || config == nil || !config.Enabled {
+		return
+	}
+
+	if _, err := r.syncer.Reconcile(ctx, "scheduled", "", "System"); err != nil {
+		logging.FromContext(ctx).Error("scheduled catalog reconciliation failed", slog.String("error", err.Error()))
+	}
+}
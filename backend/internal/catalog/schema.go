@@ -1,5 +1,11 @@
 package catalog
 
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
 // ProjectCatalog represents the root structure of the catalog-info.yaml file
 type ProjectCatalog struct {
 	APIVersion string          `yaml:"apiVersion"`
@@ -16,6 +22,36 @@ type ProjectMetadata struct {
 	Tags        []string `yaml:"tags,omitempty"`
 	Owner       string   `yaml:"owner"` // Team Name or UUID
 	Links       []Link   `yaml:"links,omitempty"`
+	Contacts    Contacts `yaml:"contacts,omitempty"`
+	Docs        string   `yaml:"docs,omitempty"` // Markdown file shown on the project page, relative to the repository root
+}
+
+// DocsPath returns metadata.docs as a clean path relative to the repository root,
+// or an error when it is absolute, leaves the repository or is not a markdown file
+func (m ProjectMetadata) DocsPath() (string, error) {
+	docs := strings.TrimSpace(m.Docs)
+	if docs == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(docs, "/") {
+		return "", fmt.Errorf("must be relative to the repository root")
+	}
+	cleaned := path.Clean(docs)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("must not leave the repository")
+	}
+	lower := strings.ToLower(cleaned)
+	if !strings.HasSuffix(lower, ".md") && !strings.HasSuffix(lower, ".markdown") {
+		return "", fmt.Errorf("must be a markdown file (.md or .markdown)")
+	}
+	return cleaned, nil
+}
+
+// Contacts are the project's default channels for reaching the owning team
+type Contacts struct {
+	Slack     string `yaml:"slack,omitempty"`     // Channel, e.g. #payments
+	Email     string `yaml:"email,omitempty"`     // Team mailing list
+	PagerDuty string `yaml:"pagerduty,omitempty"` // PagerDuty service ID
 }
 
 // ProjectSpec contains the list of services
@@ -29,12 +65,109 @@ type ServiceSpec struct {
 	Title        string       `yaml:"title"`
 	Description  string       `yaml:"description,omitempty"`
 	Language     string       `yaml:"language,omitempty"`
-	Environment  string       `yaml:"environment,omitempty"`
+	Environment  string       `yaml:"environment,omitempty"`  // Deprecated: use environments
+	Environments []string     `yaml:"environments,omitempty"` // Every environment the service runs in
 	Repository   string       `yaml:"repository,omitempty"`
 	Owner        string       `yaml:"owner,omitempty"` // Optional override
 	Tags         []string     `yaml:"tags,omitempty"`
 	Links        []Link       `yaml:"links,omitempty"`
 	Dependencies Dependencies `yaml:"dependencies,omitempty"`
+	DependsOn    []string     `yaml:"dependsOn,omitempty"` // Services this one calls at runtime: name, or project-name/service-name
+	OnCall       *OnCallSpec  `yaml:"oncall,omitempty"`    // Overrides the project contacts
+
+	// ArgoCD application per environment; can also be given as the
+	// portalight.dev/argocd-apps annotation ("production=app-prod,staging=app-staging")
+	ArgoCD      []ArgoCDAppSpec   `yaml:"argocd,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// AllEnvironments returns the environments the service runs in: the environments
+// list, the deprecated environment field and the environments of its ArgoCD apps,
+// without duplicates and in that order
+func (s ServiceSpec) AllEnvironments() []string {
+	envs := append([]string{}, s.Environments...)
+	if s.Environment != "" {
+		envs = append(envs, s.Environment)
+	}
+	if apps, err := s.ArgoCDApps(); err == nil {
+		for _, app := range apps {
+			envs = append(envs, app.Environment)
+		}
+	}
+
+	seen := make(map[string]bool, len(envs))
+	unique := []string{}
+	for _, env := range envs {
+		env = strings.TrimSpace(env)
+		if env == "" || seen[env] {
+			continue
+		}
+		seen[env] = true
+		unique = append(unique, env)
+	}
+	return unique
+}
+
+// AnnotationArgoCDApps lists ArgoCD apps as comma-separated environment=appName pairs
+const AnnotationArgoCDApps = "portalight.dev/argocd-apps"
+
+// ArgoCDAppSpec links a service to the ArgoCD application deploying one environment
+type ArgoCDAppSpec struct {
+	Environment string `yaml:"environment" json:"environment"`
+	AppName     string `yaml:"appName" json:"app_name"`
+}
+
+// ArgoCDApps returns the service's ArgoCD apps from the argocd block and the annotation
+func (s ServiceSpec) ArgoCDApps() ([]ArgoCDAppSpec, error) {
+	apps := append([]ArgoCDAppSpec{}, s.ArgoCD...)
+
+	value := s.Annotations[AnnotationArgoCDApps]
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		env, appName, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry '%s': expected environment=appName", pair)
+		}
+		apps = append(apps, ArgoCDAppSpec{
+			Environment: strings.TrimSpace(env),
+			AppName:     strings.TrimSpace(appName),
+		})
+	}
+
+	return apps, nil
+}
+
+// OnCallSpec describes who gets paged for a service and how incidents escalate
+type OnCallSpec struct {
+	Slack      string           `yaml:"slack,omitempty"`     // Incident channel
+	PagerDuty  string           `yaml:"pagerduty,omitempty"` // PagerDuty service ID
+	Rotation   string           `yaml:"rotation,omitempty"`  // Schedule name or URL
+	Escalation []EscalationStep `yaml:"escalation,omitempty"`
+}
+
+// EscalationStep is one level of an escalation policy
+type EscalationStep struct {
+	Contact      string `yaml:"contact"`                // Person, team or channel to escalate to
+	AfterMinutes int    `yaml:"afterMinutes,omitempty"` // Delay before this step is reached
+}
+
+// SlackChannel returns the service's on-call channel, falling back to the project contacts
+func (s ServiceSpec) SlackChannel(contacts Contacts) string {
+	if s.OnCall != nil && s.OnCall.Slack != "" {
+		return s.OnCall.Slack
+	}
+	return contacts.Slack
+}
+
+// PagerDutyService returns the service's PagerDuty service, falling back to the project contacts
+func (s ServiceSpec) PagerDutyService(contacts Contacts) string {
+	if s.OnCall != nil && s.OnCall.PagerDuty != "" {
+		return s.OnCall.PagerDuty
+	}
+	return contacts.PagerDuty
 }
 
 // Link represents an external link
@@ -2,16 +2,27 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/github"
+	"github.com/portalight/backend/internal/logging"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
+	"github.com/portalight/backend/internal/services"
 )
 
+// syncQueryTimeout bounds each statement of a catalog sync or reconciliation, which
+// upserts whole projects with their services and may outlast the default timeout
+const syncQueryTimeout = 30 * time.Second
+
 type Syncer struct {
 	githubClient *github.GitHubClient
 	projectRepo  *repositories.ProjectRepository
@@ -19,6 +30,17 @@ type Syncer struct {
 	teamRepo     *repositories.TeamRepository
 	historyRepo  *repositories.SyncHistoryRepository
 	configRepo   *repositories.GitHubConfigRepository
+	argocdRepo   *repositories.ArgoCDRepository
+	docsRepo     *repositories.ProjectDocsRepository
+	depRepo      *repositories.ServiceDependencyRepository
+	tagRepo      *repositories.TagVocabularyRepository
+	argocdClient *services.ArgoCDClient // Optional: used to warn about unknown ArgoCD apps
+	userRepo     *repositories.UserRepository
+	notifier     *services.UserNotifier // Emails sync failures
+	teamNotifier *services.TeamNotifier // Posts sync failures to the project's team channels
+
+	running     atomic.Int64 // Syncs currently in progress
+	reconciling sync.Mutex   // Held while a full-scan reconciliation runs
 }
 
 func NewSyncer(
@@ -27,16 +49,33 @@ func NewSyncer(
 	teamRepo *repositories.TeamRepository,
 	historyRepo *repositories.SyncHistoryRepository,
 	configRepo *repositories.GitHubConfigRepository,
+	userRepo *repositories.UserRepository,
+	notifier *services.UserNotifier,
+	teamNotifier *services.TeamNotifier,
 ) *Syncer {
 	return &Syncer{
-		projectRepo: projectRepo,
-		serviceRepo: serviceRepo,
-		teamRepo:    teamRepo,
-		historyRepo: historyRepo,
-		configRepo:  configRepo,
+		projectRepo:  projectRepo,
+		serviceRepo:  serviceRepo,
+		teamRepo:     teamRepo,
+		historyRepo:  historyRepo,
+		configRepo:   configRepo,
+		argocdRepo:   repositories.NewArgoCDRepository(),
+		docsRepo:     repositories.NewProjectDocsRepository(),
+		depRepo:      repositories.NewServiceDependencyRepository(),
+		tagRepo:      repositories.NewTagVocabularyRepository(),
+		argocdClient: services.NewArgoCDClient(),
+		userRepo:     userRepo,
+		notifier:     notifier,
+		teamNotifier: teamNotifier,
 	}
 }
 
+// QueueDepth returns the number of syncs currently in progress. Syncs run inline
+// (there is no async queue yet), so this is the whole sync backlog.
+func (s *Syncer) QueueDepth() int {
+	return int(s.running.Load())
+}
+
 // initClient initializes the GitHub client from the stored configuration
 func (s *Syncer) initClient(ctx context.Context) error {
 	config, err := s.configRepo.GetConfig(ctx)
@@ -64,41 +103,158 @@ func (s *Syncer) initClient(ctx context.Context) error {
 	return fmt.Errorf("no valid authentication method found")
 }
 
-// Scan lists available project files in the configured repository
+// Client returns the GitHub client built from the stored configuration
+func (s *Syncer) Client(ctx context.Context) (*github.GitHubClient, error) {
+	if err := s.initClient(ctx); err != nil {
+		return nil, err
+	}
+	return s.githubClient, nil
+}
+
+// Scan lists available project files under all configured paths of the repository
 func (s *Syncer) Scan(ctx context.Context) ([]string, error) {
+	files, err := s.scanFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filePaths := make([]string, 0, len(files))
+	for _, f := range files {
+		filePaths = append(filePaths, f.Path)
+	}
+	return filePaths, nil
+}
+
+// scanFiles lists the YAML files under all configured paths with their blob SHAs
+func (s *Syncer) scanFiles(ctx context.Context) ([]github.FileInfo, error) {
 	if err := s.initClient(ctx); err != nil {
 		return nil, err
 	}
 
 	config, _ := s.configRepo.GetConfig(ctx) // Already checked in initClient
 
-	files, err := s.githubClient.ListFiles(ctx, config.RepoOwner, config.RepoName, config.ProjectsPath, config.Branch)
+	// Paths may overlap (e.g. "services" and "services/payments"), so dedupe by file path
+	seen := make(map[string]bool)
+	var files []github.FileInfo
+	for _, projectsPath := range config.ProjectsPaths {
+		listed, err := s.githubClient.ListFiles(ctx, config.RepoOwner, config.RepoName, projectsPath, config.Branch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range listed {
+			// Simple filter for .yaml or .yml
+			if len(f.Name) > 5 && (f.Name[len(f.Name)-5:] == ".yaml" || f.Name[len(f.Name)-4:] == ".yml") {
+				if !seen[f.Path] {
+					seen[f.Path] = true
+					files = append(files, f)
+				}
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// fetchCatalog fetches and parses a catalog file from the configured repository
+func (s *Syncer) fetchCatalog(ctx context.Context, filePath string) (*ProjectCatalog, error) {
+	config, _ := s.configRepo.GetConfig(ctx) // Already checked in initClient
+
+	content, err := s.githubClient.GetFileContent(ctx, config.RepoOwner, config.RepoName, filePath, config.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file: %w", err)
+	}
+
+	catalog, err := ParseYAML(content)
 	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	return catalog, nil
+}
+
+// fetchDocs fetches the markdown file named by metadata.docs, or returns nil when
+// the catalog names none. The path was checked by schema validation.
+func (s *Syncer) fetchDocs(ctx context.Context, catalog *ProjectCatalog) (*models.ProjectDocs, error) {
+	docsPath, err := catalog.Metadata.DocsPath()
+	if err != nil || docsPath == "" {
 		return nil, err
 	}
+	config, _ := s.configRepo.GetConfig(ctx) // Already checked in initClient
 
-	var filePaths []string
-	for _, f := range files {
-		// Simple filter for .yaml or .yml
-		if len(f.Name) > 5 && (f.Name[len(f.Name)-5:] == ".yaml" || f.Name[len(f.Name)-4:] == ".yml") {
-			filePaths = append(filePaths, f.Path)
-		}
+	content, sha, err := s.githubClient.GetFile(ctx, config.RepoOwner, config.RepoName, docsPath, config.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch docs %s: %w", docsPath, err)
 	}
+	return &models.ProjectDocs{Path: docsPath, Content: string(content), SHA: sha}, nil
+}
 
-	return filePaths, nil
+// CommitStatusContext identifies catalog sync statuses on GitHub commits
+const CommitStatusContext = "portalight/catalog-sync"
+
+// commitStates maps sync statuses to GitHub commit status states
+var commitStates = map[string]string{
+	"running": "pending",
+	"success": "success",
+	"failed":  "failure",
+}
+
+// ReportCommitStatus sets the catalog sync status of a commit in the configured
+// repository, so developers see the outcome of a webhook sync in GitHub
+func (s *Syncer) ReportCommitStatus(ctx context.Context, sha string, syncStatus string, description string) error {
+	state, ok := commitStates[syncStatus]
+	if !ok {
+		return fmt.Errorf("no commit status for sync status '%s'", syncStatus)
+	}
+	if err := s.initClient(ctx); err != nil {
+		return err
+	}
+
+	config, _ := s.configRepo.GetConfig(ctx) // Already checked in initClient
+	return s.githubClient.SetCommitStatus(ctx, config.RepoOwner, config.RepoName, sha, state, description, CommitStatusContext)
+}
+
+// PreviewProject builds the sync plan for a project file without writing anything
+func (s *Syncer) PreviewProject(ctx context.Context, filePath string, teamID string) (*SyncPlan, error) {
+	if err := s.initClient(ctx); err != nil {
+		return nil, err
+	}
+
+	catalog, err := s.fetchCatalog(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildPlan(ctx, filePath, catalog, teamID)
 }
 
 // SyncProject syncs a single project file
 func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string, userID string, userName string) (*models.SyncHistory, error) {
+	return s.syncProject(ctx, "manual", filePath, teamID, userID, userName)
+}
+
+// ReconcileProject re-reads a project's catalog file and resolves any drift
+// (missing services, stale orphans, wrong owners). Safe to run repeatedly.
+func (s *Syncer) ReconcileProject(ctx context.Context, project *models.Project, userID string, userName string) (*models.SyncHistory, error) {
+	if project.CatalogFilePath == "" {
+		return nil, fmt.Errorf("project is not linked to a catalog file")
+	}
+	return s.syncProject(ctx, "reconcile", project.CatalogFilePath, project.OwnerTeamID, userID, userName)
+}
+
+// syncProject fetches, plans and applies a single project file
+func (s *Syncer) syncProject(ctx context.Context, syncType string, filePath string, teamID string, userID string, userName string) (*models.SyncHistory, error) {
+	s.running.Add(1)
+	defer s.running.Add(-1)
+	ctx = database.WithQueryTimeout(ctx, syncQueryTimeout)
+
 	if err := s.initClient(ctx); err != nil {
 		return nil, err
 	}
 
-	config, _ := s.configRepo.GetConfig(ctx)
-
 	history := &models.SyncHistory{
 		ID:              uuid.New().String(),
-		SyncType:        "manual",
+		SyncType:        syncType,
 		CatalogFilePath: filePath,
 		Status:          "running",
 		StartedAt:       time.Now(),
@@ -109,6 +265,8 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 	if err := s.historyRepo.Create(ctx, history); err != nil {
 		return nil, fmt.Errorf("failed to create sync history: %w", err)
 	}
+	ctx = logging.With(ctx, slog.String("sync_id", history.ID), slog.String("catalog_file", filePath))
+	logging.FromContext(ctx).Info("catalog sync started", slog.String("sync_type", syncType))
 
 	// Helper to finish sync
 	finish := func(status string, err error) (*models.SyncHistory, error) {
@@ -120,32 +278,80 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 			history.ErrorMessage = err.Error()
 		}
 		_ = s.historyRepo.Update(ctx, history)
+		logger := logging.FromContext(ctx).With(slog.String("status", status), slog.Int64("duration_ms", history.DurationMs))
+		if status == "failed" {
+			logger.Error("catalog sync failed", slog.String("error", history.ErrorMessage))
+		} else {
+			logger.Info("catalog sync finished")
+		}
+		if status == "failed" {
+			s.notifySyncFailure(ctx, history)
+		}
 		return history, err
 	}
 
-	// 1. Fetch Content
-	content, err := s.githubClient.GetFileContent(ctx, config.RepoOwner, config.RepoName, filePath, config.Branch)
+	// 1. Fetch and parse content
+	catalog, err := s.fetchCatalog(ctx, filePath)
 	if err != nil {
-		return finish("failed", fmt.Errorf("failed to fetch file: %w", err))
+		return finish("failed", err)
 	}
 
-	// 2. Parse
-	catalog, err := ParseYAML(content)
+	// 2. Resolve the plan (validation, owners, conflicts) before writing anything
+	plan, err := s.buildPlan(ctx, filePath, catalog, teamID)
 	if err != nil {
-		return finish("failed", fmt.Errorf("failed to parse yaml: %w", err))
+		return finish("failed", fmt.Errorf("failed to build sync plan: %w", err))
 	}
+	history.Plan = plan
 
 	// 3. Validate Schema
-	validationErrors := ValidateSchema(catalog)
-	if len(validationErrors) > 0 {
-		history.ValidationErrors = validationErrors
+	if len(plan.ValidationErrors) > 0 {
+		history.ValidationErrors = plan.ValidationErrors
 		return finish("failed", fmt.Errorf("schema validation failed"))
 	}
+	if len(plan.Errors) > 0 {
+		return finish("failed", fmt.Errorf("sync plan has conflicts: %s", strings.Join(plan.Errors, "; ")))
+	}
 
-	// 4. Use provided team ID as Owner
-	ownerTeamID := teamID
+	// 4. Fetch the project docs, so a missing docs file fails the sync before anything is written
+	docs, err := s.fetchDocs(ctx, catalog)
+	if err != nil {
+		return finish("failed", err)
+	}
 
-	// 5. Upsert Project
+	// 5. Apply the plan in a single transaction: a failure part-way leaves the
+	// previous state untouched and only the history records it
+	if err := s.applyPlan(ctx, filePath, catalog, plan, docs, history); err != nil {
+		if plan.Project.Action == PlanActionCreate {
+			history.ProjectID = "" // Rolled back with the rest
+		}
+		history.ProjectsCreated, history.ProjectsUpdated = 0, 0
+		history.ServicesCreated, history.ServicesUpdated, history.ServicesOrphaned = 0, 0, 0
+		return finish("failed", err)
+	}
+
+	return finish("success", nil)
+}
+
+// applyPlan writes the project, its docs, its services and their links, and removes
+// orphaned services, all in one transaction. docs is nil when the catalog names none.
+// history is filled in as the plan is applied.
+func (s *Syncer) applyPlan(ctx context.Context, filePath string, catalog *ProjectCatalog, plan *SyncPlan, docs *models.ProjectDocs, history *models.SyncHistory) error {
+	tx, err := repositories.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin sync transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	projectRepo := s.projectRepo.WithQuerier(tx)
+	serviceRepo := s.serviceRepo.WithQuerier(tx)
+	argocdRepo := s.argocdRepo.WithQuerier(tx)
+	depRepo := s.depRepo.WithQuerier(tx)
+	docsRepo := s.docsRepo.WithQuerier(tx)
+
+	// Use resolved team as Owner
+	ownerTeamID := plan.Project.OwnerTeamID
+
+	// Upsert Project
 	project := &models.Project{
 		Name: catalog.Metadata.Title, // Use Title as Name for display, or Metadata.Name?
 		// The DB has Name as unique. Metadata.Name is the ID (kebab-case). Metadata.Title is display.
@@ -157,6 +363,8 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 		Description:     catalog.Metadata.Description,
 		OwnerTeamID:     ownerTeamID,
 		CatalogFilePath: filePath,
+		CatalogName:     catalog.Metadata.Name,
+		Slug:            catalog.Metadata.Name,
 		CatalogMetadata: catalog,
 		AutoSynced:      true,
 		SyncStatus:      "success",
@@ -169,32 +377,46 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 		}
 	}
 
-	if err := s.projectRepo.UpsertFromCatalog(ctx, project); err != nil {
-		return finish("failed", fmt.Errorf("failed to upsert project: %w", err))
+	if err := projectRepo.UpsertFromCatalog(ctx, project); err != nil {
+		// A concurrent sync may have taken the name after the plan was built; the
+		// conflict already says which catalog file holds it
+		var conflict *repositories.ProjectConflictError
+		if errors.As(err, &conflict) {
+			return conflict
+		}
+		return fmt.Errorf("failed to upsert project: %w", err)
 	}
 	history.ProjectID = project.ID
 	history.ProjectName = project.Name
-	history.ProjectsUpdated = 1 // Or Created, hard to distinguish without checking first
+	if plan.Project.Action == PlanActionCreate {
+		history.ProjectsCreated = 1
+	} else {
+		history.ProjectsUpdated = 1
+	}
+
+	// Docs removed from the catalog are dropped with it
+	if docs != nil {
+		docs.ProjectID = project.ID
+		if err := docsRepo.Upsert(ctx, docs); err != nil {
+			return err
+		}
+	} else if err := docsRepo.Delete(ctx, project.ID); err != nil {
+		return err
+	}
+
+	// Upsert Services
+	logging.FromContext(ctx).Info("📊 [Sync] Found services in catalog", slog.Int("services", len(catalog.Spec.Services)))
+	servicePlans := make(map[string]ServicePlan)
+	for _, svcPlan := range plan.Services {
+		servicePlans[svcPlan.Name] = svcPlan
+	}
 
-	// 6. Upsert Services
-	fmt.Printf("📊 [Sync] Found %d services in catalog\n", len(catalog.Spec.Services))
-	log.Printf("📊 [Sync] Found %d services in catalog", len(catalog.Spec.Services))
 	var activeServiceNames []string
+	serviceIDs := make(map[string]string)
 	for _, svcSpec := range catalog.Spec.Services {
-		// Resolve Service Owner - default to project owner
-		serviceOwnerID := ownerTeamID
-		if svcSpec.Owner != "" {
-			svcTeam, err := s.teamRepo.FindByName(ctx, svcSpec.Owner)
-			if err != nil {
-				// Log warning but continue? Or fail?
-				// Let's fail for strictness as per plan
-				return finish("failed", fmt.Errorf("failed to find service owner team '%s': %w", svcSpec.Owner, err))
-			}
-			if svcTeam == nil {
-				return finish("failed", fmt.Errorf("service owner team '%s' not found", svcSpec.Owner))
-			}
-			serviceOwnerID = svcTeam.ID
-		}
+		// Service owner was resolved by the plan (defaults to project owner)
+		svcPlan := servicePlans[svcSpec.Name]
+		serviceOwnerID := svcPlan.OwnerTeamID
 
 		service := &models.Service{
 			Name: svcSpec.Name,   // This is the ID/Name
@@ -212,6 +434,9 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 			CatalogSource:   filePath,
 			AutoSynced:      true,
 			CatalogMetadata: svcSpec,
+
+			SlackChannel:     svcSpec.SlackChannel(catalog.Metadata.Contacts),
+			PagerDutyService: svcSpec.PagerDutyService(catalog.Metadata.Contacts),
 		}
 
 		for _, link := range svcSpec.Links {
@@ -223,19 +448,119 @@ func (s *Syncer) SyncProject(ctx context.Context, filePath string, teamID string
 			}
 		}
 
-		if err := s.serviceRepo.UpsertFromCatalog(ctx, service); err != nil {
-			return finish("failed", fmt.Errorf("failed to upsert service '%s': %w", svcSpec.Name, err))
+		if err := serviceRepo.UpsertFromCatalog(ctx, service); err != nil {
+			return fmt.Errorf("failed to upsert service '%s': %w", svcSpec.Name, err)
+		}
+		if err := serviceRepo.SetEnvironments(ctx, service.ID, svcSpec.AllEnvironments()); err != nil {
+			return fmt.Errorf("failed to sync environments for service '%s': %w", svcSpec.Name, err)
 		}
 		activeServiceNames = append(activeServiceNames, svcSpec.Name)
-		history.ServicesUpdated++
+		serviceIDs[svcSpec.Name] = service.ID
+
+		// Catalog-managed ArgoCD app links (validated by the plan)
+		apps := make(map[string]string)
+		for _, app := range svcPlan.ArgoCDApps {
+			apps[app.Environment] = app.AppName
+		}
+		if err := argocdRepo.SyncCatalogApps(ctx, service.ID, apps); err != nil {
+			return fmt.Errorf("failed to sync ArgoCD apps for service '%s': %w", svcSpec.Name, err)
+		}
+		if svcPlan.Action == PlanActionCreate {
+			history.ServicesCreated++
+		} else {
+			history.ServicesUpdated++
+		}
 	}
 
-	// 7. Handle Orphans - Delete services not in catalog
-	if err := s.serviceRepo.DeleteOrphanedServices(ctx, project.ID, activeServiceNames); err != nil {
-		return finish("failed", fmt.Errorf("failed to delete orphaned services: %w", err))
+	// Dependency edges, once every service of the file has an ID
+	if err := s.syncDependencies(ctx, serviceRepo, depRepo, catalog, serviceIDs); err != nil {
+		return err
 	}
-	// We'd need to count orphans to update history, but UpdateOrphanStatus doesn't return count.
-	// We can skip exact count for now.
 
-	return finish("success", nil)
+	// Handle Orphans - Delete services not in catalog
+	if err := serviceRepo.DeleteOrphanedServices(ctx, project.ID, activeServiceNames); err != nil {
+		return fmt.Errorf("failed to delete orphaned services: %w", err)
+	}
+	history.ServicesOrphaned = plan.CountServices(PlanActionOrphan)
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit sync: %w", err)
+	}
+	return nil
+}
+
+// syncDependencies replaces the dependsOn edges of the file's services.
+// serviceIDs holds the services of the file; references to other projects are looked up.
+func (s *Syncer) syncDependencies(ctx context.Context, serviceRepo *repositories.ServiceRepository, depRepo *repositories.ServiceDependencyRepository, catalog *ProjectCatalog, serviceIDs map[string]string) error {
+	externalIDs, err := serviceRepo.FindIDsByRefs(ctx, externalDependencies(catalog))
+	if err != nil {
+		return fmt.Errorf("failed to resolve service dependencies: %w", err)
+	}
+
+	for _, svcSpec := range catalog.Spec.Services {
+		targetIDs := []string{}
+		for _, entry := range svcSpec.DependsOn {
+			ref, err := parseDependencyRef(catalog.Metadata.Name, entry)
+			if err != nil {
+				continue
+			}
+			id, ok := serviceIDs[ref.Service]
+			if ref.Project != "" {
+				id, ok = externalIDs[ref.String()]
+			}
+			// Services of other projects that are not synced yet are linked on a later sync
+			if !ok {
+				logging.FromContext(ctx).Warn("⚠️  [Sync] Dependency does not exist yet, skipping", slog.String("dependency", ref.String()), slog.String("service", svcSpec.Name))
+				continue
+			}
+			targetIDs = append(targetIDs, id)
+		}
+		if err := depRepo.ReplaceForService(ctx, serviceIDs[svcSpec.Name], models.DependencyTypeRuntime, targetIDs); err != nil {
+			return fmt.Errorf("failed to sync dependencies for service '%s': %w", svcSpec.Name, err)
+		}
+	}
+	return nil
+}
+
+// notifySyncFailure emails a failed sync to the user who started it, or to every
+// superadmin when nobody did (webhook and scheduled syncs), and posts it to the
+// project's team channels
+func (s *Syncer) notifySyncFailure(ctx context.Context, history *models.SyncHistory) {
+	s.teamNotifier.Notify(models.TeamEvent{
+		Event:           models.NotificationSyncFailed,
+		ProjectID:       history.ProjectID,
+		ProjectName:     history.ProjectName,
+		CatalogFilePath: history.CatalogFilePath,
+		Actor:           history.SyncedByName,
+		Error:           history.ErrorMessage,
+	})
+
+	var recipients []string
+	if history.SyncedBy != "" {
+		if user, err := s.userRepo.FindByID(ctx, history.SyncedBy); err == nil && user.Email != "" {
+			recipients = []string{user.Email}
+		}
+	}
+	if len(recipients) == 0 {
+		emails, err := s.userRepo.EmailsByRole(ctx, models.RoleAdmin)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to find superadmins to notify", slog.Any("error", err))
+			return
+		}
+		recipients = emails
+	}
+
+	email := services.SyncFailureEmail{
+		ProjectName:     history.ProjectName,
+		CatalogFilePath: history.CatalogFilePath,
+		SyncType:        history.SyncType,
+		Error:           history.ErrorMessage,
+		StartedAt:       history.StartedAt,
+	}
+	if validationErrors, ok := history.ValidationErrors.([]ValidationError); ok {
+		for _, e := range validationErrors {
+			email.ValidationErrors = append(email.ValidationErrors, e.Field+": "+e.Message)
+		}
+	}
+	s.notifier.Notify(models.NotificationSyncFailed, recipients, "Catalog sync failed: "+history.CatalogFilePath, email)
 }
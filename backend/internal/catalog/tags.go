@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// normalizeTags normalizes a service's tags, maps aliases through the vocabulary and
+// drops duplicates. In strict mode tags outside the vocabulary are dropped; every
+// dropped or truncated tag is reported as a warning.
+func normalizeTags(tags []string, vocab *models.TagVocabulary) ([]string, []string) {
+	var known map[string]bool
+	if vocab != nil && vocab.Enabled {
+		known = make(map[string]bool, len(vocab.Tags))
+		for _, tag := range vocab.Tags {
+			known[tag] = true
+		}
+	}
+
+	var warnings []string
+	seen := make(map[string]bool, len(tags))
+	normalized := []string{}
+	for _, raw := range tags {
+		tag := models.NormalizeTag(raw)
+		if tag == "" {
+			continue
+		}
+		if len([]rune(strings.Join(strings.Fields(raw), " "))) > models.MaxTagLength {
+			warnings = append(warnings, fmt.Sprintf("tag '%s' was truncated to %d characters", raw, models.MaxTagLength))
+		}
+		if known != nil {
+			if canonical, ok := vocab.Aliases[tag]; ok {
+				tag = canonical
+			}
+			if vocab.Strict && !known[tag] {
+				warnings = append(warnings, fmt.Sprintf("tag '%s' is not in the tag vocabulary and was dropped", raw))
+				continue
+			}
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > models.MaxTagsPerEntity {
+		warnings = append(warnings, fmt.Sprintf("%d tags given, only the first %d are kept", len(normalized), models.MaxTagsPerEntity))
+		normalized = normalized[:models.MaxTagsPerEntity]
+	}
+	return normalized, warnings
+}
@@ -0,0 +1,199 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/redact"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// GitHubTeamSyncService mirrors the team memberships of a GitHub organization onto
+// the portalight teams with the same name (case-insensitive)
+type GitHubTeamSyncService struct {
+	syncer    *Syncer // Provides the GitHub client and configuration
+	teamRepo  *repositories.TeamRepository
+	userRepo  *repositories.UserRepository
+	auditRepo *repositories.AuditLogRepository
+}
+
+// NewGitHubTeamSyncService creates a new GitHubTeamSyncService
+func NewGitHubTeamSyncService(
+	syncer *Syncer,
+	teamRepo *repositories.TeamRepository,
+	userRepo *repositories.UserRepository,
+	auditRepo *repositories.AuditLogRepository,
+) *GitHubTeamSyncService {
+	return &GitHubTeamSyncService{
+		syncer:    syncer,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// Sync replaces the members of every matched portalight team with the GitHub team's
+// members that have a portalight account (linked by GitHub ID). Members without a
+// linked GitHub account are kept, since GitHub knows nothing about them. org defaults
+// to the owner of the catalog repository; every membership change is audited as
+// actorEmail.
+func (s *GitHubTeamSyncService) Sync(ctx context.Context, org, actorEmail string) (*models.GitHubTeamSyncResult, error) {
+	client, err := s.syncer.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if org == "" {
+		config, err := s.syncer.configRepo.GetConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		org = config.RepoOwner
+	}
+
+	githubTeams, err := client.ListOrgTeams(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.GitHubTeamSyncResult{
+		Org:            org,
+		Teams:          []models.GitHubTeamSyncTeam{},
+		UnmatchedTeams: []string{},
+		UnknownUsers:   []string{},
+	}
+	unknownUsers := make(map[string]bool)
+	userIDs := make(map[int64]string) // GitHub ID -> portalight user ID, "" if there is none
+
+	for _, githubTeam := range githubTeams {
+		team, err := s.teamRepo.FindByName(ctx, githubTeam.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up team '%s': %w", githubTeam.Name, err)
+		}
+		if team == nil {
+			result.UnmatchedTeams = append(result.UnmatchedTeams, githubTeam.Slug)
+			continue
+		}
+
+		githubMembers, err := client.ListTeamMembers(ctx, org, githubTeam.Slug)
+		if err != nil {
+			return nil, err
+		}
+
+		var memberIDs []string
+		for _, member := range githubMembers {
+			userID, ok := userIDs[member.ID]
+			if !ok {
+				userID, err = s.findUserByGitHubID(ctx, member.ID)
+				if err != nil {
+					return nil, err
+				}
+				userIDs[member.ID] = userID
+			}
+			if userID == "" {
+				unknownUsers[member.Login] = true
+				continue
+			}
+			memberIDs = append(memberIDs, userID)
+		}
+
+		localMembers, err := s.membersWithoutGitHub(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		memberIDs = append(memberIDs, localMembers...)
+
+		diff, err := s.teamRepo.UpdateTeamMembers(ctx, team.ID, memberIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update members of team '%s': %w", team.Name, err)
+		}
+		s.auditChanges(actorEmail, team, githubTeam.Slug, diff)
+
+		result.Teams = append(result.Teams, models.GitHubTeamSyncTeam{
+			TeamID:     team.ID,
+			TeamName:   team.Name,
+			GitHubTeam: githubTeam.Slug,
+			Changes:    *diff,
+		})
+	}
+
+	for login := range unknownUsers {
+		result.UnknownUsers = append(result.UnknownUsers, login)
+	}
+	sort.Strings(result.UnknownUsers)
+
+	if err := s.syncer.configRepo.UpdateTeamSyncAt(ctx); err != nil {
+		return nil, err
+	}
+	result.SyncedAt = time.Now()
+	return result, nil
+}
+
+// findUserByGitHubID returns the ID of the portalight user linked to a GitHub
+// account, or "" if there is none
+func (s *GitHubTeamSyncService) findUserByGitHubID(ctx context.Context, githubID int64) (string, error) {
+	user, err := s.userRepo.FindByGithubID(ctx, githubID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to look up GitHub user %d: %w", githubID, err)
+	}
+	return user.ID, nil
+}
+
+// membersWithoutGitHub returns the current members of a team that have no linked GitHub account
+func (s *GitHubTeamSyncService) membersWithoutGitHub(ctx context.Context, teamID string) ([]string, error) {
+	memberIDs, err := s.teamRepo.GetTeamMemberIDs(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	var local []string
+	for _, id := range memberIDs {
+		user, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load team member %s: %w", id, err)
+		}
+		if user.GithubID == 0 {
+			local = append(local, id)
+		}
+	}
+	return local, nil
+}
+
+// auditChanges records one audit log entry per member added to or removed from a team
+func (s *GitHubTeamSyncService) auditChanges(actorEmail string, team *models.Team, githubTeam string, diff *models.TeamMembershipDiff) {
+	record := func(action, memberID string) {
+		detailsJSON := redact.JSON(map[string]interface{}{
+			"team_id":     team.ID,
+			"team_name":   team.Name,
+			"user_id":     memberID,
+			"source":      "github",
+			"github_team": githubTeam,
+		})
+
+		entry := models.AuditLog{
+			UserEmail:    actorEmail,
+			Action:       action,
+			ResourceType: "team",
+			ResourceID:   team.ID,
+			ResourceName: team.Name,
+			Details:      detailsJSON,
+			Status:       "success",
+		}
+		if err := s.auditRepo.Create(context.Background(), &entry); err != nil {
+			log.Printf("Failed to audit %s for team %s: %v", action, team.Name, err)
+		}
+	}
+
+	for _, memberID := range diff.Added {
+		record("add_team_member", memberID)
+	}
+	for _, memberID := range diff.Removed {
+		record("remove_team_member", memberID)
+	}
+}
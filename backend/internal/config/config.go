@@ -1,23 +1,118 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/portalight/backend/internal/logging"
+)
+
+const minJWTSecretLength = 32
+
+// defaultRouteMaxInFlight caps expensive list endpoints well below the global limit
+const defaultRouteMaxInFlight = "/api/v1/projects=20,/api/v1/services=20,/api/v1/audit-logs=10,/api/v1/discover=5"
+
+// Credential backends selectable with CREDENTIAL_BACKEND
+const (
+	CredentialBackendDatabase       = "database"
+	CredentialBackendSecretsManager = "aws_secrets_manager"
 )
 
 type Config struct {
 	Port               string
+	PublicURL          string // Externally reachable base URL of the API, e.g. for webhooks
 	MetadataRepoURL    string
 	MetadataRepoBranch string
-	GithubToken        string
-	CORSAllowedOrigins []string
+	GithubToken        string   `redact:"true"`
+	CORSAllowedOrigins []string // Exact origins, "*", or wildcard subdomains like https://*.example.com
 	GithubClientID     string
-	GithubClientSecret string
+	GithubClientSecret string `redact:"true"`
 	GithubAllowedOrg   string
-	JWTSecret          string
-	EncryptionKey      string
+	JWTSecret          string `redact:"true"`
+	EncryptionKey      string `redact:"true"`
+
+	// GitLab OAuth login; disabled when GitlabClientID is empty
+	GitlabURL          string // gitlab.com or a self-hosted instance
+	GitlabClientID     string
+	GitlabClientSecret string `redact:"true"`
+	GitlabAllowedGroup string // Full path of the group users must belong to; required on gitlab.com
+
+	// OpenID Connect login (e.g. Google Workspace); disabled when OIDCIssuerURL is empty
+	OIDCIssuerURL     string
+	OIDCClientID      string
+	OIDCClientSecret  string `redact:"true"`
+	OIDCAllowedDomain string // Required hosted domain (hd claim); empty allows any account
+
+	// CORS preflight caching and credentialed requests (cookies, Authorization)
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// Where new AWS credentials are stored: "database" (AES-GCM, default) or "aws_secrets_manager"
+	CredentialBackend string
+
+	// Global limits for outbound AWS provisioning
+	ProvisionMaxConcurrency int
+	ProvisionQueueTimeout   time.Duration
+
+	// Reject provisioning requests without a ticket URL
+	RequireTicketURL bool
+
+	// Minimum level of structured logs: debug, info, warn or error
+	LogLevel string
+
+	// OTLP/HTTP trace exporter endpoint; tracing is disabled when empty
+	OTelExporterEndpoint string
+
+	// How often project budgets are evaluated
+	BudgetEvaluationInterval time.Duration
+
+	// How often every catalog file is compared against the database; zero disables it
+	CatalogReconcileInterval time.Duration
+
+	// How often the cleanup job runs, and how long resources marked deleted are kept
+	CleanupInterval       time.Duration
+	ResourceRetentionDays int
+
+	// Local hour (0-23) after which teams receive the previous day's digest
+	DigestSendHour int
+
+	// Outgoing mail for notifications; email is disabled when SMTPHost is empty
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string `redact:"true"`
+	SMTPFrom     string
+	SMTPTLSMode  string // starttls, tls or none; empty upgrades with STARTTLS when offered
+
+	// Database connection pool; every statement is bounded by DBQueryTimeout unless
+	// a long operation opts into a longer one
+	DBMaxConns        int
+	DBMinConns        int
+	DBMaxConnLifetime time.Duration
+	DBQueryTimeout    time.Duration
+
+	// In-flight HTTP request caps protecting the database pool
+	MaxInFlightRequests      int
+	RouteMaxInFlightRequests map[string]int
+
+	// Searches each user may run per minute; 0 disables the limit
+	SearchRateLimit int
+
+	// CIDR ranges allowed to manage credentials and provisioning permissions; empty allows all
+	AdminIPAllowlist []string
+	// CIDR ranges of reverse proxies whose X-Forwarded-For is believed; empty trusts none
+	TrustedProxies []string
+
+	// loadErrors collects problems reading *_FILE secrets, reported by Validate
+	loadErrors []error
 }
 
 func Load() *Config {
@@ -26,18 +121,249 @@ func Load() *Config {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	return &Config{
+	cfg := &Config{
 		Port:               getEnv("PORT", "8080"),
+		PublicURL:          strings.TrimRight(getEnv("PUBLIC_URL", ""), "/"),
 		MetadataRepoURL:    getEnv("METADATA_REPO_URL", ""),
 		MetadataRepoBranch: getEnv("METADATA_REPO_BRANCH", "main"),
-		GithubToken:        getEnv("GITHUB_TOKEN", ""),
-		CORSAllowedOrigins: []string{getEnv("CORS_ORIGIN", "http://localhost:3000")},
+		CORSAllowedOrigins: splitList(getEnv("CORS_ORIGIN", "http://localhost:3000")),
 		GithubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
-		GithubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
 		GithubAllowedOrg:   getEnv("GITHUB_ALLOWED_ORG", ""),
-		JWTSecret:          getEnv("JWT_SECRET", "secret-key-change-me"),
-		EncryptionKey:      getEnv("ENCRYPTION_KEY", ""),
 	}
+
+	cfg.CORSAllowCredentials = cfg.getEnvBool("CORS_ALLOW_CREDENTIALS", false)
+	cfg.CORSMaxAge = cfg.getEnvDuration("CORS_MAX_AGE", 24*time.Hour)
+	cfg.CredentialBackend = getEnv("CREDENTIAL_BACKEND", CredentialBackendDatabase)
+	cfg.ProvisionMaxConcurrency = cfg.getEnvInt("PROVISION_MAX_CONCURRENCY", 5)
+	cfg.ProvisionQueueTimeout = cfg.getEnvDuration("PROVISION_QUEUE_TIMEOUT", 10*time.Minute)
+	cfg.RequireTicketURL = cfg.getEnvBool("REQUIRE_TICKET_URL", false)
+	cfg.LogLevel = strings.ToLower(getEnv("LOG_LEVEL", "info"))
+	cfg.OTelExporterEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	cfg.BudgetEvaluationInterval = cfg.getEnvDuration("BUDGET_EVALUATION_INTERVAL", 24*time.Hour)
+	cfg.CatalogReconcileInterval = cfg.getEnvDuration("CATALOG_RECONCILE_INTERVAL", 6*time.Hour)
+	cfg.CleanupInterval = cfg.getEnvDuration("CLEANUP_INTERVAL", 24*time.Hour)
+	cfg.ResourceRetentionDays = cfg.getEnvInt("RESOURCE_RETENTION_DAYS", 30)
+	cfg.DigestSendHour = cfg.getEnvInt("DIGEST_SEND_HOUR", 8)
+	cfg.SMTPHost = getEnv("SMTP_HOST", "")
+	cfg.SMTPPort = cfg.getEnvInt("SMTP_PORT", 587)
+	cfg.SMTPUsername = getEnv("SMTP_USERNAME", "")
+	cfg.SMTPFrom = getEnv("SMTP_FROM", "")
+	cfg.SMTPTLSMode = getEnv("SMTP_TLS_MODE", "")
+	cfg.DBMaxConns = cfg.getEnvInt("DB_MAX_CONNS", 25)
+	cfg.DBMinConns = cfg.getEnvInt("DB_MIN_CONNS", 5)
+	cfg.DBMaxConnLifetime = cfg.getEnvDuration("DB_MAX_CONN_LIFETIME", time.Hour)
+	cfg.DBQueryTimeout = cfg.getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second)
+	cfg.MaxInFlightRequests = cfg.getEnvInt("HTTP_MAX_IN_FLIGHT", 100)
+	cfg.RouteMaxInFlightRequests = cfg.getEnvLimits("HTTP_ROUTE_MAX_IN_FLIGHT", defaultRouteMaxInFlight)
+	cfg.SearchRateLimit = cfg.getEnvInt("SEARCH_RATE_LIMIT", 60)
+	cfg.AdminIPAllowlist = splitList(getEnv("ADMIN_IP_ALLOWLIST", ""))
+	cfg.TrustedProxies = splitList(getEnv("TRUSTED_PROXIES", ""))
+	cfg.GitlabURL = strings.TrimRight(getEnv("GITLAB_URL", "https://gitlab.com"), "/")
+	cfg.GitlabClientID = getEnv("GITLAB_CLIENT_ID", "")
+	cfg.GitlabAllowedGroup = strings.Trim(getEnv("GITLAB_ALLOWED_GROUP", ""), "/")
+	cfg.OIDCIssuerURL = strings.TrimRight(getEnv("OIDC_ISSUER_URL", ""), "/")
+	cfg.OIDCClientID = getEnv("OIDC_CLIENT_ID", "")
+	cfg.OIDCAllowedDomain = getEnv("OIDC_ALLOWED_DOMAIN", "")
+
+	// Secrets can come from the environment or from a mounted file (KEY_FILE)
+	cfg.GithubToken = cfg.getSecret("GITHUB_TOKEN")
+	cfg.GithubClientSecret = cfg.getSecret("GITHUB_CLIENT_SECRET")
+	cfg.JWTSecret = cfg.getSecret("JWT_SECRET")
+	cfg.EncryptionKey = cfg.getSecret("ENCRYPTION_KEY")
+	cfg.SMTPPassword = cfg.getSecret("SMTP_PASSWORD")
+	cfg.OIDCClientSecret = cfg.getSecret("OIDC_CLIENT_SECRET")
+	cfg.GitlabClientSecret = cfg.getSecret("GITLAB_CLIENT_SECRET")
+
+	return cfg
+}
+
+// Validate fails fast on configuration that would make the server insecure or broken
+func (c *Config) Validate() error {
+	problems := append([]error{}, c.loadErrors...)
+
+	if c.JWTSecret == "" {
+		problems = append(problems, errors.New("JWT_SECRET is required"))
+	} else if len(c.JWTSecret) < minJWTSecretLength {
+		problems = append(problems, fmt.Errorf("JWT_SECRET must be at least %d characters", minJWTSecretLength))
+	}
+
+	if len(c.EncryptionKey) != 32 {
+		problems = append(problems, errors.New("ENCRYPTION_KEY must be exactly 32 bytes"))
+	}
+
+	if c.CredentialBackend != CredentialBackendDatabase && c.CredentialBackend != CredentialBackendSecretsManager {
+		problems = append(problems, fmt.Errorf("CREDENTIAL_BACKEND must be %q or %q", CredentialBackendDatabase, CredentialBackendSecretsManager))
+	}
+
+	if c.ProvisionMaxConcurrency < 1 {
+		problems = append(problems, errors.New("PROVISION_MAX_CONCURRENCY must be at least 1"))
+	}
+	if c.ProvisionQueueTimeout <= 0 {
+		problems = append(problems, errors.New("PROVISION_QUEUE_TIMEOUT must be positive"))
+	}
+	if c.BudgetEvaluationInterval <= 0 {
+		problems = append(problems, errors.New("BUDGET_EVALUATION_INTERVAL must be positive"))
+	}
+	if c.CatalogReconcileInterval < 0 {
+		problems = append(problems, errors.New("CATALOG_RECONCILE_INTERVAL must not be negative"))
+	}
+	if c.DBMaxConns < 1 {
+		problems = append(problems, errors.New("DB_MAX_CONNS must be at least 1"))
+	}
+	if c.DBMinConns < 0 || c.DBMinConns > c.DBMaxConns {
+		problems = append(problems, errors.New("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS"))
+	}
+	if c.DBMaxConnLifetime <= 0 {
+		problems = append(problems, errors.New("DB_MAX_CONN_LIFETIME must be positive"))
+	}
+	if c.DBQueryTimeout <= 0 {
+		problems = append(problems, errors.New("DB_QUERY_TIMEOUT must be positive"))
+	}
+	if c.CleanupInterval <= 0 {
+		problems = append(problems, errors.New("CLEANUP_INTERVAL must be positive"))
+	}
+	if c.ResourceRetentionDays < 0 {
+		problems = append(problems, errors.New("RESOURCE_RETENTION_DAYS must not be negative"))
+	}
+
+	if c.SearchRateLimit < 0 {
+		problems = append(problems, errors.New("SEARCH_RATE_LIMIT must not be negative"))
+	}
+
+	if !logging.ValidLevel(c.LogLevel) {
+		problems = append(problems, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel))
+	}
+
+	if c.DigestSendHour < 0 || c.DigestSendHour > 23 {
+		problems = append(problems, errors.New("DIGEST_SEND_HOUR must be between 0 and 23"))
+	}
+	if c.SMTPHost != "" && c.SMTPFrom == "" {
+		problems = append(problems, errors.New("SMTP_FROM is required when SMTP_HOST is set"))
+	}
+	switch c.SMTPTLSMode {
+	case "", "starttls", "tls", "none":
+	default:
+		problems = append(problems, fmt.Errorf("SMTP_TLS_MODE must be starttls, tls or none, got %q", c.SMTPTLSMode))
+	}
+
+	if c.MaxInFlightRequests < 0 {
+		problems = append(problems, errors.New("HTTP_MAX_IN_FLIGHT must not be negative (0 disables the limit)"))
+	}
+
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Errorf("invalid PUBLIC_URL %q: expected scheme://host[:port][/path]", c.PublicURL))
+		}
+	}
+
+	if c.OIDCIssuerURL != "" {
+		if u, err := url.Parse(c.OIDCIssuerURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			problems = append(problems, fmt.Errorf("invalid OIDC_ISSUER_URL %q: expected https://host[/path]", c.OIDCIssuerURL))
+		}
+		if c.OIDCClientID == "" || c.OIDCClientSecret == "" {
+			problems = append(problems, errors.New("OIDC_CLIENT_ID and OIDC_CLIENT_SECRET are required when OIDC_ISSUER_URL is set"))
+		}
+	}
+
+	if c.GitlabClientID != "" {
+		if u, err := url.Parse(c.GitlabURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Errorf("invalid GITLAB_URL %q: expected scheme://host[:port][/path]", c.GitlabURL))
+		}
+		if c.GitlabClientSecret == "" {
+			problems = append(problems, errors.New("GITLAB_CLIENT_SECRET is required when GITLAB_CLIENT_ID is set"))
+		}
+		// Anyone can sign up on gitlab.com, so logins must be limited to a group there
+		if c.GitlabAllowedGroup == "" && c.GitlabURL == "https://gitlab.com" {
+			problems = append(problems, errors.New("GITLAB_ALLOWED_GROUP is required when GITLAB_URL is https://gitlab.com"))
+		}
+	}
+
+	for _, cidr := range c.AdminIPAllowlist {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, fmt.Errorf("invalid ADMIN_IP_ALLOWLIST range %q: expected CIDR notation, e.g. 10.0.0.0/8", cidr))
+		}
+	}
+	for _, cidr := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, fmt.Errorf("invalid TRUSTED_PROXIES range %q: expected CIDR notation, e.g. 10.0.0.0/8", cidr))
+		}
+	}
+
+	for _, origin := range c.CORSAllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			problems = append(problems, err)
+		}
+		if origin == "*" && c.CORSAllowCredentials {
+			problems = append(problems, errors.New(`CORS_ORIGIN "*" cannot be combined with CORS_ALLOW_CREDENTIALS: list the allowed origins instead`))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		problems = append(problems, fmt.Errorf("invalid CORS_MAX_AGE %s: must not be negative", c.CORSMaxAge))
+	}
+
+	return errors.Join(problems...)
+}
+
+// getSecret reads KEY_FILE if set, otherwise the KEY env var, and logs the source (never the value)
+func (c *Config) getSecret(key string) string {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			c.loadErrors = append(c.loadErrors, fmt.Errorf("failed to read %s_FILE: %w", key, err))
+			return ""
+		}
+		log.Printf("Config: %s loaded from file %s", key, path)
+		return strings.TrimRight(string(content), "\r\n")
+	}
+
+	if value := os.Getenv(key); value != "" {
+		log.Printf("Config: %s loaded from environment", key)
+		return value
+	}
+
+	log.Printf("Config: %s not set", key)
+	return ""
+}
+
+// validateOrigin checks that a CORS origin is "*" or scheme://host[:port] without a
+// path. The host may start with "*." to allow any subdomain.
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+
+	candidate := origin
+	scheme, rest, wildcard := strings.Cut(origin, "://*.")
+	if wildcard {
+		candidate = scheme + "://" + rest
+	}
+
+	u, err := url.Parse(candidate)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid CORS origin %q: expected scheme://host[:port]", origin)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid CORS origin %q: must not contain a path, query or fragment", origin)
+	}
+	if strings.Contains(u.Host, "*") {
+		return fmt.Errorf("invalid CORS origin %q: a wildcard is only allowed as the first label, e.g. https://*.example.com", origin)
+	}
+	if wildcard && !strings.Contains(u.Hostname(), ".") {
+		return fmt.Errorf("invalid CORS origin %q: a wildcard needs a domain of at least two labels", origin)
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func getEnv(key, defaultValue string) string {
@@ -46,3 +372,60 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt reads an integer env var, recording a load error if it is malformed
+func (c *Config) getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		c.loadErrors = append(c.loadErrors, fmt.Errorf("invalid %s %q: must be an integer", key, value))
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvBool reads a boolean env var ("true", "false", "1", "0"), recording a load error if it is malformed
+func (c *Config) getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		c.loadErrors = append(c.loadErrors, fmt.Errorf("invalid %s %q: must be true or false", key, value))
+		return defaultValue
+	}
+	return b
+}
+
+// getEnvDuration reads a duration env var (e.g. "90s", "10m"), recording a load error if it is malformed
+func (c *Config) getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		c.loadErrors = append(c.loadErrors, fmt.Errorf("invalid %s %q: must be a duration like 10m", key, value))
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvLimits reads comma-separated path=limit pairs, recording a load error for malformed entries
+func (c *Config) getEnvLimits(key string, defaultValue string) map[string]int {
+	limits := make(map[string]int)
+	for _, pair := range splitList(getEnv(key, defaultValue)) {
+		path, value, ok := strings.Cut(pair, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || n < 0 || !strings.HasPrefix(path, "/") {
+			c.loadErrors = append(c.loadErrors, fmt.Errorf("invalid %s entry %q: expected /path=limit", key, pair))
+			continue
+		}
+		limits[strings.TrimSpace(path)] = n
+	}
+	return limits
+}
@@ -0,0 +1,146 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/portalight/backend/internal/telemetry"
+)
+
+// ReferencePrefix marks stored credentials that live in AWS Secrets Manager; the rest
+// of the value is the secret ARN
+const ReferencePrefix = "ssm://"
+
+// secretNamePrefix groups the portal's secrets in Secrets Manager
+const secretNamePrefix = "portalight/"
+
+// CredentialBackend stores credential payloads outside the database
+type CredentialBackend interface {
+	// Store saves value under a name derived from name and returns a reference to it
+	Store(ctx context.Context, name string, value string) (reference string, err error)
+	// Retrieve returns the value a reference points to
+	Retrieve(ctx context.Context, reference string) (string, error)
+	// Delete removes the value a reference points to; deleting a missing value succeeds
+	Delete(ctx context.Context, reference string) error
+}
+
+// activeBackend is set from config at startup; nil keeps credentials AES-GCM encrypted in PostgreSQL
+var activeBackend CredentialBackend
+
+// SetCredentialBackend selects where new credentials are stored
+func SetCredentialBackend(backend CredentialBackend) {
+	activeBackend = backend
+}
+
+// ActiveCredentialBackend returns the configured backend, or nil for the database default
+func ActiveCredentialBackend() CredentialBackend {
+	return activeBackend
+}
+
+// IsReference reports whether a stored credential value points to Secrets Manager
+func IsReference(stored string) bool {
+	return strings.HasPrefix(stored, ReferencePrefix)
+}
+
+// SecretsManagerBackend keeps credentials in AWS Secrets Manager. It authenticates with
+// the standard AWS environment (env vars, shared config, instance role), never with
+// credentials from the portal's own store.
+type SecretsManagerBackend struct {
+	client *secretsmanager.Client
+	region string
+}
+
+// NewSecretsManagerBackend creates a backend for the region of the default AWS config
+func NewSecretsManagerBackend(ctx context.Context) (*SecretsManagerBackend, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("AWS region is not set (AWS_REGION)")
+	}
+
+	return &SecretsManagerBackend{client: secretsmanager.NewFromConfig(cfg), region: cfg.Region}, nil
+}
+
+var unsafeSecretNameChars = regexp.MustCompile(`[^A-Za-z0-9/_+=.@-]+`)
+
+// Store creates a new secret and returns its ARN. A random suffix keeps names unique
+// since portal credential names are not.
+func (b *SecretsManagerBackend) Store(ctx context.Context, name string, value string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := io.ReadFull(rand.Reader, suffix); err != nil {
+		return "", fmt.Errorf("failed to generate secret name: %w", err)
+	}
+	secretName := secretNamePrefix + unsafeSecretNameChars.ReplaceAllString(name, "-") + "-" + hex.EncodeToString(suffix)
+
+	ctx, span := b.startSpan(ctx, "CreateSecret")
+	defer span.End()
+	out, err := b.client.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(secretName),
+		SecretString: aws.String(value),
+		Description:  aws.String("Portalight credential " + name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("secrets manager CreateSecret failed: %w", err)
+	}
+	return aws.ToString(out.ARN), nil
+}
+
+// Retrieve returns the current value of the secret identified by reference (ARN or name)
+func (b *SecretsManagerBackend) Retrieve(ctx context.Context, reference string) (string, error) {
+	ctx, span := b.startSpan(ctx, "GetSecretValue")
+	defer span.End()
+	out, err := b.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(reference)})
+	if err != nil {
+		return "", fmt.Errorf("secrets manager GetSecretValue failed: %w", err)
+	}
+	return aws.ToString(out.SecretString), nil
+}
+
+// Update stores a new version of an existing secret, e.g. after key rotation
+func (b *SecretsManagerBackend) Update(ctx context.Context, reference string, value string) error {
+	ctx, span := b.startSpan(ctx, "PutSecretValue")
+	defer span.End()
+	_, err := b.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(reference),
+		SecretString: aws.String(value),
+	})
+	if err != nil {
+		return fmt.Errorf("secrets manager PutSecretValue failed: %w", err)
+	}
+	return nil
+}
+
+// Delete schedules the secret for deletion after Secrets Manager's default recovery
+// window, so a credential deleted by mistake can still be restored in AWS
+func (b *SecretsManagerBackend) Delete(ctx context.Context, reference string) error {
+	ctx, span := b.startSpan(ctx, "DeleteSecret")
+	defer span.End()
+	_, err := b.client.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: aws.String(reference)})
+	var notFound *types.ResourceNotFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return fmt.Errorf("secrets manager DeleteSecret failed: %w", err)
+	}
+	return nil
+}
+
+// startSpan traces one Secrets Manager call
+func (b *SecretsManagerBackend) startSpan(ctx context.Context, action string) (context.Context, trace.Span) {
+	return telemetry.Tracer().Start(ctx, "SecretsManager."+action, trace.WithAttributes(
+		attribute.String("aws.region", b.region),
+	))
+}
@@ -17,10 +17,21 @@ var (
 	ErrInvalidKeyLength  = errors.New("encryption key must be 32 bytes")
 )
 
-// getKey retrieves the encryption key from environment variable
+// configuredKey is set from config at startup (e.g. loaded from ENCRYPTION_KEY_FILE)
+var configuredKey string
+
+// SetKey sets the encryption key, taking precedence over the ENCRYPTION_KEY env var
+func SetKey(key string) {
+	configuredKey = key
+}
+
+// getKey retrieves the encryption key from config or environment variable
 // The key must be exactly 32 bytes for AES-256
 func getKey() ([]byte, error) {
-	key := os.Getenv("ENCRYPTION_KEY")
+	key := configuredKey
+	if key == "" {
+		key = os.Getenv("ENCRYPTION_KEY")
+	}
 	if key == "" {
 		return nil, ErrKeyNotSet
 	}
@@ -64,37 +75,47 @@ func Encrypt(plaintext string) (string, error) {
 // Decrypt decrypts base64-encoded ciphertext using AES-256-GCM
 // Validates the authentication tag and returns plaintext
 func Decrypt(ciphertextB64 string) (string, error) {
+	plaintext, err := DecryptBytes(ciphertextB64)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// DecryptBytes is like Decrypt but returns the plaintext as a byte slice,
+// so callers can zero it once they are done with it
+func DecryptBytes(ciphertextB64 string) ([]byte, error) {
 	key, err := getKey()
 	if err != nil {
-		return "", fmt.Errorf("failed to get encryption key: %w", err)
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
 	}
 
 	ciphertext, err := base64.StdEncoding.DecodeString(ciphertextB64)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %w", err)
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
 	nonceSize := gcm.NonceSize()
 	if len(ciphertext) < nonceSize {
-		return "", ErrInvalidCiphertext
+		return nil, ErrInvalidCiphertext
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
 
 	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt: %w", err)
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
 	}
 
-	return string(plaintext), nil
+	return plaintext, nil
 }
@@ -5,14 +5,23 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var DB *pgxpool.Pool
+var DB *Pool
+
+// PoolConfig sizes the connection pool and bounds its statements
+type PoolConfig struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	QueryTimeout    time.Duration // Default per-statement timeout; see WithTimeout
+}
 
 // Connect establishes a connection pool to PostgreSQL
-func Connect() error {
+func Connect(poolConfig PoolConfig) error {
 	dbHost := getEnvWithDefault("DB_HOST", "localhost")
 	dbPort := getEnvWithDefault("DB_PORT", "5432")
 	dbUser := getEnvWithDefault("DB_USER", "alindchaurasia")
@@ -43,8 +52,15 @@ func Connect() error {
 	}
 
 	// Set pool configuration
-	config.MaxConns = 25
-	config.MinConns = 5
+	config.MaxConns = poolConfig.MaxConns
+	config.MinConns = poolConfig.MinConns
+	config.MaxConnLifetime = poolConfig.MaxConnLifetime
+	if poolConfig.QueryTimeout > 0 {
+		queryTimeout = poolConfig.QueryTimeout
+	}
+
+	// Trace every query (no-op unless tracing is enabled)
+	config.ConnConfig.Tracer = queryTracer{}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -52,12 +68,14 @@ func Connect() error {
 	}
 
 	// Test connection
-	if err := pool.Ping(context.Background()); err != nil {
+	ctx, cancel := WithTimeout(context.Background())
+	defer cancel()
+	if err := pool.Ping(ctx); err != nil {
 		return fmt.Errorf("unable to ping database: %w", err)
 	}
 
-	DB = pool
-	log.Printf("✅ Connected to PostgreSQL database: %s", dbName)
+	DB = &Pool{Pool: pool}
+	log.Printf("✅ Connected to PostgreSQL database: %s (max conns %d, min conns %d, query timeout %s)", dbName, config.MaxConns, config.MinConns, queryTimeout)
 	return nil
 }
 
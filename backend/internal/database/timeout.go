@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultQueryTimeout bounds a statement when Connect was given no query timeout
+const DefaultQueryTimeout = 5 * time.Second
+
+// StreamQueryTimeout bounds queries whose rows are streamed to a client, such as
+// exports. The timeout covers reading every row, not just running the query, so
+// the default would cut a large export off after its 200 has been sent.
+const StreamQueryTimeout = 10 * time.Minute
+
+// queryTimeout is the default per-statement timeout, set by Connect
+var queryTimeout = DefaultQueryTimeout
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout returns a context whose statements may run for up to d instead
+// of the default query timeout. Long operations such as catalog syncs and resource
+// discovery opt in with it; everything else inherits the default.
+func WithQueryTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, d)
+}
+
+// WithTimeout bounds a single statement, including the wait for a pool connection,
+// by the query timeout of ctx (see WithQueryTimeout). An earlier deadline of ctx
+// still applies.
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := queryTimeout
+	if d, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Pool is the connection pool. Its statements, and those of transactions begun on
+// it, are bounded by WithTimeout, so a saturated pool or a slow query returns
+// context.DeadlineExceeded instead of hanging the request.
+type Pool struct {
+	*pgxpool.Pool
+}
+
+// Exec runs a statement under the query timeout
+func (p *Pool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	return p.Pool.Exec(ctx, sql, args...)
+}
+
+// Query runs a query under the query timeout; the timeout ends when the rows are closed
+func (p *Pool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := WithTimeout(ctx)
+	rows, err := p.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+// QueryRow runs a query under the query timeout; the timeout ends when the row is scanned
+func (p *Pool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := WithTimeout(ctx)
+	return &timeoutRow{Row: p.Pool.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+// Begin starts a transaction whose statements are bounded by the query timeout.
+// The transaction as a whole is not, so a sync may hold it across many statements.
+func (p *Pool) Begin(ctx context.Context) (pgx.Tx, error) {
+	beginCtx, cancel := WithTimeout(ctx)
+	defer cancel()
+	tx, err := p.Pool.Begin(beginCtx)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutTx{Tx: tx}, nil
+}
+
+// timeoutTx bounds every statement of a transaction by the query timeout
+type timeoutTx struct {
+	pgx.Tx
+}
+
+func (t *timeoutTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	beginCtx, cancel := WithTimeout(ctx)
+	defer cancel()
+	tx, err := t.Tx.Begin(beginCtx)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutTx{Tx: tx}, nil
+}
+
+func (t *timeoutTx) Commit(ctx context.Context) error {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	return t.Tx.Commit(ctx)
+}
+
+func (t *timeoutTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	return t.Tx.Exec(ctx, sql, args...)
+}
+
+func (t *timeoutTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := WithTimeout(ctx)
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (t *timeoutTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := WithTimeout(ctx)
+	return &timeoutRow{Row: t.Tx.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+// timeoutRows releases the query timeout once the rows are read or closed
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.cancel() // pgx has closed the rows
+	return false
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+// timeoutRow releases the query timeout once the row is scanned
+type timeoutRow struct {
+	pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.Row.Scan(dest...)
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestWithTimeoutUsesDefault(t *testing.T) {
+	ctx, cancel := WithTimeout(context.Background())
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("expected a deadline")
+	}
+	if remaining := time.Until(deadline); remaining > queryTimeout || remaining < queryTimeout-time.Second {
+		t.Errorf("deadline in %s, want about %s", remaining, queryTimeout)
+	}
+}
+
+func TestWithQueryTimeoutOverridesDefault(t *testing.T) {
+	ctx, cancel := WithTimeout(WithQueryTimeout(context.Background(), StreamQueryTimeout))
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	if remaining := time.Until(deadline); remaining < StreamQueryTimeout-time.Second {
+		t.Errorf("deadline in %s, want about %s", remaining, StreamQueryTimeout)
+	}
+}
+
+func TestWithTimeoutKeepsEarlierDeadline(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelParent()
+
+	ctx, cancel := WithTimeout(WithQueryTimeout(parent, time.Hour))
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	if time.Until(deadline) > 10*time.Millisecond {
+		t.Error("the parent's earlier deadline was not kept")
+	}
+}
+
+// fakeRows returns n rows and records whether it was closed
+type fakeRows struct {
+	pgx.Rows
+	n      int
+	closed bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.n == 0 {
+		return false
+	}
+	r.n--
+	return true
+}
+
+func (r *fakeRows) Close() { r.closed = true }
+
+func TestTimeoutRowsReleaseTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		read func(rows pgx.Rows)
+	}{
+		{"read to the end", func(rows pgx.Rows) {
+			for rows.Next() {
+			}
+		}},
+		{"closed early", func(rows pgx.Rows) {
+			rows.Next()
+			rows.Close()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			rows := &timeoutRows{Rows: &fakeRows{n: 3}, cancel: cancel}
+
+			tt.read(rows)
+			if ctx.Err() == nil {
+				t.Error("timeout was not released")
+			}
+		})
+	}
+}
+
+// testPool connects to TEST_DATABASE_URL; the test is skipped when it is not set
+func testPool(t *testing.T) *Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return &Pool{Pool: pool}
+}
+
+// setQueryTimeout changes the default query timeout for the duration of a test
+func setQueryTimeout(t *testing.T, d time.Duration) {
+	previous := queryTimeout
+	queryTimeout = d
+	t.Cleanup(func() { queryTimeout = previous })
+}
+
+func TestSlowQueryIsCancelled(t *testing.T) {
+	pool := testPool(t)
+	setQueryTimeout(t, 100*time.Millisecond)
+
+	start := time.Now()
+	_, err := pool.Exec(context.Background(), "SELECT pg_sleep(5)")
+	if err == nil {
+		t.Fatal("slow query was not cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("query ran for %s before it was cancelled: %v", elapsed, err)
+	}
+}
+
+func TestStreamedRowsOutliveDefaultTimeout(t *testing.T) {
+	pool := testPool(t)
+	setQueryTimeout(t, 100*time.Millisecond)
+
+	// Reading the rows takes longer than the default timeout, as a slow export client would
+	ctx := WithQueryTimeout(context.Background(), StreamQueryTimeout)
+	rows, err := pool.Query(ctx, "SELECT generate_series(1, 3)")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		time.Sleep(60 * time.Millisecond)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("reading rows failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("read %d rows, want 3", count)
+	}
+}
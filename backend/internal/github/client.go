@@ -3,9 +3,14 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v57/github"
+	"github.com/portalight/backend/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 )
 
@@ -45,25 +50,37 @@ type FileInfo struct {
 
 // GetFileContent retrieves the content of a file from the repository
 func (c *GitHubClient) GetFileContent(ctx context.Context, owner, repo, path, branch string) ([]byte, error) {
+	content, _, err := c.GetFile(ctx, owner, repo, path, branch)
+	return content, err
+}
+
+// GetFile retrieves the content of a file from the repository along with its blob SHA
+func (c *GitHubClient) GetFile(ctx context.Context, owner, repo, path, branch string) ([]byte, string, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "GitHubClient.GetFile", trace.WithAttributes(
+		attribute.String("github.repo", owner+"/"+repo),
+		attribute.String("github.path", path),
+	))
+	defer span.End()
+
 	opts := &github.RepositoryContentGetOptions{
 		Ref: branch,
 	}
 
 	fileContent, _, _, err := c.client.Repositories.GetContents(ctx, owner, repo, path, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file content: %w", err)
+		return nil, "", fmt.Errorf("failed to get file content: %w", err)
 	}
 
 	if fileContent == nil {
-		return nil, fmt.Errorf("file not found or is a directory: %s", path)
+		return nil, "", fmt.Errorf("file not found or is a directory: %s", path)
 	}
 
 	content, err := fileContent.GetContent()
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode file content: %w", err)
+		return nil, "", fmt.Errorf("failed to decode file content: %w", err)
 	}
 
-	return []byte(content), nil
+	return []byte(content), fileContent.GetSHA(), nil
 }
 
 // ListFiles recursively lists all files in a directory matching a pattern (simple suffix match for now)
@@ -119,6 +136,238 @@ func (c *GitHubClient) ValidateAccess(ctx context.Context, owner, repo string) e
 	return nil
 }
 
+// maxStatusDescription is the longest commit status description GitHub accepts
+const maxStatusDescription = 140
+
+// SetCommitStatus reports a status for a commit ("pending", "success", "failure" or "error").
+// Requires the repo:status (or repo) scope.
+func (c *GitHubClient) SetCommitStatus(ctx context.Context, owner, repo, sha, state, description, context string) error {
+	if runes := []rune(description); len(runes) > maxStatusDescription {
+		description = string(runes[:maxStatusDescription-1]) + "…"
+	}
+
+	status := &github.RepoStatus{
+		State:       github.String(state),
+		Description: github.String(description),
+		Context:     github.String(context),
+	}
+	if _, _, err := c.client.Repositories.CreateStatus(ctx, owner, repo, sha, status); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+	return nil
+}
+
+// TokenScopes returns the OAuth scopes of a classic PAT. ok is false when GitHub
+// does not report scopes (fine-grained tokens and GitHub Apps).
+func (c *GitHubClient) TokenScopes(ctx context.Context) (scopes []string, ok bool, err error) {
+	_, resp, err := c.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get token scopes: %w", err)
+	}
+	if _, ok := resp.Header["X-Oauth-Scopes"]; !ok {
+		return nil, false, nil
+	}
+
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true, nil
+}
+
+// HasScope reports whether scopes grant scope, either directly or through
+// its parent (e.g. "repo" grants "repo:status")
+func HasScope(scopes []string, scope string) bool {
+	parent, _, _ := strings.Cut(scope, ":")
+	for _, s := range scopes {
+		if s == scope || s == parent {
+			return true
+		}
+	}
+	return false
+}
+
+// Hook is a repository webhook
+type Hook struct {
+	ID     int64
+	URL    string // Payload URL
+	Events []string
+	Active bool
+}
+
+// ListHooks lists the webhooks of a repository. Requires admin:repo_hook (or repo).
+func (c *GitHubClient) ListHooks(ctx context.Context, owner, repo string) ([]Hook, error) {
+	var hooks []Hook
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.client.Repositories.ListHooks(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list hooks: %w", err)
+		}
+		for _, hook := range page {
+			url, _ := hook.Config["url"].(string)
+			hooks = append(hooks, Hook{
+				ID:     hook.GetID(),
+				URL:    url,
+				Events: hook.Events,
+				Active: hook.GetActive(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return hooks, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreateHook creates an active JSON webhook for events and returns its ID
+func (c *GitHubClient) CreateHook(ctx context.Context, owner, repo, url, secret string, events []string) (int64, error) {
+	hook, _, err := c.client.Repositories.CreateHook(ctx, owner, repo, newHook(url, secret, events))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create hook: %w", err)
+	}
+	return hook.GetID(), nil
+}
+
+// UpdateHook replaces the URL, secret and events of a webhook and activates it
+func (c *GitHubClient) UpdateHook(ctx context.Context, owner, repo string, id int64, url, secret string, events []string) error {
+	if _, _, err := c.client.Repositories.EditHook(ctx, owner, repo, id, newHook(url, secret, events)); err != nil {
+		return fmt.Errorf("failed to update hook %d: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteHook deletes a webhook. A hook that no longer exists is not an error.
+func (c *GitHubClient) DeleteHook(ctx context.Context, owner, repo string, id int64) error {
+	resp, err := c.client.Repositories.DeleteHook(ctx, owner, repo, id)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to delete hook %d: %w", id, err)
+	}
+	return nil
+}
+
+// GitHubTeam is a team of a GitHub organization
+type GitHubTeam struct {
+	ID   int64
+	Name string
+	Slug string
+}
+
+// GitHubUser is a GitHub account
+type GitHubUser struct {
+	ID    int64
+	Login string
+}
+
+// ListOrgTeams lists the teams of an organization. Requires read:org.
+func (c *GitHubClient) ListOrgTeams(ctx context.Context, org string) ([]GitHubTeam, error) {
+	var teams []GitHubTeam
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.client.Teams.ListTeams(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list teams of %s: %w", org, err)
+		}
+		for _, team := range page {
+			teams = append(teams, GitHubTeam{ID: team.GetID(), Name: team.GetName(), Slug: team.GetSlug()})
+		}
+		if resp.NextPage == 0 {
+			return teams, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// ListTeamMembers lists the members of an organization team, including members of
+// its child teams. Requires read:org.
+func (c *GitHubClient) ListTeamMembers(ctx context.Context, org, teamSlug string) ([]GitHubUser, error) {
+	var members []GitHubUser
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.client.Teams.ListTeamMembersBySlug(ctx, org, teamSlug, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of %s/%s: %w", org, teamSlug, err)
+		}
+		for _, user := range page {
+			members = append(members, GitHubUser{ID: user.GetID(), Login: user.GetLogin()})
+		}
+		if resp.NextPage == 0 {
+			return members, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// WorkflowRunSummary is the state of a GitHub Actions workflow run
+type WorkflowRunSummary struct {
+	WorkflowName string    `json:"workflow_name"`
+	RunID        int64     `json:"run_id"`
+	Status       string    `json:"status"`     // queued, in_progress, completed, ...
+	Conclusion   string    `json:"conclusion"` // success, failure, cancelled, ...; empty until completed
+	Branch       string    `json:"branch"`
+	RunStartedAt time.Time `json:"run_started_at"`
+	HTMLURL      string    `json:"html_url"`
+}
+
+// GetLatestWorkflowRun returns the most recent GitHub Actions run on branch, or nil if
+// the branch has no runs. An empty branch means the repository's default branch.
+// Requires actions:read (or repo for private repositories).
+func (c *GitHubClient) GetLatestWorkflowRun(ctx context.Context, owner, repo, branch string) (*WorkflowRunSummary, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "GitHubClient.GetLatestWorkflowRun", trace.WithAttributes(
+		attribute.String("github.repo", owner+"/"+repo),
+		attribute.String("github.branch", branch),
+	))
+	defer span.End()
+
+	if branch == "" {
+		repository, _, err := c.client.Repositories.Get(ctx, owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access repository %s/%s: %w", owner, repo, err)
+		}
+		branch = repository.GetDefaultBranch()
+	}
+
+	runs, _, err := c.client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		ListOptions: github.ListOptions{PerPage: 5},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	if len(runs.WorkflowRuns) == 0 {
+		return nil, nil
+	}
+
+	// Runs are listed newest first
+	run := runs.WorkflowRuns[0]
+	return &WorkflowRunSummary{
+		WorkflowName: run.GetName(),
+		RunID:        run.GetID(),
+		Status:       run.GetStatus(),
+		Conclusion:   run.GetConclusion(),
+		Branch:       run.GetHeadBranch(),
+		RunStartedAt: run.GetRunStartedAt().Time,
+		HTMLURL:      run.GetHTMLURL(),
+	}, nil
+}
+
+func newHook(url, secret string, events []string) *github.Hook {
+	return &github.Hook{
+		Config: map[string]interface{}{
+			"url":          url,
+			"content_type": "json",
+			"secret":       secret,
+			"insecure_ssl": "0",
+		},
+		Events: events,
+		Active: github.Bool(true),
+	}
+}
+
 func getFileName(path string) string {
 	parts := strings.Split(path, "/")
 	return parts[len(parts)-1]
@@ -0,0 +1,113 @@
+// Package logging configures the process-wide slog logger and carries a
+// request-scoped logger (request_id, user_id, route) through contexts
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"reflect"
+
+	"github.com/portalight/backend/internal/redact"
+)
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// Setup makes a JSON slog handler at the given level ("debug", "info", "warn" or
+// "error") the default. Lines written with the log package go through it at info.
+// Secrets are redacted from every record, see redactAttr.
+func Setup(level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	slog.SetDefault(slog.New(newHandler(os.Stderr, lvl)))
+	log.SetFlags(0) // slog adds the time
+	return nil
+}
+
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr})
+}
+
+// redactAttr masks attributes whose key looks like a secret, and logs structs, maps
+// and slices as their redacted copy so fields tagged redact:"true" never reach the log
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if redact.IsSensitiveName(a.Key) {
+		return slog.String(a.Key, redact.Mask)
+	}
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	value := a.Value.Any()
+	if _, ok := value.(error); ok {
+		return a
+	}
+	v := reflect.Indirect(reflect.ValueOf(value))
+	// Raw JSON is masked by key; other types with a String method are logged as text
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return slog.Any(a.Key, redact.Value(value))
+	}
+	if _, ok := value.(fmt.Stringer); ok {
+		return a
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return slog.Any(a.Key, redact.Value(value))
+	}
+	return a
+}
+
+// ValidLevel reports whether Setup accepts the level
+func ValidLevel(level string) bool {
+	var lvl slog.Level
+	return lvl.UnmarshalText([]byte(level)) == nil
+}
+
+// FromContext returns the context's logger, or the default logger if it has none
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// WithLogger returns a context carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// With returns a context whose logger adds the given attributes to every record
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// WithRequestID returns a context carrying the request ID, whose logger adds it to
+// every record as request_id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return With(ctx, slog.String("request_id", requestID))
+}
+
+// RequestID returns the context's request ID, or "" outside a request
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Detach returns a context that keeps ctx's values, including its logger, but is not
+// canceled with it, for work that outlives the request (e.g. async provisioning)
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
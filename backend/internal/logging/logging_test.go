@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Key      string `json:"key" redact:"true"`
+}
+
+func TestHandlerRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newHandler(&buf, slog.LevelInfo))
+
+	logger.Info("login",
+		slog.String("password", "hunter2"),
+		slog.Any("request", &loginRequest{Username: "alice", Key: "AKIASECRET"}),
+		slog.Any("config", json.RawMessage(`{"db_password":"s3cr3t","size":1}`)),
+		slog.Group("oauth", slog.String("access_token", "gho_token")),
+		slog.Any("error", errors.New("boom")),
+		slog.String("secret_id", "kept-id"),
+	)
+
+	line := buf.String()
+	for _, secret := range []string{"hunter2", "AKIASECRET", "s3cr3t", "gho_token"} {
+		if strings.Contains(line, secret) {
+			t.Errorf("log line leaks %q: %s", secret, line)
+		}
+	}
+	for _, kept := range []string{`"username":"alice"`, `"size":1`, `"error":"boom"`, `"secret_id":"kept-id"`} {
+		if !strings.Contains(line, kept) {
+			t.Errorf("log line lost %s: %s", kept, line)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+// Sources of a user's access in the access report
+const (
+	AccessSourceUser         = "user"                    // Granted to the user via project_access
+	AccessSourceTeam         = "team"                    // Granted to one of the user's teams via project_access
+	AccessSourceOwnerTeam    = "owner_team"              // Member of the project's owner team
+	AccessSourceRole         = "role"                    // Superadmins can access every project
+	AccessSourceProvisioning = "provisioning_permission" // May provision a resource type, in one project or everywhere
+)
+
+// AccessReportEntry is one way a user can access a project. A user reaching a
+// project in several ways has one entry per source.
+type AccessReportEntry struct {
+	UserID      string `json:"user_id"`
+	UserEmail   string `json:"user_email"`
+	UserName    string `json:"user_name"`
+	UserRole    Role   `json:"user_role"`
+	ProjectID   string `json:"project_id,omitempty"`   // Empty for provisioning permissions granted in every project
+	ProjectName string `json:"project_name,omitempty"` // Empty for provisioning permissions granted in every project
+	Source      string `json:"source"`
+	TeamName    string `json:"team_name,omitempty"`  // Team sources only
+	Permission  string `json:"permission,omitempty"` // Provisioning permissions only: the resource type
+	Credential  string `json:"credential,omitempty"` // Provisioning permissions limited to one credential
+}
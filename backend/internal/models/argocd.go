@@ -8,10 +8,21 @@ type ServiceArgoCDApp struct {
 	ServiceID       string    `json:"service_id"`
 	ArgoCDAppName   string    `json:"argocd_app_name"`
 	EnvironmentName string    `json:"environment_name"`
+	Source          string    `json:"source"` // manual or catalog
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Whether the linked service environment is active; unset when the service
+	// does not list the environment
+	EnvironmentActive *bool `json:"environment_active,omitempty"`
 }
 
+// ArgoCD app link sources
+const (
+	ArgoCDAppSourceManual  = "manual"  // Linked in the UI
+	ArgoCDAppSourceCatalog = "catalog" // Managed by catalog sync
+)
+
 // ArgoCDApplication represents an ArgoCD application from the ArgoCD API
 type ArgoCDApplication struct {
 	Name       string `json:"name"`
@@ -32,6 +43,61 @@ type ArgoCDPod struct {
 	Restarts   int      `json:"restarts"`
 	Age        string   `json:"age"`
 	Containers []string `json:"containers"`
+
+	// Owning workload from the resource tree, e.g. Deployment "api" via ReplicaSet "api-5d9c7"
+	WorkloadKind string `json:"workload_kind,omitempty"`
+	Workload     string `json:"workload,omitempty"`
+	ReplicaSet   string `json:"replica_set,omitempty"`
+
+	// Container requests and limits from the pod manifest; only with ?resources=true
+	Resources []ArgoCDContainerResources `json:"resources,omitempty"`
+}
+
+// ArgoCDContainerResources are the resource requests and limits of a container,
+// as Kubernetes quantities, e.g. {"cpu": "250m", "memory": "512Mi"}
+type ArgoCDContainerResources struct {
+	Container string            `json:"container"`
+	Requests  map[string]string `json:"requests,omitempty"`
+	Limits    map[string]string `json:"limits,omitempty"`
+}
+
+// ArgoCDPodDescription is the scheduling and container status of a pod, like kubectl describe
+type ArgoCDPodDescription struct {
+	Name       string                  `json:"name"`
+	Namespace  string                  `json:"namespace"`
+	Phase      string                  `json:"phase"` // Pending, Running, Succeeded, Failed, Unknown
+	NodeName   string                  `json:"node_name,omitempty"`
+	QOSClass   string                  `json:"qos_class,omitempty"` // Guaranteed, Burstable, BestEffort
+	Conditions []ArgoCDPodCondition    `json:"conditions"`
+	Containers []ArgoCDContainerStatus `json:"containers"`
+}
+
+// ArgoCDPodCondition is a pod condition, e.g. Ready or PodScheduled
+type ArgoCDPodCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"` // True, False, Unknown
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"last_transition_time,omitempty"`
+}
+
+// ArgoCDContainerStatus is the state of a container in a pod and why it last terminated
+type ArgoCDContainerStatus struct {
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	Ready        bool   `json:"ready"`
+	RestartCount int    `json:"restart_count"`
+	State        string `json:"state"`                  // running, waiting, terminated
+	StateReason  string `json:"state_reason,omitempty"` // e.g. CrashLoopBackOff
+
+	// Previous termination, set once the container has restarted
+	LastTerminationReason   string `json:"last_termination_reason,omitempty"` // e.g. OOMKilled, Error
+	LastTerminationMessage  string `json:"last_termination_message,omitempty"`
+	LastTerminationExitCode *int   `json:"last_termination_exit_code,omitempty"`
+	LastTerminatedAt        string `json:"last_terminated_at,omitempty"`
+
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
 }
 
 // ArgoCDAppStatus represents the full status of an ArgoCD application
@@ -39,3 +105,35 @@ type ArgoCDAppStatus struct {
 	Application ArgoCDApplication `json:"application"`
 	Pods        []ArgoCDPod       `json:"pods"`
 }
+
+// ArgoCDSyncOptions are the optional parameters of a sync
+type ArgoCDSyncOptions struct {
+	Prune    bool   `json:"prune,omitempty"`    // Delete resources no longer in Git
+	Revision string `json:"revision,omitempty"` // Git revision to sync to; empty uses the app's target revision
+}
+
+// ArgoCDOperationState is the state of an application's current or last sync operation
+type ArgoCDOperationState struct {
+	Phase      string `json:"phase"` // Running, Terminating, Succeeded, Failed, Error
+	Message    string `json:"message,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+	StartedAt  string `json:"started_at,omitempty"`
+	FinishedAt string `json:"finished_at,omitempty"` // Empty while running
+
+	// Per-resource results of the sync
+	ResourcesTotal  int `json:"resources_total"`
+	ResourcesSynced int `json:"resources_synced"`
+	ResourcesFailed int `json:"resources_failed"`
+}
+
+// ArgoCDEvent is a Kubernetes event about an application or one of its resources
+type ArgoCDEvent struct {
+	Type           string `json:"type"`   // Normal or Warning
+	Reason         string `json:"reason"` // e.g. OperationStarted, ResourceUpdated
+	Message        string `json:"message"`
+	Count          int    `json:"count"`
+	ObjectKind     string `json:"object_kind"`
+	ObjectName     string `json:"object_name"`
+	FirstTimestamp string `json:"first_timestamp,omitempty"`
+	LastTimestamp  string `json:"last_timestamp,omitempty"`
+}
@@ -11,9 +11,14 @@ type AuditLog struct {
 	ResourceType string    `json:"resource_type"` // e.g., "S3", "SQS", "SNS", "project"
 	ResourceID   string    `json:"resource_id,omitempty"`
 	ResourceName string    `json:"resource_name,omitempty"`
-	Details      string    `json:"details"` // JSON string with action details
+	ProjectID    string    `json:"project_id,omitempty"` // Project the action affected, if any
+	Details      string    `json:"details"`              // JSON string with action details
 	IPAddress    string    `json:"ip_address,omitempty"`
-	Status       string    `json:"status"`    // "success" or "failure"
-	Timestamp    time.Time `json:"timestamp"` // Changed from string to time.Time
+	Status       string    `json:"status"`                // "success" or "failure"
+	Route        string    `json:"route,omitempty"`       // "METHOD /pattern" of the API call, set by the audit middleware
+	StatusCode   int       `json:"status_code,omitempty"` // HTTP response status of the API call
+	DurationMs   int64     `json:"duration_ms,omitempty"` // Latency of the API call
+	RequestID    string    `json:"request_id,omitempty"`  // X-Request-ID of the API call, as in the request log
+	Timestamp    time.Time `json:"timestamp"`             // Changed from string to time.Time
 	CreatedAt    time.Time `json:"created_at"`
 }
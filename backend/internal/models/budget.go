@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// BudgetType is what a project budget measures
+type BudgetType string
+
+const (
+	BudgetTypeResourceCount BudgetType = "resource_count"
+	BudgetTypeEstimatedCost BudgetType = "estimated_cost" // USD per month
+)
+
+// ProjectBudget is a soft guardrail that alerts when a project exceeds a threshold
+type ProjectBudget struct {
+	ID            string     `json:"id"`
+	ProjectID     string     `json:"project_id"`
+	ProjectName   string     `json:"project_name,omitempty"`
+	Type          BudgetType `json:"type"`
+	ResourceType  string     `json:"resource_type,omitempty"` // resource_count only; empty counts all types
+	Threshold     float64    `json:"threshold"`
+	NotifyChannel string     `json:"notify_channel,omitempty"` // Incoming webhook URL
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Open breach, if any (not a column)
+	Breach *BudgetEvent `json:"breach,omitempty"`
+}
+
+// BudgetEvent records one breach of a budget, from first detection until resolved
+type BudgetEvent struct {
+	ID          string     `json:"id"`
+	BudgetID    string     `json:"budget_id"`
+	ProjectID   string     `json:"project_id"`
+	Value       float64    `json:"value"`
+	Threshold   float64    `json:"threshold"`
+	BreachedAt  time.Time  `json:"breached_at"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+	NotifiedAt  *time.Time `json:"notified_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// TeamDigestSettings is a team's opt-in for the daily change digest
+type TeamDigestSettings struct {
+	TeamID          string    `json:"team_id"`
+	TeamName        string    `json:"team_name,omitempty"`
+	Enabled         bool      `json:"enabled"`
+	Timezone        string    `json:"timezone"`                    // IANA name; the digest covers the previous local day
+	SlackWebhookURL string    `json:"slack_webhook_url,omitempty"` // Incoming webhook URL
+	EmailRecipients []string  `json:"email_recipients"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TeamDigest summarizes one day of changes in the projects a team owns
+type TeamDigest struct {
+	TeamName string
+	Date     time.Time // Local day covered by the digest
+	Timezone string
+
+	ProjectsSynced  []DigestItem
+	ServicesAdded   []DigestItem
+	ServicesRemoved []DigestItem
+
+	ResourcesProvisioned []DigestItem
+	ResourcesFailed      []DigestItem
+	ResourcesDeleted     []DigestItem
+
+	BudgetBreaches []DigestBudgetBreach
+}
+
+// DigestItem is one changed project, service or resource in a digest
+type DigestItem struct {
+	Project string
+	Name    string
+	Detail  string // e.g. resource type or sync status
+}
+
+// DigestBudgetBreach is a budget breach opened during the digest day
+type DigestBudgetBreach struct {
+	Project   string
+	Type      BudgetType
+	Value     float64
+	Threshold float64
+}
+
+// IsEmpty reports whether nothing happened during the digest day
+func (d *TeamDigest) IsEmpty() bool {
+	return len(d.ProjectsSynced) == 0 && len(d.ServicesAdded) == 0 && len(d.ServicesRemoved) == 0 &&
+		len(d.ResourcesProvisioned) == 0 && len(d.ResourcesFailed) == 0 && len(d.ResourcesDeleted) == 0 &&
+		len(d.BudgetBreaches) == 0
+}
@@ -14,23 +14,54 @@ const (
 	ResourceStatusUnknown DiscoveredResourceStatus = "unknown"
 )
 
+// discoveredTransitions lists the statuses each discovered-resource status may move to.
+// Deleted resources can come back when they reappear in AWS, and every resource is
+// reset to unknown before a full sync.
+var discoveredTransitions = map[DiscoveredResourceStatus][]DiscoveredResourceStatus{
+	ResourceStatusActive:  {ResourceStatusDeleted, ResourceStatusUnknown},
+	ResourceStatusDeleted: {ResourceStatusActive, ResourceStatusUnknown},
+	ResourceStatusUnknown: {ResourceStatusActive, ResourceStatusDeleted},
+}
+
+// CanTransitionTo reports whether a discovered resource may move from s to next
+func (s DiscoveredResourceStatus) CanTransitionTo(next DiscoveredResourceStatus) bool {
+	for _, allowed := range discoveredTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // DiscoveredResource represents an AWS resource discovered and tracked
 type DiscoveredResource struct {
 	ID           string                   `json:"id"`
 	ProjectID    string                   `json:"project_id"`
 	SecretID     string                   `json:"secret_id,omitempty"`
 	ARN          string                   `json:"arn"`
-	ResourceType string                   `json:"resource_type"` // s3, sqs, sns, rds, lambda
+	ResourceID   string                   `json:"resource_id,omitempty"` // Provisioned resource this row tracks, if any
+	ResourceType string                   `json:"resource_type"`         // s3, sqs, sns, rds, lambda, eks
 	Name         string                   `json:"name"`
 	Region       string                   `json:"region"`
 	Status       DiscoveredResourceStatus `json:"status"`
 	Metadata     json.RawMessage          `json:"metadata"`
+	Notes        string                   `json:"notes"`       // Set by leads, e.g. "legacy bucket, do not delete"
+	OwnerLabel   string                   `json:"owner_label"` // Owning squad or person, free-form
+	ManagedBy    string                   `json:"managed_by"`  // portalight, terraform or manual
 	LastSyncedAt *time.Time               `json:"last_synced_at,omitempty"`
 	DiscoveredAt time.Time                `json:"discovered_at"`
 	CreatedAt    time.Time                `json:"created_at"`
 	UpdatedAt    time.Time                `json:"updated_at"`
 }
 
+// UpdateDiscoveredResourceRequest changes the annotations of a discovered resource;
+// omitted fields are left as they are
+type UpdateDiscoveredResourceRequest struct {
+	Notes      *string `json:"notes"`
+	OwnerLabel *string `json:"owner_label"`
+	ManagedBy  *string `json:"managed_by"`
+}
+
 // AssociateResourcesRequest is the request to associate discovered resources with a project
 type AssociateResourcesRequest struct {
 	ProjectID string   `json:"project_id"`
@@ -43,4 +74,30 @@ type AssociateResourcesRequest struct {
 		Region       string          `json:"region"`
 		Metadata     json.RawMessage `json:"metadata"`
 	} `json:"resources"`
+
+	// SkipVerification stores the resources without checking that the credential
+	// can see them (superadmin only), for credentials that lack list permissions
+	SkipVerification bool `json:"skip_verification"`
+}
+
+// ResourceAssociationError explains why a submitted resource was not associated
+type ResourceAssociationError struct {
+	ARN   string `json:"arn"`
+	Error string `json:"error"`
+}
+
+// BulkAssociateResourcesRequest associates resources with a project by ARN alone.
+// ARNs not yet tracked by any project are looked up in AWS with the credential.
+type BulkAssociateResourcesRequest struct {
+	ProjectID string   `json:"project_id"`
+	SecretID  string   `json:"secret_id"` // Optional: defaults to the project's credential
+	Region    string   `json:"region"`    // Optional: defaults to the credential's region
+	ARNs      []string `json:"arns"`
+}
+
+// BulkAssociateResourcesResult summarizes a bulk association
+type BulkAssociateResourcesResult struct {
+	Associated     int      `json:"associated"`
+	AlreadyExisted int      `json:"already_existed"`
+	NotFound       []string `json:"not_found"`
 }
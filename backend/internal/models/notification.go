@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification events users can opt out of
+const (
+	NotificationProvisioningSucceeded = "provisioning_succeeded"
+	NotificationProvisioningFailed    = "provisioning_failed"
+	NotificationSyncFailed            = "sync_failed"
+	NotificationTeamDigest            = "team_digest"
+)
+
+// NotificationEvents lists every notification event, in display order
+var NotificationEvents = []string{
+	NotificationProvisioningSucceeded,
+	NotificationProvisioningFailed,
+	NotificationSyncFailed,
+	NotificationTeamDigest,
+}
+
+// SMTP TLS modes
+const (
+	SMTPTLSStartTLS = "starttls" // Upgrade with STARTTLS; fails if the server does not offer it
+	SMTPTLSImplicit = "tls"      // TLS from the first byte, usually port 465
+	SMTPTLSNone     = "none"     // Plain text; credentials are only sent to localhost
+)
+
+// SMTPSettings is the outgoing mail server configured in the UI. It takes precedence
+// over the SMTP_* environment variables once Host is set.
+type SMTPSettings struct {
+	Host        string    `json:"host"`
+	Port        int       `json:"port"`
+	TLSMode     string    `json:"tls_mode"`
+	From        string    `json:"from"`
+	Username    string    `json:"username"`
+	Password    string    `json:"password,omitempty" redact:"true"` // Write-only; empty keeps the stored password
+	PasswordSet bool      `json:"password_set"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Validate checks the settings; empty settings are valid and disable the override
+func (s *SMTPSettings) Validate() error {
+	if s.Host == "" {
+		return nil
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	switch s.TLSMode {
+	case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+	default:
+		return fmt.Errorf("tls_mode must be %s, %s or %s", SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone)
+	}
+	if s.From == "" {
+		return fmt.Errorf("from is required")
+	}
+	return nil
+}
+
+// NotificationPreferences says which notification events a user receives
+type NotificationPreferences struct {
+	Events    map[string]bool `json:"events"` // Every event in NotificationEvents
+	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
+}
+
+// Enabled reports whether the user receives an event; unknown events are delivered
+func (p *NotificationPreferences) Enabled(event string) bool {
+	enabled, ok := p.Events[event]
+	return !ok || enabled
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Notification channel types
+const (
+	NotificationChannelSlack   = "slack"   // Slack incoming webhook; receives a readable message
+	NotificationChannelWebhook = "webhook" // Any HTTP endpoint; receives the raw TeamEvent JSON
+)
+
+// NotificationResourceDeleted is sent to team channels when a resource sync finds that
+// a resource no longer exists in AWS. Users cannot opt into it by email.
+const NotificationResourceDeleted = "resource_deleted"
+
+// TeamNotificationEvents lists the events a team channel can subscribe to
+var TeamNotificationEvents = []string{
+	NotificationProvisioningSucceeded,
+	NotificationProvisioningFailed,
+	NotificationSyncFailed,
+	NotificationResourceDeleted,
+}
+
+// NotificationChannel is a team's Slack or webhook endpoint for project events
+type NotificationChannel struct {
+	ID        string    `json:"id"`
+	TeamID    string    `json:"team_id"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the channel type, URL and events; an empty event list subscribes
+// the channel to every event
+func (c *NotificationChannel) Validate() error {
+	switch c.Type {
+	case NotificationChannelSlack, NotificationChannelWebhook:
+	default:
+		return fmt.Errorf("type must be %s or %s", NotificationChannelSlack, NotificationChannelWebhook)
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if c.Type == NotificationChannelSlack && u.Scheme != "https" {
+		return fmt.Errorf("slack channels need an https webhook URL")
+	}
+
+	if len(c.Events) == 0 {
+		c.Events = slices.Clone(TeamNotificationEvents)
+	}
+	for _, event := range c.Events {
+		if !slices.Contains(TeamNotificationEvents, event) {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+	return nil
+}
+
+// TeamEvent is the payload posted to team notification channels
+type TeamEvent struct {
+	Event           string    `json:"event"`
+	ProjectID       string    `json:"project_id"`
+	ProjectName     string    `json:"project_name,omitempty"`
+	ResourceType    string    `json:"resource_type,omitempty"`
+	ResourceName    string    `json:"resource_name,omitempty"`
+	ARN             string    `json:"arn,omitempty"`
+	CatalogFilePath string    `json:"catalog_file_path,omitempty"`
+	Actor           string    `json:"actor,omitempty"` // Email of the user who started the action
+	Error           string    `json:"error,omitempty"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
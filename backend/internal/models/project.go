@@ -16,9 +16,12 @@ type Project struct {
 
 	// GitHub Integration Fields
 	CatalogFilePath string     `json:"catalog_file_path,omitempty"`
+	CatalogName     string     `json:"catalog_name,omitempty"`     // metadata.name of the catalog file
+	Slug            string     `json:"slug,omitempty"`             // Unique copy of metadata.name; survives title renames
 	CatalogMetadata any        `json:"catalog_metadata,omitempty"` // JSONB
 	LastSyncedAt    *time.Time `json:"last_synced_at,omitempty"`
-	SyncStatus      string     `json:"sync_status,omitempty"`
+	LastSyncedSHA   string     `json:"last_synced_sha,omitempty"` // Blob SHA of the catalog file at the last reconciliation
+	SyncStatus      string     `json:"sync_status,omitempty"`     // never_synced, success, failed or orphaned
 	SyncError       string     `json:"sync_error,omitempty"`
 	AutoSynced      bool       `json:"auto_synced"`
 
@@ -26,9 +29,33 @@ type Project struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ProjectStats aggregates the services and discovered resources of a project
+type ProjectStats struct {
+	ServiceCount          int            `json:"service_count"`
+	ServicesByLanguage    map[string]int `json:"services_by_language"`
+	ServicesByEnvironment map[string]int `json:"services_by_environment"` // A service counts once per environment it runs in
+	ServicesByTeam        map[string]int `json:"services_by_team"`
+	AutoSyncedCount       int            `json:"auto_synced_count"` // Services synced from the catalog file
+	ManualCount           int            `json:"manual_count"`
+	LastSyncedAt          *time.Time     `json:"last_synced_at,omitempty"` // Last catalog sync of the project
+	ResourceCount         int            `json:"resource_count"`           // Discovered resources that are not deleted
+	ResourcesByType       map[string]int `json:"resources_by_type"`
+}
+
 // ProjectWithServices includes the project and all its associated services
 type ProjectWithServices struct {
 	Project
-	Services []Service `json:"services"`
-	TeamName string    `json:"team_name,omitempty"`
+	Services       []Service       `json:"services"`
+	TeamName       string          `json:"team_name,omitempty"`
+	Budgets        []ProjectBudget `json:"budgets"`         // Each with its open breach, if any
+	BudgetBreached bool            `json:"budget_breached"` // True when any budget is in breach
+}
+
+// ProjectDocs is the markdown file a catalog project names as metadata.docs
+type ProjectDocs struct {
+	ProjectID string    `json:"project_id"`
+	Path      string    `json:"path"` // Relative to the catalog repository root
+	Content   string    `json:"content"`
+	SHA       string    `json:"sha"` // Blob SHA as of the last sync
+	FetchedAt time.Time `json:"fetched_at"`
 }
@@ -6,7 +6,9 @@ import "time"
 type ProvisioningPermission struct {
 	ID           string    `json:"id"`
 	UserID       string    `json:"user_id"`
-	ResourceType string    `json:"resource_type"` // s3, sqs, sns
+	ResourceType string    `json:"resource_type"`           // A registered provisioner type: s3, sqs, lambda, ...
+	ProjectID    string    `json:"project_id,omitempty"`    // Empty means any project
+	CredentialID string    `json:"credential_id,omitempty"` // Empty means any credential
 	GrantedBy    string    `json:"granted_by"`
 	GrantedAt    time.Time `json:"granted_at"`
 }
@@ -14,15 +16,109 @@ type ProvisioningPermission struct {
 // UserProvisioningPermissions represents all provisioning permissions for a user
 type UserProvisioningPermissions struct {
 	UserID       string   `json:"user_id"`
-	AllowedTypes []string `json:"allowed_types"` // ["s3", "sqs", "sns"]
-	S3Enabled    bool     `json:"s3_enabled"`
-	SQSEnabled   bool     `json:"sqs_enabled"`
-	SNSEnabled   bool     `json:"sns_enabled"`
+	AllowedTypes []string `json:"allowed_types"` // e.g. ["s3", "sqs"]
+
+	// Deprecated: mirror AllowedTypes for clients that predate it
+	S3Enabled       bool `json:"s3_enabled"`
+	SQSEnabled      bool `json:"sqs_enabled"`
+	SNSEnabled      bool `json:"sns_enabled"`
+	DynamoDBEnabled bool `json:"dynamodb_enabled"`
+
+	// Scoped lists permissions restricted to a project and/or credential.
+	// The fields above only reflect global permissions.
+	Scoped []ProvisioningPermission `json:"scoped"`
+}
+
+// SetLegacyFlags fills in the deprecated per-type booleans from AllowedTypes
+func (p *UserProvisioningPermissions) SetLegacyFlags() {
+	for _, t := range p.AllowedTypes {
+		if flag := legacyTypeFlag(p, t); flag != nil {
+			*flag = true
+		}
+	}
 }
 
 // UpdateProvisioningPermissionsRequest is the request to update a user's provisioning permissions
 type UpdateProvisioningPermissionsRequest struct {
-	S3Enabled  bool `json:"s3_enabled"`
-	SQSEnabled bool `json:"sqs_enabled"`
-	SNSEnabled bool `json:"sns_enabled"`
+	// Resource types to allow; each must be a registered provisionable type
+	AllowedTypes []string `json:"allowed_types"`
+
+	// Deprecated: still accepted and merged into AllowedTypes
+	S3Enabled       bool `json:"s3_enabled"`
+	SQSEnabled      bool `json:"sqs_enabled"`
+	SNSEnabled      bool `json:"sns_enabled"`
+	DynamoDBEnabled bool `json:"dynamodb_enabled"`
+
+	// Optional scope; when empty the permissions apply globally
+	ProjectID    string `json:"project_id,omitempty"`
+	CredentialID string `json:"credential_id,omitempty"`
+}
+
+// Types returns the requested resource types: AllowedTypes plus any type enabled
+// through the deprecated booleans, without duplicates
+func (r *UpdateProvisioningPermissionsRequest) Types() []string {
+	requested := append([]string{}, r.AllowedTypes...)
+	legacy := []struct {
+		resourceType string
+		enabled      bool
+	}{{"s3", r.S3Enabled}, {"sqs", r.SQSEnabled}, {"sns", r.SNSEnabled}, {"dynamodb", r.DynamoDBEnabled}}
+	for _, l := range legacy {
+		if l.enabled {
+			requested = append(requested, l.resourceType)
+		}
+	}
+
+	types := []string{}
+	seen := map[string]bool{}
+	for _, t := range requested {
+		if t != "" && !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// legacyTypeFlag returns the deprecated boolean for a resource type, or nil for
+// types added after the booleans were frozen
+func legacyTypeFlag(p *UserProvisioningPermissions, resourceType string) *bool {
+	switch resourceType {
+	case "s3":
+		return &p.S3Enabled
+	case "sqs":
+		return &p.SQSEnabled
+	case "sns":
+		return &p.SNSEnabled
+	case "dynamodb":
+		return &p.DynamoDBEnabled
+	}
+	return nil
+}
+
+// ApprovalStatus is the state of a dev's provisioning request
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusApproved ApprovalStatus = "approved"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+)
+
+// ProvisioningApproval is a dev's request to provision a resource, which a lead
+// must approve before anything is created in AWS
+type ProvisioningApproval struct {
+	ID          string         `json:"id"`
+	ResourceID  string         `json:"resource_id"`
+	SecretID    string         `json:"secret_id"` // Empty once the credential was deleted
+	RequestedBy string         `json:"requested_by"`
+	ApprovedBy  string         `json:"approved_by,omitempty"` // Lead who approved or rejected it
+	Status      ApprovalStatus `json:"status"`
+	Reason      string         `json:"reason,omitempty"` // Set on rejection
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// RejectProvisioningRequest is the body of POST /api/v1/provision/{resourceID}/reject
+type RejectProvisioningRequest struct {
+	Reason string `json:"reason"`
 }
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/portalight/backend/internal/redact"
+)
+
+// notSecret lists fields whose names look sensitive but hold no secret
+var notSecret = map[string]bool{
+	"AccessReportEntry.Credential": true, // Name of the credential a permission is limited to
+	"SMTPSettings.PasswordSet":     true, // Whether a password is stored
+}
+
+// TestSecretFieldsAreRedacted parses every file of the package, so a struct added later
+// is checked without being registered anywhere. A field whose Go or JSON name looks like
+// a secret (see redact.IsSensitiveName) must carry `redact:"true"`.
+func TestSecretFieldsAreRedacted(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatalf("failed to parse the models package: %v", err)
+	}
+
+	checked := 0
+	for _, pkg := range pkgs {
+		ast.Inspect(pkg, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				var tag reflect.StructTag
+				if field.Tag != nil {
+					value, _ := strconv.Unquote(field.Tag.Value)
+					tag = reflect.StructTag(value)
+				}
+				jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+				for _, name := range field.Names {
+					checked++
+					if notSecret[spec.Name.Name+"."+name.Name] || !redact.IsSensitiveName(name.Name) && !redact.IsSensitiveName(jsonName) {
+						continue
+					}
+					if tag.Get("redact") != "true" {
+						t.Errorf("%s: %s.%s looks like a secret but has no redact:\"true\" tag", fset.Position(name.Pos()), spec.Name.Name, name.Name)
+					}
+				}
+			}
+			return true
+		})
+	}
+	if checked == 0 {
+		t.Fatal("no struct fields found")
+	}
+}
@@ -5,17 +5,67 @@ import (
 	"time"
 )
 
+// ProvisionStatus is the lifecycle state of a resource provisioned through the portal
+type ProvisionStatus string
+
+const (
+	ProvisionStatusAwaitingApproval ProvisionStatus = "awaiting_approval" // Requested by a dev; a lead must approve it
+	ProvisionStatusProvisioning     ProvisionStatus = "provisioning"
+	ProvisionStatusActive           ProvisionStatus = "active"
+	ProvisionStatusFailed           ProvisionStatus = "failed"
+	ProvisionStatusDeleted          ProvisionStatus = "deleted"
+)
+
+// provisionTransitions lists the statuses each status may move to
+var provisionTransitions = map[ProvisionStatus][]ProvisionStatus{
+	ProvisionStatusAwaitingApproval: {ProvisionStatusProvisioning, ProvisionStatusFailed},
+	ProvisionStatusProvisioning:     {ProvisionStatusActive, ProvisionStatusFailed},
+	ProvisionStatusActive:           {ProvisionStatusDeleted},
+	ProvisionStatusFailed:           {ProvisionStatusDeleted},
+}
+
+// CanTransitionTo reports whether a resource may move from s to next
+func (s ProvisionStatus) CanTransitionTo(next ProvisionStatus) bool {
+	for _, allowed := range provisionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Who manages a resource's lifecycle. The portal only changes or deletes resources it manages.
+const (
+	ManagedByPortalight = "portalight"
+	ManagedByTerraform  = "terraform" // Also set for CloudFormation stacks
+	ManagedByManual     = "manual"
+)
+
+// IsValidManagedBy reports whether s is one of the ManagedBy values
+func IsValidManagedBy(s string) bool {
+	switch s {
+	case ManagedByPortalight, ManagedByTerraform, ManagedByManual:
+		return true
+	}
+	return false
+}
+
 type Resource struct {
-	ID        string          `json:"id"`
-	ProjectID string          `json:"project_id"`
-	Name      string          `json:"name"`
-	Type      string          `json:"type"`
-	Status    string          `json:"status"`
-	Config    json.RawMessage `json:"config"`
-	ARN       string          `json:"arn,omitempty"`
-	ErrorMsg  string          `json:"error_message,omitempty"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID          string          `json:"id"`
+	ProjectID   string          `json:"project_id"`
+	ProjectName string          `json:"project_name,omitempty"` // Only set by cross-project listings
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Status      ProvisionStatus `json:"status"`
+	Stage       string          `json:"stage,omitempty"` // e.g. "queued (3 ahead)", "provisioning"
+	Config      json.RawMessage `json:"config"`
+	ARN         string          `json:"arn,omitempty"`
+	ErrorMsg    string          `json:"error_message,omitempty"`
+	Reason      string          `json:"reason,omitempty"`     // Why the resource was requested
+	TicketURL   string          `json:"ticket_url,omitempty"` // Ticket tracking the request
+	ManagedBy   string          `json:"managed_by"`           // portalight, terraform or manual
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
 
 type CreateResourceRequest struct {
@@ -24,29 +74,108 @@ type CreateResourceRequest struct {
 	Name      string          `json:"name"`
 	Type      string          `json:"type"`
 	Config    json.RawMessage `json:"config"`
+	Reason    string          `json:"reason"`               // Required: why the resource is needed
+	TicketURL string          `json:"ticket_url,omitempty"` // Required when REQUIRE_TICKET_URL is set
 }
 
 // S3Config represents S3 bucket configuration
 type S3Config struct {
-	Region              string `json:"region"`
-	Versioning          bool   `json:"versioning"`
-	PublicAccessBlocked bool   `json:"public_access_blocked"`
-	Encryption          string `json:"encryption"` // "AES256" or "aws:kms"
+	Region              string            `json:"region"`
+	Versioning          bool              `json:"versioning"`
+	PublicAccessBlocked bool              `json:"public_access_blocked"`
+	Encryption          string            `json:"encryption"` // "AES256" or "aws:kms"
+	Tags                map[string]string `json:"tags,omitempty"`
+	LifecycleRules      []S3LifecycleRule `json:"lifecycle_rules,omitempty"`
+}
+
+// S3LifecycleRule expires and/or transitions the objects under a prefix.
+// Zero days leave that action out; a rule needs at least one of them.
+type S3LifecycleRule struct {
+	Prefix                 string `json:"prefix"` // Empty applies to the whole bucket
+	ExpireDays             int    `json:"expire_days,omitempty"`
+	TransitionDays         int    `json:"transition_days,omitempty"`
+	TransitionStorageClass string `json:"transition_storage_class,omitempty"` // "GLACIER", "STANDARD_IA", ...
+	Enabled                bool   `json:"enabled"`
 }
 
 // SQSConfig represents SQS queue configuration
 type SQSConfig struct {
-	Region               string `json:"region"`
-	QueueType            string `json:"queue_type"` // "standard" or "fifo"
-	VisibilityTimeout    int    `json:"visibility_timeout"`
-	MessageRetentionDays int    `json:"message_retention_days"`
-	DelaySeconds         int    `json:"delay_seconds"`
+	Region               string            `json:"region"`
+	QueueType            string            `json:"queue_type"` // "standard" or "fifo"
+	VisibilityTimeout    int               `json:"visibility_timeout"`
+	MessageRetentionDays int               `json:"message_retention_days"`
+	DelaySeconds         int               `json:"delay_seconds"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+
+	// Dead-letter queue created alongside the queue; receives messages after
+	// MaxReceiveCount failed receives (default 3). The name defaults to "<name>-dlq".
+	DeadLetterQueueEnabled bool   `json:"dead_letter_queue_enabled"`
+	MaxReceiveCount        int    `json:"max_receive_count,omitempty"`
+	DeadLetterQueueName    string `json:"dead_letter_queue_name,omitempty"`
 }
 
 // SNSConfig represents SNS topic configuration
 type SNSConfig struct {
-	Region    string `json:"region"`
-	TopicType string `json:"topic_type"` // "standard" or "fifo"
+	Region    string            `json:"region"`
+	TopicType string            `json:"topic_type"` // "standard" or "fifo"
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// SNSSubscription is a subscription to an SNS topic
+type SNSSubscription struct {
+	SubscriptionARN string `json:"subscription_arn"` // "PendingConfirmation" until an email/https endpoint confirms
+	Protocol        string `json:"protocol"`         // email, sqs, https or lambda
+	Endpoint        string `json:"endpoint"`
+}
+
+// DynamoDBConfig represents DynamoDB table configuration
+type DynamoDBConfig struct {
+	Region        string            `json:"region"`
+	BillingMode   string            `json:"billing_mode"` // "PAY_PER_REQUEST" or "PROVISIONED"
+	ReadCapacity  int64             `json:"read_capacity"`
+	WriteCapacity int64             `json:"write_capacity"`
+	HashKey       string            `json:"hash_key"`
+	HashKeyType   string            `json:"hash_key_type"` // "S", "N" or "B"
+	RangeKey      string            `json:"range_key,omitempty"`
+	RangeKeyType  string            `json:"range_key_type,omitempty"`
+	StreamEnabled bool              `json:"stream_enabled"`
+	TTLAttribute  string            `json:"ttl_attribute,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+// ElastiCacheConfig represents ElastiCache cluster configuration. Redis is created as
+// a replication group with NumNodes nodes (primary plus replicas); Memcached as a
+// cache cluster with NumNodes nodes.
+type ElastiCacheConfig struct {
+	Region                   string            `json:"region"`
+	NodeType                 string            `json:"node_type"` // e.g. "cache.t3.micro"
+	NumNodes                 int               `json:"num_nodes"`
+	Engine                   string            `json:"engine"` // "redis" or "memcached"
+	EngineVersion            string            `json:"engine_version,omitempty"`
+	SubnetGroupName          string            `json:"subnet_group_name,omitempty"`
+	SecurityGroupIDs         []string          `json:"security_group_ids,omitempty"`
+	AutomaticFailoverEnabled bool              `json:"automatic_failover_enabled"` // Redis only, needs at least 2 nodes
+	Tags                     map[string]string `json:"tags,omitempty"`
+}
+
+// LambdaConfig represents Lambda function configuration. The code is either a container
+// image (ImageURI) or a zip archive in S3 (S3Bucket and S3Key, with Runtime and Handler).
+type LambdaConfig struct {
+	Region         string            `json:"region"`
+	ImageURI       string            `json:"image_uri,omitempty"` // ECR image, e.g. "<account>.dkr.ecr.<region>.amazonaws.com/app:latest"
+	S3Bucket       string            `json:"s3_bucket,omitempty"`
+	S3Key          string            `json:"s3_key,omitempty"`
+	Runtime        string            `json:"runtime,omitempty"` // e.g. "python3.12"; zip archives only
+	Handler        string            `json:"handler,omitempty"` // e.g. "app.handler"; zip archives only
+	MemoryMB       int               `json:"memory_mb,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+	Environment    map[string]string `json:"environment,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+
+	// Execution role: an existing role ARN, or CreateRole for a new role that can
+	// only write CloudWatch logs (named "<function>-execution-role")
+	RoleARN    string `json:"role_arn,omitempty"`
+	CreateRole bool   `json:"create_role"`
 }
 
 // ProvisionResult contains the result of a provisioning operation
@@ -55,4 +184,23 @@ type ProvisionResult struct {
 	ARN     string `json:"arn,omitempty"`
 	Region  string `json:"region,omitempty"`
 	Error   string `json:"error,omitempty"`
+
+	// Endpoint is the address clients connect to (host:port), for resources that have one
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Tags actually applied to the resource (user tags plus portalight:* tags)
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Warnings are non-fatal problems after the resource was created (e.g. tagging failed)
+	Warnings []string `json:"warnings,omitempty"`
+
+	// SecondaryARNs are companion resources created with the main one, keyed by
+	// role (e.g. SecondaryDeadLetterQueue)
+	SecondaryARNs map[string]string `json:"secondary_arns,omitempty"`
+
+	// Details describe settings applied after creation (e.g. lifecycle rules) for the audit log
+	Details []string `json:"details,omitempty"`
 }
+
+// SecondaryDeadLetterQueue is the SecondaryARNs key of an SQS dead-letter queue
+const SecondaryDeadLetterQueue = "dead_letter_queue"
@@ -0,0 +1,36 @@
+package models
+
+// Ways a provisioned resource and its discovered resource can disagree
+const (
+	DivergenceUnlinked = "unlinked"        // Active with an ARN, but no discovered resource is linked to it
+	DivergenceStatus   = "status_mismatch" // Linked, but one side is deleted or gone while the other is active
+)
+
+// Repair actions for divergent pairs
+const (
+	RepairLink           = "link"            // Link the discovered resource with the same ARN
+	RepairDeleteResource = "delete_resource" // AWS no longer has it: mark the provisioned resource deleted
+	RepairUnlink         = "unlink"          // AWS still has it but the portal does not manage it: keep it as a plain discovered resource
+)
+
+// ResourceDivergence is a provisioned resource whose discovered_resources row is
+// missing or disagrees with it
+type ResourceDivergence struct {
+	Kind                 string                   `json:"kind"`
+	ResourceID           string                   `json:"resource_id"`
+	ProjectID            string                   `json:"project_id"`
+	Name                 string                   `json:"name"`
+	Type                 string                   `json:"type"`
+	ARN                  string                   `json:"arn,omitempty"`
+	ResourceStatus       ProvisionStatus          `json:"resource_status"`
+	DiscoveredResourceID string                   `json:"discovered_resource_id,omitempty"`
+	DiscoveredStatus     DiscoveredResourceStatus `json:"discovered_status,omitempty"`
+	Repair               string                   `json:"repair,omitempty"` // Empty when it cannot be repaired here, e.g. no discovered resource has the ARN yet
+}
+
+// ResourceReconciliation is the outcome of a repair run
+type ResourceReconciliation struct {
+	Linked    int64                `json:"linked"` // Links added by matching ARNs
+	Repaired  []ResourceDivergence `json:"repaired"`
+	Remaining []ResourceDivergence `json:"remaining"` // Still divergent, including those that cannot be repaired here
+}
@@ -0,0 +1,28 @@
+package models
+
+// Search result types
+const (
+	SearchTypeService  = "service"
+	SearchTypeProject  = "project"
+	SearchTypeResource = "resource"
+)
+
+// SearchResult is a single search hit
+type SearchResult struct {
+	Type         string  `json:"type"` // project, service or resource
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	ProjectID    string  `json:"project_id,omitempty"`
+	MatchedField string  `json:"matched_field"` // name, description, tags or arn
+	Highlight    string  `json:"highlight"`     // Snippet of the matched field
+	Rank         float64 `json:"-"`
+}
+
+// SearchResponse is the result of a global search
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+
+	// Truncated is true for each searched type that had more hits than the limit
+	Truncated map[string]bool `json:"truncated"`
+}
@@ -10,6 +10,11 @@ const (
 	AccessTypeRead  AccessType = "read"  // Can only discover existing resources
 )
 
+// CanWrite reports whether credentials of this access type may create or delete resources
+func (t AccessType) CanWrite() bool {
+	return t != AccessTypeRead
+}
+
 // Secret represents cloud provider credentials
 type Secret struct {
 	ID                   string     `json:"id"`
@@ -18,7 +23,7 @@ type Secret struct {
 	Region               string     `json:"region"`
 	AccountID            string     `json:"account_id,omitempty"` // AWS Account ID
 	AccessType           AccessType `json:"access_type"`          // read or write
-	CredentialsEncrypted string     `json:"-"`                    // Never expose in JSON
+	CredentialsEncrypted string     `json:"-" redact:"true"`      // Never expose in JSON
 	CreatedBy            string     `json:"created_by"`
 	CreatedAt            time.Time  `json:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at"`
@@ -26,8 +31,29 @@ type Secret struct {
 
 // AWSCredentials represents decrypted AWS access credentials
 type AWSCredentials struct {
-	AccessKeyID     string `json:"access_key_id"`
-	SecretAccessKey string `json:"secret_access_key"`
+	AccessKeyID     string `json:"access_key_id" redact:"true"`
+	SecretAccessKey string `json:"secret_access_key" redact:"true"`
+}
+
+// SecretUsage lists what still references a secret, so it is not deleted by accident
+type SecretUsage struct {
+	Projects            SecretUsageGroup `json:"projects"`             // Projects using it as default credential
+	Resources           SecretUsageGroup `json:"resources"`            // Provisioned resources managed with it
+	DiscoveredResources SecretUsageGroup `json:"discovered_resources"` // Discovered resources refreshed with it
+	PendingApprovals    SecretUsageGroup `json:"pending_approvals"`    // Resources awaiting approval to be provisioned with it
+	Permissions         SecretUsageGroup `json:"permissions"`          // Provisioning permissions limited to it, as "email: type"
+}
+
+// SecretUsageGroup is the number and names of one kind of referencing row
+type SecretUsageGroup struct {
+	Count int      `json:"count"`
+	Names []string `json:"names"`
+}
+
+// InUse reports whether anything references the secret
+func (u *SecretUsage) InUse() bool {
+	return u.Projects.Count > 0 || u.Resources.Count > 0 || u.DiscoveredResources.Count > 0 ||
+		u.PendingApprovals.Count > 0 || u.Permissions.Count > 0
 }
 
 // CreateSecretRequest is used when creating a new secret
@@ -37,6 +63,12 @@ type CreateSecretRequest struct {
 	Region          string     `json:"region"`
 	AccountID       string     `json:"account_id"`
 	AccessType      AccessType `json:"access_type"` // read or write
-	AccessKeyID     string     `json:"access_key_id"`
-	SecretAccessKey string     `json:"secret_access_key"`
+	AccessKeyID     string     `json:"access_key_id" redact:"true"`
+	SecretAccessKey string     `json:"secret_access_key" redact:"true"`
+}
+
+// RotateSecretRequest replaces the key pair stored for an existing secret
+type RotateSecretRequest struct {
+	AccessKeyID     string `json:"access_key_id" redact:"true"`
+	SecretAccessKey string `json:"secret_access_key" redact:"true"`
 }
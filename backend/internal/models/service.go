@@ -13,7 +13,8 @@ type Service struct {
 	TeamName      string   `json:"team_name,omitempty"`
 	ProjectID     string   `json:"project_id,omitempty"`
 	Description   string   `json:"description"`
-	Environment   string   `json:"environment"`
+	Environment   string   `json:"environment"`  // Deprecated: single environment, kept for older clients
+	Environments  []string `json:"environments"` // Every active environment the service runs in
 	Language      string   `json:"language"`
 	Tags          []string `json:"tags"`
 	Repository    string   `json:"repository"`
@@ -21,6 +22,10 @@ type Service struct {
 	GrafanaURL    string   `json:"grafana_url,omitempty"`
 	ConfluenceURL string   `json:"confluence_url,omitempty"`
 
+	// On-call contacts
+	SlackChannel     string `json:"slack_channel,omitempty"`
+	PagerDutyService string `json:"pagerduty_service,omitempty"`
+
 	// ArgoCD Integration
 	ArgoCDAppName string `json:"argocd_app_name,omitempty"`
 	ArgoCDURL     string `json:"argocd_url,omitempty"`
@@ -42,10 +47,31 @@ type Service struct {
 	MappedResources []ServiceResourceMapping `json:"mapped_resources,omitempty"`
 }
 
+// Service link types
+const (
+	ServiceLinkTypeGeneric         = "generic"
+	ServiceLinkTypeRunbook         = "runbook"
+	ServiceLinkTypeIncidentTracker = "incident_tracker"
+	ServiceLinkTypeMonitoring      = "monitoring"
+	ServiceLinkTypeAPIDocs         = "api_docs"
+	ServiceLinkTypeRepository      = "repository"
+)
+
+// IsValidServiceLinkType reports whether t is one of the service link types
+func IsValidServiceLinkType(t string) bool {
+	switch t {
+	case ServiceLinkTypeGeneric, ServiceLinkTypeRunbook, ServiceLinkTypeIncidentTracker,
+		ServiceLinkTypeMonitoring, ServiceLinkTypeAPIDocs, ServiceLinkTypeRepository:
+		return true
+	}
+	return false
+}
+
 // ServiceLink represents a custom link for a service (Sentry, PagerDuty, etc.)
 type ServiceLink struct {
 	ID        string    `json:"id"`
 	ServiceID string    `json:"service_id"`
+	Type      string    `json:"type"`
 	Label     string    `json:"label"`
 	URL       string    `json:"url"`
 	Icon      string    `json:"icon,omitempty"`
@@ -53,11 +79,30 @@ type ServiceLink struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ServiceEnvironment is one environment a service runs in
+type ServiceEnvironment struct {
+	ServiceID string    `json:"service_id"`
+	Name      string    `json:"name"`      // Free-form: production, staging, dev, ...
+	IsActive  bool      `json:"is_active"` // Inactive environments are kept but not listed in Service.Environments
+	CreatedAt time.Time `json:"created_at"`
+
+	// ArgoCD app linked for the same environment name, if any
+	ArgoCDAppID   string `json:"argocd_app_id,omitempty"`
+	ArgoCDAppName string `json:"argocd_app_name,omitempty"`
+}
+
+// CreateServiceEnvironmentRequest is the body of POST /api/v1/services/{id}/environments
+type CreateServiceEnvironmentRequest struct {
+	Name     string `json:"name"`
+	IsActive *bool  `json:"is_active"` // Defaults to true
+}
+
 // ServiceResourceMapping represents a mapping between a service and an AWS resource
 type ServiceResourceMapping struct {
 	ID                   string    `json:"id"`
 	ServiceID            string    `json:"service_id"`
 	DiscoveredResourceID string    `json:"discovered_resource_id"`
+	Environment          string    `json:"environment,omitempty"` // Empty when the resource serves every environment
 	CreatedAt            time.Time `json:"created_at"`
 
 	// Joined data
@@ -67,6 +112,27 @@ type ServiceResourceMapping struct {
 	Region       string `json:"region,omitempty"`
 }
 
+// DependencyTypeRuntime is the type of edges declared with dependsOn in the catalog
+const DependencyTypeRuntime = "runtime"
+
+// ServiceDependency is one edge of the service dependency graph, joined with
+// the service at the other end of the edge
+type ServiceDependency struct {
+	ServiceID      string    `json:"service_id"`
+	ServiceName    string    `json:"service_name"`
+	ProjectID      string    `json:"project_id,omitempty"`
+	DependencyType string    `json:"dependency_type"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ServiceDependencyGraph is the neighbourhood of one service in the dependency graph
+type ServiceDependencyGraph struct {
+	Upstream   []ServiceDependency `json:"upstream"`   // Services this one depends on
+	Downstream []ServiceDependency `json:"downstream"` // Services that depend on this one
+	InCycle    bool                `json:"in_cycle"`
+	Cycle      []string            `json:"cycle"` // Names of the services on a cycle through this one, including itself
+}
+
 // ProvisionRequest represents a resource provisioning request
 type ProvisionRequest struct {
 	SecretID     string                 `json:"secret_id"`
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Overall service health values
+const (
+	ServiceHealthHealthy   = "healthy"
+	ServiceHealthDegraded  = "degraded"  // Something needs attention, e.g. an app is progressing or a metric alerted
+	ServiceHealthUnhealthy = "unhealthy" // An app is degraded or missing, or a mapped resource was deleted
+	ServiceHealthUnknown   = "unknown"   // No ArgoCD apps or resources to judge by
+)
+
+// ServiceHealth combines the ArgoCD, AWS resource and metric state of a service
+type ServiceHealth struct {
+	ServiceID        string            `json:"service_id"`
+	ServiceName      string            `json:"service_name"`
+	Overall          string            `json:"overall"`
+	ArgoCDStatuses   map[string]string `json:"argocd_statuses"`   // Environment -> ArgoCD health
+	ResourceStatuses map[string]string `json:"resource_statuses"` // "type/name" -> discovered resource status
+	MetricAlerts     []string          `json:"metric_alerts"`
+	LastChecked      time.Time         `json:"last_checked"`
+}
@@ -16,9 +16,18 @@ type SyncHistory struct {
 	ServicesOrphaned int         `json:"services_orphaned"`
 	ErrorMessage     string      `json:"error_message,omitempty"`
 	ValidationErrors interface{} `json:"validation_errors,omitempty"` // JSONB
+	Plan             interface{} `json:"plan,omitempty"`              // JSONB
 	StartedAt        time.Time   `json:"started_at"`
 	CompletedAt      *time.Time  `json:"completed_at,omitempty"`
 	DurationMs       int64       `json:"duration_ms"`
 	SyncedBy         string      `json:"synced_by,omitempty"`
 	SyncedByName     string      `json:"synced_by_name,omitempty"`
 }
+
+// ProjectSyncResponse is returned by POST /api/v1/projects/{id}/sync
+type ProjectSyncResponse struct {
+	Success     bool   `json:"success"`
+	ProjectName string `json:"project_name"`
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+}
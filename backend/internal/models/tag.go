@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TagVocabulary is the optional controlled vocabulary for service tags applied during
+// catalog sync. Aliases map variants to canonical tags (e.g. "golang" -> "go").
+type TagVocabulary struct {
+	Enabled   bool              `json:"enabled"`
+	Strict    bool              `json:"strict"`  // Drop tags outside the vocabulary (reported as sync warnings)
+	Tags      []string          `json:"tags"`    // Canonical tags
+	Aliases   map[string]string `json:"aliases"` // Alias -> canonical tag
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// TagCount is a distinct service tag and the number of services carrying it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// Limits applied to service tags during catalog sync
+const (
+	MaxTagLength     = 30
+	MaxTagsPerEntity = 15
+)
+
+// NormalizeTag lowercases a tag, trims it, collapses inner whitespace to single
+// spaces and truncates it to MaxTagLength characters
+func NormalizeTag(tag string) string {
+	tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+	if runes := []rune(tag); len(runes) > MaxTagLength {
+		tag = strings.TrimSpace(string(runes[:MaxTagLength]))
+	}
+	return tag
+}
+
+// Normalize normalizes the vocabulary's tags and aliases in place and reports the
+// first alias that points at a tag outside the vocabulary
+func (v *TagVocabulary) Normalize() error {
+	seen := make(map[string]bool, len(v.Tags))
+	tags := []string{}
+	for _, tag := range v.Tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	v.Tags = tags
+
+	aliases := make(map[string]string, len(v.Aliases))
+	for alias, canonical := range v.Aliases {
+		alias, canonical = NormalizeTag(alias), NormalizeTag(canonical)
+		if alias == "" || alias == canonical {
+			continue
+		}
+		if !seen[canonical] {
+			return fmt.Errorf("alias '%s' maps to '%s', which is not in the vocabulary", alias, canonical)
+		}
+		aliases[alias] = canonical
+	}
+	v.Aliases = aliases
+	return nil
+}
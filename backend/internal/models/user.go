@@ -21,7 +21,9 @@ type User struct {
 	Avatar         string    `json:"avatar,omitempty"`
 	GithubID       int64     `json:"github_id,omitempty"`
 	GithubUsername string    `json:"github_username,omitempty"`
-	PasswordHash   string    `json:"-"` // Password hash, not exposed in JSON
+	GitlabID       int64     `json:"gitlab_id,omitempty"`
+	GitlabUsername string    `json:"gitlab_username,omitempty"`
+	PasswordHash   string    `json:"-" redact:"true"` // Password hash, not exposed in JSON
 	CreatedAt      time.Time `json:"created_at"`
 }
 
@@ -35,6 +37,30 @@ type Team struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// TeamMembershipDiff lists the users added to and removed from a team by a
+// membership update
+type TeamMembershipDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// GitHubTeamSyncResult summarizes a sync of team memberships from a GitHub organization
+type GitHubTeamSyncResult struct {
+	Org            string               `json:"org"`
+	Teams          []GitHubTeamSyncTeam `json:"teams"`           // Portalight teams matched by name
+	UnmatchedTeams []string             `json:"unmatched_teams"` // GitHub teams without a portalight team
+	UnknownUsers   []string             `json:"unknown_users"`   // GitHub logins without a portalight account
+	SyncedAt       time.Time            `json:"synced_at"`
+}
+
+// GitHubTeamSyncTeam is the membership change of one portalight team
+type GitHubTeamSyncTeam struct {
+	TeamID     string             `json:"team_id"`
+	TeamName   string             `json:"team_name"`
+	GitHubTeam string             `json:"github_team"` // Slug
+	Changes    TeamMembershipDiff `json:"changes"`
+}
+
 // Permission represents what a user can do
 type Permission struct {
 	Resource string `json:"resource"`
@@ -0,0 +1,204 @@
+// Package redact masks secrets before values are written to logs or audit details.
+//
+// Struct fields tagged `redact:"true"` are always masked. Untyped JSON (request
+// bodies, resource configs) is masked by key name, see IsSensitiveName.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Mask replaces redacted values
+const Mask = "[REDACTED]"
+
+// sensitiveNameParts mark a field or JSON key as secret when contained in its (lowercased) name
+var sensitiveNameParts = []string{"secret", "token", "password", "passwd", "private_key", "privatekey", "access_key", "accesskey", "credential", "authorization"}
+
+// referenceNames look sensitive but only hold IDs of stored secrets
+var referenceNames = map[string]bool{"secret_id": true, "secretid": true, "credential_id": true, "credentialid": true}
+
+// IsSensitiveName reports whether a field or key name looks like it holds a secret
+func IsSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	if referenceNames[lower] {
+		return false
+	}
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns a copy of v that is safe to log: structs become maps keyed by their
+// JSON names with tagged fields masked; slices, maps and pointers are walked recursively
+func Value(v interface{}) interface{} {
+	return redactValue(reflect.ValueOf(v))
+}
+
+// JSON marshals a redacted copy of v, for log lines and audit details
+func JSON(v interface{}) string {
+	data, err := json.Marshal(Value(v))
+	if err != nil {
+		return fmt.Sprintf("<unloggable %T>", v)
+	}
+	return string(data)
+}
+
+// RawJSON masks sensitive keys in an untyped JSON document. Invalid JSON is masked
+// entirely, since it cannot be inspected.
+func RawJSON(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Mask
+	}
+
+	masked, err := json.Marshal(redactJSON(doc))
+	if err != nil {
+		return Mask
+	}
+	return string(masked)
+}
+
+func redactValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+
+	case reflect.Struct:
+		// Types with their own representation (time.Time, json.RawMessage wrappers...) are kept as is
+		if _, ok := v.Interface().(json.Marshaler); ok {
+			return v.Interface()
+		}
+
+		out := make(map[string]interface{}, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, skip := jsonName(field)
+			if skip || omitEmpty(field) && isEmpty(v.Field(i)) {
+				continue
+			}
+			if field.Tag.Get("redact") == "true" {
+				if !v.Field(i).IsZero() {
+					out[name] = Mask
+				}
+				continue
+			}
+			out[name] = redactValue(v.Field(i))
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		// Raw bytes (json.RawMessage, []byte) are treated as JSON documents
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Len() == 0 {
+				return nil
+			}
+			return json.RawMessage(RawJSON(v.Bytes()))
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if IsSensitiveName(key) {
+				out[key] = Mask
+				continue
+			}
+			out[key] = redactValue(iter.Value())
+		}
+		return out
+
+	default:
+		return v.Interface()
+	}
+}
+
+func redactJSON(doc interface{}) interface{} {
+	switch value := doc.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if IsSensitiveName(key) {
+				value[key] = Mask
+			} else {
+				value[key] = redactJSON(child)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactJSON(child)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// jsonName returns the JSON key for a struct field and whether encoding/json skips it
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, false
+	}
+	return field.Name, false
+}
+
+// omitEmpty reports whether a struct field is tagged omitempty
+func omitEmpty(field reflect.StructField) bool {
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty reports whether encoding/json's omitempty leaves out v
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	default:
+		return v.IsZero()
+	}
+}
@@ -0,0 +1,34 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	type request struct {
+		Name      string          `json:"name"`
+		Key       string          `json:"key" redact:"true"`
+		TicketURL string          `json:"ticket_url,omitempty"`
+		Config    json.RawMessage `json:"config,omitempty"`
+	}
+
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"tagged field", request{Name: "prod", Key: "AKIASECRET"}, `{"key":"[REDACTED]","name":"prod"}`},
+		{"raw JSON by key", request{Config: json.RawMessage(`{"db_password":"x","size":1}`)}, `{"config":{"db_password":"[REDACTED]","size":1},"name":""}`},
+		{"empty raw JSON", request{Config: json.RawMessage{}}, `{"name":""}`},
+		{"map by key", map[string]interface{}{"token": "gho_x", "secret_id": "id", "nested": map[string]string{"password": "x"}},
+			`{"nested":{"password":"[REDACTED]"},"secret_id":"id","token":"[REDACTED]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSON(tt.v); got != tt.want {
+				t.Errorf("JSON = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// AccessReportRepository derives who can access which project, for access reviews
+type AccessReportRepository struct{}
+
+// NewAccessReportRepository creates a new access report repository
+func NewAccessReportRepository() *AccessReportRepository {
+	return &AccessReportRepository{}
+}
+
+// accessReportQuery lists every (user, project, source) with the same rules as
+// ProjectRepository.CanUserAccess, plus superadmins on every project and the
+// provisioning permission grants
+const accessReportQuery = `
+	WITH access AS (
+		SELECT pa.user_id, pa.project_id, '` + models.AccessSourceUser + `' AS source,
+		       NULL::text AS team_name, NULL::text AS permission, NULL::text AS credential
+		FROM project_access pa
+		WHERE pa.user_id IS NOT NULL
+
+		UNION ALL
+		SELECT tm.user_id, pa.project_id, '` + models.AccessSourceTeam + `', t.name, NULL, NULL
+		FROM project_access pa
+		JOIN team_members tm ON tm.team_id = pa.team_id
+		JOIN teams t ON t.id = pa.team_id
+
+		UNION ALL
+		SELECT tm.user_id, p.id, '` + models.AccessSourceOwnerTeam + `', t.name, NULL, NULL
+		FROM projects p
+		JOIN team_members tm ON tm.team_id = p.owner_team_id
+		JOIN teams t ON t.id = p.owner_team_id
+
+		UNION ALL
+		SELECT u.id, p.id, '` + models.AccessSourceRole + `', NULL, NULL, NULL
+		FROM users u
+		CROSS JOIN projects p
+		WHERE u.role = '` + string(models.RoleAdmin) + `'
+
+		UNION ALL
+		SELECT upp.user_id, upp.project_id, '` + models.AccessSourceProvisioning + `', NULL, upp.resource_type, s.name
+		FROM user_provisioning_permissions upp
+		LEFT JOIN secrets s ON s.id = upp.credential_id
+	)
+	SELECT u.id, u.email, u.name, u.role,
+	       COALESCE(p.id::text, ''), COALESCE(p.name, ''),
+	       a.source, COALESCE(a.team_name, ''), COALESCE(a.permission, ''), COALESCE(a.credential, '')
+	FROM access a
+	JOIN users u ON u.id = a.user_id
+	LEFT JOIN projects p ON p.id = a.project_id
+	ORDER BY u.email, p.name NULLS FIRST, a.source, a.team_name, a.permission
+`
+
+// ForEach calls fn for every access report entry, ordered by user and project,
+// without loading the whole report into memory. Reading runs under the stream timeout.
+func (r *AccessReportRepository) ForEach(ctx context.Context, fn func(models.AccessReportEntry) error) error {
+	rows, err := database.DB.Query(database.WithQueryTimeout(ctx, database.StreamQueryTimeout), accessReportQuery)
+	if err != nil {
+		return fmt.Errorf("failed to build access report: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.AccessReportEntry
+		if err := rows.Scan(&e.UserID, &e.UserEmail, &e.UserName, &e.UserRole,
+			&e.ProjectID, &e.ProjectName,
+			&e.Source, &e.TeamName, &e.Permission, &e.Credential); err != nil {
+			return fmt.Errorf("failed to scan access report entry: %w", err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// TestAccessReportDerivesEverySource builds one fixture covering every access
+// source and checks the report has exactly one entry per way each user gets in
+func TestAccessReportDerivesEverySource(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	users := make(map[string]*models.User) // By label
+	var userIDs, teamIDs []string
+	var projectID string
+	t.Cleanup(func() {
+		ctx := context.Background()
+		database.DB.Exec(ctx, "DELETE FROM user_provisioning_permissions WHERE user_id = ANY($1::uuid[])", userIDs)
+		if projectID != "" {
+			database.DB.Exec(ctx, "DELETE FROM projects WHERE id = $1::uuid", projectID)
+		}
+		database.DB.Exec(ctx, "DELETE FROM teams WHERE id = ANY($1::uuid[])", teamIDs)
+		database.DB.Exec(ctx, "DELETE FROM users WHERE id = ANY($1::uuid[])", userIDs)
+	})
+
+	for _, label := range []string{"direct", "guest", "owner", "owner-and-direct", "admin", "provisioner", "outsider"} {
+		role := models.RoleDev
+		if label == "admin" {
+			role = models.RoleAdmin
+		}
+		user := &models.User{Name: label, Email: fmt.Sprintf("report-%s-%d@test.invalid", label, suffix), Role: role}
+		if err := (&UserRepository{}).Create(ctx, user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		users[label] = user
+		userIDs = append(userIDs, user.ID)
+	}
+
+	createTeam := func(name string, members ...string) *models.Team {
+		team := &models.Team{Name: fmt.Sprintf("%s-%d", name, suffix)}
+		if err := (&TeamRepository{}).Create(ctx, team); err != nil {
+			t.Fatalf("failed to create team: %v", err)
+		}
+		teamIDs = append(teamIDs, team.ID)
+		var memberIDs []string
+		for _, label := range members {
+			memberIDs = append(memberIDs, users[label].ID)
+		}
+		if _, err := (&TeamRepository{}).UpdateTeamMembers(ctx, team.ID, memberIDs); err != nil {
+			t.Fatalf("failed to add team members: %v", err)
+		}
+		return team
+	}
+	ownerTeam := createTeam("report-owner", "owner", "owner-and-direct")
+	guestTeam := createTeam("report-guest", "guest")
+
+	projects := &ProjectRepository{}
+	project := &models.Project{Name: fmt.Sprintf("report-%d", suffix), OwnerTeamID: ownerTeam.ID}
+	if err := projects.Create(ctx, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	projectID = project.ID
+	err := projects.UpdateProjectAccess(ctx, project.ID, []string{guestTeam.ID},
+		[]string{users["direct"].ID, users["owner-and-direct"].ID})
+	if err != nil {
+		t.Fatalf("failed to grant project access: %v", err)
+	}
+
+	permissions := &ProvisioningPermissionRepository{}
+	grants := []*models.UpdateProvisioningPermissionsRequest{
+		{AllowedTypes: []string{"sqs"}, ProjectID: project.ID},
+		{AllowedTypes: []string{"s3"}}, // Every project
+	}
+	for _, grant := range grants {
+		if err := permissions.SetUserPermissions(ctx, users["provisioner"].ID, grant, users["admin"].ID); err != nil {
+			t.Fatalf("failed to grant provisioning permission: %v", err)
+		}
+	}
+
+	// user|source|team|permission|project, with the project empty for grants everywhere
+	want := []string{
+		"direct|user|||" + project.Name,
+		"guest|team|" + guestTeam.Name + "||" + project.Name,
+		"owner|owner_team|" + ownerTeam.Name + "||" + project.Name,
+		"owner-and-direct|owner_team|" + ownerTeam.Name + "||" + project.Name,
+		"owner-and-direct|user|||" + project.Name,
+		"admin|role|||" + project.Name,
+		"provisioner|provisioning_permission||s3|",
+		"provisioner|provisioning_permission||sqs|" + project.Name,
+	}
+
+	labels := make(map[string]string) // User ID to label
+	for label, user := range users {
+		labels[user.ID] = label
+	}
+	var got []string
+	err = NewAccessReportRepository().ForEach(ctx, func(e models.AccessReportEntry) error {
+		label, ok := labels[e.UserID]
+		// The admin reaches every project; only this fixture's one matters here
+		if !ok || e.ProjectID != "" && e.ProjectID != project.ID {
+			return nil
+		}
+		got = append(got, fmt.Sprintf("%s|%s|%s|%s|%s", label, e.Source, e.TeamName, e.Permission, e.ProjectName))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to build the report: %v", err)
+	}
+
+	sort.Strings(got)
+	sort.Strings(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("report entries:\n got %q\nwant %q", got, want)
+	}
+}
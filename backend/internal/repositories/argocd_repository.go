@@ -3,9 +3,10 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/portalight/backend/internal/database"
+	"github.com/jackc/pgx/v5"
 	"github.com/portalight/backend/internal/models"
 )
 
@@ -13,23 +14,33 @@ import (
 var ErrNotFound = errors.New("record not found")
 
 // ArgoCDRepository handles ArgoCD-related database operations
-type ArgoCDRepository struct{}
+type ArgoCDRepository struct {
+	querier
+}
 
 // NewArgoCDRepository creates a new ArgoCD repository
 func NewArgoCDRepository() *ArgoCDRepository {
 	return &ArgoCDRepository{}
 }
 
-// GetByServiceID retrieves all ArgoCD apps linked to a service
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ArgoCDRepository) WithQuerier(q Querier) *ArgoCDRepository {
+	return &ArgoCDRepository{querier: querier{q: q}}
+}
+
+// GetByServiceID retrieves all ArgoCD apps linked to a service, joined with the
+// service environment each one is linked to
 func (r *ArgoCDRepository) GetByServiceID(ctx context.Context, serviceID string) ([]models.ServiceArgoCDApp, error) {
 	query := `
-		SELECT id, service_id, argocd_app_name, environment_name, created_at, updated_at
-		FROM service_argocd_apps
-		WHERE service_id = $1
-		ORDER BY environment_name
+		SELECT a.id, a.service_id, a.argocd_app_name, a.environment_name, a.source, a.created_at, a.updated_at,
+		       se.is_active
+		FROM service_argocd_apps a
+		LEFT JOIN service_environments se ON se.argocd_app_id = a.id
+		WHERE a.service_id = $1
+		ORDER BY a.environment_name
 	`
 
-	rows, err := database.DB.Query(ctx, query, serviceID)
+	rows, err := r.db().Query(ctx, query, serviceID)
 	if err != nil {
 		return nil, err
 	}
@@ -43,8 +54,10 @@ func (r *ArgoCDRepository) GetByServiceID(ctx context.Context, serviceID string)
 			&app.ServiceID,
 			&app.ArgoCDAppName,
 			&app.EnvironmentName,
+			&app.Source,
 			&app.CreatedAt,
 			&app.UpdatedAt,
+			&app.EnvironmentActive,
 		)
 		if err != nil {
 			return nil, err
@@ -57,24 +70,112 @@ func (r *ArgoCDRepository) GetByServiceID(ctx context.Context, serviceID string)
 
 // Create links an ArgoCD app to a service
 func (r *ArgoCDRepository) Create(ctx context.Context, app *models.ServiceArgoCDApp) error {
+	if app.Source == "" {
+		app.Source = models.ArgoCDAppSourceManual
+	}
+
 	query := `
-		INSERT INTO service_argocd_apps (service_id, argocd_app_name, environment_name)
-		VALUES ($1, $2, $3)
+		INSERT INTO service_argocd_apps (service_id, argocd_app_name, environment_name, source)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at, updated_at
 	`
 
-	return database.DB.QueryRow(ctx, query,
+	err := r.db().QueryRow(ctx, query,
 		app.ServiceID,
 		app.ArgoCDAppName,
 		app.EnvironmentName,
+		app.Source,
 	).Scan(&app.ID, &app.CreatedAt, &app.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db().Exec(ctx, relinkServiceEnvironmentsSQL, app.ServiceID)
+	return err
+}
+
+// SyncCatalogApps makes the catalog-sourced links of a service match apps (environment -> app name).
+// Manually created links are never modified; a catalog entry that duplicates one is skipped.
+func (r *ArgoCDRepository) SyncCatalogApps(ctx context.Context, serviceID string, apps map[string]string) error {
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, argocd_app_name, environment_name, source
+		FROM service_argocd_apps
+		WHERE service_id = $1
+	`, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to load ArgoCD app links: %w", err)
+	}
+
+	type link struct{ id, appName, environment, source string }
+	var existing []link
+	for rows.Next() {
+		var l link
+		if err := rows.Scan(&l.id, &l.appName, &l.environment, &l.source); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan ArgoCD app link: %w", err)
+		}
+		existing = append(existing, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load ArgoCD app links: %w", err)
+	}
+
+	pending := make(map[string]string, len(apps))
+	for env, appName := range apps {
+		pending[env] = appName
+	}
+
+	for _, l := range existing {
+		appName, wanted := pending[l.environment]
+		switch {
+		case wanted && l.appName == appName:
+			// Already linked (manually or by a previous sync)
+			delete(pending, l.environment)
+		case l.source != models.ArgoCDAppSourceCatalog:
+			// Manual links are left alone
+		case wanted:
+			if _, err := tx.Exec(ctx,
+				`UPDATE service_argocd_apps SET argocd_app_name = $1, updated_at = $2 WHERE id = $3`,
+				appName, time.Now(), l.id,
+			); err != nil {
+				return fmt.Errorf("failed to update ArgoCD app link: %w", err)
+			}
+			delete(pending, l.environment)
+		default:
+			if _, err := tx.Exec(ctx, `DELETE FROM service_argocd_apps WHERE id = $1`, l.id); err != nil {
+				return fmt.Errorf("failed to delete ArgoCD app link: %w", err)
+			}
+		}
+	}
+
+	for env, appName := range pending {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO service_argocd_apps (service_id, argocd_app_name, environment_name, source)
+			VALUES ($1, $2, $3, $4)
+		`, serviceID, appName, env, models.ArgoCDAppSourceCatalog); err != nil {
+			return fmt.Errorf("failed to create ArgoCD app link: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, relinkServiceEnvironmentsSQL, serviceID); err != nil {
+		return fmt.Errorf("failed to link service environments: %w", err)
+	}
+
+	return tx.Commit(ctx)
 }
 
 // Delete removes an ArgoCD app link from a service
 func (r *ArgoCDRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM service_argocd_apps WHERE id = $1`
 
-	result, err := database.DB.Exec(ctx, query, id)
+	result, err := r.db().Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -89,7 +190,7 @@ func (r *ArgoCDRepository) Delete(ctx context.Context, id string) error {
 // DeleteByServiceID removes all ArgoCD app links for a service
 func (r *ArgoCDRepository) DeleteByServiceID(ctx context.Context, serviceID string) error {
 	query := `DELETE FROM service_argocd_apps WHERE service_id = $1`
-	_, err := database.DB.Exec(ctx, query, serviceID)
+	_, err := r.db().Exec(ctx, query, serviceID)
 	return err
 }
 
@@ -99,21 +200,26 @@ func (r *ArgoCDRepository) Update(ctx context.Context, app *models.ServiceArgoCD
 		UPDATE service_argocd_apps
 		SET argocd_app_name = $1, environment_name = $2, updated_at = $3
 		WHERE id = $4
+		RETURNING service_id
 	`
 
 	now := time.Now()
-	result, err := database.DB.Exec(ctx, query,
+	err := r.db().QueryRow(ctx, query,
 		app.ArgoCDAppName,
 		app.EnvironmentName,
 		now,
 		app.ID,
-	)
+	).Scan(&app.ServiceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
-		return ErrNotFound
+	// The app may have moved to another environment
+	if _, err := r.db().Exec(ctx, relinkServiceEnvironmentsSQL, app.ServiceID); err != nil {
+		return err
 	}
 
 	app.UpdatedAt = now
@@ -123,17 +229,18 @@ func (r *ArgoCDRepository) Update(ctx context.Context, app *models.ServiceArgoCD
 // FindByID finds a specific ArgoCD app link
 func (r *ArgoCDRepository) FindByID(ctx context.Context, id string) (*models.ServiceArgoCDApp, error) {
 	query := `
-		SELECT id, service_id, argocd_app_name, environment_name, created_at, updated_at
+		SELECT id, service_id, argocd_app_name, environment_name, source, created_at, updated_at
 		FROM service_argocd_apps
 		WHERE id = $1
 	`
 
 	var app models.ServiceArgoCDApp
-	err := database.DB.QueryRow(ctx, query, id).Scan(
+	err := r.db().QueryRow(ctx, query, id).Scan(
 		&app.ID,
 		&app.ServiceID,
 		&app.ArgoCDAppName,
 		&app.EnvironmentName,
+		&app.Source,
 		&app.CreatedAt,
 		&app.UpdatedAt,
 	)
@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
 )
@@ -12,72 +15,191 @@ import (
 // AuditLogRepository handles audit log database operations
 type AuditLogRepository struct{}
 
+// AuditLogFilter narrows down audit log queries; zero values are ignored
+type AuditLogFilter struct {
+	UserEmail string
+	ProjectID string
+	RequestID string
+	From      time.Time // Inclusive
+	To        time.Time // Exclusive
+}
+
 // GetAll retrieves all audit logs, optionally filtered by user email
 func (r *AuditLogRepository) GetAll(ctx context.Context, userEmail string) ([]models.AuditLog, error) {
-	var query string
+	var logs []models.AuditLog
+	err := r.ForEach(ctx, AuditLogFilter{UserEmail: userEmail}, func(log models.AuditLog) error {
+		logs = append(logs, log)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// auditLogColumns are the columns scanned by scanAuditLog
+const auditLogColumns = `id, user_email, user_name, action, resource_type, resource_id, resource_name, project_id::text, details, status, route, status_code, duration_ms, request_id, timestamp, created_at`
+
+// where builds the WHERE clause for the filter and its positional args
+func (f AuditLogFilter) where() (string, []interface{}) {
+	var conditions []string
 	var args []interface{}
 
-	if userEmail != "" {
-		query = `
-			SELECT id, user_email, user_name, action, resource_type, resource_id, resource_name, details, status, timestamp, created_at
-			FROM audit_logs
-			WHERE user_email = $1
-			ORDER BY timestamp DESC
-		`
-		args = append(args, userEmail)
-	} else {
-		query = `
-			SELECT id, user_email, user_name, action, resource_type, resource_id, resource_name, details, status, timestamp, created_at
-			FROM audit_logs
-			ORDER BY timestamp DESC
-		`
-	}
-
-	rows, err := database.DB.Query(ctx, query, args...)
+	if f.UserEmail != "" {
+		args = append(args, f.UserEmail)
+		conditions = append(conditions, fmt.Sprintf("user_email = $%d", len(args)))
+	}
+	if f.ProjectID != "" {
+		args = append(args, f.ProjectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d::uuid", len(args)))
+	}
+	if f.RequestID != "" {
+		args = append(args, f.RequestID)
+		conditions = append(conditions, fmt.Sprintf("request_id = $%d", len(args)))
+	}
+	if !f.From.IsZero() {
+		args = append(args, f.From)
+		conditions = append(conditions, fmt.Sprintf("timestamp >= $%d", len(args)))
+	}
+	if !f.To.IsZero() {
+		args = append(args, f.To)
+		conditions = append(conditions, fmt.Sprintf("timestamp < $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ForEach streams matching audit logs (newest first) to fn without loading them all
+// into memory. Reading runs under the stream timeout.
+func (r *AuditLogRepository) ForEach(ctx context.Context, filter AuditLogFilter, fn func(models.AuditLog) error) error {
+	where, args := filter.where()
+	query := `SELECT ` + auditLogColumns + ` FROM audit_logs` + where + ` ORDER BY timestamp DESC`
+
+	rows, err := database.DB.Query(database.WithQueryTimeout(ctx, database.StreamQueryTimeout), query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var logs []models.AuditLog
 	for rows.Next() {
 		var log models.AuditLog
-		var resourceType, resourceID, resourceName, details *string
-
-		err := rows.Scan(
-			&log.ID,
-			&log.UserEmail,
-			&log.UserName,
-			&log.Action,
-			&resourceType,
-			&resourceID,
-			&resourceName,
-			&details,
-			&log.Status,
-			&log.Timestamp,
-			&log.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		if resourceType != nil {
-			log.ResourceType = *resourceType
+		if err := scanAuditLog(rows, &log); err != nil {
+			return err
 		}
-		if resourceID != nil {
-			log.ResourceID = *resourceID
+		if err := fn(log); err != nil {
+			return err
 		}
-		if resourceName != nil {
-			log.ResourceName = *resourceName
+	}
+
+	return rows.Err()
+}
+
+// auditLogSortFields are the columns audit logs can be listed by
+var auditLogSortFields = []string{"timestamp", "action", "user_email"}
+
+// List retrieves one page of matching audit logs (newest first by default) and the
+// total number of matches
+func (r *AuditLogRepository) List(ctx context.Context, filter AuditLogFilter, opts ListOptions) ([]models.AuditLog, int, error) {
+	where, args := filter.where()
+	baseQuery := `SELECT ` + auditLogColumns + ` FROM audit_logs` + where
+
+	query, pageArgs, err := ApplyPagination(baseQuery, opts.withDefaultSort("timestamp", "desc"), auditLogSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := database.DB.Query(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []models.AuditLog{}
+	total := 0
+	for rows.Next() {
+		var log models.AuditLog
+		if err := scanAuditLog(rows, &log, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
 		}
-		if details != nil {
-			log.Details = *details
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(logs) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery, args...)
+		if err != nil {
+			return nil, 0, err
 		}
+	}
 
-		logs = append(logs, log)
+	return logs, total, nil
+}
+
+// scanAuditLog scans a row selected with auditLogColumns, followed by any extra destinations
+func scanAuditLog(rows pgx.Rows, log *models.AuditLog, extra ...interface{}) error {
+	var resourceType, resourceID, resourceName, projectID, details, route, requestID *string
+	var statusCode *int
+	var durationMs *int64
+
+	dest := []interface{}{
+		&log.ID,
+		&log.UserEmail,
+		&log.UserName,
+		&log.Action,
+		&resourceType,
+		&resourceID,
+		&resourceName,
+		&projectID,
+		&details,
+		&log.Status,
+		&route,
+		&statusCode,
+		&durationMs,
+		&requestID,
+		&log.Timestamp,
+		&log.CreatedAt,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	if resourceType != nil {
+		log.ResourceType = *resourceType
+	}
+	if resourceID != nil {
+		log.ResourceID = *resourceID
+	}
+	if resourceName != nil {
+		log.ResourceName = *resourceName
+	}
+	if projectID != nil {
+		log.ProjectID = *projectID
+	}
+	if details != nil {
+		log.Details = *details
 	}
+	if route != nil {
+		log.Route = *route
+	}
+	if statusCode != nil {
+		log.StatusCode = *statusCode
+	}
+	if durationMs != nil {
+		log.DurationMs = *durationMs
+	}
+	if requestID != nil {
+		log.RequestID = *requestID
+	}
+	return nil
+}
 
-	return logs, rows.Err()
+// FindByDateRange streams audit logs with from <= timestamp < to to fn; zero times are unbounded
+func (r *AuditLogRepository) FindByDateRange(ctx context.Context, from, to time.Time, fn func(models.AuditLog) error) error {
+	return r.ForEach(ctx, AuditLogFilter{From: from, To: to}, fn)
 }
 
 // Create creates a new audit log entry
@@ -93,11 +215,14 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 	}
 
 	query := `
-		INSERT INTO audit_logs (id, user_email, user_name, action, resource_type, resource_id, resource_name, details, status, timestamp, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO audit_logs (id, user_email, user_name, action, resource_type, resource_id, resource_name, project_id, details, status,
+			route, status_code, duration_ms, request_id, timestamp, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::uuid, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
-	var resourceType, resourceID, resourceName, details *string
+	var resourceType, resourceID, resourceName, projectID, details, route, requestID *string
+	var statusCode *int
+	var durationMs *int64
 	if log.ResourceType != "" {
 		resourceType = &log.ResourceType
 	}
@@ -107,9 +232,20 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 	if log.ResourceName != "" {
 		resourceName = &log.ResourceName
 	}
+	if log.ProjectID != "" {
+		projectID = &log.ProjectID
+	}
 	if log.Details != "" {
 		details = &log.Details
 	}
+	if log.Route != "" {
+		route = &log.Route
+		statusCode = &log.StatusCode
+		durationMs = &log.DurationMs
+	}
+	if log.RequestID != "" {
+		requestID = &log.RequestID
+	}
 
 	_, err := database.DB.Exec(ctx, query,
 		log.ID,
@@ -119,8 +255,13 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 		resourceType,
 		resourceID,
 		resourceName,
+		projectID,
 		details,
 		log.Status,
+		route,
+		statusCode,
+		durationMs,
+		requestID,
 		log.Timestamp,
 		log.CreatedAt,
 	)
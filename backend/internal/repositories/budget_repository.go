@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// BudgetRepository handles project budgets and their breach events
+type BudgetRepository struct{}
+
+// NewBudgetRepository creates a new budget repository
+func NewBudgetRepository() *BudgetRepository {
+	return &BudgetRepository{}
+}
+
+// budgetSelect loads budgets together with their open breach, if any
+const budgetSelect = `
+	SELECT b.id, b.project_id, p.name, b.type, b.resource_type, b.threshold, b.notify_channel, b.created_at, b.updated_at,
+	       e.id, e.value, e.threshold, e.breached_at, e.evaluated_at, e.notified_at
+	FROM project_budgets b
+	JOIN projects p ON p.id = b.project_id
+	LEFT JOIN budget_events e ON e.budget_id = b.id AND e.resolved_at IS NULL
+`
+
+// ListByProject returns the budgets of a project
+func (r *BudgetRepository) ListByProject(ctx context.Context, projectID string) ([]models.ProjectBudget, error) {
+	return r.list(ctx, budgetSelect+" WHERE b.project_id = $1::uuid ORDER BY b.created_at", projectID)
+}
+
+// ListAll returns every budget, for the evaluator
+func (r *BudgetRepository) ListAll(ctx context.Context) ([]models.ProjectBudget, error) {
+	return r.list(ctx, budgetSelect+" ORDER BY b.project_id, b.created_at")
+}
+
+// FindByID returns a budget of a project
+func (r *BudgetRepository) FindByID(ctx context.Context, projectID, id string) (*models.ProjectBudget, error) {
+	budgets, err := r.list(ctx, budgetSelect+" WHERE b.project_id = $1::uuid AND b.id = $2::uuid", projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(budgets) == 0 {
+		return nil, ErrNotFound
+	}
+	return &budgets[0], nil
+}
+
+func (r *BudgetRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.ProjectBudget, error) {
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budgets: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := []models.ProjectBudget{}
+	for rows.Next() {
+		var budget models.ProjectBudget
+		var resourceType, notifyChannel *string
+		var event models.BudgetEvent
+		var eventID *string
+		var eventValue, eventThreshold *float64
+		var breachedAt, evaluatedAt *time.Time
+
+		err := rows.Scan(
+			&budget.ID,
+			&budget.ProjectID,
+			&budget.ProjectName,
+			&budget.Type,
+			&resourceType,
+			&budget.Threshold,
+			&notifyChannel,
+			&budget.CreatedAt,
+			&budget.UpdatedAt,
+			&eventID,
+			&eventValue,
+			&eventThreshold,
+			&breachedAt,
+			&evaluatedAt,
+			&event.NotifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget: %w", err)
+		}
+
+		if resourceType != nil {
+			budget.ResourceType = *resourceType
+		}
+		if notifyChannel != nil {
+			budget.NotifyChannel = *notifyChannel
+		}
+		if eventID != nil {
+			event.ID = *eventID
+			event.BudgetID = budget.ID
+			event.ProjectID = budget.ProjectID
+			event.Value = *eventValue
+			event.Threshold = *eventThreshold
+			event.BreachedAt = *breachedAt
+			event.EvaluatedAt = *evaluatedAt
+			budget.Breach = &event
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// Create adds a budget to a project
+func (r *BudgetRepository) Create(ctx context.Context, budget *models.ProjectBudget) error {
+	query := `
+		INSERT INTO project_budgets (project_id, type, resource_type, threshold, notify_channel)
+		VALUES ($1::uuid, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	resourceType, notifyChannel := budgetNullables(budget)
+
+	err := database.DB.QueryRow(ctx, query,
+		budget.ProjectID,
+		budget.Type,
+		resourceType,
+		budget.Threshold,
+		notifyChannel,
+	).Scan(&budget.ID, &budget.CreatedAt, &budget.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create budget: %w", err)
+	}
+	return nil
+}
+
+// Update changes the threshold, resource type and notification channel of a budget
+func (r *BudgetRepository) Update(ctx context.Context, budget *models.ProjectBudget) error {
+	query := `
+		UPDATE project_budgets
+		SET type = $3, resource_type = $4, threshold = $5, notify_channel = $6, updated_at = NOW()
+		WHERE project_id = $1::uuid AND id = $2::uuid
+		RETURNING updated_at
+	`
+	resourceType, notifyChannel := budgetNullables(budget)
+
+	err := database.DB.QueryRow(ctx, query,
+		budget.ProjectID,
+		budget.ID,
+		budget.Type,
+		resourceType,
+		budget.Threshold,
+		notifyChannel,
+	).Scan(&budget.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update budget: %w", err)
+	}
+	return nil
+}
+
+// budgetNullables maps empty optional budget fields to NULL
+func budgetNullables(budget *models.ProjectBudget) (resourceType, notifyChannel *string) {
+	if budget.ResourceType != "" {
+		resourceType = &budget.ResourceType
+	}
+	if budget.NotifyChannel != "" {
+		notifyChannel = &budget.NotifyChannel
+	}
+	return resourceType, notifyChannel
+}
+
+// Delete removes a budget and its breach history
+func (r *BudgetRepository) Delete(ctx context.Context, projectID, id string) error {
+	tag, err := database.DB.Exec(ctx,
+		"DELETE FROM project_budgets WHERE project_id = $1::uuid AND id = $2::uuid",
+		projectID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordBreach opens a breach event for the budget, or refreshes the value of the
+// already open one, and returns it
+func (r *BudgetRepository) RecordBreach(ctx context.Context, budget *models.ProjectBudget, value float64) (*models.BudgetEvent, error) {
+	query := `
+		INSERT INTO budget_events (budget_id, project_id, value, threshold)
+		VALUES ($1::uuid, $2::uuid, $3, $4)
+		ON CONFLICT (budget_id) WHERE resolved_at IS NULL DO UPDATE SET
+			value = EXCLUDED.value,
+			evaluated_at = NOW()
+		RETURNING id, value, threshold, breached_at, evaluated_at, notified_at
+	`
+
+	event := models.BudgetEvent{BudgetID: budget.ID, ProjectID: budget.ProjectID}
+	err := database.DB.QueryRow(ctx, query, budget.ID, budget.ProjectID, value, budget.Threshold).Scan(
+		&event.ID,
+		&event.Value,
+		&event.Threshold,
+		&event.BreachedAt,
+		&event.EvaluatedAt,
+		&event.NotifiedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record budget breach: %w", err)
+	}
+	return &event, nil
+}
+
+// MarkNotified records that the breach notification was delivered
+func (r *BudgetRepository) MarkNotified(ctx context.Context, eventID string) error {
+	_, err := database.DB.Exec(ctx, "UPDATE budget_events SET notified_at = NOW() WHERE id = $1::uuid", eventID)
+	if err != nil {
+		return fmt.Errorf("failed to mark budget event notified: %w", err)
+	}
+	return nil
+}
+
+// ResolveBreach closes the open breach of a budget, if any, and reports whether one was open
+func (r *BudgetRepository) ResolveBreach(ctx context.Context, budgetID string, value float64) (bool, error) {
+	tag, err := database.DB.Exec(ctx, `
+		UPDATE budget_events
+		SET value = $2, evaluated_at = NOW(), resolved_at = NOW()
+		WHERE budget_id = $1::uuid AND resolved_at IS NULL
+	`, budgetID, value)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve budget breach: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListEvents returns the most recent breach events of a project
+func (r *BudgetRepository) ListEvents(ctx context.Context, projectID string, limit int) ([]models.BudgetEvent, error) {
+	query := `
+		SELECT id, budget_id, project_id, value, threshold, breached_at, evaluated_at, notified_at, resolved_at
+		FROM budget_events
+		WHERE project_id = $1::uuid
+		ORDER BY breached_at DESC
+		LIMIT $2
+	`
+
+	rows, err := database.DB.Query(ctx, query, projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query budget events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.BudgetEvent{}
+	for rows.Next() {
+		var event models.BudgetEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.BudgetID,
+			&event.ProjectID,
+			&event.Value,
+			&event.Threshold,
+			&event.BreachedAt,
+			&event.EvaluatedAt,
+			&event.NotifiedAt,
+			&event.ResolvedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	credentialCacheSize = 128
+	credentialCacheTTL  = 5 * time.Minute
+)
+
+// credentialCache is shared by all SecretRepository instances, since they are
+// created as zero-value structs throughout the handlers
+var credentialCache = newCredentialLRU(credentialCacheSize, credentialCacheTTL)
+
+type credentialEntry struct {
+	secretID  string
+	plaintext []byte // Decrypted credentials JSON, zeroed on eviction
+	expiresAt time.Time
+}
+
+// credentialLRU is a size-bounded, TTL-based LRU of decrypted credentials
+type credentialLRU struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // Front is most recently used
+	entries  map[string]*list.Element
+}
+
+func newCredentialLRU(capacity int, ttl time.Duration) *credentialLRU {
+	return &credentialLRU{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached plaintext, or nil if missing or expired
+func (c *credentialLRU) get(secretID string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[secretID]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*credentialEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+
+	c.order.MoveToFront(elem)
+	out := make([]byte, len(entry.plaintext))
+	copy(out, entry.plaintext)
+	return out
+}
+
+// put stores a copy of the plaintext, evicting the least recently used entry when full
+func (c *credentialLRU) put(secretID string, plaintext []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[secretID]; ok {
+		c.removeElement(elem)
+	}
+
+	stored := make([]byte, len(plaintext))
+	copy(stored, plaintext)
+	c.entries[secretID] = c.order.PushFront(&credentialEntry{
+		secretID:  secretID,
+		plaintext: stored,
+		expiresAt: time.Now().Add(c.ttl),
+	})
+
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// invalidate drops a secret from the cache, e.g. after rotation or deletion
+func (c *credentialLRU) invalidate(secretID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[secretID]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// removeElement unlinks an entry and zeroes its plaintext. Caller must hold c.mu.
+func (c *credentialLRU) removeElement(elem *list.Element) {
+	entry := elem.Value.(*credentialEntry)
+	zeroBytes(entry.plaintext)
+	c.order.Remove(elem)
+	delete(c.entries, entry.secretID)
+}
+
+// zeroBytes overwrites a buffer so decrypted secrets don't linger on the heap
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/crypto"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// storedPlaintext returns the cache's own buffer for a secret, so tests can see it being zeroed
+func storedPlaintext(c *credentialLRU, secretID string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[secretID]; ok {
+		return elem.Value.(*credentialEntry).plaintext
+	}
+	return nil
+}
+
+func TestCredentialCacheReturnsCopies(t *testing.T) {
+	cache := newCredentialLRU(2, time.Minute)
+	plaintext := []byte("secret")
+	cache.put("a", plaintext)
+
+	plaintext[0] = 'X'
+	got := cache.get("a")
+	if string(got) != "secret" {
+		t.Fatalf("got %q, want the plaintext as it was put", got)
+	}
+	got[0] = 'Y'
+	if again := cache.get("a"); string(again) != "secret" {
+		t.Errorf("got %q after modifying a returned copy", again)
+	}
+}
+
+func TestCredentialCacheExpires(t *testing.T) {
+	cache := newCredentialLRU(2, 20*time.Millisecond)
+	cache.put("a", []byte("secret"))
+	stored := storedPlaintext(cache, "a")
+
+	time.Sleep(40 * time.Millisecond)
+	if got := cache.get("a"); got != nil {
+		t.Errorf("got %q after the TTL, want nil", got)
+	}
+	if !bytes.Equal(stored, make([]byte, len(stored))) {
+		t.Error("expired plaintext was not zeroed")
+	}
+	if cache.order.Len() != 0 || len(cache.entries) != 0 {
+		t.Error("expired entry was not removed")
+	}
+}
+
+func TestCredentialCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newCredentialLRU(2, time.Minute)
+	cache.put("a", []byte("secret-a"))
+	cache.put("b", []byte("secret-b"))
+	evicted := storedPlaintext(cache, "b")
+
+	// Reading a makes b the least recently used entry
+	cache.get("a")
+	cache.put("c", []byte("secret-c"))
+
+	if got := cache.get("b"); got != nil {
+		t.Errorf("b was not evicted, got %q", got)
+	}
+	if !bytes.Equal(evicted, make([]byte, len(evicted))) {
+		t.Error("evicted plaintext was not zeroed")
+	}
+	for _, id := range []string{"a", "c"} {
+		if got := cache.get(id); string(got) != "secret-"+id {
+			t.Errorf("get(%q) = %q, want secret-%s", id, got, id)
+		}
+	}
+}
+
+func TestCredentialCacheInvalidate(t *testing.T) {
+	cache := newCredentialLRU(2, time.Minute)
+	cache.put("a", []byte("secret"))
+	stored := storedPlaintext(cache, "a")
+
+	cache.invalidate("a")
+	if got := cache.get("a"); got != nil {
+		t.Errorf("got %q after invalidation, want nil", got)
+	}
+	if !bytes.Equal(stored, make([]byte, len(stored))) {
+		t.Error("invalidated plaintext was not zeroed")
+	}
+
+	// Replacing an entry zeroes the old plaintext too
+	cache.put("a", []byte("old"))
+	stored = storedPlaintext(cache, "a")
+	cache.put("a", []byte("new"))
+	if !bytes.Equal(stored, make([]byte, len(stored))) {
+		t.Error("replaced plaintext was not zeroed")
+	}
+	if got := cache.get("a"); string(got) != "new" {
+		t.Errorf("got %q, want new", got)
+	}
+}
+
+func TestCredentialCacheConcurrentAccess(t *testing.T) {
+	const capacity = 8
+	cache := newCredentialLRU(capacity, time.Minute)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := fmt.Sprintf("secret-%d", (worker+i)%20)
+				if got := cache.get(id); got != nil && string(got) != id {
+					t.Errorf("get(%q) = %q", id, got)
+					return
+				}
+				cache.put(id, []byte(id))
+				if i%10 == 0 {
+					cache.invalidate(id)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if cache.order.Len() > capacity || len(cache.entries) != cache.order.Len() {
+		t.Errorf("cache holds %d list entries and %d map entries, capacity %d", cache.order.Len(), len(cache.entries), capacity)
+	}
+}
+
+// createTestSecret stores a secret created by a throwaway user; both are removed when the test ends
+func createTestSecret(t *testing.T, credentials *models.AWSCredentials) *models.Secret {
+	t.Helper()
+	ctx := context.Background()
+	crypto.SetKey("0123456789abcdef0123456789abcdef")
+	t.Cleanup(func() { crypto.SetKey("") })
+
+	user := &models.User{
+		Name:  "Secret Owner",
+		Email: fmt.Sprintf("secret-owner-%d@test.invalid", time.Now().UnixNano()),
+		Role:  models.RoleDev,
+	}
+	if err := (&UserRepository{}).Create(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	secret := &models.Secret{Name: "cache-test", Provider: "AWS", CreatedBy: user.ID}
+	if err := (&SecretRepository{}).Create(ctx, secret, credentials); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+	t.Cleanup(func() {
+		credentialCache.invalidate(secret.ID)
+		database.DB.Exec(context.Background(), "DELETE FROM secrets WHERE id = $1::uuid", secret.ID)
+		database.DB.Exec(context.Background(), "DELETE FROM users WHERE id = $1::uuid", user.ID)
+	})
+	return secret
+}
+
+func TestUpdateCredentialsInvalidatesCache(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	repo := &SecretRepository{}
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIAOLD", SecretAccessKey: "old"})
+
+	if _, _, err := repo.GetCredentials(ctx, secret.ID); err != nil {
+		t.Fatalf("GetCredentials failed: %v", err)
+	}
+	if credentialCache.get(secret.ID) == nil {
+		t.Fatal("credentials were not cached")
+	}
+
+	if err := repo.UpdateCredentials(ctx, secret.ID, &models.AWSCredentials{AccessKeyID: "AKIANEW", SecretAccessKey: "new"}); err != nil {
+		t.Fatalf("UpdateCredentials failed: %v", err)
+	}
+	if credentialCache.get(secret.ID) != nil {
+		t.Error("rotated credentials are still cached")
+	}
+
+	credentials, _, err := repo.GetCredentials(ctx, secret.ID)
+	if err != nil {
+		t.Fatalf("GetCredentials failed: %v", err)
+	}
+	if credentials.AccessKeyID != "AKIANEW" {
+		t.Errorf("got access key %q after rotation, want AKIANEW", credentials.AccessKeyID)
+	}
+}
+
+func TestDeleteInvalidatesCache(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	repo := &SecretRepository{}
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIAOLD", SecretAccessKey: "old"})
+
+	if _, _, err := repo.GetCredentials(ctx, secret.ID); err != nil {
+		t.Fatalf("GetCredentials failed: %v", err)
+	}
+	if err := repo.Delete(ctx, secret.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if credentialCache.get(secret.ID) != nil {
+		t.Error("deleted credentials are still cached")
+	}
+}
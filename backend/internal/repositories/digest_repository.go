@@ -0,0 +1,266 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// DigestRepository handles team digest settings, deliveries and the daily change summary
+type DigestRepository struct{}
+
+// NewDigestRepository creates a new digest repository
+func NewDigestRepository() *DigestRepository {
+	return &DigestRepository{}
+}
+
+// GetSettings returns the digest settings of a team; teams that never configured one
+// get the disabled default
+func (r *DigestRepository) GetSettings(ctx context.Context, teamID string) (*models.TeamDigestSettings, error) {
+	query := `
+		SELECT t.id, t.name, COALESCE(s.enabled, false), COALESCE(s.timezone, 'UTC'),
+		       s.slack_webhook_url, COALESCE(s.email_recipients, '{}'), COALESCE(s.updated_at, t.created_at)
+		FROM teams t
+		LEFT JOIN team_digest_settings s ON s.team_id = t.id
+		WHERE t.id = $1::uuid
+	`
+
+	settings, err := scanDigestSettings(database.DB.QueryRow(ctx, query, teamID))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+	return settings, nil
+}
+
+// ListEnabled returns the settings of every team that opted in
+func (r *DigestRepository) ListEnabled(ctx context.Context) ([]models.TeamDigestSettings, error) {
+	query := `
+		SELECT t.id, t.name, s.enabled, s.timezone, s.slack_webhook_url, s.email_recipients, s.updated_at
+		FROM team_digest_settings s
+		JOIN teams t ON t.id = s.team_id
+		WHERE s.enabled
+		ORDER BY t.name
+	`
+
+	rows, err := database.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := []models.TeamDigestSettings{}
+	for rows.Next() {
+		s, err := scanDigestSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest settings: %w", err)
+		}
+		settings = append(settings, *s)
+	}
+	return settings, rows.Err()
+}
+
+func scanDigestSettings(row pgx.Row) (*models.TeamDigestSettings, error) {
+	var s models.TeamDigestSettings
+	var slackWebhookURL *string
+	err := row.Scan(&s.TeamID, &s.TeamName, &s.Enabled, &s.Timezone, &slackWebhookURL, &s.EmailRecipients, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if slackWebhookURL != nil {
+		s.SlackWebhookURL = *slackWebhookURL
+	}
+	return &s, nil
+}
+
+// SaveSettings creates or replaces the digest settings of a team
+func (r *DigestRepository) SaveSettings(ctx context.Context, settings *models.TeamDigestSettings) error {
+	query := `
+		INSERT INTO team_digest_settings (team_id, enabled, timezone, slack_webhook_url, email_recipients, updated_at)
+		VALUES ($1::uuid, $2, $3, $4, $5, NOW())
+		ON CONFLICT (team_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			timezone = EXCLUDED.timezone,
+			slack_webhook_url = EXCLUDED.slack_webhook_url,
+			email_recipients = EXCLUDED.email_recipients,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	var slackWebhookURL *string
+	if settings.SlackWebhookURL != "" {
+		slackWebhookURL = &settings.SlackWebhookURL
+	}
+	if settings.EmailRecipients == nil {
+		settings.EmailRecipients = []string{}
+	}
+
+	err := database.DB.QueryRow(ctx, query,
+		settings.TeamID,
+		settings.Enabled,
+		settings.Timezone,
+		slackWebhookURL,
+		settings.EmailRecipients,
+	).Scan(&settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save digest settings: %w", err)
+	}
+	return nil
+}
+
+// ClaimDelivery records that the digest of a team for a local day is being sent.
+// It returns false when that digest was already claimed, so each day is sent once.
+func (r *DigestRepository) ClaimDelivery(ctx context.Context, teamID string, day time.Time) (bool, error) {
+	tag, err := database.DB.Exec(ctx, `
+		INSERT INTO team_digest_deliveries (team_id, digest_date)
+		VALUES ($1::uuid, $2::date)
+		ON CONFLICT (team_id, digest_date) DO NOTHING
+	`, teamID, day.Format("2006-01-02"))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest delivery: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ReleaseDelivery drops a claim after a failed send so the next run retries it
+func (r *DigestRepository) ReleaseDelivery(ctx context.Context, teamID string, day time.Time) error {
+	_, err := database.DB.Exec(ctx,
+		"DELETE FROM team_digest_deliveries WHERE team_id = $1::uuid AND digest_date = $2::date",
+		teamID, day.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to release digest delivery: %w", err)
+	}
+	return nil
+}
+
+// CollectDigest gathers the changes in the team's projects between from and to
+func (r *DigestRepository) CollectDigest(ctx context.Context, teamID string, from, to time.Time) (*models.TeamDigest, error) {
+	digest := &models.TeamDigest{}
+	var err error
+
+	// Latest successful sync of each project during the day
+	digest.ProjectsSynced, err = r.items(ctx, `
+		SELECT DISTINCT ON (p.name) p.name, p.name, h.status
+		FROM catalog_sync_history h
+		JOIN projects p ON p.id = h.project_id
+		WHERE p.owner_team_id = $1::uuid AND h.status IN ('success', 'partial')
+		  AND h.completed_at >= $2 AND h.completed_at < $3
+		ORDER BY p.name, h.completed_at DESC
+	`, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect synced projects: %w", err)
+	}
+
+	digest.ServicesAdded, err = r.items(ctx, `
+		SELECT p.name, s.name, ''
+		FROM services s
+		JOIN projects p ON p.id = s.project_id
+		WHERE p.owner_team_id = $1::uuid AND s.created_at >= $2 AND s.created_at < $3
+		ORDER BY p.name, s.name
+	`, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect added services: %w", err)
+	}
+
+	digest.ServicesRemoved, err = r.items(ctx, `
+		SELECT p.name, s.name, ''
+		FROM services s
+		JOIN projects p ON p.id = s.project_id
+		WHERE p.owner_team_id = $1::uuid AND s.orphaned AND s.orphaned_at >= $2 AND s.orphaned_at < $3
+		ORDER BY p.name, s.name
+	`, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect removed services: %w", err)
+	}
+
+	digest.ResourcesProvisioned, err = r.items(ctx, `
+		SELECT p.name, res.name, res.type
+		FROM resources res
+		JOIN projects p ON p.id = res.project_id
+		WHERE p.owner_team_id = $1::uuid AND res.status IN ('active', 'deleted')
+		  AND res.created_at >= $2 AND res.created_at < $3
+		ORDER BY p.name, res.name
+	`, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect provisioned resources: %w", err)
+	}
+
+	digest.ResourcesFailed, err = r.resourcesWithStatus(ctx, teamID, models.ProvisionStatusFailed, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect failed resources: %w", err)
+	}
+
+	digest.ResourcesDeleted, err = r.resourcesWithStatus(ctx, teamID, models.ProvisionStatusDeleted, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect deleted resources: %w", err)
+	}
+
+	digest.BudgetBreaches, err = r.budgetBreaches(ctx, teamID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect budget breaches: %w", err)
+	}
+
+	return digest, nil
+}
+
+// resourcesWithStatus lists resources that moved to status during the window
+func (r *DigestRepository) resourcesWithStatus(ctx context.Context, teamID string, status models.ProvisionStatus, from, to time.Time) ([]models.DigestItem, error) {
+	return r.items(ctx, `
+		SELECT p.name, res.name, res.type
+		FROM resources res
+		JOIN projects p ON p.id = res.project_id
+		WHERE p.owner_team_id = $1::uuid AND res.status = $4
+		  AND res.updated_at >= $2 AND res.updated_at < $3
+		ORDER BY p.name, res.name
+	`, teamID, from, to, status)
+}
+
+// items runs a query returning (project, name, detail) rows
+func (r *DigestRepository) items(ctx context.Context, query string, args ...interface{}) ([]models.DigestItem, error) {
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.DigestItem
+	for rows.Next() {
+		var item models.DigestItem
+		if err := rows.Scan(&item.Project, &item.Name, &item.Detail); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *DigestRepository) budgetBreaches(ctx context.Context, teamID string, from, to time.Time) ([]models.DigestBudgetBreach, error) {
+	rows, err := database.DB.Query(ctx, `
+		SELECT p.name, b.type, e.value, e.threshold
+		FROM budget_events e
+		JOIN project_budgets b ON b.id = e.budget_id
+		JOIN projects p ON p.id = e.project_id
+		WHERE p.owner_team_id = $1::uuid AND e.breached_at >= $2 AND e.breached_at < $3
+		ORDER BY p.name, e.breached_at
+	`, teamID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaches []models.DigestBudgetBreach
+	for rows.Next() {
+		var breach models.DigestBudgetBreach
+		if err := rows.Scan(&breach.Project, &breach.Type, &breach.Value, &breach.Threshold); err != nil {
+			return nil, err
+		}
+		breaches = append(breaches, breach)
+	}
+	return breaches, rows.Err()
+}
@@ -18,17 +18,24 @@ func NewDiscoveredResourceRepository() *DiscoveredResourceRepository {
 	return &DiscoveredResourceRepository{}
 }
 
-// Create creates a new discovered resource
+// Create creates a new discovered resource. ResourceID links it to the provisioned
+// resource it tracks; re-creating an existing row keeps its link, notes and owner label.
+// ManagedBy defaults to portalight for linked rows and manual otherwise; on an existing
+// row only terraform (found in the resource's tags) replaces the stored value.
+// The status of an existing row is overwritten rather than moved with TransitionStatus:
+// the caller has just seen the resource in AWS, which outranks any stored status.
 func (r *DiscoveredResourceRepository) Create(ctx context.Context, res *models.DiscoveredResource) error {
 	query := `
-		INSERT INTO discovered_resources (project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO discovered_resources (project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, resource_id, managed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, '')::uuid, $12)
 		ON CONFLICT (project_id, arn) DO UPDATE SET
 			status = EXCLUDED.status,
 			metadata = EXCLUDED.metadata,
 			last_synced_at = EXCLUDED.last_synced_at,
+			resource_id = COALESCE(EXCLUDED.resource_id, discovered_resources.resource_id),
+			managed_by = CASE WHEN EXCLUDED.managed_by = 'terraform' THEN EXCLUDED.managed_by ELSE discovered_resources.managed_by END,
 			updated_at = NOW()
-		RETURNING id
+		RETURNING id, managed_by
 	`
 
 	now := time.Now()
@@ -36,6 +43,12 @@ func (r *DiscoveredResourceRepository) Create(ctx context.Context, res *models.D
 	if metadata == nil {
 		metadata = json.RawMessage("{}")
 	}
+	if res.ManagedBy == "" {
+		res.ManagedBy = models.ManagedByManual
+		if res.ResourceID != "" {
+			res.ManagedBy = models.ManagedByPortalight
+		}
+	}
 
 	err := database.DB.QueryRow(ctx, query,
 		res.ProjectID,
@@ -48,7 +61,9 @@ func (r *DiscoveredResourceRepository) Create(ctx context.Context, res *models.D
 		metadata,
 		&now,
 		now,
-	).Scan(&res.ID)
+		res.ResourceID,
+		res.ManagedBy,
+	).Scan(&res.ID, &res.ManagedBy)
 
 	return err
 }
@@ -56,7 +71,7 @@ func (r *DiscoveredResourceRepository) Create(ctx context.Context, res *models.D
 // GetByProjectID retrieves all discovered resources for a project
 func (r *DiscoveredResourceRepository) GetByProjectID(ctx context.Context, projectID string) ([]models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		WHERE project_id = $1
 		ORDER BY resource_type, name
@@ -71,13 +86,14 @@ func (r *DiscoveredResourceRepository) GetByProjectID(ctx context.Context, proje
 	var resources []models.DiscoveredResource
 	for rows.Next() {
 		var res models.DiscoveredResource
-		var secretID, metadata *string
+		var secretID, resourceID, metadata *string
 		var lastSyncedAt *time.Time
 
 		err := rows.Scan(
 			&res.ID,
 			&res.ProjectID,
 			&secretID,
+			&resourceID,
 			&res.ARN,
 			&res.ResourceType,
 			&res.Name,
@@ -88,6 +104,9 @@ func (r *DiscoveredResourceRepository) GetByProjectID(ctx context.Context, proje
 			&res.DiscoveredAt,
 			&res.CreatedAt,
 			&res.UpdatedAt,
+			&res.Notes,
+			&res.OwnerLabel,
+			&res.ManagedBy,
 		)
 		if err != nil {
 			return nil, err
@@ -96,6 +115,9 @@ func (r *DiscoveredResourceRepository) GetByProjectID(ctx context.Context, proje
 		if secretID != nil {
 			res.SecretID = *secretID
 		}
+		if resourceID != nil {
+			res.ResourceID = *resourceID
+		}
 		if metadata != nil {
 			res.Metadata = json.RawMessage(*metadata)
 		}
@@ -112,7 +134,7 @@ func (r *DiscoveredResourceRepository) GetByProjectID(ctx context.Context, proje
 // GetAll retrieves all discovered resources
 func (r *DiscoveredResourceRepository) GetAll(ctx context.Context) ([]models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		ORDER BY resource_type, name
 	`
@@ -126,13 +148,14 @@ func (r *DiscoveredResourceRepository) GetAll(ctx context.Context) ([]models.Dis
 	var resources []models.DiscoveredResource
 	for rows.Next() {
 		var res models.DiscoveredResource
-		var secretID, metadata *string
+		var secretID, resourceID, metadata *string
 		var lastSyncedAt *time.Time
 
 		err := rows.Scan(
 			&res.ID,
 			&res.ProjectID,
 			&secretID,
+			&resourceID,
 			&res.ARN,
 			&res.ResourceType,
 			&res.Name,
@@ -143,6 +166,9 @@ func (r *DiscoveredResourceRepository) GetAll(ctx context.Context) ([]models.Dis
 			&res.DiscoveredAt,
 			&res.CreatedAt,
 			&res.UpdatedAt,
+			&res.Notes,
+			&res.OwnerLabel,
+			&res.ManagedBy,
 		)
 		if err != nil {
 			return nil, err
@@ -151,6 +177,9 @@ func (r *DiscoveredResourceRepository) GetAll(ctx context.Context) ([]models.Dis
 		if secretID != nil {
 			res.SecretID = *secretID
 		}
+		if resourceID != nil {
+			res.ResourceID = *resourceID
+		}
 		if metadata != nil {
 			res.Metadata = json.RawMessage(*metadata)
 		}
@@ -167,7 +196,7 @@ func (r *DiscoveredResourceRepository) GetAll(ctx context.Context) ([]models.Dis
 // GetBySecretID retrieves all discovered resources for a secret
 func (r *DiscoveredResourceRepository) GetBySecretID(ctx context.Context, secretID string) ([]models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		WHERE secret_id = $1
 	`
@@ -181,13 +210,14 @@ func (r *DiscoveredResourceRepository) GetBySecretID(ctx context.Context, secret
 	var resources []models.DiscoveredResource
 	for rows.Next() {
 		var res models.DiscoveredResource
-		var secretID, metadata *string
+		var secretID, resourceID, metadata *string
 		var lastSyncedAt *time.Time
 
 		err := rows.Scan(
 			&res.ID,
 			&res.ProjectID,
 			&secretID,
+			&resourceID,
 			&res.ARN,
 			&res.ResourceType,
 			&res.Name,
@@ -198,6 +228,9 @@ func (r *DiscoveredResourceRepository) GetBySecretID(ctx context.Context, secret
 			&res.DiscoveredAt,
 			&res.CreatedAt,
 			&res.UpdatedAt,
+			&res.Notes,
+			&res.OwnerLabel,
+			&res.ManagedBy,
 		)
 		if err != nil {
 			return nil, err
@@ -206,6 +239,9 @@ func (r *DiscoveredResourceRepository) GetBySecretID(ctx context.Context, secret
 		if secretID != nil {
 			res.SecretID = *secretID
 		}
+		if resourceID != nil {
+			res.ResourceID = *resourceID
+		}
 		if metadata != nil {
 			res.Metadata = json.RawMessage(*metadata)
 		}
@@ -222,19 +258,20 @@ func (r *DiscoveredResourceRepository) GetBySecretID(ctx context.Context, secret
 // GetByARN retrieves a discovered resource by ARN for a project
 func (r *DiscoveredResourceRepository) GetByARN(ctx context.Context, projectID, arn string) (*models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		WHERE project_id = $1 AND arn = $2
 	`
 
 	var res models.DiscoveredResource
-	var secretID, metadata *string
+	var secretID, resourceID, metadata *string
 	var lastSyncedAt *time.Time
 
 	err := database.DB.QueryRow(ctx, query, projectID, arn).Scan(
 		&res.ID,
 		&res.ProjectID,
 		&secretID,
+		&resourceID,
 		&res.ARN,
 		&res.ResourceType,
 		&res.Name,
@@ -245,6 +282,9 @@ func (r *DiscoveredResourceRepository) GetByARN(ctx context.Context, projectID,
 		&res.DiscoveredAt,
 		&res.CreatedAt,
 		&res.UpdatedAt,
+		&res.Notes,
+		&res.OwnerLabel,
+		&res.ManagedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -253,6 +293,9 @@ func (r *DiscoveredResourceRepository) GetByARN(ctx context.Context, projectID,
 	if secretID != nil {
 		res.SecretID = *secretID
 	}
+	if resourceID != nil {
+		res.ResourceID = *resourceID
+	}
 	if metadata != nil {
 		res.Metadata = json.RawMessage(*metadata)
 	}
@@ -263,22 +306,84 @@ func (r *DiscoveredResourceRepository) GetByARN(ctx context.Context, projectID,
 	return &res, nil
 }
 
+// GetByARNs retrieves the discovered resources with any of the given ARNs, across all projects
+func (r *DiscoveredResourceRepository) GetByARNs(ctx context.Context, arns []string) ([]models.DiscoveredResource, error) {
+	query := `
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
+		FROM discovered_resources
+		WHERE arn = ANY($1)
+		ORDER BY arn, created_at
+	`
+
+	rows, err := database.DB.Query(ctx, query, arns)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var resources []models.DiscoveredResource
+	for rows.Next() {
+		var res models.DiscoveredResource
+		var secretID, resourceID, metadata *string
+		var lastSyncedAt *time.Time
+
+		err := rows.Scan(
+			&res.ID,
+			&res.ProjectID,
+			&secretID,
+			&resourceID,
+			&res.ARN,
+			&res.ResourceType,
+			&res.Name,
+			&res.Region,
+			&res.Status,
+			&metadata,
+			&lastSyncedAt,
+			&res.DiscoveredAt,
+			&res.CreatedAt,
+			&res.UpdatedAt,
+			&res.Notes,
+			&res.OwnerLabel,
+			&res.ManagedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if secretID != nil {
+			res.SecretID = *secretID
+		}
+		if resourceID != nil {
+			res.ResourceID = *resourceID
+		}
+		if metadata != nil {
+			res.Metadata = json.RawMessage(*metadata)
+		}
+		res.LastSyncedAt = lastSyncedAt
+
+		resources = append(resources, res)
+	}
+
+	return resources, rows.Err()
+}
+
 // FindByID finds a discovered resource by ID
 func (r *DiscoveredResourceRepository) FindByID(ctx context.Context, id string) (*models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		WHERE id = $1
 	`
 
 	var res models.DiscoveredResource
-	var secretID, metadata *string
+	var secretID, resourceID, metadata *string
 	var lastSyncedAt *time.Time
 
 	err := database.DB.QueryRow(ctx, query, id).Scan(
 		&res.ID,
 		&res.ProjectID,
 		&secretID,
+		&resourceID,
 		&res.ARN,
 		&res.ResourceType,
 		&res.Name,
@@ -289,6 +394,9 @@ func (r *DiscoveredResourceRepository) FindByID(ctx context.Context, id string)
 		&res.DiscoveredAt,
 		&res.CreatedAt,
 		&res.UpdatedAt,
+		&res.Notes,
+		&res.OwnerLabel,
+		&res.ManagedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -297,6 +405,9 @@ func (r *DiscoveredResourceRepository) FindByID(ctx context.Context, id string)
 	if secretID != nil {
 		res.SecretID = *secretID
 	}
+	if resourceID != nil {
+		res.ResourceID = *resourceID
+	}
 	if metadata != nil {
 		res.Metadata = json.RawMessage(*metadata)
 	}
@@ -310,20 +421,21 @@ func (r *DiscoveredResourceRepository) FindByID(ctx context.Context, id string)
 // FindByName finds a discovered resource by name
 func (r *DiscoveredResourceRepository) FindByName(ctx context.Context, name string) (*models.DiscoveredResource, error) {
 	query := `
-		SELECT id, project_id, secret_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at
+		SELECT id, project_id, secret_id, resource_id, arn, resource_type, name, region, status, metadata, last_synced_at, discovered_at, created_at, updated_at, notes, owner_label, managed_by
 		FROM discovered_resources
 		WHERE name = $1
 		LIMIT 1
 	`
 
 	var res models.DiscoveredResource
-	var secretID, metadata *string
+	var secretID, resourceID, metadata *string
 	var lastSyncedAt *time.Time
 
 	err := database.DB.QueryRow(ctx, query, name).Scan(
 		&res.ID,
 		&res.ProjectID,
 		&secretID,
+		&resourceID,
 		&res.ARN,
 		&res.ResourceType,
 		&res.Name,
@@ -334,6 +446,9 @@ func (r *DiscoveredResourceRepository) FindByName(ctx context.Context, name stri
 		&res.DiscoveredAt,
 		&res.CreatedAt,
 		&res.UpdatedAt,
+		&res.Notes,
+		&res.OwnerLabel,
+		&res.ManagedBy,
 	)
 	if err != nil {
 		return nil, err
@@ -342,6 +457,9 @@ func (r *DiscoveredResourceRepository) FindByName(ctx context.Context, name stri
 	if secretID != nil {
 		res.SecretID = *secretID
 	}
+	if resourceID != nil {
+		res.ResourceID = *resourceID
+	}
 	if metadata != nil {
 		res.Metadata = json.RawMessage(*metadata)
 	}
@@ -352,51 +470,148 @@ func (r *DiscoveredResourceRepository) FindByName(ctx context.Context, name stri
 	return &res, nil
 }
 
-// UpdateStatus updates the status of a discovered resource
-func (r *DiscoveredResourceRepository) UpdateStatus(ctx context.Context, id string, status models.DiscoveredResourceStatus) error {
+// CountByProjectID returns the number of discovered resources of a project that are
+// not deleted, in total and by resource type
+func (r *DiscoveredResourceRepository) CountByProjectID(ctx context.Context, projectID string) (int, map[string]int, error) {
 	query := `
-		UPDATE discovered_resources 
-		SET status = $1, last_synced_at = NOW(), updated_at = NOW()
-		WHERE id = $2
+		SELECT resource_type, COUNT(*)
+		FROM discovered_resources
+		WHERE project_id = $1 AND status != 'deleted'
+		GROUP BY resource_type
 	`
 
-	result, err := database.DB.Exec(ctx, query, status, id)
+	rows, err := database.DB.Query(ctx, query, projectID)
 	if err != nil {
-		return err
+		return 0, nil, fmt.Errorf("failed to count discovered resources: %w", err)
 	}
+	defer rows.Close()
+
+	total := 0
+	byType := make(map[string]int)
+	for rows.Next() {
+		var resourceType string
+		var count int
+		if err := rows.Scan(&resourceType, &count); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan resource count: %w", err)
+		}
+		byType[resourceType] = count
+		total += count
+	}
+	return total, byType, rows.Err()
+}
 
+// UpdateAnnotations sets the notes and owner label of a discovered resource
+func (r *DiscoveredResourceRepository) UpdateAnnotations(ctx context.Context, id, notes, ownerLabel string) error {
+	result, err := database.DB.Exec(ctx,
+		`UPDATE discovered_resources SET notes = $2, owner_label = $3, updated_at = NOW() WHERE id = $1`,
+		id, notes, ownerLabel,
+	)
+	if err != nil {
+		return err
+	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("resource not found")
+		return fmt.Errorf("discovered resource not found: %s", id)
 	}
+	return nil
+}
 
+// SetManagedBy records who manages a discovered resource, and the provisioned resource
+// it tracks, if any
+func (r *DiscoveredResourceRepository) SetManagedBy(ctx context.Context, id, managedBy string) error {
+	query := `
+		WITH updated AS (
+			UPDATE discovered_resources SET managed_by = $2, updated_at = NOW() WHERE id = $1
+			RETURNING resource_id
+		), linked AS (
+			UPDATE resources SET managed_by = $2, updated_at = NOW()
+			WHERE id IN (SELECT resource_id FROM updated)
+		)
+		SELECT COUNT(*) FROM updated
+	`
+	var updated int
+	if err := database.DB.QueryRow(ctx, query, id, managedBy).Scan(&updated); err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("discovered resource not found: %s", id)
+	}
 	return nil
 }
 
-// MarkAllAsUnknown marks all resources for a project as unknown (before sync)
-func (r *DiscoveredResourceRepository) MarkAllAsUnknown(ctx context.Context, projectID, secretID string) error {
+// TransitionStatus moves a discovered resource from one status to another. The update
+// only applies while the resource is still in from; otherwise a *StatusConflictError
+// reports the status it actually has. Moving to deleted also deletes the linked
+// provisioned resource.
+func (r *DiscoveredResourceRepository) TransitionStatus(ctx context.Context, id string, from, to models.DiscoveredResourceStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	// A linked provisioned resource that disappeared from AWS is deleted along with it
 	query := `
-		UPDATE discovered_resources 
-		SET status = 'unknown', updated_at = NOW()
-		WHERE project_id = $1 AND secret_id = $2
+		WITH updated AS (
+			UPDATE discovered_resources 
+			SET status = $1, last_synced_at = NOW(), updated_at = NOW()
+			WHERE id = $2 AND status = $3
+			RETURNING resource_id, status
+		), linked AS (
+			UPDATE resources r
+			SET status = 'deleted', updated_at = NOW()
+			FROM updated u
+			WHERE r.id = u.resource_id AND u.status = 'deleted' AND r.status IN ('active', 'failed')
+		)
+		SELECT COUNT(*) FROM updated
 	`
 
-	_, err := database.DB.Exec(ctx, query, projectID, secretID)
-	return err
+	var updated int
+	if err := database.DB.QueryRow(ctx, query, to, id, from).Scan(&updated); err != nil {
+		return err
+	}
+	if updated > 0 {
+		return nil
+	}
+
+	var actual string
+	if err := database.DB.QueryRow(ctx, "SELECT status FROM discovered_resources WHERE id = $1", id).Scan(&actual); err != nil {
+		return fmt.Errorf("resource not found")
+	}
+	return &StatusConflictError{ResourceID: id, Expected: string(from), Actual: actual, Target: string(to)}
 }
 
-// MarkUnknownAsDeleted marks all unknown resources as deleted (after sync)
-func (r *DiscoveredResourceRepository) MarkUnknownAsDeleted(ctx context.Context, projectID, secretID string) (int64, error) {
+// SetMetadataKey stores value under key in a discovered resource's metadata, keeping other keys
+func (r *DiscoveredResourceRepository) SetMetadataKey(ctx context.Context, id, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
 	query := `
-		UPDATE discovered_resources 
-		SET status = 'deleted', updated_at = NOW()
-		WHERE project_id = $1 AND secret_id = $2 AND status = 'unknown'
+		UPDATE discovered_resources
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), ARRAY[$2::text], $3::jsonb), updated_at = NOW()
+		WHERE id = $1
 	`
-
-	result, err := database.DB.Exec(ctx, query, projectID, secretID)
+	result, err := database.DB.Exec(ctx, query, id, key, string(data))
 	if err != nil {
-		return 0, err
+		return fmt.Errorf("failed to update metadata: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("resource not found")
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes resources that have been marked deleted for longer
+// than olderThan, together with their service mappings
+func (r *DiscoveredResourceRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := `
+		DELETE FROM discovered_resources
+		WHERE status = 'deleted' AND updated_at < NOW() - make_interval(secs => $1)
+	`
 
+	result, err := database.DB.Exec(ctx, query, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted resources: %w", err)
+	}
 	return result.RowsAffected(), nil
 }
 
@@ -415,3 +630,43 @@ func (r *DiscoveredResourceRepository) Delete(ctx context.Context, id string) er
 
 	return nil
 }
+
+// Search finds discovered resources by name or ARN, exact name matches first
+func (r *DiscoveredResourceRepository) Search(ctx context.Context, query string, limit int, userID string) ([]models.SearchResult, error) {
+	sql := `
+		SELECT id::text, name, resource_type, project_id::text, arn,
+			` + searchNameRank("name") + ` AS rank
+		FROM discovered_resources
+		WHERE (name ILIKE $3 OR arn ILIKE $3 OR name % $1)
+			AND status != 'deleted'
+			AND ` + projectAccessCondition("project_id", "$4") + `
+		ORDER BY rank DESC, name
+		LIMIT $5
+	`
+
+	rows, err := database.DB.Query(ctx, sql, searchArgs(query, limit, userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search discovered resources: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchTypeResource}
+		var projectID *string
+		var arn string
+		if err := rows.Scan(&result.ID, &result.Name, &result.Description, &projectID, &arn, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan resource search result: %w", err)
+		}
+		if projectID != nil {
+			result.ProjectID = *projectID
+		}
+		result.MatchedField, result.Highlight = matchedField(query,
+			[2]string{"name", result.Name},
+			[2]string{"arn", arn},
+		)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/portalight/backend/internal/database"
 )
 
 type GitHubConfig struct {
@@ -14,36 +14,40 @@ type GitHubConfig struct {
 	RepoOwner                    string     `json:"repo_owner"`
 	RepoName                     string     `json:"repo_name"`
 	Branch                       string     `json:"branch"`
-	ProjectsPath                 string     `json:"projects_path"`
+	ProjectsPaths                []string   `json:"projects_paths"`
 	AuthType                     string     `json:"auth_type"`
 	GitHubAppID                  *int64     `json:"github_app_id"`
 	GitHubAppInstallationID      *int64     `json:"github_app_installation_id"`
-	GitHubAppPrivateKeyEncrypted *string    `json:"-"`
-	PATEncrypted                 *string    `json:"-"`
-	WebhookSecret                string     `json:"webhook_secret,omitempty"`
+	GitHubAppPrivateKeyEncrypted *string    `json:"-" redact:"true"`
+	PATEncrypted                 *string    `json:"-" redact:"true"`
+	WebhookSecret                string     `json:"-" redact:"true"`
+	WebhookHookID                *int64     `json:"webhook_hook_id"` // Set when the webhook was created through the setup endpoint
 	Enabled                      bool       `json:"enabled"`
 	LastScanAt                   *time.Time `json:"last_scan_at"`
 	LastScanStatus               *string    `json:"last_scan_status"`
 	LastScanError                *string    `json:"last_scan_error"`
+	LastWebhookReceivedAt        *time.Time `json:"last_webhook_received_at"`
+	LastTeamSyncAt               *time.Time `json:"last_team_sync_at"`
 	CreatedAt                    time.Time  `json:"created_at"`
 	UpdatedAt                    time.Time  `json:"updated_at"`
 }
 
 type GitHubConfigRepository struct {
-	db *pgxpool.Pool
+	db *database.Pool
 }
 
-func NewGitHubConfigRepository(db *pgxpool.Pool) *GitHubConfigRepository {
+func NewGitHubConfigRepository(db *database.Pool) *GitHubConfigRepository {
 	return &GitHubConfigRepository{db: db}
 }
 
 // GetConfig retrieves the singleton configuration
 func (r *GitHubConfigRepository) GetConfig(ctx context.Context) (*GitHubConfig, error) {
 	query := `
-		SELECT id, repo_owner, repo_name, branch, projects_path, auth_type,
+		SELECT id, repo_owner, repo_name, branch, projects_paths, auth_type,
 		       github_app_id, github_app_installation_id, github_app_private_key_encrypted,
 		       personal_access_token_encrypted, enabled, last_scan_at, last_scan_status,
-		       last_scan_error, created_at, updated_at
+		       last_scan_error, last_webhook_received_at, COALESCE(webhook_secret, ''), webhook_hook_id,
+		       last_team_sync_at, created_at, updated_at
 		FROM github_metadata_config
 		LIMIT 1
 	`
@@ -51,10 +55,11 @@ func (r *GitHubConfigRepository) GetConfig(ctx context.Context) (*GitHubConfig,
 
 	var config GitHubConfig
 	err := row.Scan(
-		&config.ID, &config.RepoOwner, &config.RepoName, &config.Branch, &config.ProjectsPath, &config.AuthType,
+		&config.ID, &config.RepoOwner, &config.RepoName, &config.Branch, &config.ProjectsPaths, &config.AuthType,
 		&config.GitHubAppID, &config.GitHubAppInstallationID, &config.GitHubAppPrivateKeyEncrypted,
 		&config.PATEncrypted, &config.Enabled, &config.LastScanAt, &config.LastScanStatus,
-		&config.LastScanError, &config.CreatedAt, &config.UpdatedAt,
+		&config.LastScanError, &config.LastWebhookReceivedAt, &config.WebhookSecret, &config.WebhookHookID,
+		&config.LastTeamSyncAt, &config.CreatedAt, &config.UpdatedAt,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -74,7 +79,7 @@ func (r *GitHubConfigRepository) SaveConfig(ctx context.Context, config *GitHubC
 
 	query := `
 		INSERT INTO github_metadata_config (
-			id, repo_owner, repo_name, branch, projects_path, auth_type,
+			id, repo_owner, repo_name, branch, projects_paths, auth_type,
 			github_app_id, github_app_installation_id, github_app_private_key_encrypted,
 			personal_access_token_encrypted, enabled, updated_at
 		) VALUES (
@@ -84,7 +89,7 @@ func (r *GitHubConfigRepository) SaveConfig(ctx context.Context, config *GitHubC
 			repo_owner = EXCLUDED.repo_owner,
 			repo_name = EXCLUDED.repo_name,
 			branch = EXCLUDED.branch,
-			projects_path = EXCLUDED.projects_path,
+			projects_paths = EXCLUDED.projects_paths,
 			auth_type = EXCLUDED.auth_type,
 			github_app_id = EXCLUDED.github_app_id,
 			github_app_installation_id = EXCLUDED.github_app_installation_id,
@@ -95,7 +100,7 @@ func (r *GitHubConfigRepository) SaveConfig(ctx context.Context, config *GitHubC
 	`
 
 	_, err := r.db.Exec(ctx, query,
-		singletonID, config.RepoOwner, config.RepoName, config.Branch, config.ProjectsPath, config.AuthType,
+		singletonID, config.RepoOwner, config.RepoName, config.Branch, config.ProjectsPaths, config.AuthType,
 		config.GitHubAppID, config.GitHubAppInstallationID, config.GitHubAppPrivateKeyEncrypted,
 		config.PATEncrypted, config.Enabled,
 	)
@@ -107,6 +112,28 @@ func (r *GitHubConfigRepository) SaveConfig(ctx context.Context, config *GitHubC
 	return nil
 }
 
+// SaveWebhook stores the catalog webhook's GitHub hook ID and secret; nil and "" clear them
+func (r *GitHubConfigRepository) SaveWebhook(ctx context.Context, hookID *int64, secret string) error {
+	singletonID := "00000000-0000-0000-0000-000000000001"
+
+	var secretValue *string
+	if secret != "" {
+		secretValue = &secret
+	}
+
+	query := `
+		UPDATE github_metadata_config
+		SET webhook_hook_id = $2,
+		    webhook_secret = $3,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, singletonID, hookID, secretValue); err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+	return nil
+}
+
 // UpdateScanStatus updates the last scan status
 func (r *GitHubConfigRepository) UpdateScanStatus(ctx context.Context, status string, errMessage *string) error {
 	singletonID := "00000000-0000-0000-0000-000000000001"
@@ -123,3 +150,64 @@ func (r *GitHubConfigRepository) UpdateScanStatus(ctx context.Context, status st
 	}
 	return nil
 }
+
+// UpdateTeamSyncAt sets last_team_sync_at to now
+func (r *GitHubConfigRepository) UpdateTeamSyncAt(ctx context.Context) error {
+	singletonID := "00000000-0000-0000-0000-000000000001"
+	query := `
+		UPDATE github_metadata_config
+		SET last_team_sync_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(ctx, query, singletonID); err != nil {
+		return fmt.Errorf("failed to update team sync time: %w", err)
+	}
+	return nil
+}
+
+// RecordWebhookDelivery stores a received webhook and bumps last_webhook_received_at
+func (r *GitHubConfigRepository) RecordWebhookDelivery(ctx context.Context, eventType, deliveryID string) error {
+	singletonID := "00000000-0000-0000-0000-000000000001"
+
+	var delivery *string
+	if deliveryID != "" {
+		delivery = &deliveryID
+	}
+
+	if _, err := r.db.Exec(ctx,
+		`INSERT INTO webhook_deliveries (delivery_id, event_type) VALUES ($1, $2)`,
+		delivery, eventType,
+	); err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx,
+		`UPDATE github_metadata_config SET last_webhook_received_at = NOW() WHERE id = $1`,
+		singletonID,
+	); err != nil {
+		return fmt.Errorf("failed to update last webhook time: %w", err)
+	}
+
+	// Keep the table small; deliveries are only counted over the last 24h
+	if _, err := r.db.Exec(ctx,
+		`DELETE FROM webhook_deliveries WHERE received_at < NOW() - INTERVAL '7 days'`,
+	); err != nil {
+		return fmt.Errorf("failed to purge old webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// CountRecentWebhookDeliveries returns the number of webhooks received within the window
+// (compared against the database clock, like received_at itself)
+func (r *GitHubConfigRepository) CountRecentWebhookDeliveries(ctx context.Context, window time.Duration) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM webhook_deliveries WHERE received_at >= NOW() - $1 * INTERVAL '1 second'`,
+		window.Seconds(),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// NotificationChannelRepository handles the Slack and webhook channels of teams
+type NotificationChannelRepository struct{}
+
+// NewNotificationChannelRepository creates a new notification channel repository
+func NewNotificationChannelRepository() *NotificationChannelRepository {
+	return &NotificationChannelRepository{}
+}
+
+const notificationChannelColumns = `id, team_id, type, url, events, COALESCE(created_by, ''), created_at`
+
+// ListByTeam returns the channels of a team, oldest first
+func (r *NotificationChannelRepository) ListByTeam(ctx context.Context, teamID string) ([]models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + `
+		FROM notification_channels
+		WHERE team_id = $1::uuid
+		ORDER BY created_at`
+	return r.list(ctx, query, teamID)
+}
+
+// ListForProjectEvent returns the channels subscribed to an event of a project: those
+// of the owner team and of every team granted access to the project
+func (r *NotificationChannelRepository) ListForProjectEvent(ctx context.Context, projectID, event string) ([]models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + `
+		FROM notification_channels
+		WHERE $2 = ANY(events)
+		  AND team_id IN (
+			SELECT owner_team_id FROM projects WHERE id = $1::uuid AND owner_team_id IS NOT NULL
+			UNION
+			SELECT team_id FROM project_access WHERE project_id = $1::uuid
+		  )
+		ORDER BY created_at`
+	return r.list(ctx, query, projectID, event)
+}
+
+func (r *NotificationChannelRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.NotificationChannel, error) {
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []models.NotificationChannel{}
+	for rows.Next() {
+		c, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification channel: %w", err)
+		}
+		channels = append(channels, *c)
+	}
+	return channels, rows.Err()
+}
+
+// Create stores a new channel, filling in its ID and creation time
+func (r *NotificationChannelRepository) Create(ctx context.Context, channel *models.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (team_id, type, url, events, created_by)
+		VALUES ($1::uuid, $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at
+	`
+	err := database.DB.QueryRow(ctx, query, channel.TeamID, channel.Type, channel.URL, channel.Events, channel.CreatedBy).
+		Scan(&channel.ID, &channel.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a channel of a team; ErrNotFound means the team has no such channel
+func (r *NotificationChannelRepository) Delete(ctx context.Context, teamID, channelID string) error {
+	tag, err := database.DB.Exec(ctx,
+		`DELETE FROM notification_channels WHERE id = $1::uuid AND team_id = $2::uuid`, channelID, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanNotificationChannel(row pgx.Row) (*models.NotificationChannel, error) {
+	var c models.NotificationChannel
+	if err := row.Scan(&c.ID, &c.TeamID, &c.Type, &c.URL, &c.Events, &c.CreatedBy, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// NotificationPreferencesRepository handles which notification events users receive
+type NotificationPreferencesRepository struct{}
+
+// NewNotificationPreferencesRepository creates a new notification preferences repository
+func NewNotificationPreferencesRepository() *NotificationPreferencesRepository {
+	return &NotificationPreferencesRepository{}
+}
+
+// Get returns a user's preferences with an entry for every known event. Users who
+// never saved preferences receive everything.
+func (r *NotificationPreferencesRepository) Get(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	query := `
+		SELECT disabled_events, updated_at
+		FROM user_notification_preferences
+		WHERE user_id = $1::uuid
+	`
+
+	var disabled []string
+	var updatedAt time.Time
+	prefs := &models.NotificationPreferences{Events: make(map[string]bool)}
+	err := database.DB.QueryRow(ctx, query, userID).Scan(&disabled, &updatedAt)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	if err == nil {
+		prefs.UpdatedAt = &updatedAt
+	}
+
+	for _, event := range models.NotificationEvents {
+		prefs.Events[event] = true
+	}
+	for _, event := range disabled {
+		if _, ok := prefs.Events[event]; ok {
+			prefs.Events[event] = false
+		}
+	}
+	return prefs, nil
+}
+
+// Save stores the events a user opted out of; events missing from prefs stay enabled
+func (r *NotificationPreferencesRepository) Save(ctx context.Context, userID string, prefs *models.NotificationPreferences) error {
+	disabled := []string{}
+	for _, event := range models.NotificationEvents {
+		if enabled, ok := prefs.Events[event]; ok && !enabled {
+			disabled = append(disabled, event)
+		}
+	}
+	now := time.Now()
+
+	query := `
+		INSERT INTO user_notification_preferences (user_id, disabled_events, updated_at)
+		VALUES ($1::uuid, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			disabled_events = EXCLUDED.disabled_events,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := database.DB.Exec(ctx, query, userID, disabled, now); err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	prefs.UpdatedAt = &now
+	return nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/portalight/backend/internal/database"
+)
+
+const (
+	// DefaultListLimit is used when a list request does not ask for a page size
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size a client can request
+	MaxListLimit = 500
+)
+
+// ErrInvalidListOptions wraps errors caused by a bad sort field or direction
+var ErrInvalidListOptions = errors.New("invalid list options")
+
+// ListOptions controls paging and ordering of GetAll queries
+type ListOptions struct {
+	Limit     int
+	Offset    int
+	SortField string // Must be one of the repository's sortable columns; empty uses the first one
+	SortDir   string // "asc" or "desc"; empty uses the repository default
+}
+
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// ApplyPagination wraps a base SELECT so every row also carries the total row count
+// (as a trailing total_count column) and only the requested page is returned.
+// The sort field is checked against allowedFields, which must all be columns of the
+// base query; the first allowed field is the default. The base query must select id,
+// which breaks ties so pages stay stable. Returned args hold the LIMIT and OFFSET
+// values and must be passed after the base query's own args.
+func ApplyPagination(query string, opts ListOptions, allowedFields []string) (string, []interface{}, error) {
+	if len(allowedFields) == 0 {
+		return "", nil, fmt.Errorf("no sortable fields configured")
+	}
+
+	field := opts.SortField
+	if field == "" {
+		field = allowedFields[0]
+	}
+	allowed := false
+	for _, candidate := range allowedFields {
+		if field == candidate {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", nil, fmt.Errorf("%w: sort field %q (allowed: %s)", ErrInvalidListOptions, field, strings.Join(allowedFields, ", "))
+	}
+
+	var dir string
+	switch strings.ToLower(opts.SortDir) {
+	case "", "asc":
+		dir = "ASC"
+	case "desc":
+		dir = "DESC"
+	default:
+		return "", nil, fmt.Errorf("%w: sort direction %q (allowed: asc, desc)", ErrInvalidListOptions, opts.SortDir)
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Continue numbering after the highest placeholder of the base query
+	next := 1
+	for _, match := range placeholderPattern.FindAllStringSubmatch(query, -1) {
+		if n, err := strconv.Atoi(match[1]); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	orderBy := field + " " + dir
+	if field != "id" {
+		orderBy += ", id"
+	}
+
+	paged := fmt.Sprintf(`
+		WITH base AS (%s)
+		SELECT base.*, COUNT(*) OVER () AS total_count
+		FROM base
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, query, orderBy, next, next+1)
+
+	return paged, []interface{}{limit, offset}, nil
+}
+
+// countRows counts the rows of a base query. Paginated queries carry the total on
+// every row, so this is only needed when a page past the end comes back empty.
+func countRows(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var total int
+	err := database.DB.QueryRow(ctx, "WITH base AS ("+query+") SELECT COUNT(*) FROM base", args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return total, nil
+}
+
+// withDefaultSort fills in the repository's natural order when the client did not pick one
+func (o ListOptions) withDefaultSort(field, dir string) ListOptions {
+	if o.SortField == "" {
+		o.SortField = field
+		if o.SortDir == "" {
+			o.SortDir = dir
+		}
+	}
+	return o
+}
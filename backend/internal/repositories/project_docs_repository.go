@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/models"
+)
+
+// ProjectDocsRepository handles the markdown docs of catalog projects
+type ProjectDocsRepository struct {
+	querier
+}
+
+// NewProjectDocsRepository creates a new project docs repository
+func NewProjectDocsRepository() *ProjectDocsRepository {
+	return &ProjectDocsRepository{}
+}
+
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ProjectDocsRepository) WithQuerier(q Querier) *ProjectDocsRepository {
+	return &ProjectDocsRepository{querier: querier{q: q}}
+}
+
+// FindByProjectID returns the docs of a project, or ErrNotFound when it has none
+func (r *ProjectDocsRepository) FindByProjectID(ctx context.Context, projectID string) (*models.ProjectDocs, error) {
+	var docs models.ProjectDocs
+	err := r.db().QueryRow(ctx, `
+		SELECT project_id, path, content, sha, fetched_at
+		FROM project_docs
+		WHERE project_id = $1::uuid
+	`, projectID).Scan(&docs.ProjectID, &docs.Path, &docs.Content, &docs.SHA, &docs.FetchedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project docs: %w", err)
+	}
+	return &docs, nil
+}
+
+// Upsert stores the docs of a project, replacing any earlier version
+func (r *ProjectDocsRepository) Upsert(ctx context.Context, docs *models.ProjectDocs) error {
+	err := r.db().QueryRow(ctx, `
+		INSERT INTO project_docs (project_id, path, content, sha, fetched_at)
+		VALUES ($1::uuid, $2, $3, $4, NOW())
+		ON CONFLICT (project_id) DO UPDATE SET
+			path = EXCLUDED.path,
+			content = EXCLUDED.content,
+			sha = EXCLUDED.sha,
+			fetched_at = EXCLUDED.fetched_at
+		RETURNING fetched_at
+	`, docs.ProjectID, docs.Path, docs.Content, docs.SHA).Scan(&docs.FetchedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save project docs: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the docs of a project; projects without docs are not an error
+func (r *ProjectDocsRepository) Delete(ctx context.Context, projectID string) error {
+	if _, err := r.db().Exec(ctx, `DELETE FROM project_docs WHERE project_id = $1::uuid`, projectID); err != nil {
+		return fmt.Errorf("failed to delete project docs: %w", err)
+	}
+	return nil
+}
+
+// CatalogPathsByDocsPath maps every stored docs path to the catalog files of the
+// projects that reference it, so a push to a docs file re-syncs those projects
+func (r *ProjectDocsRepository) CatalogPathsByDocsPath(ctx context.Context) (map[string][]string, error) {
+	rows, err := r.db().Query(ctx, `
+		SELECT d.path, p.catalog_file_path
+		FROM project_docs d
+		JOIN projects p ON p.id = d.project_id
+		WHERE p.catalog_file_path IS NOT NULL AND p.catalog_file_path != ''
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project docs paths: %w", err)
+	}
+	defer rows.Close()
+
+	paths := make(map[string][]string)
+	for rows.Next() {
+		var docsPath, catalogPath string
+		if err := rows.Scan(&docsPath, &catalogPath); err != nil {
+			return nil, fmt.Errorf("failed to scan project docs path: %w", err)
+		}
+		paths[docsPath] = append(paths[docsPath], catalogPath)
+	}
+	return paths, rows.Err()
+}
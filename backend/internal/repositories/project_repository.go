@@ -2,33 +2,49 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/portalight/backend/internal/database"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/portalight/backend/internal/models"
 )
 
 // ProjectRepository handles project database operations
-type ProjectRepository struct{}
+type ProjectRepository struct {
+	querier
+}
 
-// GetAll retrieves all projects
-func (r *ProjectRepository) GetAll(ctx context.Context) ([]models.Project, error) {
-	query := `
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ProjectRepository) WithQuerier(q Querier) *ProjectRepository {
+	return &ProjectRepository{querier: querier{q: q}}
+}
+
+// projectSortFields are the columns projects can be listed by
+var projectSortFields = []string{"created_at", "name", "updated_at"}
+
+// GetAll retrieves one page of projects and the total number of projects
+func (r *ProjectRepository) GetAll(ctx context.Context, opts ListOptions) ([]models.Project, int, error) {
+	baseQuery := `
 		SELECT id, name, description, confluence_url, avatar, owner_team_id, created_at, updated_at
 		FROM projects
-		ORDER BY created_at DESC
 	`
 
-	rows, err := database.DB.Query(ctx, query)
+	query, args, err := ApplyPagination(baseQuery, opts.withDefaultSort("created_at", "desc"), projectSortFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	rows, err := r.db().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var projects []models.Project
+	projects := []models.Project{}
+	total := 0
 	for rows.Next() {
 		var project models.Project
 		var confluenceURL, avatar, ownerTeamID *string
@@ -42,9 +58,10 @@ func (r *ProjectRepository) GetAll(ctx context.Context) ([]models.Project, error
 			&ownerTeamID,
 			&project.CreatedAt,
 			&project.UpdatedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if confluenceURL != nil {
@@ -65,7 +82,17 @@ func (r *ProjectRepository) GetAll(ctx context.Context) ([]models.Project, error
 		projects = append(projects, project)
 	}
 
-	return projects, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(projects) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return projects, total, nil
 }
 
 // FindByID finds a project by ID
@@ -81,7 +108,7 @@ func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*models.Pr
 	var project models.Project
 	var confluenceURL, avatar, ownerTeamID, secretID, catalogFilePath, syncStatus *string
 
-	err := database.DB.QueryRow(ctx, query, id).Scan(
+	err := r.db().QueryRow(ctx, query, id).Scan(
 		&project.ID,
 		&project.Name,
 		&project.Description,
@@ -143,7 +170,7 @@ func (r *ProjectRepository) FindByName(ctx context.Context, name string) (*model
 	var project models.Project
 	var confluenceURL, avatar, ownerTeamID, secretID, catalogFilePath, syncStatus *string
 
-	err := database.DB.QueryRow(ctx, query, name).Scan(
+	err := r.db().QueryRow(ctx, query, name).Scan(
 		&project.ID,
 		&project.Name,
 		&project.Description,
@@ -221,7 +248,7 @@ func (r *ProjectRepository) Create(ctx context.Context, project *models.Project)
 		secretID = &project.SecretID
 	}
 
-	_, err := database.DB.Exec(ctx, query,
+	_, err := r.db().Exec(ctx, query,
 		project.ID,
 		project.Name,
 		project.Description,
@@ -260,7 +287,7 @@ func (r *ProjectRepository) Update(ctx context.Context, project *models.Project)
 		secretID = &project.SecretID
 	}
 
-	_, err := database.DB.Exec(ctx, query,
+	_, err := r.db().Exec(ctx, query,
 		project.Name,
 		project.Description,
 		confluenceURL,
@@ -277,7 +304,7 @@ func (r *ProjectRepository) Update(ctx context.Context, project *models.Project)
 // Delete deletes a project
 func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM projects WHERE id = $1::uuid`
-	_, err := database.DB.Exec(ctx, query, id)
+	_, err := r.db().Exec(ctx, query, id)
 	return err
 }
 
@@ -289,7 +316,7 @@ func (r *ProjectRepository) GetProjectAccess(ctx context.Context, projectID stri
 		WHERE project_id = $1::uuid
 	`
 
-	rows, err := database.DB.Query(ctx, query, projectID)
+	rows, err := r.db().Query(ctx, query, projectID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -312,10 +339,33 @@ func (r *ProjectRepository) GetProjectAccess(ctx context.Context, projectID stri
 	return teamIDs, userIDs, rows.Err()
 }
 
+// CanUserAccess reports whether a user owns the project through a team or was
+// granted access via project_access (the same rule search applies)
+func (r *ProjectRepository) CanUserAccess(ctx context.Context, projectID, userID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM projects p
+			WHERE p.id = $1::uuid
+				AND p.owner_team_id IN (SELECT team_id FROM team_members WHERE user_id = $2::uuid)
+		) OR EXISTS (
+			SELECT 1 FROM project_access pa
+			WHERE pa.project_id = $1::uuid
+				AND (pa.user_id = $2::uuid
+					OR pa.team_id IN (SELECT team_id FROM team_members WHERE user_id = $2::uuid))
+		)
+	`
+
+	var allowed bool
+	if err := r.db().QueryRow(ctx, query, projectID, userID).Scan(&allowed); err != nil {
+		return false, fmt.Errorf("failed to check project access: %w", err)
+	}
+	return allowed, nil
+}
+
 // UpdateProjectAccess updates who has access to a project
 func (r *ProjectRepository) UpdateProjectAccess(ctx context.Context, projectID string, teamIDs, userIDs []string) error {
 	// Start transaction
-	tx, err := database.DB.Begin(ctx)
+	tx, err := r.db().Begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -354,7 +404,7 @@ func (r *ProjectRepository) UpdateProjectAccess(ctx context.Context, projectID s
 func (r *ProjectRepository) FindByCatalogPath(ctx context.Context, path string) (*models.Project, error) {
 	query := `
 		SELECT id, name, description, confluence_url, avatar, owner_team_id, 
-		       catalog_file_path, catalog_metadata, last_synced_at, sync_status, sync_error, auto_synced,
+		       catalog_file_path, catalog_name, slug, catalog_metadata, last_synced_at, sync_status, sync_error, auto_synced,
 		       created_at, updated_at
 		FROM projects
 		WHERE catalog_file_path = $1
@@ -362,10 +412,10 @@ func (r *ProjectRepository) FindByCatalogPath(ctx context.Context, path string)
 
 	var project models.Project
 	var confluenceURL, avatar, ownerTeamID *string
-	var catalogFilePath, syncStatus, syncError *string
+	var catalogFilePath, catalogName, slug, syncStatus, syncError *string
 	var lastSyncedAt *time.Time
 
-	err := database.DB.QueryRow(ctx, query, path).Scan(
+	err := r.db().QueryRow(ctx, query, path).Scan(
 		&project.ID,
 		&project.Name,
 		&project.Description,
@@ -373,6 +423,8 @@ func (r *ProjectRepository) FindByCatalogPath(ctx context.Context, path string)
 		&avatar,
 		&ownerTeamID,
 		&catalogFilePath,
+		&catalogName,
+		&slug,
 		&project.CatalogMetadata,
 		&lastSyncedAt,
 		&syncStatus,
@@ -401,6 +453,12 @@ func (r *ProjectRepository) FindByCatalogPath(ctx context.Context, path string)
 	if catalogFilePath != nil {
 		project.CatalogFilePath = *catalogFilePath
 	}
+	if catalogName != nil {
+		project.CatalogName = *catalogName
+	}
+	if slug != nil {
+		project.Slug = *slug
+	}
 	if syncStatus != nil {
 		project.SyncStatus = *syncStatus
 	}
@@ -412,7 +470,41 @@ func (r *ProjectRepository) FindByCatalogPath(ctx context.Context, path string)
 	return &project, nil
 }
 
-// UpsertFromCatalog creates or updates a project from catalog data
+// ErrNameConflict is matched (with errors.Is) by a ProjectConflictError
+var ErrNameConflict = errors.New("project name conflict")
+
+// ProjectConflictError is returned by UpsertFromCatalog when another project already
+// uses the project's name or slug
+type ProjectConflictError struct {
+	Field           string // "name" or "slug"
+	Value           string
+	CatalogFilePath string // Catalog file of the other project; empty if it was created by hand
+}
+
+func (e *ProjectConflictError) Error() string {
+	owner := e.CatalogFilePath
+	if owner == "" {
+		owner = "a project not synced from the catalog"
+	}
+	return fmt.Sprintf("project %s '%s' already used by %s", e.Field, e.Value, owner)
+}
+
+func (e *ProjectConflictError) Is(target error) bool {
+	return target == ErrNameConflict
+}
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// projectConflictColumns maps the unique constraints of projects to their column
+var projectConflictColumns = map[string]string{
+	"projects_name_key": "name",
+	"idx_projects_slug": "slug",
+}
+
+// UpsertFromCatalog creates or updates a project from catalog data, keyed by catalog
+// file path. If another project already has the name or slug it returns a
+// *ProjectConflictError, also when a concurrent sync created that project first.
 func (r *ProjectRepository) UpsertFromCatalog(ctx context.Context, project *models.Project) error {
 	if project.ID == "" {
 		project.ID = uuid.New().String()
@@ -428,16 +520,18 @@ func (r *ProjectRepository) UpsertFromCatalog(ctx context.Context, project *mode
 		INSERT INTO projects (
 			id, name, description, confluence_url, avatar, owner_team_id,
 			catalog_file_path, catalog_metadata, last_synced_at, sync_status, sync_error, auto_synced,
-			created_at, updated_at
+			created_at, updated_at, catalog_name, slug
 		) VALUES (
 			$1, $2, $3, $4, $5, $6,
 			$7, $8, $9, $10, $11, $12,
-			$13, $14
+			$13, $14, $15, $16
 		)
 		ON CONFLICT (catalog_file_path) DO UPDATE SET
 			name = EXCLUDED.name,
 			description = EXCLUDED.description,
 			owner_team_id = EXCLUDED.owner_team_id,
+			catalog_name = EXCLUDED.catalog_name,
+			slug = EXCLUDED.slug,
 			catalog_metadata = EXCLUDED.catalog_metadata,
 			last_synced_at = EXCLUDED.last_synced_at,
 			sync_status = EXCLUDED.sync_status,
@@ -447,7 +541,7 @@ func (r *ProjectRepository) UpsertFromCatalog(ctx context.Context, project *mode
 		RETURNING id
 	`
 
-	var confluenceURL, avatar, ownerTeamID *string
+	var confluenceURL, avatar, ownerTeamID, slug *string
 	if project.ConfluenceURL != "" {
 		confluenceURL = &project.ConfluenceURL
 	}
@@ -457,8 +551,18 @@ func (r *ProjectRepository) UpsertFromCatalog(ctx context.Context, project *mode
 	if project.OwnerTeamID != "" {
 		ownerTeamID = &project.OwnerTeamID
 	}
+	if project.Slug != "" {
+		slug = &project.Slug
+	}
+
+	// A savepoint keeps the caller's transaction usable after a unique violation
+	savepoint, err := r.db().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer savepoint.Rollback(ctx)
 
-	err := database.DB.QueryRow(ctx, query,
+	err = savepoint.QueryRow(ctx, query,
 		project.ID,
 		project.Name,
 		project.Description,
@@ -473,7 +577,153 @@ func (r *ProjectRepository) UpsertFromCatalog(ctx context.Context, project *mode
 		project.AutoSynced,
 		project.CreatedAt,
 		project.UpdatedAt,
+		project.CatalogName,
+		slug,
 	).Scan(&project.ID)
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if column, ok := projectConflictColumns[pgErr.ConstraintName]; ok {
+			savepoint.Rollback(ctx)
+			return r.conflictError(ctx, column, project)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return savepoint.Commit(ctx)
+}
+
+// conflictError builds the ProjectConflictError for a project whose name or slug is taken
+func (r *ProjectRepository) conflictError(ctx context.Context, column string, project *models.Project) error {
+	value := project.Name
+	if column == "slug" {
+		value = project.Slug
+	}
+
+	var catalogFilePath *string
+	err := r.db().QueryRow(ctx,
+		`SELECT catalog_file_path FROM projects WHERE `+column+` = $1`, value,
+	).Scan(&catalogFilePath)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to look up conflicting project: %w", err)
+	}
+
+	conflict := &ProjectConflictError{Field: column, Value: value}
+	if catalogFilePath != nil {
+		conflict.CatalogFilePath = *catalogFilePath
+	}
+	return conflict
+}
+
+// ProjectSyncStatusOrphaned marks a project whose catalog file no longer exists
+const ProjectSyncStatusOrphaned = "orphaned"
+
+// ListCatalogProjects returns every project linked to a catalog file, with the
+// fields the catalog reconciliation needs
+func (r *ProjectRepository) ListCatalogProjects(ctx context.Context) ([]models.Project, error) {
+	query := `
+		SELECT id, name, owner_team_id, catalog_file_path, last_synced_sha, sync_status
+		FROM projects
+		WHERE catalog_file_path IS NOT NULL AND catalog_file_path != ''
+		ORDER BY catalog_file_path
+	`
+
+	rows, err := r.db().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list catalog projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var project models.Project
+		var ownerTeamID, lastSyncedSHA, syncStatus *string
+		if err := rows.Scan(&project.ID, &project.Name, &ownerTeamID, &project.CatalogFilePath, &lastSyncedSHA, &syncStatus); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		if ownerTeamID != nil {
+			project.OwnerTeamID = *ownerTeamID
+		}
+		if lastSyncedSHA != nil {
+			project.LastSyncedSHA = *lastSyncedSHA
+		}
+		if syncStatus != nil {
+			project.SyncStatus = *syncStatus
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+// SetLastSyncedSHA records the blob SHA of the catalog file a project was last synced from
+func (r *ProjectRepository) SetLastSyncedSHA(ctx context.Context, id, sha string) error {
+	_, err := r.db().Exec(ctx, `UPDATE projects SET last_synced_sha = $2 WHERE id = $1`, id, sha)
 	return err
 }
+
+// MarkOrphaned flags a project whose catalog file was removed from the repository.
+// Its services are kept; syncing the file again, should it come back, clears the flag.
+func (r *ProjectRepository) MarkOrphaned(ctx context.Context, id, reason string) error {
+	_, err := r.db().Exec(ctx, `
+		UPDATE projects
+		SET sync_status = $2, sync_error = $3, last_synced_sha = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, id, ProjectSyncStatusOrphaned, reason)
+	return err
+}
+
+// FindBySlug returns the project with the given slug, or nil if there is none
+func (r *ProjectRepository) FindBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	var project models.Project
+	var catalogFilePath *string
+	err := r.db().QueryRow(ctx,
+		`SELECT id, name, slug, catalog_file_path FROM projects WHERE slug = $1`, slug,
+	).Scan(&project.ID, &project.Name, &project.Slug, &catalogFilePath)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if catalogFilePath != nil {
+		project.CatalogFilePath = *catalogFilePath
+	}
+	return &project, nil
+}
+
+// Search finds projects by name or description, exact name matches first
+func (r *ProjectRepository) Search(ctx context.Context, query string, limit int, userID string) ([]models.SearchResult, error) {
+	sql := `
+		SELECT id::text, name, COALESCE(description, ''),
+			` + searchNameRank("name") + ` + ts_rank_cd(` + searchDocument + `, plainto_tsquery('english', $1)) AS rank
+		FROM projects
+		WHERE (name ILIKE $3 OR description ILIKE $3 OR name % $1
+			OR ` + searchDocument + ` @@ plainto_tsquery('english', $1))
+			AND ` + projectAccessCondition("id", "$4") + `
+		ORDER BY rank DESC, name
+		LIMIT $5
+	`
+
+	rows, err := r.db().Query(ctx, sql, searchArgs(query, limit, userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchTypeProject}
+		if err := rows.Scan(&result.ID, &result.Name, &result.Description, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan project search result: %w", err)
+		}
+		result.ProjectID = result.ID
+		result.MatchedField, result.Highlight = matchedField(query,
+			[2]string{"name", result.Name},
+			[2]string{"description", result.Description},
+		)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
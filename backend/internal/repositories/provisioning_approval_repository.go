@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/models"
+)
+
+// ErrApprovalDecided is returned when approving or rejecting a request that is no longer pending
+var ErrApprovalDecided = errors.New("provisioning request was already approved or rejected")
+
+// ProvisioningApprovalRepository handles the approvals of dev provisioning requests
+type ProvisioningApprovalRepository struct {
+	querier
+}
+
+// NewProvisioningApprovalRepository creates a new provisioning approval repository
+func NewProvisioningApprovalRepository() *ProvisioningApprovalRepository {
+	return &ProvisioningApprovalRepository{}
+}
+
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ProvisioningApprovalRepository) WithQuerier(q Querier) *ProvisioningApprovalRepository {
+	return &ProvisioningApprovalRepository{querier: querier{q: q}}
+}
+
+// Create stores a pending approval, filling in its ID and timestamps
+func (r *ProvisioningApprovalRepository) Create(ctx context.Context, approval *models.ProvisioningApproval) error {
+	query := `
+		INSERT INTO provisioning_approvals (resource_id, secret_id, requested_by, status)
+		VALUES ($1::uuid, $2::uuid, NULLIF($3, '')::uuid, $4)
+		RETURNING id, created_at, updated_at
+	`
+	approval.Status = models.ApprovalStatusPending
+	err := r.db().QueryRow(ctx, query, approval.ResourceID, approval.SecretID, approval.RequestedBy, approval.Status).
+		Scan(&approval.ID, &approval.CreatedAt, &approval.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create provisioning approval: %w", err)
+	}
+	return nil
+}
+
+// FindByResourceID returns the approval of a resource; ErrNotFound means it never needed one
+func (r *ProvisioningApprovalRepository) FindByResourceID(ctx context.Context, resourceID string) (*models.ProvisioningApproval, error) {
+	query := `
+		SELECT id, resource_id, COALESCE(secret_id::text, ''), COALESCE(requested_by::text, ''), COALESCE(approved_by::text, ''),
+		       status, reason, created_at, updated_at
+		FROM provisioning_approvals
+		WHERE resource_id = $1::uuid
+	`
+	var a models.ProvisioningApproval
+	err := r.db().QueryRow(ctx, query, resourceID).Scan(
+		&a.ID, &a.ResourceID, &a.SecretID, &a.RequestedBy, &a.ApprovedBy,
+		&a.Status, &a.Reason, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provisioning approval: %w", err)
+	}
+	return &a, nil
+}
+
+// Decide approves or rejects a pending approval. Only one decision wins: a request
+// that is no longer pending returns ErrApprovalDecided.
+func (r *ProvisioningApprovalRepository) Decide(ctx context.Context, approval *models.ProvisioningApproval, status models.ApprovalStatus, deciderID, reason string) error {
+	query := `
+		UPDATE provisioning_approvals
+		SET status = $1, approved_by = NULLIF($2, '')::uuid, reason = $3, updated_at = NOW()
+		WHERE id = $4::uuid AND status = 'pending'
+		RETURNING updated_at
+	`
+	err := r.db().QueryRow(ctx, query, status, deciderID, reason, approval.ID).Scan(&approval.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrApprovalDecided
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update provisioning approval: %w", err)
+	}
+	approval.Status, approval.ApprovedBy, approval.Reason = status, deciderID, reason
+	return nil
+}
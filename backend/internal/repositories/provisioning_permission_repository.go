@@ -13,9 +13,10 @@ type ProvisioningPermissionRepository struct{}
 // GetUserPermissions retrieves all provisioning permissions for a user
 func (r *ProvisioningPermissionRepository) GetUserPermissions(ctx context.Context, userID string) (*models.UserProvisioningPermissions, error) {
 	query := `
-		SELECT resource_type
+		SELECT id, resource_type, project_id, credential_id, granted_by, granted_at
 		FROM user_provisioning_permissions
 		WHERE user_id = $1
+		ORDER BY granted_at
 	`
 
 	rows, err := database.DB.Query(ctx, query, userID)
@@ -27,31 +28,49 @@ func (r *ProvisioningPermissionRepository) GetUserPermissions(ctx context.Contex
 	permissions := &models.UserProvisioningPermissions{
 		UserID:       userID,
 		AllowedTypes: []string{},
+		Scoped:       []models.ProvisioningPermission{},
 	}
 
 	for rows.Next() {
-		var resourceType string
-		if err := rows.Scan(&resourceType); err != nil {
+		perm := models.ProvisioningPermission{UserID: userID}
+		var projectID, credentialID *string
+		if err := rows.Scan(&perm.ID, &perm.ResourceType, &projectID, &credentialID, &perm.GrantedBy, &perm.GrantedAt); err != nil {
 			return nil, err
 		}
 
-		permissions.AllowedTypes = append(permissions.AllowedTypes, resourceType)
-
-		switch resourceType {
-		case "s3":
-			permissions.S3Enabled = true
-		case "sqs":
-			permissions.SQSEnabled = true
-		case "sns":
-			permissions.SNSEnabled = true
+		if projectID != nil || credentialID != nil {
+			if projectID != nil {
+				perm.ProjectID = *projectID
+			}
+			if credentialID != nil {
+				perm.CredentialID = *credentialID
+			}
+			permissions.Scoped = append(permissions.Scoped, perm)
+			continue
 		}
+
+		permissions.AllowedTypes = append(permissions.AllowedTypes, perm.ResourceType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return permissions, rows.Err()
+	permissions.SetLegacyFlags()
+	return permissions, nil
 }
 
-// SetUserPermissions updates a user's provisioning permissions
+// SetUserPermissions replaces a user's provisioning permissions with req.Types(), which
+// the caller must have validated. Only permissions in the request's scope
+// (project/credential, or global) are replaced.
 func (r *ProvisioningPermissionRepository) SetUserPermissions(ctx context.Context, userID string, req *models.UpdateProvisioningPermissionsRequest, grantedBy string) error {
+	var projectID, credentialID *string
+	if req.ProjectID != "" {
+		projectID = &req.ProjectID
+	}
+	if req.CredentialID != "" {
+		credentialID = &req.CredentialID
+	}
+
 	// Start a transaction
 	tx, err := database.DB.Begin(ctx)
 	if err != nil {
@@ -59,36 +78,26 @@ func (r *ProvisioningPermissionRepository) SetUserPermissions(ctx context.Contex
 	}
 	defer tx.Rollback(ctx)
 
-	// Delete existing permissions for this user
-	deleteQuery := `DELETE FROM user_provisioning_permissions WHERE user_id = $1`
-	_, err = tx.Exec(ctx, deleteQuery, userID)
+	// Delete existing permissions for this user in the same scope
+	deleteQuery := `
+		DELETE FROM user_provisioning_permissions
+		WHERE user_id = $1
+		  AND project_id IS NOT DISTINCT FROM $2::uuid
+		  AND credential_id IS NOT DISTINCT FROM $3::uuid
+	`
+	_, err = tx.Exec(ctx, deleteQuery, userID, projectID, credentialID)
 	if err != nil {
 		return err
 	}
 
 	// Insert new permissions
 	insertQuery := `
-		INSERT INTO user_provisioning_permissions (user_id, resource_type, granted_by)
-		VALUES ($1, $2, $3)
+		INSERT INTO user_provisioning_permissions (user_id, resource_type, project_id, credential_id, granted_by)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	if req.S3Enabled {
-		_, err = tx.Exec(ctx, insertQuery, userID, "s3", grantedBy)
-		if err != nil {
-			return err
-		}
-	}
-
-	if req.SQSEnabled {
-		_, err = tx.Exec(ctx, insertQuery, userID, "sqs", grantedBy)
-		if err != nil {
-			return err
-		}
-	}
-
-	if req.SNSEnabled {
-		_, err = tx.Exec(ctx, insertQuery, userID, "sns", grantedBy)
-		if err != nil {
+	for _, resourceType := range req.Types() {
+		if _, err := tx.Exec(ctx, insertQuery, userID, resourceType, projectID, credentialID, grantedBy); err != nil {
 			return err
 		}
 	}
@@ -97,16 +106,28 @@ func (r *ProvisioningPermissionRepository) SetUserPermissions(ctx context.Contex
 }
 
 // CanUserProvision checks if a user can provision a specific resource type
-func (r *ProvisioningPermissionRepository) CanUserProvision(ctx context.Context, userID string, resourceType string) (bool, error) {
+// for the given project with the given credential. A permission without a
+// project or credential applies to any project or credential respectively.
+func (r *ProvisioningPermissionRepository) CanUserProvision(ctx context.Context, userID string, resourceType string, projectID string, credentialID string) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM user_provisioning_permissions
 			WHERE user_id = $1 AND resource_type = $2
+			  AND (project_id IS NULL OR project_id = $3::uuid)
+			  AND (credential_id IS NULL OR credential_id = $4::uuid)
 		)
 	`
 
+	var projectParam, credentialParam *string
+	if projectID != "" {
+		projectParam = &projectID
+	}
+	if credentialID != "" {
+		credentialParam = &credentialID
+	}
+
 	var exists bool
-	err := database.DB.QueryRow(ctx, query, userID, resourceType).Scan(&exists)
+	err := database.DB.QueryRow(ctx, query, userID, resourceType, projectParam, credentialParam).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
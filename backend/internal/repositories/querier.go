@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/portalight/backend/internal/database"
+)
+
+// Querier runs statements for a repository. Both the connection pool and a pgx.Tx
+// satisfy it, so several repositories can share one transaction; Begin on a
+// transaction opens a savepoint.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// querier is embedded by repositories that can run inside a caller's transaction.
+// The zero value uses the connection pool.
+type querier struct {
+	q Querier
+}
+
+// db returns the Querier the repository's statements run against
+func (r querier) db() Querier {
+	if r.q != nil {
+		return r.q
+	}
+	return database.DB
+}
+
+// BeginTx starts a transaction on the connection pool for use with WithQuerier
+func BeginTx(ctx context.Context) (pgx.Tx, error) {
+	return database.DB.Begin(ctx)
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// ResourceReconciliationRepository compares provisioned resources with the
+// discovered_resources rows that track them
+type ResourceReconciliationRepository struct {
+	resources *ResourceRepository // Moves provisioned resources between statuses
+}
+
+// NewResourceReconciliationRepository creates a new reconciliation repository
+func NewResourceReconciliationRepository() *ResourceReconciliationRepository {
+	return &ResourceReconciliationRepository{resources: NewResourceRepository(database.DB)}
+}
+
+// LinkByARN links unlinked discovered resources to the provisioned resource of the
+// same project and ARN and returns how many were linked
+func (r *ResourceReconciliationRepository) LinkByARN(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE discovered_resources d
+		SET resource_id = res.id, updated_at = NOW()
+		FROM resources res
+		WHERE d.resource_id IS NULL
+		  AND res.project_id = d.project_id
+		  AND res.arn = d.arn
+		  AND NOT EXISTS (SELECT 1 FROM discovered_resources x WHERE x.resource_id = res.id)
+	`
+	result, err := database.DB.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link discovered resources: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// FindDivergent lists the provisioned resources whose discovered resource is missing
+// or disagrees with them. Discovered resources in status unknown are mid-sync and
+// not reported.
+func (r *ResourceReconciliationRepository) FindDivergent(ctx context.Context) ([]models.ResourceDivergence, error) {
+	query := `
+		SELECT res.id, res.project_id, res.name, res.type, res.status, COALESCE(res.arn, ''),
+		       COALESCE(d.id::text, ''), COALESCE(d.status, ''),
+		       EXISTS (
+		           SELECT 1 FROM discovered_resources x
+		           WHERE x.project_id = res.project_id AND x.arn = res.arn AND x.resource_id IS NULL
+		       )
+		FROM resources res
+		LEFT JOIN discovered_resources d ON d.resource_id = res.id
+		WHERE (d.id IS NULL AND res.status = 'active' AND COALESCE(res.arn, '') <> '')
+		   OR (d.status = 'deleted' AND res.status = 'active')
+		   OR (d.status = 'active' AND res.status <> 'active')
+		ORDER BY res.project_id, res.name
+	`
+
+	rows, err := database.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find divergent resources: %w", err)
+	}
+	defer rows.Close()
+
+	divergences := []models.ResourceDivergence{}
+	for rows.Next() {
+		var d models.ResourceDivergence
+		var linkable bool
+		err := rows.Scan(&d.ResourceID, &d.ProjectID, &d.Name, &d.Type, &d.ResourceStatus, &d.ARN,
+			&d.DiscoveredResourceID, &d.DiscoveredStatus, &linkable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan divergent resource: %w", err)
+		}
+
+		switch {
+		case d.DiscoveredResourceID == "":
+			d.Kind = models.DivergenceUnlinked
+			if linkable {
+				d.Repair = models.RepairLink
+			}
+		case d.ResourceStatus == models.ProvisionStatusActive:
+			d.Kind = models.DivergenceStatus
+			d.Repair = models.RepairDeleteResource
+		default:
+			d.Kind = models.DivergenceStatus
+			d.Repair = models.RepairUnlink
+		}
+		divergences = append(divergences, d)
+	}
+	return divergences, rows.Err()
+}
+
+// Repair applies the repair action of a divergence. Each action only applies while
+// the rows are still in the state the divergence was found in.
+func (r *ResourceReconciliationRepository) Repair(ctx context.Context, d models.ResourceDivergence) error {
+	var query string
+	var args []interface{}
+	switch d.Repair {
+	case models.RepairLink:
+		query = `
+			UPDATE discovered_resources
+			SET resource_id = $1, updated_at = NOW()
+			WHERE project_id = $2 AND arn = $3 AND resource_id IS NULL
+		`
+		args = []interface{}{d.ResourceID, d.ProjectID, d.ARN}
+	case models.RepairDeleteResource:
+		err := r.resources.TransitionStatus(ctx, d.ResourceID, models.ProvisionStatusActive, models.ProvisionStatusDeleted, StatusDetails{})
+		var conflict *StatusConflictError
+		if errors.As(err, &conflict) {
+			return fmt.Errorf("resource %s changed since it was checked", d.ResourceID)
+		}
+		return err
+	case models.RepairUnlink:
+		query = `
+			UPDATE discovered_resources
+			SET resource_id = NULL, updated_at = NOW()
+			WHERE id = $1 AND resource_id = $2
+		`
+		args = []interface{}{d.DiscoveredResourceID, d.ResourceID}
+	default:
+		return fmt.Errorf("resource %s has no repair action", d.ResourceID)
+	}
+
+	result, err := database.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to repair resource %s: %w", d.ResourceID, err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("resource %s changed since it was checked", d.ResourceID)
+	}
+	return nil
+}
@@ -2,25 +2,49 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
 )
 
+// ErrInvalidStatusTransition is returned for transitions the status model forbids
+var ErrInvalidStatusTransition = errors.New("invalid status transition")
+
+// StatusConflictError is returned when a resource was no longer in the expected status,
+// usually because another writer changed it first
+type StatusConflictError struct {
+	ResourceID string
+	Expected   string
+	Actual     string
+	Target     string
+}
+
+func (e *StatusConflictError) Error() string {
+	return fmt.Sprintf("resource %s is %s, expected %s before moving to %s", e.ResourceID, e.Actual, e.Expected, e.Target)
+}
+
 type ResourceRepository struct {
-	db *pgxpool.Pool
+	db Querier
 }
 
-func NewResourceRepository(db *pgxpool.Pool) *ResourceRepository {
+func NewResourceRepository(db *database.Pool) *ResourceRepository {
 	return &ResourceRepository{db: db}
 }
 
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ResourceRepository) WithQuerier(q Querier) *ResourceRepository {
+	return &ResourceRepository{db: q}
+}
+
 func (r *ResourceRepository) Create(ctx context.Context, resource *models.Resource) error {
 	query := `
-		INSERT INTO resources (project_id, name, type, status, config, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO resources (project_id, name, type, status, config, reason, ticket_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id
 	`
 	resource.CreatedAt = time.Now()
@@ -32,6 +56,8 @@ func (r *ResourceRepository) Create(ctx context.Context, resource *models.Resour
 		resource.Type,
 		resource.Status,
 		resource.Config,
+		resource.Reason,
+		resource.TicketURL,
 		resource.CreatedAt,
 		resource.UpdatedAt,
 	).Scan(&resource.ID)
@@ -43,8 +69,26 @@ func (r *ResourceRepository) Create(ctx context.Context, resource *models.Resour
 }
 
 func (r *ResourceRepository) FindByProjectID(ctx context.Context, projectID string) ([]models.Resource, error) {
+	resources := []models.Resource{}
+	err := r.forEachByProjectID(ctx, projectID, func(res models.Resource) error {
+		resources = append(resources, res)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// ForEachByProjectID streams a project's resources to fn without loading them all
+// into memory. Reading runs under the stream timeout.
+func (r *ResourceRepository) ForEachByProjectID(ctx context.Context, projectID string, fn func(models.Resource) error) error {
+	return r.forEachByProjectID(database.WithQueryTimeout(ctx, database.StreamQueryTimeout), projectID, fn)
+}
+
+func (r *ResourceRepository) forEachByProjectID(ctx context.Context, projectID string, fn func(models.Resource) error) error {
 	query := `
-		SELECT id, project_id, name, type, status, config, arn, error_message, created_at, updated_at
+		SELECT id, project_id, name, type, status, stage, config, arn, error_message, reason, ticket_url, managed_by, created_at, updated_at
 		FROM resources
 		WHERE project_id = $1
 		ORDER BY created_at DESC
@@ -52,28 +96,182 @@ func (r *ResourceRepository) FindByProjectID(ctx context.Context, projectID stri
 
 	rows, err := r.db.Query(ctx, query, projectID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query resources: %w", err)
+		return fmt.Errorf("failed to query resources: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var res models.Resource
+		var stage, arn, errorMsg *string
+		err := rows.Scan(
+			&res.ID,
+			&res.ProjectID,
+			&res.Name,
+			&res.Type,
+			&res.Status,
+			&stage,
+			&res.Config,
+			&arn,
+			&errorMsg,
+			&res.Reason,
+			&res.TicketURL,
+			&res.ManagedBy,
+			&res.CreatedAt,
+			&res.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan resource: %w", err)
+		}
+		if stage != nil {
+			res.Stage = *stage
+		}
+		if arn != nil {
+			res.ARN = *arn
+		}
+		if errorMsg != nil {
+			res.ErrorMsg = *errorMsg
+		}
+		if err := fn(res); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindByID returns a single resource; pgx.ErrNoRows is returned (wrapped) if it does not exist
+func (r *ResourceRepository) FindByID(ctx context.Context, id string) (*models.Resource, error) {
+	query := `
+		SELECT id, project_id, name, type, status, stage, config, arn, error_message, reason, ticket_url, managed_by, created_at, updated_at
+		FROM resources
+		WHERE id = $1
+	`
+
+	var res models.Resource
+	var stage, arn, errorMsg *string
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&res.ID,
+		&res.ProjectID,
+		&res.Name,
+		&res.Type,
+		&res.Status,
+		&stage,
+		&res.Config,
+		&arn,
+		&errorMsg,
+		&res.Reason,
+		&res.TicketURL,
+		&res.ManagedBy,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource: %w", err)
+	}
+	if stage != nil {
+		res.Stage = *stage
+	}
+	if arn != nil {
+		res.ARN = *arn
+	}
+	if errorMsg != nil {
+		res.ErrorMsg = *errorMsg
+	}
+
+	return &res, nil
+}
+
+// ResourceFilter narrows down cross-project resource queries; zero values are ignored
+type ResourceFilter struct {
+	Status models.ProvisionStatus
+	Type   string
+	// AccessibleBy limits results to projects the user can access (CanUserAccess rules)
+	AccessibleBy string
+	// OwnedBy limits results to projects owned by one of the user's teams
+	OwnedBy string
+}
+
+// where builds the WHERE clause for the filter and its positional args
+func (f ResourceFilter) where(includeStatus bool) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if includeStatus && f.Status != "" {
+		args = append(args, f.Status)
+		conditions = append(conditions, fmt.Sprintf("r.status = $%d", len(args)))
+	}
+	if f.Type != "" {
+		args = append(args, f.Type)
+		conditions = append(conditions, fmt.Sprintf("r.type = $%d", len(args)))
+	}
+	if f.AccessibleBy != "" {
+		args = append(args, f.AccessibleBy)
+		conditions = append(conditions, projectAccessCondition("r.project_id", fmt.Sprintf("$%d", len(args))))
+	}
+	if f.OwnedBy != "" {
+		args = append(args, f.OwnedBy)
+		conditions = append(conditions, fmt.Sprintf("p.owner_team_id IN (SELECT team_id FROM team_members WHERE user_id = $%d::uuid)", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// resourceSortFields are the columns resources can be listed by
+var resourceSortFields = []string{"created_at", "updated_at", "name", "type", "status", "project_name"}
+
+// GetAll retrieves one page of resources across projects, with their project's
+// name, and the total number of matches
+func (r *ResourceRepository) GetAll(ctx context.Context, filter ResourceFilter, opts ListOptions) ([]models.Resource, int, error) {
+	where, args := filter.where(true)
+	baseQuery := `
+		SELECT r.id, r.project_id, p.name AS project_name, r.name, r.type, r.status, r.stage, r.config,
+		       r.arn, r.error_message, r.reason, r.ticket_url, r.managed_by, r.created_at, r.updated_at
+		FROM resources r
+		JOIN projects p ON p.id = r.project_id
+	` + where
+
+	query, pageArgs, err := ApplyPagination(baseQuery, opts.withDefaultSort("created_at", "desc"), resourceSortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list resources: %w", err)
 	}
 	defer rows.Close()
 
 	resources := []models.Resource{}
+	total := 0
 	for rows.Next() {
 		var res models.Resource
-		var arn, errorMsg *string
+		var stage, arn, errorMsg *string
 		err := rows.Scan(
 			&res.ID,
 			&res.ProjectID,
+			&res.ProjectName,
 			&res.Name,
 			&res.Type,
 			&res.Status,
+			&stage,
 			&res.Config,
 			&arn,
 			&errorMsg,
+			&res.Reason,
+			&res.TicketURL,
+			&res.ManagedBy,
 			&res.CreatedAt,
 			&res.UpdatedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan resource: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan resource: %w", err)
+		}
+		if stage != nil {
+			res.Stage = *stage
 		}
 		if arn != nil {
 			res.ARN = *arn
@@ -83,45 +281,127 @@ func (r *ResourceRepository) FindByProjectID(ctx context.Context, projectID stri
 		}
 		resources = append(resources, res)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(resources) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery, args...)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
 
-	return resources, nil
+	return resources, total, nil
 }
 
-func (r *ResourceRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+// CountByStatus counts the resources matching the filter per status. The filter's
+// status is ignored, so every status is counted.
+func (r *ResourceRepository) CountByStatus(ctx context.Context, filter ResourceFilter) (map[models.ProvisionStatus]int, error) {
+	where, args := filter.where(false)
 	query := `
-		UPDATE resources
-		SET status = $1, updated_at = $2
-		WHERE id = $3
+		SELECT r.status, COUNT(*)
+		FROM resources r
+		JOIN projects p ON p.id = r.project_id
+	` + where + `
+		GROUP BY r.status
 	`
-	_, err := r.db.Exec(ctx, query, status, time.Now(), id)
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update resource status: %w", err)
+		return nil, fmt.Errorf("failed to count resources: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	counts := map[models.ProvisionStatus]int{}
+	for rows.Next() {
+		var status models.ProvisionStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan resource count: %w", err)
+		}
+		counts[status] = count
+	}
+	return counts, rows.Err()
+}
+
+// CountActiveByProject counts the provisioned resources of a project that were not
+// deleted or failed; an empty resourceType counts all types
+func (r *ResourceRepository) CountActiveByProject(ctx context.Context, projectID string, resourceType string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM resources
+		WHERE project_id = $1
+		  AND status NOT IN ('deleted', 'failed')
+		  AND ($2 = '' OR type = $2)
+	`
+	var count int
+	if err := r.db.QueryRow(ctx, query, projectID, resourceType).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count resources: %w", err)
+	}
+	return count, nil
 }
 
-func (r *ResourceRepository) UpdateStatusWithError(ctx context.Context, id string, status string, errorMsg string) error {
+// UpdateStage records the current provisioning stage (e.g. "queued (3 ahead)")
+func (r *ResourceRepository) UpdateStage(ctx context.Context, id string, stage string) error {
 	query := `
 		UPDATE resources
-		SET status = $1, error_message = $2, updated_at = $3
-		WHERE id = $4
+		SET stage = $1, updated_at = $2
+		WHERE id = $3
 	`
-	_, err := r.db.Exec(ctx, query, status, errorMsg, time.Now(), id)
+	_, err := r.db.Exec(ctx, query, stage, time.Now(), id)
 	if err != nil {
-		return fmt.Errorf("failed to update resource status: %w", err)
+		return fmt.Errorf("failed to update resource stage: %w", err)
 	}
 	return nil
 }
 
-func (r *ResourceRepository) UpdateStatusWithARN(ctx context.Context, id string, status string, arn string) error {
+// StatusDetails are written together with a status transition
+type StatusDetails struct {
+	ErrorMessage string // Replaces the stored error message (empty clears it)
+	ARN          string // Set when non-empty, kept otherwise
+}
+
+// TransitionStatus moves a resource from one status to another. The update only applies
+// while the resource is still in status from, so concurrent writers cannot overwrite each
+// other; a lost race returns a *StatusConflictError. Transitions not allowed by the
+// status model return ErrInvalidStatusTransition without touching the database.
+// The linked discovered resource follows moves to active and deleted.
+func (r *ResourceRepository) TransitionStatus(ctx context.Context, id string, from, to models.ProvisionStatus, details StatusDetails) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	// Active and deleted carry over to the linked discovered resource; the other
+	// statuses only exist before it is created
 	query := `
-		UPDATE resources
-		SET status = $1, arn = $2, updated_at = $3
-		WHERE id = $4
+		WITH updated AS (
+			UPDATE resources
+			SET status = $1, error_message = $2, arn = COALESCE(NULLIF($3, ''), arn), updated_at = $4
+			WHERE id = $5 AND status = $6
+			RETURNING id, status
+		), linked AS (
+			UPDATE discovered_resources d
+			SET status = u.status, updated_at = $4
+			FROM updated u
+			WHERE d.resource_id = u.id AND u.status IN ('active', 'deleted') AND d.status <> u.status
+		)
+		SELECT COUNT(*) FROM updated
 	`
-	_, err := r.db.Exec(ctx, query, status, arn, time.Now(), id)
+	var updated int
+	err := r.db.QueryRow(ctx, query, to, details.ErrorMessage, details.ARN, time.Now(), id, from).Scan(&updated)
 	if err != nil {
 		return fmt.Errorf("failed to update resource status: %w", err)
 	}
-	return nil
+	if updated > 0 {
+		return nil
+	}
+
+	var actual string
+	if err := r.db.QueryRow(ctx, "SELECT status FROM resources WHERE id = $1", id).Scan(&actual); err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("resource %s: %w", id, err)
+		}
+		return fmt.Errorf("failed to read resource status: %w", err)
+	}
+	return &StatusConflictError{ResourceID: id, Expected: string(from), Actual: actual, Target: string(to)}
 }
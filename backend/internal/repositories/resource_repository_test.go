@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+func TestTransitionStatusRejectsIllegalTransitions(t *testing.T) {
+	// Checked before the database is touched
+	repo := NewResourceRepository(nil)
+	tests := []struct{ from, to models.ProvisionStatus }{
+		{models.ProvisionStatusFailed, models.ProvisionStatusActive},
+		{models.ProvisionStatusDeleted, models.ProvisionStatusActive},
+		{models.ProvisionStatusActive, models.ProvisionStatusProvisioning},
+		{models.ProvisionStatusAwaitingApproval, models.ProvisionStatusActive},
+	}
+	for _, tt := range tests {
+		err := repo.TransitionStatus(context.Background(), "id", tt.from, tt.to, StatusDetails{})
+		if !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("%s -> %s: got %v, want ErrInvalidStatusTransition", tt.from, tt.to, err)
+		}
+	}
+
+	discovered := NewDiscoveredResourceRepository()
+	err := discovered.TransitionStatus(context.Background(), "id", models.ResourceStatusActive, models.ResourceStatusActive)
+	if !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("active -> active: got %v, want ErrInvalidStatusTransition", err)
+	}
+}
+
+// raceTransitions runs every transition concurrently and returns the targets of the
+// ones that succeeded; the others must fail with a *StatusConflictError
+func raceTransitions[S ~string](t *testing.T, targets []S, transition func(to S) error) []S {
+	t.Helper()
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, to := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = transition(to)
+		}()
+	}
+	wg.Wait()
+
+	var won []S
+	for i, err := range errs {
+		var conflict *StatusConflictError
+		switch {
+		case err == nil:
+			won = append(won, targets[i])
+		case errors.As(err, &conflict):
+		default:
+			t.Fatalf("transition to %s failed: %v", targets[i], err)
+		}
+	}
+	return won
+}
+
+func TestConcurrentResourceTransitionsHaveOneWinner(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	repo := NewResourceRepository(database.DB)
+
+	resource := &models.Resource{
+		Name:   fmt.Sprintf("transition-%d", time.Now().UnixNano()),
+		Type:   "s3",
+		Status: models.ProvisionStatusProvisioning,
+		Config: json.RawMessage("{}"),
+	}
+	if err := repo.Create(ctx, resource); err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM resources WHERE id = $1::uuid", resource.ID)
+	})
+
+	// The provisioner finishing and the janitor failing the same resource race
+	var targets []models.ProvisionStatus
+	for i := 0; i < 10; i++ {
+		targets = append(targets, models.ProvisionStatusActive, models.ProvisionStatusFailed)
+	}
+	won := raceTransitions(t, targets, func(to models.ProvisionStatus) error {
+		return repo.TransitionStatus(ctx, resource.ID, models.ProvisionStatusProvisioning, to, StatusDetails{})
+	})
+	if len(won) != 1 {
+		t.Fatalf("%d transitions succeeded, want exactly 1", len(won))
+	}
+
+	stored, err := repo.FindByID(ctx, resource.ID)
+	if err != nil {
+		t.Fatalf("failed to read resource: %v", err)
+	}
+	if stored.Status != won[0] {
+		t.Errorf("status is %s, want the winner's %s", stored.Status, won[0])
+	}
+
+	// The loser's view is stale now, so its follow-up transition conflicts too
+	var conflict *StatusConflictError
+	err = repo.TransitionStatus(ctx, resource.ID, models.ProvisionStatusProvisioning, models.ProvisionStatusFailed, StatusDetails{})
+	if !errors.As(err, &conflict) || conflict.Actual != string(won[0]) {
+		t.Errorf("stale transition: got %v, want a conflict reporting %s", err, won[0])
+	}
+}
+
+func TestConcurrentDiscoveredTransitionsHaveOneWinner(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	repo := NewDiscoveredResourceRepository()
+
+	var id string
+	err := database.DB.QueryRow(ctx, `
+		INSERT INTO discovered_resources (arn, resource_type, name, region, status)
+		VALUES ($1, 's3', 'transition-test', 'eu-west-1', 'active')
+		RETURNING id::text
+	`, fmt.Sprintf("arn:aws:s3:::transition-%d", time.Now().UnixNano())).Scan(&id)
+	if err != nil {
+		t.Fatalf("failed to create discovered resource: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM discovered_resources WHERE id = $1::uuid", id)
+	})
+
+	var targets []models.DiscoveredResourceStatus
+	for i := 0; i < 10; i++ {
+		targets = append(targets, models.ResourceStatusDeleted, models.ResourceStatusUnknown)
+	}
+	won := raceTransitions(t, targets, func(to models.DiscoveredResourceStatus) error {
+		return repo.TransitionStatus(ctx, id, models.ResourceStatusActive, to)
+	})
+	if len(won) != 1 {
+		t.Fatalf("%d transitions succeeded, want exactly 1", len(won))
+	}
+
+	var status string
+	if err := database.DB.QueryRow(ctx, "SELECT status FROM discovered_resources WHERE id = $1", id).Scan(&status); err != nil {
+		t.Fatalf("failed to read status: %v", err)
+	}
+	if status != string(won[0]) {
+		t.Errorf("status is %s, want the winner's %s", status, won[0])
+	}
+}
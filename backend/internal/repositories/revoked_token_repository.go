@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+)
+
+// RevokedTokenRepository handles the JWT revocation list
+type RevokedTokenRepository struct{}
+
+// isRevokedQuery is a constant so pgx prepares it once per connection and reuses
+// the statement on every authenticated request
+const isRevokedQuery = `SELECT 1 FROM revoked_tokens WHERE jti = $1 AND expires_at > NOW()`
+
+// IsRevoked checks whether a token ID has been revoked and has not yet expired
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var one int
+	err := database.DB.QueryRow(ctx, isRevokedQuery, jti).Scan(&one)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return true, nil
+}
+
+// Revoke adds a token ID to the revocation list until it expires
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, jti string, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, revoked_at, expires_at)
+		VALUES ($1, $2, NOW(), $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	var userIDParam *string
+	if userID != "" {
+		userIDParam = &userID
+	}
+
+	if _, err := database.DB.Exec(ctx, query, jti, userIDParam, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes revocation entries for tokens that have expired anyway
+func (r *RevokedTokenRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := database.DB.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge revoked tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// SearchRepository runs a global search across projects, services and discovered resources
+type SearchRepository struct {
+	projectRepo  *ProjectRepository
+	serviceRepo  *ServiceRepository
+	resourceRepo *DiscoveredResourceRepository
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{
+		projectRepo:  &ProjectRepository{},
+		serviceRepo:  &ServiceRepository{},
+		resourceRepo: NewDiscoveredResourceRepository(),
+	}
+}
+
+// searchDocument must match the expression of the GIN indexes in 015_add_search_indexes.sql
+const searchDocument = `to_tsvector('english', name || ' ' || COALESCE(description, ''))`
+
+// Search returns up to limit hits per type, best matches first, and flags the types
+// that had more. An empty types slice searches all types. A non-empty userID restricts
+// results to projects the user can access; each entity query applies that filter
+// itself, so hits the user cannot see never count against the limit.
+func (r *SearchRepository) Search(ctx context.Context, query string, types []string, limit int, userID string) (*models.SearchResponse, error) {
+	if len(types) == 0 {
+		types = []string{models.SearchTypeProject, models.SearchTypeService, models.SearchTypeResource}
+	}
+
+	response := &models.SearchResponse{
+		Results:   []models.SearchResult{},
+		Truncated: make(map[string]bool, len(types)),
+	}
+	for _, t := range types {
+		// One extra row tells whether the type was truncated
+		var hits []models.SearchResult
+		var err error
+		switch t {
+		case models.SearchTypeProject:
+			hits, err = r.projectRepo.Search(ctx, query, limit+1, userID)
+		case models.SearchTypeService:
+			hits, err = r.serviceRepo.Search(ctx, query, limit+1, userID)
+		case models.SearchTypeResource:
+			hits, err = r.resourceRepo.Search(ctx, query, limit+1, userID)
+		default:
+			return nil, fmt.Errorf("unsupported search type: %s", t)
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Truncated[t] = len(hits) > limit
+		if len(hits) > limit {
+			hits = hits[:limit]
+		}
+		response.Results = append(response.Results, hits...)
+	}
+
+	sort.SliceStable(response.Results, func(i, j int) bool {
+		return response.Results[i].Rank > response.Results[j].Rank
+	})
+	return response, nil
+}
+
+// searchNameRank scores a name column: exact match, then prefix, then trigram similarity.
+// $1 is the raw query and $2 its escaped ILIKE prefix pattern.
+func searchNameRank(column string) string {
+	return fmt.Sprintf(`(CASE WHEN lower(%[1]s) = lower($1) THEN 3 WHEN %[1]s ILIKE $2 THEN 2 ELSE 0 END + similarity(%[1]s, $1))`, column)
+}
+
+// projectAccessCondition restricts rows to projects the user (the userParam
+// placeholder, e.g. "$4") owns through a team or was granted via project_access.
+// A NULL user (superadmin) matches everything.
+func projectAccessCondition(projectIDColumn, userParam string) string {
+	return fmt.Sprintf(`(
+		%[2]s::uuid IS NULL
+		OR %[1]s IN (
+			SELECT p.id FROM projects p
+			WHERE p.owner_team_id IN (SELECT team_id FROM team_members WHERE user_id = %[2]s::uuid)
+		)
+		OR %[1]s IN (
+			SELECT pa.project_id FROM project_access pa
+			WHERE pa.user_id = %[2]s::uuid
+				OR pa.team_id IN (SELECT team_id FROM team_members WHERE user_id = %[2]s::uuid)
+		)
+	)`, projectIDColumn, userParam)
+}
+
+// searchArgs builds the shared query arguments:
+// $1 query, $2 prefix pattern, $3 contains pattern, $4 user ID (NULL for unrestricted), $5 limit
+func searchArgs(query string, limit int, userID string) []interface{} {
+	escaped := escapeLike(query)
+	var user *string
+	if userID != "" {
+		user = &userID
+	}
+	return []interface{}{query, escaped + "%", "%" + escaped + "%", user, limit}
+}
+
+// escapeLike escapes ILIKE wildcards so the query is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// searchSnippet returns up to ~80 characters of text around the first match of query
+func searchSnippet(text, query string) string {
+	const context = 30
+
+	runes := []rune(text)
+	q := []rune(strings.ToLower(query))
+	index := strings.Index(strings.ToLower(text), string(q))
+	if index >= 0 {
+		index = len([]rune(strings.ToLower(text)[:index]))
+	}
+	if index < 0 || len(runes) != len([]rune(strings.ToLower(text))) {
+		// No literal match (e.g. a stemmed full-text hit): show the beginning
+		if len(runes) > 2*context+len(q) {
+			return string(runes[:2*context+len(q)]) + "…"
+		}
+		return text
+	}
+
+	start := max(index-context, 0)
+	end := min(index+len(q)+context, len(runes))
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// matchedField picks the first candidate field containing query (falls back to the first field)
+func matchedField(query string, fields ...[2]string) (string, string) {
+	lower := strings.ToLower(query)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field[1]), lower) {
+			return field[0], searchSnippet(field[1], query)
+		}
+	}
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return fields[0][0], searchSnippet(fields[0][1], query)
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// createTestProject creates a project owned by a new team and a resource in it with
+// the given ARN; everything is removed when the test ends
+func createTestProject(t *testing.T, name, arn string) (teamID, projectID string) {
+	t.Helper()
+	ctx := context.Background()
+
+	team := &models.Team{Name: fmt.Sprintf("%s-team-%d", name, time.Now().UnixNano())}
+	if err := (&TeamRepository{}).Create(ctx, team); err != nil {
+		t.Fatalf("failed to create team: %v", err)
+	}
+	project := &models.Project{Name: fmt.Sprintf("%s-%d", name, time.Now().UnixNano()), OwnerTeamID: team.ID}
+	if err := (&ProjectRepository{}).Create(ctx, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	_, err := database.DB.Exec(ctx, `
+		INSERT INTO discovered_resources (project_id, arn, resource_type, name, region)
+		VALUES ($1::uuid, $2, 's3', $3, 'eu-west-1')
+	`, project.ID, arn, name+"-bucket")
+	if err != nil {
+		t.Fatalf("failed to create discovered resource: %v", err)
+	}
+
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM projects WHERE id = $1::uuid", project.ID)
+		database.DB.Exec(context.Background(), "DELETE FROM teams WHERE id = $1::uuid", team.ID)
+	})
+	return team.ID, project.ID
+}
+
+func TestSearchByARNFragmentIsScopedToAccessibleProjects(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+
+	// Both ARNs share the fragment the dev searches for
+	fragment := fmt.Sprintf("scope%d", time.Now().UnixNano())
+	ownTeamID, ownProjectID := createTestProject(t, "own", "arn:aws:s3:::own-"+fragment)
+	_, foreignProjectID := createTestProject(t, "foreign", "arn:aws:s3:::foreign-"+fragment)
+
+	dev := &models.User{
+		Name:  "Search Dev",
+		Email: fmt.Sprintf("search-dev-%d@test.invalid", time.Now().UnixNano()),
+		Role:  models.RoleDev,
+	}
+	if err := (&UserRepository{}).Create(ctx, dev); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM users WHERE id = $1::uuid", dev.ID)
+	})
+	if _, err := (&TeamRepository{}).UpdateTeamMembers(ctx, ownTeamID, []string{dev.ID}); err != nil {
+		t.Fatalf("failed to add team member: %v", err)
+	}
+
+	repo := NewSearchRepository()
+	resourceProjects := func(userID string) map[string]bool {
+		response, err := repo.Search(ctx, fragment, []string{models.SearchTypeResource}, 10, userID)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		projects := make(map[string]bool)
+		for _, result := range response.Results {
+			projects[result.ProjectID] = true
+		}
+		return projects
+	}
+
+	devResults := resourceProjects(dev.ID)
+	if !devResults[ownProjectID] {
+		t.Error("dev did not find the resource of their own project")
+	}
+	if devResults[foreignProjectID] {
+		t.Error("dev found a resource of a project they cannot access")
+	}
+
+	// An unrestricted (superadmin) search sees both
+	if all := resourceProjects(""); !all[ownProjectID] || !all[foreignProjectID] {
+		t.Errorf("unrestricted search found projects %v, want both", all)
+	}
+}
@@ -3,9 +3,12 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/portalight/backend/internal/crypto"
 	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
@@ -22,10 +25,10 @@ func (r *SecretRepository) Create(ctx context.Context, secret *models.Secret, cr
 		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
 
-	// Encrypt credentials
-	encrypted, err := crypto.Encrypt(string(credJSON))
+	// Encrypt credentials, or hand them to the external credential backend
+	encrypted, err := sealCredentials(ctx, secret.Name, credJSON)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt credentials: %w", err)
+		return err
 	}
 
 	query := `
@@ -151,50 +154,232 @@ func (r *SecretRepository) FindByID(ctx context.Context, id string) (*models.Sec
 	return &secret, nil
 }
 
-// GetCredentials retrieves and decrypts credentials for a secret
-func (r *SecretRepository) GetCredentials(ctx context.Context, secretID string) (*models.AWSCredentials, error) {
+// GetCredentials retrieves and decrypts credentials for a secret, along with its
+// access type so callers can refuse read-only credentials for write operations
+func (r *SecretRepository) GetCredentials(ctx context.Context, secretID string) (*models.AWSCredentials, models.AccessType, error) {
 	query := `
-		SELECT credentials_encrypted
+		SELECT credentials_encrypted, access_type
 		FROM secrets
 		WHERE id = $1
 	`
 
 	var encrypted string
-	err := database.DB.QueryRow(ctx, query, secretID).Scan(&encrypted)
+	var accessType *string
+	err := database.DB.QueryRow(ctx, query, secretID).Scan(&encrypted, &accessType)
 	if err != nil {
-		return nil, fmt.Errorf("secret not found: %w", err)
+		return nil, "", fmt.Errorf("secret not found: %w", err)
 	}
 
-	// Decrypt credentials
-	decrypted, err := crypto.Decrypt(encrypted)
+	access := models.AccessTypeWrite
+	if accessType != nil {
+		access = models.AccessType(*accessType)
+	}
+
+	var credentials *models.AWSCredentials
+	if plaintext := credentialCache.get(secretID); plaintext != nil {
+		credentials, err = unmarshalCredentials(plaintext)
+	} else {
+		credentials, err = decryptCredentials(ctx, secretID, encrypted)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+		return nil, "", err
 	}
+	return credentials, access, nil
+}
 
-	// Unmarshal credentials
-	var credentials models.AWSCredentials
-	if err := json.Unmarshal([]byte(decrypted), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+// UpdateCredentials re-encrypts the credentials for a secret (e.g. key rotation)
+func (r *SecretRepository) UpdateCredentials(ctx context.Context, secretID string, credentials *models.AWSCredentials) error {
+	credJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
 	}
+	defer zeroBytes(credJSON)
 
-	return &credentials, nil
+	// Credentials kept in Secrets Manager get a new secret version in place
+	var stored string
+	err = database.DB.QueryRow(ctx, "SELECT credentials_encrypted FROM secrets WHERE id = $1", secretID).Scan(&stored)
+	if err != nil {
+		return fmt.Errorf("secret not found: %w", err)
+	}
+	if crypto.IsReference(stored) {
+		backend, err := referenceBackend(ctx)
+		if err != nil {
+			return err
+		}
+		if updater, ok := backend.(interface {
+			Update(ctx context.Context, reference string, value string) error
+		}); ok {
+			if err := updater.Update(ctx, strings.TrimPrefix(stored, crypto.ReferencePrefix), string(credJSON)); err != nil {
+				return fmt.Errorf("failed to update credentials in Secrets Manager: %w", err)
+			}
+			credentialCache.invalidate(secretID)
+			_, err = database.DB.Exec(ctx, "UPDATE secrets SET updated_at = $1 WHERE id = $2", time.Now(), secretID)
+			return err
+		}
+	}
+
+	encrypted, err := sealCredentials(ctx, secretID, credJSON)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE secrets SET credentials_encrypted = $1, updated_at = $2 WHERE id = $3`
+	result, err := database.DB.Exec(ctx, query, encrypted, time.Now(), secretID)
+	credentialCache.invalidate(secretID)
+	if err != nil {
+		return fmt.Errorf("failed to update credentials: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("secret not found")
+	}
+
+	return nil
 }
 
-// Delete removes a secret by ID
+// deletedCredentialReason is the rejection reason of approvals whose credential was deleted
+const deletedCredentialReason = "the credential was deleted"
+
+// Delete removes a secret by ID. Projects and discovered resources that still
+// reference it keep their rows; their secret_id is set to NULL. Pending approvals
+// that would provision with it are rejected and their resources failed. Provisioning
+// permissions limited to it are deleted, since without a credential they would
+// apply to every credential. Credentials kept in Secrets Manager are deleted there too.
 func (r *SecretRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM secrets WHERE id = $1`
-	result, err := database.DB.Exec(ctx, query, id)
+	tx, err := database.DB.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete secret: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rejectQuery := `
+		WITH rejected AS (
+			UPDATE provisioning_approvals
+			SET status = 'rejected', reason = $2, updated_at = NOW()
+			WHERE secret_id = $1::uuid AND status = 'pending'
+			RETURNING resource_id
+		)
+		UPDATE resources
+		SET status = 'failed', error_message = 'Rejected: ' || $2, updated_at = NOW()
+		WHERE id IN (SELECT resource_id FROM rejected) AND status = 'awaiting_approval'
+	`
+	if _, err := tx.Exec(ctx, rejectQuery, id, deletedCredentialReason); err != nil {
+		return fmt.Errorf("failed to reject pending approvals: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE provisioning_approvals SET secret_id = NULL WHERE secret_id = $1::uuid`, id); err != nil {
+		return fmt.Errorf("failed to detach secret from approvals: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_provisioning_permissions WHERE credential_id = $1::uuid`, id); err != nil {
+		return fmt.Errorf("failed to delete provisioning permissions of secret: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
+	if _, err := tx.Exec(ctx, `UPDATE projects SET secret_id = NULL WHERE secret_id = $1::uuid`, id); err != nil {
+		return fmt.Errorf("failed to detach secret from projects: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE discovered_resources SET secret_id = NULL WHERE secret_id = $1::uuid`, id); err != nil {
+		return fmt.Errorf("failed to detach secret from discovered resources: %w", err)
+	}
+
+	var stored string
+	err = tx.QueryRow(ctx, `DELETE FROM secrets WHERE id = $1 RETURNING credentials_encrypted`, id).Scan(&stored)
+	if errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("secret not found")
 	}
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
 
+	// Remove the Secrets Manager copy before committing, so a failure keeps the
+	// credential usable; Secrets Manager can still restore it if the commit fails
+	if crypto.IsReference(stored) {
+		backend, err := referenceBackend(ctx)
+		if err != nil {
+			return err
+		}
+		if err := backend.Delete(ctx, strings.TrimPrefix(stored, crypto.ReferencePrefix)); err != nil {
+			return fmt.Errorf("failed to delete credentials from Secrets Manager: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	credentialCache.invalidate(id)
 	return nil
 }
 
+// GetUsage returns the projects, provisioned resources, discovered resources, pending
+// approvals and provisioning permissions that reference a secret. A provisioned resource counts when its
+// discovered resource was found with the secret or its project uses the secret by
+// default, which is how resource operations pick their credential.
+func (r *SecretRepository) GetUsage(ctx context.Context, id string) (*models.SecretUsage, error) {
+	usage := &models.SecretUsage{}
+
+	groups := []struct {
+		group *models.SecretUsageGroup
+		query string
+	}{
+		{&usage.Projects, `SELECT name FROM projects WHERE secret_id = $1::uuid ORDER BY name`},
+		{&usage.Resources, `
+			SELECT r.name
+			FROM resources r
+			WHERE r.status != 'deleted'
+			  AND (r.project_id IN (SELECT id FROM projects WHERE secret_id = $1::uuid)
+			       OR EXISTS (
+			           SELECT 1 FROM discovered_resources d
+			           WHERE d.project_id = r.project_id AND d.arn = r.arn AND d.secret_id = $1::uuid))
+			ORDER BY r.name
+		`},
+		{&usage.DiscoveredResources, `
+			SELECT name FROM discovered_resources
+			WHERE secret_id = $1::uuid AND status != 'deleted'
+			ORDER BY name
+		`},
+		{&usage.PendingApprovals, `
+			SELECT r.name
+			FROM provisioning_approvals a
+			JOIN resources r ON r.id = a.resource_id
+			WHERE a.secret_id = $1::uuid AND a.status = 'pending'
+			ORDER BY r.name
+		`},
+		{&usage.Permissions, `
+			SELECT COALESCE(u.email, u.name) || ': ' || p.resource_type
+			FROM user_provisioning_permissions p
+			JOIN users u ON u.id = p.user_id
+			WHERE p.credential_id = $1::uuid
+			ORDER BY 1
+		`},
+	}
+
+	for _, g := range groups {
+		names, err := r.queryNames(ctx, g.query, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret usage: %w", err)
+		}
+		*g.group = models.SecretUsageGroup{Count: len(names), Names: names}
+	}
+
+	return usage, nil
+}
+
+func (r *SecretRepository) queryNames(ctx context.Context, query, id string) ([]string, error) {
+	rows, err := database.DB.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 // GetByIDWithCredentials retrieves a secret and its decrypted credentials
 func (r *SecretRepository) GetByIDWithCredentials(ctx context.Context, id string) (*models.Secret, *models.AWSCredentials, error) {
 	query := `
@@ -238,17 +423,83 @@ func (r *SecretRepository) GetByIDWithCredentials(ctx context.Context, id string
 		secret.CreatedBy = *createdBy
 	}
 
-	// Decrypt credentials
-	decrypted, err := crypto.Decrypt(encrypted)
+	var credentials *models.AWSCredentials
+	if plaintext := credentialCache.get(id); plaintext != nil {
+		credentials, err = unmarshalCredentials(plaintext)
+	} else {
+		credentials, err = decryptCredentials(ctx, id, encrypted)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &secret, credentials, nil
+}
+
+// sealCredentials returns the value stored in credentials_encrypted: a Secrets Manager
+// reference when an external backend is configured, AES-GCM ciphertext otherwise
+func sealCredentials(ctx context.Context, name string, credJSON []byte) (string, error) {
+	if backend := crypto.ActiveCredentialBackend(); backend != nil {
+		reference, err := backend.Store(ctx, name, string(credJSON))
+		if err != nil {
+			return "", fmt.Errorf("failed to store credentials in Secrets Manager: %w", err)
+		}
+		return crypto.ReferencePrefix + reference, nil
+	}
+
+	encrypted, err := crypto.Encrypt(string(credJSON))
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+		return "", fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+	return encrypted, nil
+}
+
+// referenceBackend returns the backend resolving ssm:// references. Credentials stored
+// while Secrets Manager was configured stay readable after switching back to the database.
+func referenceBackend(ctx context.Context) (crypto.CredentialBackend, error) {
+	if backend := crypto.ActiveCredentialBackend(); backend != nil {
+		return backend, nil
+	}
+	backend, err := crypto.NewSecretsManagerBackend(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("credentials are stored in Secrets Manager: %w", err)
+	}
+	return backend, nil
+}
+
+// decryptCredentials decrypts (or fetches) stored credentials and caches the plaintext
+func decryptCredentials(ctx context.Context, secretID string, encrypted string) (*models.AWSCredentials, error) {
+	var plaintext []byte
+	if crypto.IsReference(encrypted) {
+		backend, err := referenceBackend(ctx)
+		if err != nil {
+			return nil, err
+		}
+		value, err := backend.Retrieve(ctx, strings.TrimPrefix(encrypted, crypto.ReferencePrefix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve credentials from Secrets Manager: %w", err)
+		}
+		plaintext = []byte(value)
+	} else {
+		var err error
+		plaintext, err = crypto.DecryptBytes(encrypted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt credentials: %w", err)
+		}
 	}
 
-	// Unmarshal credentials
+	credentialCache.put(secretID, plaintext)
+	return unmarshalCredentials(plaintext)
+}
+
+// unmarshalCredentials parses decrypted credentials and zeroes the plaintext buffer
+func unmarshalCredentials(plaintext []byte) (*models.AWSCredentials, error) {
+	defer zeroBytes(plaintext)
+
 	var credentials models.AWSCredentials
-	if err := json.Unmarshal([]byte(decrypted), &credentials); err != nil {
-		return nil, nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
 	}
 
-	return &secret, &credentials, nil
+	return &credentials, nil
 }
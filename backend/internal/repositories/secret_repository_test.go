@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/crypto"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+func TestDeleteSecretRejectsPendingApprovals(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	secrets := &SecretRepository{}
+	resources := NewResourceRepository(database.DB)
+	approvals := NewProvisioningApprovalRepository()
+
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIATEST", SecretAccessKey: "secret"})
+	resource := &models.Resource{
+		Name:   fmt.Sprintf("awaiting-%d", time.Now().UnixNano()),
+		Type:   "sqs",
+		Status: models.ProvisionStatusAwaitingApproval,
+		Config: json.RawMessage("{}"),
+	}
+	if err := resources.Create(ctx, resource); err != nil {
+		t.Fatalf("failed to create resource: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM resources WHERE id = $1::uuid", resource.ID)
+	})
+	approval := &models.ProvisioningApproval{ResourceID: resource.ID, SecretID: secret.ID}
+	if err := approvals.Create(ctx, approval); err != nil {
+		t.Fatalf("failed to create approval: %v", err)
+	}
+
+	usage, err := secrets.GetUsage(ctx, secret.ID)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if usage.PendingApprovals.Count != 1 || usage.PendingApprovals.Names[0] != resource.Name || !usage.InUse() {
+		t.Errorf("usage = %+v, want the pending approval of %s", usage.PendingApprovals, resource.Name)
+	}
+
+	if err := secrets.Delete(ctx, secret.ID); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+
+	// The request is rejected rather than dropped, and its resource leaves awaiting_approval
+	decided, err := approvals.FindByResourceID(ctx, resource.ID)
+	if err != nil {
+		t.Fatalf("approval was not kept: %v", err)
+	}
+	if decided.Status != models.ApprovalStatusRejected || decided.SecretID != "" || decided.Reason == "" {
+		t.Errorf("approval = %+v, want rejected with a reason and no secret", decided)
+	}
+	failed, err := resources.FindByID(ctx, resource.ID)
+	if err != nil {
+		t.Fatalf("failed to get resource: %v", err)
+	}
+	if failed.Status != models.ProvisionStatusFailed {
+		t.Errorf("resource status = %s, want %s", failed.Status, models.ProvisionStatusFailed)
+	}
+}
+
+func TestDeleteSecretRemovesScopedPermissions(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	secrets := &SecretRepository{}
+
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIATEST", SecretAccessKey: "secret"})
+	userID := secret.CreatedBy
+	grant := &models.UpdateProvisioningPermissionsRequest{AllowedTypes: []string{"s3"}, CredentialID: secret.ID}
+	if err := (&ProvisioningPermissionRepository{}).SetUserPermissions(ctx, userID, grant, userID); err != nil {
+		t.Fatalf("failed to grant permission: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM user_provisioning_permissions WHERE user_id = $1::uuid", userID)
+	})
+
+	usage, err := secrets.GetUsage(ctx, secret.ID)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if usage.Permissions.Count != 1 || !usage.InUse() {
+		t.Errorf("usage = %+v, want the scoped permission", usage.Permissions)
+	}
+
+	if err := secrets.Delete(ctx, secret.ID); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+	var remaining int
+	if err := database.DB.QueryRow(ctx, "SELECT COUNT(*) FROM user_provisioning_permissions WHERE user_id = $1::uuid", userID).Scan(&remaining); err != nil {
+		t.Fatalf("failed to count permissions: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("%d permissions remain; the scoped one should be removed, not widened to every credential", remaining)
+	}
+}
+
+// fakeVault is a credential backend holding values in memory
+type fakeVault struct {
+	values map[string]string
+}
+
+func (v *fakeVault) Store(_ context.Context, name string, value string) (string, error) {
+	reference := fmt.Sprintf("arn:aws:secretsmanager:eu-west-1:123456789012:secret:%s-%d", name, len(v.values))
+	v.values[reference] = value
+	return reference, nil
+}
+
+func (v *fakeVault) Retrieve(_ context.Context, reference string) (string, error) {
+	value, ok := v.values[reference]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", reference)
+	}
+	return value, nil
+}
+
+func (v *fakeVault) Delete(_ context.Context, reference string) error {
+	delete(v.values, reference)
+	return nil
+}
+
+func TestDeleteSecretRemovesVaultCopy(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	vault := &fakeVault{values: make(map[string]string)}
+	crypto.SetCredentialBackend(vault)
+	t.Cleanup(func() { crypto.SetCredentialBackend(nil) })
+
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIATEST", SecretAccessKey: "secret"})
+	if len(vault.values) != 1 {
+		t.Fatalf("vault holds %d secrets after create, want 1", len(vault.values))
+	}
+
+	if err := (&SecretRepository{}).Delete(ctx, secret.ID); err != nil {
+		t.Fatalf("failed to delete secret: %v", err)
+	}
+	if len(vault.values) != 0 {
+		t.Errorf("vault still holds %d secrets after the credential was deleted", len(vault.values))
+	}
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// ServiceDependencyRepository handles the service dependency graph
+type ServiceDependencyRepository struct {
+	querier
+}
+
+// NewServiceDependencyRepository creates a new ServiceDependencyRepository
+func NewServiceDependencyRepository() *ServiceDependencyRepository {
+	return &ServiceDependencyRepository{}
+}
+
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ServiceDependencyRepository) WithQuerier(q Querier) *ServiceDependencyRepository {
+	return &ServiceDependencyRepository{querier: querier{q: q}}
+}
+
+// ReplaceForService sets the outgoing edges of one type for a service,
+// removing edges to services that are no longer listed
+func (r *ServiceDependencyRepository) ReplaceForService(ctx context.Context, sourceID, dependencyType string, targetIDs []string) error {
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM service_dependencies
+		WHERE source_service_id = $1::uuid
+		  AND dependency_type = $2
+		  AND target_service_id::text != ALL($3)
+	`, sourceID, dependencyType, targetIDs); err != nil {
+		return fmt.Errorf("failed to delete stale dependencies: %w", err)
+	}
+
+	for _, targetID := range targetIDs {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO service_dependencies (source_service_id, target_service_id, dependency_type)
+			VALUES ($1::uuid, $2::uuid, $3)
+			ON CONFLICT DO NOTHING
+		`, sourceID, targetID, dependencyType); err != nil {
+			return fmt.Errorf("failed to insert dependency: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetGraph returns the upstream and downstream services of a service and the
+// dependency cycle it is on, if any
+func (r *ServiceDependencyRepository) GetGraph(ctx context.Context, serviceID string) (*models.ServiceDependencyGraph, error) {
+	upstream, err := r.ListDependencies(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	downstream, err := r.ListDependents(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	cycle, err := r.ListCycle(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ServiceDependencyGraph{
+		Upstream:   upstream,
+		Downstream: downstream,
+		InCycle:    len(cycle) > 0,
+		Cycle:      cycle,
+	}, nil
+}
+
+// ListDependencies returns the services the given service depends on
+func (r *ServiceDependencyRepository) ListDependencies(ctx context.Context, serviceID string) ([]models.ServiceDependency, error) {
+	return r.list(ctx, `
+		SELECT s.id, s.name, s.project_id, d.dependency_type, d.created_at
+		FROM service_dependencies d
+		JOIN services s ON s.id = d.target_service_id
+		WHERE d.source_service_id = $1::uuid
+		ORDER BY s.name
+	`, serviceID)
+}
+
+// ListDependents returns the services that depend on the given service
+func (r *ServiceDependencyRepository) ListDependents(ctx context.Context, serviceID string) ([]models.ServiceDependency, error) {
+	return r.list(ctx, `
+		SELECT s.id, s.name, s.project_id, d.dependency_type, d.created_at
+		FROM service_dependencies d
+		JOIN services s ON s.id = d.source_service_id
+		WHERE d.target_service_id = $1::uuid
+		ORDER BY s.name
+	`, serviceID)
+}
+
+// ListCycle returns the names of the services that lie on a dependency cycle
+// through the given service, including the service itself, or an empty list
+func (r *ServiceDependencyRepository) ListCycle(ctx context.Context, serviceID string) ([]string, error) {
+	// A service is on a cycle with this one if each can reach the other;
+	// UNION (not UNION ALL) stops the recursion once a cycle is walked
+	rows, err := r.db().Query(ctx, `
+		WITH RECURSIVE upstream(id) AS (
+			SELECT target_service_id FROM service_dependencies WHERE source_service_id = $1::uuid
+			UNION
+			SELECT d.target_service_id FROM service_dependencies d JOIN upstream u ON d.source_service_id = u.id
+		), downstream(id) AS (
+			SELECT source_service_id FROM service_dependencies WHERE target_service_id = $1::uuid
+			UNION
+			SELECT d.source_service_id FROM service_dependencies d JOIN downstream u ON d.target_service_id = u.id
+		)
+		SELECT s.name
+		FROM services s
+		WHERE s.id IN (SELECT id FROM upstream)
+		  AND s.id IN (SELECT id FROM downstream)
+		ORDER BY s.name
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependency cycle: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan service name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (r *ServiceDependencyRepository) list(ctx context.Context, query, serviceID string) ([]models.ServiceDependency, error) {
+	rows, err := r.db().Query(ctx, query, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	dependencies := []models.ServiceDependency{}
+	for rows.Next() {
+		var dep models.ServiceDependency
+		var projectID *string
+		if err := rows.Scan(&dep.ServiceID, &dep.ServiceName, &projectID, &dep.DependencyType, &dep.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan service dependency: %w", err)
+		}
+		if projectID != nil {
+			dep.ProjectID = *projectID
+		}
+		dependencies = append(dependencies, dep)
+	}
+
+	return dependencies, rows.Err()
+}
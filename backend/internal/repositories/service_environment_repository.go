@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// relinkServiceEnvironmentsSQL points every environment of service $1 at the ArgoCD
+// app linked for the same environment name. It runs whenever either side changes;
+// a deleted app is unlinked by the foreign key.
+const relinkServiceEnvironmentsSQL = `
+	UPDATE service_environments se
+	SET argocd_app_id = (
+		SELECT a.id FROM service_argocd_apps a
+		WHERE a.service_id = se.service_id AND LOWER(a.environment_name) = LOWER(se.environment)
+		LIMIT 1
+	)
+	WHERE se.service_id = $1::uuid
+`
+
+// ServiceEnvironmentRepository handles the environments of services
+type ServiceEnvironmentRepository struct {
+	querier
+}
+
+// NewServiceEnvironmentRepository creates a new service environment repository
+func NewServiceEnvironmentRepository() *ServiceEnvironmentRepository {
+	return &ServiceEnvironmentRepository{}
+}
+
+// ListByService returns every environment of a service, active or not, with its ArgoCD app
+func (r *ServiceEnvironmentRepository) ListByService(ctx context.Context, serviceID string) ([]models.ServiceEnvironment, error) {
+	rows, err := r.db().Query(ctx, `
+		SELECT se.service_id, se.environment, se.is_active, se.created_at,
+		       COALESCE(se.argocd_app_id::text, ''), COALESCE(a.argocd_app_name, '')
+		FROM service_environments se
+		LEFT JOIN service_argocd_apps a ON a.id = se.argocd_app_id
+		WHERE se.service_id = $1::uuid
+		ORDER BY se.environment
+	`, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query service environments: %w", err)
+	}
+	defer rows.Close()
+
+	environments := []models.ServiceEnvironment{}
+	for rows.Next() {
+		var env models.ServiceEnvironment
+		if err := rows.Scan(&env.ServiceID, &env.Name, &env.IsActive, &env.CreatedAt, &env.ArgoCDAppID, &env.ArgoCDAppName); err != nil {
+			return nil, fmt.Errorf("failed to scan service environment: %w", err)
+		}
+		environments = append(environments, env)
+	}
+	return environments, rows.Err()
+}
+
+// Upsert adds an environment to a service, or updates is_active of an existing one,
+// and links it to the ArgoCD app of the same name. created reports whether it was new.
+func (r *ServiceEnvironmentRepository) Upsert(ctx context.Context, env *models.ServiceEnvironment) (created bool, err error) {
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// xmax is only zero for a freshly inserted row
+	err = tx.QueryRow(ctx, `
+		INSERT INTO service_environments (service_id, environment, is_active)
+		VALUES ($1::uuid, $2, $3)
+		ON CONFLICT (service_id, environment) DO UPDATE SET is_active = EXCLUDED.is_active
+		RETURNING created_at, xmax = 0
+	`, env.ServiceID, env.Name, env.IsActive).Scan(&env.CreatedAt, &created)
+	if err != nil {
+		return false, fmt.Errorf("failed to save service environment: %w", err)
+	}
+	if _, err := tx.Exec(ctx, relinkServiceEnvironmentsSQL, env.ServiceID); err != nil {
+		return false, fmt.Errorf("failed to link service environment: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(se.argocd_app_id::text, ''), COALESCE(a.argocd_app_name, '')
+		FROM service_environments se
+		LEFT JOIN service_argocd_apps a ON a.id = se.argocd_app_id
+		WHERE se.service_id = $1::uuid AND se.environment = $2
+	`, env.ServiceID, env.Name).Scan(&env.ArgoCDAppID, &env.ArgoCDAppName)
+	if err != nil {
+		return false, fmt.Errorf("failed to read service environment: %w", err)
+	}
+
+	return created, tx.Commit(ctx)
+}
+
+// Delete removes an environment from a service; ErrNotFound means the service has no such environment
+func (r *ServiceEnvironmentRepository) Delete(ctx context.Context, serviceID, name string) error {
+	tag, err := r.db().Exec(ctx,
+		`DELETE FROM service_environments WHERE service_id = $1::uuid AND environment = $2`, serviceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete service environment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
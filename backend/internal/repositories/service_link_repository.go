@@ -19,13 +19,28 @@ func NewServiceLinkRepository() *ServiceLinkRepository {
 // GetByServiceID retrieves all links for a service
 func (r *ServiceLinkRepository) GetByServiceID(ctx context.Context, serviceID string) ([]models.ServiceLink, error) {
 	query := `
-		SELECT id, service_id, label, url, icon, created_at, updated_at
+		SELECT id, service_id, type, label, url, icon, created_at, updated_at
 		FROM service_links
 		WHERE service_id = $1
 		ORDER BY label
 	`
+	return r.query(ctx, query, serviceID)
+}
+
+// GetByServiceIDAndType retrieves the links of one type for a service
+func (r *ServiceLinkRepository) GetByServiceIDAndType(ctx context.Context, serviceID, linkType string) ([]models.ServiceLink, error) {
+	query := `
+		SELECT id, service_id, type, label, url, icon, created_at, updated_at
+		FROM service_links
+		WHERE service_id = $1 AND type = $2
+		ORDER BY label
+	`
+	return r.query(ctx, query, serviceID, linkType)
+}
 
-	rows, err := database.DB.Query(ctx, query, serviceID)
+// query runs a SELECT of service links
+func (r *ServiceLinkRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.ServiceLink, error) {
+	rows, err := database.DB.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +54,7 @@ func (r *ServiceLinkRepository) GetByServiceID(ctx context.Context, serviceID st
 		err := rows.Scan(
 			&link.ID,
 			&link.ServiceID,
+			&link.Type,
 			&link.Label,
 			&link.URL,
 			&icon,
@@ -62,8 +78,8 @@ func (r *ServiceLinkRepository) GetByServiceID(ctx context.Context, serviceID st
 // Create creates a new service link
 func (r *ServiceLinkRepository) Create(ctx context.Context, link *models.ServiceLink) error {
 	query := `
-		INSERT INTO service_links (service_id, label, url, icon, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO service_links (service_id, type, label, url, icon, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
@@ -75,6 +91,7 @@ func (r *ServiceLinkRepository) Create(ctx context.Context, link *models.Service
 
 	err := database.DB.QueryRow(ctx, query,
 		link.ServiceID,
+		link.Type,
 		link.Label,
 		link.URL,
 		icon,
@@ -91,12 +108,13 @@ func (r *ServiceLinkRepository) Create(ctx context.Context, link *models.Service
 	return nil
 }
 
-// Update updates an existing service link
+// Update updates an existing service link. An empty type keeps the current one.
 func (r *ServiceLinkRepository) Update(ctx context.Context, link *models.ServiceLink) error {
 	query := `
 		UPDATE service_links
-		SET label = $1, url = $2, icon = $3, updated_at = $4
+		SET label = $1, url = $2, icon = $3, updated_at = $4, type = COALESCE(NULLIF($6, ''), type)
 		WHERE id = $5
+		RETURNING type
 	`
 
 	now := time.Now()
@@ -105,13 +123,14 @@ func (r *ServiceLinkRepository) Update(ctx context.Context, link *models.Service
 		icon = &link.Icon
 	}
 
-	_, err := database.DB.Exec(ctx, query,
+	err := database.DB.QueryRow(ctx, query,
 		link.Label,
 		link.URL,
 		icon,
 		now,
 		link.ID,
-	)
+		link.Type,
+	).Scan(&link.Type)
 
 	if err != nil {
 		return err
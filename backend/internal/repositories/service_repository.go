@@ -7,33 +7,71 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
 )
 
 // ServiceRepository handles service database operations
-type ServiceRepository struct{}
+type ServiceRepository struct {
+	querier
+}
 
-// GetAll retrieves all services
-func (r *ServiceRepository) GetAll(ctx context.Context) ([]models.Service, error) {
-	query := `
+// WithQuerier returns a copy of the repository that runs against q, e.g. a transaction
+func (r *ServiceRepository) WithQuerier(q Querier) *ServiceRepository {
+	return &ServiceRepository{querier: querier{q: q}}
+}
+
+// serviceSortFields are the columns services can be listed by
+var serviceSortFields = []string{"name", "created_at", "updated_at", "environment"}
+
+// serviceEnvironmentsColumn selects the active environments of each row of the services table
+const serviceEnvironmentsColumn = `ARRAY(
+			SELECT se.environment FROM service_environments se
+			WHERE se.service_id = services.id AND se.is_active
+			ORDER BY se.environment
+		) AS environments`
+
+// ServiceFilter narrows down service lists; zero values are ignored
+type ServiceFilter struct {
+	Environment string // Services running in this environment, case-insensitive
+}
+
+// GetAll retrieves one page of the services matching filter and their total number
+func (r *ServiceRepository) GetAll(ctx context.Context, filter ServiceFilter, opts ListOptions) ([]models.Service, int, error) {
+	baseQuery := `
 		SELECT id, name, description, environment, language, tags, github_repo, owner, grafana_url, confluence_url, team_id, project_id,
-		       catalog_source, auto_synced, catalog_metadata
+		       catalog_source, auto_synced, catalog_metadata, slack_channel, pagerduty_service, created_at, updated_at,
+		       ` + serviceEnvironmentsColumn + `
 		FROM services
-		ORDER BY name
 	`
+	var baseArgs []interface{}
+	if filter.Environment != "" {
+		baseQuery += `
+		WHERE EXISTS (
+			SELECT 1 FROM service_environments se
+			WHERE se.service_id = services.id AND se.is_active AND LOWER(se.environment) = LOWER($1)
+		)
+	`
+		baseArgs = append(baseArgs, filter.Environment)
+	}
 
-	rows, err := database.DB.Query(ctx, query)
+	query, pageArgs, err := ApplyPagination(baseQuery, opts.withDefaultSort("name", "asc"), serviceSortFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	args := append(baseArgs, pageArgs...)
+
+	rows, err := r.db().Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	services := []models.Service{}
+	total := 0
 	for rows.Next() {
 		var service models.Service
 		var environment, language, grafanaURL, confluenceURL, teamID, projectID *string
-		var catalogSource *string
+		var catalogSource, slackChannel, pagerDutyService *string
 		var tags []string
 
 		err := rows.Scan(
@@ -52,9 +90,15 @@ func (r *ServiceRepository) GetAll(ctx context.Context) ([]models.Service, error
 			&catalogSource,
 			&service.AutoSynced,
 			&service.CatalogMetadata,
+			&slackChannel,
+			&pagerDutyService,
+			&service.CreatedAt,
+			&service.UpdatedAt,
+			&service.Environments,
+			&total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if environment != nil {
@@ -83,17 +127,34 @@ func (r *ServiceRepository) GetAll(ctx context.Context) ([]models.Service, error
 		if catalogSource != nil {
 			service.CatalogSource = *catalogSource
 		}
+		if slackChannel != nil {
+			service.SlackChannel = *slackChannel
+		}
+		if pagerDutyService != nil {
+			service.PagerDutyService = *pagerDutyService
+		}
 
 		services = append(services, service)
 	}
 
-	return services, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(services) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery, baseArgs...)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return services, total, nil
 }
 
 // FindByID finds a service by ID
 func (r *ServiceRepository) FindByID(ctx context.Context, id string) (*models.Service, error) {
 	query := `
-		SELECT id, name, description, environment, language, tags, github_repo, owner, grafana_url, confluence_url, team_id, project_id
+		SELECT id, name, description, environment, language, tags, github_repo, owner, grafana_url, confluence_url, team_id, project_id,
+		       ` + serviceEnvironmentsColumn + `
 		FROM services
 		WHERE id = $1::uuid
 	`
@@ -102,7 +163,7 @@ func (r *ServiceRepository) FindByID(ctx context.Context, id string) (*models.Se
 	var environment, language, grafanaURL, confluenceURL, teamID, projectID *string
 	var tags []string
 
-	err := database.DB.QueryRow(ctx, query, id).Scan(
+	err := r.db().QueryRow(ctx, query, id).Scan(
 		&service.ID,
 		&service.Name,
 		&service.Description,
@@ -115,6 +176,7 @@ func (r *ServiceRepository) FindByID(ctx context.Context, id string) (*models.Se
 		&confluenceURL,
 		&teamID,
 		&projectID,
+		&service.Environments,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -154,7 +216,8 @@ func (r *ServiceRepository) FindByID(ctx context.Context, id string) (*models.Se
 // FindByName finds a service by name
 func (r *ServiceRepository) FindByName(ctx context.Context, name string) (*models.Service, error) {
 	query := `
-		SELECT id, name, description, environment, language, tags, github_repo, owner, grafana_url, confluence_url, team_id, project_id
+		SELECT id, name, description, environment, language, tags, github_repo, owner, grafana_url, confluence_url, team_id, project_id,
+		       ` + serviceEnvironmentsColumn + `
 		FROM services
 		WHERE name = $1
 	`
@@ -163,7 +226,7 @@ func (r *ServiceRepository) FindByName(ctx context.Context, name string) (*model
 	var environment, language, grafanaURL, confluenceURL, teamID, projectID *string
 	var tags []string
 
-	err := database.DB.QueryRow(ctx, query, name).Scan(
+	err := r.db().QueryRow(ctx, query, name).Scan(
 		&service.ID,
 		&service.Name,
 		&service.Description,
@@ -176,6 +239,7 @@ func (r *ServiceRepository) FindByName(ctx context.Context, name string) (*model
 		&confluenceURL,
 		&teamID,
 		&projectID,
+		&service.Environments,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -217,7 +281,7 @@ func (r *ServiceRepository) Update(ctx context.Context, service *models.Service)
 		WHERE id = $1::uuid
 	`
 
-	_, err := database.DB.Exec(ctx, query, service.ID, service.Owner)
+	_, err := r.db().Exec(ctx, query, service.ID, service.Owner)
 	if err != nil {
 		return fmt.Errorf("failed to update service: %w", err)
 	}
@@ -230,13 +294,14 @@ func (r *ServiceRepository) FindByProjectID(ctx context.Context, projectID strin
 	query := `
 		SELECT id, name, description, team_id, project_id, environment, language, tags,
 		       github_repo, grafana_url, confluence_url, owner, catalog_source,
-		       auto_synced, created_at, updated_at
+		       auto_synced, slack_channel, pagerduty_service, created_at, updated_at,
+		       ` + serviceEnvironmentsColumn + `
 		FROM services
 		WHERE project_id = $1
 		ORDER BY name
 	`
 
-	rows, err := database.DB.Query(ctx, query, projectID)
+	rows, err := r.db().Query(ctx, query, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +311,7 @@ func (r *ServiceRepository) FindByProjectID(ctx context.Context, projectID strin
 	for rows.Next() {
 		var service models.Service
 		var teamID, grafanaURL, confluenceURL, owner, catalogSource *string
+		var slackChannel, pagerDutyService *string
 
 		err := rows.Scan(
 			&service.ID,
@@ -262,8 +328,11 @@ func (r *ServiceRepository) FindByProjectID(ctx context.Context, projectID strin
 			&owner,
 			&catalogSource,
 			&service.AutoSynced,
+			&slackChannel,
+			&pagerDutyService,
 			&service.CreatedAt,
 			&service.UpdatedAt,
+			&service.Environments,
 		)
 		if err != nil {
 			return nil, err
@@ -284,6 +353,12 @@ func (r *ServiceRepository) FindByProjectID(ctx context.Context, projectID strin
 		if catalogSource != nil {
 			service.CatalogSource = *catalogSource
 		}
+		if slackChannel != nil {
+			service.SlackChannel = *slackChannel
+		}
+		if pagerDutyService != nil {
+			service.PagerDutyService = *pagerDutyService
+		}
 
 		services = append(services, service)
 	}
@@ -307,12 +382,14 @@ func (r *ServiceRepository) UpsertFromCatalog(ctx context.Context, service *mode
 			id, name, description, environment, language, tags, github_repo, owner,
 			grafana_url, confluence_url, team_id, project_id,
 			catalog_source, auto_synced, catalog_metadata,
+			slack_channel, pagerduty_service,
 			created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8,
 			$9, $10, $11, $12,
 			$13, $14, $15,
-			$16, $17
+			$16, $17,
+			$18, $19
 		)
 		ON CONFLICT (project_id, name) DO UPDATE SET
 			description = EXCLUDED.description,
@@ -327,19 +404,27 @@ func (r *ServiceRepository) UpsertFromCatalog(ctx context.Context, service *mode
 			catalog_source = EXCLUDED.catalog_source,
 			auto_synced = EXCLUDED.auto_synced,
 			catalog_metadata = EXCLUDED.catalog_metadata,
+			slack_channel = EXCLUDED.slack_channel,
+			pagerduty_service = EXCLUDED.pagerduty_service,
 			updated_at = EXCLUDED.updated_at
 		RETURNING id
 	`
 
-	var teamID, projectID *string
+	var teamID, projectID, slackChannel, pagerDutyService *string
 	if service.Team != "" {
 		teamID = &service.Team
 	}
 	if service.ProjectID != "" {
 		projectID = &service.ProjectID
 	}
+	if service.SlackChannel != "" {
+		slackChannel = &service.SlackChannel
+	}
+	if service.PagerDutyService != "" {
+		pagerDutyService = &service.PagerDutyService
+	}
 
-	err := database.DB.QueryRow(ctx, query,
+	err := r.db().QueryRow(ctx, query,
 		service.ID,
 		service.Name,
 		service.Description,
@@ -355,6 +440,8 @@ func (r *ServiceRepository) UpsertFromCatalog(ctx context.Context, service *mode
 		service.CatalogSource,
 		service.AutoSynced,
 		service.CatalogMetadata,
+		slackChannel,
+		pagerDutyService,
 		service.CreatedAt,
 		service.UpdatedAt,
 	).Scan(&service.ID)
@@ -362,6 +449,68 @@ func (r *ServiceRepository) UpsertFromCatalog(ctx context.Context, service *mode
 	return err
 }
 
+// SetEnvironments replaces the environments a service runs in. Environments that
+// were switched off stay inactive.
+func (r *ServiceRepository) SetEnvironments(ctx context.Context, serviceID string, environments []string) error {
+	if environments == nil {
+		environments = []string{} // A NULL array would match nothing in != ALL
+	}
+
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM service_environments
+		WHERE service_id = $1::uuid AND environment != ALL($2)
+	`, serviceID, environments); err != nil {
+		return fmt.Errorf("failed to remove service environments: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO service_environments (service_id, environment)
+		SELECT $1::uuid, UNNEST($2::text[])
+		ON CONFLICT DO NOTHING
+	`, serviceID, environments); err != nil {
+		return fmt.Errorf("failed to add service environments: %w", err)
+	}
+	if _, err := tx.Exec(ctx, relinkServiceEnvironmentsSQL, serviceID); err != nil {
+		return fmt.Errorf("failed to link service environments: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindIDsByRefs maps project-name/service-name references, where project-name
+// is the catalog name of a synced project, to service IDs; unknown references are left out
+func (r *ServiceRepository) FindIDsByRefs(ctx context.Context, refs []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	if len(refs) == 0 {
+		return ids, nil
+	}
+
+	rows, err := r.db().Query(ctx, `
+		SELECT s.id, p.catalog_name || '/' || s.name
+		FROM services s
+		JOIN projects p ON p.id = s.project_id
+		WHERE p.catalog_name || '/' || s.name = ANY($1)
+	`, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up services by reference: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, ref string
+		if err := rows.Scan(&id, &ref); err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		ids[ref] = id
+	}
+	return ids, rows.Err()
+}
+
 // DeleteOrphanedServices removes services that belong to a project but are not in the active list
 func (r *ServiceRepository) DeleteOrphanedServices(ctx context.Context, projectID string, activeServiceNames []string) error {
 	query := `
@@ -370,9 +519,125 @@ func (r *ServiceRepository) DeleteOrphanedServices(ctx context.Context, projectI
 		  AND auto_synced = true
 		  AND name != ALL($2)
 	`
-	_, err := database.DB.Exec(ctx, query, projectID, activeServiceNames)
+	_, err := r.db().Exec(ctx, query, projectID, activeServiceNames)
 	if err != nil {
 		return fmt.Errorf("failed to delete orphaned services: %w", err)
 	}
 	return nil
 }
+
+// TagCounts returns every distinct service tag with the number of services carrying
+// it, most used first
+func (r *ServiceRepository) TagCounts(ctx context.Context) ([]models.TagCount, error) {
+	query := `
+		SELECT tag, COUNT(*)
+		FROM services, unnest(tags) AS tag
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag
+	`
+
+	rows, err := r.db().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count service tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.TagCount{}
+	for rows.Next() {
+		var count models.TagCount
+		if err := rows.Scan(&count.Tag, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// GetProjectStats counts the services of a project by language, environment and
+// team in a single query. Services without a language or team count as "unspecified".
+// The resource fields are left for the caller.
+func (r *ServiceRepository) GetProjectStats(ctx context.Context, projectID string) (*models.ProjectStats, error) {
+	query := `
+		WITH project_services AS (
+			SELECT s.id, s.auto_synced,
+			       COALESCE(NULLIF(s.language, ''), 'unspecified') AS language,
+			       COALESCE(t.name, 'unspecified') AS team
+			FROM services s
+			LEFT JOIN teams t ON t.id = s.team_id
+			WHERE s.project_id = $1::uuid
+		)
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE ps.auto_synced),
+			(SELECT last_synced_at FROM projects WHERE id = $1::uuid),
+			(SELECT COALESCE(jsonb_object_agg(language, n), '{}'::jsonb)
+			 FROM (SELECT language, COUNT(*) AS n FROM project_services GROUP BY language) l),
+			(SELECT COALESCE(jsonb_object_agg(environment, n), '{}'::jsonb)
+			 FROM (
+				SELECT se.environment, COUNT(*) AS n
+				FROM project_services p
+				JOIN service_environments se ON se.service_id = p.id AND se.is_active
+				GROUP BY se.environment
+			 ) e),
+			(SELECT COALESCE(jsonb_object_agg(team, n), '{}'::jsonb)
+			 FROM (SELECT team, COUNT(*) AS n FROM project_services GROUP BY team) t)
+		FROM project_services ps
+	`
+
+	stats := &models.ProjectStats{}
+	err := r.db().QueryRow(ctx, query, projectID).Scan(
+		&stats.ServiceCount,
+		&stats.AutoSyncedCount,
+		&stats.LastSyncedAt,
+		&stats.ServicesByLanguage,
+		&stats.ServicesByEnvironment,
+		&stats.ServicesByTeam,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute project stats: %w", err)
+	}
+	stats.ManualCount = stats.ServiceCount - stats.AutoSyncedCount
+	return stats, nil
+}
+
+// Search finds services by name, description or tags, exact name matches first.
+// Services without a project are visible to everyone.
+func (r *ServiceRepository) Search(ctx context.Context, query string, limit int, userID string) ([]models.SearchResult, error) {
+	sql := `
+		SELECT id::text, name, COALESCE(description, ''), project_id::text, COALESCE(array_to_string(tags, ', '), ''),
+			` + searchNameRank("name") + ` + ts_rank_cd(` + searchDocument + `, plainto_tsquery('english', $1)) AS rank
+		FROM services
+		WHERE (name ILIKE $3 OR description ILIKE $3 OR array_to_string(tags, ' ') ILIKE $3 OR name % $1
+			OR ` + searchDocument + ` @@ plainto_tsquery('english', $1))
+			AND (project_id IS NULL OR ` + projectAccessCondition("project_id", "$4") + `)
+		ORDER BY rank DESC, name
+		LIMIT $5
+	`
+
+	rows, err := r.db().Query(ctx, sql, searchArgs(query, limit, userID)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search services: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		result := models.SearchResult{Type: models.SearchTypeService}
+		var projectID *string
+		var tags string
+		if err := rows.Scan(&result.ID, &result.Name, &result.Description, &projectID, &tags, &result.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan service search result: %w", err)
+		}
+		if projectID != nil {
+			result.ProjectID = *projectID
+		}
+		result.MatchedField, result.Highlight = matchedField(query,
+			[2]string{"name", result.Name},
+			[2]string{"tags", tags},
+			[2]string{"description", result.Description},
+		)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
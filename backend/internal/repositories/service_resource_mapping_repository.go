@@ -23,6 +23,7 @@ func (r *ServiceResourceMappingRepository) GetByServiceID(ctx context.Context, s
 			srm.id, 
 			srm.service_id, 
 			srm.discovered_resource_id, 
+			srm.environment,
 			srm.created_at,
 			dr.name,
 			dr.resource_type,
@@ -43,12 +44,13 @@ func (r *ServiceResourceMappingRepository) GetByServiceID(ctx context.Context, s
 	var mappings []models.ServiceResourceMapping
 	for rows.Next() {
 		var m models.ServiceResourceMapping
-		var resourceName, resourceType, resourceARN, region *string
+		var environment, resourceName, resourceType, resourceARN, region *string
 
 		err := rows.Scan(
 			&m.ID,
 			&m.ServiceID,
 			&m.DiscoveredResourceID,
+			&environment,
 			&m.CreatedAt,
 			&resourceName,
 			&resourceType,
@@ -59,6 +61,9 @@ func (r *ServiceResourceMappingRepository) GetByServiceID(ctx context.Context, s
 			return nil, err
 		}
 
+		if environment != nil {
+			m.Environment = *environment
+		}
 		if resourceName != nil {
 			m.ResourceName = *resourceName
 		}
@@ -81,8 +86,8 @@ func (r *ServiceResourceMappingRepository) GetByServiceID(ctx context.Context, s
 // Create creates a new service-to-resource mapping
 func (r *ServiceResourceMappingRepository) Create(ctx context.Context, mapping *models.ServiceResourceMapping) error {
 	query := `
-		INSERT INTO service_resource_mappings (service_id, discovered_resource_id, created_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO service_resource_mappings (service_id, discovered_resource_id, environment, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
 		RETURNING id
 	`
 
@@ -90,6 +95,7 @@ func (r *ServiceResourceMappingRepository) Create(ctx context.Context, mapping *
 	err := database.DB.QueryRow(ctx, query,
 		mapping.ServiceID,
 		mapping.DiscoveredResourceID,
+		mapping.Environment,
 		now,
 	).Scan(&mapping.ID)
 
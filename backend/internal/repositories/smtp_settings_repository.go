@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/crypto"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// smtpSettingsID is the ID of the singleton smtp_settings row
+const smtpSettingsID = "00000000-0000-0000-0000-000000000001"
+
+// SMTPSettingsRepository handles the outgoing mail server configured in the UI
+type SMTPSettingsRepository struct{}
+
+// NewSMTPSettingsRepository creates a new SMTP settings repository
+func NewSMTPSettingsRepository() *SMTPSettingsRepository {
+	return &SMTPSettingsRepository{}
+}
+
+// Get returns the SMTP settings without the password; before they are saved the
+// host is empty
+func (r *SMTPSettingsRepository) Get(ctx context.Context) (*models.SMTPSettings, error) {
+	settings, _, err := r.get(ctx)
+	return settings, err
+}
+
+// GetWithPassword returns the SMTP settings with the decrypted password
+func (r *SMTPSettingsRepository) GetWithPassword(ctx context.Context) (*models.SMTPSettings, error) {
+	settings, encrypted, err := r.get(ctx)
+	if err != nil || encrypted == "" {
+		return settings, err
+	}
+	settings.Password, err = crypto.Decrypt(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SMTP password: %w", err)
+	}
+	return settings, nil
+}
+
+func (r *SMTPSettingsRepository) get(ctx context.Context) (*models.SMTPSettings, string, error) {
+	query := `
+		SELECT host, port, tls_mode, from_address, username, password_encrypted, updated_at
+		FROM smtp_settings
+		WHERE id = $1::uuid
+	`
+
+	settings := &models.SMTPSettings{Port: 587, TLSMode: models.SMTPTLSStartTLS}
+	var encrypted string
+	err := database.DB.QueryRow(ctx, query, smtpSettingsID).Scan(
+		&settings.Host, &settings.Port, &settings.TLSMode, &settings.From,
+		&settings.Username, &encrypted, &settings.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return settings, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get SMTP settings: %w", err)
+	}
+	settings.PasswordSet = encrypted != ""
+	return settings, encrypted, nil
+}
+
+// Save creates or replaces the SMTP settings. An empty Password keeps the stored one
+// unless Username is cleared too, which removes the credentials.
+func (r *SMTPSettingsRepository) Save(ctx context.Context, settings *models.SMTPSettings) error {
+	var encrypted *string // nil keeps the stored password
+	switch {
+	case settings.Password != "":
+		value, err := crypto.Encrypt(settings.Password)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt SMTP password: %w", err)
+		}
+		encrypted = &value
+	case settings.Username == "":
+		empty := ""
+		encrypted = &empty
+	}
+	settings.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO smtp_settings (id, host, port, tls_mode, from_address, username, password_encrypted, updated_at)
+		VALUES ($1::uuid, $2, $3, $4, $5, $6, COALESCE($7, ''), $8)
+		ON CONFLICT (id) DO UPDATE SET
+			host = EXCLUDED.host,
+			port = EXCLUDED.port,
+			tls_mode = EXCLUDED.tls_mode,
+			from_address = EXCLUDED.from_address,
+			username = EXCLUDED.username,
+			password_encrypted = COALESCE($7, smtp_settings.password_encrypted),
+			updated_at = EXCLUDED.updated_at
+		RETURNING password_encrypted <> ''
+	`
+	err := database.DB.QueryRow(ctx, query, smtpSettingsID, settings.Host, settings.Port, settings.TLSMode,
+		settings.From, settings.Username, encrypted, settings.UpdatedAt).Scan(&settings.PasswordSet)
+	if err != nil {
+		return fmt.Errorf("failed to save SMTP settings: %w", err)
+	}
+	settings.Password = ""
+	return nil
+}
@@ -3,16 +3,17 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
 )
 
 type SyncHistoryRepository struct {
-	db *pgxpool.Pool
+	db *database.Pool
 }
 
-func NewSyncHistoryRepository(db *pgxpool.Pool) *SyncHistoryRepository {
+func NewSyncHistoryRepository(db *database.Pool) *SyncHistoryRepository {
 	return &SyncHistoryRepository{db: db}
 }
 
@@ -23,12 +24,12 @@ func (r *SyncHistoryRepository) Create(ctx context.Context, history *models.Sync
 			id, sync_type, project_id, project_name, catalog_file_path,
 			status, projects_created, projects_updated, services_created, services_updated, services_orphaned,
 			error_message, validation_errors, started_at, completed_at, duration_ms,
-			synced_by, synced_by_name
+			synced_by, synced_by_name, plan
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10, $11,
 			$12, $13, $14, $15, $16,
-			$17, $18
+			$17, $18, $19
 		)
 	`
 
@@ -43,12 +44,13 @@ func (r *SyncHistoryRepository) Create(ctx context.Context, history *models.Sync
 	}
 
 	validationErrorsJSON, _ := json.Marshal(history.ValidationErrors)
+	planJSON, _ := json.Marshal(history.Plan)
 
 	_, err := r.db.Exec(ctx, query,
 		history.ID, history.SyncType, projectID, history.ProjectName, history.CatalogFilePath,
 		history.Status, history.ProjectsCreated, history.ProjectsUpdated, history.ServicesCreated, history.ServicesUpdated, history.ServicesOrphaned,
 		history.ErrorMessage, validationErrorsJSON, history.StartedAt, history.CompletedAt, history.DurationMs,
-		syncedBy, history.SyncedByName,
+		syncedBy, history.SyncedByName, planJSON,
 	)
 
 	return err
@@ -62,19 +64,98 @@ func (r *SyncHistoryRepository) Update(ctx context.Context, history *models.Sync
 		    projects_created = $2, projects_updated = $3,
 		    services_created = $4, services_updated = $5, services_orphaned = $6,
 		    error_message = $7, validation_errors = $8,
-		    completed_at = $9, duration_ms = $10
-		WHERE id = $11
+		    completed_at = $9, duration_ms = $10, plan = $11,
+		    project_id = $13, project_name = $14
+		WHERE id = $12
 	`
 
+	// The project is only known once the sync has resolved it
+	var projectID *string
+	if history.ProjectID != "" {
+		projectID = &history.ProjectID
+	}
+
 	validationErrorsJSON, _ := json.Marshal(history.ValidationErrors)
+	planJSON, _ := json.Marshal(history.Plan)
 
 	_, err := r.db.Exec(ctx, query,
 		history.Status, history.ProjectsCreated, history.ProjectsUpdated,
 		history.ServicesCreated, history.ServicesUpdated, history.ServicesOrphaned,
 		history.ErrorMessage, validationErrorsJSON,
-		history.CompletedAt, history.DurationMs,
-		history.ID,
+		history.CompletedAt, history.DurationMs, planJSON,
+		history.ID, projectID, history.ProjectName,
 	)
 
 	return err
 }
+
+// syncHistorySortFields are the columns sync history can be listed by
+var syncHistorySortFields = []string{"started_at"}
+
+// FindByProjectID retrieves one page of a project's syncs, newest first, and the
+// total number of them
+func (r *SyncHistoryRepository) FindByProjectID(ctx context.Context, projectID string, opts ListOptions) ([]models.SyncHistory, int, error) {
+	return r.list(ctx, " WHERE project_id = $1::uuid", []interface{}{projectID}, opts)
+}
+
+// FindAll retrieves one page of every sync, newest first, and the total number of them
+func (r *SyncHistoryRepository) FindAll(ctx context.Context, opts ListOptions) ([]models.SyncHistory, int, error) {
+	return r.list(ctx, "", nil, opts)
+}
+
+// list pages through the sync history matching where. The plan is left out to keep
+// pages small.
+func (r *SyncHistoryRepository) list(ctx context.Context, where string, args []interface{}, opts ListOptions) ([]models.SyncHistory, int, error) {
+	baseQuery := `
+		SELECT id, sync_type, COALESCE(project_id::text, ''), COALESCE(project_name, ''), COALESCE(catalog_file_path, ''),
+		       status, COALESCE(projects_created, 0), COALESCE(projects_updated, 0),
+		       COALESCE(services_created, 0), COALESCE(services_updated, 0), COALESCE(services_orphaned, 0),
+		       COALESCE(error_message, ''), validation_errors, started_at, completed_at, COALESCE(duration_ms, 0),
+		       COALESCE(synced_by::text, ''), COALESCE(synced_by_name, '')
+		FROM catalog_sync_history
+	` + where
+
+	query, pageArgs, err := ApplyPagination(baseQuery, opts.withDefaultSort("started_at", "desc"), syncHistorySortFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list sync history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.SyncHistory{}
+	total := 0
+	for rows.Next() {
+		var h models.SyncHistory
+		var validationErrors []byte
+		err := rows.Scan(
+			&h.ID, &h.SyncType, &h.ProjectID, &h.ProjectName, &h.CatalogFilePath,
+			&h.Status, &h.ProjectsCreated, &h.ProjectsUpdated,
+			&h.ServicesCreated, &h.ServicesUpdated, &h.ServicesOrphaned,
+			&h.ErrorMessage, &validationErrors, &h.StartedAt, &h.CompletedAt, &h.DurationMs,
+			&h.SyncedBy, &h.SyncedByName,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan sync history: %w", err)
+		}
+		if len(validationErrors) > 0 && string(validationErrors) != "null" {
+			h.ValidationErrors = json.RawMessage(validationErrors)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(history) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery, args...)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return history, total, nil
+}
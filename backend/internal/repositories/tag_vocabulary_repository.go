@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// tagVocabularyID is the ID of the singleton tag_vocabulary row
+const tagVocabularyID = "00000000-0000-0000-0000-000000000001"
+
+// TagVocabularyRepository handles the controlled vocabulary for service tags
+type TagVocabularyRepository struct{}
+
+// NewTagVocabularyRepository creates a new tag vocabulary repository
+func NewTagVocabularyRepository() *TagVocabularyRepository {
+	return &TagVocabularyRepository{}
+}
+
+// Get returns the tag vocabulary; before one is saved this is the disabled default
+func (r *TagVocabularyRepository) Get(ctx context.Context) (*models.TagVocabulary, error) {
+	query := `
+		SELECT enabled, strict, tags, aliases, updated_at
+		FROM tag_vocabulary
+		WHERE id = $1::uuid
+	`
+
+	vocab := &models.TagVocabulary{Tags: []string{}, Aliases: map[string]string{}}
+	var aliases []byte
+	err := database.DB.QueryRow(ctx, query, tagVocabularyID).Scan(
+		&vocab.Enabled, &vocab.Strict, &vocab.Tags, &aliases, &vocab.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return vocab, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag vocabulary: %w", err)
+	}
+	if err := json.Unmarshal(aliases, &vocab.Aliases); err != nil {
+		return nil, fmt.Errorf("failed to decode tag aliases: %w", err)
+	}
+	return vocab, nil
+}
+
+// Save creates or replaces the tag vocabulary
+func (r *TagVocabularyRepository) Save(ctx context.Context, vocab *models.TagVocabulary) error {
+	aliases, err := json.Marshal(vocab.Aliases)
+	if err != nil {
+		return fmt.Errorf("failed to encode tag aliases: %w", err)
+	}
+	vocab.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO tag_vocabulary (id, enabled, strict, tags, aliases, updated_at)
+		VALUES ($1::uuid, $2, $3, $4, $5::jsonb, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			strict = EXCLUDED.strict,
+			tags = EXCLUDED.tags,
+			aliases = EXCLUDED.aliases,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err = database.DB.Exec(ctx, query, tagVocabularyID, vocab.Enabled, vocab.Strict, vocab.Tags, string(aliases), vocab.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save tag vocabulary: %w", err)
+	}
+	return nil
+}
@@ -14,21 +14,29 @@ import (
 // TeamRepository handles team database operations
 type TeamRepository struct{}
 
-// GetAll retrieves all teams
-func (r *TeamRepository) GetAll(ctx context.Context) ([]models.Team, error) {
-	query := `
+// teamSortFields are the columns teams can be listed by
+var teamSortFields = []string{"created_at", "name"}
+
+// GetAll retrieves one page of teams and the total number of teams
+func (r *TeamRepository) GetAll(ctx context.Context, opts ListOptions) ([]models.Team, int, error) {
+	baseQuery := `
 		SELECT id, name, description, created_at
 		FROM teams
-		ORDER BY created_at DESC
 	`
 
-	rows, err := database.DB.Query(ctx, query)
+	query, args, err := ApplyPagination(baseQuery, opts.withDefaultSort("created_at", "desc"), teamSortFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var teams []models.Team
+	teams := []models.Team{}
+	total := 0
 	for rows.Next() {
 		var team models.Team
 		err := rows.Scan(
@@ -36,9 +44,10 @@ func (r *TeamRepository) GetAll(ctx context.Context) ([]models.Team, error) {
 			&team.Name,
 			&team.Description,
 			&team.CreatedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		// Load member IDs
@@ -51,7 +60,17 @@ func (r *TeamRepository) GetAll(ctx context.Context) ([]models.Team, error) {
 		teams = append(teams, team)
 	}
 
-	return teams, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(teams) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return teams, total, nil
 }
 
 // FindByID finds a team by ID
@@ -163,19 +182,25 @@ func (r *TeamRepository) GetTeamMemberIDs(ctx context.Context, teamID string) ([
 	return memberIDs, rows.Err()
 }
 
-// UpdateTeamMembers updates the members of a team
-func (r *TeamRepository) UpdateTeamMembers(ctx context.Context, teamID string, memberIDs []string) error {
+// UpdateTeamMembers replaces the members of a team and returns which users
+// were added and removed relative to the previous membership
+func (r *TeamRepository) UpdateTeamMembers(ctx context.Context, teamID string, memberIDs []string) (*models.TeamMembershipDiff, error) {
+	existingIDs, err := r.GetTeamMemberIDs(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
 	// Start transaction
 	tx, err := database.DB.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
 	// Delete existing members
 	_, err = tx.Exec(ctx, "DELETE FROM team_members WHERE team_id = $1::uuid", teamID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Add new members
@@ -184,11 +209,42 @@ func (r *TeamRepository) UpdateTeamMembers(ctx context.Context, teamID string, m
 			"INSERT INTO team_members (team_id, user_id) VALUES ($1::uuid, $2::uuid)",
 			teamID, memberID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return tx.Commit(ctx)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return diffMemberIDs(existingIDs, memberIDs), nil
+}
+
+// diffMemberIDs computes the users present in next but not previous (added)
+// and in previous but not next (removed), preserving input order
+func diffMemberIDs(previous, next []string) *models.TeamMembershipDiff {
+	before := make(map[string]bool, len(previous))
+	for _, id := range previous {
+		before[id] = true
+	}
+	after := make(map[string]bool, len(next))
+	for _, id := range next {
+		after[id] = true
+	}
+
+	diff := &models.TeamMembershipDiff{Added: []string{}, Removed: []string{}}
+	for _, id := range next {
+		if !before[id] {
+			diff.Added = append(diff.Added, id)
+			before[id] = true // skip duplicates in the request
+		}
+	}
+	for _, id := range previous {
+		if !after[id] {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	return diff
 }
 
 // FindByName finds a team by name (case-insensitive)
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/portalight/backend/internal/database"
+)
+
+// useTestDB points database.DB at TEST_DATABASE_URL, a database with the schema
+// applied. Tests needing it are skipped when it is not set.
+func useTestDB(t testing.TB) {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+
+	previous := database.DB
+	database.DB = &database.Pool{Pool: pool}
+	t.Cleanup(func() {
+		database.DB = previous
+		pool.Close()
+	})
+}
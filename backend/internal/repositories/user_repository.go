@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -130,6 +131,19 @@ func (r *UserRepository) FindByGithubID(ctx context.Context, githubID int64) (*m
 	return &user, nil
 }
 
+// FindByGithubUsername finds a user by GitHub username; GitHub usernames are case-insensitive
+func (r *UserRepository) FindByGithubUsername(ctx context.Context, username string) (*models.User, error) {
+	var id string
+	err := database.DB.QueryRow(ctx, "SELECT id FROM users WHERE LOWER(github_username) = LOWER($1)", username).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	if user.ID == "" {
@@ -186,7 +200,30 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	return err
 }
 
-// Update updates an existing user
+// ErrLastSuperadmin is returned when a change would leave no superadmin
+var ErrLastSuperadmin = errors.New("cannot remove the last remaining superadmin")
+
+// ensureOtherSuperadmin returns ErrLastSuperadmin when userID is the only superadmin.
+// It locks the superadmin rows until tx ends, so concurrent demotions and deletes
+// are decided one after another and cannot remove the last superadmin together.
+func ensureOtherSuperadmin(ctx context.Context, tx pgx.Tx, userID string) error {
+	rows, err := tx.Query(ctx, "SELECT id::text FROM users WHERE role = $1 FOR UPDATE", models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to lock superadmins: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to lock superadmins: %w", err)
+	}
+	for _, id := range ids {
+		if id == userID && len(ids) == 1 {
+			return ErrLastSuperadmin
+		}
+	}
+	return nil
+}
+
+// Update updates an existing user. Demoting the last superadmin returns ErrLastSuperadmin.
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users
@@ -209,7 +246,19 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		avatarURL = &user.Avatar
 	}
 
-	_, err := database.DB.Exec(ctx, query,
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if user.Role != models.RoleAdmin {
+		if err := ensureOtherSuperadmin(ctx, tx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, query,
 		user.Name,
 		email,
 		user.Role,
@@ -219,8 +268,11 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		time.Now(),
 		user.ID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit(ctx)
 }
 
 // GetUserTeamIDs retrieves team IDs for a user
@@ -249,21 +301,29 @@ func (r *UserRepository) GetUserTeamIDs(ctx context.Context, userID string) ([]s
 	return teamIDs, rows.Err()
 }
 
-// GetAll retrieves all users
-func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
-	query := `
+// userSortFields are the columns users can be listed by
+var userSortFields = []string{"created_at", "name", "email", "role"}
+
+// GetAll retrieves one page of users and the total number of users
+func (r *UserRepository) GetAll(ctx context.Context, opts ListOptions) ([]models.User, int, error) {
+	baseQuery := `
 		SELECT id, name, email, role, avatar, github_id, github_username, avatar_url, created_at
 		FROM users
-		ORDER BY created_at DESC
 	`
 
-	rows, err := database.DB.Query(ctx, query)
+	query, args, err := ApplyPagination(baseQuery, opts.withDefaultSort("created_at", "desc"), userSortFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	rows, err := database.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var users []models.User
+	users := []models.User{}
+	total := 0
 	for rows.Next() {
 		var user models.User
 		var email, avatar *string
@@ -280,9 +340,10 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 			&githubUsername,
 			&avatarURL,
 			&user.CreatedAt,
+			&total,
 		)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		if email != nil {
@@ -312,7 +373,17 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 		users = append(users, user)
 	}
 
-	return users, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(users) == 0 && opts.Offset > 0 {
+		total, err = countRows(ctx, baseQuery)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return users, total, nil
 }
 
 // FindByID finds a user by ID
@@ -375,3 +446,155 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 
 	return &user, nil
 }
+
+// SetTeamIDs replaces the team memberships of a user
+func (r *UserRepository) SetTeamIDs(ctx context.Context, userID string, teamIDs []string) error {
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "DELETE FROM team_members WHERE user_id = $1::uuid", userID)
+	if err != nil {
+		return fmt.Errorf("failed to clear team memberships: %w", err)
+	}
+
+	for _, teamID := range teamIDs {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO team_members (team_id, user_id) VALUES ($1::uuid, $2::uuid)",
+			teamID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to add team membership: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindByOIDCSubject finds the user linked to an OpenID Connect subject
+func (r *UserRepository) FindByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	var id string
+	err := database.DB.QueryRow(ctx, "SELECT id FROM users WHERE oidc_subject = $1", subject).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+// LinkOIDCSubject links an OpenID Connect subject to the user with the given email
+// (compared case-insensitively), unless that user is linked to another subject. It
+// returns the linked user, or ErrNotFound if there is none to link.
+func (r *UserRepository) LinkOIDCSubject(ctx context.Context, email, subject string) (*models.User, error) {
+	var id string
+	err := database.DB.QueryRow(ctx, `
+		UPDATE users SET oidc_subject = $2, updated_at = NOW()
+		WHERE LOWER(email) = LOWER($1) AND (oidc_subject IS NULL OR oidc_subject = $2)
+		RETURNING id
+	`, email, subject).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
+
+// FindByGitlabID finds the user linked to a GitLab user ID
+func (r *UserRepository) FindByGitlabID(ctx context.Context, gitlabID int64) (*models.User, error) {
+	var id string
+	var gitlabUsername *string
+	err := database.DB.QueryRow(ctx, "SELECT id, gitlab_username FROM users WHERE gitlab_id = $1", gitlabID).Scan(&id, &gitlabUsername)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.GitlabID = gitlabID
+	if gitlabUsername != nil {
+		user.GitlabUsername = *gitlabUsername
+	}
+	return user, nil
+}
+
+// SetGitlabIdentity links a user to a GitLab user ID and records their GitLab username
+func (r *UserRepository) SetGitlabIdentity(ctx context.Context, userID string, gitlabID int64, username string) error {
+	_, err := database.DB.Exec(ctx,
+		"UPDATE users SET gitlab_id = $2, gitlab_username = $3, updated_at = NOW() WHERE id = $1",
+		userID, gitlabID, username,
+	)
+	return err
+}
+
+// EmailsByRole returns the email addresses of the users with the given role
+func (r *UserRepository) EmailsByRole(ctx context.Context, role models.Role) ([]string, error) {
+	rows, err := database.DB.Query(ctx, "SELECT email FROM users WHERE role = $1 AND email IS NOT NULL AND email <> '' ORDER BY email", role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+// Delete removes a user together with their team memberships and provisioning
+// permissions. Permissions the user granted to others are reassigned to reassignTo,
+// and catalog records, credentials and provision requests they created keep existing
+// without an owner. Deleting the last superadmin returns ErrLastSuperadmin.
+func (r *UserRepository) Delete(ctx context.Context, id, reassignTo string) error {
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := ensureOtherSuperadmin(ctx, tx, id); err != nil {
+		return err
+	}
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"DELETE FROM team_members WHERE user_id = $1::uuid", []interface{}{id}},
+		{"DELETE FROM user_provisioning_permissions WHERE user_id = $1::uuid", []interface{}{id}},
+		{"UPDATE user_provisioning_permissions SET granted_by = $2::uuid WHERE granted_by = $1::uuid", []interface{}{id, reassignTo}},
+		{"UPDATE github_metadata_config SET created_by = NULL WHERE created_by = $1::uuid", []interface{}{id}},
+		{"UPDATE catalog_sync_history SET synced_by = NULL WHERE synced_by = $1::uuid", []interface{}{id}},
+		{"UPDATE secrets SET created_by = NULL WHERE created_by = $1::uuid", []interface{}{id}},
+		{"UPDATE provision_requests SET user_id = NULL WHERE user_id = $1::uuid", []interface{}{id}},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt.query, stmt.args...); err != nil {
+			return fmt.Errorf("failed to clean up user references: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1::uuid", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user %s: %w", id, pgx.ErrNoRows)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/models"
+)
+
+// createTestSuperadmins creates n superadmins, removed again when the test ends
+func createTestSuperadmins(t *testing.T, repo *UserRepository, n int) []*models.User {
+	t.Helper()
+	ctx := context.Background()
+
+	var existing int
+	if err := database.DB.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE role = $1", models.RoleAdmin).Scan(&existing); err != nil {
+		t.Fatalf("failed to count superadmins: %v", err)
+	}
+	if existing > 0 {
+		t.Skip("the test database already has superadmins")
+	}
+
+	users := make([]*models.User, n)
+	for i := range users {
+		users[i] = &models.User{
+			Name:  fmt.Sprintf("Superadmin %d", i),
+			Email: fmt.Sprintf("superadmin-%d-%d@test.invalid", i, time.Now().UnixNano()),
+			Role:  models.RoleAdmin,
+		}
+		if err := repo.Create(ctx, users[i]); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, user := range users {
+			database.DB.Exec(context.Background(), "DELETE FROM users WHERE id = $1::uuid", user.ID)
+		}
+	})
+	return users
+}
+
+func TestConcurrentDemotionsKeepOneSuperadmin(t *testing.T) {
+	useTestDB(t)
+	repo := &UserRepository{}
+	users := createTestSuperadmins(t, repo, 2)
+
+	errs := make([]error, len(users))
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, user models.User) {
+			defer wg.Done()
+			user.Role = models.RoleDev
+			errs[i] = repo.Update(context.Background(), &user)
+		}(i, *user)
+	}
+	wg.Wait()
+
+	assertOneRejected(t, errs)
+}
+
+func TestConcurrentDeletesKeepOneSuperadmin(t *testing.T) {
+	useTestDB(t)
+	repo := &UserRepository{}
+	users := createTestSuperadmins(t, repo, 2)
+
+	errs := make([]error, len(users))
+	var wg sync.WaitGroup
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, id, reassignTo string) {
+			defer wg.Done()
+			errs[i] = repo.Delete(context.Background(), id, reassignTo)
+		}(i, user.ID, users[1-i].ID)
+	}
+	wg.Wait()
+
+	assertOneRejected(t, errs)
+}
+
+// assertOneRejected checks that exactly one of two concurrent changes removed a superadmin
+func assertOneRejected(t *testing.T, errs []error) {
+	t.Helper()
+	rejected := 0
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, ErrLastSuperadmin):
+			rejected++
+		case err != nil:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if rejected != 1 {
+		t.Errorf("%d of %d changes were rejected, want exactly 1", rejected, len(errs))
+	}
+}
+
+func TestDeleteUserWhoCreatedCredentials(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	repo := &UserRepository{}
+
+	secret := createTestSecret(t, &models.AWSCredentials{AccessKeyID: "AKIATEST", SecretAccessKey: "secret"})
+	creatorID := secret.CreatedBy
+	var requestID string
+	err := database.DB.QueryRow(ctx, `
+		INSERT INTO provision_requests (secret_id, user_id, resource_type, resource_name)
+		VALUES ($1::uuid, $2::uuid, 's3', 'delete-test-bucket')
+		RETURNING id
+	`, secret.ID, creatorID).Scan(&requestID)
+	if err != nil {
+		t.Fatalf("failed to create provision request: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM provision_requests WHERE id = $1::uuid", requestID)
+	})
+
+	admin := &models.User{
+		Name:  "Deleting Admin",
+		Email: fmt.Sprintf("deleting-admin-%d@test.invalid", time.Now().UnixNano()),
+		Role:  models.RoleDev,
+	}
+	if err := repo.Create(ctx, admin); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec(context.Background(), "DELETE FROM users WHERE id = $1::uuid", admin.ID)
+	})
+
+	if err := repo.Delete(ctx, creatorID, admin.ID); err != nil {
+		t.Fatalf("failed to delete the credential's creator: %v", err)
+	}
+
+	// The credential and the request outlive their creator
+	var createdBy, requestedBy *string
+	if err := database.DB.QueryRow(ctx, "SELECT created_by::text FROM secrets WHERE id = $1::uuid", secret.ID).Scan(&createdBy); err != nil {
+		t.Fatalf("credential was not kept: %v", err)
+	}
+	if err := database.DB.QueryRow(ctx, "SELECT user_id::text FROM provision_requests WHERE id = $1::uuid", requestID).Scan(&requestedBy); err != nil {
+		t.Fatalf("provision request was not kept: %v", err)
+	}
+	if createdBy != nil || requestedBy != nil {
+		t.Errorf("references to the deleted user remain: created_by %v, user_id %v", createdBy, requestedBy)
+	}
+}
@@ -0,0 +1,73 @@
+package scorecard
+
+import (
+	"context"
+	"strings"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// The default checks. More can be registered from other packages, e.g. on-call contacts.
+func init() {
+	Register(Check{
+		ID:    "owner_team",
+		Title: "Has an owner team",
+		Hint:  "Set owner on the service (or the project) in its catalog file to an existing team",
+		Run: func(_ context.Context, service *models.Service, _ *Sources) (bool, error) {
+			return service.Team != "", nil
+		},
+	})
+	Register(Check{
+		ID:    "repository",
+		Title: "Has a repository",
+		Hint:  "Set repository on the service in its catalog file",
+		Run: func(_ context.Context, service *models.Service, _ *Sources) (bool, error) {
+			return strings.TrimSpace(service.Repository) != "", nil
+		},
+	})
+	Register(Check{
+		ID:    "grafana_link",
+		Title: "Links a Grafana dashboard",
+		Hint:  "Add a link of type grafana to the service in its catalog file, or a Grafana link on the service page",
+		Run:   hasGrafanaLink,
+	})
+	Register(Check{
+		ID:    "argocd_app",
+		Title: "Deployed by an ArgoCD app",
+		Hint:  "Add an argocd entry per environment to the service in its catalog file, or link an ArgoCD app on the service page",
+		Run: func(ctx context.Context, service *models.Service, src *Sources) (bool, error) {
+			if service.ArgoCDAppName != "" {
+				return true, nil
+			}
+			apps, err := src.ArgoCDApps.GetByServiceID(ctx, service.ID)
+			return len(apps) > 0, err
+		},
+	})
+	Register(Check{
+		ID:    "cloud_resources",
+		Title: "Has mapped cloud resources",
+		Hint:  "Map the AWS resources the service uses on the Resources tab of the service page",
+		Run: func(ctx context.Context, service *models.Service, src *Sources) (bool, error) {
+			mappings, err := src.Mappings.GetByServiceID(ctx, service.ID)
+			return len(mappings) > 0, err
+		},
+	})
+}
+
+// hasGrafanaLink passes services with a Grafana URL from the catalog or any service
+// link pointing at Grafana
+func hasGrafanaLink(ctx context.Context, service *models.Service, src *Sources) (bool, error) {
+	if service.GrafanaURL != "" {
+		return true, nil
+	}
+	links, err := src.Links.GetByServiceID(ctx, service.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, link := range links {
+		if strings.Contains(strings.ToLower(link.URL), "grafana") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,238 @@
+// Package scorecard rates services against a set of catalog completeness checks
+// (owner team, repository, dashboards, deployments, cloud resources). Checks are
+// registered with Register, so new ones need no handler changes.
+package scorecard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// Check statuses
+const (
+	StatusPass  = "pass"
+	StatusFail  = "fail"
+	StatusError = "error" // The check could not be evaluated
+)
+
+// projectCacheTTL bounds how stale a project rollup may be; a rollup runs every
+// check for every service of the project
+const projectCacheTTL = 3 * time.Minute
+
+// projectConcurrency bounds how many services of a project are scored at once
+const projectConcurrency = 8
+
+// Sources are the repositories checks read from
+type Sources struct {
+	ArgoCDApps *repositories.ArgoCDRepository
+	Mappings   *repositories.ServiceResourceMappingRepository
+	Links      *repositories.ServiceLinkRepository
+}
+
+// Check is one readiness check. Run reports whether the service passes.
+type Check struct {
+	ID    string
+	Title string
+	Hint  string // How to make a failing service pass
+	Run   func(ctx context.Context, service *models.Service, src *Sources) (bool, error)
+}
+
+var (
+	checksMu    sync.RWMutex
+	checks      = map[string]Check{}
+	checksOrder []string
+)
+
+// Register adds a check to every scorecard; a check with the same ID is replaced
+func Register(c Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+
+	if _, exists := checks[c.ID]; !exists {
+		checksOrder = append(checksOrder, c.ID)
+	}
+	checks[c.ID] = c
+}
+
+// Checks returns all registered checks in registration order
+func Checks() []Check {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	list := make([]Check, 0, len(checksOrder))
+	for _, id := range checksOrder {
+		list = append(list, checks[id])
+	}
+	return list
+}
+
+// CheckResult is the outcome of one check for one service
+type CheckResult struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"` // pass, fail or error
+	Hint   string `json:"hint"`
+}
+
+// ServiceScorecard is the result of every check for a service
+type ServiceScorecard struct {
+	ServiceID   string        `json:"service_id"`
+	ServiceName string        `json:"service_name"`
+	Passed      int           `json:"passed"`
+	Total       int           `json:"total"`
+	Checks      []CheckResult `json:"checks"`
+}
+
+// CheckSummary counts the services of a project by their result for one check
+type CheckSummary struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Passed int    `json:"passed"`
+	Failed int    `json:"failed"` // Including services whose check errored
+}
+
+// ProjectScorecard rolls up the scorecards of every service of a project
+type ProjectScorecard struct {
+	ProjectID   string             `json:"project_id"`
+	Passed      int                `json:"passed"` // Passed checks over all services
+	Total       int                `json:"total"`
+	Checks      []CheckSummary     `json:"checks"`
+	Services    []ServiceScorecard `json:"services"`
+	GeneratedAt time.Time          `json:"generated_at"` // Rollups are cached for a few minutes
+}
+
+type projectEntry struct {
+	scorecard *ProjectScorecard // Never modified once cached
+	expiresAt time.Time
+}
+
+// Evaluator scores services with the registered checks and caches project rollups
+type Evaluator struct {
+	services *repositories.ServiceRepository
+	sources  *Sources
+
+	mu       sync.Mutex
+	projects map[string]projectEntry // By project ID
+}
+
+// NewEvaluator creates an evaluator reading from the given repositories
+func NewEvaluator(services *repositories.ServiceRepository, sources *Sources) *Evaluator {
+	return &Evaluator{
+		services: services,
+		sources:  sources,
+		projects: make(map[string]projectEntry),
+	}
+}
+
+// Service runs every registered check for a service. A check that fails to run is
+// reported with status error rather than failing the whole scorecard.
+func (e *Evaluator) Service(ctx context.Context, service *models.Service) *ServiceScorecard {
+	card := &ServiceScorecard{
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		Checks:      []CheckResult{},
+	}
+	for _, check := range Checks() {
+		result := CheckResult{ID: check.ID, Title: check.Title, Hint: check.Hint, Status: StatusFail}
+		passed, err := check.Run(ctx, service, e.sources)
+		switch {
+		case err != nil:
+			log.Printf("Scorecard check %s failed for service %s: %v", check.ID, service.ID, err)
+			result.Status = StatusError
+		case passed:
+			result.Status = StatusPass
+			card.Passed++
+		}
+		card.Total++
+		card.Checks = append(card.Checks, result)
+	}
+	return card
+}
+
+// Project scores every service of a project. Rollups are cached for projectCacheTTL.
+func (e *Evaluator) Project(ctx context.Context, projectID string) (*ProjectScorecard, error) {
+	if card := e.cached(projectID); card != nil {
+		return card, nil
+	}
+
+	projectServices, err := e.services.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services of project: %w", err)
+	}
+
+	cards := make([]ServiceScorecard, len(projectServices))
+	slots := make(chan struct{}, projectConcurrency)
+	var wg sync.WaitGroup
+	for i := range projectServices {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			cards[i] = *e.Service(ctx, &projectServices[i])
+		}(i)
+	}
+	wg.Wait()
+
+	card := &ProjectScorecard{
+		ProjectID:   projectID,
+		Checks:      []CheckSummary{},
+		Services:    cards,
+		GeneratedAt: time.Now(),
+	}
+	for _, check := range Checks() {
+		summary := CheckSummary{ID: check.ID, Title: check.Title}
+		for _, service := range cards {
+			for _, result := range service.Checks {
+				if result.ID != check.ID {
+					continue
+				}
+				if result.Status == StatusPass {
+					summary.Passed++
+				} else {
+					summary.Failed++
+				}
+			}
+		}
+		card.Checks = append(card.Checks, summary)
+	}
+	for _, service := range cards {
+		card.Passed += service.Passed
+		card.Total += service.Total
+	}
+
+	e.store(projectID, card)
+	return card, nil
+}
+
+// cached returns the cached rollup of a project, or nil if missing or expired
+func (e *Evaluator) cached(projectID string) *ProjectScorecard {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, ok := e.projects[projectID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return entry.scorecard
+}
+
+// store caches a rollup and drops expired entries so the cache only holds recently viewed projects
+func (e *Evaluator) store(projectID string, card *ProjectScorecard) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range e.projects {
+		if now.After(entry.expiresAt) {
+			delete(e.projects, id)
+		}
+	}
+	e.projects[projectID] = projectEntry{scorecard: card, expiresAt: now.Add(projectCacheTTL)}
+}
@@ -1,34 +1,88 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/portalight/backend/internal/logging"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrUnsupportedWorkloadKind is returned when restarting a kind other than Deployment or StatefulSet
+var ErrUnsupportedWorkloadKind = errors.New("only Deployment and StatefulSet workloads can be restarted")
+
+// ErrWorkloadNotManaged is returned when the workload is not one of the application's managed resources
+var ErrWorkloadNotManaged = errors.New("workload is not managed by this ArgoCD application")
+
+// restartableKinds maps the workload kinds that support a rolling restart to their API group
+var restartableKinds = map[string]string{
+	"Deployment":  "apps",
+	"StatefulSet": "apps",
+}
+
+const (
+	// defaultResourceTreeTimeout bounds the resource-tree call, which is slow for large apps
+	defaultResourceTreeTimeout = 2 * time.Minute
+	// defaultMaxPods caps the pods returned per application; each one costs a manifest request
+	defaultMaxPods = 200
+	// podManifestWorkers bounds the concurrent pod manifest requests of GetApplicationPods
+	podManifestWorkers = 8
 )
 
 // ArgoCDClient is a client for the ArgoCD API
 type ArgoCDClient struct {
-	baseURL string
-	token   string
-	client  *http.Client
+	baseURL    string
+	token      string
+	client     *http.Client
+	treeClient *http.Client // Longer timeout for resource trees
+	maxPods    int
 }
 
 // NewArgoCDClient creates a new ArgoCD client from environment variables
 func NewArgoCDClient() *ArgoCDClient {
+	treeTimeout := defaultResourceTreeTimeout
+	if value := os.Getenv("ARGOCD_RESOURCE_TREE_TIMEOUT"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			treeTimeout = d
+		} else {
+			log.Printf("⚠️  Invalid ARGOCD_RESOURCE_TREE_TIMEOUT %q, using %s", value, defaultResourceTreeTimeout)
+		}
+	}
+	maxPods := defaultMaxPods
+	if value := os.Getenv("ARGOCD_MAX_PODS"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			maxPods = n
+		} else {
+			log.Printf("⚠️  Invalid ARGOCD_MAX_PODS %q, using %d", value, defaultMaxPods)
+		}
+	}
+
 	return &ArgoCDClient{
 		baseURL: strings.TrimSuffix(os.Getenv("ARGOCD_SERVER_URL"), "/"),
 		token:   os.Getenv("ARGOCD_AUTH_TOKEN"),
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		treeClient: &http.Client{
+			Timeout: treeTimeout,
+		},
+		maxPods: maxPods,
 	}
 }
 
@@ -43,22 +97,37 @@ func (c *ArgoCDClient) GetBaseURL() string {
 }
 
 // doRequest performs an HTTP request to the ArgoCD API
-func (c *ArgoCDClient) doRequest(method, path string, body io.Reader) (*http.Response, error) {
+func (c *ArgoCDClient) doRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.doRequestWith(ctx, c.client, method, path, body)
+}
+
+// doRequestWith performs an HTTP request to the ArgoCD API with the given client
+func (c *ArgoCDClient) doRequestWith(ctx context.Context, client *http.Client, method, path string, body io.Reader) (*http.Response, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "ArgoCDClient.doRequest", trace.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("argocd.path", path),
+	))
+	defer span.End()
+
 	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
+	// Lets a proxy in front of ArgoCD log the portal request that caused the call
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
 
-	return c.client.Do(req)
+	return client.Do(req)
 }
 
 // ListApplications returns all ArgoCD applications
-func (c *ArgoCDClient) ListApplications() ([]models.ArgoCDApplication, error) {
-	resp, err := c.doRequest("GET", "/api/v1/applications", nil)
+func (c *ArgoCDClient) ListApplications(ctx context.Context) ([]models.ArgoCDApplication, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v1/applications", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list applications: %w", err)
 	}
@@ -112,8 +181,8 @@ func (c *ArgoCDClient) ListApplications() ([]models.ArgoCDApplication, error) {
 }
 
 // GetApplicationStatus returns the status of a specific application
-func (c *ArgoCDClient) GetApplicationStatus(appName string) (*models.ArgoCDApplication, error) {
-	resp, err := c.doRequest("GET", "/api/v1/applications/"+appName, nil)
+func (c *ArgoCDClient) GetApplicationStatus(ctx context.Context, appName string) (*models.ArgoCDApplication, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v1/applications/"+appName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get application: %w", err)
 	}
@@ -163,47 +232,129 @@ func (c *ArgoCDClient) GetApplicationStatus(appName string) (*models.ArgoCDAppli
 	}, nil
 }
 
-// GetApplicationPods returns all pods for an application
-func (c *ArgoCDClient) GetApplicationPods(appName string) ([]models.ArgoCDPod, error) {
+// resourceTreeNode is the part of a resource-tree node that GetApplicationPods uses
+type resourceTreeNode struct {
+	Kind       string        `json:"kind"`
+	Name       string        `json:"name"`
+	Namespace  string        `json:"namespace"`
+	ParentRefs []resourceRef `json:"parentRefs"`
+	Images     []string      `json:"images"`
+	Health     *struct {
+		Status string `json:"status"`
+	} `json:"health"`
+	Info []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"info"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// podOwnerKinds are the intermediate owners between a pod and its workload;
+// only their parents are kept while decoding a resource tree
+var podOwnerKinds = map[string]bool{
+	"ReplicaSet": true,
+	"Job":        true,
+}
+
+// decodePodNodes stream-decodes the nodes array of a resource tree, keeping at
+// most maxPods Pod nodes and the parents of pods and their intermediate owners.
+// Other nodes are decoded one at a time and dropped, so memory stays bounded by
+// the kept pods rather than the size of the tree. total counts every Pod node.
+func decodePodNodes(r io.Reader, maxPods int) (pods []resourceTreeNode, parents map[resourceRef][]resourceRef, total int, err error) {
+	parents = make(map[resourceRef][]resourceRef)
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, 0, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if key != "nodes" {
+			// Skip the value of other keys (orphanedNodes, hosts, ...)
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, nil, 0, err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, nil, 0, err
+		}
+		for dec.More() {
+			var node resourceTreeNode
+			if err := dec.Decode(&node); err != nil {
+				return nil, nil, 0, err
+			}
+			if node.Kind != "Pod" && !podOwnerKinds[node.Kind] {
+				continue
+			}
+			if node.Kind == "Pod" {
+				total++
+				if total > maxPods {
+					continue
+				}
+			}
+			ref := resourceRef{Kind: node.Kind, Name: node.Name, Namespace: node.Namespace}
+			if _, seen := parents[ref]; !seen {
+				for _, parent := range node.ParentRefs {
+					parents[ref] = append(parents[ref], resourceRef{Kind: parent.Kind, Name: parent.Name, Namespace: parent.Namespace})
+				}
+			}
+			if node.Kind == "Pod" {
+				node.ParentRefs = nil
+				pods = append(pods, node)
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	return pods, parents, total, nil
+}
+
+// expectDelim reads the next token and checks that it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+// GetApplicationPods returns the pods of an application, at most maxPods of
+// them; truncated reports whether the application has more. withResources adds
+// the resource requests and limits of each container from the pod manifests.
+func (c *ArgoCDClient) GetApplicationPods(ctx context.Context, appName string, withResources bool) (pods []models.ArgoCDPod, truncated bool, err error) {
 	// Get the resource tree which includes pods
-	resp, err := c.doRequest("GET", "/api/v1/applications/"+appName+"/resource-tree", nil)
+	resp, err := c.doRequestWith(ctx, c.treeClient, "GET", "/api/v1/applications/"+appName+"/resource-tree", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get resource tree: %w", err)
+		return nil, false, fmt.Errorf("failed to get resource tree: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
+		return nil, false, fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
 	}
 
-	var response struct {
-		Nodes []struct {
-			Kind      string   `json:"kind"`
-			Name      string   `json:"name"`
-			Namespace string   `json:"namespace"`
-			Images    []string `json:"images"`
-			Health    *struct {
-				Status string `json:"status"`
-			} `json:"health"`
-			Info []struct {
-				Name  string `json:"name"`
-				Value string `json:"value"`
-			} `json:"info"`
-			CreatedAt string `json:"createdAt"`
-		} `json:"nodes"`
+	nodes, parents, total, err := decodePodNodes(resp.Body, c.maxPods)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if total > len(nodes) {
+		logging.FromContext(ctx).Warn("⚠️  [ArgoCD] Pod list truncated", slog.String("app", appName), slog.Int("pods", total), slog.Int("returned", len(nodes)))
 	}
 
-	var pods []models.ArgoCDPod
-	for _, node := range response.Nodes {
-		if node.Kind != "Pod" {
-			continue
-		}
+	manifests := c.fetchPodManifests(ctx, appName, nodes)
 
+	for i, node := range nodes {
 		pod := models.ArgoCDPod{
 			Name:      node.Name,
 			Namespace: node.Namespace,
@@ -215,6 +366,8 @@ func (c *ArgoCDClient) GetApplicationPods(appName string) ([]models.ArgoCDPod, e
 			pod.Status = node.Health.Status
 		}
 
+		setPodWorkload(&pod, parents)
+
 		// Extract info fields from ArgoCD
 		for _, info := range node.Info {
 			switch info.Name {
@@ -234,43 +387,26 @@ func (c *ArgoCDClient) GetApplicationPods(appName string) ([]models.ArgoCDPod, e
 		}
 
 		// ALWAYS try to get containers from manifest first (most accurate)
-		manifest, err := c.GetResourceManifest(appName, node.Name, node.Namespace, "Pod")
-		if err != nil {
-			log.Printf("[DEBUG] Failed to get manifest for pod %s: %v", node.Name, err)
-		} else if manifest != "" {
-			var podManifest struct {
-				Spec struct {
-					Containers []struct {
-						Name string `json:"name"`
-					} `json:"containers"`
-					InitContainers []struct {
-						Name string `json:"name"`
-					} `json:"initContainers"`
-				} `json:"spec"`
-			}
-			if unmarshalErr := json.Unmarshal([]byte(manifest), &podManifest); unmarshalErr != nil {
-				log.Printf("[DEBUG] Failed to unmarshal manifest for pod %s: %v", node.Name, unmarshalErr)
-			} else {
-				log.Printf("[DEBUG] Pod %s has %d containers and %d initContainers", node.Name, len(podManifest.Spec.Containers), len(podManifest.Spec.InitContainers))
-				for _, container := range podManifest.Spec.Containers {
-					if container.Name != "" {
-						pod.Containers = append(pod.Containers, container.Name)
-						log.Printf("[DEBUG] Added container: %s", container.Name)
-					}
+		// Init containers are short-lived and typically don't need logs, so they are left out
+		if manifest := manifests[i]; manifest != nil {
+			for _, container := range manifest.Spec.Containers {
+				if container.Name == "" {
+					continue
+				}
+				pod.Containers = append(pod.Containers, container.Name)
+				if withResources {
+					pod.Resources = append(pod.Resources, models.ArgoCDContainerResources{
+						Container: container.Name,
+						Requests:  container.Resources.Requests,
+						Limits:    container.Resources.Limits,
+					})
 				}
-				// Note: InitContainers are short-lived, typically don't need logs
-				// But if we want to include them:
-				// for _, container := range podManifest.Spec.InitContainers {
-				//     if container.Name != "" {
-				//         pod.Containers = append(pod.Containers, container.Name)
-				//     }
-				// }
 			}
 		}
 
 		// Fallback to images if manifest parsing fails
 		if len(pod.Containers) == 0 && len(node.Images) > 0 {
-			log.Printf("[DEBUG] No containers from manifest for pod %s, falling back to images", node.Name)
+			logging.FromContext(ctx).Debug("No containers from manifest, falling back to images", slog.String("pod", node.Name))
 			for _, image := range node.Images {
 				// Extract container name from image
 				// Remove registry prefix and tag
@@ -291,7 +427,7 @@ func (c *ArgoCDClient) GetApplicationPods(appName string) ([]models.ArgoCDPod, e
 
 		// Final fallback - use a generic name
 		if len(pod.Containers) == 0 {
-			log.Printf("[DEBUG] No containers found for pod %s, using 'main'", node.Name)
+			logging.FromContext(ctx).Debug("No containers found, using 'main'", slog.String("pod", node.Name))
 			pod.Containers = []string{"main"}
 		}
 
@@ -305,20 +441,207 @@ func (c *ArgoCDClient) GetApplicationPods(appName string) ([]models.ArgoCDPod, e
 		pods = append(pods, pod)
 	}
 
-	return pods, nil
+	return pods, total > len(nodes), nil
+}
+
+// podManifest is the part of a pod manifest that GetApplicationPods and DescribePod use
+type podManifest struct {
+	Spec struct {
+		NodeName   string `json:"nodeName"`
+		Containers []struct {
+			Name      string `json:"name"`
+			Image     string `json:"image"`
+			Resources struct {
+				Requests map[string]string `json:"requests"`
+				Limits   map[string]string `json:"limits"`
+			} `json:"resources"`
+		} `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase      string `json:"phase"`
+		QOSClass   string `json:"qosClass"`
+		Conditions []struct {
+			Type               string `json:"type"`
+			Status             string `json:"status"`
+			Reason             string `json:"reason"`
+			Message            string `json:"message"`
+			LastTransitionTime string `json:"lastTransitionTime"`
+		} `json:"conditions"`
+		ContainerStatuses []struct {
+			Name         string         `json:"name"`
+			Image        string         `json:"image"`
+			Ready        bool           `json:"ready"`
+			RestartCount int            `json:"restartCount"`
+			State        containerState `json:"state"`
+			LastState    containerState `json:"lastState"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// containerState is a container's state in a pod status; at most one field is set
+type containerState struct {
+	Running *struct {
+		StartedAt string `json:"startedAt"`
+	} `json:"running"`
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Terminated *struct {
+		Reason     string `json:"reason"`
+		Message    string `json:"message"`
+		ExitCode   int    `json:"exitCode"`
+		FinishedAt string `json:"finishedAt"`
+	} `json:"terminated"`
+}
+
+// getPodManifest fetches and decodes a pod manifest
+func (c *ArgoCDClient) getPodManifest(ctx context.Context, appName, podName, namespace string) (*podManifest, error) {
+	raw, err := c.GetResourceManifest(ctx, appName, podName, namespace, "Pod")
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("empty manifest for pod %s", podName)
+	}
+	var manifest podManifest
+	if err := json.Unmarshal([]byte(raw), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pod manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// fetchPodManifests fetches the manifests of the pod nodes with podManifestWorkers
+// concurrent requests. A manifest that fails to load is left nil.
+func (c *ArgoCDClient) fetchPodManifests(ctx context.Context, appName string, nodes []resourceTreeNode) []*podManifest {
+	manifests := make([]*podManifest, len(nodes))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(podManifestWorkers, len(nodes)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				manifest, err := c.getPodManifest(ctx, appName, nodes[i].Name, nodes[i].Namespace)
+				if err != nil {
+					logging.FromContext(ctx).Debug("Failed to get pod manifest", slog.String("pod", nodes[i].Name), slog.Any("error", err))
+					continue
+				}
+				manifests[i] = manifest
+			}
+		}()
+	}
+	for i := range nodes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return manifests
+}
+
+// DescribePod returns the scheduling and container status details of a pod, much
+// like kubectl describe: its node, QoS class, conditions and why containers restarted
+func (c *ArgoCDClient) DescribePod(ctx context.Context, appName, podName, namespace string) (*models.ArgoCDPodDescription, error) {
+	manifest, err := c.getPodManifest(ctx, appName, podName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &models.ArgoCDPodDescription{
+		Name:       podName,
+		Namespace:  namespace,
+		Phase:      manifest.Status.Phase,
+		NodeName:   manifest.Spec.NodeName,
+		QOSClass:   manifest.Status.QOSClass,
+		Conditions: []models.ArgoCDPodCondition{},
+		Containers: []models.ArgoCDContainerStatus{},
+	}
+	for _, condition := range manifest.Status.Conditions {
+		description.Conditions = append(description.Conditions, models.ArgoCDPodCondition{
+			Type:               condition.Type,
+			Status:             condition.Status,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime,
+		})
+	}
+
+	// Statuses are missing until the pod is scheduled, so containers come from the spec
+	for _, container := range manifest.Spec.Containers {
+		status := models.ArgoCDContainerStatus{
+			Name:     container.Name,
+			Image:    container.Image,
+			State:    "waiting",
+			Requests: container.Resources.Requests,
+			Limits:   container.Resources.Limits,
+		}
+		for _, s := range manifest.Status.ContainerStatuses {
+			if s.Name != container.Name {
+				continue
+			}
+			status.Ready = s.Ready
+			status.RestartCount = s.RestartCount
+			switch {
+			case s.State.Running != nil:
+				status.State = "running"
+			case s.State.Terminated != nil:
+				status.State = "terminated"
+				status.StateReason = s.State.Terminated.Reason
+			case s.State.Waiting != nil:
+				status.StateReason = s.State.Waiting.Reason
+			}
+			if last := s.LastState.Terminated; last != nil {
+				exitCode := last.ExitCode
+				status.LastTerminationReason = last.Reason
+				status.LastTerminationMessage = last.Message
+				status.LastTerminationExitCode = &exitCode
+				status.LastTerminatedAt = last.FinishedAt
+			}
+		}
+		description.Containers = append(description.Containers, status)
+	}
+
+	return description, nil
+}
+
+// resourceRef identifies a Kubernetes resource in the ArgoCD resource tree
+type resourceRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// setPodWorkload follows a pod's owners (Pod -> ReplicaSet -> Deployment, or Pod -> StatefulSet)
+// and records the top-most one as its workload
+func setPodWorkload(pod *models.ArgoCDPod, parents map[resourceRef][]resourceRef) {
+	ref := resourceRef{Kind: "Pod", Name: pod.Name, Namespace: pod.Namespace}
+	for depth := 0; depth < 3; depth++ {
+		owners := parents[ref]
+		if len(owners) == 0 {
+			return
+		}
+		ref = owners[0]
+		if ref.Kind == "ReplicaSet" {
+			pod.ReplicaSet = ref.Name
+		}
+		pod.WorkloadKind = ref.Kind
+		pod.Workload = ref.Name
+	}
 }
 
 // GetResourceManifest returns the manifest of a specific resource
-func (c *ArgoCDClient) GetResourceManifest(appName, name, namespace, kind string) (string, error) {
+func (c *ArgoCDClient) GetResourceManifest(ctx context.Context, appName, name, namespace, kind string) (string, error) {
 	// For core resources (Pod, Service, etc), group is empty
 	// For custom resources, group would be something like "apps" or "networking.k8s.io"
 	// ArgoCD API expects group to be explicitly specified (empty string for core API)
 	path := fmt.Sprintf("/api/v1/applications/%s/resource?name=%s&namespace=%s&resourceName=%s&kind=%s&version=v1&group=",
 		appName, name, namespace, name, kind)
 
-	log.Printf("[DEBUG] Fetching manifest from: %s", path)
+	logging.FromContext(ctx).Debug("Fetching manifest", slog.String("path", path))
 
-	resp, err := c.doRequest("GET", path, nil)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get resource manifest: %w", err)
 	}
@@ -326,7 +649,7 @@ func (c *ArgoCDClient) GetResourceManifest(appName, name, namespace, kind string
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("[DEBUG] Manifest API error response: %s", string(body))
+		logging.FromContext(ctx).Debug("Manifest API error response", slog.String("body", string(body)))
 		return "", fmt.Errorf("ArgoCD API error: %s", resp.Status)
 	}
 
@@ -341,11 +664,11 @@ func (c *ArgoCDClient) GetResourceManifest(appName, name, namespace, kind string
 }
 
 // GetPodLogs returns logs for a specific pod
-func (c *ArgoCDClient) GetPodLogs(appName, podName, namespace, container string, tailLines int) (string, error) {
+func (c *ArgoCDClient) GetPodLogs(ctx context.Context, appName, podName, namespace, container string, tailLines int) (string, error) {
 	path := fmt.Sprintf("/api/v1/applications/%s/pods/%s/logs?namespace=%s&container=%s&tailLines=%d",
 		appName, podName, namespace, container, tailLines)
 
-	resp, err := c.doRequest("GET", path, nil)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get pod logs: %w", err)
 	}
@@ -378,14 +701,14 @@ func (c *ArgoCDClient) GetPodLogs(appName, podName, namespace, container string,
 }
 
 // DeletePod deletes a specific pod
-func (c *ArgoCDClient) DeletePod(appName, podName, namespace string) error {
+func (c *ArgoCDClient) DeletePod(ctx context.Context, appName, podName, namespace string) error {
 	// ArgoCD requires resourceName and group parameters
 	path := fmt.Sprintf("/api/v1/applications/%s/resource?name=%s&namespace=%s&resourceName=%s&kind=Pod&version=v1&group=",
 		appName, podName, namespace, podName)
 
-	log.Printf("[DEBUG] Deleting pod via: %s", path)
+	logging.FromContext(ctx).Debug("Deleting pod", slog.String("path", path))
 
-	resp, err := c.doRequest("DELETE", path, nil)
+	resp, err := c.doRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete pod: %w", err)
 	}
@@ -393,19 +716,115 @@ func (c *ArgoCDClient) DeletePod(appName, podName, namespace string) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Failed to delete pod: ArgoCD API error: %s - %s", resp.Status, string(body))
+		logging.FromContext(ctx).Error("Failed to delete pod: ArgoCD API error", slog.String("status", resp.Status), slog.String("body", string(body)))
 		return fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
 	}
 
-	log.Printf("[DEBUG] Pod %s deleted successfully", podName)
+	logging.FromContext(ctx).Debug("Pod deleted", slog.String("pod", podName))
 	return nil
 }
 
+// RestartWorkload performs a rolling restart of a Deployment or StatefulSet, like
+// `kubectl rollout restart`, by patching the kubectl.kubernetes.io/restartedAt
+// annotation of its pod template. An empty namespace is taken from the application's
+// managed resources. Returns the namespace the workload was restarted in.
+func (c *ArgoCDClient) RestartWorkload(ctx context.Context, appName, kind, name, namespace string) (string, error) {
+	group, ok := restartableKinds[kind]
+	if !ok {
+		return "", ErrUnsupportedWorkloadKind
+	}
+
+	managed, err := c.getManagedResources(ctx, appName)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, res := range managed {
+		if res.Kind == kind && res.Name == name && (namespace == "" || res.Namespace == namespace) {
+			namespace = res.Namespace
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", ErrWorkloadNotManaged
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						"kubectl.kubernetes.io/restartedAt": time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	// The patch endpoint takes the patch document as a JSON string
+	body, err := json.Marshal(string(patch))
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("/api/v1/applications/%s/resource?name=%s&namespace=%s&resourceName=%s&kind=%s&version=v1&group=%s&patchType=%s",
+		appName, appName, url.QueryEscape(namespace), url.QueryEscape(name), kind, group, url.QueryEscape("application/merge-patch+json"))
+
+	resp, err := c.doRequest(ctx, "POST", path, strings.NewReader(string(body)))
+	if err != nil {
+		return "", fmt.Errorf("failed to restart workload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(respBody))
+	}
+
+	return namespace, nil
+}
+
+// getManagedResources returns the resources an application manages directly (not their children)
+func (c *ArgoCDClient) getManagedResources(ctx context.Context, appName string) ([]resourceRef, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v1/applications/"+appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("application not found: %s", appName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
+	}
+
+	var response struct {
+		Status struct {
+			Resources []resourceRef `json:"resources"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Status.Resources, nil
+}
+
 // SyncApplication triggers a sync for an application
-func (c *ArgoCDClient) SyncApplication(appName string) error {
+func (c *ArgoCDClient) SyncApplication(ctx context.Context, appName string, opts models.ArgoCDSyncOptions) error {
 	path := fmt.Sprintf("/api/v1/applications/%s/sync", appName)
 
-	resp, err := c.doRequest("POST", path, strings.NewReader("{}"))
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, "POST", path, strings.NewReader(string(body)))
 	if err != nil {
 		return fmt.Errorf("failed to sync application: %w", err)
 	}
@@ -419,6 +838,125 @@ func (c *ArgoCDClient) SyncApplication(appName string) error {
 	return nil
 }
 
+// GetOperationState returns the state of the application's current or last sync
+// operation, or nil if it has never been synced
+func (c *ArgoCDClient) GetOperationState(ctx context.Context, appName string) (*models.ArgoCDOperationState, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v1/applications/"+appName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("application not found: %s", appName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
+	}
+
+	var response struct {
+		Status struct {
+			OperationState *struct {
+				Phase      string `json:"phase"`
+				Message    string `json:"message"`
+				StartedAt  string `json:"startedAt"`
+				FinishedAt string `json:"finishedAt"`
+				SyncResult struct {
+					Revision  string `json:"revision"`
+					Resources []struct {
+						Status string `json:"status"` // Synced, SyncFailed, Pruned, PruneSkipped
+					} `json:"resources"`
+				} `json:"syncResult"`
+			} `json:"operationState"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	op := response.Status.OperationState
+	if op == nil {
+		return nil, nil
+	}
+	state := &models.ArgoCDOperationState{
+		Phase:          op.Phase,
+		Message:        op.Message,
+		Revision:       op.SyncResult.Revision,
+		StartedAt:      op.StartedAt,
+		FinishedAt:     op.FinishedAt,
+		ResourcesTotal: len(op.SyncResult.Resources),
+	}
+	for _, res := range op.SyncResult.Resources {
+		switch res.Status {
+		case "Synced", "Pruned":
+			state.ResourcesSynced++
+		case "SyncFailed":
+			state.ResourcesFailed++
+		}
+	}
+	return state, nil
+}
+
+// GetApplicationEvents returns the Kubernetes events of an application and its
+// resources, most recent first
+func (c *ArgoCDClient) GetApplicationEvents(ctx context.Context, appName string) ([]models.ArgoCDEvent, error) {
+	resp, err := c.doRequest(ctx, "GET", "/api/v1/applications/"+appName+"/events", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("application not found: %s", appName)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ArgoCD API error: %s - %s", resp.Status, string(body))
+	}
+
+	var response struct {
+		Items []struct {
+			Type           string `json:"type"`
+			Reason         string `json:"reason"`
+			Message        string `json:"message"`
+			Count          int    `json:"count"`
+			FirstTimestamp string `json:"firstTimestamp"`
+			LastTimestamp  string `json:"lastTimestamp"`
+			EventTime      string `json:"eventTime"` // Set instead of the timestamps by newer event sources
+			InvolvedObject struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"involvedObject"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	events := make([]models.ArgoCDEvent, len(response.Items))
+	for i, item := range response.Items {
+		events[i] = models.ArgoCDEvent{
+			Type:           item.Type,
+			Reason:         item.Reason,
+			Message:        item.Message,
+			Count:          item.Count,
+			ObjectKind:     item.InvolvedObject.Kind,
+			ObjectName:     item.InvolvedObject.Name,
+			FirstTimestamp: item.FirstTimestamp,
+			LastTimestamp:  item.LastTimestamp,
+		}
+		if events[i].LastTimestamp == "" {
+			events[i].LastTimestamp = item.EventTime
+		}
+	}
+	// RFC 3339 timestamps in UTC sort as strings
+	sort.SliceStable(events, func(a, b int) bool {
+		return events[a].LastTimestamp > events[b].LastTimestamp
+	})
+	return events, nil
+}
+
 // formatDuration formats a duration into a human-readable string
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
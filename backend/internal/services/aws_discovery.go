@@ -3,28 +3,56 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudformation/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DiscoveredResource represents an AWS resource discovered via API
 type DiscoveredResource struct {
 	ARN          string                 `json:"arn"`
-	Type         string                 `json:"type"` // s3, sqs, sns, rds, lambda
+	Type         string                 `json:"type"` // s3, sqs, sns, rds, lambda, eks, cloudformation, kinesis, eventbridge
 	Name         string                 `json:"name"`
 	Region       string                 `json:"region"`
 	Status       string                 `json:"status"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	Tags         map[string]string      `json:"tags,omitempty"`
 	DiscoveredAt time.Time              `json:"discovered_at"`
+
+	// SuggestedProjectID comes from the portalight:project-id or portalight:project tag, if present
+	SuggestedProjectID string `json:"suggested_project_id,omitempty"`
+
+	// ManagedBy is terraform when the tags show infrastructure as code manages the resource
+	ManagedBy string `json:"managed_by,omitempty"`
+}
+
+// applyTags records resource tags and derives the suggested project and manager
+func (r *DiscoveredResource) applyTags(tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	r.Tags = tags
+	r.SuggestedProjectID = ProjectIDFromTags(tags)
+	r.ManagedBy = ManagedByFromTags(tags)
 }
 
 // AWSDiscovery handles discovering existing AWS resources
@@ -53,8 +81,9 @@ func (d *AWSDiscovery) createConfig(ctx context.Context, creds *models.AWSCreden
 func (d *AWSDiscovery) DiscoverAll(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
 	var allResources []DiscoveredResource
 
-	// Discover S3 buckets (global, but we still need a region for the API call)
-	s3Resources, err := d.DiscoverS3(ctx, creds, region)
+	// Discover S3 buckets of every region: ListBuckets is global, and callers
+	// checking whether a resource still exists must not miss buckets elsewhere
+	s3Resources, err := d.DiscoverS3(ctx, creds, region, true)
 	if err == nil {
 		allResources = append(allResources, s3Resources...)
 	}
@@ -83,11 +112,67 @@ func (d *AWSDiscovery) DiscoverAll(ctx context.Context, creds *models.AWSCredent
 		allResources = append(allResources, lambdaResources...)
 	}
 
+	// Discover EKS clusters
+	eksResources, err := d.DiscoverEKS(ctx, creds, region)
+	if err == nil {
+		allResources = append(allResources, eksResources...)
+	}
+
+	// Discover CloudFormation stacks
+	stackResources, err := d.DiscoverCloudFormation(ctx, creds, region)
+	if err == nil {
+		allResources = append(allResources, stackResources...)
+	}
+
+	// Discover Kinesis streams
+	kinesisResources, err := d.DiscoverKinesis(ctx, creds, region)
+	if err == nil {
+		allResources = append(allResources, kinesisResources...)
+	}
+
+	// Discover EventBridge rules
+	ruleResources, err := d.DiscoverEventBridge(ctx, creds, region)
+	if err == nil {
+		allResources = append(allResources, ruleResources...)
+	}
+
 	return allResources, nil
 }
 
-// DiscoverS3 discovers S3 buckets
-func (d *AWSDiscovery) DiscoverS3(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+// DiscoverType runs the discovery of a single resource type (s3, sqs, sns, rds, lambda,
+// eks, cloudformation, kinesis or eventbridge) in region; S3 buckets of every region
+// are returned
+func (d *AWSDiscovery) DiscoverType(ctx context.Context, creds *models.AWSCredentials, resourceType, region string) ([]DiscoveredResource, error) {
+	switch resourceType {
+	case "s3":
+		return d.DiscoverS3(ctx, creds, region, true)
+	case "sqs":
+		return d.DiscoverSQS(ctx, creds, region)
+	case "sns":
+		return d.DiscoverSNS(ctx, creds, region)
+	case "rds":
+		return d.DiscoverRDS(ctx, creds, region)
+	case "lambda":
+		return d.DiscoverLambda(ctx, creds, region)
+	case "eks":
+		return d.DiscoverEKS(ctx, creds, region)
+	case "cloudformation":
+		return d.DiscoverCloudFormation(ctx, creds, region)
+	case "kinesis":
+		return d.DiscoverKinesis(ctx, creds, region)
+	case "eventbridge":
+		return d.DiscoverEventBridge(ctx, creds, region)
+	}
+	return nil, fmt.Errorf("discovery of %s resources is not supported", resourceType)
+}
+
+// DiscoverS3 discovers the S3 buckets in region. ListBuckets is global, so each
+// bucket's region is looked up; with includeAllRegions buckets of every region are
+// returned, each with its own region.
+func (d *AWSDiscovery) DiscoverS3(ctx context.Context, creds *models.AWSCredentials, region string, includeAllRegions bool) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverS3", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
 	cfg, err := d.createConfig(ctx, creds, region)
 	if err != nil {
 		return nil, err
@@ -101,22 +186,61 @@ func (d *AWSDiscovery) DiscoverS3(ctx context.Context, creds *models.AWSCredenti
 
 	var resources []DiscoveredResource
 	for _, bucket := range result.Buckets {
-		resources = append(resources, DiscoveredResource{
+		location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get location of S3 bucket %s: %w", aws.ToString(bucket.Name), err)
+		}
+		bucketRegion := s3BucketRegion(location.LocationConstraint)
+		if bucketRegion != region && !includeAllRegions {
+			continue
+		}
+
+		resource := DiscoveredResource{
 			ARN:          fmt.Sprintf("arn:aws:s3:::%s", *bucket.Name),
 			Type:         "s3",
 			Name:         *bucket.Name,
-			Region:       region, // S3 buckets are regional but ListBuckets is global
+			Region:       bucketRegion,
 			Status:       "active",
 			Metadata:     map[string]interface{}{"created": bucket.CreationDate},
 			DiscoveredAt: time.Now(),
+		}
+
+		// Untagged buckets return NoSuchTagSet; tags are best-effort
+		tagging, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: bucket.Name}, func(o *s3.Options) {
+			o.Region = bucketRegion
 		})
+		if err == nil {
+			tags := make(map[string]string, len(tagging.TagSet))
+			for _, tag := range tagging.TagSet {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			resource.applyTags(tags)
+		}
+
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
 }
 
+// s3BucketRegion maps a GetBucketLocation constraint to a region: us-east-1 buckets
+// have none and the legacy "EU" constraint means eu-west-1
+func s3BucketRegion(constraint s3types.BucketLocationConstraint) string {
+	switch constraint {
+	case "":
+		return "us-east-1"
+	case s3types.BucketLocationConstraintEu:
+		return "eu-west-1"
+	default:
+		return string(constraint)
+	}
+}
+
 // DiscoverSQS discovers SQS queues
 func (d *AWSDiscovery) DiscoverSQS(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverSQS", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
 	cfg, err := d.createConfig(ctx, creds, region)
 	if err != nil {
 		return nil, err
@@ -139,15 +263,29 @@ func (d *AWSDiscovery) DiscoverSQS(ctx context.Context, creds *models.AWSCredent
 			}
 		}
 
-		resources = append(resources, DiscoveredResource{
-			ARN:          fmt.Sprintf("arn:aws:sqs:%s:*:%s", region, name),
+		attrs, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueUrl),
+			AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ARN of SQS queue %s: %w", name, err)
+		}
+
+		resource := DiscoveredResource{
+			ARN:          attrs.Attributes[string(sqstypes.QueueAttributeNameQueueArn)],
 			Type:         "sqs",
 			Name:         name,
 			Region:       region,
 			Status:       "active",
 			Metadata:     map[string]interface{}{"queue_url": queueUrl},
 			DiscoveredAt: time.Now(),
-		})
+		}
+
+		if tagResult, err := client.ListQueueTags(ctx, &sqs.ListQueueTagsInput{QueueUrl: aws.String(queueUrl)}); err == nil {
+			resource.applyTags(tagResult.Tags)
+		}
+
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
@@ -155,6 +293,9 @@ func (d *AWSDiscovery) DiscoverSQS(ctx context.Context, creds *models.AWSCredent
 
 // DiscoverSNS discovers SNS topics
 func (d *AWSDiscovery) DiscoverSNS(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverSNS", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
 	cfg, err := d.createConfig(ctx, creds, region)
 	if err != nil {
 		return nil, err
@@ -178,7 +319,7 @@ func (d *AWSDiscovery) DiscoverSNS(ctx context.Context, creds *models.AWSCredent
 			}
 		}
 
-		resources = append(resources, DiscoveredResource{
+		resource := DiscoveredResource{
 			ARN:          arn,
 			Type:         "sns",
 			Name:         name,
@@ -186,7 +327,17 @@ func (d *AWSDiscovery) DiscoverSNS(ctx context.Context, creds *models.AWSCredent
 			Status:       "active",
 			Metadata:     map[string]interface{}{},
 			DiscoveredAt: time.Now(),
-		})
+		}
+
+		if tagResult, err := client.ListTagsForResource(ctx, &sns.ListTagsForResourceInput{ResourceArn: topic.TopicArn}); err == nil {
+			tags := make(map[string]string, len(tagResult.Tags))
+			for _, tag := range tagResult.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			resource.applyTags(tags)
+		}
+
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
@@ -194,6 +345,9 @@ func (d *AWSDiscovery) DiscoverSNS(ctx context.Context, creds *models.AWSCredent
 
 // DiscoverRDS discovers RDS instances
 func (d *AWSDiscovery) DiscoverRDS(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverRDS", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
 	cfg, err := d.createConfig(ctx, creds, region)
 	if err != nil {
 		return nil, err
@@ -220,7 +374,7 @@ func (d *AWSDiscovery) DiscoverRDS(ctx context.Context, creds *models.AWSCredent
 			"multi_az":       db.MultiAZ,
 		}
 
-		resources = append(resources, DiscoveredResource{
+		resource := DiscoveredResource{
 			ARN:          aws.ToString(db.DBInstanceArn),
 			Type:         "rds",
 			Name:         aws.ToString(db.DBInstanceIdentifier),
@@ -228,7 +382,14 @@ func (d *AWSDiscovery) DiscoverRDS(ctx context.Context, creds *models.AWSCredent
 			Status:       status,
 			Metadata:     metadata,
 			DiscoveredAt: time.Now(),
-		})
+		}
+		tags := make(map[string]string, len(db.TagList))
+		for _, tag := range db.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		resource.applyTags(tags)
+
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
@@ -236,6 +397,9 @@ func (d *AWSDiscovery) DiscoverRDS(ctx context.Context, creds *models.AWSCredent
 
 // DiscoverLambda discovers Lambda functions
 func (d *AWSDiscovery) DiscoverLambda(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverLambda", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
 	cfg, err := d.createConfig(ctx, creds, region)
 	if err != nil {
 		return nil, err
@@ -256,7 +420,7 @@ func (d *AWSDiscovery) DiscoverLambda(ctx context.Context, creds *models.AWSCred
 			"handler":     aws.ToString(fn.Handler),
 		}
 
-		resources = append(resources, DiscoveredResource{
+		resource := DiscoveredResource{
 			ARN:          aws.ToString(fn.FunctionArn),
 			Type:         "lambda",
 			Name:         aws.ToString(fn.FunctionName),
@@ -264,8 +428,304 @@ func (d *AWSDiscovery) DiscoverLambda(ctx context.Context, creds *models.AWSCred
 			Status:       "active",
 			Metadata:     metadata,
 			DiscoveredAt: time.Now(),
+		}
+
+		// ListFunctions does not return tags; they are best-effort
+		if tagResult, err := client.ListTags(ctx, &lambda.ListTagsInput{Resource: fn.FunctionArn}); err == nil {
+			resource.applyTags(tagResult.Tags)
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// DiscoverEKS discovers EKS clusters with their version, endpoint and managed node group count
+func (d *AWSDiscovery) DiscoverEKS(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverEKS", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
+	cfg, err := d.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newEKSClient(cfg)
+	names, err := client.ListClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EKS clusters: %w", err)
+	}
+
+	var resources []DiscoveredResource
+	for _, name := range names {
+		cluster, err := client.DescribeCluster(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EKS cluster %s: %w", name, err)
+		}
+
+		metadata := map[string]interface{}{
+			"version":          cluster.Version,
+			"platform_version": cluster.PlatformVersion,
+			"endpoint":         cluster.Endpoint,
+			"role_arn":         cluster.RoleARN,
+		}
+		// A cluster without node group access (e.g. Fargate-only IAM policies) is still listed
+		if nodegroups, err := client.ListNodegroups(ctx, name); err == nil {
+			metadata["nodegroup_count"] = len(nodegroups)
+		}
+
+		resources = append(resources, DiscoveredResource{
+			ARN:          cluster.ARN,
+			Type:         "eks",
+			Name:         cluster.Name,
+			Region:       region,
+			Status:       strings.ToLower(cluster.Status),
+			Metadata:     metadata,
+			DiscoveredAt: time.Now(),
 		})
 	}
 
 	return resources, nil
 }
+
+// cloudFormationStackStatuses are the stack states worth listing: stacks that are
+// deployed, including those whose creation was rolled back
+var cloudFormationStackStatuses = []cftypes.StackStatus{
+	cftypes.StackStatusCreateComplete,
+	cftypes.StackStatusUpdateComplete,
+	cftypes.StackStatusRollbackComplete,
+}
+
+// DiscoverCloudFormation discovers CloudFormation stacks with their status, timestamps,
+// tags and outputs
+func (d *AWSDiscovery) DiscoverCloudFormation(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverCloudFormation", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
+	cfg, err := d.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := cloudformation.NewFromConfig(cfg)
+	paginator := cloudformation.NewListStacksPaginator(client, &cloudformation.ListStacksInput{
+		StackStatusFilter: cloudFormationStackStatuses,
+	})
+
+	var resources []DiscoveredResource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list CloudFormation stacks: %w", err)
+		}
+
+		for _, summary := range page.StackSummaries {
+			described, err := client.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{StackName: summary.StackId})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe CloudFormation stack %s: %w", aws.ToString(summary.StackName), err)
+			}
+			for _, stack := range described.Stacks {
+				resources = append(resources, cloudFormationResource(stack, region))
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// cloudFormationResource maps a described stack to a discovered resource
+func cloudFormationResource(stack cftypes.Stack, region string) DiscoveredResource {
+	outputs := make(map[string]string, len(stack.Outputs))
+	for _, output := range stack.Outputs {
+		outputs[aws.ToString(output.OutputKey)] = aws.ToString(output.OutputValue)
+	}
+
+	metadata := map[string]interface{}{
+		"stack_status": string(stack.StackStatus),
+		"outputs":      outputs,
+	}
+	if stack.CreationTime != nil {
+		metadata["created_at"] = stack.CreationTime.Format(time.RFC3339)
+	}
+	if stack.LastUpdatedTime != nil {
+		metadata["last_updated_at"] = stack.LastUpdatedTime.Format(time.RFC3339)
+	}
+	if stack.Description != nil {
+		metadata["description"] = aws.ToString(stack.Description)
+	}
+
+	resource := DiscoveredResource{
+		ARN:          aws.ToString(stack.StackId), // Stack IDs are the stack ARNs
+		Type:         "cloudformation",
+		Name:         aws.ToString(stack.StackName),
+		Region:       region,
+		Status:       "active",
+		Metadata:     metadata,
+		DiscoveredAt: time.Now(),
+	}
+
+	tags := make(map[string]string, len(stack.Tags))
+	for _, tag := range stack.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	resource.applyTags(tags)
+
+	return resource
+}
+
+// DiscoverKinesis discovers Kinesis data streams with their shard count, retention
+// and capacity mode
+func (d *AWSDiscovery) DiscoverKinesis(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverKinesis", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
+	cfg, err := d.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := kinesis.NewFromConfig(cfg)
+	paginator := kinesis.NewListStreamsPaginator(client, &kinesis.ListStreamsInput{})
+
+	var resources []DiscoveredResource
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Kinesis streams: %w", err)
+		}
+
+		for _, stream := range page.StreamSummaries {
+			summary, err := client.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{StreamARN: stream.StreamARN})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe Kinesis stream %s: %w", aws.ToString(stream.StreamName), err)
+			}
+			description := summary.StreamDescriptionSummary
+
+			metadata := map[string]interface{}{
+				"stream_status":   string(description.StreamStatus),
+				"shard_count":     aws.ToInt32(description.OpenShardCount),
+				"retention_hours": aws.ToInt32(description.RetentionPeriodHours),
+				"encryption_type": string(description.EncryptionType),
+			}
+			if description.StreamModeDetails != nil {
+				metadata["stream_mode"] = string(description.StreamModeDetails.StreamMode) // PROVISIONED or ON_DEMAND
+			}
+			if description.StreamCreationTimestamp != nil {
+				metadata["created_at"] = description.StreamCreationTimestamp.Format(time.RFC3339)
+			}
+
+			resource := DiscoveredResource{
+				ARN:          aws.ToString(stream.StreamARN),
+				Type:         "kinesis",
+				Name:         aws.ToString(stream.StreamName),
+				Region:       region,
+				Status:       "active",
+				Metadata:     metadata,
+				DiscoveredAt: time.Now(),
+			}
+
+			if tagResult, err := client.ListTagsForStream(ctx, &kinesis.ListTagsForStreamInput{StreamARN: stream.StreamARN}); err == nil {
+				tags := make(map[string]string, len(tagResult.Tags))
+				for _, tag := range tagResult.Tags {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+				resource.applyTags(tags)
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// DiscoverEventBridge discovers the EventBridge rules of every event bus, with their
+// schedule or event pattern
+func (d *AWSDiscovery) DiscoverEventBridge(ctx context.Context, creds *models.AWSCredentials, region string) ([]DiscoveredResource, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "AWSDiscovery.DiscoverEventBridge", trace.WithAttributes(attribute.String("aws.region", region)))
+	defer span.End()
+
+	cfg, err := d.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := eventbridge.NewFromConfig(cfg)
+
+	// The SDK has no paginators for these calls
+	var buses []string
+	busInput := &eventbridge.ListEventBusesInput{}
+	for {
+		page, err := client.ListEventBuses(ctx, busInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EventBridge event buses: %w", err)
+		}
+		for _, bus := range page.EventBuses {
+			buses = append(buses, aws.ToString(bus.Name))
+		}
+		if page.NextToken == nil {
+			break
+		}
+		busInput.NextToken = page.NextToken
+	}
+
+	var resources []DiscoveredResource
+	for _, bus := range buses {
+		ruleInput := &eventbridge.ListRulesInput{EventBusName: aws.String(bus)}
+		for {
+			page, err := client.ListRules(ctx, ruleInput)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list EventBridge rules of bus %s: %w", bus, err)
+			}
+			for _, rule := range page.Rules {
+				resource := eventBridgeResource(rule, region)
+				if tagResult, err := client.ListTagsForResource(ctx, &eventbridge.ListTagsForResourceInput{ResourceARN: rule.Arn}); err == nil {
+					tags := make(map[string]string, len(tagResult.Tags))
+					for _, tag := range tagResult.Tags {
+						tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+					}
+					resource.applyTags(tags)
+				}
+				resources = append(resources, resource)
+			}
+			if page.NextToken == nil {
+				break
+			}
+			ruleInput.NextToken = page.NextToken
+		}
+	}
+
+	return resources, nil
+}
+
+// eventBridgeResource maps a rule to a discovered resource; a rule has either a
+// schedule or an event pattern
+func eventBridgeResource(rule ebtypes.Rule, region string) DiscoveredResource {
+	metadata := map[string]interface{}{
+		"event_bus": aws.ToString(rule.EventBusName),
+		"state":     string(rule.State), // ENABLED or DISABLED
+	}
+	if rule.ScheduleExpression != nil {
+		metadata["schedule_expression"] = aws.ToString(rule.ScheduleExpression)
+	}
+	if rule.EventPattern != nil {
+		metadata["event_pattern"] = aws.ToString(rule.EventPattern)
+	}
+	if rule.Description != nil {
+		metadata["description"] = aws.ToString(rule.Description)
+	}
+	if rule.ManagedBy != nil {
+		metadata["managed_by"] = aws.ToString(rule.ManagedBy) // Rules AWS services created for themselves
+	}
+
+	return DiscoveredResource{
+		ARN:          aws.ToString(rule.Arn),
+		Type:         "eventbridge",
+		Name:         aws.ToString(rule.Name),
+		Region:       region,
+		Status:       "active",
+		Metadata:     metadata,
+		DiscoveredAt: time.Now(),
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body, as SigV4 expects it
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// eksClient calls the read-only EKS control-plane API over REST, signed with SigV4.
+// Discovery needs three GET calls, which does not warrant another SDK module.
+type eksClient struct {
+	cfg        aws.Config
+	signer     *v4.Signer
+	httpClient *http.Client
+}
+
+func newEKSClient(cfg aws.Config) *eksClient {
+	return &eksClient{
+		cfg:        cfg,
+		signer:     v4.NewSigner(),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// eksCluster is the part of the DescribeCluster response discovery uses
+type eksCluster struct {
+	Name            string `json:"name"`
+	ARN             string `json:"arn"`
+	Status          string `json:"status"` // CREATING, ACTIVE, DELETING, FAILED, UPDATING, PENDING
+	Version         string `json:"version"`
+	Endpoint        string `json:"endpoint"`
+	RoleARN         string `json:"roleArn"`
+	PlatformVersion string `json:"platformVersion"`
+}
+
+// ListClusters returns the names of every cluster in the region
+func (c *eksClient) ListClusters(ctx context.Context) ([]string, error) {
+	var names []string
+	err := c.paginate(ctx, "/clusters", func(body []byte) (string, error) {
+		var page struct {
+			Clusters  []string `json:"clusters"`
+			NextToken string   `json:"nextToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		names = append(names, page.Clusters...)
+		return page.NextToken, nil
+	})
+	return names, err
+}
+
+// DescribeCluster returns a cluster's details
+func (c *eksClient) DescribeCluster(ctx context.Context, name string) (*eksCluster, error) {
+	body, err := c.get(ctx, "/clusters/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Cluster eksCluster `json:"cluster"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode cluster %s: %w", name, err)
+	}
+	return &result.Cluster, nil
+}
+
+// ListNodegroups returns the names of a cluster's managed node groups
+func (c *eksClient) ListNodegroups(ctx context.Context, clusterName string) ([]string, error) {
+	var names []string
+	err := c.paginate(ctx, "/clusters/"+url.PathEscape(clusterName)+"/node-groups", func(body []byte) (string, error) {
+		var page struct {
+			Nodegroups []string `json:"nodegroups"`
+			NextToken  string   `json:"nextToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return "", err
+		}
+		names = append(names, page.Nodegroups...)
+		return page.NextToken, nil
+	})
+	return names, err
+}
+
+// paginate GETs every page of a list operation; handlePage returns the next token
+func (c *eksClient) paginate(ctx context.Context, path string, handlePage func([]byte) (string, error)) error {
+	nextToken := ""
+	for {
+		query := url.Values{"maxResults": {"100"}}
+		if nextToken != "" {
+			query.Set("nextToken", nextToken)
+		}
+		body, err := c.get(ctx, path, query)
+		if err != nil {
+			return err
+		}
+		nextToken, err = handlePage(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		if nextToken == "" {
+			return nil
+		}
+	}
+}
+
+// get sends a signed GET request and returns the response body
+func (c *eksClient) get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	endpoint := fmt.Sprintf("https://eks.%s.amazonaws.com%s", c.cfg.Region, path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := c.signer.SignHTTP(ctx, creds, req, emptyPayloadHash, "eks", c.cfg.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign EKS request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("EKS %s returned %d: %s", path, resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("EKS %s returned %d", path, resp.StatusCode)
+	}
+	return body, nil
+}
@@ -3,16 +3,18 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
-	"sort"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/portalight/backend/internal/models"
 )
 
@@ -20,20 +22,33 @@ import (
 type MetricDataPoint struct {
 	Timestamp time.Time `json:"timestamp"`
 	Value     float64   `json:"value"`
+	Missing   bool      `json:"missing,omitempty"` // No data for this period; Value is meaningless
 }
 
 // ResourceMetrics contains metrics for a specific resource
 type ResourceMetrics struct {
 	ResourceARN  string                       `json:"resource_arn"`
 	ResourceType string                       `json:"resource_type"`
-	Period       string                       `json:"period"` // 1h, 6h, 24h, 7d
+	Period       string                       `json:"period"` // 1h, 6h, 24h, 7d, 30d
 	Metrics      map[string][]MetricDataPoint `json:"metrics"`
 	Metadata     map[string]string            `json:"metadata,omitempty"`
+	Alarms       []AlarmStatus                `json:"alarms,omitempty"`
 	FetchedAt    time.Time                    `json:"fetched_at"`
 }
 
+// AlarmStatus is the current state of a CloudWatch metric alarm
+type AlarmStatus struct {
+	AlarmName             string    `json:"alarm_name"`
+	StateValue            string    `json:"state_value"` // "OK", "ALARM" or "INSUFFICIENT_DATA"
+	Threshold             float64   `json:"threshold"`
+	MetricName            string    `json:"metric_name"`
+	StateUpdatedTimestamp time.Time `json:"state_updated_timestamp"`
+}
+
 // AWSMetrics handles fetching CloudWatch metrics
-type AWSMetrics struct{}
+type AWSMetrics struct {
+	accountIDs sync.Map // Access key ID -> AWS account ID, looked up once per credential
+}
 
 // NewAWSMetrics creates a new AWS metrics service
 func NewAWSMetrics() *AWSMetrics {
@@ -54,6 +69,22 @@ func (m *AWSMetrics) createConfig(ctx context.Context, creds *models.AWSCredenti
 	)
 }
 
+// accountID returns the AWS account the credentials belong to, for building ARNs.
+// STS is asked once per access key; "*" is returned when it cannot tell.
+func (m *AWSMetrics) accountID(ctx context.Context, cfg aws.Config, creds *models.AWSCredentials) string {
+	if id, ok := m.accountIDs.Load(creds.AccessKeyID); ok {
+		return id.(string)
+	}
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		log.Printf("Failed to look up AWS account ID: %v", err)
+		return "*"
+	}
+	id := aws.ToString(identity.Account)
+	m.accountIDs.Store(creds.AccessKeyID, id)
+	return id
+}
+
 // GetRDSMetrics fetches metrics for an RDS instance
 func (m *AWSMetrics) GetRDSMetrics(ctx context.Context, creds *models.AWSCredentials, region, instanceID, period string) (*ResourceMetrics, error) {
 	cfg, err := m.createConfig(ctx, creds, region)
@@ -66,7 +97,7 @@ func (m *AWSMetrics) GetRDSMetrics(ctx context.Context, creds *models.AWSCredent
 	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
 
 	metrics := &ResourceMetrics{
-		ResourceARN:  fmt.Sprintf("arn:aws:rds:%s:*:db:%s", region, instanceID),
+		ResourceARN:  fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", region, m.accountID(ctx, cfg, creds), instanceID),
 		ResourceType: "rds",
 		Period:       period,
 		Metrics:      make(map[string][]MetricDataPoint),
@@ -95,35 +126,8 @@ func (m *AWSMetrics) GetRDSMetrics(ctx context.Context, creds *models.AWSCredent
 	}
 
 	// Fetch CPU, Memory, Connections metrics
-	metricNames := []string{"CPUUtilization", "FreeableMemory", "DatabaseConnections", "ReadIOPS", "WriteIOPS"}
-
-	for _, metricName := range metricNames {
-		result, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-			Namespace:  aws.String("AWS/RDS"),
-			MetricName: aws.String(metricName),
-			Dimensions: []types.Dimension{
-				{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(instanceID)},
-			},
-			StartTime:  aws.Time(startTime),
-			EndTime:    aws.Time(endTime),
-			Period:     aws.Int32(periodSeconds),
-			Statistics: []types.Statistic{types.StatisticAverage},
-		})
-
-		if err == nil && len(result.Datapoints) > 0 {
-			dataPoints := make([]MetricDataPoint, len(result.Datapoints))
-			for i, dp := range result.Datapoints {
-				dataPoints[i] = MetricDataPoint{
-					Timestamp: *dp.Timestamp,
-					Value:     *dp.Average,
-				}
-			}
-			sort.Slice(dataPoints, func(i, j int) bool {
-				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
-			})
-			metrics.Metrics[metricName] = dataPoints
-		}
-	}
+	dimensions := []types.Dimension{{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(instanceID)}}
+	m.fetchMetrics(ctx, client, metrics, "AWS/RDS", metricQueries(rdsMetrics, dimensions, types.StatisticAverage), startTime, endTime, periodSeconds)
 
 	return metrics, nil
 }
@@ -140,46 +144,15 @@ func (m *AWSMetrics) GetLambdaMetrics(ctx context.Context, creds *models.AWSCred
 	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
 
 	metrics := &ResourceMetrics{
-		ResourceARN:  fmt.Sprintf("arn:aws:lambda:%s:*:function:%s", region, functionName),
+		ResourceARN:  fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", region, m.accountID(ctx, cfg, creds), functionName),
 		ResourceType: "lambda",
 		Period:       period,
 		Metrics:      make(map[string][]MetricDataPoint),
 		FetchedAt:    time.Now(),
 	}
 
-	metricNames := []string{"Invocations", "Duration", "Errors", "Throttles", "ConcurrentExecutions"}
-
-	for _, metricName := range metricNames {
-		result, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-			Namespace:  aws.String("AWS/Lambda"),
-			MetricName: aws.String(metricName),
-			Dimensions: []types.Dimension{
-				{Name: aws.String("FunctionName"), Value: aws.String(functionName)},
-			},
-			StartTime:  aws.Time(startTime),
-			EndTime:    aws.Time(endTime),
-			Period:     aws.Int32(periodSeconds),
-			Statistics: []types.Statistic{types.StatisticSum},
-		})
-
-		if err == nil && len(result.Datapoints) > 0 {
-			dataPoints := make([]MetricDataPoint, len(result.Datapoints))
-			for i, dp := range result.Datapoints {
-				val := 0.0
-				if dp.Sum != nil {
-					val = *dp.Sum
-				}
-				dataPoints[i] = MetricDataPoint{
-					Timestamp: *dp.Timestamp,
-					Value:     val,
-				}
-			}
-			sort.Slice(dataPoints, func(i, j int) bool {
-				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
-			})
-			metrics.Metrics[metricName] = dataPoints
-		}
-	}
+	dimensions := []types.Dimension{{Name: aws.String("FunctionName"), Value: aws.String(functionName)}}
+	m.fetchMetrics(ctx, client, metrics, "AWS/Lambda", metricQueries(lambdaMetrics, dimensions, types.StatisticSum), startTime, endTime, periodSeconds)
 
 	return metrics, nil
 }
@@ -210,39 +183,17 @@ func (m *AWSMetrics) GetS3Metrics(ctx context.Context, creds *models.AWSCredenti
 
 	// S3 storage metrics - try multiple storage types
 	storageTypes := []string{"StandardStorage", "AllStorageTypes"}
-	storageMetrics := []string{"BucketSizeBytes", "NumberOfObjects"}
-
-	for _, metricName := range storageMetrics {
-		for _, storageType := range storageTypes {
-			result, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-				Namespace:  aws.String("AWS/S3"),
-				MetricName: aws.String(metricName),
-				Dimensions: []types.Dimension{
-					{Name: aws.String("BucketName"), Value: aws.String(bucketName)},
-					{Name: aws.String("StorageType"), Value: aws.String(storageType)},
-				},
-				StartTime:  aws.Time(startTime),
-				EndTime:    aws.Time(endTime),
-				Period:     aws.Int32(periodSeconds),
-				Statistics: []types.Statistic{types.StatisticAverage},
-			})
 
-			if err == nil && len(result.Datapoints) > 0 {
-				dataPoints := make([]MetricDataPoint, len(result.Datapoints))
-				for i, dp := range result.Datapoints {
-					dataPoints[i] = MetricDataPoint{
-						Timestamp: *dp.Timestamp,
-						Value:     *dp.Average,
-					}
-				}
-				sort.Slice(dataPoints, func(i, j int) bool {
-					return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
-				})
-				metrics.Metrics[metricName] = dataPoints
-				break // Found data, stop trying other storage types
-			}
+	// Queries are in preference order: the first storage type with data wins
+	var queries []metricQuery
+	for _, storageType := range storageTypes {
+		dimensions := []types.Dimension{
+			{Name: aws.String("BucketName"), Value: aws.String(bucketName)},
+			{Name: aws.String("StorageType"), Value: aws.String(storageType)},
 		}
+		queries = append(queries, metricQueries(s3Metrics, dimensions, types.StatisticAverage)...)
 	}
+	m.fetchMetrics(ctx, client, metrics, "AWS/S3", queries, startTime, endTime, periodSeconds)
 
 	return metrics, nil
 }
@@ -259,46 +210,15 @@ func (m *AWSMetrics) GetSQSMetrics(ctx context.Context, creds *models.AWSCredent
 	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
 
 	metrics := &ResourceMetrics{
-		ResourceARN:  fmt.Sprintf("arn:aws:sqs:%s:*:%s", region, queueName),
+		ResourceARN:  fmt.Sprintf("arn:aws:sqs:%s:%s:%s", region, m.accountID(ctx, cfg, creds), queueName),
 		ResourceType: "sqs",
 		Period:       period,
 		Metrics:      make(map[string][]MetricDataPoint),
 		FetchedAt:    time.Now(),
 	}
 
-	metricNames := []string{"NumberOfMessagesSent", "NumberOfMessagesReceived", "NumberOfMessagesDeleted", "ApproximateNumberOfMessagesVisible", "ApproximateAgeOfOldestMessage"}
-
-	for _, metricName := range metricNames {
-		result, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-			Namespace:  aws.String("AWS/SQS"),
-			MetricName: aws.String(metricName),
-			Dimensions: []types.Dimension{
-				{Name: aws.String("QueueName"), Value: aws.String(queueName)},
-			},
-			StartTime:  aws.Time(startTime),
-			EndTime:    aws.Time(endTime),
-			Period:     aws.Int32(periodSeconds),
-			Statistics: []types.Statistic{types.StatisticSum},
-		})
-
-		if err == nil && len(result.Datapoints) > 0 {
-			dataPoints := make([]MetricDataPoint, len(result.Datapoints))
-			for i, dp := range result.Datapoints {
-				val := 0.0
-				if dp.Sum != nil {
-					val = *dp.Sum
-				}
-				dataPoints[i] = MetricDataPoint{
-					Timestamp: *dp.Timestamp,
-					Value:     val,
-				}
-			}
-			sort.Slice(dataPoints, func(i, j int) bool {
-				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
-			})
-			metrics.Metrics[metricName] = dataPoints
-		}
-	}
+	dimensions := []types.Dimension{{Name: aws.String("QueueName"), Value: aws.String(queueName)}}
+	m.fetchMetrics(ctx, client, metrics, "AWS/SQS", metricQueries(sqsMetrics, dimensions, types.StatisticSum), startTime, endTime, periodSeconds)
 
 	return metrics, nil
 }
@@ -315,49 +235,452 @@ func (m *AWSMetrics) GetSNSMetrics(ctx context.Context, creds *models.AWSCredent
 	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
 
 	metrics := &ResourceMetrics{
-		ResourceARN:  fmt.Sprintf("arn:aws:sns:%s:*:%s", region, topicName),
+		ResourceARN:  fmt.Sprintf("arn:aws:sns:%s:%s:%s", region, m.accountID(ctx, cfg, creds), topicName),
 		ResourceType: "sns",
 		Period:       period,
 		Metrics:      make(map[string][]MetricDataPoint),
 		FetchedAt:    time.Now(),
 	}
 
-	// SNS metrics
-	metricNames := []string{"NumberOfMessagesPublished", "NumberOfNotificationsDelivered", "NumberOfNotificationsFailed", "PublishSize"}
+	dimensions := []types.Dimension{{Name: aws.String("TopicName"), Value: aws.String(topicName)}}
+	m.fetchMetrics(ctx, client, metrics, "AWS/SNS", metricQueries(snsMetrics, dimensions, types.StatisticSum), startTime, endTime, periodSeconds)
+
+	return metrics, nil
+}
+
+// GetEKSMetrics fetches Container Insights node and pod metrics for an EKS cluster.
+// The cluster needs the CloudWatch observability add-on; without it the series are empty.
+func (m *AWSMetrics) GetEKSMetrics(ctx context.Context, creds *models.AWSCredentials, region, clusterName, period string) (*ResourceMetrics, error) {
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := cloudwatch.NewFromConfig(cfg)
+
+	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
+
+	metrics := &ResourceMetrics{
+		ResourceARN:  fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", region, m.accountID(ctx, cfg, creds), clusterName),
+		ResourceType: "eks",
+		Period:       period,
+		Metrics:      make(map[string][]MetricDataPoint),
+		FetchedAt:    time.Now(),
+	}
+
+	dimensions := []types.Dimension{{Name: aws.String("ClusterName"), Value: aws.String(clusterName)}}
+	m.fetchMetrics(ctx, client, metrics, "ContainerInsights", metricQueries(eksMetrics, dimensions, types.StatisticAverage), startTime, endTime, periodSeconds)
+
+	return metrics, nil
+}
+
+// GetDynamoDBMetrics fetches capacity, latency, error and throttling metrics for a
+// DynamoDB table. Counts are summed per period; the request latency is averaged.
+func (m *AWSMetrics) GetDynamoDBMetrics(ctx context.Context, creds *models.AWSCredentials, region, tableName, period string) (*ResourceMetrics, error) {
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := cloudwatch.NewFromConfig(cfg)
+
+	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
+
+	metrics := &ResourceMetrics{
+		ResourceARN:  fmt.Sprintf("arn:aws:dynamodb:%s:%s:table/%s", region, m.accountID(ctx, cfg, creds), tableName),
+		ResourceType: "dynamodb",
+		Period:       period,
+		Metrics:      make(map[string][]MetricDataPoint),
+		FetchedAt:    time.Now(),
+	}
+
+	dimensions := []types.Dimension{{Name: aws.String("TableName"), Value: aws.String(tableName)}}
+	queries := append(
+		metricQueries(dynamoDBCountMetrics, dimensions, types.StatisticSum),
+		metricQueries(dynamoDBLatencyMetrics, dimensions, types.StatisticAverage)...,
+	)
+	m.fetchMetrics(ctx, client, metrics, "AWS/DynamoDB", queries, startTime, endTime, periodSeconds)
+
+	return metrics, nil
+}
+
+// GetKinesisMetrics fetches throughput, consumer lag and write throttling metrics for
+// a Kinesis data stream
+func (m *AWSMetrics) GetKinesisMetrics(ctx context.Context, creds *models.AWSCredentials, region, streamName, period string) (*ResourceMetrics, error) {
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	client := cloudwatch.NewFromConfig(cfg)
+
+	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
+
+	metrics := &ResourceMetrics{
+		ResourceARN:  fmt.Sprintf("arn:aws:kinesis:%s:%s:stream/%s", region, m.accountID(ctx, cfg, creds), streamName),
+		ResourceType: "kinesis",
+		Period:       period,
+		Metrics:      make(map[string][]MetricDataPoint),
+		FetchedAt:    time.Now(),
+	}
+
+	// The iterator age is the lag of the slowest consumer, so the maximum matters
+	dimensions := []types.Dimension{{Name: aws.String("StreamName"), Value: aws.String(streamName)}}
+	queries := append(
+		metricQueries(kinesisCountMetrics, dimensions, types.StatisticSum),
+		metricQueries(kinesisLagMetrics, dimensions, types.StatisticMaximum)...,
+	)
+	m.fetchMetrics(ctx, client, metrics, "AWS/Kinesis", queries, startTime, endTime, periodSeconds)
+
+	return metrics, nil
+}
+
+// GetEventBridgeMetrics fetches invocation and failure metrics for an EventBridge rule
+func (m *AWSMetrics) GetEventBridgeMetrics(ctx context.Context, creds *models.AWSCredentials, region, ruleName, period string) (*ResourceMetrics, error) {
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, metricName := range metricNames {
-		result, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
-			Namespace:  aws.String("AWS/SNS"),
-			MetricName: aws.String(metricName),
-			Dimensions: []types.Dimension{
-				{Name: aws.String("TopicName"), Value: aws.String(topicName)},
+	client := cloudwatch.NewFromConfig(cfg)
+
+	startTime, endTime, periodSeconds := m.getPeriodTimes(period)
+
+	metrics := &ResourceMetrics{
+		ResourceARN:  fmt.Sprintf("arn:aws:events:%s:%s:rule/%s", region, m.accountID(ctx, cfg, creds), ruleName),
+		ResourceType: "eventbridge",
+		Period:       period,
+		Metrics:      make(map[string][]MetricDataPoint),
+		FetchedAt:    time.Now(),
+	}
+
+	dimensions := []types.Dimension{{Name: aws.String("RuleName"), Value: aws.String(ruleName)}}
+	m.fetchMetrics(ctx, client, metrics, "AWS/Events", metricQueries(eventBridgeMetrics, dimensions, types.StatisticSum), startTime, endTime, periodSeconds)
+
+	return metrics, nil
+}
+
+// GetCloudFormationMetrics returns empty metrics for a CloudFormation stack: stacks
+// publish no useful CloudWatch metrics, but every resource type has a metrics method
+func (m *AWSMetrics) GetCloudFormationMetrics(ctx context.Context, creds *models.AWSCredentials, region, stackName, period string) (*ResourceMetrics, error) {
+	return &ResourceMetrics{
+		ResourceType: "cloudformation",
+		Period:       period,
+		Metrics:      make(map[string][]MetricDataPoint),
+		FetchedAt:    time.Now(),
+	}, nil
+}
+
+// alarmDimensions is the CloudWatch namespace and dimension that identify a resource
+// of each type in its metrics
+var alarmDimensions = map[string]struct{ Namespace, Dimension string }{
+	"rds":         {"AWS/RDS", "DBInstanceIdentifier"},
+	"lambda":      {"AWS/Lambda", "FunctionName"},
+	"s3":          {"AWS/S3", "BucketName"},
+	"sqs":         {"AWS/SQS", "QueueName"},
+	"sns":         {"AWS/SNS", "TopicName"},
+	"eks":         {"ContainerInsights", "ClusterName"},
+	"dynamodb":    {"AWS/DynamoDB", "TableName"},
+	"kinesis":     {"AWS/Kinesis", "StreamName"},
+	"eventbridge": {"AWS/Events", "RuleName"},
+}
+
+// GetResourceAlarms returns the metric alarms whose names start with the resource
+// name, the usual convention ("orders-db-high-cpu"). Alarms on the resource type's
+// namespace that watch a different resource (e.g. "orders-db-replica") are left out.
+func (m *AWSMetrics) GetResourceAlarms(ctx context.Context, creds *models.AWSCredentials, region, resourceType, resourceName string) ([]AlarmStatus, error) {
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	target, known := alarmDimensions[strings.ToLower(resourceType)]
+	alarms := []AlarmStatus{}
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(cloudwatch.NewFromConfig(cfg), &cloudwatch.DescribeAlarmsInput{
+		AlarmNamePrefix: aws.String(resourceName),
+		AlarmTypes:      []types.AlarmType{types.AlarmTypeMetricAlarm},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe alarms: %w", err)
+		}
+		for _, alarm := range page.MetricAlarms {
+			if known && aws.ToString(alarm.Namespace) == target.Namespace && !watchesResource(alarm.Dimensions, target.Dimension, resourceName) {
+				continue
+			}
+			alarms = append(alarms, AlarmStatus{
+				AlarmName:             aws.ToString(alarm.AlarmName),
+				StateValue:            string(alarm.StateValue),
+				Threshold:             aws.ToFloat64(alarm.Threshold),
+				MetricName:            aws.ToString(alarm.MetricName),
+				StateUpdatedTimestamp: aws.ToTime(alarm.StateUpdatedTimestamp),
+			})
+		}
+	}
+	return alarms, nil
+}
+
+// watchesResource reports whether an alarm's dimensions name the resource. Alarms
+// without that dimension (e.g. on a math expression) are kept.
+func watchesResource(dimensions []types.Dimension, name, value string) bool {
+	for _, d := range dimensions {
+		if aws.ToString(d.Name) == name {
+			return aws.ToString(d.Value) == value
+		}
+	}
+	return true
+}
+
+// recentAlertWindow is how far back RecentAlerts looks
+const recentAlertWindow = 5 * time.Minute
+
+// rdsCPUAlertThreshold is the CPU utilization (percent) RecentAlerts reports for RDS
+const rdsCPUAlertThreshold = 90
+
+// RecentAlerts checks the last few minutes of a Lambda function's errors and
+// throttles or an RDS instance's CPU and describes anything wrong. Other resource
+// types have no checks. Metrics that cannot be fetched produce no alerts.
+func (m *AWSMetrics) RecentAlerts(ctx context.Context, creds *models.AWSCredentials, region, resourceType, name string) ([]string, error) {
+	var namespace string
+	var queries []metricQuery
+	switch resourceType {
+	case "lambda":
+		namespace = "AWS/Lambda"
+		dimensions := []types.Dimension{{Name: aws.String("FunctionName"), Value: aws.String(name)}}
+		queries = metricQueries([]metricDef{{"Errors", counterMetric}, {"Throttles", counterMetric}}, dimensions, types.StatisticSum)
+	case "rds":
+		namespace = "AWS/RDS"
+		dimensions := []types.Dimension{{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(name)}}
+		queries = metricQueries([]metricDef{{"CPUUtilization", gaugeMetric}}, dimensions, types.StatisticMaximum)
+	default:
+		return nil, nil
+	}
+
+	cfg, err := m.createConfig(ctx, creds, region)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := time.Now()
+	metrics := &ResourceMetrics{Metrics: make(map[string][]MetricDataPoint)}
+	m.fetchMetrics(ctx, cloudwatch.NewFromConfig(cfg), metrics, namespace, queries, endTime.Add(-recentAlertWindow), endTime, 60)
+
+	var alerts []string
+	for _, metricName := range []string{"Errors", "Throttles"} {
+		var total float64
+		for _, dp := range metrics.Metrics[metricName] {
+			total += dp.Value
+		}
+		if total > 0 {
+			alerts = append(alerts, fmt.Sprintf("%s %s: %.0f %s in the last %d minutes", resourceType, name, total, strings.ToLower(metricName), int(recentAlertWindow.Minutes())))
+		}
+	}
+	for _, dp := range metrics.Metrics["CPUUtilization"] {
+		if !dp.Missing && dp.Value >= rdsCPUAlertThreshold {
+			alerts = append(alerts, fmt.Sprintf("%s %s: CPU at %.0f%% in the last %d minutes", resourceType, name, dp.Value, int(recentAlertWindow.Minutes())))
+			break
+		}
+	}
+	return alerts, nil
+}
+
+// metricKind decides how periods without data are filled in
+type metricKind int
+
+const (
+	gaugeMetric   metricKind = iota // A level; a missing period is marked Missing
+	counterMetric                   // A count of events; a missing period counted none, so it is zero
+)
+
+// metricDef is one entry of a resource type's metric table
+type metricDef struct {
+	Name string
+	Kind metricKind
+}
+
+// Metric tables per resource type. CloudWatch omits periods in which nothing was
+// published, so the kind decides what such a gap means.
+var (
+	rdsMetrics = []metricDef{
+		{"CPUUtilization", gaugeMetric},
+		{"FreeableMemory", gaugeMetric},
+		{"DatabaseConnections", gaugeMetric},
+		{"ReadIOPS", gaugeMetric},
+		{"WriteIOPS", gaugeMetric},
+	}
+	lambdaMetrics = []metricDef{
+		{"Invocations", counterMetric},
+		{"Duration", gaugeMetric}, // No invocations means no duration, not a zero one
+		{"Errors", counterMetric},
+		{"Throttles", counterMetric},
+		{"ConcurrentExecutions", gaugeMetric},
+	}
+	s3Metrics = []metricDef{
+		{"BucketSizeBytes", gaugeMetric},
+		{"NumberOfObjects", gaugeMetric},
+	}
+	sqsMetrics = []metricDef{
+		{"NumberOfMessagesSent", counterMetric},
+		{"NumberOfMessagesReceived", counterMetric},
+		{"NumberOfMessagesDeleted", counterMetric},
+		{"ApproximateNumberOfMessagesVisible", gaugeMetric},
+		{"ApproximateAgeOfOldestMessage", gaugeMetric},
+	}
+	snsMetrics = []metricDef{
+		{"NumberOfMessagesPublished", counterMetric},
+		{"NumberOfNotificationsDelivered", counterMetric},
+		{"NumberOfNotificationsFailed", counterMetric}, // Only published once something fails
+		{"PublishSize", gaugeMetric},
+	}
+	eksMetrics = []metricDef{
+		{"node_cpu_utilization", gaugeMetric},
+		{"node_memory_utilization", gaugeMetric},
+		{"pod_cpu_utilization", gaugeMetric},
+		{"pod_memory_utilization", gaugeMetric},
+	}
+	dynamoDBCountMetrics = []metricDef{
+		{"ConsumedReadCapacityUnits", counterMetric},
+		{"ConsumedWriteCapacityUnits", counterMetric},
+		{"ReturnedItemCount", counterMetric},
+		{"SystemErrors", counterMetric},
+		{"UserErrors", counterMetric},
+		{"ThrottledRequests", counterMetric}, // Only published once a request is throttled
+	}
+	dynamoDBLatencyMetrics = []metricDef{
+		{"SuccessfulRequestLatency", gaugeMetric},
+	}
+	kinesisCountMetrics = []metricDef{
+		{"IncomingRecords", counterMetric},
+		{"WriteProvisionedThroughputExceeded", counterMetric},
+	}
+	kinesisLagMetrics = []metricDef{
+		{"GetRecords.IteratorAgeMilliseconds", gaugeMetric}, // No reads means no age, not a zero one
+	}
+	eventBridgeMetrics = []metricDef{
+		{"TriggeredRules", counterMetric},
+		{"Invocations", counterMetric},
+		{"FailedInvocations", counterMetric}, // Only published once an invocation fails
+	}
+)
+
+// metricQuery is one CloudWatch series to fetch for a resource
+type metricQuery struct {
+	Name       string
+	Kind       metricKind
+	Dimensions []types.Dimension
+	Stat       types.Statistic
+}
+
+// metricQueries builds one query per metric with the same dimensions and statistic
+func metricQueries(defs []metricDef, dimensions []types.Dimension, stat types.Statistic) []metricQuery {
+	queries := make([]metricQuery, len(defs))
+	for i, def := range defs {
+		queries[i] = metricQuery{Name: def.Name, Kind: def.Kind, Dimensions: dimensions, Stat: stat}
+	}
+	return queries
+}
+
+// fetchMetrics fetches all queries in a single batched GetMetricData call and adds
+// each series to metrics.Metrics under its metric name, aligned to the same time grid
+// (see alignSeries). A query that fails is left out, as is a later query for a name
+// that already has a series. A gauge without any data is left out too, while a
+// counter without any data becomes all zeros. If the whole call fails, no series
+// are added.
+func (m *AWSMetrics) fetchMetrics(ctx context.Context, client cloudwatch.GetMetricDataAPIClient, metrics *ResourceMetrics, namespace string, queries []metricQuery, startTime, endTime time.Time, periodSeconds int32) {
+	dataQueries := make([]types.MetricDataQuery, len(queries))
+	for i, q := range queries {
+		dataQueries[i] = types.MetricDataQuery{
+			Id: aws.String(fmt.Sprintf("m%d", i)), // IDs must start with a lowercase letter
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(q.Name),
+					Dimensions: q.Dimensions,
+				},
+				Period: aws.Int32(periodSeconds),
+				Stat:   aws.String(string(q.Stat)),
 			},
-			StartTime:  aws.Time(startTime),
-			EndTime:    aws.Time(endTime),
-			Period:     aws.Int32(periodSeconds),
-			Statistics: []types.Statistic{types.StatisticSum},
-		})
-
-		if err == nil && len(result.Datapoints) > 0 {
-			dataPoints := make([]MetricDataPoint, len(result.Datapoints))
-			for i, dp := range result.Datapoints {
-				val := 0.0
-				if dp.Sum != nil {
-					val = *dp.Sum
-				}
-				dataPoints[i] = MetricDataPoint{
-					Timestamp: *dp.Timestamp,
-					Value:     val,
+		}
+	}
+
+	series := make(map[string][]MetricDataPoint)
+	failed := make(map[string]bool)
+	paginator := cloudwatch.NewGetMetricDataPaginator(client, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: dataQueries,
+		StartTime:         aws.Time(startTime),
+		EndTime:           aws.Time(endTime),
+		ScanBy:            types.ScanByTimestampAscending,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return
+		}
+		for _, result := range page.MetricDataResults {
+			id := aws.ToString(result.Id)
+			if result.StatusCode == types.StatusCodeInternalError || result.StatusCode == types.StatusCodeForbidden {
+				failed[id] = true
+				continue
+			}
+			for i := range result.Timestamps {
+				if i < len(result.Values) {
+					series[id] = append(series[id], MetricDataPoint{Timestamp: result.Timestamps[i], Value: result.Values[i]})
 				}
 			}
-			sort.Slice(dataPoints, func(i, j int) bool {
-				return dataPoints[i].Timestamp.Before(dataPoints[j].Timestamp)
-			})
-			metrics.Metrics[metricName] = dataPoints
 		}
 	}
 
-	return metrics, nil
+	grid := metricGrid(startTime, endTime, periodSeconds)
+	for i, q := range queries {
+		id := fmt.Sprintf("m%d", i)
+		dataPoints := series[id]
+		if failed[id] || len(dataPoints) == 0 {
+			continue
+		}
+		if _, ok := metrics.Metrics[q.Name]; ok {
+			continue
+		}
+		metrics.Metrics[q.Name] = alignSeries(dataPoints, grid, periodSeconds, q.Kind)
+	}
+
+	// Counters that published nothing (e.g. SNS NumberOfNotificationsFailed) counted
+	// nothing; filled after the loop so they don't shadow a later query with data
+	for i, q := range queries {
+		id := fmt.Sprintf("m%d", i)
+		if _, ok := metrics.Metrics[q.Name]; ok || failed[id] || q.Kind != counterMetric {
+			continue
+		}
+		metrics.Metrics[q.Name] = alignSeries(nil, grid, periodSeconds, q.Kind)
+	}
+}
+
+// metricGrid returns the start of every period between startTime and endTime.
+// CloudWatch rounds the start down to a multiple of the period, so the grid does too.
+func metricGrid(startTime, endTime time.Time, periodSeconds int32) []time.Time {
+	step := time.Duration(periodSeconds) * time.Second
+	var grid []time.Time
+	for t := startTime.UTC().Truncate(step); t.Before(endTime); t = t.Add(step) {
+		grid = append(grid, t)
+	}
+	return grid
+}
+
+// alignSeries returns one data point per grid timestamp. Periods without data are
+// zero for counters and marked Missing for gauges; points off the grid are dropped.
+func alignSeries(dataPoints []MetricDataPoint, grid []time.Time, periodSeconds int32, kind metricKind) []MetricDataPoint {
+	step := time.Duration(periodSeconds) * time.Second
+	values := make(map[int64]float64, len(dataPoints))
+	for _, dp := range dataPoints {
+		values[dp.Timestamp.Truncate(step).Unix()] = dp.Value
+	}
+
+	aligned := make([]MetricDataPoint, len(grid))
+	for i, t := range grid {
+		value, ok := values[t.Unix()]
+		aligned[i] = MetricDataPoint{Timestamp: t, Value: value, Missing: !ok && kind == gaugeMetric}
+	}
+	return aligned
 }
 
 // getPeriodTimes returns start time, end time, and period in seconds based on period string
@@ -380,6 +703,9 @@ func (m *AWSMetrics) getPeriodTimes(period string) (time.Time, time.Time, int32)
 	case "7d":
 		startTime = endTime.Add(-7 * 24 * time.Hour)
 		periodSeconds = 3600 // 1 hour granularity
+	case "30d":
+		startTime = endTime.Add(-30 * 24 * time.Hour)
+		periodSeconds = 86400 // 1 day granularity, for capacity planning
 	default:
 		startTime = endTime.Add(-24 * time.Hour)
 		periodSeconds = 900
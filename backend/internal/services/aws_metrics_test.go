@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// fakeCloudWatch answers GetMetricData after a fixed round-trip latency with one data
+// point per period for every query, except the metric names listed in failing or empty
+type fakeCloudWatch struct {
+	latency time.Duration
+	failing map[string]bool
+	empty   map[string]bool // Published nothing in the requested range
+	calls   atomic.Int32
+}
+
+func (f *fakeCloudWatch) GetMetricData(ctx context.Context, input *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	f.calls.Add(1)
+	time.Sleep(f.latency)
+
+	output := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range input.MetricDataQueries {
+		result := types.MetricDataResult{Id: query.Id, StatusCode: types.StatusCodeComplete}
+		name := aws.ToString(query.MetricStat.Metric.MetricName)
+		if f.failing[name] {
+			result.StatusCode = types.StatusCodeInternalError
+		} else if !f.empty[name] {
+			step := time.Duration(aws.ToInt32(query.MetricStat.Period)) * time.Second
+			for t := input.StartTime.Truncate(step); t.Before(*input.EndTime); t = t.Add(step) {
+				result.Timestamps = append(result.Timestamps, t)
+				result.Values = append(result.Values, 1)
+			}
+		}
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+	return output, nil
+}
+
+func rdsQueries() []metricQuery {
+	dimensions := []types.Dimension{{Name: aws.String("DBInstanceIdentifier"), Value: aws.String("payments-db")}}
+	return metricQueries(rdsMetrics, dimensions, types.StatisticAverage)
+}
+
+func TestFetchMetricsBatchesAllQueries(t *testing.T) {
+	client := &fakeCloudWatch{failing: map[string]bool{"FreeableMemory": true}}
+	m := NewAWSMetrics()
+	metrics := &ResourceMetrics{Metrics: make(map[string][]MetricDataPoint)}
+	startTime, endTime, periodSeconds := m.getPeriodTimes("1h")
+
+	m.fetchMetrics(context.Background(), client, metrics, "AWS/RDS", rdsQueries(), startTime, endTime, periodSeconds)
+
+	if calls := client.calls.Load(); calls != 1 {
+		t.Errorf("made %d GetMetricData calls, want 1 for all metrics", calls)
+	}
+	// The failed metric is left out; the others are kept
+	for _, def := range rdsMetrics {
+		_, ok := metrics.Metrics[def.Name]
+		if want := def.Name != "FreeableMemory"; ok != want {
+			t.Errorf("series %s present = %t, want %t", def.Name, ok, want)
+		}
+	}
+	if n := len(metrics.Metrics["CPUUtilization"]); n != len(metricGrid(startTime, endTime, periodSeconds)) {
+		t.Errorf("CPUUtilization has %d points, want one per period", n)
+	}
+}
+
+// fetchPerMetric fetches every query with its own call, as the sequential
+// GetMetricStatistics loop did before the batched GetMetricData call
+func fetchPerMetric(m *AWSMetrics, client cloudwatch.GetMetricDataAPIClient, metrics *ResourceMetrics, queries []metricQuery, startTime, endTime time.Time, periodSeconds int32) {
+	for _, q := range queries {
+		m.fetchMetrics(context.Background(), client, metrics, "AWS/RDS", []metricQuery{q}, startTime, endTime, periodSeconds)
+	}
+}
+
+// The fake's 20ms round trip stands in for CloudWatch's latency: fetching the five RDS
+// metrics takes one round trip batched and five one by one.
+//
+//	go test ./internal/services -run '^$' -bench FetchMetrics
+func BenchmarkFetchMetricsBatched(b *testing.B) {
+	client := &fakeCloudWatch{latency: 20 * time.Millisecond}
+	m := NewAWSMetrics()
+	startTime, endTime, periodSeconds := m.getPeriodTimes("1h")
+	queries := rdsQueries()
+
+	for i := 0; i < b.N; i++ {
+		metrics := &ResourceMetrics{Metrics: make(map[string][]MetricDataPoint)}
+		m.fetchMetrics(context.Background(), client, metrics, "AWS/RDS", queries, startTime, endTime, periodSeconds)
+	}
+}
+
+func BenchmarkFetchMetricsPerMetric(b *testing.B) {
+	client := &fakeCloudWatch{latency: 20 * time.Millisecond}
+	m := NewAWSMetrics()
+	startTime, endTime, periodSeconds := m.getPeriodTimes("1h")
+	queries := rdsQueries()
+
+	for i := 0; i < b.N; i++ {
+		metrics := &ResourceMetrics{Metrics: make(map[string][]MetricDataPoint)}
+		fetchPerMetric(m, client, metrics, queries, startTime, endTime, periodSeconds)
+	}
+}
+
+func TestMetricGrid(t *testing.T) {
+	start := time.Date(2024, 3, 14, 10, 7, 30, 0, time.UTC)
+	grid := metricGrid(start, start.Add(5*time.Minute), 60)
+
+	// The start is rounded down to the period, as CloudWatch does
+	if len(grid) != 6 || !grid[0].Equal(time.Date(2024, 3, 14, 10, 7, 0, 0, time.UTC)) {
+		t.Fatalf("grid = %v, want 6 minutes from 10:07", grid)
+	}
+	for i := 1; i < len(grid); i++ {
+		if step := grid[i].Sub(grid[i-1]); step != time.Minute {
+			t.Errorf("step %d is %s, want 1m", i, step)
+		}
+	}
+}
+
+func TestAlignSeries(t *testing.T) {
+	start := time.Date(2024, 3, 14, 10, 0, 0, 0, time.UTC)
+	grid := metricGrid(start, start.Add(4*time.Minute), 60)
+	at := func(minute, second int) time.Time {
+		return start.Add(time.Duration(minute)*time.Minute + time.Duration(second)*time.Second)
+	}
+
+	// Data for minutes 0 and 3 only; the point at 3:20 lands in minute 3's bucket and
+	// the one an hour later is off the grid
+	dataPoints := []MetricDataPoint{
+		{Timestamp: at(0, 0), Value: 5},
+		{Timestamp: at(3, 20), Value: 7},
+		{Timestamp: at(60, 0), Value: 9},
+	}
+
+	tests := []struct {
+		name        string
+		kind        metricKind
+		wantValues  []float64
+		wantMissing []bool
+	}{
+		{"counter gaps are zero", counterMetric, []float64{5, 0, 0, 7}, []bool{false, false, false, false}},
+		{"gauge gaps are missing", gaugeMetric, []float64{5, 0, 0, 7}, []bool{false, true, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aligned := alignSeries(dataPoints, grid, 60, tt.kind)
+			if len(aligned) != len(grid) {
+				t.Fatalf("got %d points, want one per period (%d)", len(aligned), len(grid))
+			}
+			for i, dp := range aligned {
+				if !dp.Timestamp.Equal(grid[i]) {
+					t.Errorf("point %d at %s, want %s", i, dp.Timestamp, grid[i])
+				}
+				if dp.Value != tt.wantValues[i] || dp.Missing != tt.wantMissing[i] {
+					t.Errorf("point %d = %v (missing %t), want %v (missing %t)", i, dp.Value, dp.Missing, tt.wantValues[i], tt.wantMissing[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFetchMetricsFillsSeriesWithoutData(t *testing.T) {
+	client := &fakeCloudWatch{empty: map[string]bool{
+		"NumberOfNotificationsFailed": true, // Counter: only published once something fails
+		"PublishSize":                 true, // Gauge
+	}}
+	m := NewAWSMetrics()
+	metrics := &ResourceMetrics{Metrics: make(map[string][]MetricDataPoint)}
+	startTime, endTime, periodSeconds := m.getPeriodTimes("1h")
+	dimensions := []types.Dimension{{Name: aws.String("TopicName"), Value: aws.String("payments-events")}}
+
+	m.fetchMetrics(context.Background(), client, metrics, "AWS/SNS", metricQueries(snsMetrics, dimensions, types.StatisticSum), startTime, endTime, periodSeconds)
+
+	grid := metricGrid(startTime, endTime, periodSeconds)
+	failed, ok := metrics.Metrics["NumberOfNotificationsFailed"]
+	if !ok || len(failed) != len(grid) {
+		t.Fatalf("NumberOfNotificationsFailed has %d points, want %d zeros", len(failed), len(grid))
+	}
+	for _, dp := range failed {
+		if dp.Value != 0 || dp.Missing {
+			t.Fatalf("NumberOfNotificationsFailed point = %+v, want zero", dp)
+		}
+	}
+	if _, ok := metrics.Metrics["PublishSize"]; ok {
+		t.Error("gauge without data was added; it should be left out")
+	}
+	// Every series is aligned to the same grid
+	for name, series := range metrics.Metrics {
+		if len(series) != len(grid) {
+			t.Errorf("%s has %d points, want %d", name, len(series), len(grid))
+		}
+	}
+}
@@ -2,21 +2,154 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/aws/smithy-go"
+	"github.com/portalight/backend/internal/logging"
 	"github.com/portalight/backend/internal/models"
 )
 
+const (
+	dynamoDBPollInterval  = 2 * time.Second
+	dynamoDBActiveTimeout = 5 * time.Minute
+
+	// ElastiCache clusters typically take 5-15 minutes to become available
+	elastiCachePollInterval     = 15 * time.Second
+	elastiCacheAvailableTimeout = 30 * time.Minute
+
+	lambdaPollInterval  = 2 * time.Second
+	lambdaActiveTimeout = 5 * time.Minute
+
+	// A new IAM role can take several seconds before Lambda is able to assume it
+	lambdaRolePropagationTimeout = time.Minute
+)
+
+// lambdaBasicExecutionPolicyARN lets a function write its logs to CloudWatch
+const lambdaBasicExecutionPolicyARN = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+
+// lambdaAssumeRolePolicy is the trust policy of execution roles created for functions
+const lambdaAssumeRolePolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+func init() {
+	regionOption := ProvisionerOption{Name: "region", Label: "Region", Type: "string", Required: true}
+	tagsOption := ProvisionerOption{Name: "tags", Label: "Tags", Type: "map"}
+
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "s3",
+		DisplayName: "S3 Bucket",
+		Description: "Object storage bucket",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "versioning", Label: "Versioning", Type: "boolean", Default: false},
+			{Name: "public_access_blocked", Label: "Block public access", Type: "boolean", Default: true},
+			{Name: "encryption", Label: "Encryption", Type: "select", Choices: []string{"AES256", "aws:kms"}, Default: "AES256"},
+			tagsOption,
+		},
+	})
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "sqs",
+		DisplayName: "SQS Queue",
+		Description: "Message queue",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "queue_type", Label: "Queue type", Type: "select", Choices: []string{"standard", "fifo"}, Default: "standard"},
+			{Name: "visibility_timeout", Label: "Visibility timeout (seconds)", Type: "number", Default: 30},
+			{Name: "message_retention_days", Label: "Message retention (days)", Type: "number", Default: 4},
+			{Name: "delay_seconds", Label: "Delivery delay (seconds)", Type: "number", Default: 0},
+			{Name: "dead_letter_queue_enabled", Label: "Dead-letter queue", Type: "boolean", Default: false},
+			{Name: "max_receive_count", Label: "Max receives before dead-lettering", Type: "number", Default: defaultMaxReceiveCount},
+			{Name: "dead_letter_queue_name", Label: "Dead-letter queue name", Type: "string", Description: "Defaults to <name>-dlq"},
+			tagsOption,
+		},
+	})
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "sns",
+		DisplayName: "SNS Topic",
+		Description: "Pub/sub notification topic",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "topic_type", Label: "Topic type", Type: "select", Choices: []string{"standard", "fifo"}, Default: "standard"},
+			tagsOption,
+		},
+	})
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "dynamodb",
+		DisplayName: "DynamoDB Table",
+		Description: "NoSQL key-value table",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "billing_mode", Label: "Billing mode", Type: "select", Choices: []string{"PAY_PER_REQUEST", "PROVISIONED"}, Default: "PAY_PER_REQUEST"},
+			{Name: "read_capacity", Label: "Read capacity units", Type: "number", Default: 5, Description: "Only used with PROVISIONED billing"},
+			{Name: "write_capacity", Label: "Write capacity units", Type: "number", Default: 5, Description: "Only used with PROVISIONED billing"},
+			{Name: "hash_key", Label: "Partition key", Type: "string", Required: true},
+			{Name: "hash_key_type", Label: "Partition key type", Type: "select", Choices: []string{"S", "N", "B"}, Default: "S"},
+			{Name: "range_key", Label: "Sort key", Type: "string"},
+			{Name: "range_key_type", Label: "Sort key type", Type: "select", Choices: []string{"S", "N", "B"}, Default: "S"},
+			{Name: "stream_enabled", Label: "Enable streams", Type: "boolean", Default: false},
+			{Name: "ttl_attribute", Label: "TTL attribute", Type: "string"},
+			tagsOption,
+		},
+	})
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "elasticache",
+		DisplayName: "ElastiCache Cluster",
+		Description: "Managed Redis or Memcached cache",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "engine", Label: "Engine", Type: "select", Choices: []string{"redis", "memcached"}, Default: "redis"},
+			{Name: "engine_version", Label: "Engine version", Type: "string", Description: "Defaults to the latest version"},
+			{Name: "node_type", Label: "Node type", Type: "string", Default: "cache.t3.micro"},
+			{Name: "num_nodes", Label: "Number of nodes", Type: "number", Default: 1, Description: "For Redis, the primary plus its replicas"},
+			{Name: "automatic_failover_enabled", Label: "Automatic failover", Type: "boolean", Default: false, Description: "Redis only; needs at least 2 nodes"},
+			{Name: "subnet_group_name", Label: "Subnet group", Type: "string", Description: "Defaults to the default VPC"},
+			{Name: "security_group_ids", Label: "Security group IDs", Type: "list"},
+			tagsOption,
+		},
+	})
+	RegisterProvisionerType(ProvisionerType{
+		Type:        "lambda",
+		DisplayName: "Lambda Function",
+		Description: "Serverless function from a container image or S3 zip",
+		Options: []ProvisionerOption{
+			regionOption,
+			{Name: "image_uri", Label: "Container image URI", Type: "string", Description: "Set either an image or an S3 zip archive"},
+			{Name: "s3_bucket", Label: "Code S3 bucket", Type: "string"},
+			{Name: "s3_key", Label: "Code S3 key", Type: "string"},
+			{Name: "runtime", Label: "Runtime", Type: "string", Description: "Zip archives only, e.g. python3.12"},
+			{Name: "handler", Label: "Handler", Type: "string", Description: "Zip archives only, e.g. app.handler"},
+			{Name: "memory_mb", Label: "Memory (MB)", Type: "number", Default: 128},
+			{Name: "timeout_seconds", Label: "Timeout (seconds)", Type: "number", Default: 3},
+			{Name: "environment", Label: "Environment variables", Type: "map"},
+			{Name: "role_arn", Label: "Execution role ARN", Type: "string"},
+			{Name: "create_role", Label: "Create a basic execution role", Type: "boolean", Default: false, Description: "Used when no role ARN is given"},
+			tagsOption,
+		},
+	})
+}
+
 // AWSProvisioner handles AWS resource provisioning
 type AWSProvisioner struct{}
 
@@ -38,10 +171,20 @@ func (p *AWSProvisioner) createAWSConfig(creds *models.AWSCredentials, region st
 }
 
 // ProvisionS3 creates an S3 bucket with the specified configuration
-func (p *AWSProvisioner) ProvisionS3(ctx context.Context, name string, config models.S3Config, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+func (p *AWSProvisioner) ProvisionS3(ctx context.Context, name string, config models.S3Config, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
 	awsCfg := p.createAWSConfig(creds, config.Region)
 	client := s3.NewFromConfig(awsCfg)
 
+	// Validate lifecycle rules before anything is created
+	lifecycleRules, err := s3LifecycleRules(config.LifecycleRules)
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid lifecycle rules: %v", err),
+		}, nil
+	}
+
 	// Create bucket input
 	input := &s3.CreateBucketInput{
 		Bucket: aws.String(name),
@@ -55,7 +198,7 @@ func (p *AWSProvisioner) ProvisionS3(ctx context.Context, name string, config mo
 	}
 
 	// Create the bucket
-	_, err := client.CreateBucket(ctx, input)
+	_, err = client.CreateBucket(ctx, input)
 	if err != nil {
 		return &models.ProvisionResult{
 			Success: false,
@@ -127,25 +270,125 @@ func (p *AWSProvisioner) ProvisionS3(ctx context.Context, name string, config mo
 		}
 	}
 
+	// Configure lifecycle rules; buckets without rules keep no lifecycle configuration
+	var details []string
+	if len(lifecycleRules) > 0 {
+		_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket:                 aws.String(name),
+			LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: lifecycleRules},
+		})
+		if err != nil {
+			return &models.ProvisionResult{
+				Success: false,
+				Error:   fmt.Sprintf("Bucket created but failed to configure lifecycle rules: %s", parseAWSError(err, "S3")),
+			}, nil
+		}
+		for i, rule := range config.LifecycleRules {
+			details = append(details, describeS3LifecycleRule(i, rule))
+		}
+	}
+
+	// Apply tags; the bucket already exists so failures are only warnings
+	var warnings []string
+	if len(config.Tags) > 0 {
+		tagSet := make([]s3types.Tag, 0, len(config.Tags))
+		for k, v := range config.Tags {
+			tagSet = append(tagSet, s3types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		_, err = client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+			Bucket:  aws.String(name),
+			Tagging: &s3types.Tagging{TagSet: tagSet},
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Bucket created but failed to apply tags: %s", parseAWSError(err, "S3")))
+		}
+	}
+
 	arn := fmt.Sprintf("arn:aws:s3:::%s", name)
 	return &models.ProvisionResult{
-		Success: true,
-		ARN:     arn,
-		Region:  config.Region,
+		Success:  true,
+		ARN:      arn,
+		Region:   config.Region,
+		Tags:     config.Tags,
+		Warnings: warnings,
+		Details:  details,
 	}, nil
 }
 
+// s3LifecycleRuleID names a lifecycle rule after its position in the config
+func s3LifecycleRuleID(i int) string {
+	return fmt.Sprintf("portalight-rule-%d", i+1)
+}
+
+// s3LifecycleRules maps the configured lifecycle rules to the SDK type
+func s3LifecycleRules(rules []models.S3LifecycleRule) ([]s3types.LifecycleRule, error) {
+	result := make([]s3types.LifecycleRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.ExpireDays < 0 || rule.TransitionDays < 0 {
+			return nil, fmt.Errorf("rule %d: days must not be negative", i+1)
+		}
+		if rule.ExpireDays == 0 && rule.TransitionDays == 0 {
+			return nil, fmt.Errorf("rule %d: set expire_days, transition_days or both", i+1)
+		}
+
+		lifecycleRule := s3types.LifecycleRule{
+			ID:     aws.String(s3LifecycleRuleID(i)),
+			Filter: &s3types.LifecycleRuleFilter{Prefix: aws.String(rule.Prefix)},
+			Status: s3types.ExpirationStatusDisabled,
+		}
+		if rule.Enabled {
+			lifecycleRule.Status = s3types.ExpirationStatusEnabled
+		}
+
+		if rule.TransitionDays > 0 {
+			storageClass := s3types.TransitionStorageClass(rule.TransitionStorageClass)
+			if !slices.Contains(storageClass.Values(), storageClass) {
+				return nil, fmt.Errorf("rule %d: unsupported transition storage class '%s'", i+1, rule.TransitionStorageClass)
+			}
+			lifecycleRule.Transitions = []s3types.Transition{{
+				Days:         aws.Int32(int32(rule.TransitionDays)),
+				StorageClass: storageClass,
+			}}
+		}
+		if rule.ExpireDays > 0 {
+			if rule.TransitionDays > 0 && rule.ExpireDays <= rule.TransitionDays {
+				return nil, fmt.Errorf("rule %d: expire_days must be after transition_days", i+1)
+			}
+			lifecycleRule.Expiration = &s3types.LifecycleExpiration{Days: aws.Int32(int32(rule.ExpireDays))}
+		}
+
+		result = append(result, lifecycleRule)
+	}
+	return result, nil
+}
+
+// describeS3LifecycleRule summarises a lifecycle rule for the audit log
+func describeS3LifecycleRule(i int, rule models.S3LifecycleRule) string {
+	prefix := rule.Prefix
+	if prefix == "" {
+		prefix = "*"
+	}
+	var actions []string
+	if rule.TransitionDays > 0 {
+		actions = append(actions, fmt.Sprintf("to %s after %d days", rule.TransitionStorageClass, rule.TransitionDays))
+	}
+	if rule.ExpireDays > 0 {
+		actions = append(actions, fmt.Sprintf("expire after %d days", rule.ExpireDays))
+	}
+	status := "enabled"
+	if !rule.Enabled {
+		status = "disabled"
+	}
+	return fmt.Sprintf("lifecycle rule %s (%s, prefix %s): %s", s3LifecycleRuleID(i), status, prefix, strings.Join(actions, ", "))
+}
+
 // ProvisionSQS creates an SQS queue with the specified configuration
-func (p *AWSProvisioner) ProvisionSQS(ctx context.Context, name string, config models.SQSConfig, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+func (p *AWSProvisioner) ProvisionSQS(ctx context.Context, name string, config models.SQSConfig, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
 	awsCfg := p.createAWSConfig(creds, config.Region)
 	client := sqs.NewFromConfig(awsCfg)
 
-	queueName := name
-	if config.QueueType == "fifo" {
-		if !strings.HasSuffix(queueName, ".fifo") {
-			queueName = queueName + ".fifo"
-		}
-	}
+	queueName := sqsQueueName(name, config.QueueType)
 
 	// Build attributes
 	attributes := map[string]string{}
@@ -171,41 +414,128 @@ func (p *AWSProvisioner) ProvisionSQS(ctx context.Context, name string, config m
 		attributes[string(sqstypes.QueueAttributeNameFifoQueue)] = "true"
 	}
 
+	// The dead-letter queue must exist before the main queue can point at it
+	var dlqURL, dlqARN string
+	if config.DeadLetterQueueEnabled {
+		dlqName := config.DeadLetterQueueName
+		if dlqName == "" {
+			dlqName = strings.TrimSuffix(name, ".fifo") + "-dlq"
+		}
+		dlqName = sqsQueueName(dlqName, config.QueueType)
+
+		var errMsg string
+		dlqURL, dlqARN, errMsg = p.createDeadLetterQueue(ctx, client, dlqName, config)
+		if errMsg != "" {
+			return &models.ProvisionResult{Success: false, Error: errMsg}, nil
+		}
+
+		maxReceiveCount := config.MaxReceiveCount
+		if maxReceiveCount <= 0 {
+			maxReceiveCount = defaultMaxReceiveCount
+		}
+		redrivePolicy, err := json.Marshal(map[string]interface{}{
+			"deadLetterTargetArn": dlqARN,
+			"maxReceiveCount":     maxReceiveCount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build redrive policy: %w", err)
+		}
+		attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)] = string(redrivePolicy)
+	}
+
 	input := &sqs.CreateQueueInput{
 		QueueName:  aws.String(queueName),
 		Attributes: attributes,
 	}
+	if len(config.Tags) > 0 {
+		input.Tags = config.Tags
+	}
 
 	result, err := client.CreateQueue(ctx, input)
 	if err != nil {
+		// Don't leave an unused dead-letter queue behind
+		if dlqURL != "" {
+			if _, delErr := client.DeleteQueue(ctx, &sqs.DeleteQueueInput{QueueUrl: aws.String(dlqURL)}); delErr != nil {
+				logging.FromContext(ctx).Error("Failed to clean up dead-letter queue", slog.String("queue_url", dlqURL), slog.Any("error", delErr))
+			}
+		}
 		return &models.ProvisionResult{
 			Success: false,
 			Error:   parseAWSError(err, "SQS"),
 		}, nil
 	}
 
+	provisionResult := &models.ProvisionResult{
+		Success: true,
+		ARN:     *result.QueueUrl, // Use URL as fallback
+		Region:  config.Region,
+		Tags:    config.Tags,
+	}
+	if dlqARN != "" {
+		provisionResult.SecondaryARNs = map[string]string{models.SecondaryDeadLetterQueue: dlqARN}
+	}
+
 	// Get queue ARN
+	attrResult, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       result.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err == nil {
+		provisionResult.ARN = attrResult.Attributes[string(sqstypes.QueueAttributeNameQueueArn)]
+	}
+
+	return provisionResult, nil
+}
+
+// defaultMaxReceiveCount is how often a message is received before it moves to the dead-letter queue
+const defaultMaxReceiveCount = 3
+
+// sqsQueueName adds the .fifo suffix FIFO queue names require
+func sqsQueueName(name, queueType string) string {
+	if queueType == "fifo" && !strings.HasSuffix(name, ".fifo") {
+		return name + ".fifo"
+	}
+	return name
+}
+
+// createDeadLetterQueue creates the dead-letter queue of an SQS queue and returns
+// its URL and ARN, or an error message for the ProvisionResult
+func (p *AWSProvisioner) createDeadLetterQueue(ctx context.Context, client *sqs.Client, name string, config models.SQSConfig) (string, string, string) {
+	// Keep failed messages as long as SQS allows so they can be inspected
+	attributes := map[string]string{
+		string(sqstypes.QueueAttributeNameMessageRetentionPeriod): "1209600",
+	}
+	if config.QueueType == "fifo" {
+		attributes[string(sqstypes.QueueAttributeNameFifoQueue)] = "true"
+	}
+
+	input := &sqs.CreateQueueInput{
+		QueueName:  aws.String(name),
+		Attributes: attributes,
+	}
+	if len(config.Tags) > 0 {
+		input.Tags = config.Tags
+	}
+
+	result, err := client.CreateQueue(ctx, input)
+	if err != nil {
+		return "", "", fmt.Sprintf("Failed to create dead-letter queue: %s", parseAWSError(err, "SQS"))
+	}
+
 	attrResult, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
 		QueueUrl:       result.QueueUrl,
 		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
 	})
 	if err != nil {
-		return &models.ProvisionResult{
-			Success: true,
-			ARN:     *result.QueueUrl, // Use URL as fallback
-			Region:  config.Region,
-		}, nil
+		return "", "", fmt.Sprintf("Failed to get dead-letter queue ARN: %s", parseAWSError(err, "SQS"))
 	}
 
-	return &models.ProvisionResult{
-		Success: true,
-		ARN:     attrResult.Attributes[string(sqstypes.QueueAttributeNameQueueArn)],
-		Region:  config.Region,
-	}, nil
+	return *result.QueueUrl, attrResult.Attributes[string(sqstypes.QueueAttributeNameQueueArn)], ""
 }
 
 // ProvisionSNS creates an SNS topic with the specified configuration
-func (p *AWSProvisioner) ProvisionSNS(ctx context.Context, name string, config models.SNSConfig, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+func (p *AWSProvisioner) ProvisionSNS(ctx context.Context, name string, config models.SNSConfig, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
 	awsCfg := p.createAWSConfig(creds, config.Region)
 	client := sns.NewFromConfig(awsCfg)
 
@@ -226,6 +556,10 @@ func (p *AWSProvisioner) ProvisionSNS(ctx context.Context, name string, config m
 		}
 	}
 
+	for k, v := range config.Tags {
+		input.Tags = append(input.Tags, snstypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
 	result, err := client.CreateTopic(ctx, input)
 	if err != nil {
 		return &models.ProvisionResult{
@@ -238,9 +572,808 @@ func (p *AWSProvisioner) ProvisionSNS(ctx context.Context, name string, config m
 		Success: true,
 		ARN:     *result.TopicArn,
 		Region:  config.Region,
+		Tags:    config.Tags,
+	}, nil
+}
+
+// SNSSubscriptionProtocols are the protocols CreateSNSSubscription supports
+var SNSSubscriptionProtocols = []string{"email", "sqs", "https", "lambda"}
+
+// CreateSNSSubscription subscribes an endpoint to a topic. For sqs the endpoint is the
+// queue ARN, and the queue policy is extended so the topic may send to it. Lambda
+// functions must already allow sns.amazonaws.com to invoke them.
+func (p *AWSProvisioner) CreateSNSSubscription(ctx context.Context, topicARN, protocol, endpoint string, creds *models.AWSCredentials) (*models.SNSSubscription, error) {
+	topic, err := arn.Parse(topicARN)
+	if err != nil || topic.Service != "sns" {
+		return nil, fmt.Errorf("invalid SNS topic ARN: %s", topicARN)
+	}
+	if err := validateSNSEndpoint(protocol, endpoint); err != nil {
+		return nil, err
+	}
+
+	if protocol == "sqs" {
+		if err := p.allowTopicToSendToQueue(ctx, topicARN, endpoint, creds); err != nil {
+			return nil, err
+		}
+	}
+
+	client := sns.NewFromConfig(p.createAWSConfig(creds, topic.Region))
+	result, err := client.Subscribe(ctx, &sns.SubscribeInput{
+		TopicArn:              aws.String(topicARN),
+		Protocol:              aws.String(protocol),
+		Endpoint:              aws.String(endpoint),
+		ReturnSubscriptionArn: true,
+	})
+	if err != nil {
+		return nil, errors.New(parseAWSError(err, "SNS"))
+	}
+
+	return &models.SNSSubscription{
+		SubscriptionARN: aws.ToString(result.SubscriptionArn),
+		Protocol:        protocol,
+		Endpoint:        endpoint,
+	}, nil
+}
+
+// ListSNSSubscriptions lists all subscriptions of a topic
+func (p *AWSProvisioner) ListSNSSubscriptions(ctx context.Context, topicARN string, creds *models.AWSCredentials) ([]models.SNSSubscription, error) {
+	topic, err := arn.Parse(topicARN)
+	if err != nil || topic.Service != "sns" {
+		return nil, fmt.Errorf("invalid SNS topic ARN: %s", topicARN)
+	}
+
+	client := sns.NewFromConfig(p.createAWSConfig(creds, topic.Region))
+	paginator := sns.NewListSubscriptionsByTopicPaginator(client, &sns.ListSubscriptionsByTopicInput{
+		TopicArn: aws.String(topicARN),
+	})
+
+	subscriptions := []models.SNSSubscription{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.New(parseAWSError(err, "SNS"))
+		}
+		for _, sub := range page.Subscriptions {
+			subscriptions = append(subscriptions, models.SNSSubscription{
+				SubscriptionARN: aws.ToString(sub.SubscriptionArn),
+				Protocol:        aws.ToString(sub.Protocol),
+				Endpoint:        aws.ToString(sub.Endpoint),
+			})
+		}
+	}
+	return subscriptions, nil
+}
+
+// validateSNSEndpoint checks that endpoint has the form protocol expects
+func validateSNSEndpoint(protocol, endpoint string) error {
+	switch protocol {
+	case "email":
+		if !strings.Contains(endpoint, "@") {
+			return fmt.Errorf("email endpoint must be an email address")
+		}
+	case "https":
+		if !strings.HasPrefix(endpoint, "https://") {
+			return fmt.Errorf("https endpoint must be an https:// URL")
+		}
+	case "sqs", "lambda":
+		resource, err := arn.Parse(endpoint)
+		if err != nil || resource.Service != protocol {
+			return fmt.Errorf("%s endpoint must be an %s ARN", protocol, strings.ToUpper(protocol))
+		}
+	default:
+		return fmt.Errorf("unsupported protocol %q (supported: %s)", protocol, strings.Join(SNSSubscriptionProtocols, ", "))
+	}
+	return nil
+}
+
+// allowTopicToSendToQueue adds a statement to the queue policy letting the topic send
+// messages to it, keeping existing statements. Nothing changes if it is already allowed.
+func (p *AWSProvisioner) allowTopicToSendToQueue(ctx context.Context, topicARN, queueARN string, creds *models.AWSCredentials) error {
+	queue, _ := arn.Parse(queueARN) // Validated by validateSNSEndpoint
+	client := sqs.NewFromConfig(p.createAWSConfig(creds, queue.Region))
+
+	urlResult, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName:              aws.String(queue.Resource),
+		QueueOwnerAWSAccountId: aws.String(queue.AccountID),
+	})
+	if err != nil {
+		return errors.New(parseAWSError(err, "SQS"))
+	}
+
+	attrs, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       urlResult.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNamePolicy},
+	})
+	if err != nil {
+		return errors.New(parseAWSError(err, "SQS"))
+	}
+
+	policy := map[string]interface{}{"Version": "2012-10-17"}
+	if existing := attrs.Attributes[string(sqstypes.QueueAttributeNamePolicy)]; existing != "" {
+		if err := json.Unmarshal([]byte(existing), &policy); err != nil {
+			return fmt.Errorf("failed to parse queue policy: %w", err)
+		}
+	}
+	statements, _ := policy["Statement"].([]interface{})
+
+	sid := queuePolicySid(topicARN)
+	for _, statement := range statements {
+		if m, ok := statement.(map[string]interface{}); ok && m["Sid"] == sid {
+			return nil
+		}
+	}
+	policy["Statement"] = append(statements, map[string]interface{}{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueARN,
+		"Condition": map[string]interface{}{
+			"ArnEquals": map[string]string{"aws:SourceArn": topicARN},
+		},
+	})
+
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to encode queue policy: %w", err)
+	}
+	if _, err := client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   urlResult.QueueUrl,
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): string(document)},
+	}); err != nil {
+		return fmt.Errorf("failed to update queue policy: %s", parseAWSError(err, "SQS"))
+	}
+	return nil
+}
+
+// queuePolicySid names the queue policy statement for a topic; statement IDs may
+// only contain letters and digits
+func queuePolicySid(topicARN string) string {
+	sid := []rune("PortalightSNS")
+	for _, r := range topicARN[strings.LastIndex(topicARN, ":")+1:] {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sid = append(sid, r)
+		}
+	}
+	return string(sid)
+}
+
+// ProvisionDynamoDB creates a DynamoDB table and waits until it is ACTIVE
+func (p *AWSProvisioner) ProvisionDynamoDB(ctx context.Context, name string, config models.DynamoDBConfig, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
+	awsCfg := p.createAWSConfig(creds, config.Region)
+	client := dynamodb.NewFromConfig(awsCfg)
+
+	if config.HashKey == "" {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   "DynamoDB tables require a hash key",
+		}, nil
+	}
+
+	// Key schema and attribute definitions
+	attributes := []dynamodbtypes.AttributeDefinition{
+		{AttributeName: aws.String(config.HashKey), AttributeType: dynamoAttributeType(config.HashKeyType)},
+	}
+	keySchema := []dynamodbtypes.KeySchemaElement{
+		{AttributeName: aws.String(config.HashKey), KeyType: dynamodbtypes.KeyTypeHash},
+	}
+	if config.RangeKey != "" {
+		attributes = append(attributes, dynamodbtypes.AttributeDefinition{
+			AttributeName: aws.String(config.RangeKey),
+			AttributeType: dynamoAttributeType(config.RangeKeyType),
+		})
+		keySchema = append(keySchema, dynamodbtypes.KeySchemaElement{
+			AttributeName: aws.String(config.RangeKey),
+			KeyType:       dynamodbtypes.KeyTypeRange,
+		})
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(name),
+		AttributeDefinitions: attributes,
+		KeySchema:            keySchema,
+		BillingMode:          dynamodbtypes.BillingModePayPerRequest,
+	}
+
+	if config.BillingMode == string(dynamodbtypes.BillingModeProvisioned) {
+		readCapacity, writeCapacity := config.ReadCapacity, config.WriteCapacity
+		if readCapacity <= 0 {
+			readCapacity = 5
+		}
+		if writeCapacity <= 0 {
+			writeCapacity = 5
+		}
+		input.BillingMode = dynamodbtypes.BillingModeProvisioned
+		input.ProvisionedThroughput = &dynamodbtypes.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(readCapacity),
+			WriteCapacityUnits: aws.Int64(writeCapacity),
+		}
+	}
+
+	if config.StreamEnabled {
+		input.StreamSpecification = &dynamodbtypes.StreamSpecification{
+			StreamEnabled:  aws.Bool(true),
+			StreamViewType: dynamodbtypes.StreamViewTypeNewAndOldImages,
+		}
+	}
+
+	for k, v := range config.Tags {
+		input.Tags = append(input.Tags, dynamodbtypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	result, err := client.CreateTable(ctx, input)
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "DynamoDB"),
+		}, nil
+	}
+
+	// Poll until the table is ACTIVE
+	if err := waitForDynamoDBTable(ctx, client, name); err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   fmt.Sprintf("Table created but did not become active: %s", parseAWSError(err, "DynamoDB")),
+		}, nil
+	}
+
+	// TTL can only be configured once the table is active
+	var warnings []string
+	if config.TTLAttribute != "" {
+		_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String(name),
+			TimeToLiveSpecification: &dynamodbtypes.TimeToLiveSpecification{
+				AttributeName: aws.String(config.TTLAttribute),
+				Enabled:       aws.Bool(true),
+			},
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Table created but failed to enable TTL: %s", parseAWSError(err, "DynamoDB")))
+		}
+	}
+
+	return &models.ProvisionResult{
+		Success:  true,
+		ARN:      aws.ToString(result.TableDescription.TableArn),
+		Region:   config.Region,
+		Tags:     config.Tags,
+		Warnings: warnings,
 	}, nil
 }
 
+// ProvisionElastiCache creates a Redis replication group or a Memcached cache cluster
+// and waits until it is available, returning its primary endpoint
+func (p *AWSProvisioner) ProvisionElastiCache(ctx context.Context, name string, config models.ElastiCacheConfig, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
+	awsCfg := p.createAWSConfig(creds, config.Region)
+	client := elasticache.NewFromConfig(awsCfg)
+
+	if config.Engine == "" {
+		config.Engine = "redis"
+	}
+	if config.NodeType == "" {
+		config.NodeType = "cache.t3.micro"
+	}
+	if config.NumNodes <= 0 {
+		config.NumNodes = 1
+	}
+
+	var tags []elasticachetypes.Tag
+	for k, v := range config.Tags {
+		tags = append(tags, elasticachetypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	var (
+		clusterARN, endpoint string
+		err                  error
+	)
+	switch config.Engine {
+	case "redis":
+		if config.AutomaticFailoverEnabled && config.NumNodes < 2 {
+			return &models.ProvisionResult{
+				Success: false,
+				Error:   "Automatic failover requires at least 2 nodes",
+			}, nil
+		}
+		input := &elasticache.CreateReplicationGroupInput{
+			ReplicationGroupId:          aws.String(name),
+			ReplicationGroupDescription: aws.String("Provisioned by Portalight"),
+			Engine:                      aws.String(config.Engine),
+			CacheNodeType:               aws.String(config.NodeType),
+			NumCacheClusters:            aws.Int32(int32(config.NumNodes)),
+			AutomaticFailoverEnabled:    aws.Bool(config.AutomaticFailoverEnabled),
+			SecurityGroupIds:            config.SecurityGroupIDs,
+			Tags:                        tags,
+		}
+		if config.EngineVersion != "" {
+			input.EngineVersion = aws.String(config.EngineVersion)
+		}
+		if config.SubnetGroupName != "" {
+			input.CacheSubnetGroupName = aws.String(config.SubnetGroupName)
+		}
+		if _, err = client.CreateReplicationGroup(ctx, input); err != nil {
+			break
+		}
+		clusterARN, endpoint, err = waitForReplicationGroup(ctx, client, name)
+
+	case "memcached":
+		if config.AutomaticFailoverEnabled {
+			return &models.ProvisionResult{
+				Success: false,
+				Error:   "Automatic failover is only supported by Redis",
+			}, nil
+		}
+		input := &elasticache.CreateCacheClusterInput{
+			CacheClusterId:   aws.String(name),
+			Engine:           aws.String(config.Engine),
+			CacheNodeType:    aws.String(config.NodeType),
+			NumCacheNodes:    aws.Int32(int32(config.NumNodes)),
+			SecurityGroupIds: config.SecurityGroupIDs,
+			Tags:             tags,
+		}
+		if config.EngineVersion != "" {
+			input.EngineVersion = aws.String(config.EngineVersion)
+		}
+		if config.SubnetGroupName != "" {
+			input.CacheSubnetGroupName = aws.String(config.SubnetGroupName)
+		}
+		if _, err = client.CreateCacheCluster(ctx, input); err != nil {
+			break
+		}
+		clusterARN, endpoint, err = waitForCacheCluster(ctx, client, name)
+
+	default:
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   fmt.Sprintf("Unsupported ElastiCache engine %q: use redis or memcached", config.Engine),
+		}, nil
+	}
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "ElastiCache"),
+		}, nil
+	}
+
+	result := &models.ProvisionResult{
+		Success:  true,
+		ARN:      clusterARN,
+		Region:   config.Region,
+		Endpoint: endpoint,
+		Tags:     config.Tags,
+	}
+	if endpoint != "" {
+		result.Details = append(result.Details, "Endpoint: "+endpoint)
+	}
+	return result, nil
+}
+
+// ProvisionLambda creates a Lambda function from a container image or an S3 zip archive
+// and waits until it is Active
+func (p *AWSProvisioner) ProvisionLambda(ctx context.Context, name string, config models.LambdaConfig, creds *models.AWSCredentials, userEmail string) (*models.ProvisionResult, error) {
+	config.Tags = withCreatedBy(config.Tags, userEmail)
+	awsCfg := p.createAWSConfig(creds, config.Region)
+	client := lambda.NewFromConfig(awsCfg)
+
+	input := &lambda.CreateFunctionInput{
+		FunctionName: aws.String(name),
+		Tags:         config.Tags,
+	}
+	switch {
+	case config.ImageURI != "" && config.S3Bucket != "":
+		return &models.ProvisionResult{Success: false, Error: "Set either a container image or an S3 zip archive, not both"}, nil
+	case config.ImageURI != "":
+		input.PackageType = lambdatypes.PackageTypeImage
+		input.Code = &lambdatypes.FunctionCode{ImageUri: aws.String(config.ImageURI)}
+	case config.S3Bucket != "" && config.S3Key != "":
+		if config.Runtime == "" || config.Handler == "" {
+			return &models.ProvisionResult{Success: false, Error: "Functions deployed from a zip archive require a runtime and a handler"}, nil
+		}
+		input.PackageType = lambdatypes.PackageTypeZip
+		input.Code = &lambdatypes.FunctionCode{S3Bucket: aws.String(config.S3Bucket), S3Key: aws.String(config.S3Key)}
+		input.Runtime = lambdatypes.Runtime(config.Runtime)
+		input.Handler = aws.String(config.Handler)
+	default:
+		return &models.ProvisionResult{Success: false, Error: "Lambda functions require a container image URI or an S3 bucket and key"}, nil
+	}
+	if config.RoleARN == "" && !config.CreateRole {
+		return &models.ProvisionResult{Success: false, Error: "Lambda functions require an execution role ARN or create_role"}, nil
+	}
+
+	if config.MemoryMB > 0 {
+		input.MemorySize = aws.Int32(int32(config.MemoryMB))
+	}
+	if config.TimeoutSeconds > 0 {
+		input.Timeout = aws.Int32(int32(config.TimeoutSeconds))
+	}
+	if len(config.Environment) > 0 {
+		input.Environment = &lambdatypes.Environment{Variables: config.Environment}
+	}
+
+	var details []string
+	roleARN, roleName := config.RoleARN, ""
+	if roleARN == "" {
+		var err error
+		roleName = lambdaExecutionRoleName(name)
+		roleARN, err = p.createLambdaExecutionRole(ctx, awsCfg, roleName, config.Tags)
+		if err != nil {
+			return &models.ProvisionResult{
+				Success: false,
+				Error:   fmt.Sprintf("Failed to create execution role: %s", parseAWSError(err, "IAM")),
+			}, nil
+		}
+		details = append(details, "Execution role: "+roleARN)
+	}
+	input.Role = aws.String(roleARN)
+
+	result, err := createLambdaFunction(ctx, client, input, roleName != "")
+	if err != nil {
+		// Don't leave an unused execution role behind
+		if roleName != "" {
+			if delErr := p.deleteLambdaExecutionRole(ctx, awsCfg, roleName); delErr != nil {
+				logging.FromContext(ctx).Error("Failed to clean up execution role", slog.String("role", roleName), slog.Any("error", delErr))
+			}
+		}
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "Lambda"),
+		}, nil
+	}
+
+	if err := waitForLambdaFunction(ctx, client, name); err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   fmt.Sprintf("Function created but did not become active: %s", parseAWSError(err, "Lambda")),
+		}, nil
+	}
+
+	return &models.ProvisionResult{
+		Success: true,
+		ARN:     aws.ToString(result.FunctionArn),
+		Region:  config.Region,
+		Tags:    config.Tags,
+		Details: details,
+	}, nil
+}
+
+// createLambdaFunction calls CreateFunction; with a freshly created role it retries while
+// Lambda cannot assume the role yet
+func createLambdaFunction(ctx context.Context, client *lambda.Client, input *lambda.CreateFunctionInput, newRole bool) (*lambda.CreateFunctionOutput, error) {
+	deadline := time.Now().Add(lambdaRolePropagationTimeout)
+	for {
+		result, err := client.CreateFunction(ctx, input)
+		var invalidParam *lambdatypes.InvalidParameterValueException
+		if err == nil || !newRole || time.Now().After(deadline) ||
+			!errors.As(err, &invalidParam) || !strings.Contains(invalidParam.ErrorMessage(), "cannot be assumed") {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lambdaPollInterval):
+		}
+	}
+}
+
+// lambdaExecutionRoleName derives the execution role name from the function name,
+// keeping it within IAM's 64 character limit
+func lambdaExecutionRoleName(functionName string) string {
+	const suffix = "-execution-role"
+	if len(functionName) > 64-len(suffix) {
+		functionName = functionName[:64-len(suffix)]
+	}
+	return functionName + suffix
+}
+
+// createLambdaExecutionRole creates a role Lambda can assume with only the basic
+// execution (CloudWatch Logs) policy attached
+func (p *AWSProvisioner) createLambdaExecutionRole(ctx context.Context, awsCfg aws.Config, roleName string, tags map[string]string) (string, error) {
+	client := iam.NewFromConfig(awsCfg)
+
+	input := &iam.CreateRoleInput{
+		RoleName:                 aws.String(roleName),
+		AssumeRolePolicyDocument: aws.String(lambdaAssumeRolePolicy),
+		Description:              aws.String("Lambda execution role created by Portalight"),
+	}
+	for k, v := range tags {
+		input.Tags = append(input.Tags, iamtypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	result, err := client.CreateRole(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(lambdaBasicExecutionPolicyARN),
+	})
+	if err != nil {
+		if delErr := p.deleteLambdaExecutionRole(ctx, awsCfg, roleName); delErr != nil {
+			logging.FromContext(ctx).Error("Failed to clean up execution role", slog.String("role", roleName), slog.Any("error", delErr))
+		}
+		return "", err
+	}
+	return aws.ToString(result.Role.Arn), nil
+}
+
+// deleteLambdaExecutionRole detaches the basic execution policy and deletes the role
+func (p *AWSProvisioner) deleteLambdaExecutionRole(ctx context.Context, awsCfg aws.Config, roleName string) error {
+	client := iam.NewFromConfig(awsCfg)
+
+	_, err := client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(lambdaBasicExecutionPolicyARN),
+	})
+	var notFound *iamtypes.NoSuchEntityException
+	if err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	_, err = client.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String(roleName)})
+	return err
+}
+
+// DeprovisionS3 deletes an S3 bucket; AWS refuses to delete buckets that still contain objects
+func (p *AWSProvisioner) DeprovisionS3(ctx context.Context, name string, region string, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+	awsCfg := p.createAWSConfig(creds, region)
+	client := s3.NewFromConfig(awsCfg)
+
+	_, err := client.DeleteBucket(ctx, &s3.DeleteBucketInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "S3"),
+		}, nil
+	}
+
+	return &models.ProvisionResult{
+		Success: true,
+		ARN:     fmt.Sprintf("arn:aws:s3:::%s", name),
+		Region:  region,
+	}, nil
+}
+
+// DeprovisionSQS purges and deletes an SQS queue
+func (p *AWSProvisioner) DeprovisionSQS(ctx context.Context, name string, region string, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+	awsCfg := p.createAWSConfig(creds, region)
+	client := sqs.NewFromConfig(awsCfg)
+
+	urlResult, err := client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: aws.String(name),
+	})
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "SQS"),
+		}, nil
+	}
+
+	// A purge already in progress is fine, the queue is being emptied either way
+	_, err = client.PurgeQueue(ctx, &sqs.PurgeQueueInput{
+		QueueUrl: urlResult.QueueUrl,
+	})
+	var purgeInProgress *sqstypes.PurgeQueueInProgress
+	if err != nil && !errors.As(err, &purgeInProgress) {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to purge queue: %s", parseAWSError(err, "SQS")),
+		}, nil
+	}
+
+	_, err = client.DeleteQueue(ctx, &sqs.DeleteQueueInput{
+		QueueUrl: urlResult.QueueUrl,
+	})
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "SQS"),
+		}, nil
+	}
+
+	return &models.ProvisionResult{
+		Success: true,
+		Region:  region,
+	}, nil
+}
+
+// DeprovisionSNS deletes an SNS topic and its subscriptions
+func (p *AWSProvisioner) DeprovisionSNS(ctx context.Context, topicARN string, region string, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+	awsCfg := p.createAWSConfig(creds, region)
+	client := sns.NewFromConfig(awsCfg)
+
+	_, err := client.DeleteTopic(ctx, &sns.DeleteTopicInput{
+		TopicArn: aws.String(topicARN),
+	})
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "SNS"),
+		}, nil
+	}
+
+	return &models.ProvisionResult{
+		Success: true,
+		ARN:     topicARN,
+		Region:  region,
+	}, nil
+}
+
+// DeprovisionElastiCache deletes the Redis replication group or Memcached cache cluster
+// identified by its ARN, without taking a final snapshot
+func (p *AWSProvisioner) DeprovisionElastiCache(ctx context.Context, resourceARN string, region string, creds *models.AWSCredentials) (*models.ProvisionResult, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ElastiCache ARN %q: %w", resourceARN, err)
+	}
+	kind, id, _ := strings.Cut(parsed.Resource, ":")
+
+	awsCfg := p.createAWSConfig(creds, region)
+	client := elasticache.NewFromConfig(awsCfg)
+
+	switch kind {
+	case "replicationgroup":
+		_, err = client.DeleteReplicationGroup(ctx, &elasticache.DeleteReplicationGroupInput{
+			ReplicationGroupId: aws.String(id),
+		})
+	case "cluster":
+		_, err = client.DeleteCacheCluster(ctx, &elasticache.DeleteCacheClusterInput{
+			CacheClusterId: aws.String(id),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported ElastiCache resource %q", parsed.Resource)
+	}
+	if err != nil {
+		return &models.ProvisionResult{
+			Success: false,
+			Error:   parseAWSError(err, "ElastiCache"),
+		}, nil
+	}
+
+	return &models.ProvisionResult{
+		Success: true,
+		ARN:     resourceARN,
+		Region:  region,
+	}, nil
+}
+
+// waitForDynamoDBTable polls DescribeTable until the table status is ACTIVE
+func waitForDynamoDBTable(ctx context.Context, client *dynamodb.Client, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, dynamoDBActiveTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(dynamoDBPollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(name)})
+		if err != nil {
+			return err
+		}
+		if out.Table != nil && out.Table.TableStatus == dynamodbtypes.TableStatusActive {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for table %s to become active", name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForLambdaFunction polls GetFunction until the function state is Active
+func waitForLambdaFunction(ctx context.Context, client *lambda.Client, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, lambdaActiveTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(lambdaPollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := client.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: aws.String(name)})
+		if err != nil {
+			return err
+		}
+		if out.Configuration != nil {
+			switch out.Configuration.State {
+			case lambdatypes.StateActive:
+				return nil
+			case lambdatypes.StateFailed:
+				return fmt.Errorf("function %s failed to start: %s", name, aws.ToString(out.Configuration.StateReason))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for function %s to become active", name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForReplicationGroup polls DescribeReplicationGroups until the group is available
+// and returns its ARN and primary endpoint
+func waitForReplicationGroup(ctx context.Context, client *elasticache.Client, id string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, elastiCacheAvailableTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(elastiCachePollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := client.DescribeReplicationGroups(ctx, &elasticache.DescribeReplicationGroupsInput{ReplicationGroupId: aws.String(id)})
+		if err != nil {
+			return "", "", err
+		}
+		if len(out.ReplicationGroups) > 0 && aws.ToString(out.ReplicationGroups[0].Status) == "available" {
+			group := out.ReplicationGroups[0]
+			// Cluster mode disabled groups have a single node group with the primary endpoint
+			endpoint := group.ConfigurationEndpoint
+			if len(group.NodeGroups) > 0 && group.NodeGroups[0].PrimaryEndpoint != nil {
+				endpoint = group.NodeGroups[0].PrimaryEndpoint
+			}
+			return aws.ToString(group.ARN), formatCacheEndpoint(endpoint), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", "", fmt.Errorf("timed out waiting for replication group %s to become available", id)
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForCacheCluster polls DescribeCacheClusters until the cluster is available
+// and returns its ARN and configuration endpoint
+func waitForCacheCluster(ctx context.Context, client *elasticache.Client, id string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, elastiCacheAvailableTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(elastiCachePollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := client.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{CacheClusterId: aws.String(id)})
+		if err != nil {
+			return "", "", err
+		}
+		if len(out.CacheClusters) > 0 && aws.ToString(out.CacheClusters[0].CacheClusterStatus) == "available" {
+			cluster := out.CacheClusters[0]
+			return aws.ToString(cluster.ARN), formatCacheEndpoint(cluster.ConfigurationEndpoint), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", "", fmt.Errorf("timed out waiting for cache cluster %s to become available", id)
+		case <-ticker.C:
+		}
+	}
+}
+
+// formatCacheEndpoint renders an ElastiCache endpoint as host:port
+func formatCacheEndpoint(endpoint *elasticachetypes.Endpoint) string {
+	if endpoint == nil || endpoint.Address == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", aws.ToString(endpoint.Address), aws.ToInt32(endpoint.Port))
+}
+
+// dynamoAttributeType maps a config key type to a DynamoDB attribute type (defaults to string)
+func dynamoAttributeType(keyType string) dynamodbtypes.ScalarAttributeType {
+	switch strings.ToUpper(keyType) {
+	case "N":
+		return dynamodbtypes.ScalarAttributeTypeN
+	case "B":
+		return dynamodbtypes.ScalarAttributeTypeB
+	default:
+		return dynamodbtypes.ScalarAttributeTypeS
+	}
+}
+
 // parseAWSError converts AWS errors to user-friendly messages
 func parseAWSError(err error, service string) string {
 	var apiErr smithy.APIError
@@ -248,12 +1381,21 @@ func parseAWSError(err error, service string) string {
 		code := apiErr.ErrorCode()
 		message := apiErr.ErrorMessage()
 
+		// Creating a function or role with a role the caller may not hand over to Lambda
+		if strings.Contains(message, "iam:PassRole") {
+			return "Access denied: your IAM user needs iam:PassRole on the execution role to create Lambda functions."
+		}
+
 		switch code {
 		// S3 errors
 		case "BucketAlreadyExists":
 			return "A bucket with this name already exists globally. S3 bucket names must be unique across all AWS accounts."
 		case "BucketAlreadyOwnedByYou":
 			return "You already own a bucket with this name."
+		case "BucketNotEmpty":
+			return "The bucket is not empty. Delete all objects (and versions) before deleting the bucket."
+		case "NoSuchBucket":
+			return "The bucket does not exist."
 		case "InvalidBucketName":
 			return fmt.Sprintf("Invalid bucket name: %s. Bucket names must be 3-63 characters, lowercase, and can contain only letters, numbers, and hyphens.", message)
 
@@ -262,10 +1404,45 @@ func parseAWSError(err error, service string) string {
 			return "A queue with this name already exists."
 		case "QueueNameExists":
 			return "A queue with this name already exists with different attributes."
+		case "AWS.SimpleQueueService.NonExistentQueue", "QueueDoesNotExist":
+			return "The queue does not exist."
 
 		// SNS errors
 		case "TopicLimitExceeded":
 			return "You have reached the maximum number of SNS topics for your account."
+		case "NotFound":
+			return "The topic does not exist."
+
+		// DynamoDB errors
+		case "ResourceInUseException":
+			return "A table with this name already exists or is being created."
+		case "LimitExceededException":
+			return fmt.Sprintf("AWS limit exceeded: %s", message)
+
+		// ElastiCache errors
+		case "ReplicationGroupAlreadyExists", "CacheClusterAlreadyExists":
+			return "A cache cluster with this name already exists."
+		case "ReplicationGroupNotFoundFault", "CacheClusterNotFound":
+			return "The cache cluster does not exist."
+		case "CacheSubnetGroupNotFoundFault":
+			return "The cache subnet group does not exist."
+		case "InsufficientCacheClusterCapacity":
+			return fmt.Sprintf("Not enough capacity for this node type: %s", message)
+
+		// Lambda and IAM errors
+		case "ResourceConflictException":
+			return fmt.Sprintf("The resource already exists or is being modified: %s", message)
+		case "InvalidParameterValueException":
+			if strings.Contains(message, "cannot be assumed") {
+				return "Lambda cannot assume the execution role. Check that its trust policy allows lambda.amazonaws.com."
+			}
+			return fmt.Sprintf("Invalid parameter: %s", message)
+		case "CodeStorageExceededException":
+			return "The account's Lambda code storage limit has been reached."
+		case "EntityAlreadyExists":
+			return "An IAM role with this name already exists. Pass its ARN as role_arn instead of create_role."
+		case "NoSuchEntity":
+			return fmt.Sprintf("IAM entity not found: %s", message)
 
 		// Common errors
 		case "InvalidClientTokenId":
@@ -273,7 +1450,7 @@ func parseAWSError(err error, service string) string {
 		case "SignatureDoesNotMatch":
 			return "Invalid AWS credentials. Please check your Secret Access Key."
 		case "AccessDenied", "AccessDeniedException":
-			return fmt.Sprintf("Access denied. Ensure your IAM user has permissions to create %s resources.", service)
+			return fmt.Sprintf("Access denied. Ensure your IAM user has permissions to manage %s resources.", service)
 		case "UnauthorizedAccess":
 			return "Unauthorized access. Please check your AWS credentials and permissions."
 		case "InvalidParameterValue":
@@ -0,0 +1,73 @@
+package services
+
+import "github.com/portalight/backend/internal/models"
+
+// Tag keys applied to resources provisioned through portalight. Both the
+// project-id/provisioned-by keys and the shorter project/created-by keys are
+// written, since tag policies and cost reports already filter on each set
+const (
+	TagProjectID     = "portalight:project-id"
+	TagProject       = "portalight:project"
+	TagProjectName   = "portalight:project-name"
+	TagProvisionedBy = "portalight:provisioned-by"
+	TagCreatedBy     = "portalight:created-by"
+)
+
+// Tags marking a resource as managed by infrastructure as code
+const (
+	TagCloudFormationStack = "aws:cloudformation:stack-name"
+	TagManagedBy           = "ManagedBy"
+)
+
+// ManagedByFromTags returns terraform for resources whose tags show that infrastructure
+// as code manages them, and "" when the tags say nothing
+func ManagedByFromTags(tags map[string]string) string {
+	if _, ok := tags[TagCloudFormationStack]; ok {
+		return models.ManagedByTerraform
+	}
+	if _, ok := tags[TagManagedBy]; ok {
+		return models.ManagedByTerraform
+	}
+	return ""
+}
+
+// PortalightTags builds the project ownership tags applied to provisioned resources
+func PortalightTags(projectID, projectName string) map[string]string {
+	tags := map[string]string{
+		TagProjectID: projectID,
+		TagProject:   projectID,
+	}
+	if projectName != "" {
+		tags[TagProjectName] = projectName
+	}
+	return tags
+}
+
+// withCreatedBy adds the portalight:provisioned-by and portalight:created-by tags;
+// they always win over user-supplied tags
+func withCreatedBy(tags map[string]string, userEmail string) map[string]string {
+	if userEmail == "" {
+		return tags
+	}
+	return MergeTags(tags, map[string]string{TagProvisionedBy: userEmail, TagCreatedBy: userEmail})
+}
+
+// ProjectIDFromTags returns the owning project from either project tag, and "" when neither is set
+func ProjectIDFromTags(tags map[string]string) string {
+	if projectID := tags[TagProjectID]; projectID != "" {
+		return projectID
+	}
+	return tags[TagProject]
+}
+
+// MergeTags returns a new map with the overrides applied on top of the base tags
+func MergeTags(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
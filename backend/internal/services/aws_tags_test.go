@@ -0,0 +1,37 @@
+package services
+
+import "testing"
+
+func TestProvisionTagsCarryBothKeySets(t *testing.T) {
+	tags := withCreatedBy(PortalightTags("proj-1", "payments"), "dev@example.com")
+	for key, want := range map[string]string{
+		TagProjectID:     "proj-1",
+		TagProject:       "proj-1",
+		TagProjectName:   "payments",
+		TagProvisionedBy: "dev@example.com",
+		TagCreatedBy:     "dev@example.com",
+	} {
+		if tags[key] != want {
+			t.Errorf("tag %s = %q, want %q", key, tags[key], want)
+		}
+	}
+}
+
+func TestDiscoverySuggestsProjectFromEitherKey(t *testing.T) {
+	tests := []struct {
+		tags map[string]string
+		want string
+	}{
+		{map[string]string{TagProjectID: "proj-1"}, "proj-1"},
+		{map[string]string{TagProject: "proj-2"}, "proj-2"},
+		{map[string]string{TagProjectID: "proj-1", TagProject: "proj-2"}, "proj-1"},
+		{map[string]string{"team": "payments"}, ""},
+	}
+	for _, tt := range tests {
+		var r DiscoveredResource
+		r.applyTags(tt.tags)
+		if r.SuggestedProjectID != tt.want {
+			t.Errorf("tags %v: suggested project = %q, want %q", tt.tags, r.SuggestedProjectID, tt.want)
+		}
+	}
+}
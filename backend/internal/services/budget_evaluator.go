@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ErrNoCostEstimator is returned for estimated_cost budgets when no cost source is configured
+var ErrNoCostEstimator = errors.New("no cost estimator configured")
+
+// CostEstimator estimates the monthly cost (USD) of a project's resources
+type CostEstimator interface {
+	EstimateMonthlyCost(ctx context.Context, projectID string) (float64, error)
+}
+
+// BudgetNotifier delivers a budget breach alert to the budget's channel
+type BudgetNotifier interface {
+	NotifyBreach(ctx context.Context, budget models.ProjectBudget, event models.BudgetEvent) error
+}
+
+// BudgetEvaluator periodically compares project budgets against current usage,
+// records breaches and notifies once per breach until it is resolved
+type BudgetEvaluator struct {
+	budgetRepo   *repositories.BudgetRepository
+	resourceRepo *repositories.ResourceRepository
+	costs        CostEstimator // Optional; estimated_cost budgets are skipped without it
+	notifier     BudgetNotifier
+	mu           sync.Mutex
+	stopCh       chan struct{}
+	running      bool
+}
+
+// NewBudgetEvaluator creates a budget evaluator; costs may be nil
+func NewBudgetEvaluator(resourceRepo *repositories.ResourceRepository, costs CostEstimator, notifier BudgetNotifier) *BudgetEvaluator {
+	return &BudgetEvaluator{
+		budgetRepo:   repositories.NewBudgetRepository(),
+		resourceRepo: resourceRepo,
+		costs:        costs,
+		notifier:     notifier,
+	}
+}
+
+// CurrentValue computes the value a budget is measured against
+func (e *BudgetEvaluator) CurrentValue(ctx context.Context, budget models.ProjectBudget) (float64, error) {
+	switch budget.Type {
+	case models.BudgetTypeResourceCount:
+		count, err := e.resourceRepo.CountActiveByProject(ctx, budget.ProjectID, budget.ResourceType)
+		return float64(count), err
+	case models.BudgetTypeEstimatedCost:
+		if e.costs == nil {
+			return 0, ErrNoCostEstimator
+		}
+		return e.costs.EstimateMonthlyCost(ctx, budget.ProjectID)
+	default:
+		return 0, fmt.Errorf("unknown budget type: %s", budget.Type)
+	}
+}
+
+// EvaluateAll checks every budget once
+func (e *BudgetEvaluator) EvaluateAll(ctx context.Context) {
+	budgets, err := e.budgetRepo.ListAll(ctx)
+	if err != nil {
+		log.Printf("Failed to load project budgets: %v", err)
+		return
+	}
+
+	for _, budget := range budgets {
+		if err := e.Evaluate(ctx, budget); err != nil {
+			log.Printf("Failed to evaluate budget %s of project %s: %v", budget.ID, budget.ProjectName, err)
+		}
+	}
+	log.Printf("Evaluated %d project budgets", len(budgets))
+}
+
+// Evaluate checks one budget, opening or resolving its breach as needed
+func (e *BudgetEvaluator) Evaluate(ctx context.Context, budget models.ProjectBudget) error {
+	value, err := e.CurrentValue(ctx, budget)
+	if errors.Is(err, ErrNoCostEstimator) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if value <= budget.Threshold {
+		resolved, err := e.budgetRepo.ResolveBreach(ctx, budget.ID, value)
+		if err != nil {
+			return err
+		}
+		if resolved {
+			log.Printf("Budget %s of project %s is back under its threshold (%.2f <= %.2f)", budget.ID, budget.ProjectName, value, budget.Threshold)
+		}
+		return nil
+	}
+
+	event, err := e.budgetRepo.RecordBreach(ctx, &budget, value)
+	if err != nil {
+		return err
+	}
+
+	// Notify once per breach; a failed delivery is retried on the next run
+	if event.NotifiedAt != nil || budget.NotifyChannel == "" || e.notifier == nil {
+		return nil
+	}
+	if err := e.notifier.NotifyBreach(ctx, budget, *event); err != nil {
+		return fmt.Errorf("failed to send breach notification: %w", err)
+	}
+	return e.budgetRepo.MarkNotified(ctx, event.ID)
+}
+
+// Start runs EvaluateAll immediately and then on every interval
+func (e *BudgetEvaluator) Start(interval time.Duration) {
+	e.mu.Lock()
+	if e.running {
+		e.mu.Unlock()
+		return
+	}
+	e.running = true
+	e.stopCh = make(chan struct{})
+	e.mu.Unlock()
+
+	go func() {
+		e.EvaluateAll(context.Background())
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.EvaluateAll(context.Background())
+			case <-e.stopCh:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Budget evaluator started with interval: %v", interval)
+}
+
+// Stop stops the periodic evaluation
+func (e *BudgetEvaluator) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.running {
+		close(e.stopCh)
+		e.running = false
+		log.Println("Budget evaluator stopped")
+	}
+}
+
+// WebhookBudgetNotifier posts breach alerts to the budget's incoming webhook URL
+type WebhookBudgetNotifier struct {
+	notifications *NotificationService
+}
+
+// NewWebhookBudgetNotifier creates a webhook notifier
+func NewWebhookBudgetNotifier(notifications *NotificationService) *WebhookBudgetNotifier {
+	return &WebhookBudgetNotifier{notifications: notifications}
+}
+
+// NotifyBreach sends the breach message to budget.NotifyChannel
+func (n *WebhookBudgetNotifier) NotifyBreach(ctx context.Context, budget models.ProjectBudget, event models.BudgetEvent) error {
+	subject := "resources"
+	if budget.ResourceType != "" {
+		subject = budget.ResourceType + " resources"
+	}
+	text := fmt.Sprintf("⚠️ Project %s exceeds its %s budget: %.0f %s (threshold %.0f)",
+		budget.ProjectName, budget.Type, event.Value, subject, event.Threshold)
+	if budget.Type == models.BudgetTypeEstimatedCost {
+		text = fmt.Sprintf("⚠️ Project %s exceeds its estimated cost budget: $%.2f/month (threshold $%.2f)",
+			budget.ProjectName, event.Value, event.Threshold)
+	}
+
+	return n.notifications.SendSlack(ctx, budget.NotifyChannel, Notification{
+		Subject: "Budget breach: " + budget.ProjectName,
+		Text:    text,
+	})
+}
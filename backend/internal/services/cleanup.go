@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/database"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// cleanupQueryTimeout bounds each purge, which may delete a backlog of rows on its first run
+const cleanupQueryTimeout = 5 * time.Minute
+
+// ResourceCleanupJob periodically purges rows that are kept only for a while:
+// discovered resources marked deleted and revocations of expired tokens
+type ResourceCleanupJob struct {
+	resourceRepo     *repositories.DiscoveredResourceRepository
+	revokedTokenRepo *repositories.RevokedTokenRepository
+	mu               sync.Mutex
+	stopCh           chan struct{}
+	running          bool
+}
+
+// NewResourceCleanupJob creates a cleanup job
+func NewResourceCleanupJob(resourceRepo *repositories.DiscoveredResourceRepository, revokedTokenRepo *repositories.RevokedTokenRepository) *ResourceCleanupJob {
+	return &ResourceCleanupJob{
+		resourceRepo:     resourceRepo,
+		revokedTokenRepo: revokedTokenRepo,
+	}
+}
+
+// Start runs a cleanup immediately and then on every interval. Deleted resources
+// are purged once they have been deleted for longer than retentionPeriod.
+func (j *ResourceCleanupJob) Start(interval, retentionPeriod time.Duration) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return
+	}
+	j.running = true
+	j.stopCh = make(chan struct{})
+	j.mu.Unlock()
+
+	go func() {
+		j.runCleanup(context.Background(), retentionPeriod)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				j.runCleanup(context.Background(), retentionPeriod)
+			case <-j.stopCh:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Resource cleanup job started with interval: %v, retention: %v", interval, retentionPeriod)
+}
+
+// Stop stops the periodic cleanup
+func (j *ResourceCleanupJob) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		close(j.stopCh)
+		j.running = false
+		log.Println("Resource cleanup job stopped")
+	}
+}
+
+// runCleanup performs one cleanup cycle; a failing step does not skip the others
+func (j *ResourceCleanupJob) runCleanup(ctx context.Context, retentionPeriod time.Duration) {
+	ctx = database.WithQueryTimeout(ctx, cleanupQueryTimeout)
+	purged, err := j.resourceRepo.PurgeDeleted(ctx, retentionPeriod)
+	if err != nil {
+		log.Printf("Failed to purge deleted discovered resources: %v", err)
+	} else {
+		log.Printf("🧹 Purged %d discovered resources deleted more than %v ago", purged, retentionPeriod)
+	}
+
+	tokens, err := j.revokedTokenRepo.PurgeExpired(ctx)
+	if err != nil {
+		log.Printf("Failed to purge revoked tokens: %v", err)
+	} else {
+		log.Printf("🧹 Purged %d expired revoked tokens", tokens)
+	}
+}
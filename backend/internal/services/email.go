@@ -0,0 +1,87 @@
+package services
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+// Each notification event has a text template (also used for Slack) and an HTML
+// template that fills the "title" and "content" blocks of layout.html
+//
+//go:embed email_templates
+var emailTemplateFS embed.FS
+
+// digestSection is one list of items in the HTML digest
+type digestSection struct {
+	Title string
+	Items []models.DigestItem
+}
+
+var emailFuncs = map[string]interface{}{
+	"item": func(i models.DigestItem) string {
+		if i.Detail == "" {
+			return i.Project + " / " + i.Name
+		}
+		return fmt.Sprintf("%s / %s (%s)", i.Project, i.Name, i.Detail)
+	},
+	"section": func(title string, items []models.DigestItem) digestSection {
+		return digestSection{Title: title, Items: items}
+	},
+}
+
+var (
+	emailTextTemplates = make(map[string]*texttemplate.Template)
+	emailHTMLTemplates = make(map[string]*htmltemplate.Template)
+)
+
+func init() {
+	for _, event := range models.NotificationEvents {
+		emailTextTemplates[event] = texttemplate.Must(texttemplate.New(event+".txt").
+			Funcs(emailFuncs).ParseFS(emailTemplateFS, "email_templates/"+event+".txt"))
+		emailHTMLTemplates[event] = htmltemplate.Must(htmltemplate.New("layout.html").
+			Funcs(emailFuncs).ParseFS(emailTemplateFS, "email_templates/layout.html", "email_templates/"+event+".html"))
+	}
+}
+
+// ProvisioningEmail is the data of the provisioning_succeeded and provisioning_failed emails
+type ProvisioningEmail struct {
+	ResourceName string
+	ResourceType string
+	ProjectName  string
+	ARN          string
+	Details      []string
+	Error        string
+}
+
+// SyncFailureEmail is the data of the sync_failed email
+type SyncFailureEmail struct {
+	ProjectName      string
+	CatalogFilePath  string
+	SyncType         string
+	Error            string
+	ValidationErrors []string
+	StartedAt        time.Time
+}
+
+// RenderEmail renders the text and HTML bodies of a notification event
+func RenderEmail(event, subject string, data interface{}) (Notification, error) {
+	textTemplate, ok := emailTextTemplates[event]
+	if !ok {
+		return Notification{}, fmt.Errorf("unknown notification event %q", event)
+	}
+
+	var text, html strings.Builder
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return Notification{}, fmt.Errorf("failed to render %s email: %w", event, err)
+	}
+	if err := emailHTMLTemplates[event].Execute(&html, data); err != nil {
+		return Notification{}, fmt.Errorf("failed to render %s email: %w", event, err)
+	}
+	return Notification{Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares got with testdata/<name>, or rewrites the file with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the golden file; run go test -update and review the diff\n--- got ---\n%s", name, got)
+	}
+}
+
+// assertRenderGolden renders an event and compares both bodies with their golden files
+func assertRenderGolden(t *testing.T, event, name string, data interface{}) {
+	t.Helper()
+	notification, err := RenderEmail(event, "subject", data)
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	assertGolden(t, name+".txt.golden", notification.Text)
+	assertGolden(t, name+".html.golden", notification.HTML)
+}
+
+func TestRenderTeamDigestGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		digest *models.TeamDigest
+	}{
+		{"team_digest", &models.TeamDigest{
+			TeamName: "Payments",
+			Date:     time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			Timezone: "Europe/Berlin",
+			ProjectsSynced: []models.DigestItem{
+				{Project: "payments", Name: "payments", Detail: "success"},
+				{Project: "ledger", Name: "ledger", Detail: "failed"},
+			},
+			ServicesAdded:        []models.DigestItem{{Project: "payments", Name: "payments-worker"}},
+			ServicesRemoved:      []models.DigestItem{{Project: "payments", Name: "legacy-gateway"}},
+			ResourcesProvisioned: []models.DigestItem{{Project: "payments", Name: "payments-events", Detail: "sqs"}},
+			ResourcesFailed:      []models.DigestItem{{Project: "ledger", Name: "ledger-db", Detail: "rds"}},
+			ResourcesDeleted:     []models.DigestItem{{Project: "payments", Name: "tmp-exports", Detail: "s3"}},
+			BudgetBreaches: []models.DigestBudgetBreach{
+				{Project: "payments", Type: models.BudgetTypeResourceCount, Value: 12, Threshold: 10},
+			},
+		}},
+		// Empty sections are left out
+		{"team_digest_partial", &models.TeamDigest{
+			TeamName:             "Payments",
+			Date:                 time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			Timezone:             "UTC",
+			ResourcesProvisioned: []models.DigestItem{{Project: "payments", Name: "payments-events", Detail: "sqs"}},
+		}},
+		// Names are escaped in HTML
+		{"team_digest_escaping", &models.TeamDigest{
+			TeamName:      "R&D <core>",
+			Date:          time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+			Timezone:      "UTC",
+			ServicesAdded: []models.DigestItem{{Project: "lab", Name: "<script>alert(1)</script>"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRenderGolden(t, models.NotificationTeamDigest, tt.name, tt.digest)
+		})
+	}
+}
+
+func TestRenderEventEmailsGolden(t *testing.T) {
+	tests := []struct {
+		event string
+		name  string
+		data  interface{}
+	}{
+		{models.NotificationProvisioningSucceeded, "provisioning_succeeded", ProvisioningEmail{
+			ResourceName: "payments-events",
+			ResourceType: "sqs",
+			ProjectName:  "payments",
+			ARN:          "arn:aws:sqs:eu-west-1:123456789012:payments-events",
+			Details:      []string{"FIFO queue", "Visibility timeout: 30s"},
+		}},
+		{models.NotificationProvisioningSucceeded, "provisioning_succeeded_minimal", ProvisioningEmail{
+			ResourceName: "payments-exports",
+			ResourceType: "s3",
+		}},
+		{models.NotificationProvisioningFailed, "provisioning_failed", ProvisioningEmail{
+			ResourceName: "ledger-db",
+			ResourceType: "rds",
+			ProjectName:  "ledger",
+			Error:        `DBInstanceAlreadyExists: DB instance "ledger-db" already exists`,
+		}},
+		{models.NotificationSyncFailed, "sync_failed", SyncFailureEmail{
+			ProjectName:      "payments",
+			CatalogFilePath:  "catalog-info.yaml",
+			SyncType:         "webhook",
+			Error:            "catalog validation failed",
+			ValidationErrors: []string{"metadata.owner: is required", "spec.services[0].title: is required"},
+			StartedAt:        time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC),
+		}},
+		{models.NotificationSyncFailed, "sync_failed_no_validation_errors", SyncFailureEmail{
+			CatalogFilePath: "services/catalog-info.yaml",
+			SyncType:        "scheduled",
+			Error:           "failed to fetch file: 404 Not Found",
+			StartedAt:       time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRenderGolden(t, tt.event, tt.name, tt.data)
+		})
+	}
+}
+
+func TestRenderEmailCoversEveryEvent(t *testing.T) {
+	for _, event := range models.NotificationEvents {
+		if emailTextTemplates[event] == nil || emailHTMLTemplates[event] == nil {
+			t.Errorf("event %s has no email templates", event)
+		}
+	}
+	if _, err := RenderEmail("unknown_event", "subject", nil); err == nil {
+		t.Error("rendering an unknown event did not fail")
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ErrEmailNotConfigured is returned when an email is sent without SMTP settings
+var ErrEmailNotConfigured = errors.New("SMTP is not configured")
+
+const (
+	smtpTimeout       = 30 * time.Second // Per delivery attempt
+	smtpMaxAttempts   = 3
+	smtpRetryBaseWait = 2 * time.Second // Doubled after every failed attempt
+)
+
+// Notification is a message delivered to people outside the portal
+type Notification struct {
+	Subject string // Email subject; Slack messages show only Text
+	Text    string
+	HTML    string // Optional HTML alternative for email
+}
+
+// SMTPConfig holds the outgoing mail server settings; an empty Host disables email
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	TLSMode  string // models.SMTPTLS*; empty uses STARTTLS when the server offers it
+	Username string
+	Password string `redact:"true"`
+	From     string
+}
+
+// NotificationService delivers notifications over Slack incoming webhooks and SMTP
+type NotificationService struct {
+	client       *http.Client
+	smtp         SMTPConfig // From the environment
+	settingsRepo *repositories.SMTPSettingsRepository
+}
+
+// NewNotificationService creates a notification service. SMTP settings saved in the
+// UI take precedence over smtpConfig, which may be empty.
+func NewNotificationService(smtpConfig SMTPConfig, settingsRepo *repositories.SMTPSettingsRepository) *NotificationService {
+	return &NotificationService{
+		client:       &http.Client{Timeout: 10 * time.Second},
+		smtp:         smtpConfig,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// smtpConfig returns the SMTP settings saved in the UI, or the environment's if none are
+func (s *NotificationService) smtpConfig(ctx context.Context) (SMTPConfig, error) {
+	settings, err := s.settingsRepo.GetWithPassword(ctx)
+	if err != nil {
+		return SMTPConfig{}, err
+	}
+	if settings.Host == "" {
+		return s.smtp, nil
+	}
+	return SMTPConfig{
+		Host:     settings.Host,
+		Port:     settings.Port,
+		TLSMode:  settings.TLSMode,
+		Username: settings.Username,
+		Password: settings.Password,
+		From:     settings.From,
+	}, nil
+}
+
+// EmailEnabled reports whether SMTP is configured
+func (s *NotificationService) EmailEnabled(ctx context.Context) bool {
+	config, err := s.smtpConfig(ctx)
+	return err == nil && config.Host != ""
+}
+
+// SendSlack posts the notification to an incoming webhook URL using the {"text": ...}
+// payload understood by Slack and compatible tools
+func (s *NotificationService) SendSlack(ctx context.Context, webhookURL string, n Notification) error {
+	body, err := json.Marshal(map[string]string{"text": n.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendEmail sends the notification to the recipients, as plain text or, if it has
+// HTML, as text and HTML alternatives. Transient SMTP failures (connection errors and
+// 4xx replies) are retried with exponential backoff; every outcome is logged.
+func (s *NotificationService) SendEmail(ctx context.Context, to []string, n Notification) error {
+	config, err := s.smtpConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config.Host == "" {
+		return ErrEmailNotConfigured
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	msg, err := buildEmail(config.From, to, n)
+	if err != nil {
+		return err
+	}
+
+	wait := smtpRetryBaseWait
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err = deliverEmail(ctx, config, to, msg)
+		if err == nil {
+			log.Printf("📧 Email %q delivered to %d recipient(s) (attempt %d)", n.Subject, len(to), attempt)
+			return nil
+		}
+		if !isTransientSMTPError(err) || attempt == smtpMaxAttempts {
+			log.Printf("❌ Email %q to %d recipient(s) failed after %d attempt(s): %v", n.Subject, len(to), attempt, err)
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+
+		log.Printf("⚠️  Email %q attempt %d failed, retrying in %s: %v", n.Subject, attempt, wait, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// buildEmail renders the message headers and body; HTML notifications become
+// multipart/alternative with the text part first
+func buildEmail(from string, to []string, n Notification) ([]byte, error) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", n.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	text := strings.ReplaceAll(n.Text, "\n", "\r\n")
+	if n.HTML == "" {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(text)
+		return msg.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", n.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// deliverEmail makes one delivery attempt using the configured TLS mode
+func deliverEmail(ctx context.Context, config SMTPConfig, to []string, msg []byte) error {
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	tlsConfig := &tls.Config{ServerName: config.Host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if config.TLSMode == models.SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if config.TLSMode != models.SMTPTLSImplicit && config.TLSMode != models.SMTPTLSNone {
+		offered, _ := client.Extension("STARTTLS")
+		if !offered && config.TLSMode == models.SMTPTLSStartTLS {
+			return errors.New("server does not offer STARTTLS")
+		}
+		if offered {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if config.Username != "" {
+		// PlainAuth refuses to send credentials without TLS unless the server is localhost
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// isTransientSMTPError reports whether a delivery may succeed when retried: network
+// failures and 4xx replies are transient, 5xx replies and TLS/auth problems are not
+func isTransientSMTPError(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// oidcKeyRefreshInterval limits how often an unknown key ID triggers a JWKS refetch
+const oidcKeyRefreshInterval = time.Minute
+
+// OIDCConfig holds the settings of an OpenID Connect identity provider
+type OIDCConfig struct {
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string `redact:"true"`
+	RedirectURL   string
+	AllowedDomain string // Google Workspace hosted domain (hd claim); empty allows any
+}
+
+// OIDCClaims are the ID token claims used to sign users in
+type OIDCClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	HostedDomain  string `json:"hd"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// OIDCProvider signs users in with an OpenID Connect provider such as Google
+// Workspace. The discovery document and signing keys are fetched on first use.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	oauthConfig *oauth2.Config
+	jwksURI     string
+	keys        map[string]interface{} // Key ID -> *rsa.PublicKey or *ecdsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewOIDCProvider creates a provider; it returns nil if no issuer is configured
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	if config.IssuerURL == "" {
+		return nil
+	}
+	config.IssuerURL = strings.TrimRight(config.IssuerURL, "/")
+	return &OIDCProvider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthCodeURL returns the provider's login URL for the given state and nonce
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	oauthConfig, err := p.oauth(ctx)
+	if err != nil {
+		return "", err
+	}
+	opts := []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("nonce", nonce)}
+	if p.config.AllowedDomain != "" {
+		// Only a hint for Google's account chooser; the hd claim is still verified
+		opts = append(opts, oauth2.SetAuthURLParam("hd", p.config.AllowedDomain))
+	}
+	return oauthConfig.AuthCodeURL(state, opts...), nil
+}
+
+// Exchange trades an authorization code for an ID token and verifies it
+func (p *OIDCProvider) Exchange(ctx context.Context, code, nonce string) (*OIDCClaims, error) {
+	oauthConfig, err := p.oauth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := oauthConfig.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	return p.Verify(ctx, rawIDToken, nonce)
+}
+
+// Verify checks the ID token's signature, issuer, audience, expiry and nonce, and
+// that its email is verified and belongs to the allowed hosted domain
+func (p *OIDCProvider) Verify(ctx context.Context, rawIDToken, nonce string) (*OIDCClaims, error) {
+	if _, err := p.oauth(ctx); err != nil {
+		return nil, err
+	}
+
+	claims := &OIDCClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.config.IssuerURL),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, errors.New("invalid id_token: sub and email are required")
+	}
+	if !claims.EmailVerified {
+		return nil, errors.New("email address is not verified")
+	}
+	if p.config.AllowedDomain != "" && !strings.EqualFold(claims.HostedDomain, p.config.AllowedDomain) {
+		return nil, fmt.Errorf("account does not belong to %s", p.config.AllowedDomain)
+	}
+	return claims, nil
+}
+
+// oauth returns the OAuth2 config, fetching the discovery document on first use
+func (p *OIDCProvider) oauth(ctx context.Context) (*oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauthConfig != nil {
+		return p.oauthConfig, nil
+	}
+
+	var discovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to load OIDC discovery document: %w", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != p.config.IssuerURL {
+		return nil, fmt.Errorf("OIDC discovery document is for issuer %q, expected %q", discovery.Issuer, p.config.IssuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing endpoints")
+	}
+
+	// ID tokens carry the issuer exactly as the discovery document spells it
+	p.config.IssuerURL = discovery.Issuer
+	p.jwksURI = discovery.JWKSURI
+	p.oauthConfig = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}
+	return p.oauthConfig, nil
+}
+
+// key returns the signing key with the given ID, refetching the key set when the
+// ID is unknown (the provider rotated its keys)
+func (p *OIDCProvider) key(ctx context.Context, kid string) (interface{}, error) {
+	if _, err := p.oauth(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.jwksURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to load OIDC signing keys: %w", err)
+	}
+	p.keys = make(map[string]interface{}, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err == nil {
+			p.keys[k.Kid] = key
+		}
+	}
+	p.keysFetched = time.Now()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jsonWebKey is an RSA or EC public key of a JWKS document (RFC 7517)
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/semaphore"
+)
+
+// ErrProvisioningBacklog is returned when a provision waited longer than the queue timeout
+var ErrProvisioningBacklog = errors.New("provisioning backlog: too many concurrent provisioning operations, please retry later")
+
+var (
+	provisionsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "portalight_provisions_in_flight",
+		Help: "Number of AWS provisioning operations currently running",
+	})
+	provisionsQueued = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "portalight_provisions_queued",
+		Help: "Number of AWS provisioning operations waiting for a free slot",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(provisionsInFlight, provisionsQueued)
+}
+
+// ProvisionLimiter caps concurrent outbound AWS provisioning operations across all users.
+// Waiters are served in FIFO order and can observe their position in the queue.
+type ProvisionLimiter struct {
+	sem          *semaphore.Weighted
+	queueTimeout time.Duration
+
+	mu      sync.Mutex
+	waiting []*provisionWaiter
+}
+
+type provisionWaiter struct {
+	moved chan struct{} // Signalled when the waiter's queue position changes
+}
+
+// NewProvisionLimiter creates a limiter allowing maxConcurrent provisions at once;
+// queued provisions fail with ErrProvisioningBacklog after queueTimeout
+func NewProvisionLimiter(maxConcurrent int, queueTimeout time.Duration) *ProvisionLimiter {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &ProvisionLimiter{
+		sem:          semaphore.NewWeighted(int64(maxConcurrent)),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire blocks until a provisioning slot is free. onPosition is called with the
+// number of provisions ahead of this one whenever it changes while waiting.
+// The returned release func must be called once the provision has finished.
+func (l *ProvisionLimiter) Acquire(ctx context.Context, onPosition func(ahead int)) (func(), error) {
+	// Fast path: a slot is free and nobody is waiting
+	if l.sem.TryAcquire(1) {
+		provisionsInFlight.Inc()
+		return l.release, nil
+	}
+
+	waiter := &provisionWaiter{moved: make(chan struct{}, 1)}
+	l.mu.Lock()
+	l.waiting = append(l.waiting, waiter)
+	l.mu.Unlock()
+	provisionsQueued.Inc()
+
+	// Report position changes from a single goroutine so updates stay ordered
+	done := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		last := -1
+		for {
+			if ahead := l.position(waiter); ahead >= 0 && ahead != last && onPosition != nil {
+				onPosition(ahead)
+				last = ahead
+			}
+			select {
+			case <-waiter.moved:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	waitCtx := ctx
+	if l.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+	}
+	err := l.sem.Acquire(waitCtx, 1)
+
+	l.dequeue(waiter)
+	provisionsQueued.Dec()
+	close(done)
+	<-reported
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, ErrProvisioningBacklog
+		}
+		return nil, err
+	}
+
+	provisionsInFlight.Inc()
+	return l.release, nil
+}
+
+func (l *ProvisionLimiter) release() {
+	provisionsInFlight.Dec()
+	l.sem.Release(1)
+}
+
+// position returns how many waiters are ahead of w, or -1 if it is no longer queued
+func (l *ProvisionLimiter) position(w *provisionWaiter) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, queued := range l.waiting {
+		if queued == w {
+			return i
+		}
+	}
+	return -1
+}
+
+// dequeue removes w from the waiting list and notifies everyone behind it
+func (l *ProvisionLimiter) dequeue(w *provisionWaiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, queued := range l.waiting {
+		if queued != w {
+			continue
+		}
+		l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+		for _, behind := range l.waiting[i:] {
+			select {
+			case behind.moved <- struct{}{}:
+			default:
+			}
+		}
+		return
+	}
+}
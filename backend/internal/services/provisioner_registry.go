@@ -0,0 +1,69 @@
+package services
+
+import "sync"
+
+// ProvisionerOption describes one config field of a provisionable resource type
+type ProvisionerOption struct {
+	Name        string      `json:"name"` // JSON key in the resource config
+	Label       string      `json:"label"`
+	Type        string      `json:"type"` // string, number, boolean, select, list, map
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default,omitempty"`
+	Choices     []string    `json:"choices,omitempty"` // For select options
+	Description string      `json:"description,omitempty"`
+}
+
+// ProvisionerType describes a resource type the provisioner can create
+type ProvisionerType struct {
+	Type        string              `json:"type"`
+	DisplayName string              `json:"display_name"`
+	Description string              `json:"description"`
+	Options     []ProvisionerOption `json:"options"`
+}
+
+var (
+	provisionerTypesMu    sync.RWMutex
+	provisionerTypes      = map[string]ProvisionerType{}
+	provisionerTypesOrder []string
+)
+
+// RegisterProvisionerType makes a resource type available for provisioning
+func RegisterProvisionerType(t ProvisionerType) {
+	provisionerTypesMu.Lock()
+	defer provisionerTypesMu.Unlock()
+
+	if _, exists := provisionerTypes[t.Type]; !exists {
+		provisionerTypesOrder = append(provisionerTypesOrder, t.Type)
+	}
+	provisionerTypes[t.Type] = t
+}
+
+// ProvisionerTypes returns all registered resource types in registration order
+func ProvisionerTypes() []ProvisionerType {
+	provisionerTypesMu.RLock()
+	defer provisionerTypesMu.RUnlock()
+
+	types := make([]ProvisionerType, 0, len(provisionerTypesOrder))
+	for _, name := range provisionerTypesOrder {
+		types = append(types, provisionerTypes[name])
+	}
+	return types
+}
+
+// ProvisionableTypes returns the names of all registered resource types in registration
+// order; provisioning requests and permission grants are validated against it
+func ProvisionableTypes() []string {
+	provisionerTypesMu.RLock()
+	defer provisionerTypesMu.RUnlock()
+
+	return append([]string(nil), provisionerTypesOrder...)
+}
+
+// LookupProvisionerType returns a registered resource type by name
+func LookupProvisionerType(name string) (ProvisionerType, bool) {
+	provisionerTypesMu.RLock()
+	defer provisionerTypesMu.RUnlock()
+
+	t, ok := provisionerTypes[name]
+	return t, ok
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// ResourceReconciler keeps provisioned resources and the discovered_resources rows
+// tracking them consistent. Status changes propagate between linked rows as they
+// happen; the reconciler repairs pairs that drifted before the link existed or
+// while one side was changed outside the portal.
+type ResourceReconciler struct {
+	repo *repositories.ResourceReconciliationRepository
+}
+
+// NewResourceReconciler creates a new resource reconciler
+func NewResourceReconciler() *ResourceReconciler {
+	return &ResourceReconciler{
+		repo: repositories.NewResourceReconciliationRepository(),
+	}
+}
+
+// Report lists the divergent pairs without changing anything
+func (r *ResourceReconciler) Report(ctx context.Context) ([]models.ResourceDivergence, error) {
+	return r.repo.FindDivergent(ctx)
+}
+
+// Reconcile links discovered resources to provisioned ones by ARN, then repairs the
+// divergent pairs. With resourceIDs only those provisioned resources are repaired.
+func (r *ResourceReconciler) Reconcile(ctx context.Context, resourceIDs []string) (*models.ResourceReconciliation, error) {
+	result := &models.ResourceReconciliation{
+		Repaired:  []models.ResourceDivergence{},
+		Remaining: []models.ResourceDivergence{},
+	}
+
+	var err error
+	if len(resourceIDs) == 0 {
+		result.Linked, err = r.repo.LinkByARN(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	divergences, err := r.repo.FindDivergent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(resourceIDs))
+	for _, id := range resourceIDs {
+		selected[id] = true
+	}
+
+	for _, d := range divergences {
+		if len(selected) > 0 && !selected[d.ResourceID] {
+			continue
+		}
+		if d.Repair == "" {
+			result.Remaining = append(result.Remaining, d)
+			continue
+		}
+		if err := r.repo.Repair(ctx, d); err != nil {
+			log.Printf("Failed to repair resource %s (%s): %v", d.ResourceID, d.Repair, err)
+			result.Remaining = append(result.Remaining, d)
+			continue
+		}
+		result.Repaired = append(result.Repaired, d)
+	}
+
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/portalight/backend/internal/database"
 	"github.com/portalight/backend/internal/models"
 	"github.com/portalight/backend/internal/repositories"
 )
@@ -23,22 +24,28 @@ type SyncResult struct {
 	Error            string    `json:"error,omitempty"`
 }
 
+// discoveryQueryTimeout bounds each statement of a resource sync, which reads and
+// updates every resource the project tracks
+const discoveryQueryTimeout = 30 * time.Second
+
 // ResourceSyncService handles background synchronization of AWS resources
 type ResourceSyncService struct {
 	discovery    *AWSDiscovery
 	secretRepo   *repositories.SecretRepository
 	resourceRepo *repositories.DiscoveredResourceRepository
+	teamNotifier *TeamNotifier // Tells project teams about resources deleted outside the portal
 	mu           sync.Mutex
 	stopCh       chan struct{}
 	running      bool
 }
 
 // NewResourceSyncService creates a new sync service
-func NewResourceSyncService() *ResourceSyncService {
+func NewResourceSyncService(teamNotifier *TeamNotifier) *ResourceSyncService {
 	return &ResourceSyncService{
 		discovery:    NewAWSDiscovery(),
 		secretRepo:   &repositories.SecretRepository{},
 		resourceRepo: repositories.NewDiscoveredResourceRepository(),
+		teamNotifier: teamNotifier,
 		stopCh:       make(chan struct{}),
 	}
 }
@@ -47,6 +54,7 @@ func NewResourceSyncService() *ResourceSyncService {
 // It only checks if EXISTING associated resources still exist in AWS
 // It does NOT add new resources - those must be explicitly associated via "Discover Resources"
 func (s *ResourceSyncService) SyncProject(ctx context.Context, projectID, secretID, region string) (*SyncResult, error) {
+	ctx = database.WithQueryTimeout(ctx, discoveryQueryTimeout)
 	result := &SyncResult{
 		ProjectID: projectID,
 		SecretID:  secretID,
@@ -80,27 +88,45 @@ func (s *ResourceSyncService) SyncProject(ctx context.Context, projectID, secret
 		return result, err
 	}
 
-	// Create a map of ARNs that exist in AWS
-	awsARNs := make(map[string]bool)
+	// Index what exists in AWS by ARN
+	awsResources := make(map[string]DiscoveredResource, len(discovered))
 	for _, d := range discovered {
-		awsARNs[d.ARN] = true
+		awsResources[d.ARN] = d
 	}
 
 	result.ResourcesFound = len(discovered)
 
 	// Check each existing associated resource against AWS
 	for _, res := range existingResources {
-		if awsARNs[res.ARN] {
+		if d, ok := awsResources[res.ARN]; ok {
 			// Resource still exists in AWS
 			if res.Status != models.ResourceStatusActive {
-				s.resourceRepo.UpdateStatus(ctx, res.ID, models.ResourceStatusActive)
+				if err := s.resourceRepo.TransitionStatus(ctx, res.ID, res.Status, models.ResourceStatusActive); err != nil {
+					log.Printf("Skipping status update for resource %s: %v", res.ID, err)
+				}
+			}
+			// A resource since imported into Terraform is no longer the portal's to change
+			if d.ManagedBy == models.ManagedByTerraform && res.ManagedBy != models.ManagedByTerraform {
+				if err := s.resourceRepo.SetManagedBy(ctx, res.ID, models.ManagedByTerraform); err != nil {
+					log.Printf("Failed to mark resource %s as managed by Terraform: %v", res.ID, err)
+				}
 			}
 			result.ResourcesActive++
 		} else {
 			// Resource no longer exists in AWS
 			if res.Status != models.ResourceStatusDeleted {
-				s.resourceRepo.UpdateStatus(ctx, res.ID, models.ResourceStatusDeleted)
+				if err := s.resourceRepo.TransitionStatus(ctx, res.ID, res.Status, models.ResourceStatusDeleted); err != nil {
+					log.Printf("Skipping status update for resource %s: %v", res.ID, err)
+					continue
+				}
 				result.ResourcesDeleted++
+				s.teamNotifier.Notify(models.TeamEvent{
+					Event:        models.NotificationResourceDeleted,
+					ProjectID:    projectID,
+					ResourceType: res.ResourceType,
+					ResourceName: res.Name,
+					ARN:          res.ARN,
+				})
 			}
 		}
 	}
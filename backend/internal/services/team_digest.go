@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// digestCheckInterval is how often the job looks for teams whose digest is due; digests
+// go out on the first check after the send hour in each team's timezone
+const digestCheckInterval = time.Hour
+
+// TeamDigestJob sends each opted-in team one summary of the previous day's catalog
+// and resource changes in the projects it owns
+type TeamDigestJob struct {
+	digestRepo    *repositories.DigestRepository
+	notifications *NotificationService
+	notifier      *UserNotifier // Skips recipients who opted out of digests
+	sendHour      int           // Local hour (0-23) after which the previous day's digest is sent
+	mu            sync.Mutex
+	stopCh        chan struct{}
+	running       bool
+}
+
+// NewTeamDigestJob creates the digest job
+func NewTeamDigestJob(notifications *NotificationService, notifier *UserNotifier, sendHour int) *TeamDigestJob {
+	return &TeamDigestJob{
+		digestRepo:    repositories.NewDigestRepository(),
+		notifications: notifications,
+		notifier:      notifier,
+		sendHour:      sendHour,
+	}
+}
+
+// RunOnce sends every digest that is due at now. A digest is claimed per team and local
+// day before sending, so repeated runs (or several replicas) send it only once.
+func (j *TeamDigestJob) RunOnce(ctx context.Context, now time.Time) {
+	teams, err := j.digestRepo.ListEnabled(ctx)
+	if err != nil {
+		log.Printf("Failed to load team digest settings: %v", err)
+		return
+	}
+
+	for _, settings := range teams {
+		if err := j.sendDue(ctx, settings, now); err != nil {
+			log.Printf("Failed to send digest for team %s: %v", settings.TeamName, err)
+		}
+	}
+}
+
+// sendDue sends the digest of the previous local day if it is due and not yet sent
+func (j *TeamDigestJob) sendDue(ctx context.Context, settings models.TeamDigestSettings, now time.Time) error {
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", settings.Timezone, err)
+	}
+
+	local := now.In(loc)
+	if local.Hour() < j.sendHour {
+		return nil
+	}
+	to := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	from := to.AddDate(0, 0, -1)
+
+	claimed, err := j.digestRepo.ClaimDelivery(ctx, settings.TeamID, from)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := j.send(ctx, settings, from, to); err != nil {
+		if releaseErr := j.digestRepo.ReleaseDelivery(ctx, settings.TeamID, from); releaseErr != nil {
+			log.Printf("Failed to release digest claim for team %s: %v", settings.TeamName, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (j *TeamDigestJob) send(ctx context.Context, settings models.TeamDigestSettings, from, to time.Time) error {
+	digest, err := j.digestRepo.CollectDigest(ctx, settings.TeamID, from.UTC(), to.UTC())
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		log.Printf("No changes for team %s on %s, skipping digest", settings.TeamName, from.Format("2006-01-02"))
+		return nil
+	}
+	digest.TeamName = settings.TeamName
+	digest.Date = from
+	digest.Timezone = settings.Timezone
+
+	subject := fmt.Sprintf("Portalight digest for %s: %s", settings.TeamName, from.Format("2006-01-02"))
+	notification, err := RenderEmail(models.NotificationTeamDigest, subject, digest)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if settings.SlackWebhookURL != "" {
+		if err := j.notifications.SendSlack(ctx, settings.SlackWebhookURL, notification); err != nil {
+			errs = append(errs, fmt.Errorf("slack: %w", err))
+		}
+	}
+	if len(settings.EmailRecipients) > 0 {
+		if err := j.notifier.SendEmail(ctx, models.NotificationTeamDigest, settings.EmailRecipients, notification); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	log.Printf("Sent digest for team %s on %s", settings.TeamName, from.Format("2006-01-02"))
+	return nil
+}
+
+// Start checks for due digests immediately and then every hour
+func (j *TeamDigestJob) Start() {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return
+	}
+	j.running = true
+	j.stopCh = make(chan struct{})
+	j.mu.Unlock()
+
+	go func() {
+		j.RunOnce(context.Background(), time.Now())
+
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				j.RunOnce(context.Background(), now)
+			case <-j.stopCh:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Team digest job started (send hour %02d:00 team-local)", j.sendHour)
+}
+
+// Stop stops the digest job
+func (j *TeamDigestJob) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		close(j.stopCh)
+		j.running = false
+		log.Println("Team digest job stopped")
+	}
+}
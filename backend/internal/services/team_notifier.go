@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/portalight/backend/internal/models"
+	"github.com/portalight/backend/internal/repositories"
+)
+
+const (
+	teamNotifyTimeout       = 5 * time.Second // Per post
+	teamNotifyMaxAttempts   = 3
+	teamNotifyRetryBaseWait = 2 * time.Second // Doubled after every failed attempt
+)
+
+// TeamNotifier posts project events to the Slack and webhook channels of the teams
+// that own or can access the project
+type TeamNotifier struct {
+	client      *http.Client
+	channelRepo *repositories.NotificationChannelRepository
+	projectRepo *repositories.ProjectRepository
+}
+
+// NewTeamNotifier creates a new TeamNotifier
+func NewTeamNotifier(channelRepo *repositories.NotificationChannelRepository, projectRepo *repositories.ProjectRepository) *TeamNotifier {
+	return &TeamNotifier{
+		client:      &http.Client{Timeout: teamNotifyTimeout},
+		channelRepo: channelRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// Notify delivers the event in the background to every subscribed channel. Failed
+// posts are retried with exponential backoff and then logged; the caller never waits.
+func (n *TeamNotifier) Notify(event models.TeamEvent) {
+	if event.ProjectID == "" {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	go func() {
+		ctx := context.Background()
+		channels, err := n.channelRepo.ListForProjectEvent(ctx, event.ProjectID, event.Event)
+		if err != nil {
+			log.Printf("Failed to load notification channels for %s: %v", event.Event, err)
+			return
+		}
+		if len(channels) == 0 {
+			return
+		}
+		if event.ProjectName == "" {
+			if project, err := n.projectRepo.FindByID(ctx, event.ProjectID); err == nil {
+				event.ProjectName = project.Name
+			}
+		}
+		for _, channel := range channels {
+			go n.deliver(ctx, channel, event)
+		}
+	}()
+}
+
+// deliver posts one event to one channel, retrying failed attempts
+func (n *TeamNotifier) deliver(ctx context.Context, channel models.NotificationChannel, event models.TeamEvent) {
+	var payload interface{} = event
+	if channel.Type == models.NotificationChannelSlack {
+		payload = map[string]string{"text": slackEventText(event)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to encode %s notification: %v", event.Event, err)
+		return
+	}
+
+	wait := teamNotifyRetryBaseWait
+	for attempt := 1; ; attempt++ {
+		err = n.post(ctx, channel.URL, body)
+		if err == nil {
+			return
+		}
+		if attempt == teamNotifyMaxAttempts {
+			log.Printf("❌ %s notification to %s channel %s failed after %d attempt(s): %v", event.Event, channel.Type, channel.ID, attempt, err)
+			return
+		}
+		log.Printf("⚠️  %s notification to %s channel %s attempt %d failed, retrying in %s: %v", event.Event, channel.Type, channel.ID, attempt, wait, err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+func (n *TeamNotifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// slackEventText renders an event as a short Slack message
+func slackEventText(event models.TeamEvent) string {
+	project := event.ProjectName
+	if project == "" {
+		project = event.ProjectID
+	}
+
+	var b strings.Builder
+	switch event.Event {
+	case models.NotificationProvisioningSucceeded:
+		fmt.Fprintf(&b, ":white_check_mark: %s *%s* is ready in project *%s*", event.ResourceType, event.ResourceName, project)
+		if event.ARN != "" {
+			fmt.Fprintf(&b, "\nARN: `%s`", event.ARN)
+		}
+	case models.NotificationProvisioningFailed:
+		fmt.Fprintf(&b, ":x: Provisioning %s *%s* in project *%s* failed", event.ResourceType, event.ResourceName, project)
+	case models.NotificationSyncFailed:
+		fmt.Fprintf(&b, ":x: Catalog sync of `%s` for project *%s* failed", event.CatalogFilePath, project)
+	case models.NotificationResourceDeleted:
+		fmt.Fprintf(&b, ":warning: %s *%s* in project *%s* no longer exists in AWS", event.ResourceType, event.ResourceName, project)
+		if event.ARN != "" {
+			fmt.Fprintf(&b, "\nARN: `%s`", event.ARN)
+		}
+	default:
+		fmt.Fprintf(&b, "%s in project *%s*", event.Event, project)
+	}
+	if event.Actor != "" {
+		fmt.Fprintf(&b, "\nStarted by %s", event.Actor)
+	}
+	if event.Error != "" {
+		fmt.Fprintf(&b, "\nError: %s", event.Error)
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/portalight/backend/internal/repositories"
+)
+
+// UserNotifier emails users about notification events they have not opted out of
+type UserNotifier struct {
+	notifications *NotificationService
+	userRepo      *repositories.UserRepository
+	prefsRepo     *repositories.NotificationPreferencesRepository
+}
+
+// NewUserNotifier creates a new UserNotifier
+func NewUserNotifier(notifications *NotificationService, userRepo *repositories.UserRepository, prefsRepo *repositories.NotificationPreferencesRepository) *UserNotifier {
+	return &UserNotifier{
+		notifications: notifications,
+		userRepo:      userRepo,
+		prefsRepo:     prefsRepo,
+	}
+}
+
+// Notify renders the event's email and sends it in the background to the recipients
+// that want it. Failures are logged; nothing is sent when SMTP is not configured.
+func (n *UserNotifier) Notify(event string, to []string, subject string, data interface{}) {
+	notification, err := RenderEmail(event, subject, data)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+
+	go func() {
+		err := n.SendEmail(context.Background(), event, to, notification)
+		if err != nil && !errors.Is(err, ErrEmailNotConfigured) {
+			log.Printf("Failed to send %s notification: %v", event, err)
+		}
+	}()
+}
+
+// SendEmail sends one email to the recipients that have not opted out of the event.
+// Addresses that do not belong to a user always receive it.
+func (n *UserNotifier) SendEmail(ctx context.Context, event string, to []string, notification Notification) error {
+	var recipients []string
+	for _, email := range to {
+		if n.wants(ctx, event, email) {
+			recipients = append(recipients, email)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	return n.notifications.SendEmail(ctx, recipients, notification)
+}
+
+// wants reports whether the owner of an email address receives an event
+func (n *UserNotifier) wants(ctx context.Context, event, email string) bool {
+	user, err := n.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return true // Not a user, e.g. a team mailing list
+	}
+	prefs, err := n.prefsRepo.Get(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to load notification preferences of %s: %v", email, err)
+		return true
+	}
+	return prefs.Enabled(event)
+}
@@ -0,0 +1,65 @@
+// Package telemetry sets up OpenTelemetry tracing for the backend.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies this service in traces
+const ServiceName = "portalight-backend"
+
+// Version is set at build time:
+//
+//	go build -ldflags "-X github.com/portalight/backend/internal/telemetry.Version=1.2.3" ./cmd/server
+var Version = "dev"
+
+// Tracer returns the tracer used for manual spans
+func Tracer() trace.Tracer {
+	return otel.Tracer("portalight")
+}
+
+// Init installs a global tracer provider exporting to the OTLP/HTTP endpoint.
+// With an empty endpoint tracing stays a no-op. The returned func flushes pending spans.
+func Init(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		log.Println("Tracing disabled (OTEL_EXPORTER_OTLP_ENDPOINT not set)")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	log.Printf("Tracing enabled, exporting to %s", endpoint)
+	return provider.Shutdown, nil
+}
@@ -237,17 +237,33 @@ function CredentialsTab({ credentials, onRefresh }: { credentials: any[], onRefr
         }
     };
 
-    const handleDelete = async (id: string) => {
+    const handleDelete = async (id: string, force = false) => {
         try {
             const token = typeof window !== 'undefined' ? localStorage.getItem('token') : null;
             const headers: Record<string, string> = {};
             if (token) headers['Authorization'] = `Bearer ${token}`;
 
-            const response = await fetch(`${process.env.NEXT_PUBLIC_API_BASE_URL || 'http://localhost:8080'}/api/v1/credentials/${id}`, {
+            const response = await fetch(`${process.env.NEXT_PUBLIC_API_BASE_URL || 'http://localhost:8080'}/api/v1/credentials/${id}${force ? '?force=true' : ''}`, {
                 method: 'DELETE',
                 headers,
             });
 
+            // Still referenced: show what would lose its credential and ask again
+            if (response.status === 409 && !force) {
+                const { usage } = await response.json();
+                const summary = [
+                    `${usage.projects.count} project(s)`,
+                    `${usage.resources.count} provisioned resource(s)`,
+                    `${usage.discovered_resources.count} discovered resource(s)`,
+                    `${usage.pending_approvals.count} request(s) awaiting approval`,
+                    `${usage.permissions.count} provisioning permission(s)`,
+                ].join(', ');
+                if (window.confirm(`This credential is still used by ${summary}. They will no longer be refreshable, requests awaiting approval will be rejected and permissions limited to this credential will be removed. Delete anyway?`)) {
+                    await handleDelete(id, true);
+                }
+                return;
+            }
+
             if (!response.ok) {
                 throw new Error('Failed to delete credential');
             }
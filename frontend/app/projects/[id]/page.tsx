@@ -7,7 +7,7 @@ import ProjectEditModal from '@/components/ProjectEditModal';
 import ProjectAccessModal from '@/components/ProjectAccessModal';
 import ResourceDiscoveryModal from '@/components/ResourceDiscoveryModal';
 import ConfirmationModal from '@/components/ConfirmationModal';
-import { fetchProjectById, fetchCurrentUser, updateProject, fetchTeams, fetchUsers, updateProjectAccess, syncProject, fetchProjectResources, fetchDiscoveredResources, syncProjectResources, fetchAWSCredentials, removeDiscoveredResource, deleteProject } from '@/lib/api';
+import { fetchProjectById, fetchCurrentUser, updateProject, fetchTeams, fetchUsers, updateProjectAccess, syncProject, fetchProjectResources, fetchDiscoveredResources, syncProjectResources, fetchAWSCredentials, removeDiscoveredResource, deleteProject, fetchProjectDocs } from '@/lib/api';
 import { ProjectWithServices, User, Team, Project, Resource, Secret, DiscoveredResource, DiscoveredResourceDB } from '@/lib/types';
 import styles from './page.module.css';
 import CustomDropdown from '@/components/ui/CustomDropdown';
@@ -33,6 +33,7 @@ export default function ProjectDetailPage() {
     const [resources, setResources] = useState<Resource[]>([]);
     const [discoveredResources, setDiscoveredResources] = useState<DiscoveredResourceDB[]>([]);
     const [credentials, setCredentials] = useState<Secret[]>([]);
+    const [docs, setDocs] = useState<string | null>(null);
     const [syncing, setSyncing] = useState(false);
     const [resourceSyncing, setResourceSyncing] = useState(false);
     const [syncMessage, setSyncMessage] = useState<{ type: 'success' | 'error', text: string } | null>(null);
@@ -91,13 +92,15 @@ export default function ProjectDetailPage() {
 
             // Use the actual project ID for resources (these endpoints need the UUID)
             const actualProjectId = projectData.id;
-            const [resourcesData, discoveredData] = await Promise.all([
+            const [resourcesData, discoveredData, docsData] = await Promise.all([
                 fetchProjectResources(actualProjectId),
                 fetchDiscoveredResources(actualProjectId),
+                fetchProjectDocs(actualProjectId).catch(() => null),
             ]);
 
             setResources(resourcesData);
             setDiscoveredResources(discoveredData || []);
+            setDocs(docsData);
         } catch (error) {
             console.error('Failed to load project:', error);
         } finally {
@@ -483,7 +486,8 @@ export default function ProjectDetailPage() {
                                         { value: 'sqs', label: '📨 SQS Queues' },
                                         { value: 'sns', label: '🔔 SNS Topics' },
                                         { value: 'rds', label: '🗄️ RDS Databases' },
-                                        { value: 'lambda', label: '⚡ Lambda Functions' }
+                                        { value: 'lambda', label: '⚡ Lambda Functions' },
+                                        { value: 'eks', label: '☸️ EKS Clusters' }
                                     ]}
                                     value={resourceFilter}
                                     onChange={setResourceFilter}
@@ -530,6 +534,7 @@ export default function ProjectDetailPage() {
                                                     {resource.resource_type === 'sns' && '🔔 SNS Topic'}
                                                     {resource.resource_type === 'rds' && '🗄️ RDS Database'}
                                                     {resource.resource_type === 'lambda' && '⚡ Lambda Function'}
+                                                    {resource.resource_type === 'eks' && '☸️ EKS Cluster'}
                                                     {' • '}{resource.region}
                                                 </p>
                                                 <div className={styles.serviceMeta}>
@@ -600,6 +605,16 @@ export default function ProjectDetailPage() {
                         )}
                     </div>
 
+                    {/* Project docs from the catalog repository (metadata.docs) */}
+                    {docs && (
+                        <div className={styles.servicesSection}>
+                            <h2 style={{ fontSize: '1.25rem', fontWeight: 600, marginBottom: '1rem' }}>📖 Docs</h2>
+                            <pre style={{ whiteSpace: 'pre-wrap', fontFamily: 'inherit', fontSize: '0.938rem', lineHeight: 1.6, color: '#374151', margin: 0 }}>
+                                {docs}
+                            </pre>
+                        </div>
+                    )}
+
                 </div>
             </main>
 
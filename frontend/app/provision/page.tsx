@@ -71,6 +71,8 @@ export default function ProvisionPage() {
     const [selectedType, setSelectedType] = useState<string>('');
     const [selectedCredential, setSelectedCredential] = useState<string>('');
     const [resourceName, setResourceName] = useState('');
+    const [reason, setReason] = useState('');
+    const [ticketUrl, setTicketUrl] = useState('');
 
     // S3 Config
     const [s3Region, setS3Region] = useState('ap-south-1');
@@ -84,6 +86,8 @@ export default function ProvisionPage() {
     const [sqsVisibilityTimeout, setSqsVisibilityTimeout] = useState(30);
     const [sqsMessageRetentionDays, setSqsMessageRetentionDays] = useState(4);
     const [sqsDelaySeconds, setSqsDelaySeconds] = useState(0);
+    const [sqsDlqEnabled, setSqsDlqEnabled] = useState(false);
+    const [sqsMaxReceiveCount, setSqsMaxReceiveCount] = useState(3);
 
     // SNS Config
     const [snsRegion, setSnsRegion] = useState('ap-south-1');
@@ -97,7 +101,7 @@ export default function ProvisionPage() {
         try {
             const [projectsData, credsData, userData] = await Promise.all([
                 fetchProjects(),
-                fetchAWSCredentials(),
+                fetchAWSCredentials('write'), // Read-only credentials cannot provision
                 fetchCurrentUser(),
             ]);
             setProjects(projectsData);
@@ -152,6 +156,8 @@ export default function ProvisionPage() {
                     visibility_timeout: sqsVisibilityTimeout,
                     message_retention_days: sqsMessageRetentionDays,
                     delay_seconds: sqsDelaySeconds,
+                    dead_letter_queue_enabled: sqsDlqEnabled,
+                    max_receive_count: sqsMaxReceiveCount,
                 };
             case 'sns':
                 return {
@@ -164,7 +170,7 @@ export default function ProvisionPage() {
     };
 
     const handleSubmit = async () => {
-        if (!resourceName || !selectedProject || !selectedType || !selectedCredential) {
+        if (!resourceName || !reason.trim() || !selectedProject || !selectedType || !selectedCredential) {
             setError('Please fill in all required fields');
             return;
         }
@@ -179,6 +185,8 @@ export default function ProvisionPage() {
                 name: resourceName,
                 type: selectedType,
                 config: buildConfig(),
+                reason: reason.trim(),
+                ticket_url: ticketUrl.trim() || undefined,
             });
 
             router.push(`/projects/${selectedProject}`);
@@ -346,6 +354,28 @@ export default function ProvisionPage() {
                 />
                 <p className={styles.hint}>Delay before messages become visible (0-900 seconds)</p>
             </div>
+            <div className={styles.formRow}>
+                <div className={styles.formGroup}>
+                    <label className={styles.checkboxLabel}>
+                        <input type="checkbox" checked={sqsDlqEnabled} onChange={(e) => setSqsDlqEnabled(e.target.checked)} />
+                        <span>Dead-Letter Queue</span>
+                    </label>
+                    <p className={styles.hint}>Creates {resourceName || 'name'}-dlq for messages that keep failing</p>
+                </div>
+                {sqsDlqEnabled && (
+                    <div className={styles.formGroup}>
+                        <label className={styles.label}>Max Receive Count</label>
+                        <input
+                            type="number"
+                            className={styles.input}
+                            value={sqsMaxReceiveCount}
+                            onChange={(e) => setSqsMaxReceiveCount(parseInt(e.target.value) || 1)}
+                            min={1}
+                            max={1000}
+                        />
+                    </div>
+                )}
+            </div>
         </>
     );
 
@@ -391,6 +421,27 @@ export default function ProvisionPage() {
             {selectedType === 's3' && renderS3Config()}
             {selectedType === 'sqs' && renderSQSConfig()}
             {selectedType === 'sns' && renderSNSConfig()}
+            <div className={styles.formGroup}>
+                <label className={styles.label}>Reason</label>
+                <textarea
+                    className={styles.input}
+                    value={reason}
+                    onChange={(e) => setReason(e.target.value)}
+                    placeholder="Why is this resource needed?"
+                    rows={3}
+                />
+                <p className={styles.hint}>Required. Shown to the lead approving the request and kept in the audit log.</p>
+            </div>
+            <div className={styles.formGroup}>
+                <label className={styles.label}>Ticket URL</label>
+                <input
+                    type="url"
+                    className={styles.input}
+                    value={ticketUrl}
+                    onChange={(e) => setTicketUrl(e.target.value)}
+                    placeholder="https://jira.example.com/browse/OPS-123"
+                />
+            </div>
         </div>
     );
 
@@ -460,6 +511,16 @@ export default function ProvisionPage() {
                         <span className={styles.reviewLabel}>Region:</span>
                         <span className={styles.reviewValue}>{(config as any).region}</span>
                     </div>
+                    <div className={styles.reviewItem}>
+                        <span className={styles.reviewLabel}>Reason:</span>
+                        <span className={styles.reviewValue}>{reason}</span>
+                    </div>
+                    {ticketUrl && (
+                        <div className={styles.reviewItem}>
+                            <span className={styles.reviewLabel}>Ticket:</span>
+                            <span className={styles.reviewValue}>{ticketUrl}</span>
+                        </div>
+                    )}
                 </div>
                 <div className={styles.configSummary}>
                     <h4>Configuration Details</h4>
@@ -473,7 +534,7 @@ export default function ProvisionPage() {
         switch (step) {
             case 1: return !!selectedProject;
             case 2: return !!selectedType;
-            case 3: return !!resourceName;
+            case 3: return !!resourceName && !!reason.trim();
             case 4: return !!selectedCredential;
             default: return true;
         }
@@ -3,7 +3,7 @@
 import { useParams, useRouter } from 'next/navigation';
 import { useState, useEffect } from 'react';
 import Header from '@/components/layout/Header';
-import { fetchResourceById, fetchCurrentUser, fetchResourceMetrics, fetchProjectById, fetchAWSCredentials } from '@/lib/api';
+import { fetchResourceById, fetchCurrentUser, fetchResourceMetrics, fetchProjectById, fetchAWSCredentials, presentPoints, MetricDataPoint } from '@/lib/api';
 import { DiscoveredResourceDB, User, Project, AWSCredential } from '@/lib/types';
 import styles from './page.module.css';
 
@@ -23,7 +23,7 @@ interface Metric {
     datapoints: Array<{ timestamp: string; value: number }>;
 }
 
-type MetricsData = Record<string, Array<{ timestamp: string; value: number }>>;
+type MetricsData = Record<string, MetricDataPoint[]>;
 
 export default function ResourceDetailPage() {
     const params = useParams();
@@ -210,7 +210,7 @@ export default function ResourceDetailPage() {
                         <div className={styles.metricsHeader}>
                             <h2>Metrics</h2>
                             <div className={styles.periodSelector}>
-                                {['1h', '6h', '24h', '7d'].map((period) => (
+                                {['1h', '6h', '24h', '7d', '30d'].map((period) => (
                                     <button
                                         key={period}
                                         className={`${styles.periodButton} ${selectedPeriod === period ? styles.periodButtonActive : ''}`}
@@ -230,12 +230,13 @@ export default function ResourceDetailPage() {
                                     <div key={index} className={styles.metricCard}>
                                         <h4>{metricName}</h4>
                                         <div className={styles.metricValue}>
-                                            {datapoints.length > 0
-                                                ? `${datapoints[datapoints.length - 1].value.toFixed(2)}`
-                                                : 'No data'}
+                                            {(() => {
+                                                const present = presentPoints(datapoints);
+                                                return present.length > 0 ? present[present.length - 1].value.toFixed(2) : 'No data';
+                                            })()}
                                         </div>
                                         <div className={styles.metricDatapoints}>
-                                            {datapoints.length} data points
+                                            {presentPoints(datapoints).length} data points
                                         </div>
                                     </div>
                                 ))}
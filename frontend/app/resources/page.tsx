@@ -3,7 +3,7 @@
 import { useState, useEffect, Suspense } from 'react';
 import { useRouter, useSearchParams } from 'next/navigation';
 import Header from '@/components/layout/Header';
-import { fetchAWSCredentials, fetchResourceMetrics, ResourceMetrics, MetricDataPoint } from '@/lib/api';
+import { fetchAWSCredentials, fetchResourceMetrics, ResourceMetrics, MetricDataPoint, presentPoints } from '@/lib/api';
 import { Secret } from '@/lib/types';
 import styles from './page.module.css';
 
@@ -13,6 +13,11 @@ const RESOURCE_TYPE_INFO: Record<string, { icon: string; label: string; color: s
     sns: { icon: '🔔', label: 'SNS Topic', color: '#DD344C' },
     rds: { icon: '🗄️', label: 'RDS Database', color: '#3B48CC' },
     lambda: { icon: '⚡', label: 'Lambda Function', color: '#FA7343' },
+    eks: { icon: '☸️', label: 'EKS Cluster', color: '#326CE5' },
+    dynamodb: { icon: '🧮', label: 'DynamoDB Table', color: '#4053D6' },
+    cloudformation: { icon: '📚', label: 'CloudFormation Stack', color: '#E7157B' },
+    kinesis: { icon: '🌊', label: 'Kinesis Stream', color: '#8C4FFF' },
+    eventbridge: { icon: '🗓️', label: 'EventBridge Rule', color: '#E7157B' },
 };
 
 const METRIC_LABELS: Record<string, string> = {
@@ -37,6 +42,26 @@ const METRIC_LABELS: Record<string, string> = {
     NumberOfMessagesDeleted: 'Messages Deleted',
     ApproximateNumberOfMessagesVisible: 'Visible Messages',
     ApproximateAgeOfOldestMessage: 'Oldest Message Age (s)',
+    // EKS (Container Insights)
+    node_cpu_utilization: 'Node CPU (%)',
+    node_memory_utilization: 'Node Memory (%)',
+    pod_cpu_utilization: 'Pod CPU (%)',
+    pod_memory_utilization: 'Pod Memory (%)',
+    // DynamoDB
+    ConsumedReadCapacityUnits: 'Consumed Read Capacity',
+    ConsumedWriteCapacityUnits: 'Consumed Write Capacity',
+    SuccessfulRequestLatency: 'Request Latency (ms)',
+    ReturnedItemCount: 'Returned Items',
+    SystemErrors: 'System Errors',
+    UserErrors: 'User Errors',
+    ThrottledRequests: 'Throttled Requests',
+    // Kinesis
+    IncomingRecords: 'Incoming Records',
+    'GetRecords.IteratorAgeMilliseconds': 'Iterator Age (ms)',
+    WriteProvisionedThroughputExceeded: 'Write Throttles',
+    // EventBridge
+    TriggeredRules: 'Triggered',
+    FailedInvocations: 'Failed Invocations',
 };
 
 function ResourceDetailsContent() {
@@ -169,8 +194,9 @@ function ResourceDetailsContent() {
         return value.toFixed(value < 10 ? 2 : 0);
     };
 
-    const getMetricStats = (dataPoints: MetricDataPoint[]): { min: number; max: number; avg: number; latest: number } => {
-        if (!dataPoints || dataPoints.length === 0) {
+    const getMetricStats = (allPoints: MetricDataPoint[]): { min: number; max: number; avg: number; latest: number } => {
+        const dataPoints = presentPoints(allPoints || []);
+        if (dataPoints.length === 0) {
             return { min: 0, max: 0, avg: 0, latest: 0 };
         }
         const values = dataPoints.map(dp => dp.value);
@@ -400,16 +426,16 @@ function ResourceDetailsContent() {
                                                 <div style={{ padding: '1.25rem', background: 'linear-gradient(135deg, #ff99002e, #ff990010)', borderRadius: '0.75rem', border: '1px solid #ff990040' }}>
                                                     <label style={{ fontSize: '0.75rem', color: '#92400e', textTransform: 'uppercase', fontWeight: 500 }}>Total Size</label>
                                                     <p style={{ fontSize: '1.5rem', color: '#111827', margin: '0.5rem 0 0 0', fontWeight: 600 }}>
-                                                        {metrics?.metrics?.BucketSizeBytes && metrics.metrics.BucketSizeBytes.length > 0
-                                                            ? `${(metrics.metrics.BucketSizeBytes[metrics.metrics.BucketSizeBytes.length - 1].value / 1e9).toFixed(2)} GB`
+                                                        {metrics?.metrics?.BucketSizeBytes && presentPoints(metrics.metrics.BucketSizeBytes).length > 0
+                                                            ? `${(getMetricStats(metrics.metrics.BucketSizeBytes).latest / 1e9).toFixed(2)} GB`
                                                             : 'N/A'}
                                                     </p>
                                                 </div>
                                                 <div style={{ padding: '1.25rem', background: 'linear-gradient(135deg, #ff99002e, #ff990010)', borderRadius: '0.75rem', border: '1px solid #ff990040' }}>
                                                     <label style={{ fontSize: '0.75rem', color: '#92400e', textTransform: 'uppercase', fontWeight: 500 }}>Objects</label>
                                                     <p style={{ fontSize: '1.5rem', color: '#111827', margin: '0.5rem 0 0 0', fontWeight: 600 }}>
-                                                        {metrics?.metrics?.NumberOfObjects && metrics.metrics.NumberOfObjects.length > 0
-                                                            ? metrics.metrics.NumberOfObjects[metrics.metrics.NumberOfObjects.length - 1].value.toLocaleString()
+                                                        {metrics?.metrics?.NumberOfObjects && presentPoints(metrics.metrics.NumberOfObjects).length > 0
+                                                            ? getMetricStats(metrics.metrics.NumberOfObjects).latest.toLocaleString()
                                                             : 'N/A'}
                                                     </p>
                                                 </div>
@@ -458,8 +484,8 @@ function ResourceDetailsContent() {
                                                 <label style={{ fontSize: '0.75rem', color: '#9d174d', textTransform: 'uppercase', fontWeight: 500 }}>Visible Messages</label>
                                                 <p style={{ fontSize: '1.5rem', color: '#111827', margin: '0.5rem 0 0 0', fontWeight: 600 }}>
                                                     {(() => {
-                                                        const arr = metrics?.metrics?.ApproximateNumberOfMessagesVisible;
-                                                        if (!arr || arr.length === 0) return '0';
+                                                        const arr = presentPoints(metrics?.metrics?.ApproximateNumberOfMessagesVisible || []);
+                                                        if (arr.length === 0) return '0';
                                                         // For visible messages, show the latest value (last in array)
                                                         return arr[arr.length - 1]?.value?.toLocaleString() || '0';
                                                     })()}
@@ -599,6 +625,7 @@ function ResourceDetailsContent() {
                                                 { value: '6h', label: 'Last 6 Hours' },
                                                 { value: '24h', label: 'Last 24 Hours' },
                                                 { value: '7d', label: 'Last 7 Days' },
+                                                { value: '30d', label: 'Last 30 Days' },
                                             ].map((option) => (
                                                 <button
                                                     key={option.value}
@@ -748,12 +775,13 @@ function ResourceDetailsContent() {
                                                         const idx = Math.min(Math.max(0, Math.round(percentage * (dataPoints.length - 1))), dataPoints.length - 1);
                                                         const dp = dataPoints[idx];
                                                         if (dp) {
+                                                            const label = dp.missing ? 'No data' : formatValue(dp.value, metricName);
                                                             const tooltip = e.currentTarget.querySelector('.chart-tooltip') as HTMLElement;
                                                             const crosshair = e.currentTarget.querySelector('.chart-crosshair') as HTMLElement;
                                                             if (tooltip) {
                                                                 tooltip.style.display = 'block';
                                                                 tooltip.style.left = `${Math.min(Math.max(10, x - 40), rect.width - 90)}px`;
-                                                                tooltip.innerHTML = `<div style="font-weight:600;font-size:0.813rem;">${formatValue(dp.value, metricName)}</div><div style="font-size:0.688rem;color:#6b7280;">${new Date(dp.timestamp).toLocaleTimeString()}</div>`;
+                                                                tooltip.innerHTML = `<div style="font-weight:600;font-size:0.813rem;">${label}</div><div style="font-size:0.688rem;color:#6b7280;">${new Date(dp.timestamp).toLocaleTimeString()}</div>`;
                                                             }
                                                             if (crosshair) {
                                                                 crosshair.style.display = 'block';
@@ -808,15 +836,31 @@ function ResourceDetailsContent() {
                                                         const effectiveHeight = chartHeight - padding.top - padding.bottom;
                                                         const effectiveWidth = chartWidth - padding.left - padding.right;
 
-                                                        // Generate points
+                                                        // Generate points; missing gauge periods keep their place on the x axis
                                                         const points = dataPoints.map((dp, i) => {
                                                             const x = padding.left + (i / (dataPoints.length - 1 || 1)) * effectiveWidth;
                                                             const y = padding.top + effectiveHeight - ((dp.value - stats.min) / ((stats.max - stats.min) || 1)) * effectiveHeight;
-                                                            return { x, y, value: dp.value };
+                                                            return { x, y, value: dp.value, missing: dp.missing };
                                                         });
 
-                                                        const linePoints = points.map(p => `${p.x},${p.y}`).join(' ');
-                                                        const areaPoints = `${padding.left},${chartHeight - padding.bottom} ${linePoints} ${chartWidth - padding.right},${chartHeight - padding.bottom}`;
+                                                        // Break the line at missing periods instead of interpolating across them
+                                                        const segments: string[] = [];
+                                                        let segment: string[] = [];
+                                                        for (const p of points) {
+                                                            if (p.missing) {
+                                                                if (segment.length > 0) segments.push(segment.join(' '));
+                                                                segment = [];
+                                                            } else {
+                                                                segment.push(`${p.x},${p.y}`);
+                                                            }
+                                                        }
+                                                        if (segment.length > 0) segments.push(segment.join(' '));
+
+                                                        const present = points.filter(p => !p.missing);
+                                                        const linePoints = present.map(p => `${p.x},${p.y}`).join(' ');
+                                                        const areaPoints = present.length > 0
+                                                            ? `${present[0].x},${chartHeight - padding.bottom} ${linePoints} ${present[present.length - 1].x},${chartHeight - padding.bottom}`
+                                                            : '';
 
                                                         return (
                                                             <svg viewBox={`0 0 ${chartWidth} ${chartHeight}`} preserveAspectRatio="none" className={styles.chartSvg} style={{ cursor: 'crosshair' }}>
@@ -847,15 +891,18 @@ function ResourceDetailsContent() {
                                                                     fill={`url(#gradient-${metricName})`}
                                                                 />
 
-                                                                {/* Main line */}
-                                                                <polyline
-                                                                    fill="none"
-                                                                    stroke={typeInfo.color}
-                                                                    strokeWidth="0.5"
-                                                                    strokeLinecap="round"
-                                                                    strokeLinejoin="round"
-                                                                    points={linePoints}
-                                                                />
+                                                                {/* Main line, one segment per run of periods with data */}
+                                                                {segments.map((segmentPoints, i) => (
+                                                                    <polyline
+                                                                        key={i}
+                                                                        fill="none"
+                                                                        stroke={typeInfo.color}
+                                                                        strokeWidth="0.5"
+                                                                        strokeLinecap="round"
+                                                                        strokeLinejoin="round"
+                                                                        points={segmentPoints}
+                                                                    />
+                                                                ))}
                                                             </svg>
 
                                                         );
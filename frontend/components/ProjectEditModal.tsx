@@ -65,7 +65,7 @@ export default function ProjectEditModal({
                 description,
                 confluence_url: confluenceUrl,
                 avatar,
-                secret_id: secretId || undefined
+                secret_id: secretId // Empty clears the default credential
             });
             onClose();
         } catch (error) {
@@ -18,6 +18,10 @@ const RESOURCE_TYPE_INFO: Record<string, { icon: string; label: string; color: s
     sns: { icon: '🔔', label: 'SNS Topic', color: '#DD344C' },
     rds: { icon: '🗄️', label: 'RDS Database', color: '#3B48CC' },
     lambda: { icon: '⚡', label: 'Lambda Function', color: '#FA7343' },
+    eks: { icon: '☸️', label: 'EKS Cluster', color: '#326CE5' },
+    cloudformation: { icon: '📚', label: 'CloudFormation Stack', color: '#E7157B' },
+    kinesis: { icon: '🌊', label: 'Kinesis Stream', color: '#8C4FFF' },
+    eventbridge: { icon: '🗓️', label: 'EventBridge Rule', color: '#E7157B' },
 };
 
 const AWS_REGIONS = [
@@ -36,7 +40,7 @@ export default function ResourceDiscoveryModal({ projectId, onClose, onResources
     const [credentials, setCredentials] = useState<Secret[]>([]);
     const [selectedCredential, setSelectedCredential] = useState<string>('');
     const [selectedRegion, setSelectedRegion] = useState<string>('ap-south-1');
-    const [selectedTypes, setSelectedTypes] = useState<string[]>(['s3', 'sqs', 'sns', 'rds', 'lambda']);
+    const [selectedTypes, setSelectedTypes] = useState<string[]>(['s3', 'sqs', 'sns', 'rds', 'lambda', 'eks', 'cloudformation', 'kinesis', 'eventbridge']);
 
     const [loading, setLoading] = useState(true);
     const [discovering, setDiscovering] = useState(false);
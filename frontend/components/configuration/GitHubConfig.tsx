@@ -9,7 +9,7 @@ export default function GitHubConfig() {
         repo_owner: '',
         repo_name: '',
         branch: 'main',
-        projects_path: 'projects',
+        projects_paths: ['projects'] as string[],
         auth_type: 'pat',
         personal_access_token: '',
         enabled: true,
@@ -47,8 +47,9 @@ export default function GitHubConfig() {
         setMessage(null);
 
         try {
-            await updateGitHubConfig(config);
-            setMessage({ type: 'success', text: 'Configuration saved successfully' });
+            const result = await updateGitHubConfig(config);
+            const warnings: string[] = result.warnings || [];
+            setMessage({ type: 'success', text: ['Configuration saved successfully', ...warnings].join('. ') });
         } catch (error: any) {
             console.error('Failed to save config:', error);
             setMessage({ type: 'error', text: error.message || 'Failed to save configuration' });
@@ -139,14 +140,17 @@ export default function GitHubConfig() {
                         />
                     </div>
                     <div className={styles.formGroup}>
-                        <label className={styles.formLabel}>Projects Path</label>
+                        <label className={styles.formLabel}>Projects Paths</label>
                         <input
                             type="text"
                             required
                             className={styles.formInput}
-                            value={config.projects_path}
-                            onChange={e => setConfig({ ...config, projects_path: e.target.value })}
-                            placeholder="projects"
+                            value={(config.projects_paths || []).join(', ')}
+                            onChange={e => setConfig({
+                                ...config,
+                                projects_paths: e.target.value.split(',').map(p => p.trim()).filter(Boolean),
+                            })}
+                            placeholder="projects, infra/catalog.yaml"
                         />
                     </div>
                 </div>
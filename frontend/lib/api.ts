@@ -1,4 +1,4 @@
-import { Service, ServiceLink, ServiceResourceMapping, Secret, Stats, Resource, DiscoveredResource, DiscoveredResourceDB } from './types';
+import { Service, ServiceLink, ServiceLinkType, ServiceResourceMapping, ServiceEnvironment, Secret, Stats, Resource, DiscoveredResource, DiscoveredResourceDB, ManagedBy } from './types';
 
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080';
 
@@ -30,11 +30,126 @@ async function handleResponse(response: Response, errorMessage: string = 'Reques
     return response.json();
 }
 
-export async function fetchServices(): Promise<Service[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/services`, {
+// List endpoints are paginated; the UI loads up to the server's maximum page size
+const LIST_PAGE_SIZE = 500;
+
+interface ListResponse<T> {
+    items: T[];
+    total: number;
+}
+
+async function fetchList<T>(path: string, errorMessage: string, filters: Record<string, string> = {}): Promise<T[]> {
+    const params = new URLSearchParams({ ...filters, limit: String(LIST_PAGE_SIZE) });
+    const response = await fetch(`${API_BASE_URL}${path}?${params}`, {
+        headers: getHeaders(),
+    });
+    const page: ListResponse<T> = await handleResponse(response, errorMessage);
+    return page.items;
+}
+
+// environment limits the list to services running in that environment
+export async function fetchServices(environment?: string): Promise<Service[]> {
+    return fetchList<Service>('/api/v1/services', 'Failed to fetch services', environment ? { environment } : {});
+}
+
+export async function fetchServiceTags(): Promise<import('./types').TagCount[]> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/tags`, {
+        headers: getHeaders(),
+    });
+    if (!response.ok) {
+        throw new Error('Failed to fetch service tags');
+    }
+    return response.json();
+}
+
+export async function fetchServiceHealth(serviceId: string, withMetrics: boolean = false): Promise<import('./types').ServiceHealth> {
+    const query = withMetrics ? '?metrics=true' : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/health${query}`, {
         headers: getHeaders(),
     });
-    return handleResponse(response, 'Failed to fetch services');
+    return handleResponse(response, 'Failed to fetch service health');
+}
+
+export async function fetchServiceCIStatus(serviceId: string): Promise<import('./types').ServiceCIStatus> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/ci-status`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch CI status');
+}
+
+export async function fetchProjectServicesHealth(projectId: string, withMetrics: boolean = false): Promise<import('./types').ServiceHealth[]> {
+    const query = withMetrics ? '?metrics=true' : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/projects/${projectId}/services/health${query}`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch service health');
+}
+
+export async function fetchServiceScorecard(serviceId: string): Promise<import('./types').ServiceScorecard> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/scorecard`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch service scorecard');
+}
+
+export async function fetchProjectScorecard(projectId: string): Promise<import('./types').ProjectScorecard> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/projects/${projectId}/scorecard`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch project scorecard');
+}
+
+export async function fetchProjectStats(projectId: string): Promise<import('./types').ProjectStats> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/projects/${projectId}/stats`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch project stats');
+}
+
+// Raw markdown of the project's catalog docs file; null when the catalog names none
+export async function fetchProjectDocs(projectId: string): Promise<string | null> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/projects/${projectId}/docs`, {
+        headers: getHeaders(),
+    });
+    if (response.status === 404) {
+        return null;
+    }
+    if (!response.ok) {
+        return handleResponse(response, 'Failed to fetch project docs');
+    }
+    return response.text();
+}
+
+export async function fetchNotificationPreferences(): Promise<import('./types').NotificationPreferences> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/users/current/notification-preferences`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch notification preferences');
+}
+
+export async function updateNotificationPreferences(events: Partial<Record<import('./types').NotificationEvent, boolean>>): Promise<import('./types').NotificationPreferences> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/users/current/notification-preferences`, {
+        method: 'PUT',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ events }),
+    });
+    return handleResponse(response, 'Failed to update notification preferences');
+}
+
+export async function fetchSMTPSettings(): Promise<import('./types').SMTPSettings> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/settings/smtp`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch SMTP settings');
+}
+
+export async function updateSMTPSettings(settings: import('./types').SMTPSettings): Promise<import('./types').SMTPSettings> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/settings/smtp`, {
+        method: 'PUT',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify(settings),
+    });
+    return handleResponse(response, 'Failed to update SMTP settings');
 }
 
 export async function createService(service: Partial<Service>): Promise<Service> {
@@ -68,27 +183,29 @@ export async function updateService(serviceId: string, updates: { owner?: string
 }
 
 // Service Links
-export async function fetchServiceLinks(serviceId: string): Promise<ServiceLink[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/links`, {
+export async function fetchServiceLinks(serviceId: string, type?: ServiceLinkType): Promise<ServiceLink[]> {
+    const query = type ? `?type=${type}` : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/links${query}`, {
         headers: getHeaders(),
     });
     return handleResponse(response, 'Failed to fetch service links');
 }
 
-export async function addServiceLink(serviceId: string, label: string, url: string, icon?: string): Promise<ServiceLink> {
+export async function addServiceLink(serviceId: string, label: string, url: string, icon?: string, type: ServiceLinkType = 'generic'): Promise<ServiceLink> {
     const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/links`, {
         method: 'POST',
         headers: getHeaders({ 'Content-Type': 'application/json' }),
-        body: JSON.stringify({ label, url, icon }),
+        body: JSON.stringify({ type, label, url, icon }),
     });
     return handleResponse(response, 'Failed to add link');
 }
 
-export async function updateServiceLink(serviceId: string, linkId: string, label: string, url: string, icon?: string): Promise<ServiceLink> {
+// An omitted type keeps the link's current type
+export async function updateServiceLink(serviceId: string, linkId: string, label: string, url: string, icon?: string, type?: ServiceLinkType): Promise<ServiceLink> {
     const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/links/${linkId}`, {
         method: 'PUT',
         headers: getHeaders({ 'Content-Type': 'application/json' }),
-        body: JSON.stringify({ label, url, icon }),
+        body: JSON.stringify({ type, label, url, icon }),
     });
     return handleResponse(response, 'Failed to update link');
 }
@@ -101,19 +218,46 @@ export async function deleteServiceLink(serviceId: string, linkId: string): Prom
     return handleResponse(response, 'Failed to delete link');
 }
 
+// Service Environments
+export async function fetchServiceEnvironments(serviceId: string): Promise<ServiceEnvironment[]> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/environments`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch service environments');
+}
+
+// Adds the environment, or updates is_active when it already exists
+export async function addServiceEnvironment(serviceId: string, name: string, isActive: boolean = true): Promise<ServiceEnvironment> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/environments`, {
+        method: 'POST',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ name, is_active: isActive }),
+    });
+    return handleResponse(response, 'Failed to add environment');
+}
+
+export async function deleteServiceEnvironment(serviceId: string, name: string): Promise<void> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/environments/${encodeURIComponent(name)}`, {
+        method: 'DELETE',
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to delete environment');
+}
+
 // Service Resource Mappings
-export async function fetchServiceResources(serviceId: string): Promise<ServiceResourceMapping[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/resources`, {
+export async function fetchServiceResources(serviceId: string, environment?: string): Promise<ServiceResourceMapping[]> {
+    const query = environment ? `?environment=${encodeURIComponent(environment)}` : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/resources${query}`, {
         headers: getHeaders(),
     });
     return handleResponse(response, 'Failed to fetch service resources');
 }
 
-export async function mapResourcesToService(serviceId: string, resourceIds: string[]): Promise<{ success: boolean; mapped: number }> {
+export async function mapResourcesToService(serviceId: string, resourceIds: string[], environment?: string): Promise<{ success: boolean; mapped: number }> {
     const response = await fetch(`${API_BASE_URL}/api/v1/services/${serviceId}/resources`, {
         method: 'POST',
         headers: getHeaders({ 'Content-Type': 'application/json' }),
-        body: JSON.stringify({ resource_ids: resourceIds }),
+        body: JSON.stringify({ resource_ids: resourceIds, environment }),
     });
     return handleResponse(response, 'Failed to map resources');
 }
@@ -137,8 +281,9 @@ export async function fetchSecrets(): Promise<Secret[]> {
 }
 
 // AWS Credentials Management
-export async function fetchAWSCredentials(): Promise<Secret[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/credentials`, {
+export async function fetchAWSCredentials(accessType?: 'read' | 'write'): Promise<Secret[]> {
+    const query = accessType ? `?access_type=${accessType}` : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/credentials${query}`, {
         headers: getHeaders(),
     });
     return handleResponse(response, 'Failed to fetch AWS credentials');
@@ -182,6 +327,21 @@ export async function deleteAWSCredential(id: string): Promise<void> {
     }
 }
 
+export async function rotateAWSCredential(id: string, accessKeyId: string, secretAccessKey: string): Promise<void> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/credentials/${id}/rotate`, {
+        method: 'PUT',
+        headers: getHeaders(),
+        body: JSON.stringify({
+            access_key_id: accessKeyId,
+            secret_access_key: secretAccessKey,
+        }),
+    });
+    if (!response.ok) {
+        const errorText = await response.text();
+        throw new Error(errorText || 'Failed to rotate AWS credential');
+    }
+}
+
 // Dev Provisioning Permissions
 export interface UserProvisioningPermissions {
     user_id: string;
@@ -223,6 +383,25 @@ export async function createResource(request: any): Promise<Resource> {
     return response.json();
 }
 
+// Approve a dev's provisioning request (lead/superadmin); provisioning starts right away
+export async function approveProvisioning(resourceId: string): Promise<Resource> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/provision/${resourceId}/approve`, {
+        method: 'POST',
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to approve provisioning');
+}
+
+// Reject a dev's provisioning request; the requester is sent the reason
+export async function rejectProvisioning(resourceId: string, reason: string): Promise<import('./types').ProvisioningApproval> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/provision/${resourceId}/reject`, {
+        method: 'POST',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ reason }),
+    });
+    return handleResponse(response, 'Failed to reject provisioning');
+}
+
 export async function fetchProjectResources(projectId: string): Promise<Resource[]> {
     const response = await fetch(`${API_BASE_URL}/api/v1/projects/${projectId}/resources`, {
         headers: getHeaders(),
@@ -233,6 +412,28 @@ export async function fetchProjectResources(projectId: string): Promise<Resource
     return response.json();
 }
 
+// Resources across every project the user can see, with per-status counts
+export async function fetchResources(filters: { status?: string; type?: string; limit?: number; offset?: number } = {}): Promise<import('./types').ResourceList> {
+    const params = new URLSearchParams();
+    Object.entries(filters).forEach(([key, value]) => {
+        if (value !== undefined && value !== '') params.set(key, String(value));
+    });
+    const response = await fetch(`${API_BASE_URL}/api/v1/resources?${params}`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch resources');
+}
+
+// Catalog sync history of one project, or of every project (superadmin) without projectId
+export async function fetchSyncHistory(projectId?: string, limit = 20, offset = 0): Promise<import('./types').SyncHistoryPage> {
+    const path = projectId ? `/api/v1/projects/${projectId}/sync-history` : '/api/v1/sync-history';
+    const params = new URLSearchParams({ limit: String(limit), offset: String(offset) });
+    const response = await fetch(`${API_BASE_URL}${path}?${params}`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch sync history');
+}
+
 // AWS Resource Discovery
 
 
@@ -245,7 +446,8 @@ export interface DiscoveryResponse {
 export async function discoverResources(
     secretId: string,
     region?: string,
-    types?: string[]
+    types?: string[],
+    includeAllRegions?: boolean // Also return S3 buckets of other regions
 ): Promise<DiscoveryResponse> {
     const response = await fetch(`${API_BASE_URL}/api/v1/discover`, {
         method: 'POST',
@@ -254,6 +456,7 @@ export async function discoverResources(
             secret_id: secretId,
             region: region,
             types: types,
+            include_all_regions: includeAllRegions,
         }),
     });
     return handleResponse(response, 'Failed to discover resources');
@@ -263,6 +466,12 @@ export async function discoverResources(
 export interface MetricDataPoint {
     timestamp: string;
     value: number;
+    missing?: boolean; // Gauge with no data in this period; value is meaningless
+}
+
+// presentPoints drops the periods a gauge has no data for
+export function presentPoints(points: MetricDataPoint[]): MetricDataPoint[] {
+    return points.filter(dp => !dp.missing);
 }
 
 export interface ResourceMetrics {
@@ -271,17 +480,28 @@ export interface ResourceMetrics {
     period: string;
     metrics: Record<string, MetricDataPoint[]>;
     metadata?: Record<string, string>;
+    alarms?: AlarmStatus[];
     fetched_at: string;
 }
 
+export interface AlarmStatus {
+    alarm_name: string;
+    state_value: 'OK' | 'ALARM' | 'INSUFFICIENT_DATA';
+    threshold: number;
+    metric_name: string;
+    state_updated_timestamp: string;
+}
+
 export async function fetchResourceMetrics(
     secretId: string,
     resourceType: string,
     resourceName: string,
     region?: string,
-    period: string = '24h'
+    period: string = '24h',
+    refresh: boolean = false // Bypasses the server cache; honoured for leads and superadmins
 ): Promise<ResourceMetrics> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/resources/metrics`, {
+    const query = refresh ? '?refresh=true' : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/resources/metrics${query}`, {
         method: 'POST',
         headers: getHeaders({ 'Content-Type': 'application/json' }),
         body: JSON.stringify({
@@ -295,6 +515,20 @@ export async function fetchResourceMetrics(
     return handleResponse(response, 'Failed to fetch resource metrics');
 }
 
+export async function fetchResourceAlarms(
+    secretId: string,
+    resourceType: string,
+    resourceName: string,
+    region?: string
+): Promise<AlarmStatus[]> {
+    const params = new URLSearchParams({ secret_id: secretId, resource_type: resourceType, resource_name: resourceName });
+    if (region) params.set('region', region);
+    const response = await fetch(`${API_BASE_URL}/api/v1/resources/alarms?${params}`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch resource alarms');
+}
+
 // Discovered Resources & Sync
 // Discovered Resources & Sync
 
@@ -330,14 +564,16 @@ export async function syncProjectResources(
 export async function associateResources(
     projectId: string,
     secretId: string,
-    resources: DiscoveredResource[]
-): Promise<{ success: boolean; resources_added: number }> {
+    resources: DiscoveredResource[],
+    skipVerification = false
+): Promise<{ success: boolean; resources_added: number; errors: { arn: string; error: string }[] }> {
     const response = await fetch(`${API_BASE_URL}/api/v1/resources/associate`, {
         method: 'POST',
         headers: getHeaders({ 'Content-Type': 'application/json' }),
         body: JSON.stringify({
             project_id: projectId,
             secret_id: secretId,
+            skip_verification: skipVerification,
             resources: resources.map(r => ({
                 arn: r.arn,
                 resource_type: r.type,
@@ -350,6 +586,20 @@ export async function associateResources(
     return handleResponse(response, 'Failed to associate resources');
 }
 
+export async function associateResourcesByARN(
+    projectId: string,
+    arns: string[],
+    secretId?: string,
+    region?: string
+): Promise<{ associated: number; already_existed: number; not_found: string[] }> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/resources/associate/bulk`, {
+        method: 'POST',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify({ project_id: projectId, secret_id: secretId, region, arns }),
+    });
+    return handleResponse(response, 'Failed to associate resources');
+}
+
 export async function fetchDiscoveredResources(projectId?: string): Promise<DiscoveredResourceDB[]> {
     const url = projectId
         ? `${API_BASE_URL}/api/v1/resources/discovered?project_id=${projectId}`
@@ -369,6 +619,18 @@ export async function removeDiscoveredResource(resourceId: string): Promise<{ su
     return handleResponse(response, 'Failed to remove resource');
 }
 
+export async function updateDiscoveredResource(
+    resourceId: string,
+    changes: { notes?: string; owner_label?: string; managed_by?: ManagedBy }
+): Promise<DiscoveredResourceDB> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/resources/discovered/${resourceId}`, {
+        method: 'PATCH',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify(changes),
+    });
+    return handleResponse(response, 'Failed to update resource');
+}
+
 export async function fetchResourceById(identifier: string): Promise<DiscoveredResourceDB> {
     const response = await fetch(`${API_BASE_URL}/api/v1/resources/discovered/${identifier}`, {
         headers: getHeaders(),
@@ -394,10 +656,7 @@ export async function fetchCurrentUser(): Promise<import('./types').CurrentUserR
 }
 
 export async function fetchUsers(): Promise<import('./types').User[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/users`, {
-        headers: getHeaders(),
-    });
-    return handleResponse(response, 'Failed to fetch users');
+    return fetchList<import('./types').User>('/api/v1/users', 'Failed to fetch users');
 }
 
 export async function createUser(user: Partial<import('./types').User>): Promise<import('./types').User> {
@@ -421,7 +680,7 @@ export async function updateUser(user: import('./types').User): Promise<import('
 }
 
 export async function deleteUser(userId: string): Promise<void> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/users?id=${userId}`, {
+    const response = await fetch(`${API_BASE_URL}/api/v1/users/${userId}`, {
         method: 'DELETE',
         headers: getHeaders(),
     });
@@ -430,10 +689,7 @@ export async function deleteUser(userId: string): Promise<void> {
 
 // Team Management API
 export async function fetchTeams(): Promise<import('./types').Team[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/teams`, {
-        headers: getHeaders(),
-    });
-    return handleResponse(response, 'Failed to fetch teams');
+    return fetchList<import('./types').Team>('/api/v1/teams', 'Failed to fetch teams');
 }
 
 export async function createTeam(name: string, description: string): Promise<import('./types').Team> {
@@ -447,7 +703,7 @@ export async function createTeam(name: string, description: string): Promise<imp
 }
 
 export async function deleteTeam(teamId: string): Promise<void> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/teams?id=${teamId}`, {
+    const response = await fetch(`${API_BASE_URL}/api/v1/teams/${teamId}`, {
         method: 'DELETE',
         headers: getHeaders(),
     });
@@ -464,12 +720,48 @@ export async function updateTeamMembers(teamId: string, memberIds: string[]): Pr
     return response.json();
 }
 
-// Project Management API
-export async function fetchProjects(): Promise<import('./types').Project[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/projects`, {
+// Adds the users a CSV of emails or GitHub usernames (one per line) names to a team (superadmin)
+export async function importTeamMembers(teamId: string, file: File): Promise<{ added: number; skipped: number; not_found: string[] }> {
+    const formData = new FormData();
+    formData.append('file', file);
+    const response = await fetch(`${API_BASE_URL}/api/v1/teams/${teamId}/members/import`, {
+        method: 'POST',
+        headers: getHeaders(),
+        body: formData,
+    });
+    return handleResponse(response, 'Failed to import team members');
+}
+
+export async function fetchTeamNotificationChannels(teamId: string): Promise<import('./types').NotificationChannel[]> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/teams/${teamId}/notifications`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch notification channels');
+}
+
+export async function createTeamNotificationChannel(
+    teamId: string,
+    channel: Pick<import('./types').NotificationChannel, 'type' | 'url' | 'events'>
+): Promise<import('./types').NotificationChannel> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/teams/${teamId}/notifications`, {
+        method: 'POST',
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify(channel),
+    });
+    return handleResponse(response, 'Failed to create notification channel');
+}
+
+export async function deleteTeamNotificationChannel(teamId: string, channelId: string): Promise<void> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/teams/${teamId}/notifications/${channelId}`, {
+        method: 'DELETE',
         headers: getHeaders(),
     });
-    return handleResponse(response, 'Failed to fetch projects');
+    if (!response.ok) throw new Error('Failed to delete notification channel');
+}
+
+// Project Management API
+export async function fetchProjects(): Promise<import('./types').Project[]> {
+    return fetchList<import('./types').Project>('/api/v1/projects', 'Failed to fetch projects');
 }
 
 export async function fetchProjectById(id: string): Promise<import('./types').ProjectWithServices> {
@@ -533,6 +825,10 @@ export async function fetchAuditLogs(params?: import('./types').AuditLogQueryPar
     return response.json();
 }
 
+export async function fetchProjectAuditLogs(projectId: string): Promise<import('./types').AuditLog[]> {
+    return fetchList<import('./types').AuditLog>(`/api/v1/projects/${projectId}/audit-logs`, 'Failed to fetch project audit logs');
+}
+
 export async function createAuditLog(log: Partial<import('./types').AuditLog>): Promise<import('./types').AuditLog> {
     const response = await fetch(`${API_BASE_URL}/api/v1/audit-logs`, {
         method: 'POST',
@@ -621,6 +917,45 @@ export interface ArgoCDPod {
     restarts: number;
     age: string;
     containers: string[];
+    workload_kind?: string;
+    workload?: string;
+    replica_set?: string;
+    resources?: ArgoCDContainerResources[]; // Only when requested
+}
+
+export interface ArgoCDContainerResources {
+    container: string;
+    requests?: Record<string, string>; // e.g. { cpu: '250m', memory: '512Mi' }
+    limits?: Record<string, string>;
+}
+
+export interface ArgoCDPodDescription {
+    name: string;
+    namespace: string;
+    phase: string;
+    node_name?: string;
+    qos_class?: string;
+    conditions: {
+        type: string;
+        status: string;
+        reason?: string;
+        message?: string;
+        last_transition_time?: string;
+    }[];
+    containers: {
+        name: string;
+        image: string;
+        ready: boolean;
+        restart_count: number;
+        state: 'running' | 'waiting' | 'terminated';
+        state_reason?: string;
+        last_termination_reason?: string;
+        last_termination_message?: string;
+        last_termination_exit_code?: number;
+        last_terminated_at?: string;
+        requests?: Record<string, string>;
+        limits?: Record<string, string>;
+    }[];
 }
 
 export interface ServiceArgoCDApp {
@@ -628,6 +963,7 @@ export interface ServiceArgoCDApp {
     service_id: string;
     argocd_app_name: string;
     environment_name: string;
+    environment_active?: boolean; // Unset when no service environment matches
     created_at: string;
     updated_at: string;
 }
@@ -649,8 +985,9 @@ export async function fetchArgoCDApplications(): Promise<ArgoCDApplication[]> {
 }
 
 // Get ArgoCD apps linked to a service
-export async function fetchServiceArgoCDApps(serviceId: string): Promise<ServiceArgoCDApp[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/service/${serviceId}/apps`, {
+export async function fetchServiceArgoCDApps(serviceId: string, environment?: string): Promise<ServiceArgoCDApp[]> {
+    const query = environment ? `?environment=${encodeURIComponent(environment)}` : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/service/${serviceId}/apps${query}`, {
         headers: getHeaders(),
     });
     return handleResponse(response, 'Failed to fetch service ArgoCD apps');
@@ -693,13 +1030,23 @@ export async function fetchArgoCDAppStatus(appName: string): Promise<ArgoCDAppli
 }
 
 // Get pods for an ArgoCD application
-export async function fetchArgoCDAppPods(appName: string): Promise<ArgoCDPod[]> {
-    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/pods`, {
+export async function fetchArgoCDAppPods(appName: string, withResources = false): Promise<ArgoCDPod[]> {
+    const query = withResources ? '?resources=true' : '';
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/pods${query}`, {
         headers: getHeaders(),
     });
     return handleResponse(response, 'Failed to fetch pods');
 }
 
+// Describe a pod: node, QoS class, conditions and container termination reasons
+export async function describeArgoCDPod(appName: string, podName: string, namespace: string): Promise<ArgoCDPodDescription> {
+    const params = new URLSearchParams({ namespace });
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/pods/${podName}/describe?${params}`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to describe pod');
+}
+
 // Get logs for a pod
 export async function fetchArgoCDPodLogs(appName: string, podName: string, namespace: string, container?: string): Promise<string> {
     const params = new URLSearchParams({ namespace });
@@ -725,13 +1072,63 @@ export async function deleteArgoCDPod(appName: string, podName: string, namespac
     }
 }
 
+// Rolling-restart a Deployment or StatefulSet managed by an ArgoCD application
+export async function restartArgoCDWorkload(appName: string, kind: string, name: string, namespace: string): Promise<void> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/resources/${kind}/${name}/restart?namespace=${namespace}`, {
+        method: 'POST',
+        headers: getHeaders(),
+    });
+    if (!response.ok) {
+        throw new Error(await response.text() || 'Failed to restart workload');
+    }
+}
+
 // Trigger a sync for an ArgoCD application
-export async function syncArgoCDApp(appName: string): Promise<void> {
+export async function syncArgoCDApp(appName: string, options: { prune?: boolean; revision?: string } = {}): Promise<void> {
     const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/sync`, {
         method: 'POST',
-        headers: getHeaders(),
+        headers: getHeaders({ 'Content-Type': 'application/json' }),
+        body: JSON.stringify(options),
     });
     if (!response.ok) {
         throw new Error('Failed to sync application');
     }
 }
+
+export interface ArgoCDOperationState {
+    phase: 'Running' | 'Terminating' | 'Succeeded' | 'Failed' | 'Error';
+    message?: string;
+    revision?: string;
+    started_at?: string;
+    finished_at?: string;
+    resources_total: number;
+    resources_synced: number;
+    resources_failed: number;
+}
+
+export interface ArgoCDEvent {
+    type: string;
+    reason: string;
+    message: string;
+    count: number;
+    object_kind: string;
+    object_name: string;
+    first_timestamp?: string;
+    last_timestamp?: string;
+}
+
+// State of the current or last sync of an ArgoCD application; null if never synced
+export async function fetchArgoCDOperation(appName: string): Promise<ArgoCDOperationState | null> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/operation`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch sync operation');
+}
+
+// Kubernetes events of an ArgoCD application, most recent first
+export async function fetchArgoCDEvents(appName: string): Promise<ArgoCDEvent[]> {
+    const response = await fetch(`${API_BASE_URL}/api/v1/argocd/apps/${appName}/events`, {
+        headers: getHeaders(),
+    });
+    return handleResponse(response, 'Failed to fetch application events');
+}
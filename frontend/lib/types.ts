@@ -5,7 +5,8 @@ export interface Service {
     team_name?: string;
     project_id?: string;
     description: string;
-    environment: 'Production' | 'Staging' | 'Experimental';
+    environment: string; // Deprecated: use environments
+    environments: string[];
     language: string;
     tags: string[];
     repository: string;
@@ -26,9 +27,104 @@ export interface Service {
     mapped_resources?: ServiceResourceMapping[];
 }
 
+export interface TagCount {
+    tag: string;
+    count: number;
+}
+
+export interface ServiceHealth {
+    service_id: string;
+    service_name: string;
+    overall: 'healthy' | 'degraded' | 'unhealthy' | 'unknown';
+    argocd_statuses: Record<string, string>; // Environment -> ArgoCD health
+    resource_statuses: Record<string, string>; // "type/name" -> resource status
+    metric_alerts: string[];
+    last_checked: string;
+}
+
+export interface WorkflowRunSummary {
+    workflow_name: string;
+    run_id: number;
+    status: string; // queued, in_progress, completed, ...
+    conclusion: string; // success, failure, cancelled, ...; empty until completed
+    branch: string;
+    run_started_at: string;
+    html_url: string;
+}
+
+export interface ServiceCIStatus {
+    service_id: string;
+    repository: string; // owner/repo
+    latest_run: WorkflowRunSummary | null;
+}
+
+export interface ScorecardCheckResult {
+    id: string;
+    title: string;
+    status: 'pass' | 'fail' | 'error';
+    hint: string;
+}
+
+export interface ServiceScorecard {
+    service_id: string;
+    service_name: string;
+    passed: number;
+    total: number;
+    checks: ScorecardCheckResult[];
+}
+
+export interface ScorecardCheckSummary {
+    id: string;
+    title: string;
+    passed: number;
+    failed: number; // Including services whose check errored
+}
+
+export interface ProjectScorecard {
+    project_id: string;
+    passed: number;
+    total: number;
+    checks: ScorecardCheckSummary[];
+    services: ServiceScorecard[];
+    generated_at: string; // Rollups are cached for a few minutes
+}
+
+export type NotificationEvent = 'provisioning_succeeded' | 'provisioning_failed' | 'sync_failed' | 'team_digest';
+
+export interface NotificationPreferences {
+    events: Record<NotificationEvent, boolean>;
+    updated_at?: string;
+}
+
+export type TeamNotificationEvent = 'provisioning_succeeded' | 'provisioning_failed' | 'sync_failed' | 'resource_deleted';
+
+export interface NotificationChannel {
+    id: string;
+    team_id: string;
+    type: 'slack' | 'webhook';
+    url: string;
+    events: TeamNotificationEvent[];
+    created_by?: string;
+    created_at: string;
+}
+
+export interface SMTPSettings {
+    host: string;
+    port: number;
+    tls_mode: 'starttls' | 'tls' | 'none';
+    from: string;
+    username: string;
+    password?: string; // Write-only; leave empty to keep the stored password
+    password_set: boolean;
+    updated_at?: string;
+}
+
+export type ServiceLinkType = 'generic' | 'runbook' | 'incident_tracker' | 'monitoring' | 'api_docs' | 'repository';
+
 export interface ServiceLink {
     id: string;
     service_id: string;
+    type: ServiceLinkType;
     label: string;
     url: string;
     icon?: string;
@@ -40,6 +136,7 @@ export interface ServiceResourceMapping {
     id: string;
     service_id: string;
     discovered_resource_id: string;
+    environment?: string; // Unset when the resource serves every environment
     created_at: string;
     resource_name?: string;
     resource_type?: string;
@@ -47,6 +144,15 @@ export interface ServiceResourceMapping {
     region?: string;
 }
 
+export interface ServiceEnvironment {
+    service_id: string;
+    name: string;
+    is_active: boolean;
+    created_at: string;
+    argocd_app_id?: string; // ArgoCD app deploying this environment
+    argocd_app_name?: string;
+}
+
 
 
 // Projects
@@ -146,8 +252,13 @@ export interface AuditLog {
     resource_type: string;
     resource_id?: string;
     resource_name?: string;
+    project_id?: string;
     details: string;
     ip_address?: string;
+    route?: string;
+    status_code?: number;
+    duration_ms?: number;
+    request_id?: string;
     timestamp: string;
     status: 'success' | 'failure';
 }
@@ -159,13 +270,39 @@ export interface AuditLogQueryParams {
     offset?: number;
 }
 
+// Who manages a resource; the portal only changes or deletes resources it manages
+export type ManagedBy = 'portalight' | 'terraform' | 'manual';
+
 export interface Resource {
     id: string;
     project_id: string;
+    project_name?: string; // Only set by the cross-project listing
     name: string;
     type: string;
-    status: 'provisioning' | 'active' | 'failed';
+    status: 'awaiting_approval' | 'provisioning' | 'active' | 'failed' | 'deleted';
     config: any;
+    reason?: string; // Why the resource was requested
+    ticket_url?: string;
+    managed_by: ManagedBy;
+    created_at: string;
+    updated_at: string;
+}
+
+export interface ResourceList {
+    items: Resource[];
+    total: number;
+    status_counts: Partial<Record<Resource['status'], number>>;
+}
+
+// A dev's provisioning request, which a lead approves or rejects
+export interface ProvisioningApproval {
+    id: string;
+    resource_id: string;
+    secret_id: string;
+    requested_by: string;
+    approved_by?: string;
+    status: 'pending' | 'approved' | 'rejected';
+    reason?: string;
     created_at: string;
     updated_at: string;
 }
@@ -178,6 +315,7 @@ export interface DiscoveredResource {
     status?: string;
     metadata: Record<string, any>;
     discovered_at?: string;
+    managed_by?: 'terraform'; // Set when the tags show infrastructure as code manages it
 }
 
 export interface DiscoveredResourceDB {
@@ -190,6 +328,9 @@ export interface DiscoveredResourceDB {
     region: string;
     status: 'active' | 'deleted' | 'unknown';
     metadata: Record<string, any>;
+    notes: string;
+    owner_label: string;
+    managed_by: ManagedBy;
     last_synced_at: string | null;
     discovered_at: string;
     created_at: string;
@@ -198,3 +339,41 @@ export interface DiscoveredResourceDB {
 
 // Type alias for backward compatibility
 export type AWSCredential = Secret;
+
+export interface SyncHistory {
+    id: string;
+    sync_type: string;
+    project_id?: string;
+    project_name?: string;
+    catalog_file_path?: string;
+    status: 'running' | 'success' | 'failed';
+    projects_created: number;
+    projects_updated: number;
+    services_created: number;
+    services_updated: number;
+    services_orphaned: number;
+    error_message?: string;
+    validation_errors?: unknown;
+    started_at: string;
+    completed_at?: string;
+    duration_ms: number;
+    synced_by?: string;
+    synced_by_name?: string;
+}
+
+export interface SyncHistoryPage {
+    history: SyncHistory[];
+    total: number;
+}
+
+export interface ProjectStats {
+    service_count: number;
+    services_by_language: Record<string, number>;
+    services_by_environment: Record<string, number>;
+    services_by_team: Record<string, number>;
+    auto_synced_count: number;
+    manual_count: number;
+    last_synced_at?: string;
+    resource_count: number;
+    resources_by_type: Record<string, number>;
+}